@@ -2,7 +2,9 @@ package subcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -11,8 +13,11 @@ import (
 
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/apply/rebalancers"
 	"github.com/segmentio/topicctl/pkg/cli"
 	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/metrics"
+	"github.com/segmentio/topicctl/pkg/progress"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -26,12 +31,21 @@ var applyCmd = &cobra.Command{
 }
 
 type applyCmdConfig struct {
+	aclConfigs                   []string
+	allowProtected               bool
 	brokersToRemove              []int
 	brokerThrottleMBsOverride    int
+	byThroughput                 bool
 	dryRun                       bool
+	githubComment                bool
+	maxMoveMBs                   int
+	maxMoves                     int
 	partitionBatchSizeOverride   int
 	pathPrefix                   string
+	progress                     string
 	rebalance                    bool
+	report                       string
+	reportFile                   string
 	retentionDropStepDurationStr string
 	skipConfirm                  bool
 	sleepLoopDuration            time.Duration
@@ -44,6 +58,18 @@ type applyCmdConfig struct {
 var applyConfig applyCmdConfig
 
 func init() {
+	applyCmd.Flags().StringArrayVar(
+		&applyConfig.aclConfigs,
+		"acls",
+		[]string{},
+		"ACL config(s) to apply after the topic configs; can be repeated and can contain globs",
+	)
+	applyCmd.Flags().BoolVar(
+		&applyConfig.allowProtected,
+		"allow-protected",
+		false,
+		"Allow partition and placement changes in protected topics",
+	)
 	applyCmd.Flags().IntSliceVar(
 		&applyConfig.brokersToRemove,
 		"to-remove",
@@ -56,12 +82,36 @@ func init() {
 		0,
 		"Broker throttle override (MB/sec)",
 	)
+	applyCmd.Flags().BoolVar(
+		&applyConfig.byThroughput,
+		"by-throughput",
+		false,
+		"Rebalance by the partition throughputs from the cluster's broker metrics; only applies if rebalance is also set",
+	)
 	applyCmd.Flags().BoolVar(
 		&applyConfig.dryRun,
 		"dry-run",
 		false,
 		"Do a dry-run",
 	)
+	applyCmd.Flags().BoolVar(
+		&applyConfig.githubComment,
+		"github-comment",
+		os.Getenv("TOPICCTL_GITHUB_COMMENT") == "true",
+		"Comment on the triggering GitHub pull request with the pending changes; only applies with dry-run",
+	)
+	applyCmd.Flags().IntVar(
+		&applyConfig.maxMoveMBs,
+		"max-move-mb",
+		0,
+		"Maximum total size of the replicas moved across all topics (MB); only applies if rebalance is also set",
+	)
+	applyCmd.Flags().IntVar(
+		&applyConfig.maxMoves,
+		"max-moves",
+		0,
+		"Maximum number of replicas moved across all topics; only applies if rebalance is also set",
+	)
 	applyCmd.Flags().IntVar(
 		&applyConfig.partitionBatchSizeOverride,
 		"partition-batch-size",
@@ -74,12 +124,30 @@ func init() {
 		os.Getenv("TOPICCTL_APPLY_PATH_PREFIX"),
 		"Prefix for topic config paths",
 	)
+	applyCmd.Flags().StringVar(
+		&applyConfig.progress,
+		"progress",
+		os.Getenv("TOPICCTL_PROGRESS"),
+		"Stream JSON progress events to this unix socket path, or to stdout if set to 'stdout'",
+	)
 	applyCmd.Flags().BoolVar(
 		&applyConfig.rebalance,
 		"rebalance",
 		false,
 		"Explicitly rebalance broker partition assignments",
 	)
+	applyCmd.Flags().StringVar(
+		&applyConfig.report,
+		"report",
+		"",
+		"Also write a report of the changes in this format (choices: markdown)",
+	)
+	applyCmd.Flags().StringVar(
+		&applyConfig.reportFile,
+		"report-file",
+		"",
+		"File to write the report to; defaults to stdout",
+	)
 	applyCmd.Flags().StringVar(
 		&applyConfig.retentionDropStepDurationStr,
 		"retention-drop-step-duration",
@@ -104,6 +172,25 @@ func init() {
 }
 
 func applyPreRun(cmd *cobra.Command, args []string) error {
+	if applyConfig.report != "" && applyConfig.report != "markdown" {
+		return fmt.Errorf(
+			"Unrecognized report format %s; must be markdown",
+			applyConfig.report,
+		)
+	}
+	if applyConfig.reportFile != "" && applyConfig.report == "" {
+		return errors.New("Cannot set report-file without report")
+	}
+	if applyConfig.githubComment && !applyConfig.dryRun {
+		return errors.New("Can only comment on GitHub pull requests with dry-run")
+	}
+	if applyConfig.byThroughput && !applyConfig.rebalance {
+		return errors.New("Cannot set by-throughput without rebalance")
+	}
+	if (applyConfig.maxMoves > 0 || applyConfig.maxMoveMBs > 0) && !applyConfig.rebalance {
+		return errors.New("Cannot set max-moves or max-move-mb without rebalance")
+	}
+
 	if applyConfig.retentionDropStepDurationStr != "" {
 		var err error
 		applyConfig.retentionDropStepDuration, err = time.ParseDuration(
@@ -138,8 +225,72 @@ func applyRun(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	var pullRequest apply.GitHubPullRequest
+	if applyConfig.githubComment {
+		var err error
+		pullRequest, err = apply.GitHubPullRequestFromEnv()
+		if err != nil {
+			return err
+		}
+	}
+
+	var report *apply.ApplyReport
+	if applyConfig.report != "" || applyConfig.githubComment {
+		report = &apply.ApplyReport{DryRun: applyConfig.dryRun}
+	}
+
+	progressReporter, err := progress.NewReporter(applyConfig.progress)
+	if err != nil {
+		return fmt.Errorf("Could not connect to progress socket: %+v", err)
+	}
+	defer progressReporter.Close()
+
+	ctx, endTracing := startTracing(ctx, "apply", args)
+	err = applyTopics(ctx, args, adminClients, report, progressReporter)
+	if err == nil && len(applyConfig.aclConfigs) > 0 {
+		err = applyACLConfigs(ctx, applyConfig.aclConfigs, adminClients, report)
+	}
+	endTracing(err)
+
+	// Write out the report even if the apply failed so that the failure is included in it
+	if applyConfig.report != "" {
+		if reportErr := writeApplyReport(report); reportErr != nil {
+			if err != nil {
+				log.Errorf("Could not write apply report: %+v", reportErr)
+				return err
+			}
+			return reportErr
+		}
+	}
+	if applyConfig.githubComment {
+		if commentErr := pullRequest.PostApplyReport(ctx, *report); commentErr != nil {
+			if err != nil {
+				log.Errorf("Could not comment on pull request: %+v", commentErr)
+				return err
+			}
+			return commentErr
+		}
+	}
+
+	return err
+}
+
+func applyTopics(
+	ctx context.Context,
+	args []string,
+	adminClients map[string]admin.Client,
+	report *apply.ApplyReport,
+	progressReporter *progress.Reporter,
+) error {
 	matchCount := 0
 
+	// Keep a cache of the partition throughputs with the cluster config path as the key so that
+	// the brokers are only scraped once per cluster
+	clusterThroughputs := map[string]metrics.PartitionThroughputs{}
+
+	// The move budget is shared by all of the topics so that it limits the whole run
+	moveBudget := newMoveBudget(applyConfig.maxMoves, applyConfig.maxMoveMBs)
+
 	for _, arg := range args {
 		if applyConfig.pathPrefix != "" && !filepath.IsAbs(arg) {
 			arg = filepath.Join(applyConfig.pathPrefix, arg)
@@ -152,7 +303,15 @@ func applyRun(cmd *cobra.Command, args []string) error {
 
 		for _, match := range matches {
 			matchCount++
-			if err := applyTopic(ctx, match, adminClients); err != nil {
+			if err := applyTopic(
+				ctx,
+				match,
+				adminClients,
+				clusterThroughputs,
+				moveBudget,
+				report,
+				progressReporter,
+			); err != nil {
 				return err
 			}
 		}
@@ -165,26 +324,108 @@ func applyRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// applyACLConfigs applies the ACL configs that match the argument args along with the topic
+// configs, so that their changes are included in the same output and report.
+func applyACLConfigs(
+	ctx context.Context,
+	args []string,
+	adminClients map[string]admin.Client,
+	report *apply.ApplyReport,
+) error {
+	matchCount := 0
+
+	for _, arg := range args {
+		if applyConfig.pathPrefix != "" && !filepath.IsAbs(arg) {
+			arg = filepath.Join(applyConfig.pathPrefix, arg)
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+			if err := applyACLs(
+				ctx,
+				match,
+				adminClients,
+				applyConfig.shared,
+				applyConfig.dryRun,
+				applyConfig.skipConfirm,
+				report,
+				nil,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	if matchCount == 0 {
+		return fmt.Errorf("No ACL configs match the provided args (%+v)", args)
+	}
+
+	return nil
+}
+
+func writeApplyReport(report *apply.ApplyReport) error {
+	markdown, err := report.ToMarkdown()
+	if err != nil {
+		return err
+	}
+
+	if applyConfig.reportFile == "" {
+		fmt.Print(markdown)
+		return nil
+	}
+
+	log.Infof("Writing apply report to %s", applyConfig.reportFile)
+	return ioutil.WriteFile(applyConfig.reportFile, []byte(markdown), 0644)
+}
+
 func applyTopic(
 	ctx context.Context,
 	topicConfigPath string,
 	adminClients map[string]admin.Client,
+	clusterThroughputs map[string]metrics.PartitionThroughputs,
+	moveBudget *rebalancers.MoveBudget,
+	report *apply.ApplyReport,
+	progressReporter *progress.Reporter,
 ) error {
 	clusterConfigPath, err := clusterConfigForTopicApply(topicConfigPath)
 	if err != nil {
 		return err
 	}
 
-	topicConfigs, err := config.LoadTopicsFile(topicConfigPath)
+	values, err := applyConfig.shared.templateValues()
 	if err != nil {
 		return err
 	}
 
-	clusterConfig, err := config.LoadClusterFile(clusterConfigPath, applyConfig.shared.expandEnv)
+	topicConfigs, err := config.LoadTopicsFileWithValues(topicConfigPath, values)
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		applyConfig.shared.expandEnv,
+		values,
+	)
 	if err != nil {
 		return err
 	}
 
+	if err := clusterConfig.CheckTopicFileName(topicConfigPath, topicConfigs); err != nil {
+		return err
+	}
+	if applyConfig.rebalance && clusterConfig.Spec.CruiseControl != nil {
+		return fmt.Errorf(
+			"Rebalances for cluster %s are delegated to Cruise Control; run the rebalance subcommand instead",
+			clusterConfig.Meta.Name,
+		)
+	}
+
 	adminClient, ok := adminClients[clusterConfigPath]
 	if !ok {
 		adminClient, err = clusterConfig.NewAdminClient(
@@ -197,12 +438,32 @@ func applyTopic(
 		if err != nil {
 			return err
 		}
+		adminClient = auditedAdminClient(
+			tracedAdminClient(adminClient),
+			clusterConfig.Meta.Name,
+			applyConfig.dryRun,
+		)
 		adminClients[clusterConfigPath] = adminClient
 	}
 
+	var throughputs metrics.PartitionThroughputs
+	if applyConfig.byThroughput {
+		throughputs, ok = clusterThroughputs[clusterConfigPath]
+		if !ok {
+			throughputs, err = partitionThroughputs(ctx, clusterConfig, adminClient)
+			if err != nil {
+				return err
+			}
+			clusterThroughputs[clusterConfigPath] = throughputs
+		}
+	}
+
 	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
 
 	for _, topicConfig := range topicConfigs {
+		if err := topicConfig.ResolveSettingsProfile(clusterConfig); err != nil {
+			return err
+		}
 		topicConfig.SetDefaults()
 		log.Infof(
 			"Processing topic %s in config %s with cluster config %s",
@@ -212,11 +473,15 @@ func applyTopic(
 		)
 
 		applierConfig := apply.TopicApplierConfig{
+			AllowProtected:             applyConfig.allowProtected,
 			BrokerThrottleMBsOverride:  applyConfig.brokerThrottleMBsOverride,
 			BrokersToRemove:            applyConfig.brokersToRemove,
 			ClusterConfig:              clusterConfig,
 			DryRun:                     applyConfig.dryRun,
+			MoveBudget:                 moveBudget,
 			PartitionBatchSizeOverride: applyConfig.partitionBatchSizeOverride,
+			PartitionThroughputs:       throughputs,
+			Progress:                   progressReporter,
 			Rebalance:                  applyConfig.rebalance,
 			RetentionDropStepDuration:  applyConfig.retentionDropStepDuration,
 			SkipConfirm:                applyConfig.skipConfirm,
@@ -224,7 +489,7 @@ func applyTopic(
 			TopicConfig:                topicConfig,
 		}
 
-		if err := cliRunner.ApplyTopic(ctx, applierConfig); err != nil {
+		if err := cliRunner.ApplyTopic(ctx, applierConfig, report); err != nil {
 			return err
 		}
 	}
@@ -238,10 +503,11 @@ func clusterConfigForTopicApply(topicConfigPath string) (string, error) {
 	}
 
 	return filepath.Abs(
-		filepath.Join(
-			filepath.Dir(topicConfigPath),
-			"..",
-			"cluster.yaml",
+		config.ClusterConfigPathForDir(
+			filepath.Join(
+				filepath.Dir(topicConfigPath),
+				"..",
+			),
 		),
 	)
 }
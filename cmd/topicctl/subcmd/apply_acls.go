@@ -0,0 +1,357 @@
+package subcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var applyACLsCmd = &cobra.Command{
+	Use:     "apply-acls [acl configs]",
+	Short:   "apply one or more ACL configs",
+	Args:    cobra.ArbitraryArgs,
+	PreRunE: applyACLsPreRun,
+	RunE:    applyACLsRun,
+}
+
+type applyACLsCmdConfig struct {
+	dryRun      bool
+	ownership   bool
+	skipConfirm bool
+
+	shared sharedOptions
+}
+
+var applyACLsConfig applyACLsCmdConfig
+
+func init() {
+	applyACLsCmd.Flags().BoolVar(
+		&applyACLsConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	applyACLsCmd.Flags().BoolVar(
+		&applyACLsConfig.ownership,
+		"ownership",
+		false,
+		"Also apply the consumer group ACLs generated from the consumerPrincipals in the cluster's topic configs",
+	)
+	applyACLsCmd.Flags().BoolVar(
+		&applyACLsConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+
+	addSharedConfigOnlyFlags(applyACLsCmd, &applyACLsConfig.shared)
+	RootCmd.AddCommand(applyACLsCmd)
+}
+
+func applyACLsPreRun(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !applyACLsConfig.ownership {
+		return errors.New("Must set at least one ACL config or ownership")
+	}
+	if applyACLsConfig.ownership && applyACLsConfig.shared.clusterConfig == "" {
+		return errors.New("Must set cluster-config when using ownership")
+	}
+	return nil
+}
+
+func applyACLsRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	// Keep a cache of the admin clients with the cluster config path as the key
+	adminClients := map[string]admin.Client{}
+
+	defer func() {
+		for _, adminClient := range adminClients {
+			adminClient.Close()
+		}
+	}()
+
+	// The ownership ACLs are generated up front so that the ones for groups that are also declared
+	// in the argument ACL configs can be merged into those instead of being applied separately
+	var ownershipConfig *config.ACLConfig
+	var ownershipClusterConfig config.ClusterConfig
+
+	if applyACLsConfig.ownership {
+		generatedConfig, clusterConfig, err := ownershipACLConfig(applyACLsConfig.shared)
+		if err != nil {
+			return err
+		}
+		ownershipConfig = &generatedConfig
+		ownershipClusterConfig = clusterConfig
+	}
+
+	matchCount := 0
+
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+			if err := applyACLs(
+				ctx,
+				match,
+				adminClients,
+				applyACLsConfig.shared,
+				applyACLsConfig.dryRun,
+				applyACLsConfig.skipConfirm,
+				nil,
+				ownershipConfig,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(args) > 0 && matchCount == 0 {
+		return fmt.Errorf("No ACL configs match the provided args (%+v)", args)
+	}
+
+	if ownershipConfig != nil {
+		return applyOwnershipACLs(
+			ctx,
+			*ownershipConfig,
+			ownershipClusterConfig,
+			adminClients,
+			applyACLsConfig.shared,
+			applyACLsConfig.dryRun,
+			applyACLsConfig.skipConfirm,
+		)
+	}
+
+	return nil
+}
+
+// applyACLs applies the ACL configs in the argument file. It's shared by the apply-acls and apply
+// subcommands, so the options are passed in instead of being read from either's flags.
+//
+// If ownershipConfig is set, the generated ownership ACLs for the groups that are declared in the
+// file are merged into the file's resources and removed from ownershipConfig.
+func applyACLs(
+	ctx context.Context,
+	aclConfigPath string,
+	adminClients map[string]admin.Client,
+	shared sharedOptions,
+	dryRun bool,
+	skipConfirm bool,
+	report *apply.ApplyReport,
+	ownershipConfig *config.ACLConfig,
+) error {
+	clusterConfigPath := shared.clusterConfig
+	if clusterConfigPath == "" {
+		var err error
+		clusterConfigPath, err = filepath.Abs(
+			config.ClusterConfigPathForDir(
+				filepath.Join(
+					filepath.Dir(aclConfigPath),
+					"..",
+				),
+			),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	values, err := shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	aclConfigs, err := config.LoadACLsFile(aclConfigPath, values)
+	if err != nil {
+		return err
+	}
+
+	if ownershipConfig != nil {
+		if groups := config.MergeOwnershipACLs(aclConfigs, ownershipConfig); len(groups) > 0 {
+			log.Infof(
+				"Merging the ownership ACLs for groups %+v into the resources in config %s",
+				groups,
+				aclConfigPath,
+			)
+		}
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	return applyACLConfigsForCluster(
+		ctx,
+		aclConfigs,
+		aclConfigPath,
+		clusterConfigPath,
+		clusterConfig,
+		adminClients,
+		shared,
+		dryRun,
+		skipConfirm,
+		report,
+	)
+}
+
+// ownershipACLConfig generates the consumer group ACLs from the consumer principals that are
+// declared in the managed topic configs of the cluster in the shared options. All of the
+// cluster's topic configs are used, even ones that aren't being applied, so that groups which
+// are declared in several topics keep the ACLs for all of their principals.
+func ownershipACLConfig(shared sharedOptions) (config.ACLConfig, config.ClusterConfig, error) {
+	values, err := shared.templateValues()
+	if err != nil {
+		return config.ACLConfig{}, config.ClusterConfig{}, err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		shared.clusterConfig,
+		shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return config.ACLConfig{}, config.ClusterConfig{}, err
+	}
+
+	topicConfigs, err := managedTopicConfigs(clusterConfig)
+	if err != nil {
+		return config.ACLConfig{}, config.ClusterConfig{}, err
+	}
+
+	return config.OwnershipACLConfig(clusterConfig, topicConfigs), clusterConfig, nil
+}
+
+// applyOwnershipACLs applies the argument generated ownership ACLs, minus any that were already
+// merged into the ACL configs being applied, to the cluster in the shared options.
+func applyOwnershipACLs(
+	ctx context.Context,
+	aclConfig config.ACLConfig,
+	clusterConfig config.ClusterConfig,
+	adminClients map[string]admin.Client,
+	shared sharedOptions,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	if len(aclConfig.Spec.Resources) == 0 {
+		log.Infof(
+			"No ownership ACLs left to apply for cluster %s",
+			clusterConfig.Meta.Name,
+		)
+		return nil
+	}
+
+	return applyACLConfigsForCluster(
+		ctx,
+		[]config.ACLConfig{aclConfig},
+		"generated from topic ownership",
+		shared.clusterConfig,
+		clusterConfig,
+		adminClients,
+		shared,
+		dryRun,
+		skipConfirm,
+		nil,
+	)
+}
+
+// applyACLConfigsForCluster applies the argument ACL configs, which come from the argument
+// source, to the cluster in the argument cluster config.
+func applyACLConfigsForCluster(
+	ctx context.Context,
+	aclConfigs []config.ACLConfig,
+	source string,
+	clusterConfigPath string,
+	clusterConfig config.ClusterConfig,
+	adminClients map[string]admin.Client,
+	shared sharedOptions,
+	dryRun bool,
+	skipConfirm bool,
+	report *apply.ApplyReport,
+) error {
+	var err error
+
+	adminClient, ok := adminClients[clusterConfigPath]
+	if !ok {
+		adminClient, err = clusterConfig.NewAdminClient(
+			ctx,
+			nil,
+			dryRun,
+			shared.saslUsername,
+			shared.saslPassword,
+		)
+		if err != nil {
+			return err
+		}
+		adminClient = auditedAdminClient(
+			adminClient,
+			clusterConfig.Meta.Name,
+			dryRun,
+		)
+		adminClients[clusterConfigPath] = adminClient
+	}
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
+
+	for _, aclConfig := range aclConfigs {
+		if err := aclConfig.ResolveACLs(clusterConfig); err != nil {
+			return err
+		}
+		if err := aclConfig.Validate(); err != nil {
+			return fmt.Errorf("Invalid ACL config %s: %+v", source, err)
+		}
+		if err := config.CheckACLConsistency(aclConfig, clusterConfig); err != nil {
+			return fmt.Errorf(
+				"ACL config %s is not consistent with cluster config %s: %+v",
+				source,
+				clusterConfigPath,
+				err,
+			)
+		}
+
+		log.Infof(
+			"Processing ACLs %s in config %s with cluster config %s",
+			aclConfig.Meta.Name,
+			source,
+			clusterConfigPath,
+		)
+
+		if err := cliRunner.ApplyACLs(
+			ctx,
+			aclConfig,
+			dryRun,
+			skipConfirm,
+			report,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package subcmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var applyQuotasCmd = &cobra.Command{
+	Use:   "apply-quotas",
+	Short: "apply the client quotas in a cluster config",
+	Args:  cobra.NoArgs,
+	RunE:  applyQuotasRun,
+}
+
+type applyQuotasCmdConfig struct {
+	dryRun      bool
+	skipConfirm bool
+
+	shared sharedOptions
+}
+
+var applyQuotasConfig applyQuotasCmdConfig
+
+func init() {
+	applyQuotasCmd.Flags().BoolVar(
+		&applyQuotasConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	applyQuotasCmd.Flags().BoolVar(
+		&applyQuotasConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+
+	addSharedConfigOnlyFlags(applyQuotasCmd, &applyQuotasConfig.shared)
+	applyQuotasCmd.MarkFlagRequired("cluster-config")
+	RootCmd.AddCommand(applyQuotasCmd)
+}
+
+func applyQuotasRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	values, err := applyQuotasConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		applyQuotasConfig.shared.clusterConfig,
+		applyQuotasConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	adminClient, err := clusterConfig.NewAdminClient(
+		ctx,
+		nil,
+		applyQuotasConfig.dryRun,
+		applyQuotasConfig.shared.saslUsername,
+		applyQuotasConfig.shared.saslPassword,
+	)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+	adminClient = auditedAdminClient(adminClient, clusterConfig.Meta.Name, applyQuotasConfig.dryRun)
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
+	return cliRunner.ApplyQuotas(
+		ctx,
+		clusterConfig,
+		applyQuotasConfig.dryRun,
+		applyQuotasConfig.skipConfirm,
+	)
+}
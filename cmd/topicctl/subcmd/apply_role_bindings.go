@@ -0,0 +1,181 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/rbac"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var applyRoleBindingsCmd = &cobra.Command{
+	Use:   "apply-role-bindings [role binding configs]",
+	Short: "apply one or more Confluent RBAC role binding configs",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  applyRoleBindingsRun,
+}
+
+type applyRoleBindingsCmdConfig struct {
+	dryRun      bool
+	skipConfirm bool
+
+	shared sharedOptions
+}
+
+var applyRoleBindingsConfig applyRoleBindingsCmdConfig
+
+func init() {
+	applyRoleBindingsCmd.Flags().BoolVar(
+		&applyRoleBindingsConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	applyRoleBindingsCmd.Flags().BoolVar(
+		&applyRoleBindingsConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+
+	addSharedConfigOnlyFlags(applyRoleBindingsCmd, &applyRoleBindingsConfig.shared)
+	RootCmd.AddCommand(applyRoleBindingsCmd)
+}
+
+func applyRoleBindingsRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	// Keep a cache of the RBAC clients with the cluster config path as the key
+	rbacClients := map[string]rbac.Client{}
+
+	matchCount := 0
+
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+			if err := applyRoleBindings(ctx, match, rbacClients); err != nil {
+				return err
+			}
+		}
+	}
+
+	if matchCount == 0 {
+		return fmt.Errorf("No role binding configs match the provided args (%+v)", args)
+	}
+
+	return nil
+}
+
+func applyRoleBindings(
+	ctx context.Context,
+	roleBindingConfigPath string,
+	rbacClients map[string]rbac.Client,
+) error {
+	clusterConfigPath := applyRoleBindingsConfig.shared.clusterConfig
+	if clusterConfigPath == "" {
+		var err error
+		clusterConfigPath, err = filepath.Abs(
+			config.ClusterConfigPathForDir(
+				filepath.Join(
+					filepath.Dir(roleBindingConfigPath),
+					"..",
+				),
+			),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	values, err := applyRoleBindingsConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	roleBindingConfigs, err := config.LoadRoleBindingsFile(roleBindingConfigPath, values)
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		applyRoleBindingsConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	rbacClient, ok := rbacClients[clusterConfigPath]
+	if !ok {
+		rbacClient, err = clusterConfig.NewRoleBindingClient(ctx)
+		if err != nil {
+			return err
+		}
+		rbacClient = auditedRoleBindingClient(rbacClient, clusterConfig.Meta.Name)
+		rbacClients[clusterConfigPath] = rbacClient
+	}
+
+	// Role bindings are managed via REST APIs, so no broker connections are needed
+	cliRunner := cli.NewCLIRunner(nil, log.Infof, false)
+
+	for _, roleBindingConfig := range roleBindingConfigs {
+		if err := roleBindingConfig.Validate(); err != nil {
+			return fmt.Errorf(
+				"Invalid role binding config %s: %+v",
+				roleBindingConfigPath,
+				err,
+			)
+		}
+		if err := config.CheckRoleBindingConsistency(roleBindingConfig, clusterConfig); err != nil {
+			return fmt.Errorf(
+				"Role binding config %s is not consistent with cluster config %s: %+v",
+				roleBindingConfigPath,
+				clusterConfigPath,
+				err,
+			)
+		}
+		if err := roleBindingConfig.ResolvePrincipals(clusterConfig); err != nil {
+			return err
+		}
+
+		log.Infof(
+			"Processing role bindings %s in config %s with cluster config %s",
+			roleBindingConfig.Meta.Name,
+			roleBindingConfigPath,
+			clusterConfigPath,
+		)
+
+		if err := cliRunner.ApplyRoleBindings(
+			ctx,
+			rbacClient,
+			roleBindingConfig,
+			applyRoleBindingsConfig.dryRun,
+			applyRoleBindingsConfig.skipConfirm,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
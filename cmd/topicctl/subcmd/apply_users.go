@@ -0,0 +1,196 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var applyUsersCmd = &cobra.Command{
+	Use:   "apply-users [user configs]",
+	Short: "apply one or more user configs",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  applyUsersRun,
+}
+
+type applyUsersCmdConfig struct {
+	dryRun          bool
+	skipConfirm     bool
+	updatePasswords bool
+
+	shared sharedOptions
+}
+
+var applyUsersConfig applyUsersCmdConfig
+
+func init() {
+	applyUsersCmd.Flags().BoolVar(
+		&applyUsersConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	applyUsersCmd.Flags().BoolVar(
+		&applyUsersConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+	applyUsersCmd.Flags().BoolVar(
+		&applyUsersConfig.updatePasswords,
+		"update-passwords",
+		false,
+		"Reset the passwords of existing credentials to the ones referenced in the config",
+	)
+
+	addSharedConfigOnlyFlags(applyUsersCmd, &applyUsersConfig.shared)
+	RootCmd.AddCommand(applyUsersCmd)
+}
+
+func applyUsersRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	// Keep a cache of the admin clients with the cluster config path as the key
+	adminClients := map[string]admin.Client{}
+
+	defer func() {
+		for _, adminClient := range adminClients {
+			adminClient.Close()
+		}
+	}()
+
+	matchCount := 0
+
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+			if err := applyUsers(ctx, match, adminClients); err != nil {
+				return err
+			}
+		}
+	}
+
+	if matchCount == 0 {
+		return fmt.Errorf("No user configs match the provided args (%+v)", args)
+	}
+
+	return nil
+}
+
+func applyUsers(
+	ctx context.Context,
+	userConfigPath string,
+	adminClients map[string]admin.Client,
+) error {
+	clusterConfigPath := applyUsersConfig.shared.clusterConfig
+	if clusterConfigPath == "" {
+		var err error
+		clusterConfigPath, err = filepath.Abs(
+			config.ClusterConfigPathForDir(
+				filepath.Join(
+					filepath.Dir(userConfigPath),
+					"..",
+				),
+			),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	values, err := applyUsersConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	userConfigs, err := config.LoadUsersFile(userConfigPath, values)
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		applyUsersConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	adminClient, ok := adminClients[clusterConfigPath]
+	if !ok {
+		adminClient, err = clusterConfig.NewAdminClient(
+			ctx,
+			nil,
+			applyUsersConfig.dryRun,
+			applyUsersConfig.shared.saslUsername,
+			applyUsersConfig.shared.saslPassword,
+		)
+		if err != nil {
+			return err
+		}
+		adminClient = auditedAdminClient(
+			adminClient,
+			clusterConfig.Meta.Name,
+			applyUsersConfig.dryRun,
+		)
+		adminClients[clusterConfigPath] = adminClient
+	}
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
+
+	for _, userConfig := range userConfigs {
+		if err := userConfig.Validate(); err != nil {
+			return fmt.Errorf("Invalid user config %s: %+v", userConfigPath, err)
+		}
+		if err := config.CheckUserConsistency(userConfig, clusterConfig); err != nil {
+			return fmt.Errorf(
+				"User config %s is not consistent with cluster config %s: %+v",
+				userConfigPath,
+				clusterConfigPath,
+				err,
+			)
+		}
+
+		log.Infof(
+			"Processing users %s in config %s with cluster config %s",
+			userConfig.Meta.Name,
+			userConfigPath,
+			clusterConfigPath,
+		)
+
+		if err := cliRunner.ApplyUsers(
+			ctx,
+			userConfig,
+			applyUsersConfig.updatePasswords,
+			applyUsersConfig.dryRun,
+			applyUsersConfig.skipConfirm,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/audit"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [resource type]",
+	Short: "query the local audit log of cluster mutations",
+	Long: strings.Join(
+		[]string{
+			"Query the local audit log of cluster mutations.",
+			"Supported types currently include: log.",
+			"",
+			"See the tool README for a detailed description of each one.",
+		},
+		"\n",
+	),
+	Args:    cobra.MinimumNArgs(1),
+	PreRunE: auditPreRun,
+	RunE:    auditRun,
+}
+
+type auditCmdConfig struct {
+	cluster   string
+	limit     int
+	operation string
+	output    string
+	since     string
+	topic     string
+}
+
+var auditConfig auditCmdConfig
+
+func init() {
+	auditCmd.Flags().StringVar(
+		&auditConfig.cluster,
+		"cluster",
+		"",
+		"Only show entries for this cluster",
+	)
+	auditCmd.Flags().IntVar(
+		&auditConfig.limit,
+		"limit",
+		0,
+		"Only show this many of the most recent entries; 0 shows all of them",
+	)
+	auditCmd.Flags().StringVar(
+		&auditConfig.operation,
+		"operation",
+		"",
+		fmt.Sprintf("Only show entries for this operation (choices: %+v)", audit.AllOperations),
+	)
+	auditCmd.Flags().StringVar(
+		&auditConfig.output,
+		"output",
+		string(cli.OutputFormatTable),
+		fmt.Sprintf(
+			"Output format (choices: %s); structured formats are printed to stdout",
+			outputFormatChoices(),
+		),
+	)
+	auditCmd.Flags().StringVar(
+		&auditConfig.since,
+		"since",
+		"",
+		"Only show entries at or after this time (RFC3339 time, date, or duration ago)",
+	)
+	auditCmd.Flags().StringVar(
+		&auditConfig.topic,
+		"topic",
+		"",
+		"Only show entries for this topic",
+	)
+
+	RootCmd.AddCommand(auditCmd)
+}
+
+func auditPreRun(cmd *cobra.Command, args []string) error {
+	if auditLogPath == "" {
+		return errors.New("Audit log path must be set")
+	}
+	if auditConfig.limit < 0 {
+		return errors.New("Limit cannot be negative")
+	}
+	if auditConfig.operation != "" {
+		var found bool
+		for _, operation := range audit.AllOperations {
+			if audit.Operation(auditConfig.operation) == operation {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf(
+				"Unrecognized operation %s; choices are %+v",
+				auditConfig.operation,
+				audit.AllOperations,
+			)
+		}
+	}
+
+	_, err := cli.ParseOutputFormat(auditConfig.output)
+	return err
+}
+
+func auditRun(cmd *cobra.Command, args []string) error {
+	resource := args[0]
+
+	switch resource {
+	case "log":
+		filter := audit.Filter{
+			Cluster:   auditConfig.cluster,
+			Topic:     auditConfig.topic,
+			Operation: audit.Operation(auditConfig.operation),
+			Limit:     auditConfig.limit,
+		}
+		if auditConfig.since != "" {
+			since, err := util.ParseTime(auditConfig.since, time.Now())
+			if err != nil {
+				return err
+			}
+			filter.Since = since
+		}
+
+		cliRunner := cli.NewCLIRunner(nil, log.Infof, false)
+		cliRunner.SetOutputFormat(cli.OutputFormat(auditConfig.output))
+		return cliRunner.GetAuditLog(auditLogPath, filter)
+	default:
+		return fmt.Errorf("Unrecognized resource type: %s", resource)
+	}
+}
@@ -18,6 +18,8 @@ var bootstrapCmd = &cobra.Command{
 type bootstrapCmdConfig struct {
 	matchRegexp   string
 	excludeRegexp string
+	includeACLs   bool
+	includeGroups bool
 	outputDir     string
 	overwrite     bool
 
@@ -39,6 +41,18 @@ func init() {
 		".^",
 		"Exclude regexp",
 	)
+	bootstrapCmd.Flags().BoolVar(
+		&bootstrapConfig.includeACLs,
+		"include-acls",
+		false,
+		"Also bootstrap the ACLs in the cluster to acls.yaml",
+	)
+	bootstrapCmd.Flags().BoolVar(
+		&bootstrapConfig.includeGroups,
+		"include-groups",
+		false,
+		"Also snapshot the offsets of the consumer groups in the bootstrapped topics",
+	)
 	bootstrapCmd.Flags().StringVarP(
 		&bootstrapConfig.outputDir,
 		"output",
@@ -55,6 +69,10 @@ func init() {
 
 	addSharedConfigOnlyFlags(bootstrapCmd, &bootstrapConfig.shared)
 	bootstrapCmd.MarkFlagRequired("cluster-config")
+	bootstrapCmd.ValidArgsFunction = completeRepeatedArgs(
+		&bootstrapConfig.shared,
+		completionKindTopics,
+	)
 	RootCmd.AddCommand(bootstrapCmd)
 }
 
@@ -62,9 +80,15 @@ func bootstrapRun(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	clusterConfig, err := config.LoadClusterFile(
+	values, err := bootstrapConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
 		bootstrapConfig.shared.clusterConfig,
 		bootstrapConfig.shared.expandEnv,
+		values,
 	)
 	if err != nil {
 		return err
@@ -89,5 +113,7 @@ func bootstrapRun(cmd *cobra.Command, args []string) error {
 		bootstrapConfig.excludeRegexp,
 		bootstrapConfig.outputDir,
 		bootstrapConfig.overwrite,
+		bootstrapConfig.includeGroups,
+		bootstrapConfig.includeACLs,
 	)
 }
@@ -0,0 +1,118 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/cli"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel [resource type]",
+	Short: "cancel in-progress operations of a particular type",
+	Long: strings.Join(
+		[]string{
+			"Cancel in-progress operations of a particular type.",
+			"Supported types currently include: reassignment.",
+			"",
+			"See the tool README for a detailed description of each one.",
+		},
+		"\n",
+	),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: cancelPreRun,
+	RunE:    cancelRun,
+}
+
+type cancelCmdConfig struct {
+	all         bool
+	dryRun      bool
+	skipConfirm bool
+	topic       string
+
+	shared sharedOptions
+}
+
+var cancelConfig cancelCmdConfig
+
+func init() {
+	cancelCmd.Flags().BoolVar(
+		&cancelConfig.all,
+		"all",
+		false,
+		"Cancel the reassignments for all topics",
+	)
+	cancelCmd.Flags().BoolVar(
+		&cancelConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	cancelCmd.Flags().BoolVar(
+		&cancelConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+	cancelCmd.Flags().StringVar(
+		&cancelConfig.topic,
+		"topic",
+		"",
+		"Only cancel the reassignments for this topic",
+	)
+
+	addSharedFlags(cancelCmd, &cancelConfig.shared)
+	cancelCmd.ValidArgsFunction = cancelComplete
+	RootCmd.AddCommand(cancelCmd)
+}
+
+func cancelComplete(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeStatic([]string{"reassignment"}, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func cancelPreRun(cmd *cobra.Command, args []string) error {
+	return cancelConfig.shared.validate()
+}
+
+func cancelRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	resource := args[0]
+
+	switch resource {
+	case "reassignment", "reassignments":
+		// Require an explicit scope so that all reassignments in the cluster aren't cancelled
+		// by accident.
+		if cancelConfig.all == (cancelConfig.topic != "") {
+			return fmt.Errorf("Must set exactly one of --topic or --all when cancelling reassignments")
+		}
+
+		adminClient, err := cancelConfig.shared.getAdminClient(ctx, sess, cancelConfig.dryRun)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		return cliRunner.CancelReassignments(
+			ctx,
+			cancelConfig.topic,
+			cancelConfig.dryRun,
+			cancelConfig.skipConfirm,
+		)
+	default:
+		return fmt.Errorf("Unrecognized resource type: %s", resource)
+	}
+}
@@ -2,6 +2,7 @@ package subcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,15 +16,26 @@ import (
 )
 
 var checkCmd = &cobra.Command{
-	Use:   "check [topic configs]",
-	Short: "check that configs are valid and (optionally) match cluster state",
-	RunE:  checkRun,
+	Use:     "check [topic configs]",
+	Short:   "check that configs are valid and (optionally) match cluster state",
+	PreRunE: checkPreRun,
+	RunE:    checkRun,
 }
 
+const (
+	checkOutputText  = "text"
+	checkOutputJUnit = "junit"
+	checkOutputSARIF = "sarif"
+)
+
 type checkCmdConfig struct {
-	checkLeaders bool
-	pathPrefix   string
-	validateOnly bool
+	aclConfigs       []string
+	checkAssignments bool
+	checkLeaders     bool
+	onlyFailures     bool
+	output           string
+	pathPrefix       string
+	validateOnly     bool
 
 	shared sharedOptions
 }
@@ -31,18 +43,47 @@ type checkCmdConfig struct {
 var checkConfig checkCmdConfig
 
 func init() {
+	checkCmd.Flags().StringArrayVar(
+		&checkConfig.aclConfigs,
+		"acls",
+		[]string{},
+		"ACL config(s) to check in addition to the topic configs; can be repeated and can contain globs",
+	)
 	checkCmd.Flags().StringVar(
 		&checkConfig.pathPrefix,
 		"path-prefix",
 		os.Getenv("TOPICCTL_APPLY_PATH_PREFIX"),
 		"Prefix for topic config paths",
 	)
+	checkCmd.Flags().BoolVar(
+		&checkConfig.checkAssignments,
+		"check-assignments",
+		false,
+		"Check the assignment setups of the consumer groups subscribed to each topic",
+	)
 	checkCmd.Flags().BoolVar(
 		&checkConfig.checkLeaders,
 		"check-leaders",
 		false,
 		"Check leaders",
 	)
+	checkCmd.Flags().BoolVar(
+		&checkConfig.onlyFailures,
+		"only-failures",
+		false,
+		"Only include topics with failed checks in the summary; only applies for text output",
+	)
+	checkCmd.Flags().StringVar(
+		&checkConfig.output,
+		"output",
+		checkOutputText,
+		fmt.Sprintf(
+			"Output format (choices: %s, %s, %s); junit and sarif reports are printed to stdout",
+			checkOutputText,
+			checkOutputJUnit,
+			checkOutputSARIF,
+		),
+	)
 	checkCmd.Flags().BoolVar(
 		&checkConfig.validateOnly,
 		"validate-only",
@@ -54,8 +95,43 @@ func init() {
 	RootCmd.AddCommand(checkCmd)
 }
 
+func checkPreRun(cmd *cobra.Command, args []string) error {
+	switch checkConfig.output {
+	case checkOutputText:
+		return nil
+	case checkOutputJUnit, checkOutputSARIF:
+		if checkConfig.onlyFailures {
+			return errors.New("Can only set only-failures with text output")
+		}
+		return nil
+	default:
+		return fmt.Errorf(
+			"Unrecognized output format %s; must be one of: %s, %s, %s",
+			checkConfig.output,
+			checkOutputText,
+			checkOutputJUnit,
+			checkOutputSARIF,
+		)
+	}
+}
+
 func checkRun(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, endTracing := startTracing(context.Background(), "check", args)
+	err := checkTopics(ctx, args)
+	endTracing(err)
+	return err
+}
+
+func checkTopics(ctx context.Context, args []string) error {
+	var report check.Report
+	switch checkConfig.output {
+	case checkOutputText:
+		report = &check.SummaryReport{OnlyFailures: checkConfig.onlyFailures}
+	case checkOutputJUnit:
+		report = &check.JUnitReport{}
+	case checkOutputSARIF:
+		report = &check.SARIFReport{}
+	}
 
 	// Keep a cache of the admin clients with the cluster config path as the key
 	adminClients := map[string]admin.Client{}
@@ -82,7 +158,31 @@ func checkRun(cmd *cobra.Command, args []string) error {
 		for _, match := range matches {
 			matchCount++
 
-			ok, err := checkTopicFile(ctx, match, adminClients)
+			ok, err := checkTopicFile(ctx, match, adminClients, report)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				okCount++
+			}
+		}
+	}
+
+	for _, arg := range checkConfig.aclConfigs {
+		if checkConfig.pathPrefix != "" && !filepath.IsAbs(arg) {
+			arg = filepath.Join(checkConfig.pathPrefix, arg)
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+
+			ok, err := checkACLFile(ctx, match, adminClients, report)
 			if err != nil {
 				return err
 			}
@@ -93,11 +193,23 @@ func checkRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if report != nil {
+		reportStr, err := report.Render()
+		if err != nil {
+			return err
+		}
+		fmt.Print(reportStr)
+	}
+
 	if matchCount == 0 {
-		return fmt.Errorf("No topic configs match the provided args (%+v)", args)
+		return fmt.Errorf(
+			"No topic or ACL configs match the provided args (%+v, %+v)",
+			args,
+			checkConfig.aclConfigs,
+		)
 	} else if matchCount > okCount {
 		return fmt.Errorf(
-			"Check failed for %d/%d topic configs",
+			"Check failed for %d/%d configs",
 			matchCount-okCount,
 			matchCount,
 		)
@@ -110,43 +222,57 @@ func checkTopicFile(
 	ctx context.Context,
 	topicConfigPath string,
 	adminClients map[string]admin.Client,
+	report check.Report,
 ) (bool, error) {
 	clusterConfigPath, err := clusterConfigForTopicCheck(topicConfigPath)
 	if err != nil {
 		return false, err
 	}
 
-	clusterConfig, err := config.LoadClusterFile(clusterConfigPath, checkConfig.shared.expandEnv)
+	values, err := checkConfig.shared.templateValues()
 	if err != nil {
 		return false, err
 	}
 
-	topicConfigs, err := config.LoadTopicsFile(topicConfigPath)
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		checkConfig.shared.expandEnv,
+		values,
+	)
 	if err != nil {
 		return false, err
 	}
 
-	var adminClient admin.Client
+	topicConfigs, err := config.LoadTopicsFileWithValues(topicConfigPath, values)
+	if err != nil {
+		return false, err
+	}
 
-	if !checkConfig.validateOnly {
-		var ok bool
-		adminClient, ok = adminClients[clusterConfigPath]
-		if !ok {
-			adminClient, err = clusterConfig.NewAdminClient(
-				ctx,
-				nil,
-				true,
-				checkConfig.shared.saslUsername,
-				checkConfig.shared.saslPassword,
+	if err := clusterConfig.CheckTopicFileName(topicConfigPath, topicConfigs); err != nil {
+		log.Errorf("Check failed for topic config %s: %+v", topicConfigPath, err)
+		if report != nil {
+			report.AddConfigFailure(
+				clusterConfig.Meta.Name,
+				topicConfigPath,
+				"file name correct",
+				err,
 			)
-			if err != nil {
-				return false, err
-			}
-			adminClients[clusterConfigPath] = adminClient
 		}
+		return false, nil
 	}
 
-	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
+	adminClient, err := checkAdminClient(ctx, clusterConfigPath, clusterConfig, adminClients)
+	if err != nil {
+		return false, err
+	}
+
+	// With text output, the per-topic results are only logged at the debug level since they're
+	// all included in the summary at the end
+	printer := log.Infof
+	if checkConfig.output == checkOutputText {
+		printer = log.Debugf
+	}
+	cliRunner := cli.NewCLIRunner(adminClient, printer, false)
 
 	for _, topicConfig := range topicConfigs {
 		topicConfig.SetDefaults()
@@ -158,36 +284,158 @@ func checkTopicFile(
 		)
 
 		topicCheckConfig := check.CheckConfig{
-			AdminClient:   adminClient,
-			CheckLeaders:  checkConfig.checkLeaders,
-			ClusterConfig: clusterConfig,
+			AdminClient:      adminClient,
+			CheckAssignments: checkConfig.checkAssignments,
+			CheckLeaders:     checkConfig.checkLeaders,
+			ClusterConfig:    clusterConfig,
 			// TODO: Add support for broker rack verification.
 			NumRacks:     -1,
 			TopicConfig:  topicConfig,
 			ValidateOnly: checkConfig.validateOnly,
 		}
-		result, err := cliRunner.CheckTopic(
+		results, err := cliRunner.CheckTopic(
 			ctx,
 			topicCheckConfig,
 		)
-		if !result || err != nil {
-			return result, err
+		if err != nil {
+			return false, err
+		}
+		if report != nil {
+			report.AddTopicResults(
+				clusterConfig.Meta.Name,
+				topicConfig.Meta.Name,
+				topicConfigPath,
+				results,
+			)
+		}
+		if !results.AllOK() {
+			return false, nil
 		}
 	}
 
 	return true, nil
 }
 
+func checkACLFile(
+	ctx context.Context,
+	aclConfigPath string,
+	adminClients map[string]admin.Client,
+	report check.Report,
+) (bool, error) {
+	clusterConfigPath, err := clusterConfigForTopicCheck(aclConfigPath)
+	if err != nil {
+		return false, err
+	}
+
+	values, err := checkConfig.shared.templateValues()
+	if err != nil {
+		return false, err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		checkConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	aclConfigs, err := config.LoadACLsFile(aclConfigPath, values)
+	if err != nil {
+		return false, err
+	}
+
+	adminClient, err := checkAdminClient(ctx, clusterConfigPath, clusterConfig, adminClients)
+	if err != nil {
+		return false, err
+	}
+
+	printer := log.Infof
+	if checkConfig.output == checkOutputText {
+		printer = log.Debugf
+	}
+	cliRunner := cli.NewCLIRunner(adminClient, printer, false)
+
+	for _, aclConfig := range aclConfigs {
+		log.Debugf(
+			"Processing ACLs %s in config %s with cluster config %s",
+			aclConfig.Meta.Name,
+			aclConfigPath,
+			clusterConfigPath,
+		)
+
+		results, err := cliRunner.CheckACLs(
+			ctx,
+			check.CheckACLsConfig{
+				AdminClient:   adminClient,
+				ClusterConfig: clusterConfig,
+				ACLConfig:     aclConfig,
+				ValidateOnly:  checkConfig.validateOnly,
+			},
+		)
+		if err != nil {
+			return false, err
+		}
+		if report != nil {
+			report.AddTopicResults(
+				clusterConfig.Meta.Name,
+				aclConfig.Meta.Name,
+				aclConfigPath,
+				results,
+			)
+		}
+		if !results.AllOK() {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// checkAdminClient returns the cached admin client for the argument cluster config, creating it
+// if needed. Nil is returned if only validating.
+func checkAdminClient(
+	ctx context.Context,
+	clusterConfigPath string,
+	clusterConfig config.ClusterConfig,
+	adminClients map[string]admin.Client,
+) (admin.Client, error) {
+	if checkConfig.validateOnly {
+		return nil, nil
+	}
+
+	adminClient, ok := adminClients[clusterConfigPath]
+	if !ok {
+		var err error
+		adminClient, err = clusterConfig.NewAdminClient(
+			ctx,
+			nil,
+			true,
+			checkConfig.shared.saslUsername,
+			checkConfig.shared.saslPassword,
+		)
+		if err != nil {
+			return nil, err
+		}
+		adminClient = tracedAdminClient(adminClient)
+		adminClients[clusterConfigPath] = adminClient
+	}
+
+	return adminClient, nil
+}
+
 func clusterConfigForTopicCheck(topicConfigPath string) (string, error) {
 	if checkConfig.shared.clusterConfig != "" {
 		return checkConfig.shared.clusterConfig, nil
 	}
 
 	return filepath.Abs(
-		filepath.Join(
-			filepath.Dir(topicConfigPath),
-			"..",
-			"cluster.yaml",
+		config.ClusterConfigPathForDir(
+			filepath.Join(
+				filepath.Dir(topicConfigPath),
+				"..",
+			),
 		),
 	)
 }
@@ -0,0 +1,142 @@
+package subcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/audit"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup [resource type]",
+	Short: "clean up unused instances of a particular type",
+	Long: strings.Join(
+		[]string{
+			"Clean up unused instances of a particular type.",
+			"Supported types currently include: groups.",
+			"",
+			"See the tool README for a detailed description of each one.",
+		},
+		"\n",
+	),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: cleanupPreRun,
+	RunE:    cleanupRun,
+}
+
+type cleanupCmdConfig struct {
+	dryRun      bool
+	idleFor     string
+	skipConfirm bool
+
+	shared sharedOptions
+}
+
+var cleanupConfig cleanupCmdConfig
+
+func init() {
+	cleanupCmd.Flags().BoolVar(
+		&cleanupConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	cleanupCmd.Flags().StringVar(
+		&cleanupConfig.idleFor,
+		"idle-for",
+		"",
+		"Only clean up groups that haven't consumed anything in this long, e.g. 30d",
+	)
+	cleanupCmd.Flags().BoolVar(
+		&cleanupConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+
+	addSharedFlags(cleanupCmd, &cleanupConfig.shared)
+	cleanupCmd.ValidArgsFunction = cleanupComplete
+	RootCmd.AddCommand(cleanupCmd)
+}
+
+func cleanupComplete(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeStatic([]string{"groups"}, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func cleanupPreRun(cmd *cobra.Command, args []string) error {
+	return cleanupConfig.shared.validate()
+}
+
+func cleanupRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	resource := args[0]
+
+	switch resource {
+	case "group", "groups":
+		if cleanupConfig.idleFor == "" {
+			return errors.New("Must set --idle-for")
+		}
+		idleFor, err := util.ParseDuration(cleanupConfig.idleFor)
+		if err != nil {
+			return err
+		}
+		if idleFor <= 0 {
+			return errors.New("Idle duration must be positive")
+		}
+
+		adminClient, clusterName, err := cleanupConfig.shared.newAdminClient(
+			ctx,
+			sess,
+			cleanupConfig.dryRun,
+			config.ListenerPurposeAdmin,
+		)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		deleted, err := cliRunner.CleanupGroups(
+			ctx,
+			idleFor,
+			cleanupConfig.dryRun,
+			cleanupConfig.skipConfirm,
+		)
+
+		for _, group := range deleted {
+			recordAuditEntry(
+				audit.Entry{
+					Operation: audit.OperationDeleteGroup,
+					Cluster:   clusterName,
+					Details: fmt.Sprintf(
+						"group %s, idle for %s",
+						group,
+						cleanupConfig.idleFor,
+					),
+				},
+				nil,
+			)
+		}
+
+		return err
+	default:
+		return fmt.Errorf("Unrecognized resource type: %s", resource)
+	}
+}
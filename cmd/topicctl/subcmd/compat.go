@@ -0,0 +1,65 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/cli"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var compatCmd = &cobra.Command{
+	Use:     "compat",
+	Short:   "report which topicctl features the cluster supports",
+	Long:    "Check the API versions supported by each broker and report which topicctl features are available in the cluster.",
+	Args:    cobra.NoArgs,
+	PreRunE: compatPreRun,
+	RunE:    compatRun,
+}
+
+type compatCmdConfig struct {
+	output string
+
+	shared sharedOptions
+}
+
+var compatConfig compatCmdConfig
+
+func init() {
+	compatCmd.Flags().StringVar(
+		&compatConfig.output,
+		"output",
+		string(cli.OutputFormatTable),
+		fmt.Sprintf(
+			"Output format (choices: %s); structured formats are printed to stdout",
+			outputFormatChoices(),
+		),
+	)
+
+	addSharedFlags(compatCmd, &compatConfig.shared)
+	RootCmd.AddCommand(compatCmd)
+}
+
+func compatPreRun(cmd *cobra.Command, args []string) error {
+	if _, err := cli.ParseOutputFormat(compatConfig.output); err != nil {
+		return err
+	}
+	return compatConfig.shared.validate()
+}
+
+func compatRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	adminClient, err := compatConfig.shared.getAdminClient(ctx, sess, true)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+	cliRunner.SetOutputFormat(cli.OutputFormat(compatConfig.output))
+	return cliRunner.GetCompat(ctx)
+}
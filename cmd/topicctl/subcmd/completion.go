@@ -0,0 +1,264 @@
+package subcmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/groups"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// completionCacheTTL is how long the cluster metadata used for completions is cached for.
+	// Each completion runs in a new process, so the cache is stored on disk.
+	completionCacheTTL = time.Minute
+
+	// completionTimeout bounds how long fetching cluster metadata for a completion can take, so
+	// that the shell doesn't hang if the cluster isn't reachable.
+	completionTimeout = 5 * time.Second
+)
+
+type completionKind string
+
+const (
+	completionKindBrokers completionKind = "brokers"
+	completionKindGroups  completionKind = "groups"
+	completionKindTopics  completionKind = "topics"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [shell]",
+	Short: "generate a shell completion script",
+	Long: strings.Join(
+		[]string{
+			"Generate a shell completion script for bash, fish, or zsh.",
+			"",
+			"In bash and fish, topic, group, and broker ID arguments are also completed from the",
+			"cluster set in the command-line flags; in zsh, only subcommands and flags are completed.",
+			"",
+			"For example, to enable completions in the current bash shell:",
+			"",
+			"  source <(topicctl completion bash)",
+		},
+		"\n",
+	),
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "fish", "zsh"},
+	RunE:      completionRun,
+}
+
+func init() {
+	RootCmd.AddCommand(completionCmd)
+}
+
+func completionRun(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return RootCmd.GenBashCompletion(os.Stdout)
+	case "fish":
+		return RootCmd.GenFishCompletion(os.Stdout, true)
+	case "zsh":
+		return RootCmd.GenZshCompletion(os.Stdout)
+	default:
+		return fmt.Errorf("Unrecognized shell: %s", args[0])
+	}
+}
+
+// completePositionalArgs returns a completion function that completes each positional argument
+// against the cluster metadata of the corresponding kind. Arguments past the end of kinds aren't
+// completed; an empty kind means a static value that isn't completed.
+func completePositionalArgs(
+	shared *sharedOptions,
+	kinds ...completionKind,
+) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(
+		cmd *cobra.Command,
+		args []string,
+		toComplete string,
+	) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(kinds) || kinds[len(args)] == "" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeFromCluster(shared, toComplete, kinds[len(args)])
+	}
+}
+
+// completeRepeatedArgs returns a completion function that completes all positional arguments
+// against the cluster metadata of the argument kind.
+func completeRepeatedArgs(
+	shared *sharedOptions,
+	kind completionKind,
+) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(
+		cmd *cobra.Command,
+		args []string,
+		toComplete string,
+	) ([]string, cobra.ShellCompDirective) {
+		return completeFromCluster(shared, toComplete, kind)
+	}
+}
+
+// completeStatic returns the values that start with toComplete.
+func completeStatic(
+	values []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	return filterCompletions(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFromCluster returns the values of the argument kinds in the cluster that start with
+// toComplete. Since completion scripts ignore errors, these are reported as empty results.
+func completeFromCluster(
+	shared *sharedOptions,
+	toComplete string,
+	kinds ...completionKind,
+) ([]string, cobra.ShellCompDirective) {
+	values := []string{}
+
+	for _, kind := range kinds {
+		kindValues, err := completionValues(shared, kind)
+		if err != nil {
+			cobra.CompDebugln(fmt.Sprintf("Error getting %s: %+v", kind, err), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		values = append(values, kindValues...)
+	}
+
+	return filterCompletions(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+type completionCacheEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Values    []string  `json:"values"`
+}
+
+// completionValues gets all of the values of the argument kind in the cluster set in the
+// shared options, using the on-disk cache if it's fresh enough.
+func completionValues(shared *sharedOptions, kind completionKind) ([]string, error) {
+	if shared.clusterConfig == "" && shared.brokerAddr == "" && shared.zkAddr == "" {
+		return nil, nil
+	}
+
+	cachePath := completionCachePath(shared, kind)
+	if cachePath != "" {
+		if contents, err := ioutil.ReadFile(cachePath); err == nil {
+			entry := completionCacheEntry{}
+			if err := json.Unmarshal(contents, &entry); err == nil &&
+				time.Since(entry.Timestamp) < completionCacheTTL {
+				return entry.Values, nil
+			}
+		}
+	}
+
+	// The admin clients log connection details, which would otherwise end up in the shell
+	log.SetOutput(ioutil.Discard)
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	adminClient, err := shared.getAdminClient(ctx, session.Must(session.NewSession()), true)
+	if err != nil {
+		return nil, err
+	}
+	defer adminClient.Close()
+
+	values := []string{}
+
+	switch kind {
+	case completionKindBrokers:
+		brokerIDs, err := adminClient.GetBrokerIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Ints(brokerIDs)
+		for _, brokerID := range brokerIDs {
+			values = append(values, fmt.Sprintf("%d", brokerID))
+		}
+	case completionKindGroups:
+		groupCoordinators, err := groups.GetGroups(ctx, adminClient.GetConnector())
+		if err != nil {
+			return nil, err
+		}
+		for _, groupCoordinator := range groupCoordinators {
+			values = append(values, groupCoordinator.GroupID)
+		}
+		sort.Strings(values)
+	case completionKindTopics:
+		topicNames, err := adminClient.GetTopicNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, topicNames...)
+		sort.Strings(values)
+	default:
+		return nil, fmt.Errorf("Unrecognized completion kind: %s", kind)
+	}
+
+	if cachePath != "" {
+		contents, err := json.Marshal(
+			completionCacheEntry{
+				Timestamp: time.Now(),
+				Values:    values,
+			},
+		)
+		if err == nil && os.MkdirAll(filepath.Dir(cachePath), 0700) == nil {
+			// The cache is only an optimization, so errors writing it are ignored
+			ioutil.WriteFile(cachePath, contents, 0600)
+		}
+	}
+
+	return values, nil
+}
+
+// completionCachePath returns the path of the cache file for the argument kind and cluster, or
+// an empty string if there's no user cache directory.
+func completionCachePath(shared *sharedOptions, kind completionKind) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	clusterConfig := shared.clusterConfig
+	if clusterConfig != "" {
+		if absPath, err := filepath.Abs(clusterConfig); err == nil {
+			clusterConfig = absPath
+		}
+	}
+
+	key := sha256.Sum256(
+		[]byte(
+			strings.Join(
+				[]string{
+					string(kind),
+					clusterConfig,
+					shared.brokerAddr,
+					shared.zkAddr,
+					shared.zkPrefix,
+				},
+				"\n",
+			),
+		),
+	)
+
+	return filepath.Join(cacheDir, "topicctl", "completions", fmt.Sprintf("%x.json", key[:8]))
+}
+
+func filterCompletions(values []string, toComplete string) []string {
+	filtered := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) {
+			filtered = append(filtered, value)
+		}
+	}
+	return filtered
+}
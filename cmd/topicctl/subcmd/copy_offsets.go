@@ -0,0 +1,153 @@
+package subcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/audit"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var copyOffsetsCmd = &cobra.Command{
+	Use:   "copy-offsets",
+	Short: "copy committed offsets from one consumer group to another",
+	Long: strings.Join(
+		[]string{
+			"Copy the committed offsets of one consumer group to another.",
+			"",
+			"This lets the consumers in a new group, e.g. a blue/green deployment,",
+			"pick up exactly where the ones in the old group left off.",
+		},
+		"\n",
+	),
+	Args:    cobra.NoArgs,
+	PreRunE: copyOffsetsPreRun,
+	RunE:    copyOffsetsRun,
+}
+
+type copyOffsetsCmdConfig struct {
+	dryRun      bool
+	from        string
+	skipConfirm bool
+	to          string
+	topics      []string
+
+	shared sharedOptions
+}
+
+var copyOffsetsConfig copyOffsetsCmdConfig
+
+func init() {
+	copyOffsetsCmd.Flags().BoolVar(
+		&copyOffsetsConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	copyOffsetsCmd.Flags().StringVar(
+		&copyOffsetsConfig.from,
+		"from",
+		"",
+		"Group to copy the offsets from",
+	)
+	copyOffsetsCmd.Flags().BoolVar(
+		&copyOffsetsConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+	copyOffsetsCmd.Flags().StringVar(
+		&copyOffsetsConfig.to,
+		"to",
+		"",
+		"Group to copy the offsets to",
+	)
+	copyOffsetsCmd.Flags().StringArrayVar(
+		&copyOffsetsConfig.topics,
+		"topic",
+		[]string{},
+		"Only copy the offsets in this topic; can be repeated",
+	)
+
+	addSharedFlags(copyOffsetsCmd, &copyOffsetsConfig.shared)
+	copyOffsetsCmd.RegisterFlagCompletionFunc(
+		"from",
+		completeRepeatedArgs(&copyOffsetsConfig.shared, completionKindGroups),
+	)
+	copyOffsetsCmd.RegisterFlagCompletionFunc(
+		"to",
+		completeRepeatedArgs(&copyOffsetsConfig.shared, completionKindGroups),
+	)
+	copyOffsetsCmd.RegisterFlagCompletionFunc(
+		"topic",
+		completeRepeatedArgs(&copyOffsetsConfig.shared, completionKindTopics),
+	)
+	RootCmd.AddCommand(copyOffsetsCmd)
+}
+
+func copyOffsetsPreRun(cmd *cobra.Command, args []string) error {
+	if copyOffsetsConfig.from == "" || copyOffsetsConfig.to == "" {
+		return errors.New("Must set both --from and --to")
+	}
+	if copyOffsetsConfig.from == copyOffsetsConfig.to {
+		return errors.New("The --from and --to groups must be different")
+	}
+
+	return copyOffsetsConfig.shared.validate()
+}
+
+func copyOffsetsRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	adminClient, clusterName, err := copyOffsetsConfig.shared.newAdminClient(
+		ctx,
+		sess,
+		true,
+		config.ListenerPurposeAdmin,
+	)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+	err = cliRunner.CopyOffsets(
+		ctx,
+		copyOffsetsConfig.from,
+		copyOffsetsConfig.to,
+		copyOffsetsConfig.topics,
+		copyOffsetsConfig.dryRun,
+		copyOffsetsConfig.skipConfirm,
+	)
+
+	if !copyOffsetsConfig.dryRun {
+		entry := audit.Entry{
+			Operation: audit.OperationCopyOffsets,
+			Cluster:   clusterName,
+			Details: fmt.Sprintf(
+				"from group %s to group %s",
+				copyOffsetsConfig.from,
+				copyOffsetsConfig.to,
+			),
+		}
+		if len(copyOffsetsConfig.topics) == 1 {
+			entry.Topic = copyOffsetsConfig.topics[0]
+		} else if len(copyOffsetsConfig.topics) > 1 {
+			entry.Details = fmt.Sprintf(
+				"%s, topics %s",
+				entry.Details,
+				strings.Join(copyOffsetsConfig.topics, ","),
+			)
+		}
+		recordAuditEntry(entry, err)
+	}
+
+	return err
+}
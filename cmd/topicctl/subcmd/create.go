@@ -0,0 +1,149 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/cli"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create [resource type]",
+	Short: "create instances of a particular type",
+	Long: strings.Join(
+		[]string{
+			"Create instances of a particular type.",
+			"Supported types currently include: acl.",
+			"",
+			"See the tool README for a detailed description of each one.",
+		},
+		"\n",
+	),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: createPreRun,
+	RunE:    createRun,
+}
+
+type createCmdConfig struct {
+	dryRun       bool
+	host         string
+	operations   []string
+	patternType  string
+	permission   string
+	principals   []string
+	resourceName string
+	resourceType string
+	skipConfirm  bool
+
+	shared sharedOptions
+}
+
+var createConfig createCmdConfig
+
+func init() {
+	createCmd.Flags().BoolVar(
+		&createConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	createCmd.Flags().StringVar(
+		&createConfig.host,
+		"host",
+		"*",
+		"Host that the ACLs apply to",
+	)
+	createCmd.Flags().StringSliceVar(
+		&createConfig.operations,
+		"operation",
+		[]string{},
+		"Operation to allow or deny, e.g. read or write; can be repeated",
+	)
+	createCmd.Flags().StringVar(
+		&createConfig.patternType,
+		"pattern-type",
+		"literal",
+		"Resource pattern type (literal or prefixed)",
+	)
+	createCmd.Flags().StringVar(
+		&createConfig.permission,
+		"permission",
+		"allow",
+		"Permission type (allow or deny)",
+	)
+	createCmd.Flags().StringSliceVar(
+		&createConfig.principals,
+		"principal",
+		[]string{},
+		"Principal that the ACLs apply to, e.g. User:alice; can be repeated",
+	)
+	createCmd.Flags().StringVar(
+		&createConfig.resourceName,
+		"resource-name",
+		"",
+		"Name of the resource, e.g. a topic name; defaults to kafka-cluster for cluster resources",
+	)
+	createCmd.Flags().StringVar(
+		&createConfig.resourceType,
+		"resource-type",
+		"",
+		"Resource type, e.g. topic, group, cluster, or transactionalid",
+	)
+	createCmd.Flags().BoolVar(
+		&createConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+
+	addSharedFlags(createCmd, &createConfig.shared)
+	RootCmd.AddCommand(createCmd)
+}
+
+func createPreRun(cmd *cobra.Command, args []string) error {
+	return createConfig.shared.validate()
+}
+
+func createRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	resource := args[0]
+
+	switch resource {
+	case "acl":
+		acls, err := admin.ACLSpec{
+			ResourceType:   createConfig.resourceType,
+			ResourceName:   createConfig.resourceName,
+			PatternType:    createConfig.patternType,
+			Principals:     createConfig.principals,
+			Host:           createConfig.host,
+			Operations:     createConfig.operations,
+			PermissionType: createConfig.permission,
+		}.ACLs()
+		if err != nil {
+			return err
+		}
+
+		adminClient, err := createConfig.shared.getAdminClient(ctx, sess, createConfig.dryRun)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		return cliRunner.CreateACLs(
+			ctx,
+			acls,
+			createConfig.dryRun,
+			createConfig.skipConfirm,
+		)
+	default:
+		return fmt.Errorf("Unrecognized resource type: %s", resource)
+	}
+}
@@ -0,0 +1,341 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/audit"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete [resource type] [optional name]",
+	Short: "delete instances of a particular type",
+	Long: strings.Join(
+		[]string{
+			"Delete instances of a particular type.",
+			"Supported types currently include: acl, group, and topic.",
+			"",
+			"See the tool README for a detailed description of each one.",
+		},
+		"\n",
+	),
+	Args:    cobra.RangeArgs(1, 2),
+	PreRunE: deletePreRun,
+	RunE:    deleteRun,
+}
+
+type deleteCmdConfig struct {
+	allowProtected bool
+	consumerWindow time.Duration
+	dryRun         bool
+	force          bool
+	host           string
+	operation      string
+	patternType    string
+	permission     string
+	principal      string
+	resourceName   string
+	resourceType   string
+	skipConfirm    bool
+	topics         []string
+
+	shared sharedOptions
+}
+
+var deleteConfig deleteCmdConfig
+
+func init() {
+	deleteCmd.Flags().BoolVar(
+		&deleteConfig.allowProtected,
+		"allow-protected",
+		false,
+		"Allow deleting protected topics; only applies for topics",
+	)
+	deleteCmd.Flags().DurationVar(
+		&deleteConfig.consumerWindow,
+		"consumer-window",
+		time.Hour,
+		"Refuse to delete topics that consumer groups have been active in during this window",
+	)
+	deleteCmd.Flags().BoolVar(
+		&deleteConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run; only applies for acls and groups",
+	)
+	deleteCmd.Flags().BoolVar(
+		&deleteConfig.force,
+		"force",
+		false,
+		"Delete topics even if they aren't marked as deletable in their configs, or groups that have members",
+	)
+	deleteCmd.Flags().StringVar(
+		&deleteConfig.host,
+		"host",
+		"",
+		"Only delete ACLs for this host; only applies for acls",
+	)
+	deleteCmd.Flags().StringVar(
+		&deleteConfig.operation,
+		"operation",
+		"",
+		"Only delete ACLs for this operation, e.g. read or write; only applies for acls",
+	)
+	deleteCmd.Flags().StringVar(
+		&deleteConfig.patternType,
+		"pattern-type",
+		"",
+		"Only delete ACLs with this resource pattern type (literal or prefixed); only applies for acls",
+	)
+	deleteCmd.Flags().StringVar(
+		&deleteConfig.permission,
+		"permission",
+		"",
+		"Only delete ACLs with this permission type (allow or deny); only applies for acls",
+	)
+	deleteCmd.Flags().StringVar(
+		&deleteConfig.principal,
+		"principal",
+		"",
+		"Only delete ACLs for this principal, e.g. User:alice; only applies for acls",
+	)
+	deleteCmd.Flags().StringVar(
+		&deleteConfig.resourceName,
+		"resource-name",
+		"",
+		"Name of the resource to delete ACLs for; only applies for acls",
+	)
+	deleteCmd.Flags().StringVar(
+		&deleteConfig.resourceType,
+		"resource-type",
+		"",
+		"Type of the resource to delete ACLs for, e.g. topic or group; only applies for acls",
+	)
+	deleteCmd.Flags().BoolVar(
+		&deleteConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts; only applies for acls and groups",
+	)
+	deleteCmd.Flags().StringArrayVar(
+		&deleteConfig.topics,
+		"topic",
+		[]string{},
+		"Only delete the group's offsets in this topic; can be repeated; only applies for groups",
+	)
+
+	addSharedConfigOnlyFlags(deleteCmd, &deleteConfig.shared)
+	deleteCmd.MarkFlagRequired("cluster-config")
+	deleteCmd.ValidArgsFunction = deleteComplete
+	RootCmd.AddCommand(deleteCmd)
+}
+
+func deleteComplete(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return completeStatic([]string{"acl", "group", "topic"}, toComplete)
+	case len(args) == 1 && args[0] == "group":
+		return completeFromCluster(&deleteConfig.shared, toComplete, completionKindGroups)
+	case len(args) == 1 && args[0] == "topic":
+		return completeFromCluster(&deleteConfig.shared, toComplete, completionKindTopics)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func deletePreRun(cmd *cobra.Command, args []string) error {
+	return deleteConfig.shared.validate()
+}
+
+func deleteRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	resource := args[0]
+
+	switch resource {
+	case "acl":
+		if len(args) > 1 {
+			return fmt.Errorf("Positional arguments are not supported with acl; use flags instead")
+		}
+
+		// Like kafka-acls.sh, require a resource so that all ACLs in the cluster can't be
+		// deleted by accident.
+		if deleteConfig.resourceType == "" || deleteConfig.resourceName == "" {
+			return fmt.Errorf("Must set --resource-type and --resource-name when deleting acls")
+		}
+
+		adminClient, err := deleteConfig.shared.getAdminClient(ctx, sess, deleteConfig.dryRun)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		return cliRunner.DeleteACLs(
+			ctx,
+			admin.ACLFilter{
+				ResourceType:   deleteConfig.resourceType,
+				ResourceName:   deleteConfig.resourceName,
+				PatternType:    deleteConfig.patternType,
+				Principal:      deleteConfig.principal,
+				Host:           deleteConfig.host,
+				Operation:      deleteConfig.operation,
+				PermissionType: deleteConfig.permission,
+			},
+			deleteConfig.dryRun,
+			deleteConfig.skipConfirm,
+		)
+	case "group":
+		if len(args) != 2 {
+			return fmt.Errorf("Must provide a group name")
+		}
+		group := args[1]
+
+		adminClient, clusterName, err := deleteConfig.shared.newAdminClient(
+			ctx,
+			sess,
+			deleteConfig.dryRun,
+			config.ListenerPurposeAdmin,
+		)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		err = cliRunner.DeleteGroup(
+			ctx,
+			group,
+			deleteConfig.topics,
+			deleteConfig.force,
+			deleteConfig.dryRun,
+			deleteConfig.skipConfirm,
+		)
+
+		if !deleteConfig.dryRun {
+			entry := audit.Entry{
+				Operation: audit.OperationDeleteGroup,
+				Cluster:   clusterName,
+				Details:   fmt.Sprintf("group %s", group),
+			}
+			if len(deleteConfig.topics) > 0 {
+				entry.Operation = audit.OperationDeleteGroupOffsets
+				if len(deleteConfig.topics) == 1 {
+					entry.Topic = deleteConfig.topics[0]
+				} else {
+					entry.Details = fmt.Sprintf(
+						"group %s, topics %s",
+						group,
+						strings.Join(deleteConfig.topics, ","),
+					)
+				}
+			}
+			recordAuditEntry(entry, err)
+		}
+
+		return err
+	case "topic":
+		if len(args) != 2 {
+			return fmt.Errorf("Must provide a topic name")
+		}
+		topic := args[1]
+
+		if err := checkTopicDeletable(topic); err != nil {
+			return err
+		}
+
+		adminClient, err := deleteConfig.shared.getAdminClient(ctx, sess, false)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		return cliRunner.DeleteTopic(ctx, topic, deleteConfig.consumerWindow)
+	default:
+		return fmt.Errorf("Unrecognized resource type: %s", resource)
+	}
+}
+
+// checkTopicDeletable verifies that the argument topic has a config in the cluster config's
+// directory tree that marks it as deletable. If force is set, then a warning is logged instead.
+// Protected topics can't be deleted, even with force, unless allowProtected is set.
+func checkTopicDeletable(topic string) error {
+	values, err := deleteConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		deleteConfig.shared.clusterConfig,
+		deleteConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+	tree, err := config.LoadConfigTree(clusterConfig.RootDir)
+	if err != nil {
+		return err
+	}
+
+	var reason string
+
+	topicConfig, ok := tree.Topics[fmt.Sprintf("%s/%s", clusterConfig.Meta.Name, topic)]
+	if !ok {
+		// Topics without configs can still be protected by the cluster's patterns
+		topicConfig = config.TopicConfig{Meta: config.TopicMeta{Name: topic}}
+	}
+	if protectedReason := topicConfig.ProtectedReason(clusterConfig); protectedReason != "" {
+		if !deleteConfig.allowProtected {
+			return fmt.Errorf(
+				"Topic %s is protected (%s); use --allow-protected to delete it anyway",
+				topic,
+				protectedReason,
+			)
+		}
+		log.Warnf(
+			"Topic %s is protected (%s); continuing because --allow-protected is set",
+			topic,
+			protectedReason,
+		)
+	}
+
+	if !ok {
+		reason = fmt.Sprintf(
+			"Topic %s does not have a config in %s",
+			topic,
+			clusterConfig.RootDir,
+		)
+	} else if !topicConfig.Deletable(time.Now()) {
+		reason = fmt.Sprintf(
+			"Topic %s is not marked as deletable; its config must be deprecated and past its removeAfter date, if set",
+			topic,
+		)
+	} else {
+		log.Infof(
+			"Topic %s is marked as deletable (reason: %s)",
+			topic,
+			topicConfig.Meta.Deprecated.Reason,
+		)
+		return nil
+	}
+
+	if !deleteConfig.force {
+		return fmt.Errorf("%s; use --force to delete it anyway", reason)
+	}
+	log.Warnf("%s; continuing because --force is set", reason)
+	return nil
+}
@@ -0,0 +1,96 @@
+package subcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var deprecationsCmd = &cobra.Command{
+	Use:   "deprecations [topic configs]",
+	Short: "list the deprecated topics in one or more topic configs",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  deprecationsRun,
+}
+
+type deprecationsCmdConfig struct {
+	expiredOnly bool
+	pathPrefix  string
+}
+
+var deprecationsConfig deprecationsCmdConfig
+
+func init() {
+	deprecationsCmd.Flags().BoolVar(
+		&deprecationsConfig.expiredOnly,
+		"expired-only",
+		false,
+		"Only show topics that are past their removal dates",
+	)
+	deprecationsCmd.Flags().StringVar(
+		&deprecationsConfig.pathPrefix,
+		"path-prefix",
+		os.Getenv("TOPICCTL_APPLY_PATH_PREFIX"),
+		"Prefix for topic config paths",
+	)
+
+	RootCmd.AddCommand(deprecationsCmd)
+}
+
+func deprecationsRun(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	matchCount := 0
+	deprecatedConfigs := []config.TopicConfig{}
+
+	for _, arg := range args {
+		if deprecationsConfig.pathPrefix != "" && !filepath.IsAbs(arg) {
+			arg = filepath.Join(deprecationsConfig.pathPrefix, arg)
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+
+			topicConfigs, err := config.LoadTopicsFile(match)
+			if err != nil {
+				return err
+			}
+
+			for _, topicConfig := range topicConfigs {
+				deprecation := topicConfig.Meta.Deprecated
+				if deprecation == nil {
+					continue
+				}
+				if deprecationsConfig.expiredOnly && !deprecation.PastRemoveAfter(now) {
+					continue
+				}
+				deprecatedConfigs = append(deprecatedConfigs, topicConfig)
+			}
+		}
+	}
+
+	if matchCount == 0 {
+		return fmt.Errorf("No topic configs match the provided args (%+v)", args)
+	}
+
+	if len(deprecatedConfigs) == 0 {
+		log.Infof("No deprecated topics found in %d topic config(s)", matchCount)
+		return nil
+	}
+
+	log.Infof(
+		"Found %d deprecated topic(s):\n%s",
+		len(deprecatedConfigs),
+		config.FormatDeprecations(deprecatedConfigs, now),
+	)
+	return nil
+}
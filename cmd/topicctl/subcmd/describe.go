@@ -0,0 +1,201 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/check"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe [resource type] [name]",
+	Short: "describe a single instance of a particular type in detail",
+	Long: strings.Join(
+		[]string{
+			"Describe a single instance of a particular type in detail.",
+			"Supported types currently include: group, topic.",
+			"",
+			"See the tool README for a detailed description of each one.",
+		},
+		"\n",
+	),
+	Args:    cobra.ExactArgs(2),
+	PreRunE: describePreRun,
+	RunE:    describeRun,
+}
+
+type describeCmdConfig struct {
+	checkLeaders bool
+	sampleWindow time.Duration
+	topicConfig  string
+
+	shared sharedOptions
+}
+
+var describeConfig describeCmdConfig
+
+func init() {
+	describeCmd.Flags().BoolVar(
+		&describeConfig.checkLeaders,
+		"check-leaders",
+		false,
+		"Also check leaders when checking the topic against its config",
+	)
+	describeCmd.Flags().DurationVar(
+		&describeConfig.sampleWindow,
+		"sample-window",
+		10*time.Second,
+		"Time between the two samples of a group used to detect rebalances; set to 0 to skip",
+	)
+	describeCmd.Flags().StringVar(
+		&describeConfig.topicConfig,
+		"topic-config",
+		"",
+		"Path to the config to check the topic against; defaults to the topic's config in the cluster-config directory, if any",
+	)
+
+	addSharedFlags(describeCmd, &describeConfig.shared)
+	describeCmd.ValidArgsFunction = describeComplete
+	RootCmd.AddCommand(describeCmd)
+}
+
+func describeComplete(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return completeStatic([]string{"group", "topic"}, toComplete)
+	case len(args) == 1 && args[0] == "group":
+		return completeFromCluster(&describeConfig.shared, toComplete, completionKindGroups)
+	case len(args) == 1 && args[0] == "topic":
+		return completeFromCluster(&describeConfig.shared, toComplete, completionKindTopics)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func describePreRun(cmd *cobra.Command, args []string) error {
+	return describeConfig.shared.validate()
+}
+
+func describeRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	resource := args[0]
+
+	switch resource {
+	case "group":
+		groupID := args[1]
+
+		adminClient, err := describeConfig.shared.getAdminClient(ctx, sess, true)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		return cliRunner.DescribeGroup(ctx, groupID, describeConfig.sampleWindow)
+	case "topic":
+		topicName := args[1]
+
+		adminClient, err := describeConfig.shared.getAdminClient(ctx, sess, true)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		checkConfig, err := describeCheckConfig(topicName, adminClient)
+		if err != nil {
+			return err
+		}
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		return cliRunner.DescribeTopic(ctx, topicName, checkConfig)
+	default:
+		return fmt.Errorf("Unrecognized resource type: %s", resource)
+	}
+}
+
+// describeCheckConfig returns the config for checking the argument topic, or nil if no config
+// for the topic can be found.
+func describeCheckConfig(
+	topicName string,
+	adminClient admin.Client,
+) (*check.CheckConfig, error) {
+	clusterConfigPath := describeConfig.shared.clusterConfig
+	if clusterConfigPath == "" {
+		if describeConfig.topicConfig == "" {
+			return nil, nil
+		}
+
+		var err error
+		clusterConfigPath, err = filepath.Abs(
+			config.ClusterConfigPathForDir(
+				filepath.Join(filepath.Dir(describeConfig.topicConfig), ".."),
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := describeConfig.shared.templateValues()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		describeConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var topicConfigs []config.TopicConfig
+	if describeConfig.topicConfig != "" {
+		topicConfigs, err = config.LoadTopicsFileWithValues(describeConfig.topicConfig, values)
+	} else {
+		topicConfigs, err = managedTopicConfigs(clusterConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, topicConfig := range topicConfigs {
+		if topicConfig.Meta.Name != topicName {
+			continue
+		}
+		topicConfig.SetDefaults()
+
+		return &check.CheckConfig{
+			AdminClient:   adminClient,
+			CheckLeaders:  describeConfig.checkLeaders,
+			ClusterConfig: clusterConfig,
+			NumRacks:      -1,
+			TopicConfig:   topicConfig,
+		}, nil
+	}
+
+	if describeConfig.topicConfig != "" {
+		return nil, fmt.Errorf(
+			"Topic %s not found in config %s",
+			topicName,
+			describeConfig.topicConfig,
+		)
+	}
+	return nil, nil
+}
@@ -0,0 +1,194 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [topic configs]",
+	Short: "show the differences between topic configs and the topics in the cluster",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  diffRun,
+}
+
+type diffCmdConfig struct {
+	exitCode   bool
+	pathPrefix string
+
+	shared sharedOptions
+}
+
+var diffConfig diffCmdConfig
+
+func init() {
+	diffCmd.Flags().BoolVar(
+		&diffConfig.exitCode,
+		"exit-code",
+		false,
+		"Exit with a non-zero status if there are any differences",
+	)
+	diffCmd.Flags().StringVar(
+		&diffConfig.pathPrefix,
+		"path-prefix",
+		os.Getenv("TOPICCTL_APPLY_PATH_PREFIX"),
+		"Prefix for topic config paths",
+	)
+
+	addSharedConfigOnlyFlags(diffCmd, &diffConfig.shared)
+	RootCmd.AddCommand(diffCmd)
+}
+
+func diffRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Keep a cache of the admin clients with the cluster config path as the key
+	adminClients := map[string]admin.Client{}
+
+	defer func() {
+		for _, adminClient := range adminClients {
+			adminClient.Close()
+		}
+	}()
+
+	matchCount := 0
+	diffCount := 0
+
+	for _, arg := range args {
+		if diffConfig.pathPrefix != "" && !filepath.IsAbs(arg) {
+			arg = filepath.Join(diffConfig.pathPrefix, arg)
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+
+			topicDiffs, err := diffTopicFile(ctx, match, adminClients)
+			if err != nil {
+				return err
+			}
+			diffCount += topicDiffs
+		}
+	}
+
+	if matchCount == 0 {
+		return fmt.Errorf("No topic configs match the provided args (%+v)", args)
+	}
+
+	if diffCount > 0 && diffConfig.exitCode {
+		return fmt.Errorf("Found differences in %d topic(s)", diffCount)
+	}
+	return nil
+}
+
+// diffTopicFile prints out the differences for each topic in the argument topic config file
+// and returns the number of topics that have differences.
+func diffTopicFile(
+	ctx context.Context,
+	topicConfigPath string,
+	adminClients map[string]admin.Client,
+) (int, error) {
+	clusterConfigPath, err := clusterConfigForTopicDiff(topicConfigPath)
+	if err != nil {
+		return 0, err
+	}
+
+	values, err := diffConfig.shared.templateValues()
+	if err != nil {
+		return 0, err
+	}
+
+	topicConfigs, err := config.LoadTopicsFileWithValues(topicConfigPath, values)
+	if err != nil {
+		return 0, err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		diffConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := clusterConfig.CheckTopicFileName(topicConfigPath, topicConfigs); err != nil {
+		return 0, err
+	}
+
+	adminClient, ok := adminClients[clusterConfigPath]
+	if !ok {
+		adminClient, err = clusterConfig.NewAdminClient(
+			ctx,
+			nil,
+			true,
+			diffConfig.shared.saslUsername,
+			diffConfig.shared.saslPassword,
+		)
+		if err != nil {
+			return 0, err
+		}
+		adminClients[clusterConfigPath] = adminClient
+	}
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
+	diffCount := 0
+
+	for _, topicConfig := range topicConfigs {
+		if err := topicConfig.ResolveSettingsProfile(clusterConfig); err != nil {
+			return 0, err
+		}
+		topicConfig.SetDefaults()
+		log.Debugf(
+			"Processing topic %s in config %s with cluster config %s",
+			topicConfig.Meta.Name,
+			topicConfigPath,
+			clusterConfigPath,
+		)
+
+		hasDiffs, err := cliRunner.DiffTopic(
+			ctx,
+			apply.TopicApplierConfig{
+				ClusterConfig: clusterConfig,
+				DryRun:        true,
+				TopicConfig:   topicConfig,
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+		if hasDiffs {
+			diffCount++
+		}
+	}
+
+	return diffCount, nil
+}
+
+func clusterConfigForTopicDiff(topicConfigPath string) (string, error) {
+	if diffConfig.shared.clusterConfig != "" {
+		return diffConfig.shared.clusterConfig, nil
+	}
+
+	return filepath.Abs(
+		config.ClusterConfigPathForDir(
+			filepath.Join(
+				filepath.Dir(topicConfigPath),
+				"..",
+			),
+		),
+	)
+}
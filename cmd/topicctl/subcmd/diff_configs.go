@@ -0,0 +1,69 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var diffConfigsCmd = &cobra.Command{
+	Use:   "diff-configs [old config dir] [new config dir]",
+	Short: "compare the topic and cluster configs in two directories",
+	Args:  cobra.ExactArgs(2),
+	RunE:  diffConfigsRun,
+}
+
+type diffConfigsCmdConfig struct {
+	exitCode bool
+}
+
+var diffConfigsConfig diffConfigsCmdConfig
+
+func init() {
+	diffConfigsCmd.Flags().BoolVar(
+		&diffConfigsConfig.exitCode,
+		"exit-code",
+		false,
+		"Exit with a non-zero status if there are any differences",
+	)
+
+	RootCmd.AddCommand(diffConfigsCmd)
+}
+
+func diffConfigsRun(cmd *cobra.Command, args []string) error {
+	oldTree, err := config.LoadConfigTree(args[0])
+	if err != nil {
+		return err
+	}
+	newTree, err := config.LoadConfigTree(args[1])
+	if err != nil {
+		return err
+	}
+
+	diffs, err := config.DiffConfigTrees(oldTree, newTree)
+	if err != nil {
+		return err
+	}
+
+	if len(diffs) == 0 {
+		log.Infof(
+			"No differences found (%d cluster config(s), %d topic config(s))",
+			len(newTree.Clusters),
+			len(newTree.Topics),
+		)
+		return nil
+	}
+
+	log.Infof(
+		"Found differences in %d config(s):\n%s",
+		len(diffs),
+		config.FormatConfigDiffs(diffs),
+	)
+
+	if diffConfigsConfig.exitCode {
+		return fmt.Errorf("Found differences in %d config(s)", len(diffs))
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/progress"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var drainCmd = &cobra.Command{
+	Use:   "drain [resource type] [id]",
+	Short: "move all replicas off of a resource",
+	Long: strings.Join(
+		[]string{
+			"Move all replicas off of a resource, e.g. before decommissioning it.",
+			"Supported types currently include: broker.",
+			"",
+			"See the tool README for a detailed description of each one.",
+		},
+		"\n",
+	),
+	Args: cobra.ExactArgs(2),
+	RunE: drainRun,
+}
+
+type drainCmdConfig struct {
+	allowProtected             bool
+	brokerThrottleMBsOverride  int
+	dryRun                     bool
+	partitionBatchSizeOverride int
+	progress                   string
+	skipConfirm                bool
+	sleepLoopDuration          time.Duration
+
+	shared sharedOptions
+}
+
+var drainConfig drainCmdConfig
+
+func init() {
+	drainCmd.Flags().BoolVar(
+		&drainConfig.allowProtected,
+		"allow-protected",
+		false,
+		"Allow moving the replicas of protected topics off of the broker",
+	)
+	drainCmd.Flags().IntVar(
+		&drainConfig.brokerThrottleMBsOverride,
+		"broker-throttle-mb",
+		0,
+		"Broker throttle override (MB/sec)",
+	)
+	drainCmd.Flags().BoolVar(
+		&drainConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	drainCmd.Flags().IntVar(
+		&drainConfig.partitionBatchSizeOverride,
+		"partition-batch-size",
+		0,
+		"Partition batch size override",
+	)
+	drainCmd.Flags().StringVar(
+		&drainConfig.progress,
+		"progress",
+		os.Getenv("TOPICCTL_PROGRESS"),
+		"Stream JSON progress events to this unix socket path, or to stdout if set to 'stdout'",
+	)
+	drainCmd.Flags().BoolVar(
+		&drainConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts during drain process",
+	)
+	drainCmd.Flags().DurationVar(
+		&drainConfig.sleepLoopDuration,
+		"sleep-loop-duration",
+		10*time.Second,
+		"Amount of time to wait between partition checks",
+	)
+
+	addSharedConfigOnlyFlags(drainCmd, &drainConfig.shared)
+	drainCmd.MarkFlagRequired("cluster-config")
+	drainCmd.ValidArgsFunction = drainComplete
+	RootCmd.AddCommand(drainCmd)
+}
+
+func drainComplete(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return completeStatic([]string{"broker"}, toComplete)
+	case len(args) == 1 && args[0] == "broker":
+		return completeFromCluster(&drainConfig.shared, toComplete, completionKindBrokers)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func drainRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	resource := args[0]
+	if resource != "broker" {
+		return fmt.Errorf("Unrecognized resource type: %s", resource)
+	}
+
+	brokerID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("Invalid broker ID %s: %+v", args[1], err)
+	}
+
+	values, err := drainConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		drainConfig.shared.clusterConfig,
+		drainConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	if clusterConfig.Spec.CruiseControl != nil {
+		return rebalanceWithCruiseControl(
+			ctx,
+			clusterConfig,
+			[]int{brokerID},
+			drainConfig.brokerThrottleMBsOverride,
+			drainConfig.allowProtected,
+			drainConfig.dryRun,
+			drainConfig.skipConfirm,
+		)
+	}
+
+	topicConfigs, err := managedTopicConfigs(clusterConfig)
+	if err != nil {
+		return err
+	}
+
+	adminClient, err := clusterConfig.NewAdminClient(
+		ctx,
+		nil,
+		drainConfig.dryRun,
+		drainConfig.shared.saslUsername,
+		drainConfig.shared.saslPassword,
+	)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+	adminClient = auditedAdminClient(adminClient, clusterConfig.Meta.Name, drainConfig.dryRun)
+
+	progressReporter, err := progress.NewReporter(drainConfig.progress)
+	if err != nil {
+		return fmt.Errorf("Could not connect to progress socket: %+v", err)
+	}
+	defer progressReporter.Close()
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
+	return cliRunner.DrainBroker(
+		ctx,
+		brokerID,
+		topicConfigs,
+		apply.TopicApplierConfig{
+			AllowProtected:             drainConfig.allowProtected,
+			BrokerThrottleMBsOverride:  drainConfig.brokerThrottleMBsOverride,
+			ClusterConfig:              clusterConfig,
+			DryRun:                     drainConfig.dryRun,
+			PartitionBatchSizeOverride: drainConfig.partitionBatchSizeOverride,
+			Progress:                   progressReporter,
+			SkipConfirm:                drainConfig.skipConfirm,
+			SleepLoopDuration:          drainConfig.sleepLoopDuration,
+		},
+	)
+}
@@ -0,0 +1,119 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/cli"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var electCmd = &cobra.Command{
+	Use:   "elect [resource type]",
+	Short: "run elections of a particular type",
+	Long: strings.Join(
+		[]string{
+			"Run elections of a particular type.",
+			"Supported types currently include: leaders.",
+			"",
+			"See the tool README for a detailed description of each one.",
+		},
+		"\n",
+	),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: electPreRun,
+	RunE:    electRun,
+}
+
+type electCmdConfig struct {
+	dryRun      bool
+	skipConfirm bool
+	topic       string
+	unclean     bool
+
+	shared sharedOptions
+}
+
+var electConfig electCmdConfig
+
+func init() {
+	electCmd.Flags().BoolVar(
+		&electConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	electCmd.Flags().BoolVar(
+		&electConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts; doesn't apply to unclean elections",
+	)
+	electCmd.Flags().StringVar(
+		&electConfig.topic,
+		"topic",
+		"",
+		"Only run elections for the partitions in this topic",
+	)
+	electCmd.Flags().BoolVar(
+		&electConfig.unclean,
+		"unclean",
+		false,
+		"Run unclean elections for leaderless partitions; this can lose data",
+	)
+
+	addSharedFlags(electCmd, &electConfig.shared)
+	electCmd.ValidArgsFunction = electComplete
+	RootCmd.AddCommand(electCmd)
+}
+
+func electComplete(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeStatic([]string{"leaders"}, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func electPreRun(cmd *cobra.Command, args []string) error {
+	return electConfig.shared.validate()
+}
+
+func electRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	resource := args[0]
+
+	switch resource {
+	case "leader", "leaders":
+		electionType := admin.LeaderElectionTypePreferred
+		if electConfig.unclean {
+			electionType = admin.LeaderElectionTypeUnclean
+		}
+
+		adminClient, err := electConfig.shared.getAdminClient(ctx, sess, electConfig.dryRun)
+		if err != nil {
+			return err
+		}
+		defer adminClient.Close()
+
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		return cliRunner.ElectLeaders(
+			ctx,
+			electConfig.topic,
+			electionType,
+			electConfig.dryRun,
+			electConfig.skipConfirm,
+		)
+	default:
+		return fmt.Errorf("Unrecognized resource type: %s", resource)
+	}
+}
@@ -0,0 +1,100 @@
+package subcmd
+
+import (
+	"context"
+
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export all topics (and optionally ACLs and quotas) in a cluster to config files",
+	Args:  cobra.NoArgs,
+	RunE:  exportRun,
+}
+
+type exportCmdConfig struct {
+	includeACLs   bool
+	includeQuotas bool
+	outputDir     string
+	overwrite     bool
+
+	shared sharedOptions
+}
+
+var exportConfig exportCmdConfig
+
+func init() {
+	exportCmd.Flags().BoolVar(
+		&exportConfig.includeACLs,
+		"include-acls",
+		false,
+		"Also export the ACLs in the cluster to acls.yaml",
+	)
+	exportCmd.Flags().BoolVar(
+		&exportConfig.includeQuotas,
+		"include-quotas",
+		false,
+		"Also export the quotas in the cluster to the quotas section of the exported cluster config",
+	)
+	exportCmd.Flags().StringVar(
+		&exportConfig.outputDir,
+		"out",
+		"",
+		"Output directory",
+	)
+	exportCmd.Flags().BoolVar(
+		&exportConfig.overwrite,
+		"overwrite",
+		false,
+		"Overwrite existing configs in output directory",
+	)
+
+	addSharedConfigOnlyFlags(exportCmd, &exportConfig.shared)
+	exportCmd.MarkFlagRequired("cluster-config")
+	exportCmd.MarkFlagRequired("out")
+	RootCmd.AddCommand(exportCmd)
+}
+
+func exportRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	values, err := exportConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		exportConfig.shared.clusterConfig,
+		exportConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+	adminClient, err := clusterConfig.NewAdminClient(
+		ctx,
+		nil,
+		true,
+		exportConfig.shared.saslUsername,
+		exportConfig.shared.saslPassword,
+	)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+	return cliRunner.ExportCluster(
+		ctx,
+		clusterConfig,
+		exportConfig.outputDir,
+		exportConfig.includeACLs,
+		exportConfig.includeQuotas,
+		exportConfig.overwrite,
+	)
+}
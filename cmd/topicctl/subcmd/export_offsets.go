@@ -0,0 +1,77 @@
+package subcmd
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/cli"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var exportOffsetsCmd = &cobra.Command{
+	Use:     "export-offsets [group name]",
+	Short:   "export the committed offsets of a consumer group to a JSON file",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: exportOffsetsPreRun,
+	RunE:    exportOffsetsRun,
+}
+
+type exportOffsetsCmdConfig struct {
+	output string
+	topics []string
+
+	shared sharedOptions
+}
+
+var exportOffsetsConfig exportOffsetsCmdConfig
+
+func init() {
+	exportOffsetsCmd.Flags().StringVarP(
+		&exportOffsetsConfig.output,
+		"output",
+		"o",
+		"",
+		"Path to write the offsets to; if unset, they're written to stdout",
+	)
+	exportOffsetsCmd.Flags().StringArrayVar(
+		&exportOffsetsConfig.topics,
+		"topic",
+		[]string{},
+		"Only export the offsets in this topic; can be repeated",
+	)
+
+	addSharedFlags(exportOffsetsCmd, &exportOffsetsConfig.shared)
+	exportOffsetsCmd.RegisterFlagCompletionFunc(
+		"topic",
+		completeRepeatedArgs(&exportOffsetsConfig.shared, completionKindTopics),
+	)
+	exportOffsetsCmd.ValidArgsFunction = completePositionalArgs(
+		&exportOffsetsConfig.shared,
+		completionKindGroups,
+	)
+	RootCmd.AddCommand(exportOffsetsCmd)
+}
+
+func exportOffsetsPreRun(cmd *cobra.Command, args []string) error {
+	return exportOffsetsConfig.shared.validate()
+}
+
+func exportOffsetsRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	adminClient, err := exportOffsetsConfig.shared.getAdminClient(ctx, sess, true)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+	return cliRunner.ExportOffsets(
+		ctx,
+		args[0],
+		exportOffsetsConfig.topics,
+		exportOffsetsConfig.output,
+	)
+}
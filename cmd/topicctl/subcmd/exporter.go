@@ -0,0 +1,292 @@
+package subcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/exporter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var exporterCmd = &cobra.Command{
+	Use:     "exporter",
+	Short:   "serve topic, broker, and consumer group metrics for Prometheus",
+	Args:    cobra.NoArgs,
+	PreRunE: exporterPreRun,
+	RunE:    exporterRun,
+}
+
+type exporterCmdConfig struct {
+	clusterConfigs      []string
+	includeDrift        bool
+	includeLag          bool
+	includePartitionLag bool
+	interval            time.Duration
+	lagGroups           []string
+	listenAddr          string
+	reloadInterval      time.Duration
+
+	shared sharedOptions
+}
+
+var exporterConfig exporterCmdConfig
+
+func init() {
+	exporterCmd.Flags().StringSliceVar(
+		&exporterConfig.clusterConfigs,
+		"cluster-configs",
+		[]string{},
+		"Paths to other cluster configs to collect metrics from",
+	)
+	exporterCmd.Flags().BoolVar(
+		&exporterConfig.includeDrift,
+		"include-drift",
+		true,
+		"Compare the managed topic configs against each cluster for the config drift metrics",
+	)
+	exporterCmd.Flags().BoolVar(
+		&exporterConfig.includeLag,
+		"include-lag",
+		true,
+		"Collect consumer group lag metrics",
+	)
+	exporterCmd.Flags().BoolVar(
+		&exporterConfig.includePartitionLag,
+		"include-partition-lag",
+		true,
+		"Also collect consumer group lag metrics for each partition",
+	)
+	exporterCmd.Flags().DurationVar(
+		&exporterConfig.interval,
+		"interval",
+		time.Minute,
+		"Amount of time between metric collections",
+	)
+	exporterCmd.Flags().StringSliceVar(
+		&exporterConfig.lagGroups,
+		"lag-groups",
+		[]string{},
+		"Regexps for the consumer groups to collect lag metrics for; defaults to all groups",
+	)
+	exporterCmd.Flags().StringVar(
+		&exporterConfig.listenAddr,
+		"listen-addr",
+		":9797",
+		"Address to serve metrics on; these are available at the /metrics path",
+	)
+	exporterCmd.Flags().DurationVar(
+		&exporterConfig.reloadInterval,
+		"reload-interval",
+		30*time.Second,
+		"How often to check the cluster configs and credential files for changes and reconnect if they've changed; set to 0 to disable",
+	)
+
+	addSharedConfigOnlyFlags(exporterCmd, &exporterConfig.shared)
+	RootCmd.AddCommand(exporterCmd)
+}
+
+func exporterPreRun(cmd *cobra.Command, args []string) error {
+	if exporterConfig.shared.clusterConfig == "" && len(exporterConfig.clusterConfigs) == 0 {
+		return errors.New("Must set cluster-config or cluster-configs")
+	}
+	if exporterConfig.interval <= 0 {
+		return errors.New("Interval must be positive")
+	}
+	if exporterConfig.reloadInterval < 0 {
+		return errors.New("Reload interval must not be negative")
+	}
+	return nil
+}
+
+func exporterRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	values, err := exporterConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	lagGroups := []*regexp.Regexp{}
+	for _, lagGroup := range exporterConfig.lagGroups {
+		lagGroupRegexp, err := regexp.Compile(lagGroup)
+		if err != nil {
+			return fmt.Errorf("Invalid --lag-groups regexp '%s': %+v", lagGroup, err)
+		}
+		lagGroups = append(lagGroups, lagGroupRegexp)
+	}
+
+	targets := []exporter.Target{}
+	defer func() {
+		for _, target := range targets {
+			target.AdminClient.Close()
+		}
+	}()
+
+	configPaths := exporterConfig.clusterConfigs
+	if exporterConfig.shared.clusterConfig != "" {
+		configPaths = append([]string{exporterConfig.shared.clusterConfig}, configPaths...)
+	}
+
+	for _, path := range configPaths {
+		clusterConfig, err := config.LoadClusterFileWithValues(
+			path,
+			exporterConfig.shared.expandEnv,
+			values,
+		)
+		if err != nil {
+			return err
+		}
+		if err := clusterConfig.Validate(); err != nil {
+			return err
+		}
+
+		var topicConfigs []config.TopicConfig
+		if exporterConfig.includeDrift {
+			topicConfigs, err = managedTopicConfigs(clusterConfig)
+			if err != nil {
+				return err
+			}
+		}
+
+		adminClient, err := exporterAdminClient(ctx, path, clusterConfig)
+		if err != nil {
+			return err
+		}
+
+		targets = append(
+			targets,
+			exporter.Target{
+				ClusterConfig: clusterConfig,
+				AdminClient:   adminClient,
+				TopicConfigs:  topicConfigs,
+			},
+		)
+	}
+
+	metricsExporter := exporter.NewExporter(
+		exporter.ExporterConfig{
+			Targets:             targets,
+			Interval:            exporterConfig.interval,
+			IncludeDrift:        exporterConfig.includeDrift,
+			IncludeLag:          exporterConfig.includeLag,
+			IncludePartitionLag: exporterConfig.includePartitionLag,
+			LagGroups:           lagGroups,
+		},
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsExporter)
+	server := &http.Server{
+		Addr:    exporterConfig.listenAddr,
+		Handler: mux,
+	}
+
+	serverErrs := make(chan error, 1)
+	go func() {
+		log.Infof("Serving metrics at %s/metrics", exporterConfig.listenAddr)
+		serverErrs <- server.ListenAndServe()
+	}()
+	go metricsExporter.Run(ctx)
+
+	select {
+	case err := <-serverErrs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// exporterAdminClient creates the admin client for the argument cluster config. Unless reloading
+// is disabled, the client is rebuilt from the latest version of the cluster config whenever it
+// or its TLS and Kerberos files change, e.g. when credentials or certificates are rotated. Only the
+// connection settings are reloaded; the other settings in the config are left as-is.
+func exporterAdminClient(
+	ctx context.Context,
+	clusterConfigPath string,
+	clusterConfig config.ClusterConfig,
+) (admin.Client, error) {
+	if exporterConfig.reloadInterval == 0 {
+		return clusterConfig.NewAdminClient(
+			ctx,
+			nil,
+			true,
+			exporterConfig.shared.saslUsername,
+			exporterConfig.shared.saslPassword,
+		)
+	}
+
+	absConfigPath, err := filepath.Abs(clusterConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first client is built from the config that was already loaded
+	firstBuild := true
+
+	return admin.NewReloadingClient(
+		ctx,
+		func(ctx context.Context) (admin.Client, []string, error) {
+			if !firstBuild {
+				values, err := exporterConfig.shared.templateValues()
+				if err != nil {
+					return nil, nil, err
+				}
+				clusterConfig, err = config.LoadClusterFileWithValues(
+					clusterConfigPath,
+					exporterConfig.shared.expandEnv,
+					values,
+				)
+				if err != nil {
+					return nil, nil, err
+				}
+				if err := clusterConfig.Validate(); err != nil {
+					return nil, nil, err
+				}
+			}
+			firstBuild = false
+
+			credentialFiles, err := clusterConfig.CredentialFiles(config.ListenerPurposeAdmin)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			adminClient, err := clusterConfig.NewAdminClient(
+				ctx,
+				nil,
+				true,
+				exporterConfig.shared.saslUsername,
+				exporterConfig.shared.saslPassword,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			paths := append([]string{absConfigPath}, credentialFiles...)
+			paths = append(paths, exporterConfig.shared.valuesFiles...)
+			return adminClient, paths, nil
+		},
+		exporterConfig.reloadInterval,
+	)
+}
@@ -2,11 +2,21 @@ package subcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -17,7 +27,7 @@ var getCmd = &cobra.Command{
 	Long: strings.Join(
 		[]string{
 			"Get instances of a particular type.",
-			"Supported types currently include: balance, brokers, config, groups, lags, members, partitions, offsets, and topics.",
+			"Supported types currently include: acls, balance, broker-configs, brokers, config, groups, health, lag, lags, members, partitions, offsets, quotas, reassignments, storage, and topics.",
 			"",
 			"See the tool README for a detailed description of each one.",
 		},
@@ -29,8 +39,28 @@ var getCmd = &cobra.Command{
 }
 
 type getCmdConfig struct {
-	full       bool
-	sortValues bool
+	atTime          string
+	byTopic         bool
+	desc            bool
+	full            bool
+	match           string
+	maxLag          int64
+	operation       string
+	output          string
+	patternType     string
+	principal       string
+	rateWindow      time.Duration
+	resourceType    string
+	selector        string
+	sortBy          string
+	sortValues      bool
+	throttled       bool
+	topic           string
+	total           bool
+	underReplicated bool
+	watch           bool
+	watchInterval   time.Duration
+	wrongLeaders    bool
 
 	shared sharedOptions
 }
@@ -38,29 +68,230 @@ type getCmdConfig struct {
 var getConfig getCmdConfig
 
 func init() {
+	getCmd.Flags().StringVar(
+		&getConfig.atTime,
+		"at-time",
+		"",
+		"Also show the offsets at this time (RFC3339 time, date, or duration ago); only applies for offsets",
+	)
+	getCmd.Flags().BoolVar(
+		&getConfig.byTopic,
+		"by-topic",
+		false,
+		"Also show the disk usage of the largest topics; only applies for storage",
+	)
+	getCmd.Flags().BoolVar(
+		&getConfig.desc,
+		"desc",
+		false,
+		"Sort in descending order; only applies for partitions",
+	)
 	getCmd.Flags().BoolVar(
 		&getConfig.full,
 		"full",
 		false,
 		"Show more full information for resources",
 	)
+	getCmd.Flags().StringVar(
+		&getConfig.match,
+		"match",
+		"",
+		"Only show topics whose names match this regular expression; only applies for topics",
+	)
+	getCmd.Flags().Int64Var(
+		&getConfig.maxLag,
+		"max-lag",
+		-1,
+		"Exit with an error if the total lag is above this value; only applies for lag",
+	)
+	getCmd.Flags().StringVar(
+		&getConfig.operation,
+		"operation",
+		"",
+		"Only show ACLs for this operation, e.g. read or write; only applies for acls",
+	)
+	getCmd.Flags().StringVar(
+		&getConfig.output,
+		"output",
+		string(cli.OutputFormatTable),
+		fmt.Sprintf(
+			"Output format (choices: %s); structured formats are printed to stdout",
+			outputFormatChoices(),
+		),
+	)
+	getCmd.Flags().StringVar(
+		&getConfig.patternType,
+		"pattern-type",
+		"",
+		"Only show ACLs with this resource pattern type (literal, prefixed, or match); only applies for acls",
+	)
+	getCmd.Flags().StringVar(
+		&getConfig.principal,
+		"principal",
+		"",
+		"Only show ACLs for this principal, e.g. User:alice; only applies for acls",
+	)
+	getCmd.Flags().DurationVar(
+		&getConfig.rateWindow,
+		"rate-window",
+		0,
+		"Sample the lags again after this long to estimate rates and the catch-up time; only applies for lag",
+	)
+	getCmd.Flags().StringVar(
+		&getConfig.resourceType,
+		"resource-type",
+		"",
+		"Only show ACLs for this resource type, e.g. topic or group; only applies for acls",
+	)
+	getCmd.Flags().StringVar(
+		&getConfig.selector,
+		"selector",
+		"",
+		"Only show topics whose configs have these labels, e.g. team=payments,tier=1; requires --cluster-config and only applies for topics",
+	)
+	getCmd.Flags().StringVar(
+		&getConfig.sortBy,
+		"sort-by",
+		string(admin.PartitionSortKeyID),
+		fmt.Sprintf(
+			"Key to sort by (choices: %s); only applies for partitions",
+			partitionSortKeyChoices(),
+		),
+	)
 	getCmd.Flags().BoolVar(
 		&getConfig.sortValues,
 		"sort-values",
 		false,
 		"Sort by value instead of name; only applies for lags at the moment",
 	)
+	getCmd.Flags().BoolVar(
+		&getConfig.throttled,
+		"throttled",
+		false,
+		"Only show topics that have throttles; only applies for topics",
+	)
+	getCmd.Flags().StringVar(
+		&getConfig.topic,
+		"topic",
+		"",
+		"Only show groups that are consuming this topic; only applies for groups",
+	)
+	getCmd.Flags().BoolVar(
+		&getConfig.total,
+		"total",
+		false,
+		"Also show per-topic and overall lag totals; only applies for lag",
+	)
+	getCmd.Flags().BoolVar(
+		&getConfig.underReplicated,
+		"under-replicated",
+		false,
+		"Only show topics with out-of-sync partitions; only applies for topics",
+	)
+	getCmd.Flags().BoolVar(
+		&getConfig.watch,
+		"watch",
+		false,
+		"Refresh the results every watch-interval, highlighting the rows that changed; only supported with table output",
+	)
+	getCmd.Flags().DurationVar(
+		&getConfig.watchInterval,
+		"watch-interval",
+		5*time.Second,
+		"Interval between refreshes when watch is set",
+	)
+	getCmd.Flags().BoolVar(
+		&getConfig.wrongLeaders,
+		"wrong-leaders",
+		false,
+		"Only show topics with partitions whose leader isn't the preferred one; only applies for topics",
+	)
 
 	addSharedFlags(getCmd, &getConfig.shared)
+	getCmd.ValidArgsFunction = getComplete
 	RootCmd.AddCommand(getCmd)
 }
 
+// getResourceTypes are the resource types that can be passed to get; these are only used for
+// shell completion.
+var getResourceTypes = []string{
+	"acls",
+	"balance",
+	"broker-configs",
+	"brokers",
+	"config",
+	"groups",
+	"health",
+	"lag",
+	"lags",
+	"members",
+	"metadata",
+	"offsets",
+	"partitions",
+	"quotas",
+	"reassignments",
+	"storage",
+	"topics",
+}
+
+func getComplete(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeStatic(getResourceTypes, toComplete)
+	}
+
+	switch {
+	case len(args) == 1 && (args[0] == "balance" || args[0] == "lags" ||
+		args[0] == "offsets" || args[0] == "partitions" || args[0] == "reassignments"):
+		return completeFromCluster(&getConfig.shared, toComplete, completionKindTopics)
+	case len(args) == 1 && (args[0] == "broker-configs" || args[0] == "storage"):
+		return completeFromCluster(&getConfig.shared, toComplete, completionKindBrokers)
+	case len(args) == 1 && args[0] == "config":
+		return completeFromCluster(
+			&getConfig.shared,
+			toComplete,
+			completionKindBrokers,
+			completionKindTopics,
+		)
+	case len(args) == 1 && (args[0] == "lag" || args[0] == "members"):
+		return completeFromCluster(&getConfig.shared, toComplete, completionKindGroups)
+	case len(args) == 2 && args[0] == "lags":
+		return completeFromCluster(&getConfig.shared, toComplete, completionKindGroups)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
 func getPreRun(cmd *cobra.Command, args []string) error {
+	if !validPartitionSortKey(admin.PartitionSortKey(getConfig.sortBy)) {
+		return fmt.Errorf(
+			"Unrecognized sort-by value '%s'; choices are %s",
+			getConfig.sortBy,
+			partitionSortKeyChoices(),
+		)
+	}
+	outputFormat, err := cli.ParseOutputFormat(getConfig.output)
+	if err != nil {
+		return err
+	}
+	if getConfig.watch {
+		if outputFormat != cli.OutputFormatTable {
+			return errors.New("Watch is only supported with table output")
+		}
+		if getConfig.watchInterval <= 0 {
+			return errors.New("Watch interval must be positive")
+		}
+	}
 	return getConfig.shared.validate()
 }
 
 func getRun(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	sess := session.Must(session.NewSession())
 
 	adminClient, err := getConfig.shared.getAdminClient(ctx, sess, true)
@@ -69,11 +300,54 @@ func getRun(cmd *cobra.Command, args []string) error {
 	}
 	defer adminClient.Close()
 
-	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+	if !getConfig.watch {
+		cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+		cliRunner.SetOutputFormat(cli.OutputFormat(getConfig.output))
+		return getResource(ctx, cliRunner, args)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	return cli.Watch(
+		ctx,
+		getConfig.watchInterval,
+		os.Stdout,
+		func(printer func(f string, a ...interface{})) error {
+			cliRunner := cli.NewCLIRunner(adminClient, printer, false)
+			return getResource(ctx, cliRunner, args)
+		},
+	)
+}
 
+// getResource runs the get command for the resource type in the argument args.
+func getResource(ctx context.Context, cliRunner *cli.CLIRunner, args []string) error {
 	resource := args[0]
 
 	switch resource {
+	case "acls":
+		var resourceName string
+
+		if len(args) == 2 {
+			resourceName = args[1]
+		} else if len(args) > 2 {
+			return fmt.Errorf("Can provide at most one positional argument with acls")
+		}
+
+		return cliRunner.GetACLs(
+			ctx,
+			admin.ACLFilter{
+				ResourceType: getConfig.resourceType,
+				ResourceName: resourceName,
+				PatternType:  getConfig.patternType,
+				Principal:    getConfig.principal,
+				Operation:    getConfig.operation,
+			},
+		)
 	case "balance":
 		var topicName string
 
@@ -83,7 +357,21 @@ func getRun(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("Can provide at most one positional argument with brokers")
 		}
 
-		return cliRunner.GetBrokerBalance(ctx, topicName)
+		return cliRunner.GetBrokerBalance(ctx, topicName, getConfig.full)
+	case "broker-configs":
+		brokerID := -1
+
+		if len(args) == 2 {
+			var err error
+			brokerID, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("Broker ID must be an integer: %+v", err)
+			}
+		} else if len(args) > 2 {
+			return fmt.Errorf("Can provide at most one positional argument with broker-configs")
+		}
+
+		return cliRunner.GetBrokerConfigs(ctx, brokerID, getConfig.full)
 	case "brokers":
 		if len(args) > 1 {
 			return fmt.Errorf("Can only provide one positional argument with brokers")
@@ -101,7 +389,25 @@ func getRun(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("Can only provide one positional argument with groups")
 		}
 
-		return cliRunner.GetGroups(ctx)
+		return cliRunner.GetGroups(ctx, getConfig.topic)
+	case "health":
+		if len(args) > 1 {
+			return fmt.Errorf("Can only provide one positional argument with health")
+		}
+
+		return cliRunner.GetHealth(ctx)
+	case "lag":
+		if len(args) != 2 {
+			return fmt.Errorf("Must provide group ID as second positional argument")
+		}
+
+		return cliRunner.GetGroupLags(
+			ctx,
+			args[1],
+			getConfig.total,
+			getConfig.maxLag,
+			getConfig.rateWindow,
+		)
 	case "lags":
 		if len(args) != 3 {
 			return fmt.Errorf("Must provide topic and groupID as additional positional arguments")
@@ -120,27 +426,163 @@ func getRun(cmd *cobra.Command, args []string) error {
 		}
 
 		return cliRunner.GetGroupMembers(ctx, args[1], getConfig.full)
+	case "metadata":
+		if len(args) > 1 {
+			return fmt.Errorf("Can only provide one positional argument with metadata")
+		}
+
+		return cliRunner.GetMetadata(ctx)
 	case "partitions":
 		if len(args) != 2 {
 			return fmt.Errorf("Must provide topic as second positional argument")
 		}
 		topicName := args[1]
 
-		return cliRunner.GetPartitions(ctx, topicName)
+		return cliRunner.GetPartitions(
+			ctx,
+			topicName,
+			admin.PartitionSortKey(getConfig.sortBy),
+			getConfig.desc,
+		)
 	case "offsets":
 		if len(args) != 2 {
 			return fmt.Errorf("Must provide topic as second positional argument")
 		}
 		topicName := args[1]
 
-		return cliRunner.GetOffsets(ctx, topicName)
+		var atTime *time.Time
+		if getConfig.atTime != "" {
+			parsedTime, err := util.ParseTime(getConfig.atTime, time.Now())
+			if err != nil {
+				return err
+			}
+			atTime = &parsedTime
+		}
+
+		return cliRunner.GetOffsets(ctx, topicName, atTime)
+	case "quotas":
+		if len(args) > 1 {
+			return fmt.Errorf("Can only provide one positional argument with quotas")
+		}
+
+		return cliRunner.GetQuotas(ctx)
+	case "reassignments":
+		var topicName string
+
+		if len(args) == 2 {
+			topicName = args[1]
+		} else if len(args) > 2 {
+			return fmt.Errorf("Can provide at most one positional argument with reassignments")
+		}
+
+		return cliRunner.GetReassignments(ctx, topicName)
+	case "storage":
+		brokerID := -1
+
+		if len(args) == 2 {
+			var err error
+			brokerID, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("Broker ID must be an integer: %+v", err)
+			}
+		} else if len(args) > 2 {
+			return fmt.Errorf("Can provide at most one positional argument with storage")
+		}
+
+		return cliRunner.GetStorage(ctx, brokerID, getConfig.byTopic, getConfig.full)
 	case "topics":
 		if len(args) > 1 {
 			return fmt.Errorf("Can only provide one positional argument with args")
 		}
 
-		return cliRunner.GetTopics(ctx, getConfig.full)
+		filter, err := getTopicFilter()
+		if err != nil {
+			return err
+		}
+
+		return cliRunner.GetTopics(ctx, getConfig.full, filter)
 	default:
 		return fmt.Errorf("Unrecognized resource type: %s", resource)
 	}
 }
+
+// getTopicFilter builds the filter for get topics from the flags. If a label selector is set,
+// the topic configs in the cluster config's directory tree are loaded to find the matching
+// topics.
+func getTopicFilter() (admin.TopicFilter, error) {
+	filter := admin.TopicFilter{
+		UnderReplicated: getConfig.underReplicated,
+		Throttled:       getConfig.throttled,
+		WrongLeaders:    getConfig.wrongLeaders,
+	}
+
+	if getConfig.match != "" {
+		nameRegexp, err := regexp.Compile(getConfig.match)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid --match regexp: %+v", err)
+		}
+		filter.NameRegexp = nameRegexp
+	}
+
+	if getConfig.selector != "" {
+		if getConfig.shared.clusterConfig == "" {
+			return filter, errors.New("Must set --cluster-config when using --selector")
+		}
+
+		selector, err := config.ParseLabelSelector(getConfig.selector)
+		if err != nil {
+			return filter, err
+		}
+
+		values, err := getConfig.shared.templateValues()
+		if err != nil {
+			return filter, err
+		}
+		clusterConfig, err := config.LoadClusterFileWithValues(
+			getConfig.shared.clusterConfig,
+			getConfig.shared.expandEnv,
+			values,
+		)
+		if err != nil {
+			return filter, err
+		}
+		topicConfigs, err := managedTopicConfigs(clusterConfig)
+		if err != nil {
+			return filter, err
+		}
+
+		filter.Names = map[string]struct{}{}
+		for _, topicConfig := range topicConfigs {
+			if topicConfig.Meta.MatchesLabels(selector) {
+				filter.Names[topicConfig.Meta.Name] = struct{}{}
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+func validPartitionSortKey(sortKey admin.PartitionSortKey) bool {
+	for _, validKey := range admin.AllPartitionSortKeys {
+		if sortKey == validKey {
+			return true
+		}
+	}
+	return false
+}
+
+func partitionSortKeyChoices() string {
+	choices := []string{}
+	for _, sortKey := range admin.AllPartitionSortKeys {
+		choices = append(choices, string(sortKey))
+	}
+	return strings.Join(choices, ", ")
+}
+
+func outputFormatChoices() string {
+	choices := []string{}
+	for _, outputFormat := range cli.AllOutputFormats {
+		choices = append(choices, string(outputFormat))
+	}
+	return strings.Join(choices, ", ")
+}
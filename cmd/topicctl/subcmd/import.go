@@ -0,0 +1,179 @@
+package subcmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [format] [input files]",
+	Short: "convert topic definitions from other tools into topic configs",
+	Long: fmt.Sprintf(
+		"Convert topic definitions from other tools into topic configs. Supported formats: %s. Use - to read from stdin.",
+		importFormatChoices(),
+	),
+	Args:    cobra.MinimumNArgs(2),
+	PreRunE: importPreRun,
+	RunE:    importRun,
+}
+
+type importCmdConfig struct {
+	defaultPartitions        int
+	defaultReplicationFactor int
+	outputDir                string
+	overwrite                bool
+
+	shared sharedOptions
+}
+
+var importConfig importCmdConfig
+
+func init() {
+	importCmd.Flags().IntVar(
+		&importConfig.defaultPartitions,
+		"partitions",
+		0,
+		"Partition count for topics that don't set one in the input",
+	)
+	importCmd.Flags().IntVar(
+		&importConfig.defaultReplicationFactor,
+		"replication-factor",
+		0,
+		"Replication factor for topics that don't set one in the input",
+	)
+	importCmd.Flags().StringVarP(
+		&importConfig.outputDir,
+		"output",
+		"o",
+		"",
+		"Output directory; configs are printed to stdout if not set",
+	)
+	importCmd.Flags().BoolVar(
+		&importConfig.overwrite,
+		"overwrite",
+		false,
+		"Overwrite existing configs in output directory",
+	)
+
+	addSharedConfigOnlyFlags(importCmd, &importConfig.shared)
+	importCmd.MarkFlagRequired("cluster-config")
+	RootCmd.AddCommand(importCmd)
+}
+
+func importPreRun(cmd *cobra.Command, args []string) error {
+	_, err := config.ParseImportFormat(args[0])
+	return err
+}
+
+func importRun(cmd *cobra.Command, args []string) error {
+	format, err := config.ParseImportFormat(args[0])
+	if err != nil {
+		return err
+	}
+
+	values, err := importConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		importConfig.shared.clusterConfig,
+		importConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	importCount := 0
+
+	for _, inputPath := range args[1:] {
+		contents, err := readImportInput(inputPath)
+		if err != nil {
+			return err
+		}
+
+		topicConfigs, err := config.ImportTopicConfigs(
+			contents,
+			format,
+			clusterConfig,
+			config.ImportOptions{
+				DefaultPartitions:        importConfig.defaultPartitions,
+				DefaultReplicationFactor: importConfig.defaultReplicationFactor,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("Error importing %s: %+v", inputPath, err)
+		}
+
+		for _, topicConfig := range topicConfigs {
+			if clusterConfig.IsUnmanagedTopic(topicConfig.Meta.Name) {
+				log.Infof(
+					"Skipping over topic %s because it matches an unmanaged topic pattern",
+					topicConfig.Meta.Name,
+				)
+				continue
+			}
+
+			if err := writeImportedTopic(topicConfig); err != nil {
+				return err
+			}
+			importCount++
+		}
+	}
+
+	log.Infof("Imported %d topic(s) from %s input", importCount, format)
+	return nil
+}
+
+func readImportInput(inputPath string) ([]byte, error) {
+	if inputPath == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(inputPath)
+}
+
+func writeImportedTopic(topicConfig config.TopicConfig) error {
+	yamlStr, err := topicConfig.ToYAML()
+	if err != nil {
+		return err
+	}
+
+	if importConfig.outputDir == "" {
+		log.Infof("Config for topic %s:\n%s", topicConfig.Meta.Name, yamlStr)
+		return nil
+	}
+
+	outputPath := filepath.Join(
+		importConfig.outputDir,
+		fmt.Sprintf("%s.yaml", topicConfig.Meta.Name),
+	)
+
+	_, err = os.Stat(outputPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if os.IsNotExist(err) || importConfig.overwrite {
+		log.Infof("Writing config to %s", outputPath)
+		return ioutil.WriteFile(outputPath, []byte(yamlStr), 0644)
+	}
+
+	log.Infof("Skipping over existing config %s", outputPath)
+	return nil
+}
+
+func importFormatChoices() string {
+	formatStrs := []string{}
+	for _, format := range config.AllImportFormats {
+		formatStrs = append(formatStrs, string(format))
+	}
+	return strings.Join(formatStrs, ", ")
+}
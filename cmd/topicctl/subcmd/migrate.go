@@ -0,0 +1,100 @@
+package subcmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var migrateConfigCmd = &cobra.Command{
+	Use:   "migrate-config [topic or cluster configs]",
+	Short: "migrate one or more configs to the latest config format version",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  migrateConfigRun,
+}
+
+type migrateConfigCmdConfig struct {
+	dryRun     bool
+	pathPrefix string
+}
+
+var migrateConfigConfig migrateConfigCmdConfig
+
+func init() {
+	migrateConfigCmd.Flags().BoolVar(
+		&migrateConfigConfig.dryRun,
+		"dry-run",
+		false,
+		"Print out the migrated configs instead of writing them",
+	)
+	migrateConfigCmd.Flags().StringVar(
+		&migrateConfigConfig.pathPrefix,
+		"path-prefix",
+		os.Getenv("TOPICCTL_APPLY_PATH_PREFIX"),
+		"Prefix for config paths",
+	)
+
+	RootCmd.AddCommand(migrateConfigCmd)
+}
+
+func migrateConfigRun(cmd *cobra.Command, args []string) error {
+	matchCount := 0
+
+	for _, arg := range args {
+		if migrateConfigConfig.pathPrefix != "" && !filepath.IsAbs(arg) {
+			arg = filepath.Join(migrateConfigConfig.pathPrefix, arg)
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+			if err := migrateConfig(match); err != nil {
+				return err
+			}
+		}
+	}
+
+	if matchCount == 0 {
+		return fmt.Errorf("No configs match the provided args (%+v)", args)
+	}
+
+	return nil
+}
+
+func migrateConfig(configPath string) error {
+	contents, changed, err := config.MigrateConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		log.Infof(
+			"Config %s is already at version %s",
+			configPath,
+			config.CurrentAPIVersion,
+		)
+		return nil
+	}
+
+	if migrateConfigConfig.dryRun {
+		log.Infof(
+			"Would migrate config %s to version %s (dry run):\n%s",
+			configPath,
+			config.CurrentAPIVersion,
+			string(contents),
+		)
+		return nil
+	}
+
+	log.Infof("Migrating config %s to version %s", configPath, config.CurrentAPIVersion)
+	return ioutil.WriteFile(configPath, contents, 0644)
+}
@@ -0,0 +1,228 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/messages"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var produceCmd = &cobra.Command{
+	Use:   "produce [topic name]",
+	Short: "produce messages to a topic",
+	Long: strings.Join(
+		[]string{
+			"Produce messages to a topic from JSON lines read from stdin or a file.",
+			"",
+			"Each line is an object with optional key, value, and headers fields, e.g.:",
+			`{"key": "user-1", "value": {"id": 1}, "headers": {"source": "topicctl"}}`,
+			"",
+			"String keys and values are written as-is; other JSON is written in compact form",
+			"before being encoded.",
+		},
+		"\n",
+	),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: producePreRun,
+	RunE:    produceRun,
+}
+
+type produceCmdConfig struct {
+	file              string
+	keyEncoder        string
+	keyProtoMessage   string
+	keySubject        string
+	partition         int
+	partitioner       string
+	schemaRegistryURL string
+	valueEncoder      string
+	valueProtoMessage string
+	valueSubject      string
+
+	shared sharedOptions
+}
+
+var produceConfig produceCmdConfig
+
+func init() {
+	produceCmd.Flags().StringVar(
+		&produceConfig.file,
+		"file",
+		"",
+		"Path to a JSON lines file of records; if blank, records are read from stdin",
+	)
+	produceCmd.Flags().StringVar(
+		&produceConfig.keyEncoder,
+		"key-encoder",
+		string(messages.EncoderTypeString),
+		fmt.Sprintf("Encoder for message keys (choices: %s)", encoderChoices()),
+	)
+	produceCmd.Flags().StringVar(
+		&produceConfig.keyProtoMessage,
+		"key-proto-message",
+		"",
+		"Fully-qualified protobuf message name for keys; defaults to the first one in the schema",
+	)
+	produceCmd.Flags().StringVar(
+		&produceConfig.keySubject,
+		"key-subject",
+		"",
+		"Schema registry subject for keys (defaults to [topic]-key)",
+	)
+	produceCmd.Flags().IntVar(
+		&produceConfig.partition,
+		"partition",
+		-1,
+		"Partition to write all messages to; if negative, the partitioner is used",
+	)
+	produceCmd.Flags().StringVar(
+		&produceConfig.partitioner,
+		"partitioner",
+		string(messages.PartitionerTypeHash),
+		fmt.Sprintf("Partitioner for message keys (choices: %s)", partitionerChoices()),
+	)
+	produceCmd.Flags().StringVar(
+		&produceConfig.schemaRegistryURL,
+		"schema-registry-url",
+		os.Getenv("TOPICCTL_SCHEMA_REGISTRY_URL"),
+		"Schema registry URL; required for the avro, protobuf, and json-schema encoders",
+	)
+	produceCmd.Flags().StringVar(
+		&produceConfig.valueEncoder,
+		"value-encoder",
+		string(messages.EncoderTypeString),
+		fmt.Sprintf("Encoder for message values (choices: %s)", encoderChoices()),
+	)
+	produceCmd.Flags().StringVar(
+		&produceConfig.valueProtoMessage,
+		"value-proto-message",
+		"",
+		"Fully-qualified protobuf message name for values; defaults to the first one in the schema",
+	)
+	produceCmd.Flags().StringVar(
+		&produceConfig.valueSubject,
+		"value-subject",
+		"",
+		"Schema registry subject for values (defaults to [topic]-value)",
+	)
+
+	addSharedFlags(produceCmd, &produceConfig.shared)
+	produceCmd.ValidArgsFunction = completePositionalArgs(
+		&produceConfig.shared,
+		completionKindTopics,
+	)
+	RootCmd.AddCommand(produceCmd)
+}
+
+func producePreRun(cmd *cobra.Command, args []string) error {
+	return produceConfig.shared.validate()
+}
+
+func produceRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	topic := args[0]
+
+	var input io.Reader
+	if produceConfig.file != "" {
+		file, err := os.Open(produceConfig.file)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	} else {
+		log.Info("Reading records from stdin")
+		input = os.Stdin
+	}
+
+	records, err := messages.ReadProduceRecords(input)
+	if err != nil {
+		return err
+	}
+
+	var registryClient *messages.SchemaRegistryClient
+	if produceConfig.schemaRegistryURL != "" {
+		registryClient, err = messages.NewSchemaRegistryClient(produceConfig.schemaRegistryURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	keySubject := produceConfig.keySubject
+	if keySubject == "" {
+		keySubject = fmt.Sprintf("%s-key", topic)
+	}
+	keyEncoder, err := messages.NewEncoder(
+		messages.EncoderType(produceConfig.keyEncoder),
+		registryClient,
+		keySubject,
+		produceConfig.keyProtoMessage,
+	)
+	if err != nil {
+		return err
+	}
+
+	valueSubject := produceConfig.valueSubject
+	if valueSubject == "" {
+		valueSubject = fmt.Sprintf("%s-value", topic)
+	}
+	valueEncoder, err := messages.NewEncoder(
+		messages.EncoderType(produceConfig.valueEncoder),
+		registryClient,
+		valueSubject,
+		produceConfig.valueProtoMessage,
+	)
+	if err != nil {
+		return err
+	}
+
+	adminClient, err := produceConfig.shared.getAdminClient(ctx, nil, true)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+	return cliRunner.Produce(
+		ctx,
+		topic,
+		records,
+		messages.PartitionerType(produceConfig.partitioner),
+		produceConfig.partition,
+		keyEncoder,
+		valueEncoder,
+	)
+}
+
+func encoderChoices() string {
+	choices := []string{}
+	for _, encoderType := range messages.AllEncoderTypes {
+		choices = append(choices, string(encoderType))
+	}
+	return strings.Join(choices, ", ")
+}
+
+func partitionerChoices() string {
+	choices := []string{}
+	for _, partitionerType := range messages.AllPartitionerTypes {
+		choices = append(choices, string(partitionerType))
+	}
+	return strings.Join(choices, ", ")
+}
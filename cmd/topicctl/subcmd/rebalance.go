@@ -0,0 +1,384 @@
+package subcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/apply/rebalancers"
+	"github.com/segmentio/topicctl/pkg/audit"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/cruisecontrol"
+	"github.com/segmentio/topicctl/pkg/metrics"
+	"github.com/segmentio/topicctl/pkg/progress"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var rebalanceCmd = &cobra.Command{
+	Use:   "rebalance",
+	Short: "rebalance all managed topics in a cluster",
+	Args:  cobra.NoArgs,
+	RunE:  rebalanceRun,
+}
+
+type rebalanceCmdConfig struct {
+	allowProtected             bool
+	brokersToRemove            []int
+	brokerThrottleMBsOverride  int
+	byThroughput               bool
+	dryRun                     bool
+	maxMoveMBs                 int
+	maxMoves                   int
+	partitionBatchSizeOverride int
+	progress                   string
+	skipConfirm                bool
+	sleepLoopDuration          time.Duration
+
+	shared sharedOptions
+}
+
+var rebalanceConfig rebalanceCmdConfig
+
+func init() {
+	rebalanceCmd.Flags().BoolVar(
+		&rebalanceConfig.allowProtected,
+		"allow-protected",
+		false,
+		"Allow rebalancing protected topics",
+	)
+	rebalanceCmd.Flags().IntSliceVar(
+		&rebalanceConfig.brokersToRemove,
+		"to-remove",
+		[]int{},
+		"Brokers to remove",
+	)
+	rebalanceCmd.Flags().IntVar(
+		&rebalanceConfig.brokerThrottleMBsOverride,
+		"broker-throttle-mb",
+		0,
+		"Broker throttle override (MB/sec)",
+	)
+	rebalanceCmd.Flags().BoolVar(
+		&rebalanceConfig.byThroughput,
+		"by-throughput",
+		false,
+		"Rebalance by the partition throughputs from the cluster's broker metrics",
+	)
+	rebalanceCmd.Flags().BoolVar(
+		&rebalanceConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	rebalanceCmd.Flags().IntVar(
+		&rebalanceConfig.maxMoveMBs,
+		"max-move-mb",
+		0,
+		"Maximum total size of the replicas moved across all topics (MB); moves the replicas with the best balance improvement per byte first",
+	)
+	rebalanceCmd.Flags().IntVar(
+		&rebalanceConfig.maxMoves,
+		"max-moves",
+		0,
+		"Maximum number of replicas moved across all topics; moves the replicas with the best balance improvement per byte first",
+	)
+	rebalanceCmd.Flags().IntVar(
+		&rebalanceConfig.partitionBatchSizeOverride,
+		"partition-batch-size",
+		0,
+		"Partition batch size override",
+	)
+	rebalanceCmd.Flags().StringVar(
+		&rebalanceConfig.progress,
+		"progress",
+		os.Getenv("TOPICCTL_PROGRESS"),
+		"Stream JSON progress events to this unix socket path, or to stdout if set to 'stdout'",
+	)
+	rebalanceCmd.Flags().BoolVar(
+		&rebalanceConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts during rebalance process",
+	)
+	rebalanceCmd.Flags().DurationVar(
+		&rebalanceConfig.sleepLoopDuration,
+		"sleep-loop-duration",
+		10*time.Second,
+		"Amount of time to wait between partition checks",
+	)
+
+	addSharedConfigOnlyFlags(rebalanceCmd, &rebalanceConfig.shared)
+	rebalanceCmd.MarkFlagRequired("cluster-config")
+	RootCmd.AddCommand(rebalanceCmd)
+}
+
+func rebalanceRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	values, err := rebalanceConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		rebalanceConfig.shared.clusterConfig,
+		rebalanceConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	if clusterConfig.Spec.CruiseControl != nil {
+		if rebalanceConfig.byThroughput {
+			return errors.New(
+				"Cannot set by-throughput for clusters whose rebalances are delegated to Cruise Control",
+			)
+		}
+		if rebalanceConfig.maxMoves > 0 || rebalanceConfig.maxMoveMBs > 0 {
+			return errors.New(
+				"Cannot set max-moves or max-move-mb for clusters whose rebalances are delegated to Cruise Control",
+			)
+		}
+		return rebalanceWithCruiseControl(
+			ctx,
+			clusterConfig,
+			rebalanceConfig.brokersToRemove,
+			rebalanceConfig.brokerThrottleMBsOverride,
+			rebalanceConfig.allowProtected,
+			rebalanceConfig.dryRun,
+			rebalanceConfig.skipConfirm,
+		)
+	}
+
+	topicConfigs, err := managedTopicConfigs(clusterConfig)
+	if err != nil {
+		return err
+	}
+	log.Infof(
+		"Found %d managed topic(s) for cluster %s in %s",
+		len(topicConfigs),
+		clusterConfig.Meta.Name,
+		clusterConfig.RootDir,
+	)
+
+	adminClient, err := clusterConfig.NewAdminClient(
+		ctx,
+		nil,
+		rebalanceConfig.dryRun,
+		rebalanceConfig.shared.saslUsername,
+		rebalanceConfig.shared.saslPassword,
+	)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+	adminClient = auditedAdminClient(adminClient, clusterConfig.Meta.Name, rebalanceConfig.dryRun)
+
+	progressReporter, err := progress.NewReporter(rebalanceConfig.progress)
+	if err != nil {
+		return fmt.Errorf("Could not connect to progress socket: %+v", err)
+	}
+	defer progressReporter.Close()
+
+	var throughputs metrics.PartitionThroughputs
+	if rebalanceConfig.byThroughput {
+		throughputs, err = partitionThroughputs(ctx, clusterConfig, adminClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
+	return cliRunner.RebalanceTopics(
+		ctx,
+		topicConfigs,
+		apply.TopicApplierConfig{
+			AllowProtected:             rebalanceConfig.allowProtected,
+			BrokerThrottleMBsOverride:  rebalanceConfig.brokerThrottleMBsOverride,
+			BrokersToRemove:            rebalanceConfig.brokersToRemove,
+			ClusterConfig:              clusterConfig,
+			DryRun:                     rebalanceConfig.dryRun,
+			MoveBudget:                 newMoveBudget(rebalanceConfig.maxMoves, rebalanceConfig.maxMoveMBs),
+			PartitionBatchSizeOverride: rebalanceConfig.partitionBatchSizeOverride,
+			PartitionThroughputs:       throughputs,
+			Progress:                   progressReporter,
+			Rebalance:                  true,
+			SkipConfirm:                rebalanceConfig.skipConfirm,
+			SleepLoopDuration:          rebalanceConfig.sleepLoopDuration,
+		},
+	)
+}
+
+// newMoveBudget returns the move budget for the argument max-moves and max-move-mb flag values,
+// or nil if neither is set. The same budget is shared by all of the topics in a run.
+func newMoveBudget(maxMoves int, maxMoveMBs int) *rebalancers.MoveBudget {
+	if maxMoves <= 0 && maxMoveMBs <= 0 {
+		return nil
+	}
+	return &rebalancers.MoveBudget{
+		MaxMoves: maxMoves,
+		MaxBytes: int64(maxMoveMBs) * 1000000,
+	}
+}
+
+// rebalanceWithCruiseControl delegates a rebalance of the argument cluster, or the removal of
+// the argument brokers from it, to the Cruise Control instance in its cluster config. Unless
+// allowProtected is set, the protected topics are excluded so that their replicas aren't moved.
+func rebalanceWithCruiseControl(
+	ctx context.Context,
+	clusterConfig config.ClusterConfig,
+	brokersToRemove []int,
+	throttleMBsOverride int,
+	allowProtected bool,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	topicConfigs, err := managedTopicConfigs(clusterConfig)
+	if err != nil {
+		return err
+	}
+
+	request := clusterConfig.CruiseControlRequest(dryRun, throttleMBsOverride)
+	request.ExcludedTopics = clusterConfig.CruiseControlExcludedTopics(topicConfigs, allowProtected)
+	if request.ExcludedTopics != "" {
+		log.Infof("Excluding topics matching %s from Cruise Control", request.ExcludedTopics)
+	}
+
+	ccClient, err := clusterConfig.NewCruiseControlClient(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infof(
+		"Delegating to Cruise Control for cluster %s at %s",
+		clusterConfig.Meta.Name,
+		clusterConfig.Spec.CruiseControl.URL,
+	)
+
+	cliRunner := cli.NewCLIRunner(nil, log.Infof, false)
+	taskID, err := cliRunner.RebalanceWithCruiseControl(
+		ctx,
+		ccClient,
+		request,
+		brokersToRemove,
+		skipConfirm,
+	)
+	if taskID != "" {
+		recordAuditEntry(cruiseControlAuditEntry(clusterConfig, request, brokersToRemove, taskID), err)
+	}
+
+	return err
+}
+
+// cruiseControlAuditEntry returns the audit log entry for the argument request, which was
+// submitted to Cruise Control and is being executed in the argument task.
+func cruiseControlAuditEntry(
+	clusterConfig config.ClusterConfig,
+	request cruisecontrol.OptimizationRequest,
+	brokersToRemove []int,
+	taskID string,
+) audit.Entry {
+	entry := audit.Entry{
+		Operation: audit.OperationCruiseControlRebalance,
+		Cluster:   clusterConfig.Meta.Name,
+		Details:   fmt.Sprintf("task %s", taskID),
+		After: map[string]string{
+			"goals":          strings.Join(request.Goals, ","),
+			"excludedTopics": request.ExcludedTopics,
+		},
+	}
+
+	if len(brokersToRemove) > 0 {
+		brokerIDs := []string{}
+		for _, brokerID := range brokersToRemove {
+			brokerIDs = append(brokerIDs, strconv.Itoa(brokerID))
+		}
+
+		entry.Operation = audit.OperationCruiseControlRemoveBrokers
+		entry.After["brokersToRemove"] = strings.Join(brokerIDs, ",")
+	}
+
+	return entry
+}
+
+// partitionThroughputs gets the throughputs of the partitions in the cluster from the metrics
+// endpoints of its brokers, as configured in the brokerMetrics section of the cluster config.
+func partitionThroughputs(
+	ctx context.Context,
+	clusterConfig config.ClusterConfig,
+	adminClient admin.Client,
+) (metrics.PartitionThroughputs, error) {
+	source, err := clusterConfig.NewThroughputSource()
+	if err != nil {
+		return nil, err
+	}
+
+	brokers, err := adminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Getting partition throughputs from the metrics of %d broker(s)", len(brokers))
+	throughputs, err := source.PartitionThroughputs(ctx, brokers)
+	if err != nil {
+		return nil, err
+	}
+	if len(throughputs) == 0 {
+		return nil, fmt.Errorf(
+			"No samples of metric %s found on the broker metrics endpoints",
+			clusterConfig.Spec.BrokerMetrics.Metric,
+		)
+	}
+
+	return throughputs, nil
+}
+
+// managedTopicConfigs returns the configs of all topics managed by the argument cluster, i.e.
+// the ones in the subdirectories of the cluster config directory, sorted by topic name.
+func managedTopicConfigs(clusterConfig config.ClusterConfig) ([]config.TopicConfig, error) {
+	tree, err := config.LoadConfigTree(clusterConfig.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	topicConfigs := []config.TopicConfig{}
+
+	for _, topicConfig := range tree.Topics {
+		if topicConfig.Meta.Cluster != clusterConfig.Meta.Name {
+			continue
+		}
+		if clusterConfig.IsUnmanagedTopic(topicConfig.Meta.Name) {
+			log.Infof(
+				"Skipping topic %s because it matches an unmanaged topic pattern",
+				topicConfig.Meta.Name,
+			)
+			continue
+		}
+		topicConfigs = append(topicConfigs, topicConfig)
+	}
+
+	sort.Slice(topicConfigs, func(a, b int) bool {
+		return topicConfigs[a].Meta.Name < topicConfigs[b].Meta.Name
+	})
+
+	return topicConfigs, nil
+}
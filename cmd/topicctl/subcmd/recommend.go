@@ -0,0 +1,284 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/messages"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var recommendPartitionsCmd = &cobra.Command{
+	Use:   "recommend-partitions [topic configs]",
+	Short: "recommend partition counts based on measured topic throughput",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  recommendPartitionsRun,
+}
+
+type recommendPartitionsCmdConfig struct {
+	allowProtected bool
+	apply          bool
+	pathPrefix     string
+	sampleDuration time.Duration
+	skipConfirm    bool
+
+	shared sharedOptions
+}
+
+var recommendPartitionsConfig recommendPartitionsCmdConfig
+
+func init() {
+	recommendPartitionsCmd.Flags().BoolVar(
+		&recommendPartitionsConfig.apply,
+		"apply",
+		false,
+		"Apply the recommended partition increases to the cluster",
+	)
+	recommendPartitionsCmd.Flags().BoolVar(
+		&recommendPartitionsConfig.allowProtected,
+		"allow-protected",
+		false,
+		"Allow partition increases in protected topics; only applies with --apply",
+	)
+	recommendPartitionsCmd.Flags().StringVar(
+		&recommendPartitionsConfig.pathPrefix,
+		"path-prefix",
+		os.Getenv("TOPICCTL_APPLY_PATH_PREFIX"),
+		"Prefix for topic config paths",
+	)
+	recommendPartitionsCmd.Flags().DurationVar(
+		&recommendPartitionsConfig.sampleDuration,
+		"sample-duration",
+		30*time.Second,
+		"Amount of time to measure topic throughput over",
+	)
+	recommendPartitionsCmd.Flags().BoolVar(
+		&recommendPartitionsConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts when applying",
+	)
+
+	addSharedConfigOnlyFlags(recommendPartitionsCmd, &recommendPartitionsConfig.shared)
+	RootCmd.AddCommand(recommendPartitionsCmd)
+}
+
+func recommendPartitionsRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	// Keep a cache of the admin clients with the cluster config path as the key
+	adminClients := map[string]admin.Client{}
+
+	defer func() {
+		for _, adminClient := range adminClients {
+			adminClient.Close()
+		}
+	}()
+
+	matchCount := 0
+
+	for _, arg := range args {
+		if recommendPartitionsConfig.pathPrefix != "" && !filepath.IsAbs(arg) {
+			arg = filepath.Join(recommendPartitionsConfig.pathPrefix, arg)
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			matchCount++
+			if err := recommendPartitions(ctx, match, adminClients); err != nil {
+				return err
+			}
+		}
+	}
+
+	if matchCount == 0 {
+		return fmt.Errorf("No topic configs match the provided args (%+v)", args)
+	}
+
+	return nil
+}
+
+func recommendPartitions(
+	ctx context.Context,
+	topicConfigPath string,
+	adminClients map[string]admin.Client,
+) error {
+	clusterConfigPath, err := clusterConfigForTopicRecommend(topicConfigPath)
+	if err != nil {
+		return err
+	}
+
+	values, err := recommendPartitionsConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	topicConfigs, err := config.LoadTopicsFileWithValues(topicConfigPath, values)
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		clusterConfigPath,
+		recommendPartitionsConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	adminClient, ok := adminClients[clusterConfigPath]
+	if !ok {
+		adminClient, err = clusterConfig.NewAdminClient(
+			ctx,
+			nil,
+			!recommendPartitionsConfig.apply,
+			recommendPartitionsConfig.shared.saslUsername,
+			recommendPartitionsConfig.shared.saslPassword,
+		)
+		if err != nil {
+			return err
+		}
+		adminClient = auditedAdminClient(
+			adminClient,
+			clusterConfig.Meta.Name,
+			!recommendPartitionsConfig.apply,
+		)
+		adminClients[clusterConfigPath] = adminClient
+	}
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
+
+	for _, topicConfig := range topicConfigs {
+		autoscaling := topicConfig.Spec.AutoscalingConfig
+		if autoscaling == nil {
+			log.Infof(
+				"Topic %s in config %s has no autoscaling settings; skipping",
+				topicConfig.Meta.Name,
+				topicConfigPath,
+			)
+			continue
+		}
+
+		topicInfo, err := adminClient.GetTopic(ctx, topicConfig.Meta.Name, false)
+		if err != nil {
+			return err
+		}
+
+		log.Infof(
+			"Measuring throughput of topic %s for %s",
+			topicConfig.Meta.Name,
+			recommendPartitionsConfig.sampleDuration,
+		)
+		throughput, err := messages.GetTopicThroughput(
+			ctx,
+			adminClient.GetConnector(),
+			topicConfig.Meta.Name,
+			topicInfo.PartitionIDs(),
+			recommendPartitionsConfig.sampleDuration,
+		)
+		if err != nil {
+			return err
+		}
+
+		currPartitions := len(topicInfo.Partitions)
+		recommended := autoscaling.RecommendedPartitions(
+			currPartitions,
+			throughput.MessagesPerSec,
+		)
+
+		log.Infof(
+			"Topic %s: %d partitions, %.2f msgs/sec total, %.2f msgs/sec in busiest partition, target %.2f msgs/sec/partition",
+			topicConfig.Meta.Name,
+			currPartitions,
+			throughput.MessagesPerSec,
+			throughput.MaxPartitionMessagesPerSec(),
+			autoscaling.TargetMessagesPerSecPerPartition,
+		)
+
+		if recommended <= currPartitions {
+			log.Infof("No partition increase needed for topic %s", topicConfig.Meta.Name)
+			continue
+		}
+		if autoscaling.CappedByMaxPartitions(throughput.MessagesPerSec) {
+			log.Warnf(
+				"Recommendation for topic %s is capped by maxPartitions (%d)",
+				topicConfig.Meta.Name,
+				autoscaling.MaxPartitions,
+			)
+		}
+
+		log.Infof(
+			"Recommend increasing partitions in topic %s from %d to %d",
+			topicConfig.Meta.Name,
+			currPartitions,
+			recommended,
+		)
+
+		if !recommendPartitionsConfig.apply {
+			continue
+		}
+
+		if err := topicConfig.ResolveSettingsProfile(clusterConfig); err != nil {
+			return err
+		}
+		topicConfig.SetDefaults()
+		topicConfig.Spec.Partitions = recommended
+
+		applierConfig := apply.TopicApplierConfig{
+			AllowProtected:    recommendPartitionsConfig.allowProtected,
+			ClusterConfig:     clusterConfig,
+			SkipConfirm:       recommendPartitionsConfig.skipConfirm,
+			SleepLoopDuration: 10 * time.Second,
+			TopicConfig:       topicConfig,
+		}
+		if err := cliRunner.IncreasePartitions(ctx, applierConfig); err != nil {
+			return err
+		}
+
+		log.Warnf(
+			"Update the partitions in %s to %d so that future applies are consistent with the cluster",
+			topicConfigPath,
+			recommended,
+		)
+	}
+
+	return nil
+}
+
+func clusterConfigForTopicRecommend(topicConfigPath string) (string, error) {
+	if recommendPartitionsConfig.shared.clusterConfig != "" {
+		return recommendPartitionsConfig.shared.clusterConfig, nil
+	}
+
+	return filepath.Abs(
+		config.ClusterConfigPathForDir(
+			filepath.Join(
+				filepath.Dir(topicConfigPath),
+				"..",
+			),
+		),
+	)
+}
@@ -2,9 +2,14 @@ package subcmd
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
 	"github.com/spf13/cobra"
 )
 
@@ -16,12 +21,28 @@ var replCmd = &cobra.Command{
 }
 
 type replCmdConfig struct {
+	clusterConfigs []string
+	historyFile    string
+
 	shared sharedOptions
 }
 
 var replConfig replCmdConfig
 
 func init() {
+	replCmd.Flags().StringSliceVar(
+		&replConfig.clusterConfigs,
+		"cluster-configs",
+		[]string{},
+		"Paths to other cluster configs that can be switched to by name with 'use'",
+	)
+	replCmd.Flags().StringVar(
+		&replConfig.historyFile,
+		"history-file",
+		defaultReplHistoryPath(),
+		"File to save commands to across sessions; set to an empty string to disable",
+	)
+
 	addSharedFlags(replCmd, &replConfig.shared)
 	RootCmd.AddCommand(replCmd)
 }
@@ -34,17 +55,103 @@ func replRun(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	sess := session.Must(session.NewSession())
 
+	values, err := replConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	// Map from cluster name to config path
+	clusterPaths := map[string]string{}
+	clusterNames := []string{}
+
+	var clusterName string
+
+	configPaths := append([]string{replConfig.shared.clusterConfig}, replConfig.clusterConfigs...)
+	for _, path := range configPaths {
+		if path == "" {
+			continue
+		}
+		clusterConfig, err := config.LoadClusterFileWithValues(
+			path,
+			replConfig.shared.expandEnv,
+			values,
+		)
+		if err != nil {
+			return err
+		}
+		if path == replConfig.shared.clusterConfig {
+			clusterName = clusterConfig.Meta.Name
+		}
+		if _, ok := clusterPaths[clusterConfig.Meta.Name]; !ok {
+			clusterNames = append(clusterNames, clusterConfig.Meta.Name)
+		}
+		clusterPaths[clusterConfig.Meta.Name] = path
+	}
+
 	adminClient, err := replConfig.shared.getAdminClient(ctx, sess, true)
 	if err != nil {
 		return err
 	}
-	defer adminClient.Close()
 
-	repl, err := cli.NewRepl(ctx, adminClient)
+	repl, err := cli.NewRepl(
+		ctx,
+		adminClient,
+		cli.ReplConfig{
+			ClusterName:  clusterName,
+			ClusterNames: clusterNames,
+			LoadCluster: func(ctx context.Context, cluster string) (string, admin.Client, error) {
+				path, ok := clusterPaths[cluster]
+				if !ok {
+					if _, err := os.Stat(cluster); err != nil {
+						return "", nil, fmt.Errorf(
+							"Cluster %s is not a known cluster name or config path",
+							cluster,
+						)
+					}
+					path = cluster
+				}
+
+				clusterConfig, err := config.LoadClusterFileWithValues(
+					path,
+					replConfig.shared.expandEnv,
+					values,
+				)
+				if err != nil {
+					return "", nil, err
+				}
+				if err := clusterConfig.Validate(); err != nil {
+					return "", nil, err
+				}
+
+				adminClient, err := clusterConfig.NewAdminClient(
+					ctx,
+					sess,
+					true,
+					replConfig.shared.saslUsername,
+					replConfig.shared.saslPassword,
+				)
+				if err != nil {
+					return "", nil, err
+				}
+				return clusterConfig.Meta.Name, adminClient, nil
+			},
+			HistoryPath: replConfig.historyFile,
+		},
+	)
 	if err != nil {
+		adminClient.Close()
 		return err
 	}
+	defer repl.Close()
 
 	repl.Run()
 	return nil
 }
+
+func defaultReplHistoryPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, cli.DefaultReplHistoryFile)
+}
@@ -0,0 +1,112 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/report"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:     "report",
+	Short:   "generate a shareable report on the state of a cluster and its managed topics",
+	Args:    cobra.NoArgs,
+	PreRunE: reportPreRun,
+	RunE:    reportRun,
+}
+
+type reportCmdConfig struct {
+	checkLeaders bool
+	format       string
+	outputPath   string
+
+	shared sharedOptions
+}
+
+var reportConfig reportCmdConfig
+
+func init() {
+	reportCmd.Flags().BoolVar(
+		&reportConfig.checkLeaders,
+		"check-leaders",
+		false,
+		"Check that the leaders of the managed topics are correct",
+	)
+	reportCmd.Flags().StringVar(
+		&reportConfig.format,
+		"format",
+		string(report.OutputFormatHTML),
+		fmt.Sprintf("Report format (choices: %+v)", report.AllOutputFormats),
+	)
+	reportCmd.Flags().StringVarP(
+		&reportConfig.outputPath,
+		"output-path",
+		"o",
+		"",
+		"Path to write the report to; if unset, it's written to stdout",
+	)
+
+	addSharedConfigOnlyFlags(reportCmd, &reportConfig.shared)
+	reportCmd.MarkFlagRequired("cluster-config")
+	RootCmd.AddCommand(reportCmd)
+}
+
+func reportPreRun(cmd *cobra.Command, args []string) error {
+	if _, err := report.ParseOutputFormat(reportConfig.format); err != nil {
+		return err
+	}
+	return reportConfig.shared.validate()
+}
+
+func reportRun(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	values, err := reportConfig.shared.templateValues()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.LoadClusterFileWithValues(
+		reportConfig.shared.clusterConfig,
+		reportConfig.shared.expandEnv,
+		values,
+	)
+	if err != nil {
+		return err
+	}
+
+	topicConfigs, err := managedTopicConfigs(clusterConfig)
+	if err != nil {
+		return err
+	}
+
+	adminClient, err := clusterConfig.NewAdminClient(
+		ctx,
+		nil,
+		true,
+		reportConfig.shared.saslUsername,
+		reportConfig.shared.saslPassword,
+	)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+	return cliRunner.GenerateReport(
+		ctx,
+		report.BuildConfig{
+			AdminClient:   adminClient,
+			ClusterConfig: clusterConfig,
+			TopicConfigs:  topicConfigs,
+			CheckLeaders:  reportConfig.checkLeaders,
+		},
+		report.OutputFormat(reportConfig.format),
+		reportConfig.outputPath,
+	)
+}
@@ -4,10 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/audit"
 	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
 	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/segmentio/topicctl/pkg/messages"
+	"github.com/segmentio/topicctl/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -21,8 +27,14 @@ var resetOffsetsCmd = &cobra.Command{
 }
 
 type resetOffsetsCmdConfig struct {
-	offset     int64
-	partitions []int
+	fromFile    string
+	offset      int64
+	partitions  []int
+	rewind      time.Duration
+	shiftBy     int64
+	toEarliest  bool
+	toLatest    bool
+	toTimestamp string
 
 	shared sharedOptions
 }
@@ -30,24 +42,90 @@ type resetOffsetsCmdConfig struct {
 var resetOffsetsConfig resetOffsetsCmdConfig
 
 func init() {
+	resetOffsetsCmd.Flags().StringVar(
+		&resetOffsetsConfig.fromFile,
+		"from-file",
+		"",
+		"Path to a JSON file that maps partition IDs to new offsets",
+	)
 	resetOffsetsCmd.Flags().Int64Var(
 		&resetOffsetsConfig.offset,
 		"offset",
 		-2,
-		"Offset",
+		"Offset (-2 for earliest, -1 for latest)",
 	)
 	resetOffsetsCmd.Flags().IntSliceVar(
 		&resetOffsetsConfig.partitions,
 		"partitions",
 		[]int{},
-		"Partition (defaults to all)",
+		"Comma-separated IDs of the partitions to reset, e.g. 3,7 (defaults to all)",
+	)
+	resetOffsetsCmd.Flags().DurationVar(
+		&resetOffsetsConfig.rewind,
+		"rewind",
+		0,
+		"Rewind to the first offsets at or after this long ago, e.g. 2h",
+	)
+	resetOffsetsCmd.Flags().Int64Var(
+		&resetOffsetsConfig.shiftBy,
+		"shift-by",
+		0,
+		"Shift the current offsets by this amount (negative to go back)",
+	)
+	resetOffsetsCmd.Flags().BoolVar(
+		&resetOffsetsConfig.toEarliest,
+		"to-earliest",
+		false,
+		"Reset to the earliest offsets",
+	)
+	resetOffsetsCmd.Flags().BoolVar(
+		&resetOffsetsConfig.toLatest,
+		"to-latest",
+		false,
+		"Reset to the latest offsets",
+	)
+	resetOffsetsCmd.Flags().StringVar(
+		&resetOffsetsConfig.toTimestamp,
+		"to-timestamp",
+		"",
+		"Reset to the first offsets at or after this time (RFC3339 time, date, or duration ago)",
 	)
 
 	addSharedFlags(resetOffsetsCmd, &resetOffsetsConfig.shared)
+	resetOffsetsCmd.ValidArgsFunction = completePositionalArgs(
+		&resetOffsetsConfig.shared,
+		completionKindTopics,
+		completionKindGroups,
+	)
 	RootCmd.AddCommand(resetOffsetsCmd)
 }
 
 func resetOffsetsPreRun(cmd *cobra.Command, args []string) error {
+	resetFlags := []string{
+		"from-file",
+		"offset",
+		"rewind",
+		"shift-by",
+		"to-earliest",
+		"to-latest",
+		"to-timestamp",
+	}
+	setFlags := []string{}
+	for _, flag := range resetFlags {
+		if cmd.Flags().Changed(flag) {
+			setFlags = append(setFlags, flag)
+		}
+	}
+	if len(setFlags) > 1 {
+		return fmt.Errorf("Can only set one of %+v; got %+v", resetFlags, setFlags)
+	}
+	if cmd.Flags().Changed("rewind") && resetOffsetsConfig.rewind <= 0 {
+		return errors.New("Rewind duration must be positive")
+	}
+	if resetOffsetsConfig.fromFile != "" && len(resetOffsetsConfig.partitions) > 0 {
+		return errors.New("Cannot set partitions when using from-file")
+	}
+
 	return resetOffsetsConfig.shared.validate()
 }
 
@@ -55,7 +133,12 @@ func resetOffsetsRun(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	adminClient, err := resetOffsetsConfig.shared.getAdminClient(ctx, nil, true)
+	adminClient, clusterName, err := resetOffsetsConfig.shared.newAdminClient(
+		ctx,
+		nil,
+		true,
+		config.ListenerPurposeAdmin,
+	)
 	if err != nil {
 		return err
 	}
@@ -68,33 +151,102 @@ func resetOffsetsRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	partitionIDsMap := map[int]struct{}{}
-	for _, partitionInfo := range topicInfo.Partitions {
-		partitionIDsMap[partitionInfo.ID] = struct{}{}
+
+	var fileOffsets map[int]int64
+	requestedPartitions := resetOffsetsConfig.partitions
+
+	if resetOffsetsConfig.fromFile != "" {
+		fileOffsets, err = groups.LoadOffsetsFile(resetOffsetsConfig.fromFile)
+		if err != nil {
+			return err
+		}
+		requestedPartitions = []int{}
+		for partition := range fileOffsets {
+			requestedPartitions = append(requestedPartitions, partition)
+		}
+	}
+
+	partitions, err := groups.SelectPartitions(
+		topic,
+		topicInfo.PartitionIDs(),
+		requestedPartitions,
+	)
+	if err != nil {
+		return err
+	}
+
+	connector := adminClient.GetConnector()
+
+	states, err := groups.GetPartitionOffsetStates(ctx, connector, topic, group, partitions)
+	if err != nil {
+		return err
 	}
 
 	partitionOffsets := map[int]int64{}
 
-	if len(resetOffsetsConfig.partitions) > 0 {
-		for _, partition := range resetOffsetsConfig.partitions {
-			if _, ok := partitionIDsMap[partition]; !ok {
-				return fmt.Errorf("Partition %d not found in topic %s", partition, topic)
+	switch {
+	case fileOffsets != nil:
+		partitionOffsets = fileOffsets
+	case cmd.Flags().Changed("shift-by"):
+		partitionOffsets, err = groups.ShiftedOffsets(states, resetOffsetsConfig.shiftBy)
+		if err != nil {
+			return err
+		}
+	case resetOffsetsConfig.toTimestamp != "" || resetOffsetsConfig.rewind > 0:
+		at := time.Now().Add(-resetOffsetsConfig.rewind)
+		if resetOffsetsConfig.toTimestamp != "" {
+			at, err = util.ParseTime(resetOffsetsConfig.toTimestamp, time.Now())
+			if err != nil {
+				return err
 			}
+		}
+		log.Infof("Resolving offsets at %s", at.Format(time.RFC3339))
 
-			partitionOffsets[partition] = resetOffsetsConfig.offset
+		timeOffsets, err := messages.GetTimeOffsets(ctx, connector, topic, partitions, at)
+		if err != nil {
+			return err
+		}
+		for _, timeOffset := range timeOffsets {
+			partitionOffsets[timeOffset.Partition] = timeOffset.Offset
 		}
-	} else {
-		for _, partitionInfo := range topicInfo.Partitions {
-			partitionOffsets[partitionInfo.ID] = resetOffsetsConfig.offset
+	default:
+		offset := resetOffsetsConfig.offset
+		if resetOffsetsConfig.toEarliest {
+			offset = -2
+		} else if resetOffsetsConfig.toLatest {
+			offset = -1
 		}
+
+		for _, state := range states {
+			switch offset {
+			case -2:
+				partitionOffsets[state.Partition] = state.FirstOffset
+			case -1:
+				partitionOffsets[state.Partition] = state.EndOffset
+			default:
+				partitionOffsets[state.Partition] = offset
+			}
+		}
+	}
+
+	if err := groups.CheckOffsets(states, partitionOffsets); err != nil {
+		return err
 	}
 
 	log.Infof(
 		"This will reset the offsets for the following partitions in topic %s for group %s:\n%s",
 		topic,
 		group,
-		groups.FormatPartitionOffsets(partitionOffsets),
+		groups.FormatOffsetResets(states, partitionOffsets),
 	)
+	if len(partitions) < len(topicInfo.Partitions) {
+		log.Infof(
+			"Only %d of the %d partitions in topic %s are reset; the others keep their current offsets.",
+			len(partitions),
+			len(topicInfo.Partitions),
+			topic,
+		)
+	}
 	log.Info(
 		"Please ensure that all other consumers are stopped, otherwise the reset might be overridden.",
 	)
@@ -105,10 +257,32 @@ func resetOffsetsRun(cmd *cobra.Command, args []string) error {
 	}
 
 	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
-	return cliRunner.ResetOffsets(
+	err = cliRunner.ResetOffsets(
 		ctx,
 		topic,
 		group,
 		partitionOffsets,
 	)
+
+	before := map[string]string{}
+	after := map[string]string{}
+	for _, state := range states {
+		if offset, ok := partitionOffsets[state.Partition]; ok {
+			before[strconv.Itoa(state.Partition)] = strconv.FormatInt(state.CommittedOffset, 10)
+			after[strconv.Itoa(state.Partition)] = strconv.FormatInt(offset, 10)
+		}
+	}
+	recordAuditEntry(
+		audit.Entry{
+			Operation: audit.OperationResetOffsets,
+			Cluster:   clusterName,
+			Topic:     topic,
+			Details:   fmt.Sprintf("group %s", group),
+			Before:    before,
+			After:     after,
+		},
+		err,
+	)
+
+	return err
 }
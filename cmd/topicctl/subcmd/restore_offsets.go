@@ -0,0 +1,138 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/segmentio/topicctl/pkg/audit"
+	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var restoreOffsetsCmd = &cobra.Command{
+	Use:   "restore-offsets [path]",
+	Short: "restore the committed offsets of a consumer group from a file",
+	Long: strings.Join(
+		[]string{
+			"Restore the committed offsets of a consumer group from a file.",
+			"",
+			"The file can be one written by export-offsets or a group snapshot written by bootstrap.",
+		},
+		"\n",
+	),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: restoreOffsetsPreRun,
+	RunE:    restoreOffsetsRun,
+}
+
+type restoreOffsetsCmdConfig struct {
+	clamp       bool
+	dryRun      bool
+	group       string
+	skipConfirm bool
+	topics      []string
+
+	shared sharedOptions
+}
+
+var restoreOffsetsConfig restoreOffsetsCmdConfig
+
+func init() {
+	restoreOffsetsCmd.Flags().BoolVar(
+		&restoreOffsetsConfig.clamp,
+		"clamp",
+		false,
+		"Move offsets that are outside of the current partition bounds to the nearest bound instead of failing",
+	)
+	restoreOffsetsCmd.Flags().BoolVar(
+		&restoreOffsetsConfig.dryRun,
+		"dry-run",
+		false,
+		"Do a dry-run",
+	)
+	restoreOffsetsCmd.Flags().StringVar(
+		&restoreOffsetsConfig.group,
+		"group",
+		"",
+		"Group to restore the offsets to; defaults to the group in the file",
+	)
+	restoreOffsetsCmd.Flags().BoolVar(
+		&restoreOffsetsConfig.skipConfirm,
+		"skip-confirm",
+		false,
+		"Skip confirmation prompts",
+	)
+	restoreOffsetsCmd.Flags().StringArrayVar(
+		&restoreOffsetsConfig.topics,
+		"topic",
+		[]string{},
+		"Only restore the offsets in this topic; can be repeated",
+	)
+
+	addSharedFlags(restoreOffsetsCmd, &restoreOffsetsConfig.shared)
+	restoreOffsetsCmd.RegisterFlagCompletionFunc(
+		"group",
+		completeRepeatedArgs(&restoreOffsetsConfig.shared, completionKindGroups),
+	)
+	RootCmd.AddCommand(restoreOffsetsCmd)
+}
+
+func restoreOffsetsPreRun(cmd *cobra.Command, args []string) error {
+	return restoreOffsetsConfig.shared.validate()
+}
+
+func restoreOffsetsRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	sess := session.Must(session.NewSession())
+
+	snapshot, err := groups.LoadGroupOffsetsSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+
+	adminClient, clusterName, err := restoreOffsetsConfig.shared.newAdminClient(
+		ctx,
+		sess,
+		true,
+		config.ListenerPurposeAdmin,
+	)
+	if err != nil {
+		return err
+	}
+	defer adminClient.Close()
+
+	group := restoreOffsetsConfig.group
+	if group == "" {
+		group = snapshot.GroupID
+	}
+
+	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, !noSpinner)
+	err = cliRunner.RestoreOffsets(
+		ctx,
+		snapshot,
+		group,
+		restoreOffsetsConfig.topics,
+		restoreOffsetsConfig.clamp,
+		restoreOffsetsConfig.dryRun,
+		restoreOffsetsConfig.skipConfirm,
+	)
+
+	if !restoreOffsetsConfig.dryRun {
+		entry := audit.Entry{
+			Operation: audit.OperationRestoreOffsets,
+			Cluster:   clusterName,
+			Details:   fmt.Sprintf("group %s, from %s", group, args[0]),
+		}
+		if len(restoreOffsetsConfig.topics) == 1 {
+			entry.Topic = restoreOffsetsConfig.topics[0]
+		}
+		recordAuditEntry(entry, err)
+	}
+
+	return err
+}
@@ -3,15 +3,24 @@ package subcmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/segmentio/topicctl/pkg/audit"
+	"github.com/segmentio/topicctl/pkg/logging"
+	"github.com/segmentio/topicctl/pkg/util"
 	"github.com/segmentio/topicctl/pkg/version"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 )
 
+var auditLogPath string
 var debug bool
+var logFormat string
+var logLevel string
+var noColor bool
 var noSpinner bool
+var subsystemLogLevels []string
 
 // RootCmd is the cobra CLI root command.
 var RootCmd = &cobra.Command{
@@ -28,12 +37,45 @@ func init() {
 		FullTimestamp:   true,
 	})
 
+	RootCmd.PersistentFlags().StringVar(
+		&auditLogPath,
+		"audit-log",
+		envDefault("TOPICCTL_AUDIT_LOG", audit.DefaultLogPath()),
+		"path of the log that all cluster mutations are recorded in; set to empty to disable",
+	)
 	RootCmd.PersistentFlags().BoolVar(
 		&debug,
 		"debug",
 		false,
 		"enable debug logging",
 	)
+	RootCmd.PersistentFlags().StringVar(
+		&logFormat,
+		"log-format",
+		envDefault("TOPICCTL_LOG_FORMAT", string(logging.FormatText)),
+		fmt.Sprintf("log format (choices: %s, %s)", logging.FormatText, logging.FormatJSON),
+	)
+	RootCmd.PersistentFlags().StringVar(
+		&logLevel,
+		"log-level",
+		envDefault("TOPICCTL_LOG_LEVEL", log.InfoLevel.String()),
+		"log level; --debug is a shortcut for --log-level=debug",
+	)
+	RootCmd.PersistentFlags().StringSliceVar(
+		&subsystemLogLevels,
+		"subsystem-log-levels",
+		envDefaultSlice("TOPICCTL_SUBSYSTEM_LOG_LEVELS"),
+		fmt.Sprintf(
+			"log level overrides in the format [subsystem]=[level] (subsystems: %+v)",
+			logging.AllSubsystems,
+		),
+	)
+	RootCmd.PersistentFlags().BoolVar(
+		&noColor,
+		"no-color",
+		false,
+		"disable colorized output; colors are also disabled if NO_COLOR is set or not in a terminal",
+	)
 	RootCmd.PersistentFlags().BoolVar(
 		&noSpinner,
 		"no-spinner",
@@ -53,8 +95,48 @@ func Execute(versionRef string) {
 }
 
 func preRun(cmd *cobra.Command, args []string) error {
+	format, err := logging.ParseFormat(logFormat)
+	if err != nil {
+		return err
+	}
+
+	level, err := log.ParseLevel(logLevel)
+	if err != nil {
+		return err
+	}
 	if debug {
-		log.SetLevel(log.DebugLevel)
+		level = log.DebugLevel
+	}
+
+	subsystemLevels, err := logging.ParseSubsystemLevels(subsystemLogLevels)
+	if err != nil {
+		return err
+	}
+
+	logging.Configure(
+		logging.Config{
+			Format:          format,
+			Level:           level,
+			SubsystemLevels: subsystemLevels,
+		},
+	)
+
+	if noColor || !util.ColorEnabled() {
+		util.DisableColor()
 	}
 	return nil
 }
+
+func envDefault(envVar string, defaultValue string) string {
+	if value := os.Getenv(envVar); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func envDefaultSlice(envVar string) []string {
+	if value := os.Getenv(envVar); value != "" {
+		return strings.Split(value, ",")
+	}
+	return []string{}
+}
@@ -3,31 +3,46 @@ package subcmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/hashicorp/go-multierror"
 	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/audit"
 	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/rbac"
+	"github.com/segmentio/topicctl/pkg/tracing"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type sharedOptions struct {
-	brokerAddr    string
-	clusterConfig string
-	expandEnv     bool
-	saslMechanism string
-	saslPassword  string
-	saslUsername  string
-	tlsCACert     string
-	tlsCert       string
-	tlsEnabled    bool
-	tlsKey        string
-	tlsSkipVerify bool
-	tlsServerName string
-	zkAddr        string
-	zkPrefix      string
+	brokerAddr      string
+	clusterConfig   string
+	expandEnv       bool
+	saslMechanism   string
+	saslPassword    string
+	saslUsername    string
+	setValues       []string
+	tlsCACert       string
+	tlsCert         string
+	tlsEnabled      bool
+	tlsKey          string
+	tlsSkipVerify   bool
+	tlsServerName   string
+	valuesFiles     []string
+	zkAddr          string
+	zkPrefix        string
+	zkTLSCACert     string
+	zkTLSCert       string
+	zkTLSEnabled    bool
+	zkTLSKey        string
+	zkTLSServerName string
+	zkTLSSkipVerify bool
 }
 
 func (s sharedOptions) validate() error {
@@ -40,8 +55,15 @@ func (s sharedOptions) validate() error {
 		)
 	}
 
-	if s.clusterConfig != "" {
-		clusterConfig, clusterConfigErr := config.LoadClusterFile(s.clusterConfig, s.expandEnv)
+	values, valuesErr := s.templateValues()
+	if valuesErr != nil {
+		err = multierror.Append(err, valuesErr)
+	} else if s.clusterConfig != "" {
+		clusterConfig, clusterConfigErr := config.LoadClusterFileWithValues(
+			s.clusterConfig,
+			s.expandEnv,
+			values,
+		)
 		if clusterConfigErr != nil {
 			err = multierror.Append(
 				err,
@@ -67,7 +89,8 @@ func (s sharedOptions) validate() error {
 	}
 	if s.clusterConfig != "" &&
 		(s.zkAddr != "" || s.zkPrefix != "" || s.brokerAddr != "" || s.tlsCACert != "" ||
-			s.tlsCert != "" || s.tlsKey != "" || s.tlsServerName != "" || s.saslMechanism != "") {
+			s.tlsCert != "" || s.tlsKey != "" || s.tlsServerName != "" || s.saslMechanism != "" ||
+			s.zkTLSEnabled || s.zkTLSCACert != "" || s.zkTLSCert != "" || s.zkTLSKey != "") {
 		log.Warn("Broker and zk flags are ignored when using cluster-config")
 	}
 
@@ -77,6 +100,7 @@ func (s sharedOptions) validate() error {
 
 	useTLS := s.tlsEnabled || s.tlsCACert != "" || s.tlsCert != "" || s.tlsKey != ""
 	useSASL := s.saslMechanism != "" || s.saslPassword != "" || s.saslUsername != ""
+	useZKTLS := s.zkTLSEnabled || s.zkTLSCACert != "" || s.zkTLSCert != "" || s.zkTLSKey != ""
 
 	if useTLS && s.zkAddr != "" {
 		log.Warn("TLS flags are ignored accessing cluster via zookeeper")
@@ -84,6 +108,9 @@ func (s sharedOptions) validate() error {
 	if useSASL && s.zkAddr != "" {
 		log.Warn("SASL flags are ignored accessing cluster via zookeeper")
 	}
+	if useZKTLS && s.zkAddr == "" {
+		log.Warn("ZK TLS flags are ignored unless accessing cluster via zookeeper")
+	}
 
 	if useSASL {
 		saslMechanism, saslErr := admin.SASLNameToMechanism(s.saslMechanism)
@@ -95,6 +122,16 @@ func (s sharedOptions) validate() error {
 			(s.saslUsername != "" || s.saslPassword != "") {
 			log.Warn("Username and password are ignored if using SASL AWS-MSK-IAM")
 		}
+		if saslMechanism == admin.SASLMechanismGSSAPI ||
+			saslMechanism == admin.SASLMechanismOAuthBearer {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"SASL %s can only be configured via a cluster config",
+					strings.ToUpper(string(saslMechanism)),
+				),
+			)
+		}
 	}
 
 	return err
@@ -105,18 +142,58 @@ func (s sharedOptions) getAdminClient(
 	sess *session.Session,
 	readOnly bool,
 ) (admin.Client, error) {
+	return s.getAdminClientForPurpose(ctx, sess, readOnly, config.ListenerPurposeAdmin)
+}
+
+// getAdminClientForPurpose is like getAdminClient, but connects via the cluster config listener
+// that's selected for the argument purpose instead of the admin one.
+func (s sharedOptions) getAdminClientForPurpose(
+	ctx context.Context,
+	sess *session.Session,
+	readOnly bool,
+	purpose string,
+) (admin.Client, error) {
+	adminClient, clusterName, err := s.newAdminClient(ctx, sess, readOnly, purpose)
+	if err != nil {
+		return nil, err
+	}
+	return auditedAdminClient(adminClient, clusterName, readOnly), nil
+}
+
+// newAdminClient creates an admin client from the options and also returns the name of the
+// cluster that it's connected to, which is the address if there's no cluster config. The purpose
+// selects the listener in the cluster config, if any, and is ignored otherwise.
+func (s sharedOptions) newAdminClient(
+	ctx context.Context,
+	sess *session.Session,
+	readOnly bool,
+	purpose string,
+) (admin.Client, string, error) {
 	if s.clusterConfig != "" {
-		clusterConfig, err := config.LoadClusterFile(s.clusterConfig, s.expandEnv)
+		values, err := s.templateValues()
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
-		return clusterConfig.NewAdminClient(
+		clusterConfig, err := config.LoadClusterFileWithValues(
+			s.clusterConfig,
+			s.expandEnv,
+			values,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		clusterConfig, err = clusterConfig.ForPurpose(purpose)
+		if err != nil {
+			return nil, "", err
+		}
+		adminClient, err := clusterConfig.NewAdminClient(
 			ctx,
 			sess,
 			readOnly,
 			s.saslUsername,
 			s.saslPassword,
 		)
+		return adminClient, clusterConfig.Meta.Name, err
 	} else if s.brokerAddr != "" {
 		tlsEnabled := (s.tlsEnabled ||
 			s.tlsCACert != "" ||
@@ -132,11 +209,11 @@ func (s sharedOptions) getAdminClient(
 		if s.saslMechanism != "" {
 			saslMechanism, err = admin.SASLNameToMechanism(s.saslMechanism)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 		}
 
-		return admin.NewBrokerAdminClient(
+		adminClient, err := admin.NewBrokerAdminClient(
 			ctx,
 			admin.BrokerAdminClientConfig{
 				ConnectorConfig: admin.ConnectorConfig{
@@ -159,16 +236,116 @@ func (s sharedOptions) getAdminClient(
 				ReadOnly: readOnly,
 			},
 		)
+		return adminClient, s.brokerAddr, err
 	} else {
-		return admin.NewZKAdminClient(
+		zkTLSEnabled := (s.zkTLSEnabled ||
+			s.zkTLSCACert != "" ||
+			s.zkTLSCert != "" ||
+			s.zkTLSKey != "")
+
+		adminClient, err := admin.NewZKAdminClient(
 			ctx,
 			admin.ZKAdminClientConfig{
 				ZKAddrs:  []string{s.zkAddr},
 				ZKPrefix: s.zkPrefix,
 				Sess:     sess,
 				ReadOnly: readOnly,
+				TLS: admin.TLSConfig{
+					Enabled:    zkTLSEnabled,
+					CACertPath: s.zkTLSCACert,
+					CertPath:   s.zkTLSCert,
+					KeyPath:    s.zkTLSKey,
+					ServerName: s.zkTLSServerName,
+					SkipVerify: s.zkTLSSkipVerify,
+				},
 			},
 		)
+		return adminClient, s.zkAddr, err
+	}
+}
+
+// templateValues returns the values that configs should be rendered with, or nil if no
+// values files or overrides were set (in which case configs aren't treated as templates).
+func (s sharedOptions) templateValues() (config.TemplateValues, error) {
+	if len(s.valuesFiles) == 0 && len(s.setValues) == 0 {
+		return nil, nil
+	}
+	return config.LoadTemplateValues(s.valuesFiles, s.setValues)
+}
+
+// startTracing sets up tracing, if it's configured in the environment, and starts the root span
+// for the argument command. The returned function ends the span with the argument error and then
+// flushes any pending spans; it should be called when the command finishes.
+func startTracing(
+	ctx context.Context,
+	command string,
+	args []string,
+) (context.Context, func(err error)) {
+	shutdown, err := tracing.Init(ctx)
+	if err != nil {
+		log.Warnf("Could not set up tracing: %+v", err)
+		return ctx, func(err error) {}
+	}
+
+	ctx, span := tracing.StartSpan(
+		ctx,
+		fmt.Sprintf("topicctl %s", command),
+		attribute.StringSlice("args", args),
+	)
+
+	return ctx, func(err error) {
+		tracing.EndSpan(span, err)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := shutdown(shutdownCtx); err != nil {
+			log.Warnf("Could not export traces: %+v", err)
+		}
+	}
+}
+
+// tracedAdminClient wraps the argument admin client so that its calls are traced, if tracing is
+// enabled.
+func tracedAdminClient(adminClient admin.Client) admin.Client {
+	if !tracing.Enabled() {
+		return adminClient
+	}
+	return admin.NewTracingClient(adminClient)
+}
+
+// auditedAdminClient wraps the argument admin client so that its mutations are recorded in the
+// audit log, unless the client is read-only or the audit log is disabled.
+func auditedAdminClient(
+	adminClient admin.Client,
+	clusterName string,
+	readOnly bool,
+) admin.Client {
+	if readOnly || auditLogPath == "" {
+		return adminClient
+	}
+	return audit.NewClient(adminClient, audit.NewLog(auditLogPath), clusterName)
+}
+
+// auditedRoleBindingClient wraps the argument role binding client so that the bindings it
+// creates and deletes are recorded in the audit log, unless the audit log is disabled.
+func auditedRoleBindingClient(rbacClient rbac.Client, clusterName string) rbac.Client {
+	if auditLogPath == "" {
+		return rbacClient
+	}
+	return audit.NewRoleBindingClient(rbacClient, audit.NewLog(auditLogPath), clusterName)
+}
+
+// recordAuditEntry appends the argument entry, along with the argument error if it's non-nil,
+// to the audit log. This is used for mutations that aren't made through an admin client.
+func recordAuditEntry(entry audit.Entry, err error) {
+	if auditLogPath == "" {
+		return
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if recordErr := audit.NewLog(auditLogPath).Record(entry); recordErr != nil {
+		log.Warnf("Could not write to audit log %s: %+v", auditLogPath, recordErr)
 	}
 }
 
@@ -260,6 +437,42 @@ func addSharedFlags(cmd *cobra.Command, options *sharedOptions) {
 		"",
 		"Prefix for cluster-related nodes in zk",
 	)
+	cmd.Flags().StringVar(
+		&options.zkTLSCACert,
+		"zk-tls-ca-cert",
+		"",
+		"Path to CA cert PEM file if using TLS with zk",
+	)
+	cmd.Flags().StringVar(
+		&options.zkTLSCert,
+		"zk-tls-cert",
+		"",
+		"Path to client cert PEM file if using TLS with zk",
+	)
+	cmd.Flags().BoolVar(
+		&options.zkTLSEnabled,
+		"zk-tls-enabled",
+		false,
+		"Use TLS for communication with zk",
+	)
+	cmd.Flags().StringVar(
+		&options.zkTLSKey,
+		"zk-tls-key",
+		"",
+		"Path to client private key PEM file if using TLS with zk",
+	)
+	cmd.Flags().StringVar(
+		&options.zkTLSServerName,
+		"zk-tls-server-name",
+		"",
+		"Server name to use for zk TLS cert verification",
+	)
+	cmd.Flags().BoolVar(
+		&options.zkTLSSkipVerify,
+		"zk-tls-skip-verify",
+		false,
+		"Skip hostname verification when using TLS with zk",
+	)
 }
 
 func addSharedConfigOnlyFlags(cmd *cobra.Command, options *sharedOptions) {
@@ -288,4 +501,16 @@ func addSharedConfigOnlyFlags(cmd *cobra.Command, options *sharedOptions) {
 		os.Getenv("TOPICCTL_SASL_USERNAME"),
 		"SASL username if using SASL; will override value set in cluster config",
 	)
+	cmd.Flags().StringArrayVar(
+		&options.setValues,
+		"set",
+		[]string{},
+		"Template value override in key=value format; can be repeated",
+	)
+	cmd.Flags().StringArrayVar(
+		&options.valuesFiles,
+		"values",
+		[]string{},
+		"Path to a file with template values for rendering configs; can be repeated",
+	)
 }
@@ -2,13 +2,17 @@ package subcmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/cli"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/messages"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -22,9 +26,13 @@ var tailCmd = &cobra.Command{
 }
 
 type tailCmdConfig struct {
-	offset     int64
-	partitions []int
-	raw        bool
+	filter            string
+	keyDecoder        string
+	offset            int64
+	partitions        []int
+	raw               bool
+	schemaRegistryURL string
+	valueDecoder      string
 
 	shared sharedOptions
 }
@@ -32,6 +40,18 @@ type tailCmdConfig struct {
 var tailConfig tailCmdConfig
 
 func init() {
+	tailCmd.Flags().StringVar(
+		&tailConfig.filter,
+		"filter",
+		"",
+		"jq expression to filter messages by, e.g. '.value.id == 1234 and .partition == 0'",
+	)
+	tailCmd.Flags().StringVar(
+		&tailConfig.keyDecoder,
+		"key-decoder",
+		string(messages.DecoderTypeString),
+		fmt.Sprintf("Decoder for message keys (choices: %s)", decoderChoices()),
+	)
 	tailCmd.Flags().Int64Var(
 		&tailConfig.offset,
 		"offset",
@@ -50,8 +70,21 @@ func init() {
 		false,
 		"Output raw values only",
 	)
+	tailCmd.Flags().StringVar(
+		&tailConfig.schemaRegistryURL,
+		"schema-registry-url",
+		os.Getenv("TOPICCTL_SCHEMA_REGISTRY_URL"),
+		"Schema registry URL; required for the avro and protobuf decoders",
+	)
+	tailCmd.Flags().StringVar(
+		&tailConfig.valueDecoder,
+		"value-decoder",
+		string(messages.DecoderTypeString),
+		fmt.Sprintf("Decoder for message values (choices: %s)", decoderChoices()),
+	)
 
 	addSharedFlags(tailCmd, &tailConfig.shared)
+	tailCmd.ValidArgsFunction = completePositionalArgs(&tailConfig.shared, completionKindTopics)
 	RootCmd.AddCommand(tailCmd)
 }
 
@@ -74,12 +107,42 @@ func tailRun(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	adminClient, err := tailConfig.shared.getAdminClient(ctx, nil, true)
+	adminClient, err := tailConfig.shared.getAdminClientForPurpose(
+		ctx,
+		nil,
+		true,
+		config.ListenerPurposeTail,
+	)
 	if err != nil {
 		return err
 	}
 	defer adminClient.Close()
 
+	var filter *messages.MessageFilter
+	if tailConfig.filter != "" {
+		filter, err = messages.NewMessageFilter(tailConfig.filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	var registryClient *messages.SchemaRegistryClient
+	if tailConfig.schemaRegistryURL != "" {
+		registryClient, err = messages.NewSchemaRegistryClient(tailConfig.schemaRegistryURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	keyDecoder, err := tailDecoder(tailConfig.keyDecoder, registryClient)
+	if err != nil {
+		return err
+	}
+	valueDecoder, err := tailDecoder(tailConfig.valueDecoder, registryClient)
+	if err != nil {
+		return err
+	}
+
 	cliRunner := cli.NewCLIRunner(adminClient, log.Infof, false)
 	return cliRunner.Tail(
 		ctx,
@@ -88,10 +151,33 @@ func tailRun(cmd *cobra.Command, args []string) error {
 		tailConfig.partitions,
 		-1,
 		"",
+		filter,
 		tailConfig.raw,
+		keyDecoder,
+		valueDecoder,
 	)
 }
 
+// tailDecoder returns the decoder for the argument type. The default string type maps to a nil
+// decoder so that the tailer's output is unchanged when no decoders are set.
+func tailDecoder(
+	decoderType string,
+	registryClient *messages.SchemaRegistryClient,
+) (messages.Decoder, error) {
+	if messages.DecoderType(decoderType) == messages.DecoderTypeString {
+		return nil, nil
+	}
+	return messages.NewDecoder(messages.DecoderType(decoderType), registryClient)
+}
+
+func decoderChoices() string {
+	choices := []string{}
+	for _, decoderType := range messages.AllDecoderTypes {
+		choices = append(choices, string(decoderType))
+	}
+	return strings.Join(choices, ", ")
+}
+
 func stringsToInts(strs []string) ([]int, error) {
 	ints := []int{}
 
@@ -0,0 +1,450 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+)
+
+// ACLInfo represents a single ACL binding in the cluster, i.e. a principal that's allowed or
+// denied an operation on a resource.
+type ACLInfo struct {
+	ResourceType   kafka.ResourceType      `json:"resourceType"`
+	ResourceName   string                  `json:"resourceName"`
+	PatternType    kafka.PatternType       `json:"patternType"`
+	Principal      string                  `json:"principal"`
+	Host           string                  `json:"host"`
+	Operation      kafka.ACLOperationType  `json:"operation"`
+	PermissionType kafka.ACLPermissionType `json:"permissionType"`
+}
+
+// String returns a human-readable summary of the ACL.
+func (a ACLInfo) String() string {
+	return fmt.Sprintf(
+		"%s %s on %s %s (%s) from host %s for %s",
+		a.PermissionType,
+		a.Operation,
+		a.ResourceType,
+		a.ResourceName,
+		a.PatternType,
+		a.Host,
+		a.Principal,
+	)
+}
+
+// Filter returns a filter that matches just this ACL.
+func (a ACLInfo) Filter() kafka.ACLFilter {
+	return kafka.ACLFilter{
+		ResourceTypeFilter:        a.ResourceType,
+		ResourceNameFilter:        a.ResourceName,
+		ResourcePatternTypeFilter: a.PatternType,
+		PrincipalFilter:           a.Principal,
+		HostFilter:                a.Host,
+		Operation:                 a.Operation,
+		PermissionType:            a.PermissionType,
+	}
+}
+
+// ACLFilter is a filter on the ACLs in a cluster. The string fields are parsed into their
+// kafka-go equivalents via KafkaFilter; blank fields match all ACLs.
+type ACLFilter struct {
+	ResourceType   string
+	ResourceName   string
+	PatternType    string
+	Principal      string
+	Host           string
+	Operation      string
+	PermissionType string
+}
+
+// KafkaFilter converts the filter into the equivalent kafka-go filter.
+func (f ACLFilter) KafkaFilter() (kafka.ACLFilter, error) {
+	kafkaFilter := kafka.ACLFilter{
+		ResourceTypeFilter:        kafka.ResourceTypeAny,
+		ResourceNameFilter:        f.ResourceName,
+		ResourcePatternTypeFilter: kafka.PatternTypeAny,
+		PrincipalFilter:           f.Principal,
+		HostFilter:                f.Host,
+		Operation:                 kafka.ACLOperationTypeAny,
+		PermissionType:            kafka.ACLPermissionTypeAny,
+	}
+
+	if f.ResourceType != "" {
+		if err := kafkaFilter.ResourceTypeFilter.UnmarshalText(
+			[]byte(f.ResourceType),
+		); err != nil {
+			return kafkaFilter, fmt.Errorf("Invalid resource type '%s'", f.ResourceType)
+		}
+	}
+	if f.PatternType != "" {
+		if err := kafkaFilter.ResourcePatternTypeFilter.UnmarshalText(
+			[]byte(f.PatternType),
+		); err != nil {
+			return kafkaFilter, fmt.Errorf("Invalid pattern type '%s'", f.PatternType)
+		}
+	}
+	if f.Operation != "" {
+		if err := kafkaFilter.Operation.UnmarshalText([]byte(f.Operation)); err != nil {
+			return kafkaFilter, fmt.Errorf("Invalid operation '%s'", f.Operation)
+		}
+	}
+	if f.PermissionType != "" {
+		if err := kafkaFilter.PermissionType.UnmarshalText(
+			[]byte(f.PermissionType),
+		); err != nil {
+			return kafkaFilter, fmt.Errorf("Invalid permission type '%s'", f.PermissionType)
+		}
+	}
+
+	return kafkaFilter, nil
+}
+
+// ACLSpec describes a set of ACLs on a single resource, in the style of the kafka-acls.sh
+// tool; an ACL is generated for each combination of principal and operation. Unlike the
+// fields in ACLFilter, all of these must resolve to concrete values.
+type ACLSpec struct {
+	ResourceType   string
+	ResourceName   string
+	PatternType    string
+	Principals     []string
+	Host           string
+	Operations     []string
+	PermissionType string
+}
+
+// ACLs validates the spec and returns the ACLs that it describes. The pattern type defaults to
+// literal, the host to "*", and the permission type to allow. The name of cluster resources
+// defaults to "kafka-cluster", which is the only one that Kafka accepts.
+func (s ACLSpec) ACLs() ([]ACLInfo, error) {
+	var resourceType kafka.ResourceType
+	if err := resourceType.UnmarshalText([]byte(s.ResourceType)); err != nil ||
+		resourceType == kafka.ResourceTypeAny {
+		return nil, fmt.Errorf("Invalid resource type '%s'", s.ResourceType)
+	}
+
+	resourceName := s.ResourceName
+	if resourceName == "" && resourceType == kafka.ResourceTypeCluster {
+		resourceName = "kafka-cluster"
+	}
+	if resourceName == "" {
+		return nil, errors.New("Resource name must be set")
+	}
+
+	patternType := kafka.PatternTypeLiteral
+	if s.PatternType != "" {
+		if err := patternType.UnmarshalText([]byte(s.PatternType)); err != nil ||
+			(patternType != kafka.PatternTypeLiteral && patternType != kafka.PatternTypePrefixed) {
+			return nil, fmt.Errorf(
+				"Invalid pattern type '%s'; must be literal or prefixed",
+				s.PatternType,
+			)
+		}
+	}
+
+	host := s.Host
+	if host == "" {
+		host = "*"
+	}
+
+	permissionType := kafka.ACLPermissionTypeAllow
+	if s.PermissionType != "" {
+		if err := permissionType.UnmarshalText([]byte(s.PermissionType)); err != nil ||
+			(permissionType != kafka.ACLPermissionTypeAllow &&
+				permissionType != kafka.ACLPermissionTypeDeny) {
+			return nil, fmt.Errorf(
+				"Invalid permission type '%s'; must be allow or deny",
+				s.PermissionType,
+			)
+		}
+	}
+
+	if len(s.Principals) == 0 {
+		return nil, errors.New("At least one principal must be set")
+	}
+	for _, principal := range s.Principals {
+		if !strings.Contains(principal, ":") {
+			return nil, fmt.Errorf(
+				"Invalid principal '%s'; must be in the form [type]:[name], e.g. User:alice",
+				principal,
+			)
+		}
+	}
+
+	if len(s.Operations) == 0 {
+		return nil, errors.New("At least one operation must be set")
+	}
+	operations := []kafka.ACLOperationType{}
+	for _, operationStr := range s.Operations {
+		var operation kafka.ACLOperationType
+		if err := operation.UnmarshalText([]byte(operationStr)); err != nil ||
+			operation == kafka.ACLOperationTypeAny {
+			return nil, fmt.Errorf("Invalid operation '%s'", operationStr)
+		}
+		operations = append(operations, operation)
+	}
+
+	acls := []ACLInfo{}
+	for _, principal := range s.Principals {
+		for _, operation := range operations {
+			acls = append(
+				acls,
+				ACLInfo{
+					ResourceType:   resourceType,
+					ResourceName:   resourceName,
+					PatternType:    patternType,
+					Principal:      principal,
+					Host:           host,
+					Operation:      operation,
+					PermissionType: permissionType,
+				},
+			)
+		}
+	}
+
+	SortACLs(acls)
+	return acls, nil
+}
+
+// ACLDiffs compares the argument desired ACLs against the current ones in a cluster. It returns
+// the desired ACLs that are missing from the cluster and the current ones that aren't desired,
+// both sorted via SortACLs.
+func ACLDiffs(desired []ACLInfo, current []ACLInfo) ([]ACLInfo, []ACLInfo) {
+	desiredSet := map[ACLInfo]struct{}{}
+	for _, acl := range desired {
+		desiredSet[acl] = struct{}{}
+	}
+	currentSet := map[ACLInfo]struct{}{}
+	for _, acl := range current {
+		currentSet[acl] = struct{}{}
+	}
+
+	missing := []ACLInfo{}
+	for acl := range desiredSet {
+		if _, ok := currentSet[acl]; !ok {
+			missing = append(missing, acl)
+		}
+	}
+	extra := []ACLInfo{}
+	for acl := range currentSet {
+		if _, ok := desiredSet[acl]; !ok {
+			extra = append(extra, acl)
+		}
+	}
+
+	SortACLs(missing)
+	SortACLs(extra)
+	return missing, extra
+}
+
+// BroadACLs returns the allow ACLs in the argument list that grant more than a typical
+// application needs, i.e. ones for all operations, for a wildcard principal (e.g. User:*), or
+// on a wildcard ("*") resource name. The results are sorted via SortACLs.
+func BroadACLs(acls []ACLInfo) []ACLInfo {
+	broad := []ACLInfo{}
+
+	for _, acl := range acls {
+		if acl.PermissionType != kafka.ACLPermissionTypeAllow {
+			continue
+		}
+		if acl.Operation == kafka.ACLOperationTypeAll ||
+			strings.HasSuffix(acl.Principal, ":*") ||
+			(acl.ResourceName == "*" && acl.PatternType == kafka.PatternTypeLiteral) {
+			broad = append(broad, acl)
+		}
+	}
+
+	SortACLs(broad)
+	return broad
+}
+
+// describeACLs gets the ACLs that match the argument filter via the DescribeAcls API. It's
+// shared by both client implementations. The results are sorted by resource, then principal,
+// then operation.
+func describeACLs(
+	ctx context.Context,
+	client *kafka.Client,
+	filter kafka.ACLFilter,
+) ([]ACLInfo, error) {
+	req := &kafka.DescribeACLsRequest{
+		Filter: filter,
+	}
+	log.Debugf("DescribeACLs request: %+v", req)
+
+	resp, err := client.DescribeACLs(ctx, req)
+	log.Debugf("DescribeACLs response: %+v (%+v)", resp, err)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("Error describing ACLs: %+v", resp.Error)
+	}
+
+	acls := []ACLInfo{}
+
+	for _, resource := range resp.Resources {
+		for _, acl := range resource.ACLs {
+			acls = append(
+				acls,
+				ACLInfo{
+					ResourceType:   resource.ResourceType,
+					ResourceName:   resource.ResourceName,
+					PatternType:    resource.PatternType,
+					Principal:      acl.Principal,
+					Host:           acl.Host,
+					Operation:      acl.Operation,
+					PermissionType: acl.PermissionType,
+				},
+			)
+		}
+	}
+
+	SortACLs(acls)
+	return acls, nil
+}
+
+// SortACLs sorts the argument ACLs by resource type, resource name, pattern type, principal,
+// host, operation, and permission type.
+func SortACLs(acls []ACLInfo) {
+	sort.Slice(acls, func(a, b int) bool {
+		aclA := acls[a]
+		aclB := acls[b]
+
+		if aclA.ResourceType != aclB.ResourceType {
+			return aclA.ResourceType < aclB.ResourceType
+		}
+		if aclA.ResourceName != aclB.ResourceName {
+			return aclA.ResourceName < aclB.ResourceName
+		}
+		if aclA.PatternType != aclB.PatternType {
+			return aclA.PatternType < aclB.PatternType
+		}
+		if aclA.Principal != aclB.Principal {
+			return aclA.Principal < aclB.Principal
+		}
+		if aclA.Host != aclB.Host {
+			return aclA.Host < aclB.Host
+		}
+		if aclA.Operation != aclB.Operation {
+			return aclA.Operation < aclB.Operation
+		}
+		return aclA.PermissionType < aclB.PermissionType
+	})
+}
+
+// createACLs creates the argument ACLs via the CreateAcls API. It's shared by both client
+// implementations.
+func createACLs(
+	ctx context.Context,
+	client *kafka.Client,
+	acls []ACLInfo,
+) error {
+	entries := []kafka.ACLEntry{}
+	for _, acl := range acls {
+		entries = append(
+			entries,
+			kafka.ACLEntry{
+				ResourceType:        acl.ResourceType,
+				ResourceName:        acl.ResourceName,
+				ResourcePatternType: acl.PatternType,
+				Principal:           acl.Principal,
+				Host:                acl.Host,
+				Operation:           acl.Operation,
+				PermissionType:      acl.PermissionType,
+			},
+		)
+	}
+
+	req := &kafka.CreateACLsRequest{
+		ACLs: entries,
+	}
+	log.Debugf("CreateACLs request: %+v", req)
+
+	resp, err := client.CreateACLs(ctx, req)
+	log.Debugf("CreateACLs response: %+v (%+v)", resp, err)
+	if err != nil {
+		return err
+	}
+
+	var createErr error
+	for e, entryErr := range resp.Errors {
+		if entryErr != nil && e < len(acls) {
+			createErr = multierror.Append(
+				createErr,
+				fmt.Errorf("Error creating ACL %s: %+v", acls[e], entryErr),
+			)
+		}
+	}
+
+	return createErr
+}
+
+// deleteACLs deletes the ACLs that match the argument filter via the DeleteAcls API and
+// returns the ones that were deleted. It's shared by both client implementations.
+func deleteACLs(
+	ctx context.Context,
+	client *kafka.Client,
+	filter kafka.ACLFilter,
+) ([]ACLInfo, error) {
+	req := &kafka.DeleteACLsRequest{
+		Filters: []kafka.DeleteACLsFilter{
+			{
+				ResourceTypeFilter:        filter.ResourceTypeFilter,
+				ResourceNameFilter:        filter.ResourceNameFilter,
+				ResourcePatternTypeFilter: filter.ResourcePatternTypeFilter,
+				PrincipalFilter:           filter.PrincipalFilter,
+				HostFilter:                filter.HostFilter,
+				Operation:                 filter.Operation,
+				PermissionType:            filter.PermissionType,
+			},
+		},
+	}
+	log.Debugf("DeleteACLs request: %+v", req)
+
+	resp, err := client.DeleteACLs(ctx, req)
+	log.Debugf("DeleteACLs response: %+v (%+v)", resp, err)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []ACLInfo{}
+	var deleteErr error
+
+	for _, result := range resp.Results {
+		if result.Error != nil {
+			deleteErr = multierror.Append(
+				deleteErr,
+				fmt.Errorf("Error deleting ACLs: %+v", result.Error),
+			)
+			continue
+		}
+
+		for _, match := range result.MatchingACLs {
+			acl := ACLInfo{
+				ResourceType:   match.ResourceType,
+				ResourceName:   match.ResourceName,
+				PatternType:    match.ResourcePatternType,
+				Principal:      match.Principal,
+				Host:           match.Host,
+				Operation:      match.Operation,
+				PermissionType: match.PermissionType,
+			}
+
+			if match.Error != nil {
+				deleteErr = multierror.Append(
+					deleteErr,
+					fmt.Errorf("Error deleting ACL %s: %+v", acl, match.Error),
+				)
+				continue
+			}
+			deleted = append(deleted, acl)
+		}
+	}
+
+	SortACLs(deleted)
+	return deleted, deleteErr
+}
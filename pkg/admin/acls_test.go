@@ -0,0 +1,241 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLFilterKafkaFilter(t *testing.T) {
+	kafkaFilter, err := ACLFilter{}.KafkaFilter()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		kafka.ACLFilter{
+			ResourceTypeFilter:        kafka.ResourceTypeAny,
+			ResourcePatternTypeFilter: kafka.PatternTypeAny,
+			Operation:                 kafka.ACLOperationTypeAny,
+			PermissionType:            kafka.ACLPermissionTypeAny,
+		},
+		kafkaFilter,
+	)
+
+	kafkaFilter, err = ACLFilter{
+		ResourceType:   "Topic",
+		ResourceName:   "test-topic",
+		PatternType:    "prefixed",
+		Principal:      "User:alice",
+		Operation:      "read",
+		PermissionType: "deny",
+	}.KafkaFilter()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		kafka.ACLFilter{
+			ResourceTypeFilter:        kafka.ResourceTypeTopic,
+			ResourceNameFilter:        "test-topic",
+			ResourcePatternTypeFilter: kafka.PatternTypePrefixed,
+			PrincipalFilter:           "User:alice",
+			Operation:                 kafka.ACLOperationTypeRead,
+			PermissionType:            kafka.ACLPermissionTypeDeny,
+		},
+		kafkaFilter,
+	)
+
+	_, err = ACLFilter{ResourceType: "bad-type"}.KafkaFilter()
+	assert.Error(t, err)
+	_, err = ACLFilter{Operation: "bad-operation"}.KafkaFilter()
+	assert.Error(t, err)
+}
+
+func TestSortACLs(t *testing.T) {
+	acls := []ACLInfo{
+		{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: "topic-b",
+			Principal:    "User:alice",
+			Operation:    kafka.ACLOperationTypeRead,
+		},
+		{
+			ResourceType: kafka.ResourceTypeGroup,
+			ResourceName: "group-a",
+			Principal:    "User:alice",
+			Operation:    kafka.ACLOperationTypeRead,
+		},
+		{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: "topic-a",
+			Principal:    "User:bob",
+			Operation:    kafka.ACLOperationTypeWrite,
+		},
+		{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: "topic-a",
+			Principal:    "User:alice",
+			Operation:    kafka.ACLOperationTypeWrite,
+		},
+	}
+	SortACLs(acls)
+
+	names := []string{}
+	for _, acl := range acls {
+		names = append(names, acl.ResourceName+"/"+acl.Principal)
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"topic-a/User:alice",
+			"topic-a/User:bob",
+			"topic-b/User:alice",
+			"group-a/User:alice",
+		},
+		names,
+	)
+}
+
+func TestACLSpecACLs(t *testing.T) {
+	acls, err := ACLSpec{
+		ResourceType: "topic",
+		ResourceName: "test-topic",
+		Principals:   []string{"User:bob", "User:alice"},
+		Operations:   []string{"write", "read"},
+	}.ACLs()
+	require.NoError(t, err)
+	require.Equal(t, 4, len(acls))
+	assert.Equal(
+		t,
+		ACLInfo{
+			ResourceType:   kafka.ResourceTypeTopic,
+			ResourceName:   "test-topic",
+			PatternType:    kafka.PatternTypeLiteral,
+			Principal:      "User:alice",
+			Host:           "*",
+			Operation:      kafka.ACLOperationTypeRead,
+			PermissionType: kafka.ACLPermissionTypeAllow,
+		},
+		acls[0],
+	)
+	assert.Equal(t, "User:bob", acls[3].Principal)
+	assert.Equal(t, kafka.ACLOperationTypeWrite, acls[3].Operation)
+
+	acls, err = ACLSpec{
+		ResourceType:   "cluster",
+		Principals:     []string{"User:alice"},
+		Operations:     []string{"alterconfigs"},
+		PermissionType: "deny",
+	}.ACLs()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(acls))
+	assert.Equal(t, "kafka-cluster", acls[0].ResourceName)
+	assert.Equal(t, kafka.ACLPermissionTypeDeny, acls[0].PermissionType)
+
+	invalidSpecs := []ACLSpec{
+		{
+			ResourceType: "any",
+			ResourceName: "test-topic",
+			Principals:   []string{"User:alice"},
+			Operations:   []string{"read"},
+		},
+		{
+			ResourceType: "topic",
+			Principals:   []string{"User:alice"},
+			Operations:   []string{"read"},
+		},
+		{
+			ResourceType: "topic",
+			ResourceName: "test-topic",
+			PatternType:  "match",
+			Principals:   []string{"User:alice"},
+			Operations:   []string{"read"},
+		},
+		{
+			ResourceType: "topic",
+			ResourceName: "test-topic",
+			Principals:   []string{"alice"},
+			Operations:   []string{"read"},
+		},
+		{
+			ResourceType: "topic",
+			ResourceName: "test-topic",
+			Principals:   []string{"User:alice"},
+		},
+		{
+			ResourceType:   "topic",
+			ResourceName:   "test-topic",
+			Principals:     []string{"User:alice"},
+			Operations:     []string{"read"},
+			PermissionType: "any",
+		},
+	}
+	for _, spec := range invalidSpecs {
+		_, err := spec.ACLs()
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestACLDiffs(t *testing.T) {
+	readACL := ACLInfo{
+		ResourceType:   kafka.ResourceTypeTopic,
+		ResourceName:   "test-topic",
+		PatternType:    kafka.PatternTypeLiteral,
+		Principal:      "User:alice",
+		Host:           "*",
+		Operation:      kafka.ACLOperationTypeRead,
+		PermissionType: kafka.ACLPermissionTypeAllow,
+	}
+	writeACL := readACL
+	writeACL.Operation = kafka.ACLOperationTypeWrite
+	otherUserACL := readACL
+	otherUserACL.Principal = "User:bob"
+
+	missing, extra := ACLDiffs(
+		[]ACLInfo{writeACL, readACL},
+		[]ACLInfo{otherUserACL, readACL},
+	)
+	assert.Equal(t, []ACLInfo{writeACL}, missing)
+	assert.Equal(t, []ACLInfo{otherUserACL}, extra)
+
+	missing, extra = ACLDiffs([]ACLInfo{readACL}, []ACLInfo{readACL})
+	assert.Equal(t, []ACLInfo{}, missing)
+	assert.Equal(t, []ACLInfo{}, extra)
+}
+
+func TestBroadACLs(t *testing.T) {
+	readACL := ACLInfo{
+		ResourceType:   kafka.ResourceTypeTopic,
+		ResourceName:   "test-topic",
+		PatternType:    kafka.PatternTypeLiteral,
+		Principal:      "User:alice",
+		Host:           "*",
+		Operation:      kafka.ACLOperationTypeRead,
+		PermissionType: kafka.ACLPermissionTypeAllow,
+	}
+	allACL := readACL
+	allACL.Operation = kafka.ACLOperationTypeAll
+	wildcardPrincipalACL := readACL
+	wildcardPrincipalACL.Principal = "User:*"
+	wildcardResourceACL := readACL
+	wildcardResourceACL.ResourceName = "*"
+	prefixedACL := readACL
+	prefixedACL.ResourceName = "test-"
+	prefixedACL.PatternType = kafka.PatternTypePrefixed
+	denyAllACL := allACL
+	denyAllACL.PermissionType = kafka.ACLPermissionTypeDeny
+
+	assert.Equal(
+		t,
+		[]ACLInfo{wildcardResourceACL, wildcardPrincipalACL, allACL},
+		BroadACLs(
+			[]ACLInfo{
+				readACL,
+				allACL,
+				wildcardPrincipalACL,
+				wildcardResourceACL,
+				prefixedACL,
+				denyAllACL,
+			},
+		),
+	)
+}
@@ -0,0 +1,290 @@
+package admin
+
+import (
+	"math"
+	"sort"
+)
+
+// BrokerLoad contains the number of replicas and leaders hosted by a single broker, along with
+// the total size of these replicas if known.
+type BrokerLoad struct {
+	BrokerID int    `json:"brokerID"`
+	Rack     string `json:"rack"`
+	Replicas int    `json:"replicas"`
+	Leaders  int    `json:"leaders"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// BalanceSkew summarizes how evenly a single quantity (e.g., replica count) is spread across
+// the brokers in a cluster.
+type BalanceSkew struct {
+	Min    int64   `json:"min"`
+	Max    int64   `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stdDev"`
+}
+
+// MaxSkew returns how far the most-loaded broker is above the mean, as a fraction of the mean.
+func (b BalanceSkew) MaxSkew() float64 {
+	if b.Mean == 0 {
+		return 0
+	}
+	return (float64(b.Max) - b.Mean) / b.Mean
+}
+
+// Score returns a balance score between 0 and 100, where 100 means that the most-loaded broker
+// is at the mean (i.e., the quantity is perfectly balanced).
+func (b BalanceSkew) Score() float64 {
+	if b.Max == 0 {
+		return 100.0
+	}
+	return 100.0 * b.Mean / float64(b.Max)
+}
+
+// TopicImbalance summarizes how much a single topic contributes to the imbalance of a
+// cluster.
+type TopicImbalance struct {
+	Topic string `json:"topic"`
+
+	// ExtraReplicas is the number of replicas that would need to move for the replicas of the
+	// topic to be spread evenly across the cluster's brokers.
+	ExtraReplicas int `json:"extraReplicas"`
+
+	// ExtraLeaders is the number of leaders that would need to move for the leaders of the
+	// topic to be spread evenly across the cluster's brokers.
+	ExtraLeaders int `json:"extraLeaders"`
+
+	// ExtraBytes is the total number of bytes that the topic's replicas exceed the per-broker
+	// mean by, summed across the brokers that are above it.
+	ExtraBytes int64 `json:"extraBytes"`
+}
+
+// ClusterBalance summarizes the balance of replicas, leaders, and, if known, bytes across the
+// brokers in a cluster.
+type ClusterBalance struct {
+	Brokers         []BrokerLoad     `json:"brokers"`
+	ReplicaSkew     BalanceSkew      `json:"replicaSkew"`
+	LeaderSkew      BalanceSkew      `json:"leaderSkew"`
+	BytesSkew       BalanceSkew      `json:"bytesSkew"`
+	HasSizes        bool             `json:"hasSizes"`
+	TopicImbalances []TopicImbalance `json:"topicImbalances"`
+}
+
+// Score returns the lowest of the scores of the individual skews.
+func (c ClusterBalance) Score() float64 {
+	score := math.Min(c.ReplicaSkew.Score(), c.LeaderSkew.Score())
+	if c.HasSizes {
+		score = math.Min(score, c.BytesSkew.Score())
+	}
+	return score
+}
+
+// GetClusterBalance evaluates the balance of the argument topics across the argument brokers.
+// If replicaLogDirs is nil, then sizes are assumed to be unknown and the byte-based metrics
+// are left empty. The topic imbalances only include the topics that are unbalanced and are
+// sorted with the worst offenders first.
+//
+// If the brokers have capacity weights, each broker is expected to have a share of the replicas,
+// leaders, and bytes that's proportional to its weight, and the skews are computed from the
+// broker values divided by their weights relative to the mean weight.
+func GetClusterBalance(
+	brokers []BrokerInfo,
+	topics []TopicInfo,
+	replicaLogDirs []ReplicaLogDirInfo,
+) ClusterBalance {
+	hasSizes := replicaLogDirs != nil
+	brokerWeights := BrokerWeights(brokers)
+
+	var totalWeight float64
+	for _, weight := range brokerWeights {
+		totalWeight += weight
+	}
+
+	loadsByID := map[int]*BrokerLoad{}
+	brokerLoads := []*BrokerLoad{}
+	for _, broker := range brokers {
+		load := &BrokerLoad{
+			BrokerID: broker.ID,
+			Rack:     broker.Rack,
+		}
+		loadsByID[broker.ID] = load
+		brokerLoads = append(brokerLoads, load)
+	}
+
+	// Map from topic to broker to bytes
+	topicBrokerBytes := map[string]map[int]int64{}
+	for _, replicaLogDir := range replicaLogDirs {
+		if replicaLogDir.IsFuture {
+			continue
+		}
+		if _, ok := topicBrokerBytes[replicaLogDir.Topic]; !ok {
+			topicBrokerBytes[replicaLogDir.Topic] = map[int]int64{}
+		}
+		topicBrokerBytes[replicaLogDir.Topic][replicaLogDir.BrokerID] += replicaLogDir.Size
+	}
+
+	topicImbalances := []TopicImbalance{}
+
+	for _, topic := range topics {
+		topicReplicas := map[int]int{}
+		topicLeaders := map[int]int{}
+		var totalReplicas, totalLeaders int
+
+		for _, partition := range topic.Partitions {
+			if _, ok := loadsByID[partition.Leader]; ok {
+				topicLeaders[partition.Leader]++
+				loadsByID[partition.Leader].Leaders++
+				totalLeaders++
+			}
+			for _, replica := range partition.Replicas {
+				if _, ok := loadsByID[replica]; ok {
+					topicReplicas[replica]++
+					loadsByID[replica].Replicas++
+					totalReplicas++
+				}
+			}
+		}
+
+		imbalance := TopicImbalance{
+			Topic:         topic.Name,
+			ExtraReplicas: extraCount(topicReplicas, totalReplicas, brokerLoads, brokerWeights),
+			ExtraLeaders:  extraCount(topicLeaders, totalLeaders, brokerLoads, brokerWeights),
+		}
+
+		if hasSizes && len(brokerLoads) > 0 {
+			var totalBytes int64
+			for brokerID, bytes := range topicBrokerBytes[topic.Name] {
+				if load, ok := loadsByID[brokerID]; ok {
+					load.Bytes += bytes
+					totalBytes += bytes
+				}
+			}
+
+			for _, load := range brokerLoads {
+				bytes := topicBrokerBytes[topic.Name][load.BrokerID]
+				shareBytes := float64(totalBytes) * brokerWeights[load.BrokerID] / totalWeight
+				if float64(bytes) > shareBytes {
+					imbalance.ExtraBytes += int64(float64(bytes) - shareBytes)
+				}
+			}
+		}
+
+		if imbalance.ExtraReplicas > 0 || imbalance.ExtraLeaders > 0 || imbalance.ExtraBytes > 0 {
+			topicImbalances = append(topicImbalances, imbalance)
+		}
+	}
+
+	sort.Slice(topicImbalances, func(a, b int) bool {
+		imbalanceA := topicImbalances[a]
+		imbalanceB := topicImbalances[b]
+
+		if hasSizes && imbalanceA.ExtraBytes != imbalanceB.ExtraBytes {
+			return imbalanceA.ExtraBytes > imbalanceB.ExtraBytes
+		}
+
+		extraA := imbalanceA.ExtraReplicas + imbalanceA.ExtraLeaders
+		extraB := imbalanceB.ExtraReplicas + imbalanceB.ExtraLeaders
+		if extraA != extraB {
+			return extraA > extraB
+		}
+		return imbalanceA.Topic < imbalanceB.Topic
+	})
+
+	balance := ClusterBalance{
+		Brokers:         []BrokerLoad{},
+		HasSizes:        hasSizes,
+		TopicImbalances: topicImbalances,
+	}
+
+	replicaValues := []int64{}
+	leaderValues := []int64{}
+	bytesValues := []int64{}
+
+	for _, load := range brokerLoads {
+		relativeWeight := brokerWeights[load.BrokerID] * float64(len(brokerLoads)) / totalWeight
+
+		balance.Brokers = append(balance.Brokers, *load)
+		replicaValues = append(replicaValues, weightedValue(int64(load.Replicas), relativeWeight))
+		leaderValues = append(leaderValues, weightedValue(int64(load.Leaders), relativeWeight))
+		bytesValues = append(bytesValues, weightedValue(load.Bytes, relativeWeight))
+	}
+
+	balance.ReplicaSkew = getBalanceSkew(replicaValues)
+	balance.LeaderSkew = getBalanceSkew(leaderValues)
+	if hasSizes {
+		balance.BytesSkew = getBalanceSkew(bytesValues)
+	}
+
+	return balance
+}
+
+// extraCount returns the number of items that would need to move for the argument per-broker
+// counts to be spread evenly, or in proportion to the broker weights. When a broker's share of
+// the total isn't a whole number, it's allowed to have up to the next one.
+func extraCount(
+	counts map[int]int,
+	total int,
+	brokerLoads []*BrokerLoad,
+	brokerWeights map[int]float64,
+) int {
+	if len(brokerLoads) == 0 {
+		return 0
+	}
+
+	var totalWeight float64
+	for _, load := range brokerLoads {
+		totalWeight += brokerWeights[load.BrokerID]
+	}
+
+	var extra int
+	for _, load := range brokerLoads {
+		maxPerBroker := int(
+			math.Ceil(float64(total) * brokerWeights[load.BrokerID] / totalWeight),
+		)
+		if counts[load.BrokerID] > maxPerBroker {
+			extra += counts[load.BrokerID] - maxPerBroker
+		}
+	}
+
+	return extra
+}
+
+// weightedValue returns the argument value divided by the argument relative weight, rounded to
+// the nearest integer.
+func weightedValue(value int64, relativeWeight float64) int64 {
+	if relativeWeight == 1.0 {
+		return value
+	}
+	return int64(math.Round(float64(value) / relativeWeight))
+}
+
+func getBalanceSkew(values []int64) BalanceSkew {
+	if len(values) == 0 {
+		return BalanceSkew{}
+	}
+
+	skew := BalanceSkew{
+		Min: values[0],
+		Max: values[0],
+	}
+
+	var total int64
+	for _, value := range values {
+		if value < skew.Min {
+			skew.Min = value
+		}
+		if value > skew.Max {
+			skew.Max = value
+		}
+		total += value
+	}
+	skew.Mean = float64(total) / float64(len(values))
+
+	var sumSquares float64
+	for _, value := range values {
+		sumSquares += math.Pow(float64(value)-skew.Mean, 2.0)
+	}
+	skew.StdDev = math.Sqrt(sumSquares / float64(len(values)))
+
+	return skew
+}
@@ -0,0 +1,241 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetClusterBalance(t *testing.T) {
+	brokers := []BrokerInfo{
+		{
+			ID:   1,
+			Rack: "rack1",
+		},
+		{
+			ID:   2,
+			Rack: "rack2",
+		},
+		{
+			ID:   3,
+			Rack: "rack3",
+		},
+	}
+	topics := []TopicInfo{
+		{
+			Name: "topic-a",
+			Partitions: []PartitionInfo{
+				{
+					ID:       0,
+					Leader:   1,
+					Replicas: []int{1, 2},
+				},
+				{
+					ID:       1,
+					Leader:   2,
+					Replicas: []int{2, 3},
+				},
+				{
+					ID:       2,
+					Leader:   3,
+					Replicas: []int{3, 1},
+				},
+			},
+		},
+		{
+			Name: "topic-b",
+			Partitions: []PartitionInfo{
+				{
+					ID:       0,
+					Leader:   1,
+					Replicas: []int{1, 2},
+				},
+				{
+					ID:       1,
+					Leader:   1,
+					Replicas: []int{1, 3},
+				},
+			},
+		},
+		{
+			Name: "topic-c",
+			Partitions: []PartitionInfo{
+				{
+					ID:       0,
+					Leader:   1,
+					Replicas: []int{1, 2},
+				},
+				{
+					ID:       1,
+					Leader:   1,
+					Replicas: []int{1, 2},
+				},
+				{
+					ID:       2,
+					Leader:   1,
+					Replicas: []int{1, 2},
+				},
+			},
+		},
+	}
+	replicaLogDirs := []ReplicaLogDirInfo{
+		{
+			Topic:    "topic-a",
+			BrokerID: 1,
+			Size:     100,
+		},
+		{
+			Topic:    "topic-a",
+			BrokerID: 2,
+			Size:     100,
+		},
+		{
+			Topic:    "topic-a",
+			BrokerID: 3,
+			Size:     100,
+		},
+		{
+			Topic:    "topic-c",
+			BrokerID: 1,
+			Size:     300,
+		},
+		{
+			Topic:    "topic-c",
+			BrokerID: 2,
+			Size:     300,
+		},
+		{
+			Topic:    "topic-c",
+			BrokerID: 3,
+			Size:     1000,
+			IsFuture: true,
+		},
+	}
+
+	balance := GetClusterBalance(brokers, topics, replicaLogDirs)
+	assert.True(t, balance.HasSizes)
+	assert.Equal(
+		t,
+		[]BrokerLoad{
+			{
+				BrokerID: 1,
+				Rack:     "rack1",
+				Replicas: 7,
+				Leaders:  6,
+				Bytes:    400,
+			},
+			{
+				BrokerID: 2,
+				Rack:     "rack2",
+				Replicas: 6,
+				Leaders:  1,
+				Bytes:    400,
+			},
+			{
+				BrokerID: 3,
+				Rack:     "rack3",
+				Replicas: 3,
+				Leaders:  1,
+				Bytes:    100,
+			},
+		},
+		balance.Brokers,
+	)
+	assert.Equal(t, int64(3), balance.ReplicaSkew.Min)
+	assert.Equal(t, int64(7), balance.ReplicaSkew.Max)
+	assert.InDelta(t, 16.0/3.0, balance.ReplicaSkew.Mean, 0.001)
+	assert.InDelta(t, 100.0*(16.0/3.0)/7.0, balance.ReplicaSkew.Score(), 0.001)
+	assert.InDelta(t, (6.0-8.0/3.0)/(8.0/3.0), balance.LeaderSkew.MaxSkew(), 0.001)
+	assert.Equal(t, int64(100), balance.BytesSkew.Min)
+	assert.InDelta(t, balance.LeaderSkew.Score(), balance.Score(), 0.001)
+	assert.Equal(
+		t,
+		[]TopicImbalance{
+			{
+				Topic:         "topic-c",
+				ExtraReplicas: 2,
+				ExtraLeaders:  2,
+				ExtraBytes:    200,
+			},
+			{
+				Topic:        "topic-b",
+				ExtraLeaders: 1,
+			},
+		},
+		balance.TopicImbalances,
+	)
+
+	noSizesBalance := GetClusterBalance(brokers, topics[:2], nil)
+	assert.False(t, noSizesBalance.HasSizes)
+	assert.Equal(t, int64(0), noSizesBalance.Brokers[0].Bytes)
+	assert.Equal(t, BalanceSkew{}, noSizesBalance.BytesSkew)
+	assert.Equal(
+		t,
+		[]TopicImbalance{
+			{
+				Topic:        "topic-b",
+				ExtraLeaders: 1,
+			},
+		},
+		noSizesBalance.TopicImbalances,
+	)
+}
+
+func TestGetClusterBalanceBrokerWeights(t *testing.T) {
+	brokers := []BrokerInfo{
+		{
+			ID:     1,
+			Rack:   "rack1",
+			Weight: 2.0,
+		},
+		{
+			ID:   2,
+			Rack: "rack2",
+		},
+		{
+			ID:   3,
+			Rack: "rack3",
+		},
+	}
+
+	partitions := []PartitionInfo{}
+	for p, broker := range []int{1, 1, 1, 1, 2, 2, 3, 3} {
+		partitions = append(
+			partitions,
+			PartitionInfo{
+				ID:       p,
+				Leader:   broker,
+				Replicas: []int{broker},
+			},
+		)
+	}
+	topics := []TopicInfo{
+		{
+			Name:       "topic-a",
+			Partitions: partitions,
+		},
+	}
+
+	// Broker 1 has twice the weight of the others, so it should have twice the replicas
+	balance := GetClusterBalance(brokers, topics, nil)
+	assert.Equal(t, 4, balance.Brokers[0].Replicas)
+	assert.Equal(t, balance.ReplicaSkew.Min, balance.ReplicaSkew.Max)
+	assert.InDelta(t, 100.0, balance.Score(), 0.001)
+	assert.Equal(t, []TopicImbalance{}, balance.TopicImbalances)
+
+	brokers[0].Weight = 0
+	unweightedBalance := GetClusterBalance(brokers, topics, nil)
+	assert.Equal(t, int64(2), unweightedBalance.ReplicaSkew.Min)
+	assert.Equal(t, int64(4), unweightedBalance.ReplicaSkew.Max)
+	assert.Equal(
+		t,
+		[]TopicImbalance{
+			{
+				Topic:         "topic-a",
+				ExtraReplicas: 1,
+				ExtraLeaders:  1,
+			},
+		},
+		unweightedBalance.TopicImbalances,
+	)
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/zk"
-	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -45,6 +45,13 @@ type BrokerAdminClientConfig struct {
 	ConnectorConfig
 	ReadOnly          bool
 	ExpectedClusterID string
+
+	// BrokerRacks is an optional mapping from broker ID to rack that overrides the racks
+	// reported by the brokers.
+	BrokerRacks map[int]string
+
+	// BrokerWeights is an optional mapping from broker ID to capacity weight.
+	BrokerWeights map[int]float64
 }
 
 // NewBrokerAdminClient constructs a new BrokerAdminClient instance.
@@ -132,6 +139,15 @@ func (c *BrokerAdminClient) GetClusterID(ctx context.Context) (string, error) {
 	return resp.ClusterID, nil
 }
 
+// GetControllerID gets the ID of the broker that's currently the cluster controller.
+func (c *BrokerAdminClient) GetControllerID(ctx context.Context) (int, error) {
+	resp, err := c.getMetadata(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Controller.ID, nil
+}
+
 // GetBrokers gets information about all brokers in the cluster.
 func (c *BrokerAdminClient) GetBrokers(ctx context.Context, ids []int) (
 	[]BrokerInfo,
@@ -214,9 +230,132 @@ func (c *BrokerAdminClient) GetBrokers(ctx context.Context, ids []int) (
 		brokerInfos[index].Config = config
 	}
 
+	OverrideBrokerRacks(brokerInfos, c.config.BrokerRacks)
+	OverrideBrokerWeights(brokerInfos, c.config.BrokerWeights)
+
 	return brokerInfos, nil
 }
 
+// GetBrokerConfigs gets the full configuration, including defaults, of the argument brokers.
+func (c *BrokerAdminClient) GetBrokerConfigs(
+	ctx context.Context,
+	ids []int,
+) ([]BrokerConfigs, error) {
+	if len(ids) == 0 {
+		var err error
+		ids, err = c.GetBrokerIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return describeBrokerConfigs(ctx, c.client, ids)
+}
+
+// GetReplicaLogDirs gets the on-disk details, including the sizes, of the replicas of the
+// argument topics via the DescribeLogDirs API.
+func (c *BrokerAdminClient) GetReplicaLogDirs(
+	ctx context.Context,
+	topics []string,
+	ids []int,
+) ([]ReplicaLogDirInfo, error) {
+	if len(ids) == 0 {
+		var err error
+		ids, err = c.GetBrokerIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return describeReplicaLogDirs(ctx, c.client, topics, ids)
+}
+
+// GetLogDirs gets the details of each log dir on the argument brokers via the DescribeLogDirs
+// API.
+func (c *BrokerAdminClient) GetLogDirs(ctx context.Context, ids []int) ([]LogDirInfo, error) {
+	if len(ids) == 0 {
+		var err error
+		ids, err = c.GetBrokerIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return describeLogDirs(ctx, c.client, nil, ids)
+}
+
+// GetPartitionReassignments gets the in-progress partition reassignments in the cluster via
+// the ListPartitionReassignments API.
+func (c *BrokerAdminClient) GetPartitionReassignments(
+	ctx context.Context,
+	topic string,
+) ([]PartitionReassignment, error) {
+	return listPartitionReassignments(ctx, c.client, topic)
+}
+
+// GetACLs gets the ACLs in the cluster that match the argument filter.
+func (c *BrokerAdminClient) GetACLs(
+	ctx context.Context,
+	filter kafka.ACLFilter,
+) ([]ACLInfo, error) {
+	return describeACLs(ctx, c.client, filter)
+}
+
+// CreateACLs creates the argument ACLs in the cluster.
+func (c *BrokerAdminClient) CreateACLs(ctx context.Context, acls []ACLInfo) error {
+	if c.config.ReadOnly {
+		return errors.New("Cannot create ACLs in read-only mode")
+	}
+
+	return createACLs(ctx, c.client, acls)
+}
+
+// DeleteACLs deletes the ACLs in the cluster that match the argument filter.
+func (c *BrokerAdminClient) DeleteACLs(
+	ctx context.Context,
+	filter kafka.ACLFilter,
+) ([]ACLInfo, error) {
+	if c.config.ReadOnly {
+		return nil, errors.New("Cannot delete ACLs in read-only mode")
+	}
+
+	return deleteACLs(ctx, c.client, filter)
+}
+
+// GetQuotas gets all of the user and client ID quotas in the cluster.
+func (c *BrokerAdminClient) GetQuotas(ctx context.Context) ([]QuotaInfo, error) {
+	return describeQuotas(ctx, c.client)
+}
+
+// UpdateQuotas applies the argument quota updates to the cluster.
+func (c *BrokerAdminClient) UpdateQuotas(ctx context.Context, updates []QuotaUpdate) error {
+	if c.config.ReadOnly {
+		return errors.New("Cannot update quotas in read-only mode")
+	}
+
+	return alterQuotas(ctx, c.client, updates)
+}
+
+// GetScramCredentials gets the SCRAM credentials of the argument users.
+func (c *BrokerAdminClient) GetScramCredentials(
+	ctx context.Context,
+	users []string,
+) ([]ScramCredentialInfo, error) {
+	return describeScramCredentials(ctx, c.client, users)
+}
+
+// UpsertScramCredentials creates or replaces the argument SCRAM credentials.
+func (c *BrokerAdminClient) UpsertScramCredentials(
+	ctx context.Context,
+	upserts []ScramCredentialUpsert,
+) error {
+	if c.config.ReadOnly {
+		return errors.New("Cannot update SCRAM credentials in read-only mode")
+	}
+
+	return alterScramCredentials(ctx, c.client, upserts)
+}
+
 // GetBrokerIDs get the IDs of all brokers in the cluster.
 func (c *BrokerAdminClient) GetBrokerIDs(ctx context.Context) ([]int, error) {
 	resp, err := c.getMetadata(ctx, nil)
@@ -466,6 +605,25 @@ func (c *BrokerAdminClient) CreateTopic(
 	return err
 }
 
+// DeleteTopic deletes a topic from the cluster.
+func (c *BrokerAdminClient) DeleteTopic(ctx context.Context, name string) error {
+	if c.config.ReadOnly {
+		return errors.New("Cannot delete topic in read-only mode")
+	}
+
+	req := kafka.DeleteTopicsRequest{
+		Topics: []string{name},
+	}
+	log.Debugf("DeleteTopics request: %+v", req)
+
+	resp, err := c.client.DeleteTopics(ctx, &req)
+	log.Debugf("DeleteTopics response: %+v (%+v)", resp, err)
+	if err != nil {
+		return err
+	}
+	return topicDeletionError(resp.Errors[name])
+}
+
 // AssignPartitions sets the replica broker IDs for one or more partitions in a topic.
 func (c *BrokerAdminClient) AssignPartitions(
 	ctx context.Context,
@@ -497,6 +655,22 @@ func (c *BrokerAdminClient) AssignPartitions(
 	return err
 }
 
+// CancelPartitionReassignments cancels the argument in-progress partition reassignments.
+// The partitions revert to their replicas from before each reassignment started.
+func (c *BrokerAdminClient) CancelPartitionReassignments(
+	ctx context.Context,
+	reassignments []PartitionReassignment,
+) error {
+	if c.config.ReadOnly {
+		return errors.New("Cannot cancel partition reassignments in read-only mode")
+	}
+	if len(reassignments) == 0 {
+		return nil
+	}
+
+	return cancelPartitionReassignments(ctx, c.client, reassignments)
+}
+
 // AddPartitions extends a topic by adding one or more new partitions to it.
 func (c *BrokerAdminClient) AddPartitions(
 	ctx context.Context,
@@ -568,6 +742,23 @@ func (c *BrokerAdminClient) RunLeaderElection(
 	return err
 }
 
+// ElectLeaders triggers leader elections of the argument type for one or more partitions,
+// which can be in different topics.
+func (c *BrokerAdminClient) ElectLeaders(
+	ctx context.Context,
+	electionType LeaderElectionType,
+	partitions []PartitionInfo,
+) error {
+	if c.config.ReadOnly {
+		return errors.New("Cannot run leader election in read-only mode")
+	}
+	if len(partitions) == 0 {
+		return nil
+	}
+
+	return electLeaders(ctx, c.client, electionType, partitions)
+}
+
 // AcquireLock acquires a lock that can be used to prevent simultaneous changes to a topic.
 // NOTE: Not implemented for broker-based clients.
 func (c *BrokerAdminClient) AcquireLock(ctx context.Context, path string) (
@@ -654,3 +845,105 @@ func configEntriesToAPIConfigs(
 
 	return apiConfigs
 }
+
+// describeBrokerConfigs gets the configs for the argument brokers via the DescribeConfigs API.
+// It's shared by both client implementations. The results are sorted by broker ID and config
+// name.
+func describeBrokerConfigs(
+	ctx context.Context,
+	client *kafka.Client,
+	ids []int,
+) ([]BrokerConfigs, error) {
+	configRequestResources := []kafka.DescribeConfigRequestResource{}
+	for _, id := range ids {
+		configRequestResources = append(
+			configRequestResources,
+			kafka.DescribeConfigRequestResource{
+				ResourceType: kafka.ResourceTypeBroker,
+				ResourceName: fmt.Sprintf("%d", id),
+			},
+		)
+	}
+
+	configsReq := kafka.DescribeConfigsRequest{
+		Resources: configRequestResources,
+	}
+	log.Debugf("DescribeConfigs request: %+v", configsReq)
+
+	configsResp, err := client.DescribeConfigs(ctx, &configsReq)
+	log.Debugf("DescribeConfigs response: %+v (%+v)", configsResp, err)
+	if err != nil {
+		return nil, err
+	}
+
+	brokerConfigs := []BrokerConfigs{}
+
+	for _, resource := range configsResp.Resources {
+		if resource.Error != nil {
+			return nil, fmt.Errorf(
+				"Error getting configs for broker %s: %+v",
+				resource.ResourceName,
+				resource.Error,
+			)
+		}
+
+		brokerID, err := strconv.Atoi(resource.ResourceName)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := []BrokerConfigEntry{}
+		for _, configEntry := range resource.ConfigEntries {
+			entry := BrokerConfigEntry{
+				Name:      configEntry.ConfigName,
+				Value:     configEntry.ConfigValue,
+				Source:    configSourceName(configEntry.ConfigSource, configEntry.IsDefault),
+				ReadOnly:  configEntry.ReadOnly,
+				Sensitive: configEntry.IsSensitive,
+			}
+			if entry.Value == "" && entry.Sensitive {
+				entry.Value = sensitivePlaceholder
+			}
+			entries = append(entries, entry)
+		}
+
+		sort.Slice(entries, func(a, b int) bool {
+			return entries[a].Name < entries[b].Name
+		})
+
+		brokerConfigs = append(
+			brokerConfigs,
+			BrokerConfigs{
+				BrokerID: brokerID,
+				Entries:  entries,
+			},
+		)
+	}
+
+	sort.Slice(brokerConfigs, func(a, b int) bool {
+		return brokerConfigs[a].BrokerID < brokerConfigs[b].BrokerID
+	})
+
+	return brokerConfigs, nil
+}
+
+func configSourceName(configSource int8, isDefault bool) string {
+	switch configSource {
+	case configSourceDynamicBrokerConfig:
+		return ConfigSourceDynamicBroker
+	case configSourceDynamicDefaultBrokerConfig:
+		return ConfigSourceDynamicDefaultBroker
+	case configSourceStaticBrokerConfig:
+		return ConfigSourceStaticBroker
+	case configSourceDefaultConfig:
+		return ConfigSourceDefault
+	case configSourceDynamicBrokerLoggerConfig:
+		return ConfigSourceDynamicBrokerLogger
+	}
+
+	// Older brokers don't return config sources, just whether each config is a default
+	if isDefault {
+		return ConfigSourceDefault
+	}
+	return ConfigSourceUnknown
+}
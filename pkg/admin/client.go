@@ -2,11 +2,18 @@ package admin
 
 import (
 	"context"
+	"errors"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/zk"
 )
 
+// ErrTopicDeletionDisabled is returned by DeleteTopic if the brokers have topic deletion
+// disabled via delete.topic.enable=false.
+var ErrTopicDeletionDisabled = errors.New(
+	"Topic deletion is disabled on the brokers (delete.topic.enable=false)",
+)
+
 // Client is an interface for interacting with a cluster for administrative tasks.
 type Client interface {
 	// GetClusterID gets the ID of the cluster.
@@ -15,9 +22,53 @@ type Client interface {
 	// GetBrokers gets information about all brokers in the cluster.
 	GetBrokers(ctx context.Context, ids []int) ([]BrokerInfo, error)
 
+	// GetBrokerConfigs gets the full configuration, including defaults, of the argument brokers.
+	// If ids is empty, the configs of all brokers are returned.
+	GetBrokerConfigs(ctx context.Context, ids []int) ([]BrokerConfigs, error)
+
+	// GetReplicaLogDirs gets the on-disk details of the replicas of the argument topics on the
+	// argument brokers. If topics is empty, the replicas of all topics are returned, and if ids
+	// is empty, all brokers are queried.
+	GetReplicaLogDirs(
+		ctx context.Context,
+		topics []string,
+		ids []int,
+	) ([]ReplicaLogDirInfo, error)
+
+	// GetLogDirs gets the details of each log dir on the argument brokers, including the
+	// replicas of all topics that are stored in it. If ids is empty, all brokers are queried.
+	GetLogDirs(ctx context.Context, ids []int) ([]LogDirInfo, error)
+
+	// GetControllerID gets the ID of the broker that's currently the cluster controller. It
+	// returns -1 if there's no active controller.
+	GetControllerID(ctx context.Context) (int, error)
+
 	// GetBrokerIDs get the IDs of all brokers in the cluster.
 	GetBrokerIDs(ctx context.Context) ([]int, error)
 
+	// GetACLs gets the ACLs in the cluster that match the argument filter.
+	GetACLs(ctx context.Context, filter kafka.ACLFilter) ([]ACLInfo, error)
+
+	// CreateACLs creates the argument ACLs in the cluster.
+	CreateACLs(ctx context.Context, acls []ACLInfo) error
+
+	// DeleteACLs deletes the ACLs in the cluster that match the argument filter. It returns
+	// the ACLs that were deleted.
+	DeleteACLs(ctx context.Context, filter kafka.ACLFilter) ([]ACLInfo, error)
+
+	// GetQuotas gets all of the user and client ID quotas in the cluster.
+	GetQuotas(ctx context.Context) ([]QuotaInfo, error)
+
+	// UpdateQuotas applies the argument quota updates to the cluster.
+	UpdateQuotas(ctx context.Context, updates []QuotaUpdate) error
+
+	// GetScramCredentials gets the SCRAM credentials of the argument users. Users that don't
+	// have any credentials are omitted.
+	GetScramCredentials(ctx context.Context, users []string) ([]ScramCredentialInfo, error)
+
+	// UpsertScramCredentials creates or replaces the argument SCRAM credentials.
+	UpsertScramCredentials(ctx context.Context, upserts []ScramCredentialUpsert) error
+
 	// GetConnector gets the Connector instance for this cluster.
 	GetConnector() *Connector
 
@@ -38,6 +89,13 @@ type Client interface {
 		detailed bool,
 	) (TopicInfo, error)
 
+	// GetPartitionReassignments gets the in-progress partition reassignments in the cluster.
+	// If topic is empty, the reassignments for all topics are returned.
+	GetPartitionReassignments(
+		ctx context.Context,
+		topic string,
+	) ([]PartitionReassignment, error)
+
 	// UpdateTopicConfig updates the configuration for the argument topic. It returns the config
 	// keys that were updated.
 	UpdateTopicConfig(
@@ -62,6 +120,10 @@ type Client interface {
 		config kafka.TopicConfig,
 	) error
 
+	// DeleteTopic deletes a topic from the cluster. ErrTopicDeletionDisabled is returned if
+	// topic deletion is disabled on the brokers.
+	DeleteTopic(ctx context.Context, name string) error
+
 	// AssignPartitions sets the replica broker IDs for one or more partitions in a topic.
 	AssignPartitions(
 		ctx context.Context,
@@ -69,6 +131,12 @@ type Client interface {
 		assignments []PartitionAssignment,
 	) error
 
+	// CancelPartitionReassignments cancels the argument in-progress partition reassignments.
+	CancelPartitionReassignments(
+		ctx context.Context,
+		reassignments []PartitionReassignment,
+	) error
+
 	// AddPartitions extends a topic by adding one or more new partitions to it.
 	AddPartitions(
 		ctx context.Context,
@@ -83,6 +151,14 @@ type Client interface {
 		partitions []int,
 	) error
 
+	// ElectLeaders triggers leader elections of the argument type for one or more
+	// partitions, which can be in different topics.
+	ElectLeaders(
+		ctx context.Context,
+		electionType LeaderElectionType,
+		partitions []PartitionInfo,
+	) error
+
 	// AcquireLock acquires a lock that can be used to prevent simultaneous changes to a topic.
 	AcquireLock(ctx context.Context, path string) (zk.Lock, error)
 
@@ -95,3 +171,12 @@ type Client interface {
 	// Close closes the client.
 	Close() error
 }
+
+// topicDeletionError converts the argument per-topic error from a DeleteTopics response into
+// the error returned by DeleteTopic.
+func topicDeletionError(err error) error {
+	if err == kafka.TopicDeletionDisabled {
+		return ErrTopicDeletionDisabled
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// CompatFeature is a topicctl feature that depends on a broker API being available.
+type CompatFeature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// API and MinVersion are the broker API, and its minimum version, that the feature needs.
+	API        string `json:"api"`
+	MinVersion int    `json:"minVersion"`
+
+	// KafkaVersion is the first Kafka version that supports the API.
+	KafkaVersion string `json:"kafkaVersion"`
+}
+
+// CompatFeatures are the features that are checked by compat reports.
+var CompatFeatures = []CompatFeature{
+	{
+		Name:         "Broker reads",
+		Description:  "Reading brokers, topics, and configs without zookeeper",
+		API:          "DescribeConfigs",
+		MinVersion:   0,
+		KafkaVersion: "2.0",
+	},
+	{
+		Name:         "ACLs",
+		Description:  "get acls, create acl, delete acl",
+		API:          "DescribeAcls",
+		MinVersion:   0,
+		KafkaVersion: "0.11",
+	},
+	{
+		Name:         "Log dirs",
+		Description:  "Replica sizes in get storage, get partitions, and get reassignments",
+		API:          "DescribeLogDirs",
+		MinVersion:   0,
+		KafkaVersion: "1.0",
+	},
+	{
+		Name:         "Incremental configs",
+		Description:  "Updating individual topic and broker configs without zookeeper",
+		API:          "IncrementalAlterConfigs",
+		MinVersion:   0,
+		KafkaVersion: "2.3",
+	},
+	{
+		Name:         "API reassignments",
+		Description:  "Applying partition changes and cancelling reassignments without zookeeper",
+		API:          "AlterPartitionReassignments",
+		MinVersion:   0,
+		KafkaVersion: "2.4",
+	},
+	{
+		Name:         "Reassignment listing",
+		Description:  "Listing in-progress reassignments with their adding and removing replicas",
+		API:          "ListPartitionReassignments",
+		MinVersion:   0,
+		KafkaVersion: "2.4",
+	},
+	{
+		Name:         "Unclean elections",
+		Description:  "elect leaders --unclean",
+		API:          "ElectLeaders",
+		MinVersion:   1,
+		KafkaVersion: "2.4",
+	},
+	{
+		Name:         "Quotas API",
+		Description:  "get quotas, apply-quotas, and non-sensitive dynamic broker configs",
+		API:          "DescribeClientQuotas",
+		MinVersion:   0,
+		KafkaVersion: "2.6",
+	},
+	{
+		Name:         "SCRAM credentials API",
+		Description:  "apply-users",
+		API:          "DescribeUserScramCredentials",
+		MinVersion:   0,
+		KafkaVersion: "2.7",
+	},
+	{
+		Name:         "Topic IDs",
+		Description:  "Topic IDs in metadata responses",
+		API:          "Metadata",
+		MinVersion:   10,
+		KafkaVersion: "2.8",
+	},
+}
+
+// BrokerAPIVersions contains the maximum supported version of each API on a single broker.
+type BrokerAPIVersions struct {
+	BrokerID    int            `json:"brokerID"`
+	MaxVersions map[string]int `json:"maxVersions"`
+
+	// Error is set if the API versions couldn't be fetched from the broker.
+	Error string `json:"error,omitempty"`
+}
+
+// CompatResult is the result of checking a single feature against all of the brokers in a
+// cluster.
+type CompatResult struct {
+	CompatFeature
+
+	// Supported is set if all of the brokers that could be reached support the feature.
+	Supported bool `json:"supported"`
+
+	// UnsupportedBrokers are the IDs of the brokers that don't support the feature.
+	UnsupportedBrokers []int `json:"unsupportedBrokers"`
+}
+
+// GetBrokerAPIVersions fetches the supported API versions from each of the argument brokers.
+// Errors from individual brokers are recorded in the results instead of being returned, since
+// they're often the reason for running the check.
+func GetBrokerAPIVersions(
+	ctx context.Context,
+	client *kafka.Client,
+	brokers []BrokerInfo,
+) []BrokerAPIVersions {
+	results := []BrokerAPIVersions{}
+
+	for _, broker := range brokers {
+		result := BrokerAPIVersions{
+			BrokerID:    broker.ID,
+			MaxVersions: map[string]int{},
+		}
+
+		addr := net.JoinHostPort(broker.Host, strconv.Itoa(int(broker.Port)))
+		resp, err := client.ApiVersions(
+			ctx,
+			&kafka.ApiVersionsRequest{
+				Addr: kafka.TCP(addr),
+			},
+		)
+		log.Debugf("API versions response from broker %d: %+v (%+v)", broker.ID, resp, err)
+
+		if err == nil && resp.Error != nil {
+			err = resp.Error
+		}
+		if err != nil {
+			result.Error = fmt.Sprintf("%+v", err)
+		} else {
+			for _, apiKey := range resp.ApiKeys {
+				result.MaxVersions[apiKey.ApiName] = apiKey.MaxVersion
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		return results[a].BrokerID < results[b].BrokerID
+	})
+
+	return results
+}
+
+// GetCompatResults checks each of the CompatFeatures against the argument broker API
+// versions. Brokers whose versions couldn't be fetched are skipped; a feature isn't supported
+// if none of the brokers could be checked.
+func GetCompatResults(brokerVersions []BrokerAPIVersions) []CompatResult {
+	results := []CompatResult{}
+
+	for _, feature := range CompatFeatures {
+		result := CompatResult{
+			CompatFeature:      feature,
+			UnsupportedBrokers: []int{},
+		}
+		checked := 0
+
+		for _, broker := range brokerVersions {
+			if broker.Error != "" {
+				continue
+			}
+			checked++
+
+			if maxVersion, ok := broker.MaxVersions[feature.API]; !ok ||
+				maxVersion < feature.MinVersion {
+				result.UnsupportedBrokers = append(result.UnsupportedBrokers, broker.BrokerID)
+			}
+		}
+
+		result.Supported = checked > 0 && len(result.UnsupportedBrokers) == 0
+		results = append(results, result)
+	}
+
+	return results
+}
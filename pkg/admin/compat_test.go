@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCompatResults(t *testing.T) {
+	newVersions := map[string]int{}
+	for _, feature := range CompatFeatures {
+		newVersions[feature.API] = feature.MinVersion
+	}
+
+	brokerVersions := []BrokerAPIVersions{
+		{
+			BrokerID:    1,
+			MaxVersions: newVersions,
+		},
+		{
+			BrokerID: 2,
+			MaxVersions: map[string]int{
+				"DescribeConfigs": 2,
+				"ElectLeaders":    0,
+				"Metadata":        9,
+			},
+		},
+		{
+			BrokerID: 3,
+			Error:    "connection refused",
+		},
+	}
+
+	supported := map[string]bool{}
+	unsupportedBrokers := map[string][]int{}
+	for _, result := range GetCompatResults(brokerVersions) {
+		supported[result.Name] = result.Supported
+		unsupportedBrokers[result.Name] = result.UnsupportedBrokers
+	}
+
+	assert.True(t, supported["Broker reads"])
+	assert.Equal(t, []int{}, unsupportedBrokers["Broker reads"])
+	assert.False(t, supported["Unclean elections"])
+	assert.Equal(t, []int{2}, unsupportedBrokers["Unclean elections"])
+	assert.False(t, supported["Topic IDs"])
+	assert.Equal(t, []int{2}, unsupportedBrokers["Topic IDs"])
+
+	// Nothing is supported if no brokers could be checked
+	for _, result := range GetCompatResults(brokerVersions[2:]) {
+		assert.False(t, result.Supported)
+	}
+
+	assert.True(t, GetCompatResults(brokerVersions[:1])[len(CompatFeatures)-1].Supported)
+}
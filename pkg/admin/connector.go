@@ -16,7 +16,6 @@ import (
 	"github.com/segmentio/kafka-go/sasl/aws_msk_iam"
 	"github.com/segmentio/kafka-go/sasl/plain"
 	"github.com/segmentio/kafka-go/sasl/scram"
-	log "github.com/sirupsen/logrus"
 )
 
 // SASLMechanism is the name of a SASL mechanism that will be used for client authentication.
@@ -24,6 +23,8 @@ type SASLMechanism string
 
 const (
 	SASLMechanismAWSMSKIAM   SASLMechanism = "aws-msk-iam"
+	SASLMechanismGSSAPI      SASLMechanism = "gssapi"
+	SASLMechanismOAuthBearer SASLMechanism = "oauthbearer"
 	SASLMechanismPlain       SASLMechanism = "plain"
 	SASLMechanismScramSHA256 SASLMechanism = "scram-sha-256"
 	SASLMechanismScramSHA512 SASLMechanism = "scram-sha-512"
@@ -52,6 +53,13 @@ type SASLConfig struct {
 	Mechanism SASLMechanism
 	Username  string
 	Password  string
+
+	// GSSAPI is only used if the mechanism is GSSAPI.
+	GSSAPI GSSAPIConfig
+
+	// OAuth is only used if the mechanism is OAUTHBEARER. The client ID and secret are the
+	// username and password above.
+	OAuth OAuthConfig
 }
 
 // Connector is a wrapper around the low-level, kafka-go dialer and client.
@@ -82,6 +90,24 @@ func NewConnector(config ConnectorConfig) (*Connector, error) {
 				Signer: signer,
 				Region: region,
 			}
+		case SASLMechanismGSSAPI:
+			mechanismClient, err = newGSSAPIMechanism(config.SASL.GSSAPI, config.SASL.Password)
+			if err != nil {
+				return nil, err
+			}
+		case SASLMechanismOAuthBearer:
+			oauthConfig := config.SASL.OAuth
+			oauthConfig.ClientID = config.SASL.Username
+			oauthConfig.ClientSecret = config.SASL.Password
+
+			provider, err := NewOAuthTokenProvider(oauthConfig)
+			if err != nil {
+				return nil, err
+			}
+			mechanismClient = &oauthBearerMechanism{
+				provider:   provider,
+				extensions: oauthConfig.Extensions,
+			}
 		case SASLMechanismPlain:
 			mechanismClient = plain.Mechanism{
 				Username: config.SASL.Username,
@@ -113,42 +139,9 @@ func NewConnector(config ConnectorConfig) (*Connector, error) {
 	if !config.TLS.Enabled {
 		connector.Dialer = kafka.DefaultDialer
 	} else {
-		var certs []tls.Certificate
-		var caCertPool *x509.CertPool
-
-		if config.TLS.CertPath != "" && config.TLS.KeyPath != "" {
-			log.Debugf(
-				"Loading key pair from %s and %s",
-				config.TLS.CertPath,
-				config.TLS.KeyPath,
-			)
-			cert, err := tls.LoadX509KeyPair(config.TLS.CertPath, config.TLS.KeyPath)
-			if err != nil {
-				return nil, err
-			}
-			certs = append(certs, cert)
-		}
-
-		if config.TLS.CACertPath != "" {
-			log.Debugf("Adding CA certs from %s", config.TLS.CACertPath)
-			caCertPool = x509.NewCertPool()
-			caCertContents, err := ioutil.ReadFile(config.TLS.CACertPath)
-			if err != nil {
-				return nil, err
-			}
-			if ok := caCertPool.AppendCertsFromPEM(caCertContents); !ok {
-				return nil, fmt.Errorf(
-					"Could not append CA certs from %s",
-					config.TLS.CACertPath,
-				)
-			}
-		}
-
-		tlsConfig = &tls.Config{
-			Certificates:       certs,
-			RootCAs:            caCertPool,
-			InsecureSkipVerify: config.TLS.SkipVerify,
-			ServerName:         config.TLS.ServerName,
+		tlsConfig, err = newTLSConfig(config.TLS)
+		if err != nil {
+			return nil, err
 		}
 		connector.Dialer = &kafka.Dialer{
 			SASLMechanism: mechanismClient,
@@ -174,6 +167,48 @@ func NewConnector(config ConnectorConfig) (*Connector, error) {
 	return connector, nil
 }
 
+// newTLSConfig builds a tls.Config from the argument config, loading the client key pair and CA
+// certs if they're set.
+func newTLSConfig(config TLSConfig) (*tls.Config, error) {
+	var certs []tls.Certificate
+	var caCertPool *x509.CertPool
+
+	if config.CertPath != "" && config.KeyPath != "" {
+		log.Debugf(
+			"Loading key pair from %s and %s",
+			config.CertPath,
+			config.KeyPath,
+		)
+		cert, err := tls.LoadX509KeyPair(config.CertPath, config.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if config.CACertPath != "" {
+		log.Debugf("Adding CA certs from %s", config.CACertPath)
+		caCertPool = x509.NewCertPool()
+		caCertContents, err := ioutil.ReadFile(config.CACertPath)
+		if err != nil {
+			return nil, err
+		}
+		if ok := caCertPool.AppendCertsFromPEM(caCertContents); !ok {
+			return nil, fmt.Errorf(
+				"Could not append CA certs from %s",
+				config.CACertPath,
+			)
+		}
+	}
+
+	return &tls.Config{
+		Certificates:       certs,
+		RootCAs:            caCertPool,
+		InsecureSkipVerify: config.SkipVerify,
+		ServerName:         config.ServerName,
+	}, nil
+}
+
 // SASLNameToMechanism converts the argument SASL mechanism name string to a valid instance of
 // the SASLMechanism enum.
 func SASLNameToMechanism(name string) (SASLMechanism, error) {
@@ -182,13 +217,15 @@ func SASLNameToMechanism(name string) (SASLMechanism, error) {
 
 	switch mechanism {
 	case SASLMechanismAWSMSKIAM,
+		SASLMechanismGSSAPI,
+		SASLMechanismOAuthBearer,
 		SASLMechanismPlain,
 		SASLMechanismScramSHA256,
 		SASLMechanismScramSHA512:
 		return mechanism, nil
 	default:
 		return mechanism, fmt.Errorf(
-			"SASL mechanism '%s' is not valid; choices are AWS-MSK-IAM, PLAIN, SCRAM-SHA-256, and SCRAM-SHA-512",
+			"SASL mechanism '%s' is not valid; choices are AWS-MSK-IAM, GSSAPI, OAUTHBEARER, PLAIN, SCRAM-SHA-256, and SCRAM-SHA-512",
 			mechanism,
 		)
 	}
@@ -0,0 +1,137 @@
+package admin
+
+import "sort"
+
+// BrokerDrainPlan summarizes the replicas that need to be moved off of a broker before it can
+// be decommissioned.
+type BrokerDrainPlan struct {
+	BrokerID int              `json:"brokerID"`
+	Topics   []TopicDrainPlan `json:"topics"`
+}
+
+// TopicDrainPlan summarizes the replicas of a single topic that are on a broker being drained.
+type TopicDrainPlan struct {
+	Topic string `json:"topic"`
+
+	// Managed is set if the topic has a config, i.e. if its replicas can be moved by topicctl.
+	Managed bool `json:"managed"`
+
+	Replicas int `json:"replicas"`
+	Leaders  int `json:"leaders"`
+
+	// Size is the total size of the topic's replicas on the broker, or -1 if unknown.
+	Size int64 `json:"size"`
+}
+
+// GetBrokerDrainPlan determines which of the argument topics have replicas on the argument
+// broker. The replica sizes are taken from replicaLogDirs, which can be nil if they aren't
+// known. The topics in the result are sorted by name.
+func GetBrokerDrainPlan(
+	brokerID int,
+	topics []TopicInfo,
+	replicaLogDirs []ReplicaLogDirInfo,
+	managedTopics map[string]struct{},
+) BrokerDrainPlan {
+	// Map from topic -> partition -> replica size on the broker
+	replicaSizes := map[string]map[int]int64{}
+	for _, replicaLogDir := range replicaLogDirs {
+		if replicaLogDir.BrokerID != brokerID || replicaLogDir.IsFuture {
+			continue
+		}
+		if _, ok := replicaSizes[replicaLogDir.Topic]; !ok {
+			replicaSizes[replicaLogDir.Topic] = map[int]int64{}
+		}
+		replicaSizes[replicaLogDir.Topic][replicaLogDir.Partition] = replicaLogDir.Size
+	}
+
+	plan := BrokerDrainPlan{
+		BrokerID: brokerID,
+		Topics:   []TopicDrainPlan{},
+	}
+
+	for _, topic := range topics {
+		_, managed := managedTopics[topic.Name]
+		topicPlan := TopicDrainPlan{
+			Topic:   topic.Name,
+			Managed: managed,
+		}
+
+		for _, partition := range topic.Partitions {
+			onBroker := false
+			for _, replica := range partition.Replicas {
+				if replica == brokerID {
+					onBroker = true
+					break
+				}
+			}
+			if !onBroker {
+				continue
+			}
+
+			topicPlan.Replicas++
+			if partition.Leader == brokerID {
+				topicPlan.Leaders++
+			}
+
+			size, ok := replicaSizes[topic.Name][partition.ID]
+			if !ok || topicPlan.Size < 0 {
+				topicPlan.Size = -1
+			} else {
+				topicPlan.Size += size
+			}
+		}
+
+		if topicPlan.Replicas > 0 {
+			plan.Topics = append(plan.Topics, topicPlan)
+		}
+	}
+
+	sort.Slice(plan.Topics, func(a, b int) bool {
+		return plan.Topics[a].Topic < plan.Topics[b].Topic
+	})
+
+	return plan
+}
+
+// Replicas returns the total number of replicas on the broker.
+func (b BrokerDrainPlan) Replicas() int {
+	var replicas int
+	for _, topic := range b.Topics {
+		replicas += topic.Replicas
+	}
+	return replicas
+}
+
+// Size returns the total size of the known replica sizes on the broker, along with whether
+// the sizes of all of the replicas are known.
+func (b BrokerDrainPlan) Size() (int64, bool) {
+	var size int64
+	complete := true
+
+	for _, topic := range b.Topics {
+		if topic.Size < 0 {
+			complete = false
+			continue
+		}
+		size += topic.Size
+	}
+
+	return size, complete
+}
+
+// Empty returns whether there are no replicas on the broker.
+func (b BrokerDrainPlan) Empty() bool {
+	return len(b.Topics) == 0
+}
+
+// UnmanagedTopics returns the names of the topics with replicas on the broker that don't have
+// configs and therefore can't be moved by topicctl.
+func (b BrokerDrainPlan) UnmanagedTopics() []string {
+	topics := []string{}
+	for _, topic := range b.Topics {
+		if !topic.Managed {
+			topics = append(topics, topic.Topic)
+		}
+	}
+	return topics
+}
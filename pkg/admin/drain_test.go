@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBrokerDrainPlan(t *testing.T) {
+	topics := []TopicInfo{
+		{
+			Name: "topic2",
+			Partitions: []PartitionInfo{
+				{Topic: "topic2", ID: 0, Leader: 1, Replicas: []int{1, 2}},
+				{Topic: "topic2", ID: 1, Leader: 3, Replicas: []int{3, 1}},
+			},
+		},
+		{
+			Name: "topic1",
+			Partitions: []PartitionInfo{
+				{Topic: "topic1", ID: 0, Leader: 2, Replicas: []int{2, 1}},
+			},
+		},
+		{
+			Name: "topic3",
+			Partitions: []PartitionInfo{
+				{Topic: "topic3", ID: 0, Leader: 2, Replicas: []int{2, 3}},
+			},
+		},
+	}
+	replicaLogDirs := []ReplicaLogDirInfo{
+		{Topic: "topic2", Partition: 0, BrokerID: 1, Size: 100},
+		{Topic: "topic2", Partition: 1, BrokerID: 1, Size: 50},
+		{Topic: "topic2", Partition: 1, BrokerID: 3, Size: 1000},
+		// Future replicas are ignored
+		{Topic: "topic2", Partition: 1, BrokerID: 1, Size: 10, IsFuture: true},
+	}
+
+	plan := GetBrokerDrainPlan(
+		1,
+		topics,
+		replicaLogDirs,
+		map[string]struct{}{"topic2": {}},
+	)
+	assert.Equal(
+		t,
+		BrokerDrainPlan{
+			BrokerID: 1,
+			Topics: []TopicDrainPlan{
+				{
+					Topic:    "topic1",
+					Managed:  false,
+					Replicas: 1,
+					Leaders:  0,
+					Size:     -1,
+				},
+				{
+					Topic:    "topic2",
+					Managed:  true,
+					Replicas: 2,
+					Leaders:  1,
+					Size:     150,
+				},
+			},
+		},
+		plan,
+	)
+	assert.Equal(t, 3, plan.Replicas())
+	size, complete := plan.Size()
+	assert.Equal(t, int64(150), size)
+	assert.False(t, complete)
+	assert.False(t, plan.Empty())
+	assert.Equal(t, []string{"topic1"}, plan.UnmanagedTopics())
+
+	assert.True(t, GetBrokerDrainPlan(4, topics, nil, nil).Empty())
+}
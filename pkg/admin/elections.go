@@ -0,0 +1,236 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol/electleaders"
+)
+
+// LeaderElectionType is a string type that represents the kind of leader election to run.
+type LeaderElectionType string
+
+const (
+	// LeaderElectionTypePreferred moves leadership back to the preferred (i.e., first) replica
+	// of each partition, provided that it's in sync.
+	LeaderElectionTypePreferred LeaderElectionType = "preferred"
+
+	// LeaderElectionTypeUnclean elects a leader for partitions that don't have one, even if
+	// the new leader isn't in sync. This can lose data.
+	LeaderElectionTypeUnclean LeaderElectionType = "unclean"
+)
+
+// ErrUncleanElectionsUnsupported is returned when an unclean leader election is requested in
+// a cluster that doesn't support them.
+var ErrUncleanElectionsUnsupported = errors.New(
+	"Unclean leader elections require Kafka v2.4 or greater",
+)
+
+// electionTypeIDs maps each LeaderElectionType to its ID in the ElectLeaders API.
+var electionTypeIDs = map[LeaderElectionType]int8{
+	LeaderElectionTypePreferred: 0,
+	LeaderElectionTypeUnclean:   1,
+}
+
+// PreferredLeaderElectionPartitions returns the partitions in the argument topics whose
+// leader isn't their preferred replica and whose preferred replica is live and in sync, i.e.
+// the partitions that a preferred leader election would change.
+func PreferredLeaderElectionPartitions(
+	topics []TopicInfo,
+	brokers []BrokerInfo,
+) []PartitionInfo {
+	brokerIDsMap := brokerIDsSet(brokers)
+	partitions := []PartitionInfo{}
+
+	for _, topic := range topics {
+		for _, partition := range topic.WrongLeaderPartitions(nil) {
+			if len(partition.Replicas) == 0 {
+				continue
+			}
+			preferred := partition.Replicas[0]
+			if _, ok := brokerIDsMap[preferred]; !ok {
+				continue
+			}
+
+			for _, replica := range partition.ISR {
+				if replica == preferred {
+					partitions = append(partitions, partition)
+					break
+				}
+			}
+		}
+	}
+
+	sortPartitionInfos(partitions)
+	return partitions
+}
+
+// UncleanLeaderElectionPartitions returns the partitions in the argument topics whose leader
+// isn't one of the argument live brokers but that have at least one live replica, i.e. the
+// partitions that an unclean leader election could bring back online.
+func UncleanLeaderElectionPartitions(
+	topics []TopicInfo,
+	brokers []BrokerInfo,
+) []PartitionInfo {
+	brokerIDsMap := brokerIDsSet(brokers)
+	partitions := []PartitionInfo{}
+
+	for _, topic := range topics {
+		for _, partition := range topic.Partitions {
+			if _, ok := brokerIDsMap[partition.Leader]; ok && partition.Leader >= 0 {
+				continue
+			}
+
+			for _, replica := range partition.Replicas {
+				if _, ok := brokerIDsMap[replica]; ok {
+					partitions = append(partitions, partition)
+					break
+				}
+			}
+		}
+	}
+
+	sortPartitionInfos(partitions)
+	return partitions
+}
+
+// supportsElectionType returns whether the argument cluster supports elections of the
+// argument type via the ElectLeaders API.
+func supportsElectionType(
+	ctx context.Context,
+	client *kafka.Client,
+	electionType LeaderElectionType,
+) (bool, error) {
+	apiVersions, err := client.ApiVersions(ctx, &kafka.ApiVersionsRequest{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, apiKey := range apiVersions.ApiKeys {
+		if apiKey.ApiName != "ElectLeaders" {
+			continue
+		}
+
+		// The election type was only added in v1
+		return electionType == LeaderElectionTypePreferred || apiKey.MaxVersion >= 1, nil
+	}
+
+	return false, nil
+}
+
+// electLeaders runs leader elections of the argument type for the argument partitions via the
+// ElectLeaders API. The high-level kafka-go client only supports preferred elections in a
+// single topic, so the request is built from the protocol types instead. The results for
+// partitions that didn't need an election are ignored.
+func electLeaders(
+	ctx context.Context,
+	client *kafka.Client,
+	electionType LeaderElectionType,
+	partitions []PartitionInfo,
+) error {
+	electionTypeID, ok := electionTypeIDs[electionType]
+	if !ok {
+		return fmt.Errorf("Unrecognized leader election type: %s", electionType)
+	}
+
+	supported, err := supportsElectionType(ctx, client, electionType)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		if electionType == LeaderElectionTypeUnclean {
+			return ErrUncleanElectionsUnsupported
+		}
+		return errors.New("Cluster does not support the ElectLeaders API")
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = kafka.DefaultTransport
+	}
+
+	topicIndices := map[string]int{}
+	req := &electleaders.Request{
+		ElectionType: electionTypeID,
+		TimeoutMs:    int32(defaultTimeout.Milliseconds()),
+	}
+
+	for _, partition := range partitions {
+		index, ok := topicIndices[partition.Topic]
+		if !ok {
+			req.TopicPartitions = append(
+				req.TopicPartitions,
+				electleaders.RequestTopicPartitions{
+					Topic: partition.Topic,
+				},
+			)
+			index = len(req.TopicPartitions) - 1
+			topicIndices[partition.Topic] = index
+		}
+
+		req.TopicPartitions[index].PartitionIDs = append(
+			req.TopicPartitions[index].PartitionIDs,
+			int32(partition.ID),
+		)
+	}
+	log.Debugf("ElectLeaders request: %+v", req)
+
+	resp, err := transport.RoundTrip(ctx, client.Addr, req)
+	log.Debugf("ElectLeaders response: %+v (%+v)", resp, err)
+	if err != nil {
+		return err
+	}
+
+	electResp, ok := resp.(*electleaders.Response)
+	if !ok {
+		return fmt.Errorf("Unexpected ElectLeaders response type: %T", resp)
+	}
+	if electResp.ErrorCode != 0 {
+		return fmt.Errorf(
+			"Error running leader elections: %+v",
+			kafka.Error(electResp.ErrorCode),
+		)
+	}
+
+	var electErr error
+
+	for _, result := range electResp.ReplicaElectionResults {
+		for _, partition := range result.PartitionResults {
+			if partition.ErrorCode == 0 ||
+				kafka.Error(partition.ErrorCode) == kafka.ElectionNotNeeded {
+				continue
+			}
+			electErr = multierror.Append(
+				electErr,
+				fmt.Errorf(
+					"Error electing leader for topic %s, partition %d: %+v",
+					result.Topic,
+					partition.PartitionID,
+					kafka.Error(partition.ErrorCode),
+				),
+			)
+		}
+	}
+
+	return electErr
+}
+
+func brokerIDsSet(brokers []BrokerInfo) map[int]struct{} {
+	brokerIDsMap := map[int]struct{}{}
+	for _, broker := range brokers {
+		brokerIDsMap[broker.ID] = struct{}{}
+	}
+	return brokerIDsMap
+}
+
+func sortPartitionInfos(partitions []PartitionInfo) {
+	sort.Slice(partitions, func(a, b int) bool {
+		return partitions[a].Topic < partitions[b].Topic ||
+			(partitions[a].Topic == partitions[b].Topic &&
+				partitions[a].ID < partitions[b].ID)
+	})
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderElectionPartitions(t *testing.T) {
+	brokers := []BrokerInfo{{ID: 1}, {ID: 2}, {ID: 3}}
+	topics := []TopicInfo{
+		{
+			Name: "topic2",
+			Partitions: []PartitionInfo{
+				// Preferred leader isn't in sync
+				{Topic: "topic2", ID: 0, Leader: 2, Replicas: []int{1, 2}, ISR: []int{2}},
+				{Topic: "topic2", ID: 1, Leader: 2, Replicas: []int{1, 2}, ISR: []int{1, 2}},
+			},
+		},
+		{
+			Name: "topic1",
+			Partitions: []PartitionInfo{
+				{Topic: "topic1", ID: 1, Leader: 3, Replicas: []int{2, 3}, ISR: []int{3, 2}},
+				{Topic: "topic1", ID: 0, Leader: 1, Replicas: []int{1, 2}, ISR: []int{1, 2}},
+				// Leader is down
+				{Topic: "topic1", ID: 2, Leader: -1, Replicas: []int{4, 3}, ISR: []int{4}},
+				// No live replicas
+				{Topic: "topic1", ID: 3, Leader: -1, Replicas: []int{4, 5}, ISR: []int{4}},
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]PartitionInfo{
+			topics[1].Partitions[0],
+			topics[0].Partitions[1],
+		},
+		PreferredLeaderElectionPartitions(topics, brokers),
+	)
+	assert.Equal(
+		t,
+		[]PartitionInfo{
+			topics[1].Partitions[2],
+		},
+		UncleanLeaderElectionPartitions(topics, brokers),
+	)
+	assert.Equal(t, []PartitionInfo{}, UncleanLeaderElectionPartitions(topics[:1], brokers))
+}
@@ -6,11 +6,13 @@ import (
 	"math"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/util"
 )
 
@@ -42,11 +44,15 @@ func FormatBrokers(brokers []BrokerInfo, full bool) string {
 		)
 	}
 
-	headers = append(
-		headers,
-		"Rack",
-		"Timestamp",
-	)
+	hasWeights := HasBrokerWeights(brokers)
+
+	headers = append(headers, "Rack")
+
+	if hasWeights {
+		headers = append(headers, "Weight")
+	}
+
+	headers = append(headers, "Timestamp")
 
 	if full {
 		headers = append(headers, "Config")
@@ -65,6 +71,7 @@ func FormatBrokers(brokers []BrokerInfo, full bool) string {
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -91,11 +98,13 @@ func FormatBrokers(brokers []BrokerInfo, full bool) string {
 			)
 		}
 
-		row = append(
-			row,
-			broker.Rack,
-			broker.Timestamp.UTC().Format(time.RFC3339),
-		)
+		row = append(row, broker.Rack)
+
+		if hasWeights {
+			row = append(row, fmt.Sprintf("%g", broker.CapacityWeight()))
+		}
+
+		row = append(row, broker.Timestamp.UTC().Format(time.RFC3339))
 
 		if full {
 			row = append(row, prettyConfig(broker.Config))
@@ -305,23 +314,42 @@ func FormatBrokerRackReplicas(brokers []BrokerInfo, topics []TopicInfo) string {
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatBrokersPerRack creates a pretty table that shows the number of
-// brokers per rack.
-func FormatBrokersPerRack(brokers []BrokerInfo) string {
+// FormatBrokerLoads creates a pretty table that shows the number of replicas, leaders, and, if
+// known, bytes hosted by each broker, along with how far each is from the cluster mean.
+func FormatBrokerLoads(balance ClusterBalance) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
-	table.SetHeader(
-		[]string{
-			"Rack",
-			"Num Brokers",
-		},
-	)
+
+	headers := []string{
+		"ID",
+		"Rack",
+		"Replicas",
+		"Replicas\nvs Mean",
+		"Leaders",
+		"Leaders\nvs Mean",
+	}
+	if balance.HasSizes {
+		headers = append(
+			headers,
+			"Size",
+			"Size\nvs Mean",
+		)
+	}
+
+	table.SetHeader(headers)
+
 	table.SetAutoWrapText(false)
 	table.SetColumnAlignment(
 		[]int{
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -333,41 +361,47 @@ func FormatBrokersPerRack(brokers []BrokerInfo) string {
 		},
 	)
 
-	brokerCountsPerRack := BrokerCountsPerRack(brokers)
-	racks := DistinctRacks(brokers)
+	for _, load := range balance.Brokers {
+		row := []string{
+			fmt.Sprintf("%d", load.BrokerID),
+			load.Rack,
+			fmt.Sprintf("%d", load.Replicas),
+			meanDiffStr(float64(load.Replicas), balance.ReplicaSkew.Mean),
+			fmt.Sprintf("%d", load.Leaders),
+			meanDiffStr(float64(load.Leaders), balance.LeaderSkew.Mean),
+		}
+		if balance.HasSizes {
+			row = append(
+				row,
+				util.PrettyBytes(load.Bytes),
+				meanDiffStr(float64(load.Bytes), balance.BytesSkew.Mean),
+			)
+		}
 
-	for _, rack := range racks {
-		table.Append(
-			[]string{
-				rack,
-				fmt.Sprintf("%d", brokerCountsPerRack[rack]),
-			},
-		)
+		table.Append(row)
 	}
 
 	table.Render()
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatTopics creates a pretty table that lists the details of the
-// argument topics.
-func FormatTopics(topics []TopicInfo, brokers []BrokerInfo, full bool) string {
+// FormatBalanceSkews creates a pretty table that summarizes the skew of the replicas, leaders,
+// and, if known, bytes across the brokers in a cluster.
+func FormatBalanceSkews(balance ClusterBalance) string {
 	buf := &bytes.Buffer{}
 
-	headers := []string{
-		"Name",
-		"Partitions",
-		"Replication",
-		"Retention\nMins",
-		"Racks\n(min,max)",
-	}
-
-	if full {
-		headers = append(headers, "Config")
-	}
-
 	table := tablewriter.NewWriter(buf)
-	table.SetHeader(headers)
+	table.SetHeader(
+		[]string{
+			"Metric",
+			"Min",
+			"Max",
+			"Mean",
+			"Std Dev",
+			"Max Skew",
+			"Score",
+		},
+	)
 	table.SetAutoWrapText(false)
 	table.SetColumnAlignment(
 		[]int{
@@ -378,7 +412,6 @@ func FormatTopics(topics []TopicInfo, brokers []BrokerInfo, full bool) string {
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
-			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -390,48 +423,58 @@ func FormatTopics(topics []TopicInfo, brokers []BrokerInfo, full bool) string {
 		},
 	)
 
-	brokerRacks := BrokerRacks(brokers)
-
-	for _, topic := range topics {
-		var retentionStr string
-
-		retention := topic.Retention()
-		if retention > 0 {
-			retentionStr = fmt.Sprintf("%d", int(retention.Minutes()))
-		}
-
-		minRacks, maxRacks, _ := topic.RackCounts(brokerRacks)
-
-		row := []string{
-			topic.Name,
-			fmt.Sprintf("%d", len(topic.Partitions)),
-			fmt.Sprintf("%d", topic.MaxReplication()),
-			retentionStr,
-			fmt.Sprintf("(%d,%d)", minRacks, maxRacks),
+	countRow := func(name string, skew BalanceSkew) []string {
+		return []string{
+			name,
+			fmt.Sprintf("%d", skew.Min),
+			fmt.Sprintf("%d", skew.Max),
+			fmt.Sprintf("%.1f", skew.Mean),
+			fmt.Sprintf("%.1f", skew.StdDev),
+			fmt.Sprintf("%.1f%%", 100.0*skew.MaxSkew()),
+			scoreStr(skew.Score()),
 		}
+	}
 
-		if full {
-			row = append(row, prettyConfig(topic.Config))
-		}
+	table.Append(countRow("Replicas", balance.ReplicaSkew))
+	table.Append(countRow("Leaders", balance.LeaderSkew))
 
-		table.Append(row)
+	if balance.HasSizes {
+		table.Append(
+			[]string{
+				"Size",
+				util.PrettyBytes(balance.BytesSkew.Min),
+				util.PrettyBytes(balance.BytesSkew.Max),
+				util.PrettyBytes(int64(balance.BytesSkew.Mean)),
+				util.PrettyBytes(int64(balance.BytesSkew.StdDev)),
+				fmt.Sprintf("%.1f%%", 100.0*balance.BytesSkew.MaxSkew()),
+				scoreStr(balance.BytesSkew.Score()),
+			},
+		)
 	}
 
 	table.Render()
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatTopicPartitions creates a pretty table with information on all of the
-// partitions for a topic.
-func FormatTopicPartitions(partitions []PartitionInfo, brokers []BrokerInfo) string {
+// FormatTopicImbalances creates a pretty table that shows the topics that contribute the most
+// to the imbalance of a cluster. If maxTopics is positive, then only that many topics are
+// included.
+func FormatTopicImbalances(balance ClusterBalance, maxTopics int) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
-	table.SetHeader(
-		[]string{
-			"ID", "Leader", "Replicas", "ISR", "Distinct\nRacks", "Racks", "Status",
-		},
-	)
+
+	headers := []string{
+		"Topic",
+		"Extra\nReplicas",
+		"Extra\nLeaders",
+	}
+	if balance.HasSizes {
+		headers = append(headers, "Extra\nSize")
+	}
+
+	table.SetHeader(headers)
+
 	table.SetAutoWrapText(false)
 	table.SetColumnAlignment(
 		[]int{
@@ -439,8 +482,6 @@ func FormatTopicPartitions(partitions []PartitionInfo, brokers []BrokerInfo) str
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
-			tablewriter.ALIGN_LEFT,
-			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -452,75 +493,56 @@ func FormatTopicPartitions(partitions []PartitionInfo, brokers []BrokerInfo) str
 		},
 	)
 
-	brokerRacks := BrokerRacks(brokers)
-	maxBrokerWidth := maxValueToMaxWidth(len(brokers))
-
-	for _, partition := range partitions {
-		racks, _ := partition.Racks(brokerRacks)
-		rackCount, _ := partition.NumRacks(brokerRacks)
-
-		inSync := util.SameElements(partition.Replicas, partition.ISR)
-
-		var correctLeader bool
-		if len(partition.Replicas) > 0 {
-			correctLeader = partition.Leader == partition.Replicas[0]
-		} else {
-			// No replica information yet
-			correctLeader = false
+	for i, imbalance := range balance.TopicImbalances {
+		if maxTopics > 0 && i >= maxTopics {
+			break
 		}
 
-		var statusPrinter func(f string, a ...interface{}) string
-		if !util.InTerminal() || (inSync && correctLeader) {
-			statusPrinter = fmt.Sprintf
-		} else if !inSync {
-			statusPrinter = color.New(color.FgRed).SprintfFunc()
-		} else if !correctLeader {
-			statusPrinter = color.New(color.FgBlue).SprintfFunc()
+		row := []string{
+			imbalance.Topic,
+			fmt.Sprintf("%d", imbalance.ExtraReplicas),
+			fmt.Sprintf("%d", imbalance.ExtraLeaders),
 		}
-
-		var statusStr string
-		if !inSync {
-			statusStr = "Out-of-sync"
-		} else if !correctLeader {
-			statusStr = "Wrong leader"
-		} else {
-			statusStr = "OK"
+		if balance.HasSizes {
+			row = append(row, util.PrettyBytes(imbalance.ExtraBytes))
 		}
 
-		table.Append(
-			[]string{
-				fmt.Sprintf("%d", partition.ID),
-				fmt.Sprintf("%d", partition.Leader),
-				intSliceString(partition.Replicas, maxBrokerWidth),
-				intSliceString(partition.ISR, maxBrokerWidth),
-				fmt.Sprintf("%d", rackCount),
-				fmt.Sprintf("%+v", racks),
-				statusPrinter("%s", statusStr),
-			},
-		)
+		table.Append(row)
 	}
 
 	table.Render()
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatConfig creates a pretty table with all of the keys and values in a topic or
-// broker config.
-func FormatConfig(configMap map[string]string) string {
+// FormatBrokerStorage creates a pretty table that summarizes the disk usage of each broker.
+func FormatBrokerStorage(storages []BrokerStorage) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
 	table.SetHeader(
 		[]string{
-			"Key",
-			"Value",
+			"ID",
+			"Rack",
+			"Log\nDirs",
+			"Replicas",
+			"Replica\nSize",
+			"Disk\nTotal",
+			"Disk\nUsable",
+			"Disk\nUsed",
 		},
 	)
+
 	table.SetAutoWrapText(false)
 	table.SetColumnAlignment(
 		[]int{
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -532,20 +554,25 @@ func FormatConfig(configMap map[string]string) string {
 		},
 	)
 
-	keys := []string{}
-	for key := range configMap {
-		keys = append(keys, key)
-	}
-
-	sort.Slice(keys, func(a, b int) bool {
-		return keys[a] < keys[b]
-	})
+	for _, storage := range storages {
+		logDirsStr := fmt.Sprintf("%d", storage.LogDirs)
+		if storage.OfflineLogDirs > 0 {
+			logDirsStr = fmt.Sprintf("%s (%d offline)", logDirsStr, storage.OfflineLogDirs)
+			if util.ColorEnabled() {
+				logDirsStr = color.New(color.FgRed).Sprint(logDirsStr)
+			}
+		}
 
-	for _, key := range keys {
 		table.Append(
 			[]string{
-				key,
-				configMap[key],
+				fmt.Sprintf("%d", storage.BrokerID),
+				storage.Rack,
+				logDirsStr,
+				fmt.Sprintf("%d", storage.Replicas),
+				util.PrettyBytes(storage.Bytes),
+				diskBytesStr(storage.TotalBytes, storage.TotalBytes),
+				diskBytesStr(storage.UsableBytes, storage.TotalBytes),
+				diskUsedStr(storage.UsedFraction()),
 			},
 		)
 	}
@@ -554,22 +581,36 @@ func FormatConfig(configMap map[string]string) string {
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatTopicLeadersPerRack creates a pretty table that shows the number
-// of partitions with a leader in each rack.
-func FormatTopicLeadersPerRack(topic TopicInfo, brokers []BrokerInfo) string {
+// FormatLogDirs creates a pretty table that shows the disk usage of each log dir on each
+// broker.
+func FormatLogDirs(logDirs []LogDirInfo) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
 	table.SetHeader(
 		[]string{
-			"Rack", "Num Leaders",
+			"Broker",
+			"Path",
+			"Replicas",
+			"Replica\nSize",
+			"Disk\nTotal",
+			"Disk\nUsable",
+			"Disk\nUsed",
+			"Error",
 		},
 	)
+
 	table.SetAutoWrapText(false)
 	table.SetColumnAlignment(
 		[]int{
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -581,14 +622,29 @@ func FormatTopicLeadersPerRack(topic TopicInfo, brokers []BrokerInfo) string {
 		},
 	)
 
-	leadersPerRack := LeadersPerRack(brokers, topic)
-	racks := DistinctRacks(brokers)
+	for _, logDir := range logDirs {
+		replicas := 0
+		for _, replica := range logDir.Replicas {
+			if !replica.IsFuture {
+				replicas++
+			}
+		}
+
+		errorStr := logDir.Error
+		if errorStr != "" && util.ColorEnabled() {
+			errorStr = color.New(color.FgRed).Sprint(errorStr)
+		}
 
-	for _, rack := range racks {
 		table.Append(
 			[]string{
-				rack,
-				fmt.Sprintf("%d", leadersPerRack[rack]),
+				fmt.Sprintf("%d", logDir.BrokerID),
+				logDir.Path,
+				fmt.Sprintf("%d", replicas),
+				util.PrettyBytes(LogDirReplicaBytes(logDir)),
+				diskBytesStr(logDir.TotalBytes, logDir.TotalBytes),
+				diskBytesStr(logDir.UsableBytes, logDir.TotalBytes),
+				diskUsedStr(LogDirUsedFraction(logDir)),
+				errorStr,
 			},
 		)
 	}
@@ -597,25 +653,23 @@ func FormatTopicLeadersPerRack(topic TopicInfo, brokers []BrokerInfo) string {
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatAssignentDiffs generates a pretty table that shows the before
-// and after states of a partition replica and/or leader update.
-func FormatAssignentDiffs(
-	curr []PartitionAssignment,
-	desired []PartitionAssignment,
-	brokers []BrokerInfo,
-) string {
+// FormatTopicStorage creates a pretty table that shows the disk usage of each topic, along with
+// the broker that has the most bytes of it. If maxTopics is positive, then only that many topics
+// are included.
+func FormatTopicStorage(storages []TopicStorage, maxTopics int) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
 	table.SetHeader(
 		[]string{
-			"Partition",
-			"Curr\nReplicas",
-			"Proposed\nReplicas",
-			"Diff?",
-			"New\nLeader?",
+			"Topic",
+			"Replicas",
+			"Size",
+			"Largest\nBroker",
+			"Largest\nBroker Size",
 		},
 	)
+
 	table.SetAutoWrapText(false)
 	table.SetColumnAlignment(
 		[]int{
@@ -623,6 +677,7 @@ func FormatAssignentDiffs(
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -634,31 +689,33 @@ func FormatAssignentDiffs(
 		},
 	)
 
-	diffs := AssignmentDiffs(curr, desired)
-
-	brokerRacks := BrokerRacks(brokers)
-	maxWidth := maxValueToMaxWidth(len(brokers))
-
-	for _, diff := range diffs {
-		var diffStr string
-		var newLeaderStr string
+	for i, storage := range storages {
+		if maxTopics > 0 && i >= maxTopics {
+			break
+		}
 
-		if !reflect.DeepEqual(diff.Old.Replicas, diff.New.Replicas) {
-			diffStr = "Y"
+		largestBrokerID := -1
+		var largestBytes int64
+		for brokerID, brokerBytes := range storage.BrokerBytes {
+			if largestBrokerID < 0 || brokerBytes > largestBytes ||
+				(brokerBytes == largestBytes && brokerID < largestBrokerID) {
+				largestBrokerID = brokerID
+				largestBytes = brokerBytes
+			}
 		}
-		if len(diff.Old.Replicas) > 0 &&
-			len(diff.New.Replicas) > 0 &&
-			diff.Old.Replicas[0] != diff.New.Replicas[0] {
-			newLeaderStr = "Y"
+
+		var largestBrokerStr string
+		if largestBrokerID >= 0 {
+			largestBrokerStr = fmt.Sprintf("%d", largestBrokerID)
 		}
 
 		table.Append(
 			[]string{
-				fmt.Sprintf("%d", diff.PartitionID),
-				assignmentRacksStr(diff.Old, brokerRacks, maxWidth),
-				assignmentRacksDiffStr(diff.Old, diff.New, brokerRacks, maxWidth),
-				diffStr,
-				newLeaderStr,
+				storage.Topic,
+				fmt.Sprintf("%d", storage.Replicas),
+				util.PrettyBytes(storage.Bytes),
+				largestBrokerStr,
+				util.PrettyBytes(largestBytes),
 			},
 		)
 	}
@@ -667,23 +724,16 @@ func FormatAssignentDiffs(
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatBrokerMaxPartitions generates a pretty table that shows the total number of
-// partitions that each broker is involved in for a diff. It's used to evaluate
-// the potential extra load that could occur on brokers during a migration.
-func FormatBrokerMaxPartitions(
-	curr []PartitionAssignment,
-	desired []PartitionAssignment,
-	brokers []BrokerInfo,
-) string {
+// FormatBrokersPerRack creates a pretty table that shows the number of
+// brokers per rack.
+func FormatBrokersPerRack(brokers []BrokerInfo) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
 	table.SetHeader(
 		[]string{
-			"Broker",
-			"Curr\nPartitions",
-			"Max\nPartitions",
-			"Proposed\nPartitions",
+			"Rack",
+			"Num Brokers",
 		},
 	)
 	table.SetAutoWrapText(false)
@@ -691,8 +741,6 @@ func FormatBrokerMaxPartitions(
 		[]int{
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
-			tablewriter.ALIGN_LEFT,
-			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -704,14 +752,1339 @@ func FormatBrokerMaxPartitions(
 		},
 	)
 
-	startPartitionsPerBroker := MaxPartitionsPerBroker(curr)
-	maxPartitionsPerBroker := MaxPartitionsPerBroker(curr, desired)
-	finalPartitionsPerBroker := MaxPartitionsPerBroker(desired)
+	brokerCountsPerRack := BrokerCountsPerRack(brokers)
+	racks := DistinctRacks(brokers)
 
-	maxCount := maxInts(
-		maxMapValues(startPartitionsPerBroker),
-		maxMapValues(maxPartitionsPerBroker),
-		maxMapValues(finalPartitionsPerBroker),
+	for _, rack := range racks {
+		table.Append(
+			[]string{
+				rack,
+				fmt.Sprintf("%d", brokerCountsPerRack[rack]),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatTopics creates a pretty table that lists the details of the
+// argument topics.
+func FormatTopics(topics []TopicInfo, brokers []BrokerInfo, full bool) string {
+	buf := &bytes.Buffer{}
+
+	headers := []string{
+		"Name",
+		"Partitions",
+		"Replication",
+		"Retention\nMins",
+		"Racks\n(min,max)",
+	}
+
+	if full {
+		headers = append(headers, "Config")
+	}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(headers)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	brokerRacks := BrokerRacks(brokers)
+
+	for _, topic := range topics {
+		var retentionStr string
+
+		retention := topic.Retention()
+		if retention > 0 {
+			retentionStr = fmt.Sprintf("%d", int(retention.Minutes()))
+		}
+
+		minRacks, maxRacks, _ := topic.RackCounts(brokerRacks)
+
+		row := []string{
+			topic.Name,
+			fmt.Sprintf("%d", len(topic.Partitions)),
+			fmt.Sprintf("%d", topic.MaxReplication()),
+			retentionStr,
+			fmt.Sprintf("(%d,%d)", minRacks, maxRacks),
+		}
+
+		if full {
+			row = append(row, prettyConfig(topic.Config))
+		}
+
+		table.Append(row)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatTopicPartitions creates a pretty table with information on all of the
+// partitions for a topic.
+func FormatTopicPartitions(partitions []PartitionInfo, brokers []BrokerInfo) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"ID", "Leader", "Replicas", "ISR", "Distinct\nRacks", "Racks", "Status",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	brokerRacks := BrokerRacks(brokers)
+	maxBrokerWidth := maxValueToMaxWidth(len(brokers))
+
+	for _, partition := range partitions {
+		racks, _ := partition.Racks(brokerRacks)
+		rackCount, _ := partition.NumRacks(brokerRacks)
+
+		table.Append(
+			[]string{
+				fmt.Sprintf("%d", partition.ID),
+				fmt.Sprintf("%d", partition.Leader),
+				intSliceString(partition.Replicas, maxBrokerWidth),
+				intSliceString(partition.ISR, maxBrokerWidth),
+				fmt.Sprintf("%d", rackCount),
+				fmt.Sprintf("%+v", racks),
+				partitionStatusStr(partition),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatLeaderElectionPartitions creates a pretty table with the current leaders and replicas
+// of the argument partitions, which can be in different topics.
+func FormatLeaderElectionPartitions(partitions []PartitionInfo) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Partition",
+			"Leader",
+			"Preferred\nLeader",
+			"Replicas",
+			"ISR",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, partition := range partitions {
+		var preferredLeader string
+		if len(partition.Replicas) > 0 {
+			preferredLeader = fmt.Sprintf("%d", partition.Replicas[0])
+		}
+
+		table.Append(
+			[]string{
+				partition.Topic,
+				fmt.Sprintf("%d", partition.ID),
+				fmt.Sprintf("%d", partition.Leader),
+				preferredLeader,
+				intSliceString(partition.Replicas, 0),
+				intSliceString(partition.ISR, 0),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatPartitionDetails creates a pretty table with information on all of the argument
+// partitions, including the sizes of their replicas and the times of their latest messages.
+// The partitions are shown in the order given.
+func FormatPartitionDetails(
+	partitions []PartitionDetails,
+	brokers []BrokerInfo,
+	now time.Time,
+) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"ID",
+			"Leader",
+			"Preferred\nLeader",
+			"Replicas",
+			"ISR",
+			"Racks",
+			"Replica\nSizes",
+			"Last\nModified",
+			"Status",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	brokerRacks := BrokerRacks(brokers)
+	maxBrokerWidth := maxValueToMaxWidth(len(brokers))
+
+	for _, partition := range partitions {
+		racks, _ := partition.Racks(brokerRacks)
+
+		var preferredLeaderStr string
+		if partition.HasPreferredLeader() {
+			preferredLeaderStr = "✓"
+		} else {
+			preferredLeaderStr = "✗"
+		}
+
+		replicaSizes := []string{}
+		for _, replica := range partition.Replicas {
+			size, ok := partition.ReplicaSizes[replica]
+			if !ok {
+				replicaSizes = append(replicaSizes, fmt.Sprintf("%d:?", replica))
+				continue
+			}
+			replicaSizes = append(
+				replicaSizes,
+				fmt.Sprintf("%d:%s", replica, util.PrettyBytes(size)),
+			)
+		}
+
+		var lastModifiedStr string
+		if !partition.LastModified.IsZero() {
+			lastModifiedStr = fmt.Sprintf(
+				"%s (%s ago)",
+				partition.LastModified.UTC().Format(time.RFC3339),
+				util.PrettyDuration(now.Sub(partition.LastModified)),
+			)
+		}
+
+		table.Append(
+			[]string{
+				fmt.Sprintf("%d", partition.ID),
+				fmt.Sprintf("%d", partition.Leader),
+				preferredLeaderStr,
+				intSliceString(partition.Replicas, maxBrokerWidth),
+				intSliceString(partition.ISR, maxBrokerWidth),
+				fmt.Sprintf("%+v", racks),
+				strings.Join(replicaSizes, " "),
+				lastModifiedStr,
+				partitionStatusStr(partition.PartitionInfo),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatACLs creates a pretty table from a list of ACLs.
+func FormatACLs(acls []ACLInfo) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Resource\nType",
+			"Resource\nName",
+			"Pattern\nType",
+			"Principal",
+			"Host",
+			"Operation",
+			"Permission",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, acl := range acls {
+		var permissionStr string
+		if util.ColorEnabled() && acl.PermissionType == kafka.ACLPermissionTypeDeny {
+			permissionStr = color.New(color.FgRed).Sprint(acl.PermissionType.String())
+		} else {
+			permissionStr = acl.PermissionType.String()
+		}
+
+		table.Append(
+			[]string{
+				acl.ResourceType.String(),
+				acl.ResourceName,
+				acl.PatternType.String(),
+				acl.Principal,
+				acl.Host,
+				acl.Operation.String(),
+				permissionStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatQuotas creates a pretty table from a list of quotas.
+func FormatQuotas(quotas []QuotaInfo) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"User",
+			"Client ID",
+			"Producer\nByte Rate",
+			"Consumer\nByte Rate",
+			"Request\nPercentage",
+			"Other",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, quota := range quotas {
+		otherKeys := []string{}
+		for key := range quota.Values {
+			if key != QuotaKeyProducerByteRate &&
+				key != QuotaKeyConsumerByteRate &&
+				key != QuotaKeyRequestPercentage {
+				otherKeys = append(otherKeys, key)
+			}
+		}
+		sort.Strings(otherKeys)
+
+		otherValues := []string{}
+		for _, key := range otherKeys {
+			otherValues = append(
+				otherValues,
+				fmt.Sprintf("%s=%s", key, quotaValueStr(quota.Values[key])),
+			)
+		}
+
+		table.Append(
+			[]string{
+				quota.Entity.User,
+				quota.Entity.ClientID,
+				quotaByteRateStr(quota.Values, QuotaKeyProducerByteRate),
+				quotaByteRateStr(quota.Values, QuotaKeyConsumerByteRate),
+				quotaPercentageStr(quota.Values, QuotaKeyRequestPercentage),
+				strings.Join(otherValues, ", "),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatTopicSummary creates a pretty table that summarizes the state of a single topic,
+// including its size (if replicaLogDirs is non-empty) and any issues with its replicas,
+// leaders, throttles, or reassignments.
+func FormatTopicSummary(
+	topic TopicInfo,
+	brokers []BrokerInfo,
+	replicaLogDirs []ReplicaLogDirInfo,
+	reassignments []PartitionReassignment,
+) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Key",
+			"Value",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	retentionStr := "(broker default)"
+	if retention := topic.Retention(); retention > 0 {
+		retentionStr = util.PrettyDuration(retention)
+	}
+
+	minRacks, maxRacks, _ := topic.RackCounts(BrokerRacks(brokers))
+
+	sizeStr := "n/a"
+	if len(replicaLogDirs) > 0 {
+		var totalBytes int64
+		for _, replicaLogDir := range replicaLogDirs {
+			if !replicaLogDir.IsFuture {
+				totalBytes += replicaLogDir.Size
+			}
+		}
+		sizeStr = util.PrettyBytes(totalBytes)
+	}
+
+	throttledStr := "no"
+	if topic.IsThrottled() {
+		throttledStr = problemStr("yes")
+	}
+
+	table.AppendBulk(
+		[][]string{
+			{"Partitions", fmt.Sprintf("%d", len(topic.Partitions))},
+			{"Replication factor", fmt.Sprintf("%d", topic.MaxReplication())},
+			{"Retention", retentionStr},
+			{"Racks (min,max)", fmt.Sprintf("(%d,%d)", minRacks, maxRacks)},
+			{"Size (all replicas)", sizeStr},
+			{
+				"Out-of-sync partitions",
+				countOfTotalStr(len(topic.OutOfSyncPartitions(nil)), len(topic.Partitions)),
+			},
+			{
+				"Wrong leaders",
+				countOfTotalStr(len(topic.WrongLeaderPartitions(nil)), len(topic.Partitions)),
+			},
+			{"Throttled", throttledStr},
+			{
+				"Reassignments in progress",
+				countOfTotalStr(len(reassignments), len(topic.Partitions)),
+			},
+		},
+	)
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatTopicThrottles creates a pretty table that shows the throttled replicas of the argument
+// topic on each broker, along with the throttle rates set on those brokers.
+func FormatTopicThrottles(topic TopicInfo, brokers []BrokerInfo) (string, error) {
+	leaderThrottles, followerThrottles, err := ParsePartitionThrottles(topic)
+	if err != nil {
+		return "", err
+	}
+	leaderRates, followerRates, err := ParseBrokerThrottles(brokers)
+	if err != nil {
+		return "", err
+	}
+
+	leaderPartitions := map[int][]int{}
+	for _, throttle := range leaderThrottles {
+		leaderPartitions[throttle.Broker] = append(
+			leaderPartitions[throttle.Broker],
+			throttle.Partition,
+		)
+	}
+	followerPartitions := map[int][]int{}
+	for _, throttle := range followerThrottles {
+		followerPartitions[throttle.Broker] = append(
+			followerPartitions[throttle.Broker],
+			throttle.Partition,
+		)
+	}
+
+	leaderRatesMap := map[int]int64{}
+	for _, rate := range leaderRates {
+		leaderRatesMap[rate.Broker] = rate.ThrottleBytes
+	}
+	followerRatesMap := map[int]int64{}
+	for _, rate := range followerRates {
+		followerRatesMap[rate.Broker] = rate.ThrottleBytes
+	}
+
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Broker",
+			"Leader\nPartitions",
+			"Follower\nPartitions",
+			"Leader\nRate",
+			"Follower\nRate",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, brokerID := range BrokerIDs(brokers) {
+		leaders := leaderPartitions[brokerID]
+		followers := followerPartitions[brokerID]
+		if len(leaders) == 0 && len(followers) == 0 {
+			continue
+		}
+
+		table.Append(
+			[]string{
+				fmt.Sprintf("%d", brokerID),
+				intSliceString(leaders, 0),
+				intSliceString(followers, 0),
+				throttleRateStr(leaderRatesMap, brokerID),
+				throttleRateStr(followerRatesMap, brokerID),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n")), nil
+}
+
+// FormatPartitionReassignments creates a pretty table that shows the argument in-progress
+// partition reassignments.
+func FormatPartitionReassignments(reassignments []PartitionReassignment) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Partition",
+			"Replicas",
+			"Adding",
+			"Removing",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, reassignment := range reassignments {
+		table.Append(
+			[]string{
+				reassignment.Topic,
+				fmt.Sprintf("%d", reassignment.Partition),
+				intSliceString(reassignment.Replicas, 0),
+				intSliceString(reassignment.AddingReplicas, 0),
+				intSliceString(reassignment.RemovingReplicas, 0),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatReassignmentProgress creates a pretty table that shows the argument in-progress
+// partition reassignments along with their estimated progress.
+func FormatReassignmentProgress(progresses []ReassignmentProgress) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Partition",
+			"Replicas",
+			"Adding",
+			"Removing",
+			"Leader",
+			"Leader\nSize",
+			"Remaining",
+			"Progress",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, progress := range progresses {
+		leaderSizeStr := "n/a"
+		if progress.LeaderSize >= 0 {
+			leaderSizeStr = util.PrettyBytes(progress.LeaderSize)
+		}
+
+		remainingStr := "n/a"
+		if remaining := progress.RemainingBytes(); remaining >= 0 {
+			remainingStr = util.PrettyBytes(remaining)
+		}
+
+		progressStr := "n/a"
+		if fraction := progress.Fraction(); fraction >= 0 {
+			progressStr = fmt.Sprintf("%.1f%%", 100.0*fraction)
+		}
+
+		table.Append(
+			[]string{
+				progress.Topic,
+				fmt.Sprintf("%d", progress.Partition),
+				intSliceString(progress.Replicas, 0),
+				addingReplicasStr(progress.AddingReplicas, progress.InSyncAddingReplicas),
+				intSliceString(progress.RemovingReplicas, 0),
+				fmt.Sprintf("%d", progress.Leader),
+				leaderSizeStr,
+				remainingStr,
+				progressStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatClusterHealth creates a pretty table that shows the results of each health check for
+// a cluster.
+func FormatClusterHealth(health ClusterHealth) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Check",
+			"Status",
+			"Details",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, check := range health.Checks() {
+		table.Append(
+			[]string{
+				check.Name,
+				healthStatusStr(check.Status),
+				check.Details,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatClusterMetadata creates a pretty table that shows the identity and mode of a cluster,
+// along with the details of each of its brokers.
+func FormatClusterMetadata(metadata ClusterMetadata) string {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "Cluster ID: %s\n", metadata.ClusterID)
+	fmt.Fprintf(buf, "Controller: %d\n", metadata.ControllerID)
+	fmt.Fprintf(buf, "Mode: %s\n", metadata.Mode)
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"ID",
+			"Host",
+			"Port",
+			"Rack",
+			"Controller",
+			"Kafka\nVersion",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, broker := range metadata.Brokers {
+		var controllerStr string
+		if broker.ID == metadata.ControllerID {
+			controllerStr = "Yes"
+		}
+
+		var versionStr string
+		if broker.Error != "" {
+			versionStr = "Unknown"
+		} else if broker.KafkaVersion != "" {
+			versionStr = fmt.Sprintf(">= %s", broker.KafkaVersion)
+		}
+
+		table.Append(
+			[]string{
+				fmt.Sprintf("%d", broker.ID),
+				broker.Host,
+				fmt.Sprintf("%d", broker.Port),
+				broker.Rack,
+				controllerStr,
+				versionStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatCompatResults creates a pretty table that shows which topicctl features are supported
+// by the brokers in a cluster.
+func FormatCompatResults(results []CompatResult) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Feature",
+			"Supported",
+			"Required API",
+			"Kafka\nVersion",
+			"Unsupported\nBrokers",
+			"Used By",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, result := range results {
+		var unsupportedStr string
+		if len(result.UnsupportedBrokers) > 0 {
+			unsupportedStr = intSliceString(result.UnsupportedBrokers, 0)
+		}
+
+		table.Append(
+			[]string{
+				result.Name,
+				compatSupportedStr(result.Supported),
+				fmt.Sprintf("%s v%d", result.API, result.MinVersion),
+				fmt.Sprintf(">= %s", result.KafkaVersion),
+				unsupportedStr,
+				result.Description,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatBrokerDrainPlan creates a pretty table that shows the replicas of each topic that are
+// on a broker being drained.
+func FormatBrokerDrainPlan(plan BrokerDrainPlan) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Replicas",
+			"Leaders",
+			"Size",
+			"Managed",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, topic := range plan.Topics {
+		var sizeStr string
+		if topic.Size >= 0 {
+			sizeStr = util.PrettyBytes(topic.Size)
+		}
+
+		var managedStr string
+		if topic.Managed {
+			managedStr = "Yes"
+		} else {
+			managedStr = problemStr("No")
+		}
+
+		table.Append(
+			[]string{
+				topic.Topic,
+				fmt.Sprintf("%d", topic.Replicas),
+				fmt.Sprintf("%d", topic.Leaders),
+				sizeStr,
+				managedStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatConfig creates a pretty table with all of the keys and values in a topic or
+// broker config.
+func FormatConfig(configMap map[string]string) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Key",
+			"Value",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	keys := []string{}
+	for key := range configMap {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(a, b int) bool {
+		return keys[a] < keys[b]
+	})
+
+	for _, key := range keys {
+		table.Append(
+			[]string{
+				key,
+				configMap[key],
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatBrokerConfigs creates a pretty table with the configs of the argument brokers. Entries
+// with the same key, value, and source are grouped together, and the keys in drifted are
+// highlighted. If full is false, configs set to their default values are omitted.
+func FormatBrokerConfigs(
+	brokerConfigs []BrokerConfigs,
+	drifted []string,
+	full bool,
+) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Key",
+			"Value",
+			"Source",
+			"Brokers",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	driftedMap := map[string]struct{}{}
+	for _, name := range drifted {
+		driftedMap[name] = struct{}{}
+	}
+
+	type entryKey struct {
+		name   string
+		value  string
+		source string
+	}
+
+	entryKeys := []entryKey{}
+	entryBrokers := map[entryKey][]int{}
+
+	for _, brokerConfig := range brokerConfigs {
+		for _, entry := range brokerConfig.Entries {
+			if !full && entry.IsDefault() {
+				continue
+			}
+
+			key := entryKey{
+				name:   entry.Name,
+				value:  entry.Value,
+				source: entry.Source,
+			}
+			if _, ok := entryBrokers[key]; !ok {
+				entryKeys = append(entryKeys, key)
+			}
+			entryBrokers[key] = append(entryBrokers[key], brokerConfig.BrokerID)
+		}
+	}
+
+	sort.Slice(entryKeys, func(a, b int) bool {
+		if entryKeys[a].name != entryKeys[b].name {
+			return entryKeys[a].name < entryKeys[b].name
+		}
+		return entryKeys[a].value < entryKeys[b].value
+	})
+
+	driftedSprintf := fmt.Sprintf
+	if util.ColorEnabled() {
+		driftedSprintf = color.New(color.FgRed).SprintfFunc()
+	}
+
+	for _, key := range entryKeys {
+		name := key.name
+		if _, ok := driftedMap[name]; ok {
+			name = driftedSprintf("%s", name)
+		}
+
+		table.Append(
+			[]string{
+				name,
+				key.value,
+				key.source,
+				intSliceString(entryBrokers[key], 0),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatBrokerConfigDrift creates a pretty table that shows the value of each of the argument
+// drifted configs on every broker.
+func FormatBrokerConfigDrift(brokerConfigs []BrokerConfigs, drifted []string) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Key",
+			"Broker",
+			"Value",
+			"Source",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, name := range drifted {
+		for _, brokerConfig := range brokerConfigs {
+			row := []string{
+				name,
+				fmt.Sprintf("%d", brokerConfig.BrokerID),
+				"",
+				"",
+			}
+
+			for _, entry := range brokerConfig.Entries {
+				if entry.Name == name {
+					row[2] = entry.Value
+					row[3] = entry.Source
+					break
+				}
+			}
+
+			table.Append(row)
+		}
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatTopicLeadersPerRack creates a pretty table that shows the number
+// of partitions with a leader in each rack.
+func FormatTopicLeadersPerRack(topic TopicInfo, brokers []BrokerInfo) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Rack", "Num Leaders",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	leadersPerRack := LeadersPerRack(brokers, topic)
+	racks := DistinctRacks(brokers)
+
+	for _, rack := range racks {
+		table.Append(
+			[]string{
+				rack,
+				fmt.Sprintf("%d", leadersPerRack[rack]),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatAssignentDiffs generates a pretty table that shows the before
+// and after states of a partition replica and/or leader update.
+func FormatAssignentDiffs(
+	curr []PartitionAssignment,
+	desired []PartitionAssignment,
+	brokers []BrokerInfo,
+) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Partition",
+			"Curr\nReplicas",
+			"Proposed\nReplicas",
+			"Diff?",
+			"New\nLeader?",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	diffs := AssignmentDiffs(curr, desired)
+
+	brokerRacks := BrokerRacks(brokers)
+	maxWidth := maxValueToMaxWidth(len(brokers))
+
+	for _, diff := range diffs {
+		var diffStr string
+		var newLeaderStr string
+
+		if !reflect.DeepEqual(diff.Old.Replicas, diff.New.Replicas) {
+			diffStr = "Y"
+		}
+		if len(diff.Old.Replicas) > 0 &&
+			len(diff.New.Replicas) > 0 &&
+			diff.Old.Replicas[0] != diff.New.Replicas[0] {
+			newLeaderStr = "Y"
+		}
+
+		table.Append(
+			[]string{
+				fmt.Sprintf("%d", diff.PartitionID),
+				assignmentRacksRemovedStr(diff.Old, diff.New, brokerRacks, maxWidth),
+				assignmentRacksDiffStr(diff.Old, diff.New, brokerRacks, maxWidth),
+				diffStr,
+				newLeaderStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatBrokerMaxPartitions generates a pretty table that shows the total number of
+// partitions that each broker is involved in for a diff. It's used to evaluate
+// the potential extra load that could occur on brokers during a migration.
+func FormatBrokerMaxPartitions(
+	curr []PartitionAssignment,
+	desired []PartitionAssignment,
+	brokers []BrokerInfo,
+) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Broker",
+			"Curr\nPartitions",
+			"Max\nPartitions",
+			"Proposed\nPartitions",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	startPartitionsPerBroker := MaxPartitionsPerBroker(curr)
+	maxPartitionsPerBroker := MaxPartitionsPerBroker(curr, desired)
+	finalPartitionsPerBroker := MaxPartitionsPerBroker(desired)
+
+	maxCount := maxInts(
+		maxMapValues(startPartitionsPerBroker),
+		maxMapValues(maxPartitionsPerBroker),
+		maxMapValues(finalPartitionsPerBroker),
 	)
 	maxCountWidth := maxValueToMaxWidth(maxCount)
 
@@ -780,6 +2153,41 @@ func assignmentRacksStr(
 	return strings.Join(elements, ", ")
 }
 
+// assignmentRacksRemovedStr returns the replicas in the argument old assignment, with the ones
+// that aren't in the new assignment colored red.
+func assignmentRacksRemovedStr(
+	old PartitionAssignment,
+	new PartitionAssignment,
+	brokerRacks map[int]string,
+	maxWidth int,
+) string {
+	if len(new.Replicas) == 0 || !util.ColorEnabled() {
+		return assignmentRacksStr(old, brokerRacks, maxWidth)
+	}
+
+	elements := []string{}
+
+	removed := color.New(color.FgRed).SprintfFunc()
+
+	for _, replica := range old.Replicas {
+		if new.Index(replica) == -1 {
+			elements = append(
+				elements,
+				removed("%*d (%s)", maxWidth, replica, brokerRacks[replica]),
+			)
+		} else {
+			elements = append(
+				elements,
+				fmt.Sprintf("%*d (%s)", maxWidth, replica, brokerRacks[replica]),
+			)
+		}
+	}
+
+	return strings.Join(elements, ", ")
+}
+
+// assignmentRacksDiffStr returns the replicas in the argument new assignment, with the ones that
+// were added colored green and the ones that changed positions colored yellow.
 func assignmentRacksDiffStr(
 	old PartitionAssignment,
 	new PartitionAssignment,
@@ -790,14 +2198,14 @@ func assignmentRacksDiffStr(
 		return ""
 	}
 
-	if !util.InTerminal() {
+	if !util.ColorEnabled() {
 		return assignmentRacksStr(new, brokerRacks, maxWidth)
 	}
 
 	elements := []string{}
 
-	added := color.New(color.FgRed).SprintfFunc()
-	moved := color.New(color.FgBlue).SprintfFunc()
+	added := color.New(color.FgGreen).SprintfFunc()
+	moved := color.New(color.FgYellow).SprintfFunc()
 
 	for r, replica := range new.Replicas {
 		var element string
@@ -824,12 +2232,12 @@ func partitionCountDiffStr(diffValue int) string {
 	var increasedSprintf func(format string, a ...interface{}) string
 	var decreasedSprintf func(format string, a ...interface{}) string
 
-	if !util.InTerminal() {
+	if !util.ColorEnabled() {
 		increasedSprintf = fmt.Sprintf
 		decreasedSprintf = fmt.Sprintf
 	} else {
-		increasedSprintf = color.New(color.FgRed).SprintfFunc()
-		decreasedSprintf = color.New(color.FgBlue).SprintfFunc()
+		increasedSprintf = color.New(color.FgGreen).SprintfFunc()
+		decreasedSprintf = color.New(color.FgRed).SprintfFunc()
 	}
 
 	if diffValue > 0 {
@@ -838,6 +2246,183 @@ func partitionCountDiffStr(diffValue int) string {
 	return fmt.Sprintf(" (%s)", decreasedSprintf("%-d", diffValue))
 }
 
+// partitionStatusStr returns a (possibly colored) summary of whether the argument partition
+// is in-sync and has its preferred leader.
+func partitionStatusStr(partition PartitionInfo) string {
+	inSync := util.SameElements(partition.Replicas, partition.ISR)
+
+	// If there's no replica information yet, the leader is treated as incorrect
+	correctLeader := partition.HasPreferredLeader()
+
+	var statusPrinter func(f string, a ...interface{}) string
+	if !util.ColorEnabled() || (inSync && correctLeader) {
+		statusPrinter = fmt.Sprintf
+	} else if !inSync {
+		statusPrinter = color.New(color.FgRed).SprintfFunc()
+	} else if !correctLeader {
+		statusPrinter = color.New(color.FgBlue).SprintfFunc()
+	}
+
+	var statusStr string
+	if !inSync {
+		statusStr = "Out-of-sync"
+	} else if !correctLeader {
+		statusStr = "Wrong leader"
+	} else {
+		statusStr = "OK"
+	}
+
+	return statusPrinter("%s", statusStr)
+}
+
+// addingReplicasStr returns a string for the argument replicas being added by a reassignment,
+// with the ones that are already in sync marked with an asterisk.
+func addingReplicasStr(adding []int, inSync []int) string {
+	inSyncSet := map[int]struct{}{}
+	for _, replica := range inSync {
+		inSyncSet[replica] = struct{}{}
+	}
+
+	replicaStrs := []string{}
+	for _, replica := range adding {
+		if _, ok := inSyncSet[replica]; ok {
+			replicaStrs = append(replicaStrs, fmt.Sprintf("%d*", replica))
+		} else {
+			replicaStrs = append(replicaStrs, fmt.Sprintf("%d", replica))
+		}
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(replicaStrs, " "))
+}
+
+// healthStatusStr returns a (possibly colored) string for the argument health check status.
+func healthStatusStr(status HealthStatus) string {
+	statusStr := strings.ToUpper(string(status))
+	if !util.ColorEnabled() {
+		return statusStr
+	}
+
+	switch status {
+	case HealthStatusWarning:
+		return color.New(color.FgYellow).Sprint(statusStr)
+	case HealthStatusUnhealthy:
+		return color.New(color.FgRed).Sprint(statusStr)
+	default:
+		return color.New(color.FgGreen).Sprint(statusStr)
+	}
+}
+
+// compatSupportedStr returns a (possibly colored) string for whether a feature is supported.
+func compatSupportedStr(supported bool) string {
+	if !util.ColorEnabled() {
+		if supported {
+			return "Yes"
+		}
+		return "No"
+	}
+
+	if supported {
+		return color.New(color.FgGreen).Sprint("Yes")
+	}
+	return color.New(color.FgRed).Sprint("No")
+}
+
+func quotaValueStr(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func quotaByteRateStr(values map[string]float64, key string) string {
+	value, ok := values[key]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s/s", util.PrettyBytes(int64(value)))
+}
+
+func quotaPercentageStr(values map[string]float64, key string) string {
+	value, ok := values[key]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s%%", quotaValueStr(value))
+}
+
+// diskBytesStr returns the argument volume size, or a placeholder if the capacity of the volume
+// isn't known.
+func diskBytesStr(value int64, totalBytes int64) string {
+	if totalBytes <= 0 {
+		return "n/a"
+	}
+	return util.PrettyBytes(value)
+}
+
+// diskUsedStr returns a (possibly colored) string for the argument used fraction of a volume.
+func diskUsedStr(fraction float64) string {
+	if fraction < 0 {
+		return "n/a"
+	}
+	percent := 100.0 * fraction
+
+	if !util.ColorEnabled() || percent < 75.0 {
+		return fmt.Sprintf("%.1f%%", percent)
+	} else if percent < 90.0 {
+		return color.New(color.FgYellow).Sprintf("%.1f%%", percent)
+	}
+	return color.New(color.FgRed).Sprintf("%.1f%%", percent)
+}
+
+// countOfTotalStr returns a string of the form "count/total", which is colored if count is
+// non-zero.
+func countOfTotalStr(count int, total int) string {
+	countStr := fmt.Sprintf("%d/%d", count, total)
+	if count > 0 {
+		return problemStr(countStr)
+	}
+	return countStr
+}
+
+// problemStr returns the argument string, colored red if in a terminal.
+func problemStr(value string) string {
+	if !util.ColorEnabled() {
+		return value
+	}
+	return color.New(color.FgRed).Sprint(value)
+}
+
+func throttleRateStr(rates map[int]int64, brokerID int) string {
+	rate, ok := rates[brokerID]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s/s", util.PrettyBytes(rate))
+}
+
+// meanDiffStr returns a (possibly colored) string showing how far the argument value is from
+// the mean, as a percentage of the mean.
+func meanDiffStr(value float64, mean float64) string {
+	if mean == 0 {
+		return "0.0%"
+	}
+	diff := 100.0 * (value - mean) / mean
+
+	if !util.ColorEnabled() || math.Abs(diff) < 10.0 {
+		return fmt.Sprintf("%+.1f%%", diff)
+	} else if diff > 0 {
+		return color.New(color.FgRed).Sprintf("%+.1f%%", diff)
+	}
+	return color.New(color.FgBlue).Sprintf("%+.1f%%", diff)
+}
+
+// scoreStr returns a (possibly colored) string for the argument balance score.
+func scoreStr(score float64) string {
+	if !util.ColorEnabled() || score >= 90.0 {
+		return fmt.Sprintf("%.1f", score)
+	} else if score >= 75.0 {
+		return color.New(color.FgYellow).Sprintf("%.1f", score)
+	}
+	return color.New(color.FgRed).Sprintf("%.1f", score)
+}
+
 func intSliceString(values []int, maxWidth int) string {
 	strValues := []string{}
 
@@ -0,0 +1,254 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	krbclient "github.com/jcmturner/gokrb5/v8/client"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/iana/keyusage"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/jcmturner/gokrb5/v8/types"
+	"github.com/segmentio/kafka-go/sasl"
+)
+
+const (
+	// DefaultGSSAPIServiceName is the Kerberos service name that the brokers are assumed to run
+	// as if one isn't set explicitly. It matches the default of sasl.kerberos.service.name.
+	DefaultGSSAPIServiceName = "kafka"
+
+	// DefaultKerberosConfigPath is the path of the Kerberos config that's used if one isn't set
+	// explicitly.
+	DefaultKerberosConfigPath = "/etc/krb5.conf"
+
+	// gssapiNoSecurityLayer is the bit for the "no security layer" option in the security layer
+	// negotiation at the end of the exchange; see RFC 4752.
+	gssapiNoSecurityLayer = 0x01
+)
+
+// GSSAPIConfig stores the Kerberos-related configuration for connections that authenticate via
+// the SASL GSSAPI mechanism.
+type GSSAPIConfig struct {
+	// Principal is the client principal, e.g. topicctl@EXAMPLE.COM. If it doesn't include a
+	// realm, the default realm in the Kerberos config is used.
+	Principal string
+
+	// KeytabPath is the path of the keytab that contains the principal's keys. If it's unset,
+	// the password in the SASL config is used instead.
+	KeytabPath string
+
+	// KerberosConfigPath is the path of the krb5.conf file that has the realm and KDC settings.
+	// It defaults to DefaultKerberosConfigPath.
+	KerberosConfigPath string
+
+	// ServiceName is the Kerberos service name of the brokers. It defaults to
+	// DefaultGSSAPIServiceName.
+	ServiceName string
+
+	// DisablePAFXFAST disables the PA-FX-FAST pre-authentication, which isn't supported by
+	// Active Directory KDCs.
+	DisablePAFXFAST bool
+}
+
+// gssapiMechanism is a sasl.Mechanism that authenticates via Kerberos. The client logs in to the
+// KDC the first time that a connection is made and then reuses the resulting ticket-granting
+// ticket for all of the brokers.
+type gssapiMechanism struct {
+	config   GSSAPIConfig
+	password string
+
+	mutex  sync.Mutex
+	client *krbclient.Client
+}
+
+var _ sasl.Mechanism = (*gssapiMechanism)(nil)
+
+func newGSSAPIMechanism(config GSSAPIConfig, password string) (*gssapiMechanism, error) {
+	if config.Principal == "" {
+		return nil, errors.New("GSSAPI principal must be set")
+	}
+	if config.KeytabPath == "" && password == "" {
+		return nil, errors.New("One of GSSAPI keytab path or SASL password must be set")
+	}
+	if config.KerberosConfigPath == "" {
+		config.KerberosConfigPath = DefaultKerberosConfigPath
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = DefaultGSSAPIServiceName
+	}
+
+	return &gssapiMechanism{
+		config:   config,
+		password: password,
+	}, nil
+}
+
+// Name returns the name of the mechanism in the SASL handshake.
+func (m *gssapiMechanism) Name() string {
+	return "GSSAPI"
+}
+
+// Start gets a service ticket for the broker that's being connected to and returns the
+// resulting AP-REQ token as the initial response.
+func (m *gssapiMechanism) Start(ctx context.Context) (sasl.StateMachine, []byte, error) {
+	metadata := sasl.MetadataFromContext(ctx)
+	if metadata == nil {
+		return nil, nil, errors.New("Broker host is required for GSSAPI authentication")
+	}
+
+	client, err := m.getClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spn := fmt.Sprintf("%s/%s", m.config.ServiceName, metadata.Host)
+	log.Debugf("Getting Kerberos service ticket for %s", spn)
+
+	ticket, sessionKey, err := client.GetServiceTicket(spn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error getting Kerberos service ticket for %s: %+v", spn, err)
+	}
+
+	token, err := spnego.NewKRB5TokenAPREQ(
+		client,
+		ticket,
+		sessionKey,
+		[]int{gssapi.ContextFlagInteg, gssapi.ContextFlagConf},
+		[]int{},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokenBytes, err := token.Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &gssapiSession{sessionKey: sessionKey}, tokenBytes, nil
+}
+
+func (m *gssapiMechanism) getClient() (*krbclient.Client, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.client != nil {
+		return m.client, nil
+	}
+
+	krb5Config, err := krbconfig.Load(m.config.KerberosConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Error loading Kerberos config from %s: %+v",
+			m.config.KerberosConfigPath,
+			err,
+		)
+	}
+
+	username, realm := splitPrincipal(m.config.Principal)
+	if realm == "" {
+		realm = krb5Config.LibDefaults.DefaultRealm
+	}
+	if realm == "" {
+		return nil, fmt.Errorf(
+			"GSSAPI principal %s doesn't have a realm and there's no default realm in %s",
+			m.config.Principal,
+			m.config.KerberosConfigPath,
+		)
+	}
+
+	settings := krbclient.DisablePAFXFAST(m.config.DisablePAFXFAST)
+	var client *krbclient.Client
+
+	if m.config.KeytabPath != "" {
+		log.Debugf("Loading Kerberos keytab from %s", m.config.KeytabPath)
+		kt, err := keytab.Load(m.config.KeytabPath)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Error loading Kerberos keytab from %s: %+v",
+				m.config.KeytabPath,
+				err,
+			)
+		}
+		client = krbclient.NewWithKeytab(username, realm, kt, krb5Config, settings)
+	} else {
+		client = krbclient.NewWithPassword(username, realm, m.password, krb5Config, settings)
+	}
+
+	log.Debugf("Logging in to Kerberos as %s@%s", username, realm)
+	if err := client.Login(); err != nil {
+		return nil, fmt.Errorf("Error logging in to Kerberos as %s@%s: %+v", username, realm, err)
+	}
+
+	m.client = client
+	return client, nil
+}
+
+// gssapiSession handles the part of the exchange after the initial AP-REQ, in which the broker
+// sends a wrap token with the security layers that it supports and the client replies with its
+// selection.
+type gssapiSession struct {
+	sessionKey types.EncryptionKey
+	negotiated bool
+}
+
+var _ sasl.StateMachine = (*gssapiSession)(nil)
+
+// Next processes the argument challenge from the broker.
+func (s *gssapiSession) Next(ctx context.Context, challenge []byte) (bool, []byte, error) {
+	if s.negotiated {
+		// The broker's reply to the security layer selection is empty
+		return true, nil, nil
+	}
+	if len(challenge) == 0 {
+		// The broker may acknowledge the AP-REQ before sending the security layers
+		return false, nil, nil
+	}
+
+	challengeToken := &gssapi.WrapToken{}
+	if err := challengeToken.Unmarshal(challenge, true); err != nil {
+		return false, nil, fmt.Errorf("Error parsing GSSAPI challenge: %+v", err)
+	}
+	if _, err := challengeToken.Verify(s.sessionKey, keyusage.GSSAPI_ACCEPTOR_SEAL); err != nil {
+		return false, nil, fmt.Errorf("Error verifying GSSAPI challenge: %+v", err)
+	}
+	if len(challengeToken.Payload) != 4 {
+		return false, nil, fmt.Errorf(
+			"GSSAPI challenge payload has %d bytes, expected 4",
+			len(challengeToken.Payload),
+		)
+	}
+	if challengeToken.Payload[0]&gssapiNoSecurityLayer == 0 {
+		return false, nil, errors.New("Brokers require a GSSAPI security layer, which isn't supported")
+	}
+
+	// Select no security layer; the max message size must be zero in that case
+	responseToken, err := gssapi.NewInitiatorWrapToken(
+		[]byte{gssapiNoSecurityLayer, 0, 0, 0},
+		s.sessionKey,
+	)
+	if err != nil {
+		return false, nil, err
+	}
+	response, err := responseToken.Marshal()
+	if err != nil {
+		return false, nil, err
+	}
+
+	// kafka-go doesn't send the response if the exchange is marked as done, so that's deferred
+	// until the broker replies.
+	s.negotiated = true
+	return false, response, nil
+}
+
+func splitPrincipal(principal string) (string, string) {
+	index := strings.LastIndex(principal, "@")
+	if index < 0 {
+		return principal, ""
+	}
+	return principal[:index], principal[index+1:]
+}
@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/iana/keyusage"
+	"github.com/jcmturner/gokrb5/v8/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGSSAPIMechanism(t *testing.T) {
+	_, err := newGSSAPIMechanism(GSSAPIConfig{KeytabPath: "topicctl.keytab"}, "")
+	assert.Error(t, err)
+
+	_, err = newGSSAPIMechanism(GSSAPIConfig{Principal: "topicctl@EXAMPLE.COM"}, "")
+	assert.Error(t, err)
+
+	mechanism, err := newGSSAPIMechanism(
+		GSSAPIConfig{
+			Principal:  "topicctl@EXAMPLE.COM",
+			KeytabPath: "topicctl.keytab",
+		},
+		"",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "GSSAPI", mechanism.Name())
+	assert.Equal(t, DefaultKerberosConfigPath, mechanism.config.KerberosConfigPath)
+	assert.Equal(t, DefaultGSSAPIServiceName, mechanism.config.ServiceName)
+
+	// Starting without the broker metadata in the context fails before contacting the KDC
+	_, _, err = mechanism.Start(context.Background())
+	assert.Error(t, err)
+}
+
+func TestGSSAPISession(t *testing.T) {
+	ctx := context.Background()
+	sessionKey := types.EncryptionKey{
+		KeyType:  etypeID.AES256_CTS_HMAC_SHA1_96,
+		KeyValue: make([]byte, 32),
+	}
+
+	session := &gssapiSession{sessionKey: sessionKey}
+
+	done, response, err := session.Next(ctx, nil)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Empty(t, response)
+
+	challenge := acceptorWrapToken(t, []byte{0x07, 0x00, 0x10, 0x00}, sessionKey)
+	done, response, err = session.Next(ctx, challenge)
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	responseToken := &gssapi.WrapToken{}
+	require.NoError(t, responseToken.Unmarshal(response, false))
+	valid, err := responseToken.Verify(sessionKey, keyusage.GSSAPI_INITIATOR_SEAL)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, []byte{gssapiNoSecurityLayer, 0, 0, 0}, responseToken.Payload)
+
+	done, response, err = session.Next(ctx, nil)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Empty(t, response)
+
+	// Brokers that require a security layer aren't supported
+	session = &gssapiSession{sessionKey: sessionKey}
+	_, _, err = session.Next(ctx, acceptorWrapToken(t, []byte{0x04, 0x00, 0x10, 0x00}, sessionKey))
+	assert.Error(t, err)
+
+	// Challenges that weren't signed with the session key are rejected
+	otherKey := types.EncryptionKey{
+		KeyType:  etypeID.AES256_CTS_HMAC_SHA1_96,
+		KeyValue: []byte("0123456789abcdef0123456789abcdef"),
+	}
+	session = &gssapiSession{sessionKey: sessionKey}
+	_, _, err = session.Next(ctx, acceptorWrapToken(t, []byte{0x01, 0x00, 0x10, 0x00}, otherKey))
+	assert.Error(t, err)
+}
+
+func TestSplitPrincipal(t *testing.T) {
+	username, realm := splitPrincipal("topicctl/host.example.com@EXAMPLE.COM")
+	assert.Equal(t, "topicctl/host.example.com", username)
+	assert.Equal(t, "EXAMPLE.COM", realm)
+
+	username, realm = splitPrincipal("topicctl")
+	assert.Equal(t, "topicctl", username)
+	assert.Equal(t, "", realm)
+}
+
+func acceptorWrapToken(t *testing.T, payload []byte, key types.EncryptionKey) []byte {
+	token := &gssapi.WrapToken{
+		Flags:     0x01,
+		EC:        12,
+		RRC:       0,
+		SndSeqNum: 0,
+		Payload:   payload,
+	}
+	require.NoError(t, token.SetCheckSum(key, keyusage.GSSAPI_ACCEPTOR_SEAL))
+
+	tokenBytes, err := token.Marshal()
+	require.NoError(t, err)
+	return tokenBytes
+}
@@ -0,0 +1,316 @@
+package admin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HealthStatus is a string type that represents the outcome of a single cluster health check.
+type HealthStatus string
+
+const (
+	// HealthStatusOK indicates that nothing is wrong.
+	HealthStatusOK HealthStatus = "ok"
+
+	// HealthStatusWarning indicates that something may need attention (e.g., left over
+	// throttles) but that the cluster is still fully functional.
+	HealthStatusWarning HealthStatus = "warning"
+
+	// HealthStatusUnhealthy indicates that the cluster is degraded.
+	HealthStatusUnhealthy HealthStatus = "unhealthy"
+)
+
+// maxHealthDetails is the maximum number of items listed in the details of a health check.
+const maxHealthDetails = 5
+
+// ClusterHealth summarizes the aspects of a cluster's state that operators typically look at
+// first when something seems wrong.
+type ClusterHealth struct {
+	// ControllerID is the ID of the controller broker, or -1 if there's no active controller.
+	ControllerID int   `json:"controllerID"`
+	BrokerIDs    []int `json:"brokerIDs"`
+
+	Topics     int `json:"topics"`
+	Partitions int `json:"partitions"`
+
+	// OfflinePartitions are the partitions whose leader isn't one of the live brokers.
+	OfflinePartitions []PartitionInfo `json:"offlinePartitions"`
+
+	// UnderReplicatedPartitions are the online partitions that have replicas that aren't in
+	// sync. Replicas that are being added by a reassignment are ignored.
+	UnderReplicatedPartitions []PartitionInfo `json:"underReplicatedPartitions"`
+
+	ThrottledTopics  []string                `json:"throttledTopics"`
+	ThrottledBrokers []int                   `json:"throttledBrokers"`
+	Reassignments    []PartitionReassignment `json:"reassignments"`
+}
+
+// HealthCheck is the result of a single cluster health check.
+type HealthCheck struct {
+	Name    string       `json:"name"`
+	Status  HealthStatus `json:"status"`
+	Details string       `json:"details"`
+}
+
+// GetClusterHealth summarizes the health of a cluster from the argument controller ID, brokers,
+// topics, and in-progress reassignments.
+func GetClusterHealth(
+	controllerID int,
+	brokers []BrokerInfo,
+	topics []TopicInfo,
+	reassignments []PartitionReassignment,
+) ClusterHealth {
+	health := ClusterHealth{
+		ControllerID:              controllerID,
+		BrokerIDs:                 BrokerIDs(brokers),
+		Topics:                    len(topics),
+		OfflinePartitions:         []PartitionInfo{},
+		UnderReplicatedPartitions: []PartitionInfo{},
+		ThrottledTopics:           ThrottledTopicNames(topics),
+		ThrottledBrokers:          ThrottledBrokerIDs(brokers),
+		Reassignments:             reassignments,
+	}
+	if health.Reassignments == nil {
+		health.Reassignments = []PartitionReassignment{}
+	}
+	sort.Ints(health.BrokerIDs)
+	sort.Ints(health.ThrottledBrokers)
+	sort.Strings(health.ThrottledTopics)
+
+	brokerIDsMap := map[int]struct{}{}
+	for _, brokerID := range health.BrokerIDs {
+		brokerIDsMap[brokerID] = struct{}{}
+	}
+
+	addingReplicas := map[string]map[int][]int{}
+	for _, reassignment := range reassignments {
+		if _, ok := addingReplicas[reassignment.Topic]; !ok {
+			addingReplicas[reassignment.Topic] = map[int][]int{}
+		}
+		addingReplicas[reassignment.Topic][reassignment.Partition] = reassignment.AddingReplicas
+	}
+
+	for _, topic := range topics {
+		for _, partition := range topic.Partitions {
+			health.Partitions++
+
+			// The leader is -1 in zookeeper if there isn't one, but the broker API just omits
+			// it, so also check that the leader is live.
+			if _, ok := brokerIDsMap[partition.Leader]; !ok || partition.Leader < 0 {
+				health.OfflinePartitions = append(health.OfflinePartitions, partition)
+			} else if !partitionReplicasInSync(
+				partition,
+				addingReplicas[partition.Topic][partition.ID],
+			) {
+				health.UnderReplicatedPartitions = append(
+					health.UnderReplicatedPartitions,
+					partition,
+				)
+			}
+		}
+	}
+
+	return health
+}
+
+// HasController returns whether the cluster has an active controller that's one of its
+// brokers.
+func (h ClusterHealth) HasController() bool {
+	for _, brokerID := range h.BrokerIDs {
+		if brokerID == h.ControllerID {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks returns the results of the individual health checks for the cluster.
+func (h ClusterHealth) Checks() []HealthCheck {
+	checks := []HealthCheck{}
+
+	if h.HasController() {
+		checks = append(
+			checks,
+			HealthCheck{
+				Name:    "Controller",
+				Status:  HealthStatusOK,
+				Details: fmt.Sprintf("Broker %d", h.ControllerID),
+			},
+		)
+	} else {
+		checks = append(
+			checks,
+			HealthCheck{
+				Name:    "Controller",
+				Status:  HealthStatusUnhealthy,
+				Details: "No active controller",
+			},
+		)
+	}
+
+	checks = append(
+		checks,
+		HealthCheck{
+			Name:    "Offline partitions",
+			Status:  statusForCount(len(h.OfflinePartitions), HealthStatusUnhealthy),
+			Details: partitionsDetails(h.OfflinePartitions, h.Partitions),
+		},
+		HealthCheck{
+			Name:    "Under-replicated partitions",
+			Status:  statusForCount(len(h.UnderReplicatedPartitions), HealthStatusUnhealthy),
+			Details: partitionsDetails(h.UnderReplicatedPartitions, h.Partitions),
+		},
+		HealthCheck{
+			Name:    "In-progress reassignments",
+			Status:  statusForCount(len(h.Reassignments), HealthStatusWarning),
+			Details: reassignmentsDetails(h.Reassignments),
+		},
+		HealthCheck{
+			Name:    "Throttled topics",
+			Status:  statusForCount(len(h.ThrottledTopics), HealthStatusWarning),
+			Details: listDetails(h.ThrottledTopics, h.Topics),
+		},
+		HealthCheck{
+			Name:    "Throttled brokers",
+			Status:  statusForCount(len(h.ThrottledBrokers), HealthStatusWarning),
+			Details: listDetails(intsToStrings(h.ThrottledBrokers), len(h.BrokerIDs)),
+		},
+	)
+
+	return checks
+}
+
+// Problems returns a description of each unhealthy check for the cluster. Warnings aren't
+// included.
+func (h ClusterHealth) Problems() []string {
+	problems := []string{}
+
+	for _, check := range h.Checks() {
+		if check.Status == HealthStatusUnhealthy {
+			problems = append(
+				problems,
+				fmt.Sprintf("%s: %s", strings.ToLower(check.Name), check.Details),
+			)
+		}
+	}
+
+	return problems
+}
+
+// Healthy returns whether none of the cluster's health checks are unhealthy.
+func (h ClusterHealth) Healthy() bool {
+	return len(h.Problems()) == 0
+}
+
+// partitionReplicasInSync returns whether all of the replicas of the argument partition, other
+// than the argument ones that are being added by a reassignment, are in its ISR.
+func partitionReplicasInSync(partition PartitionInfo, adding []int) bool {
+	isrMap := map[int]struct{}{}
+	for _, replica := range partition.ISR {
+		isrMap[replica] = struct{}{}
+	}
+	addingMap := map[int]struct{}{}
+	for _, replica := range adding {
+		addingMap[replica] = struct{}{}
+	}
+
+	for _, replica := range partition.Replicas {
+		if _, ok := addingMap[replica]; ok {
+			continue
+		}
+		if _, ok := isrMap[replica]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func statusForCount(count int, nonZeroStatus HealthStatus) HealthStatus {
+	if count > 0 {
+		return nonZeroStatus
+	}
+	return HealthStatusOK
+}
+
+// partitionsDetails summarizes the argument partitions by topic, e.g.
+// "2/100 partitions: topic1 (1), topic2 (1)".
+func partitionsDetails(partitions []PartitionInfo, total int) string {
+	if len(partitions) == 0 {
+		return fmt.Sprintf("0/%d partitions", total)
+	}
+
+	topicNames := []string{}
+	topicCounts := map[string]int{}
+	for _, partition := range partitions {
+		if _, ok := topicCounts[partition.Topic]; !ok {
+			topicNames = append(topicNames, partition.Topic)
+		}
+		topicCounts[partition.Topic]++
+	}
+	sort.Strings(topicNames)
+
+	topicStrs := []string{}
+	for _, topicName := range topicNames {
+		topicStrs = append(topicStrs, fmt.Sprintf("%s (%d)", topicName, topicCounts[topicName]))
+	}
+
+	return fmt.Sprintf(
+		"%d/%d partitions: %s",
+		len(partitions),
+		total,
+		truncatedList(topicStrs),
+	)
+}
+
+func reassignmentsDetails(reassignments []PartitionReassignment) string {
+	if len(reassignments) == 0 {
+		return "None"
+	}
+
+	topicNames := []string{}
+	topicsMap := map[string]struct{}{}
+	for _, reassignment := range reassignments {
+		if _, ok := topicsMap[reassignment.Topic]; !ok {
+			topicNames = append(topicNames, reassignment.Topic)
+			topicsMap[reassignment.Topic] = struct{}{}
+		}
+	}
+	sort.Strings(topicNames)
+
+	return fmt.Sprintf(
+		"%d partition(s) in %d topic(s): %s",
+		len(reassignments),
+		len(topicNames),
+		truncatedList(topicNames),
+	)
+}
+
+func listDetails(values []string, total int) string {
+	if len(values) == 0 {
+		return fmt.Sprintf("0/%d", total)
+	}
+	return fmt.Sprintf("%d/%d: %s", len(values), total, truncatedList(values))
+}
+
+// truncatedList joins the argument values with commas, eliding all but the first
+// maxHealthDetails of them.
+func truncatedList(values []string) string {
+	if len(values) <= maxHealthDetails {
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprintf(
+		"%s, and %d more",
+		strings.Join(values[:maxHealthDetails], ", "),
+		len(values)-maxHealthDetails,
+	)
+}
+
+func intsToStrings(values []int) []string {
+	strs := []string{}
+	for _, value := range values {
+		strs = append(strs, fmt.Sprintf("%d", value))
+	}
+	return strs
+}
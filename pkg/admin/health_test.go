@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetClusterHealth(t *testing.T) {
+	brokers := []BrokerInfo{
+		{ID: 2},
+		{ID: 1},
+		{
+			ID: 3,
+			Config: map[string]string{
+				LeaderThrottledKey: "1000000",
+			},
+		},
+	}
+	topics := []TopicInfo{
+		{
+			Name: "topic1",
+			Partitions: []PartitionInfo{
+				{Topic: "topic1", ID: 0, Leader: 1, Replicas: []int{1, 2}, ISR: []int{1, 2}},
+				{Topic: "topic1", ID: 1, Leader: 2, Replicas: []int{2, 3}, ISR: []int{2}},
+				{Topic: "topic1", ID: 2, Leader: -1, Replicas: []int{4}, ISR: []int{}},
+			},
+		},
+		{
+			Name: "topic2",
+			Config: map[string]string{
+				LeaderReplicasThrottledKey: "0:1",
+			},
+			Partitions: []PartitionInfo{
+				// Replica 3 is being added, so this partition isn't under-replicated
+				{Topic: "topic2", ID: 0, Leader: 1, Replicas: []int{1, 2, 3}, ISR: []int{1, 2}},
+			},
+		},
+	}
+	reassignments := []PartitionReassignment{
+		{
+			Topic:          "topic2",
+			Partition:      0,
+			Replicas:       []int{1, 2, 3},
+			AddingReplicas: []int{3},
+		},
+	}
+
+	health := GetClusterHealth(1, brokers, topics, reassignments)
+	assert.Equal(t, []int{1, 2, 3}, health.BrokerIDs)
+	assert.Equal(t, 2, health.Topics)
+	assert.Equal(t, 4, health.Partitions)
+	assert.Equal(t, []PartitionInfo{topics[0].Partitions[2]}, health.OfflinePartitions)
+	assert.Equal(t, []PartitionInfo{topics[0].Partitions[1]}, health.UnderReplicatedPartitions)
+	assert.Equal(t, []string{"topic2"}, health.ThrottledTopics)
+	assert.Equal(t, []int{3}, health.ThrottledBrokers)
+	assert.True(t, health.HasController())
+	assert.False(t, health.Healthy())
+	assert.Equal(
+		t,
+		[]HealthCheck{
+			{
+				Name:    "Controller",
+				Status:  HealthStatusOK,
+				Details: "Broker 1",
+			},
+			{
+				Name:    "Offline partitions",
+				Status:  HealthStatusUnhealthy,
+				Details: "1/4 partitions: topic1 (1)",
+			},
+			{
+				Name:    "Under-replicated partitions",
+				Status:  HealthStatusUnhealthy,
+				Details: "1/4 partitions: topic1 (1)",
+			},
+			{
+				Name:    "In-progress reassignments",
+				Status:  HealthStatusWarning,
+				Details: "1 partition(s) in 1 topic(s): topic2",
+			},
+			{
+				Name:    "Throttled topics",
+				Status:  HealthStatusWarning,
+				Details: "1/2: topic2",
+			},
+			{
+				Name:    "Throttled brokers",
+				Status:  HealthStatusWarning,
+				Details: "1/3: 3",
+			},
+		},
+		health.Checks(),
+	)
+	assert.Equal(
+		t,
+		[]string{
+			"offline partitions: 1/4 partitions: topic1 (1)",
+			"under-replicated partitions: 1/4 partitions: topic1 (1)",
+		},
+		health.Problems(),
+	)
+
+	// Warnings alone don't make the cluster unhealthy
+	healthyHealth := GetClusterHealth(2, brokers, topics[1:], reassignments)
+	assert.True(t, healthyHealth.Healthy())
+
+	noControllerHealth := GetClusterHealth(-1, brokers, nil, nil)
+	assert.False(t, noControllerHealth.HasController())
+	assert.Equal(
+		t,
+		[]string{"controller: No active controller"},
+		noControllerHealth.Problems(),
+	)
+	assert.Equal(t, []PartitionReassignment{}, noControllerHealth.Reassignments)
+}
@@ -0,0 +1,5 @@
+package admin
+
+import "github.com/segmentio/topicctl/pkg/logging"
+
+var log = logging.Logger(logging.SubsystemAdmin)
@@ -0,0 +1,254 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+)
+
+// kafka-go doesn't implement the DescribeLogDirs API, so the message types are registered
+// here. Versions 2 and up use the "flexible" format, which kafka-go enables because of the tagged
+// fields. Version 4, which is supported by brokers from 3.3 onwards, adds the total and usable
+// bytes of each log dir's volume.
+func init() {
+	protocol.Register(&describeLogDirsRequest{}, &describeLogDirsResponse{})
+}
+
+// Detailed API definition: https://kafka.apache.org/protocol#The_Messages_DescribeLogDirs
+type describeLogDirsRequest struct {
+	// We need at least one tagged field to indicate that v2+ uses "flexible" messages.
+	_ struct{} `kafka:"min=v2,max=v4,tag"`
+
+	Topics []describeLogDirsRequestTopic `kafka:"min=v0,max=v4,nullable"`
+
+	// brokerID is the broker that the request is sent to; since it's unexported, it isn't
+	// serialized.
+	brokerID int32
+}
+
+func (r *describeLogDirsRequest) ApiKey() protocol.ApiKey { return protocol.DescribeLogDirs }
+
+func (r *describeLogDirsRequest) Broker(cluster protocol.Cluster) (protocol.Broker, error) {
+	broker, ok := cluster.Brokers[r.brokerID]
+	if !ok {
+		return protocol.Broker{}, fmt.Errorf("Broker %d is not in the cluster metadata", r.brokerID)
+	}
+	return broker, nil
+}
+
+type describeLogDirsRequestTopic struct {
+	_ struct{} `kafka:"min=v2,max=v4,tag"`
+
+	Topic      string  `kafka:"min=v0,max=v1|min=v2,max=v4,compact"`
+	Partitions []int32 `kafka:"min=v0,max=v4"`
+}
+
+type describeLogDirsResponse struct {
+	_ struct{} `kafka:"min=v2,max=v4,tag"`
+
+	ThrottleTimeMs int32                           `kafka:"min=v0,max=v4"`
+	ErrorCode      int16                           `kafka:"min=v3,max=v4"`
+	Results        []describeLogDirsResponseResult `kafka:"min=v0,max=v4"`
+}
+
+func (r *describeLogDirsResponse) ApiKey() protocol.ApiKey { return protocol.DescribeLogDirs }
+
+type describeLogDirsResponseResult struct {
+	_ struct{} `kafka:"min=v2,max=v4,tag"`
+
+	ErrorCode   int16                          `kafka:"min=v0,max=v4"`
+	LogDir      string                         `kafka:"min=v0,max=v1|min=v2,max=v4,compact"`
+	Topics      []describeLogDirsResponseTopic `kafka:"min=v0,max=v4"`
+	TotalBytes  int64                          `kafka:"min=v4,max=v4"`
+	UsableBytes int64                          `kafka:"min=v4,max=v4"`
+}
+
+type describeLogDirsResponseTopic struct {
+	_ struct{} `kafka:"min=v2,max=v4,tag"`
+
+	Name       string                             `kafka:"min=v0,max=v1|min=v2,max=v4,compact"`
+	Partitions []describeLogDirsResponsePartition `kafka:"min=v0,max=v4"`
+}
+
+type describeLogDirsResponsePartition struct {
+	_ struct{} `kafka:"min=v2,max=v4,tag"`
+
+	PartitionIndex int32 `kafka:"min=v0,max=v4"`
+	PartitionSize  int64 `kafka:"min=v0,max=v4"`
+	OffsetLag      int64 `kafka:"min=v0,max=v4"`
+	IsFutureKey    bool  `kafka:"min=v0,max=v4"`
+}
+
+// describeReplicaLogDirs gets the log dir details of the replicas of the argument topics on
+// each of the argument brokers via the DescribeLogDirs API. It's shared by both client
+// implementations. The results are sorted by topic, partition, and broker ID.
+func describeReplicaLogDirs(
+	ctx context.Context,
+	client *kafka.Client,
+	topics []string,
+	brokerIDs []int,
+) ([]ReplicaLogDirInfo, error) {
+	logDirs, err := describeLogDirs(ctx, client, topics, brokerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaLogDirs := []ReplicaLogDirInfo{}
+
+	for _, logDir := range logDirs {
+		if logDir.Error != "" {
+			return nil, fmt.Errorf(
+				"Error describing log dir %s on broker %d: %s",
+				logDir.Path,
+				logDir.BrokerID,
+				logDir.Error,
+			)
+		}
+		replicaLogDirs = append(replicaLogDirs, logDir.Replicas...)
+	}
+
+	sort.Slice(replicaLogDirs, func(a, b int) bool {
+		replicaA := replicaLogDirs[a]
+		replicaB := replicaLogDirs[b]
+
+		if replicaA.Topic != replicaB.Topic {
+			return replicaA.Topic < replicaB.Topic
+		}
+		if replicaA.Partition != replicaB.Partition {
+			return replicaA.Partition < replicaB.Partition
+		}
+		return replicaA.BrokerID < replicaB.BrokerID
+	})
+
+	return replicaLogDirs, nil
+}
+
+// describeLogDirs gets the details of each log dir on the argument brokers, including the
+// replicas of the argument topics in it, via the DescribeLogDirs API. If topics is empty, the
+// replicas of all topics are included. Errors for individual log dirs (e.g., because the disk is
+// offline) are stored in the results instead of being returned. The results are sorted by broker
+// ID and then path.
+func describeLogDirs(
+	ctx context.Context,
+	client *kafka.Client,
+	topics []string,
+	brokerIDs []int,
+) ([]LogDirInfo, error) {
+	transport := client.Transport
+	if transport == nil {
+		transport = kafka.DefaultTransport
+	}
+
+	// A nil topics list means all topics; an empty, non-nil list would mean none
+	var requestTopics []describeLogDirsRequestTopic
+	if len(topics) > 0 {
+		topicPartitions, err := client.Metadata(
+			ctx,
+			&kafka.MetadataRequest{
+				Topics: topics,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, topic := range topicPartitions.Topics {
+			if topic.Error != nil {
+				return nil, fmt.Errorf("Error getting metadata for topic %s: %+v", topic.Name, topic.Error)
+			}
+			partitionIDs := []int32{}
+			for _, partition := range topic.Partitions {
+				partitionIDs = append(partitionIDs, int32(partition.ID))
+			}
+			requestTopics = append(
+				requestTopics,
+				describeLogDirsRequestTopic{
+					Topic:      topic.Name,
+					Partitions: partitionIDs,
+				},
+			)
+		}
+	}
+
+	logDirs := []LogDirInfo{}
+
+	for _, brokerID := range brokerIDs {
+		req := &describeLogDirsRequest{
+			Topics:   requestTopics,
+			brokerID: int32(brokerID),
+		}
+		log.Debugf("DescribeLogDirs request for broker %d: %+v", brokerID, req)
+
+		resp, err := transport.RoundTrip(ctx, client.Addr, req)
+		log.Debugf("DescribeLogDirs response for broker %d: %+v (%+v)", brokerID, resp, err)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing log dirs for broker %d: %+v", brokerID, err)
+		}
+
+		logDirsResp, ok := resp.(*describeLogDirsResponse)
+		if !ok {
+			return nil, fmt.Errorf("Unexpected DescribeLogDirs response type: %T", resp)
+		}
+		if logDirsResp.ErrorCode != 0 {
+			return nil, fmt.Errorf(
+				"Error describing log dirs for broker %d: %+v",
+				brokerID,
+				kafka.Error(logDirsResp.ErrorCode),
+			)
+		}
+
+		for _, result := range logDirsResp.Results {
+			logDir := LogDirInfo{
+				BrokerID: brokerID,
+				Path:     result.LogDir,
+				Replicas: []ReplicaLogDirInfo{},
+			}
+			if result.ErrorCode != 0 {
+				logDir.Error = kafka.Error(result.ErrorCode).Error()
+			}
+
+			// Brokers that don't support v4 of the API don't return the volume sizes, and ones
+			// that do return -1 if they're unknown
+			if result.TotalBytes > 0 {
+				logDir.TotalBytes = result.TotalBytes
+			}
+			if result.UsableBytes > 0 {
+				logDir.UsableBytes = result.UsableBytes
+			}
+
+			for _, topic := range result.Topics {
+				for _, partition := range topic.Partitions {
+					logDir.Replicas = append(
+						logDir.Replicas,
+						ReplicaLogDirInfo{
+							Topic:     topic.Name,
+							Partition: int(partition.PartitionIndex),
+							BrokerID:  brokerID,
+							LogDir:    result.LogDir,
+							Size:      partition.PartitionSize,
+							OffsetLag: partition.OffsetLag,
+							IsFuture:  partition.IsFutureKey,
+						},
+					)
+				}
+			}
+
+			logDirs = append(logDirs, logDir)
+		}
+	}
+
+	sort.Slice(logDirs, func(a, b int) bool {
+		logDirA := logDirs[a]
+		logDirB := logDirs[b]
+
+		if logDirA.BrokerID != logDirB.BrokerID {
+			return logDirA.BrokerID < logDirB.BrokerID
+		}
+		return logDirA.Path < logDirB.Path
+	})
+
+	return logDirs, nil
+}
@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	"github.com/segmentio/topicctl/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeLogDirsMessages(t *testing.T) {
+	req := &describeLogDirsRequest{
+		Topics: []describeLogDirsRequestTopic{
+			{
+				Topic:      "topic-1",
+				Partitions: []int32{0, 1, 2},
+			},
+		},
+		brokerID: 3,
+	}
+	resp := &describeLogDirsResponse{
+		ThrottleTimeMs: 10,
+		Results: []describeLogDirsResponseResult{
+			{
+				LogDir: "/var/lib/kafka",
+				Topics: []describeLogDirsResponseTopic{
+					{
+						Name: "topic-1",
+						Partitions: []describeLogDirsResponsePartition{
+							{
+								PartitionIndex: 1,
+								PartitionSize:  1234,
+								OffsetLag:      5,
+							},
+						},
+					},
+				},
+				TotalBytes:  100000,
+				UsableBytes: 40000,
+			},
+		},
+	}
+
+	for _, version := range []int16{0, 1, 2, 3, 4} {
+		buf := &bytes.Buffer{}
+		require.NoError(t, protocol.WriteRequest(buf, version, 1, "test", req))
+		_, _, _, readReq, err := protocol.ReadRequest(buf)
+		require.NoError(t, err)
+
+		// The broker ID is only used for routing, so it isn't serialized
+		assert.Equal(
+			t,
+			&describeLogDirsRequest{Topics: req.Topics},
+			readReq,
+		)
+
+		// The volume sizes are only serialized in v4+
+		expResp := *resp
+		if version < 4 {
+			expResp.Results = []describeLogDirsResponseResult{resp.Results[0]}
+			expResp.Results[0].TotalBytes = 0
+			expResp.Results[0].UsableBytes = 0
+		}
+
+		buf.Reset()
+		require.NoError(t, protocol.WriteResponse(buf, version, 1, resp))
+		_, readResp, err := protocol.ReadResponse(buf, protocol.DescribeLogDirs, version)
+		require.NoError(t, err)
+		assert.Equal(t, &expResp, readResp, "version %d", version)
+	}
+}
+
+func TestBrokerClientGetReplicaLogDirs(t *testing.T) {
+	if !util.CanTestBrokerAdmin() {
+		t.Skip("Skipping because KAFKA_TOPICS_TEST_BROKER_ADMIN is not set")
+	}
+
+	ctx := context.Background()
+	client, err := NewBrokerAdminClient(
+		ctx,
+		BrokerAdminClientConfig{
+			ConnectorConfig: ConnectorConfig{
+				BrokerAddr: util.TestKafkaAddr(),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	topicName := util.RandomString("topic-log-dirs-", 6)
+	err = client.CreateTopic(
+		ctx,
+		kafka.TopicConfig{
+			Topic:             topicName,
+			NumPartitions:     2,
+			ReplicationFactor: 2,
+		},
+	)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+
+	replicaLogDirs, err := client.GetReplicaLogDirs(ctx, []string{topicName}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(replicaLogDirs))
+
+	for _, replicaLogDir := range replicaLogDirs {
+		assert.Equal(t, topicName, replicaLogDir.Topic)
+		assert.NotEqual(t, "", replicaLogDir.LogDir)
+	}
+	assert.Equal(t, 0, replicaLogDirs[0].Partition)
+	assert.Equal(t, 1, replicaLogDirs[3].Partition)
+}
@@ -0,0 +1,166 @@
+package admin
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ClusterMode is the way that a cluster stores its metadata.
+type ClusterMode string
+
+const (
+	// ClusterModeZookeeper is used for clusters that store their metadata in zookeeper.
+	ClusterModeZookeeper ClusterMode = "zookeeper"
+
+	// ClusterModeKRaft is used for clusters that store their metadata in a KRaft quorum.
+	ClusterModeKRaft ClusterMode = "kraft"
+
+	// ClusterModeUnknown is used when none of the brokers could be checked.
+	ClusterModeUnknown ClusterMode = "unknown"
+)
+
+// describeQuorumAPIs are the names of the DescribeQuorum API, which is only served by brokers in
+// KRaft mode. kafka-go doesn't have a name for its key (55), so it's reported as a number.
+var describeQuorumAPIs = []string{"DescribeQuorum", "55"}
+
+// BrokerMetadata contains the basic details of a single broker in a cluster.
+type BrokerMetadata struct {
+	ID   int    `json:"id"`
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+	Rack string `json:"rack"`
+
+	// KafkaVersion is the most recent Kafka version whose APIs are all supported by the broker,
+	// out of the ones checked by compat reports. The broker may be running a later version.
+	KafkaVersion string `json:"kafkaVersion"`
+
+	// Error is set if the API versions couldn't be fetched from the broker.
+	Error string `json:"error,omitempty"`
+}
+
+// ClusterMetadata summarizes the identity and layout of a cluster.
+type ClusterMetadata struct {
+	ClusterID    string           `json:"clusterID"`
+	ControllerID int              `json:"controllerID"`
+	Mode         ClusterMode      `json:"mode"`
+	Brokers      []BrokerMetadata `json:"brokers"`
+}
+
+// GetClusterMetadata combines the argument cluster details and broker API versions into a
+// ClusterMetadata summary. The brokers are sorted by ID.
+func GetClusterMetadata(
+	clusterID string,
+	controllerID int,
+	brokers []BrokerInfo,
+	brokerVersions []BrokerAPIVersions,
+) ClusterMetadata {
+	versionsByID := map[int]BrokerAPIVersions{}
+	for _, versions := range brokerVersions {
+		versionsByID[versions.BrokerID] = versions
+	}
+
+	metadata := ClusterMetadata{
+		ClusterID:    clusterID,
+		ControllerID: controllerID,
+		Mode:         ClusterModeUnknown,
+		Brokers:      []BrokerMetadata{},
+	}
+
+	for _, broker := range brokers {
+		brokerMetadata := BrokerMetadata{
+			ID:   broker.ID,
+			Host: broker.Host,
+			Port: broker.Port,
+			Rack: broker.Rack,
+		}
+
+		versions, ok := versionsByID[broker.ID]
+		if !ok {
+			metadata.Brokers = append(metadata.Brokers, brokerMetadata)
+			continue
+		}
+		if versions.Error != "" {
+			brokerMetadata.Error = versions.Error
+			metadata.Brokers = append(metadata.Brokers, brokerMetadata)
+			continue
+		}
+
+		brokerMetadata.KafkaVersion = inferKafkaVersion(versions)
+
+		if hasDescribeQuorum(versions) {
+			metadata.Mode = ClusterModeKRaft
+		} else if metadata.Mode == ClusterModeUnknown {
+			metadata.Mode = ClusterModeZookeeper
+		}
+
+		metadata.Brokers = append(metadata.Brokers, brokerMetadata)
+	}
+
+	sort.Slice(metadata.Brokers, func(a, b int) bool {
+		return metadata.Brokers[a].ID < metadata.Brokers[b].ID
+	})
+
+	return metadata
+}
+
+func hasDescribeQuorum(versions BrokerAPIVersions) bool {
+	for _, api := range describeQuorumAPIs {
+		if _, ok := versions.MaxVersions[api]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inferKafkaVersion returns the most recent Kafka version out of the ones in CompatFeatures
+// whose features are all supported by the broker, or an empty string if none are.
+func inferKafkaVersion(versions BrokerAPIVersions) string {
+	kafkaVersions := []string{}
+	unsupported := map[string]struct{}{}
+
+	for _, feature := range CompatFeatures {
+		kafkaVersions = append(kafkaVersions, feature.KafkaVersion)
+
+		if maxVersion, ok := versions.MaxVersions[feature.API]; !ok ||
+			maxVersion < feature.MinVersion {
+			unsupported[feature.KafkaVersion] = struct{}{}
+		}
+	}
+
+	sort.Slice(kafkaVersions, func(a, b int) bool {
+		return compareKafkaVersions(kafkaVersions[a], kafkaVersions[b]) < 0
+	})
+
+	var inferred string
+	for _, kafkaVersion := range kafkaVersions {
+		if _, ok := unsupported[kafkaVersion]; ok {
+			break
+		}
+		inferred = kafkaVersion
+	}
+
+	return inferred
+}
+
+// compareKafkaVersions compares two dot-separated Kafka versions, returning a negative number
+// if a is before b, a positive one if it's after, and zero if they're the same.
+func compareKafkaVersions(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aPart, bPart int
+		if i < len(aParts) {
+			aPart, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bPart, _ = strconv.Atoi(bParts[i])
+		}
+		if aPart != bPart {
+			return aPart - bPart
+		}
+	}
+
+	return 0
+}
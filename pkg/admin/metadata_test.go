@@ -0,0 +1,120 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetClusterMetadata(t *testing.T) {
+	newVersions := map[string]int{}
+	for _, feature := range CompatFeatures {
+		newVersions[feature.API] = feature.MinVersion
+	}
+	kraftVersions := map[string]int{"55": 1}
+	for api, version := range newVersions {
+		kraftVersions[api] = version
+	}
+
+	brokers := []BrokerInfo{
+		{
+			ID:   3,
+			Host: "broker3",
+			Port: 9092,
+			Rack: "rack2",
+		},
+		{
+			ID:   1,
+			Host: "broker1",
+			Port: 9092,
+			Rack: "rack1",
+		},
+		{
+			ID:   2,
+			Host: "broker2",
+			Port: 9092,
+			Rack: "rack1",
+		},
+	}
+
+	metadata := GetClusterMetadata(
+		"test-cluster",
+		2,
+		brokers,
+		[]BrokerAPIVersions{
+			{
+				BrokerID:    1,
+				MaxVersions: newVersions,
+			},
+			{
+				BrokerID: 2,
+				MaxVersions: map[string]int{
+					"DescribeAcls":    1,
+					"DescribeConfigs": 2,
+					"DescribeLogDirs": 1,
+				},
+			},
+			{
+				BrokerID: 3,
+				Error:    "connection refused",
+			},
+		},
+	)
+
+	assert.Equal(
+		t,
+		ClusterMetadata{
+			ClusterID:    "test-cluster",
+			ControllerID: 2,
+			Mode:         ClusterModeZookeeper,
+			Brokers: []BrokerMetadata{
+				{
+					ID:           1,
+					Host:         "broker1",
+					Port:         9092,
+					Rack:         "rack1",
+					KafkaVersion: "2.8",
+				},
+				{
+					ID:           2,
+					Host:         "broker2",
+					Port:         9092,
+					Rack:         "rack1",
+					KafkaVersion: "2.0",
+				},
+				{
+					ID:    3,
+					Host:  "broker3",
+					Port:  9092,
+					Rack:  "rack2",
+					Error: "connection refused",
+				},
+			},
+		},
+		metadata,
+	)
+
+	kraftMetadata := GetClusterMetadata(
+		"test-cluster",
+		1,
+		brokers[1:2],
+		[]BrokerAPIVersions{
+			{
+				BrokerID:    1,
+				MaxVersions: kraftVersions,
+			},
+		},
+	)
+	assert.Equal(t, ClusterModeKRaft, kraftMetadata.Mode)
+
+	unknownMetadata := GetClusterMetadata("test-cluster", 1, brokers, nil)
+	assert.Equal(t, ClusterModeUnknown, unknownMetadata.Mode)
+	assert.Equal(t, 3, len(unknownMetadata.Brokers))
+}
+
+func TestCompareKafkaVersions(t *testing.T) {
+	assert.True(t, compareKafkaVersions("0.11", "1.0") < 0)
+	assert.True(t, compareKafkaVersions("2.10", "2.8") > 0)
+	assert.Equal(t, 0, compareKafkaVersions("2.0", "2.0"))
+	assert.True(t, compareKafkaVersions("3", "2.8") > 0)
+}
@@ -0,0 +1,317 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go/sasl"
+)
+
+const (
+	oauthTimeout = 10 * time.Second
+
+	// oauthRefreshFraction is the fraction of a token's lifetime after which it's refreshed in
+	// the background, so that callers don't have to wait for new tokens.
+	oauthRefreshFraction = 0.8
+
+	// oauthMaxExpiryMargin is the maximum amount of time before a token's expiry that it stops
+	// being handed out, to allow for clock skew and connection setup time.
+	oauthMaxExpiryMargin = 30 * time.Second
+
+	// oauthRetryInterval is the minimum time between background refresh attempts after one
+	// fails.
+	oauthRetryInterval = 5 * time.Second
+)
+
+// OAuthConfig stores the settings for getting tokens via the OAuth client credentials flow for
+// connections that authenticate via the SASL OAUTHBEARER mechanism.
+type OAuthConfig struct {
+	// TokenURL is the token endpoint of the identity provider.
+	TokenURL string
+
+	// ClientID and ClientSecret are the credentials of the OAuth client. They're sent to the
+	// token endpoint via HTTP basic auth.
+	ClientID     string
+	ClientSecret string
+
+	// Scopes are the scopes to request, if any.
+	Scopes []string
+
+	// Extensions are the SASL extensions, e.g. logicalCluster and identityPoolId for Confluent
+	// Cloud, that are sent to the brokers along with the token.
+	Extensions map[string]string
+}
+
+// OAuthTokenProvider gets access tokens via the OAuth client credentials flow. Tokens are cached
+// until they're close to expiring and are refreshed in the background ahead of that, so that
+// connections only wait for the token endpoint the first time or if a refresh has failed.
+type OAuthTokenProvider struct {
+	config     OAuthConfig
+	httpClient *http.Client
+	now        func() time.Time
+
+	mutex       sync.Mutex
+	token       string
+	refreshAt   time.Time
+	expiresAt   time.Time
+	refreshing  bool
+	nextAttempt time.Time
+}
+
+type oauthTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// NewOAuthTokenProvider returns a new OAuthTokenProvider for the argument config.
+func NewOAuthTokenProvider(config OAuthConfig) (*OAuthTokenProvider, error) {
+	if config.TokenURL == "" {
+		return nil, errors.New("OAuth token URL must be set")
+	}
+	if config.ClientID == "" || config.ClientSecret == "" {
+		return nil, errors.New("OAuth client ID and secret must be set")
+	}
+
+	return &OAuthTokenProvider{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: oauthTimeout,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// Token returns a valid access token, fetching a new one from the token endpoint if the cached
+// one is missing or about to expire.
+func (p *OAuthTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+
+	if p.token != "" && now.Before(p.expiresAt) {
+		if !now.Before(p.refreshAt) && !p.refreshing && !now.Before(p.nextAttempt) {
+			p.refreshing = true
+			go p.refresh()
+		}
+		return p.token, nil
+	}
+
+	token, err := p.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	p.setToken(token)
+	return p.token, nil
+}
+
+func (p *OAuthTokenProvider) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), oauthTimeout)
+	defer cancel()
+
+	// The current token is still handed out while the new one is being fetched
+	token, err := p.fetch(ctx)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.refreshing = false
+	if err != nil {
+		log.Warnf("Error refreshing OAuth token; will retry: %+v", err)
+		p.nextAttempt = p.now().Add(oauthRetryInterval)
+		return
+	}
+	p.setToken(token)
+}
+
+type oauthToken struct {
+	value     string
+	refreshAt time.Time
+	expiresAt time.Time
+}
+
+// setToken caches the argument token. The mutex must be held.
+func (p *OAuthTokenProvider) setToken(token oauthToken) {
+	p.token = token.value
+	p.refreshAt = token.refreshAt
+	p.expiresAt = token.expiresAt
+	p.nextAttempt = time.Time{}
+}
+
+// fetch gets a new token from the token endpoint.
+func (p *OAuthTokenProvider) fetch(ctx context.Context) (oauthToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(p.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(p.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		p.config.TokenURL,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	log.Debugf("Getting OAuth token for client %s from %s", p.config.ClientID, p.config.TokenURL)
+	requestTime := p.now()
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return oauthToken{}, fmt.Errorf(
+			"Error getting OAuth token from %s: %+v",
+			p.config.TokenURL,
+			err,
+		)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return oauthToken{}, err
+	}
+
+	tokenResp := oauthTokenResponse{}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return oauthToken{}, fmt.Errorf(
+			"Error parsing OAuth token response from %s (status %d): %+v",
+			p.config.TokenURL,
+			resp.StatusCode,
+			err,
+		)
+	}
+	if resp.StatusCode != http.StatusOK || tokenResp.Error != "" {
+		return oauthToken{}, fmt.Errorf(
+			"Got status %d from %s: %s %s",
+			resp.StatusCode,
+			p.config.TokenURL,
+			tokenResp.Error,
+			tokenResp.ErrorDescription,
+		)
+	}
+	if tokenResp.AccessToken == "" {
+		return oauthToken{}, fmt.Errorf(
+			"OAuth token response from %s doesn't have a token",
+			p.config.TokenURL,
+		)
+	}
+
+	lifetime := time.Duration(tokenResp.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		expiry, err := jwtExpiry(tokenResp.AccessToken)
+		if err != nil {
+			return oauthToken{}, fmt.Errorf(
+				"OAuth token response from %s doesn't have an expiry: %+v",
+				p.config.TokenURL,
+				err,
+			)
+		}
+		lifetime = expiry.Sub(requestTime)
+	}
+	if lifetime <= 0 {
+		return oauthToken{}, fmt.Errorf("OAuth token from %s is already expired", p.config.TokenURL)
+	}
+
+	expiryMargin := lifetime / 10
+	if expiryMargin > oauthMaxExpiryMargin {
+		expiryMargin = oauthMaxExpiryMargin
+	}
+
+	log.Debugf("Got OAuth token that expires in %s", lifetime)
+	return oauthToken{
+		value:     tokenResp.AccessToken,
+		refreshAt: requestTime.Add(time.Duration(float64(lifetime) * oauthRefreshFraction)),
+		expiresAt: requestTime.Add(lifetime - expiryMargin),
+	}, nil
+}
+
+// jwtExpiry gets the expiry time from the exp claim of the argument JWT. It's only used for
+// token endpoints that don't return expires_in; the token's signature isn't checked.
+func jwtExpiry(token string) (time.Time, error) {
+	elements := strings.Split(token, ".")
+	if len(elements) != 3 {
+		return time.Time{}, errors.New("Token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(elements[1], "="))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, err
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, errors.New("JWT doesn't have an exp claim")
+	}
+
+	return time.Unix(claims.Exp, 0), nil
+}
+
+// oauthBearerMechanism is a sasl.Mechanism that authenticates via the SASL OAUTHBEARER mechanism
+// (RFC 7628) using the tokens from an OAuthTokenProvider.
+type oauthBearerMechanism struct {
+	provider   *OAuthTokenProvider
+	extensions map[string]string
+}
+
+var _ sasl.Mechanism = (*oauthBearerMechanism)(nil)
+
+// Name returns the name of the mechanism in the SASL handshake.
+func (m *oauthBearerMechanism) Name() string {
+	return "OAUTHBEARER"
+}
+
+// Start returns the client's initial response, which contains the token and any extensions.
+func (m *oauthBearerMechanism) Start(ctx context.Context) (sasl.StateMachine, []byte, error) {
+	token, err := m.provider.Token(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := []string{}
+	for key := range m.extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "n,,\x01auth=Bearer %s\x01", token)
+	for _, key := range keys {
+		fmt.Fprintf(buf, "%s=%s\x01", key, m.extensions[key])
+	}
+	buf.WriteString("\x01")
+
+	return m, []byte(buf.String()), nil
+}
+
+// Next processes the broker's reply to the initial response. The reply is empty if the token
+// was accepted; otherwise, it's a JSON error.
+func (m *oauthBearerMechanism) Next(ctx context.Context, challenge []byte) (bool, []byte, error) {
+	if len(challenge) > 0 {
+		return false, nil, fmt.Errorf("OAUTHBEARER authentication failed: %s", challenge)
+	}
+	return true, nil, nil
+}
@@ -0,0 +1,222 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTokenEndpoint struct {
+	sync.Mutex
+
+	server    *httptest.Server
+	requests  int
+	expiresIn int
+	tokens    []string
+	fail      bool
+}
+
+func newFakeTokenEndpoint(t *testing.T) *fakeTokenEndpoint {
+	endpoint := &fakeTokenEndpoint{expiresIn: 100}
+
+	endpoint.server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			endpoint.Lock()
+			defer endpoint.Unlock()
+
+			clientID, clientSecret, _ := r.BasicAuth()
+			assert.Equal(t, "test-client", clientID)
+			assert.Equal(t, "test-secret", clientSecret)
+			assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+			assert.Equal(t, "kafka offline", r.FormValue("scope"))
+
+			if endpoint.fail {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error": "invalid_client", "error_description": "bad secret"}`)
+				return
+			}
+
+			endpoint.requests++
+			token := fmt.Sprintf("token-%d", endpoint.requests)
+			if len(endpoint.tokens) > 0 {
+				token = endpoint.tokens[0]
+				endpoint.tokens = endpoint.tokens[1:]
+			}
+
+			fmt.Fprintf(
+				w,
+				`{"access_token": "%s", "token_type": "Bearer", "expires_in": %d}`,
+				token,
+				endpoint.expiresIn,
+			)
+		}),
+	)
+	t.Cleanup(endpoint.server.Close)
+
+	return endpoint
+}
+
+func (e *fakeTokenEndpoint) requestCount() int {
+	e.Lock()
+	defer e.Unlock()
+	return e.requests
+}
+
+func TestOAuthTokenProvider(t *testing.T) {
+	ctx := context.Background()
+	endpoint := newFakeTokenEndpoint(t)
+
+	_, err := NewOAuthTokenProvider(OAuthConfig{TokenURL: endpoint.server.URL})
+	assert.Error(t, err)
+
+	provider, err := NewOAuthTokenProvider(
+		OAuthConfig{
+			TokenURL:     endpoint.server.URL,
+			ClientID:     "test-client",
+			ClientSecret: "test-secret",
+			Scopes:       []string{"kafka", "offline"},
+		},
+	)
+	require.NoError(t, err)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	var nowMutex sync.Mutex
+	provider.now = func() time.Time {
+		nowMutex.Lock()
+		defer nowMutex.Unlock()
+		return now
+	}
+	setNow := func(value time.Time) {
+		nowMutex.Lock()
+		defer nowMutex.Unlock()
+		now = value
+	}
+	start := now
+
+	token, err := provider.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, 1, endpoint.requestCount())
+
+	// The token is cached until it's due to be refreshed
+	setNow(start.Add(50 * time.Second))
+	token, err = provider.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, 1, endpoint.requestCount())
+
+	// After 80% of the lifetime, the old token is still returned while a new one is fetched in
+	// the background
+	setNow(start.Add(85 * time.Second))
+	token, err = provider.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	require.Eventually(
+		t,
+		func() bool {
+			token, err := provider.Token(ctx)
+			return err == nil && token == "token-2"
+		},
+		5*time.Second,
+		10*time.Millisecond,
+	)
+	assert.Equal(t, 2, endpoint.requestCount())
+
+	// If the token has expired, e.g. because refreshes failed, a new one is fetched right away
+	setNow(start.Add(300 * time.Second))
+	token, err = provider.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-3", token)
+
+	endpoint.Lock()
+	endpoint.fail = true
+	endpoint.Unlock()
+
+	setNow(start.Add(500 * time.Second))
+	_, err = provider.Token(ctx)
+	assert.Error(t, err)
+}
+
+func TestOAuthTokenProviderJWTExpiry(t *testing.T) {
+	ctx := context.Background()
+	endpoint := newFakeTokenEndpoint(t)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	payload := base64.RawURLEncoding.EncodeToString(
+		[]byte(fmt.Sprintf(`{"sub": "test-client", "exp": %d}`, now.Add(time.Hour).Unix())),
+	)
+
+	endpoint.expiresIn = 0
+	endpoint.tokens = []string{"header." + payload + ".signature", "not-a-jwt"}
+
+	provider, err := NewOAuthTokenProvider(
+		OAuthConfig{
+			TokenURL:     endpoint.server.URL,
+			ClientID:     "test-client",
+			ClientSecret: "test-secret",
+			Scopes:       []string{"kafka", "offline"},
+		},
+	)
+	require.NoError(t, err)
+	provider.now = func() time.Time { return now }
+
+	token, err := provider.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "header."+payload+".signature", token)
+	assert.Equal(t, now.Add(48*time.Minute), provider.refreshAt)
+	assert.Equal(t, now.Add(time.Hour-30*time.Second), provider.expiresAt)
+
+	// Tokens without any expiry are rejected
+	now = now.Add(2 * time.Hour)
+	_, err = provider.Token(ctx)
+	assert.Error(t, err)
+}
+
+func TestOAuthBearerMechanism(t *testing.T) {
+	ctx := context.Background()
+	endpoint := newFakeTokenEndpoint(t)
+
+	provider, err := NewOAuthTokenProvider(
+		OAuthConfig{
+			TokenURL:     endpoint.server.URL,
+			ClientID:     "test-client",
+			ClientSecret: "test-secret",
+			Scopes:       []string{"kafka", "offline"},
+		},
+	)
+	require.NoError(t, err)
+
+	mechanism := &oauthBearerMechanism{
+		provider: provider,
+		extensions: map[string]string{
+			"logicalCluster": "lkc-123",
+			"identityPoolId": "pool-456",
+		},
+	}
+	assert.Equal(t, "OAUTHBEARER", mechanism.Name())
+
+	stateMachine, initialResponse, err := mechanism.Start(ctx)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"n,,\x01auth=Bearer token-1\x01identityPoolId=pool-456\x01logicalCluster=lkc-123\x01\x01",
+		string(initialResponse),
+	)
+
+	done, response, err := stateMachine.Next(ctx, nil)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Empty(t, response)
+
+	_, _, err = stateMachine.Next(ctx, []byte(`{"status":"invalid_token"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,255 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// QuotaEntityDefault is the name used for the default user or client ID, i.e. the one that
+	// applies to all users or clients that don't have their own quotas. This matches the
+	// representation in kafka-configs.sh.
+	QuotaEntityDefault = "<default>"
+
+	quotaEntityTypeUser     = "user"
+	quotaEntityTypeClientID = "client-id"
+)
+
+const (
+	// QuotaKeyProducerByteRate is the maximum number of bytes per second that an entity can
+	// produce to each broker.
+	QuotaKeyProducerByteRate = "producer_byte_rate"
+
+	// QuotaKeyConsumerByteRate is the maximum number of bytes per second that an entity can
+	// fetch from each broker.
+	QuotaKeyConsumerByteRate = "consumer_byte_rate"
+
+	// QuotaKeyRequestPercentage is the maximum percentage of each broker's request handler and
+	// network threads that an entity can use.
+	QuotaKeyRequestPercentage = "request_percentage"
+
+	// QuotaKeyControllerMutationRate is the maximum rate of partition creations and deletions
+	// that an entity can make.
+	QuotaKeyControllerMutationRate = "controller_mutation_rate"
+)
+
+// AllQuotaKeys contains all of the supported quota keys.
+var AllQuotaKeys = []string{
+	QuotaKeyProducerByteRate,
+	QuotaKeyConsumerByteRate,
+	QuotaKeyRequestPercentage,
+	QuotaKeyControllerMutationRate,
+}
+
+// QuotaEntity identifies the user and/or client ID that a quota applies to. A blank field means
+// that the quota isn't specific to that dimension, and QuotaEntityDefault represents the
+// default entity.
+type QuotaEntity struct {
+	User     string `json:"user,omitempty"`
+	ClientID string `json:"clientID,omitempty"`
+}
+
+// String returns a human-readable representation of the entity.
+func (e QuotaEntity) String() string {
+	if e.User != "" && e.ClientID != "" {
+		return fmt.Sprintf("user=%s,client-id=%s", e.User, e.ClientID)
+	} else if e.User != "" {
+		return fmt.Sprintf("user=%s", e.User)
+	}
+	return fmt.Sprintf("client-id=%s", e.ClientID)
+}
+
+// QuotaInfo stores the quota values for a single entity.
+type QuotaInfo struct {
+	Entity QuotaEntity        `json:"entity"`
+	Values map[string]float64 `json:"values"`
+}
+
+// QuotaUpdate is a change to the quotas of a single entity. The keys in Values are set, and the
+// ones in RemovedKeys are removed.
+type QuotaUpdate struct {
+	Entity      QuotaEntity
+	Values      map[string]float64
+	RemovedKeys []string
+}
+
+// SortQuotas sorts the argument quotas by user and then client ID.
+func SortQuotas(quotas []QuotaInfo) {
+	sort.Slice(quotas, func(a, b int) bool {
+		entityA := quotas[a].Entity
+		entityB := quotas[b].Entity
+
+		if entityA.User != entityB.User {
+			return entityA.User < entityB.User
+		}
+		return entityA.ClientID < entityB.ClientID
+	})
+}
+
+// describeQuotas gets all of the client quotas in the cluster via the DescribeClientQuotas
+// API. It's shared by both client implementations.
+func describeQuotas(ctx context.Context, client *kafka.Client) ([]QuotaInfo, error) {
+	req := &kafka.DescribeClientQuotasRequest{
+		Components: []kafka.DescribeClientQuotasRequestComponent{},
+	}
+	log.Debugf("DescribeClientQuotas request: %+v", req)
+
+	resp, err := client.DescribeClientQuotas(ctx, req)
+	log.Debugf("DescribeClientQuotas response: %+v (%+v)", resp, err)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("Error describing quotas: %+v", resp.Error)
+	}
+
+	quotas := []QuotaInfo{}
+
+	for _, entry := range resp.Entries {
+		var entity QuotaEntity
+		var supported bool
+
+		for _, respEntity := range entry.Entities {
+			name := respEntity.EntityName
+			if name == "" {
+				name = QuotaEntityDefault
+			}
+
+			switch respEntity.EntityType {
+			case quotaEntityTypeUser:
+				entity.User = name
+				supported = true
+			case quotaEntityTypeClientID:
+				entity.ClientID = name
+				supported = true
+			default:
+				// Other entity types (e.g., ip) aren't supported yet
+				supported = false
+			}
+			if !supported {
+				break
+			}
+		}
+		if !supported {
+			log.Debugf("Skipping quota entry with unsupported entities: %+v", entry.Entities)
+			continue
+		}
+
+		quota := QuotaInfo{
+			Entity: entity,
+			Values: map[string]float64{},
+		}
+		for _, value := range entry.Values {
+			quota.Values[value.Key] = value.Value
+		}
+		quotas = append(quotas, quota)
+	}
+
+	SortQuotas(quotas)
+	return quotas, nil
+}
+
+// alterQuotas applies the argument updates via the AlterClientQuotas API. It's shared by both
+// client implementations.
+func alterQuotas(
+	ctx context.Context,
+	client *kafka.Client,
+	updates []QuotaUpdate,
+) error {
+	entries := []kafka.AlterClientQuotaEntry{}
+
+	for _, update := range updates {
+		entry := kafka.AlterClientQuotaEntry{
+			Entities: quotaEntities(update.Entity),
+			Ops:      []kafka.AlterClientQuotaOps{},
+		}
+
+		keys := []string{}
+		for key := range update.Values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			entry.Ops = append(
+				entry.Ops,
+				kafka.AlterClientQuotaOps{
+					Key:   key,
+					Value: update.Values[key],
+				},
+			)
+		}
+		for _, key := range update.RemovedKeys {
+			entry.Ops = append(
+				entry.Ops,
+				kafka.AlterClientQuotaOps{
+					Key:    key,
+					Remove: true,
+				},
+			)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	req := &kafka.AlterClientQuotasRequest{
+		Entries: entries,
+	}
+	log.Debugf("AlterClientQuotas request: %+v", req)
+
+	resp, err := client.AlterClientQuotas(ctx, req)
+	log.Debugf("AlterClientQuotas response: %+v (%+v)", resp, err)
+	if err != nil {
+		return err
+	}
+
+	var alterErr error
+	for _, entry := range resp.Entries {
+		if entry.Error != nil {
+			alterErr = multierror.Append(
+				alterErr,
+				fmt.Errorf("Error updating quotas for %+v: %+v", entry.Entities, entry.Error),
+			)
+		}
+	}
+
+	return alterErr
+}
+
+func quotaEntities(entity QuotaEntity) []kafka.AlterClientQuotaEntity {
+	entities := []kafka.AlterClientQuotaEntity{}
+
+	// Names are left blank (i.e., null) for the default entities
+	if entity.User != "" {
+		name := entity.User
+		if name == QuotaEntityDefault {
+			name = ""
+		}
+		entities = append(
+			entities,
+			kafka.AlterClientQuotaEntity{
+				EntityType: quotaEntityTypeUser,
+				EntityName: name,
+			},
+		)
+	}
+	if entity.ClientID != "" {
+		name := entity.ClientID
+		if name == QuotaEntityDefault {
+			name = ""
+		}
+		entities = append(
+			entities,
+			kafka.AlterClientQuotaEntity{
+				EntityType: quotaEntityTypeClientID,
+				EntityName: name,
+			},
+		)
+	}
+
+	return entities
+}
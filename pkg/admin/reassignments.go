@@ -0,0 +1,358 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol/alterpartitionreassignments"
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// PartitionReassignment describes an in-progress reassignment of a single partition.
+type PartitionReassignment struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+
+	// Replicas is the full replica set of the partition while the reassignment is in progress.
+	Replicas []int `json:"replicas"`
+
+	// AddingReplicas are the brokers that the partition is being moved to, and RemovingReplicas
+	// are the ones that it's being moved from.
+	AddingReplicas   []int `json:"addingReplicas"`
+	RemovingReplicas []int `json:"removingReplicas"`
+}
+
+// SortReassignments sorts the argument reassignments by topic and then partition.
+func SortReassignments(reassignments []PartitionReassignment) {
+	sort.Slice(reassignments, func(a, b int) bool {
+		if reassignments[a].Topic != reassignments[b].Topic {
+			return reassignments[a].Topic < reassignments[b].Topic
+		}
+		return reassignments[a].Partition < reassignments[b].Partition
+	})
+}
+
+// listPartitionReassignments gets the in-progress reassignments in the cluster via the
+// ListPartitionReassignments API, which is supported by brokers from 2.4 onwards. If topic is
+// empty, the reassignments for all topics are returned.
+func listPartitionReassignments(
+	ctx context.Context,
+	client *kafka.Client,
+	topic string,
+) ([]PartitionReassignment, error) {
+	req := &kafka.ListPartitionReassignmentsRequest{}
+	log.Debugf("ListPartitionReassignments request: %+v", req)
+
+	resp, err := client.ListPartitionReassignments(ctx, req)
+	log.Debugf("ListPartitionReassignments response: %+v (%+v)", resp, err)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("Error listing partition reassignments: %+v", resp.Error)
+	}
+
+	reassignments := []PartitionReassignment{}
+
+	for topicName, respTopic := range resp.Topics {
+		if topic != "" && topicName != topic {
+			continue
+		}
+
+		for _, partition := range respTopic.Partitions {
+			reassignments = append(
+				reassignments,
+				PartitionReassignment{
+					Topic:            topicName,
+					Partition:        partition.PartitionIndex,
+					Replicas:         util.CopyInts(partition.Replicas),
+					AddingReplicas:   util.CopyInts(partition.AddingReplicas),
+					RemovingReplicas: util.CopyInts(partition.RemovingReplicas),
+				},
+			)
+		}
+	}
+
+	SortReassignments(reassignments)
+	return reassignments, nil
+}
+
+// cancelPartitionReassignments cancels the argument in-progress reassignments via the
+// AlterPartitionReassignments API, which is supported by brokers from 2.4 onwards. The
+// high-level kafka-go client always sends a non-null replica list, which the brokers interpret
+// as a new reassignment, so the request is built from the protocol types instead.
+func cancelPartitionReassignments(
+	ctx context.Context,
+	client *kafka.Client,
+	reassignments []PartitionReassignment,
+) error {
+	transport := client.Transport
+	if transport == nil {
+		transport = kafka.DefaultTransport
+	}
+
+	topicIndices := map[string]int{}
+	req := &alterpartitionreassignments.Request{
+		TimeoutMs: int32(defaultTimeout.Milliseconds()),
+	}
+
+	for _, reassignment := range reassignments {
+		index, ok := topicIndices[reassignment.Topic]
+		if !ok {
+			req.Topics = append(
+				req.Topics,
+				alterpartitionreassignments.RequestTopic{
+					Name: reassignment.Topic,
+				},
+			)
+			index = len(req.Topics) - 1
+			topicIndices[reassignment.Topic] = index
+		}
+
+		// A null replica list cancels the reassignment
+		req.Topics[index].Partitions = append(
+			req.Topics[index].Partitions,
+			alterpartitionreassignments.RequestPartition{
+				PartitionIndex: int32(reassignment.Partition),
+				Replicas:       nil,
+			},
+		)
+	}
+	log.Debugf("AlterPartitionReassignments request: %+v", req)
+
+	resp, err := transport.RoundTrip(ctx, client.Addr, req)
+	log.Debugf("AlterPartitionReassignments response: %+v (%+v)", resp, err)
+	if err != nil {
+		return err
+	}
+
+	alterResp, ok := resp.(*alterpartitionreassignments.Response)
+	if !ok {
+		return fmt.Errorf("Unexpected AlterPartitionReassignments response type: %T", resp)
+	}
+	if alterResp.ErrorCode != 0 {
+		return fmt.Errorf(
+			"Error cancelling partition reassignments: %+v",
+			kafka.Error(alterResp.ErrorCode),
+		)
+	}
+
+	var cancelErr error
+
+	for _, result := range alterResp.Results {
+		for _, partition := range result.Partitions {
+			// The reassignment may have finished in the meantime
+			if partition.ErrorCode == 0 ||
+				kafka.Error(partition.ErrorCode) == kafka.NoReassignmentInProgress {
+				continue
+			}
+			cancelErr = multierror.Append(
+				cancelErr,
+				fmt.Errorf(
+					"Error cancelling reassignment for topic %s, partition %d: %+v",
+					result.Name,
+					partition.PartitionIndex,
+					kafka.Error(partition.ErrorCode),
+				),
+			)
+		}
+	}
+
+	return cancelErr
+}
+
+// reassignmentFromTarget generates a reassignment from the current and target replicas of a
+// partition, as stored in zookeeper. The replica set during the reassignment is the union of
+// the two.
+func reassignmentFromTarget(
+	topic string,
+	partition int,
+	current []int,
+	target []int,
+) PartitionReassignment {
+	reassignment := PartitionReassignment{
+		Topic:            topic,
+		Partition:        partition,
+		Replicas:         util.CopyInts(target),
+		AddingReplicas:   []int{},
+		RemovingReplicas: []int{},
+	}
+
+	targetSet := map[int]struct{}{}
+	for _, replica := range target {
+		targetSet[replica] = struct{}{}
+	}
+	currentSet := map[int]struct{}{}
+	for _, replica := range current {
+		currentSet[replica] = struct{}{}
+	}
+
+	for _, replica := range target {
+		if _, ok := currentSet[replica]; !ok {
+			reassignment.AddingReplicas = append(reassignment.AddingReplicas, replica)
+		}
+	}
+	for _, replica := range current {
+		if _, ok := targetSet[replica]; !ok {
+			reassignment.RemovingReplicas = append(reassignment.RemovingReplicas, replica)
+			reassignment.Replicas = append(reassignment.Replicas, replica)
+		}
+	}
+
+	return reassignment
+}
+
+// ReassignmentProgress is the estimated progress of an in-progress partition reassignment,
+// based on how far the replicas being added lag behind the leader.
+type ReassignmentProgress struct {
+	PartitionReassignment
+
+	Leader int `json:"leader"`
+
+	// LeaderSize is the size of the leader's replica in bytes, or -1 if it isn't known.
+	LeaderSize int64 `json:"leaderSize"`
+
+	// AddingSizes maps from the ID of each replica being added to its current size in bytes.
+	// Replicas with unknown sizes are omitted.
+	AddingSizes map[int]int64 `json:"addingSizes"`
+
+	// InSyncAddingReplicas are the replicas being added that have already caught up and joined
+	// the ISR.
+	InSyncAddingReplicas []int `json:"inSyncAddingReplicas"`
+}
+
+// RemainingBytes returns the number of bytes that the replicas being added still need to copy
+// from the leader, or -1 if this isn't known.
+func (r ReassignmentProgress) RemainingBytes() int64 {
+	var remaining int64
+
+	for _, replica := range r.AddingReplicas {
+		if r.inSync(replica) {
+			continue
+		}
+
+		size, ok := r.AddingSizes[replica]
+		if !ok || r.LeaderSize < 0 {
+			return -1
+		}
+		if size < r.LeaderSize {
+			remaining += r.LeaderSize - size
+		}
+	}
+
+	return remaining
+}
+
+// Fraction returns the estimated fraction of the reassignment that's complete, between 0 and 1,
+// or -1 if this isn't known. Replicas that are in the ISR are treated as complete, and the
+// others as complete in proportion to their size relative to that of the leader. Since the
+// leader may still be receiving writes, replicas that aren't in sync are never counted as
+// fully complete.
+func (r ReassignmentProgress) Fraction() float64 {
+	if len(r.AddingReplicas) == 0 {
+		// Only waiting for the old replicas to be removed
+		return 1.0
+	}
+
+	var total float64
+
+	for _, replica := range r.AddingReplicas {
+		if r.inSync(replica) {
+			total += 1.0
+			continue
+		}
+
+		size, ok := r.AddingSizes[replica]
+		if !ok || r.LeaderSize < 0 {
+			return -1
+		}
+		if r.LeaderSize > 0 {
+			total += math.Min(float64(size)/float64(r.LeaderSize), 0.99)
+		}
+	}
+
+	return total / float64(len(r.AddingReplicas))
+}
+
+func (r ReassignmentProgress) inSync(replica int) bool {
+	for _, inSyncReplica := range r.InSyncAddingReplicas {
+		if inSyncReplica == replica {
+			return true
+		}
+	}
+	return false
+}
+
+// GetReassignmentProgress estimates the progress of each of the argument reassignments from the
+// current state of the topics being reassigned and the sizes of their replicas. The results are
+// in the same order as the argument reassignments.
+func GetReassignmentProgress(
+	reassignments []PartitionReassignment,
+	topics []TopicInfo,
+	replicaLogDirs []ReplicaLogDirInfo,
+) []ReassignmentProgress {
+	partitions := map[string]map[int]PartitionInfo{}
+	for _, topic := range topics {
+		partitions[topic.Name] = map[int]PartitionInfo{}
+		for _, partition := range topic.Partitions {
+			partitions[topic.Name][partition.ID] = partition
+		}
+	}
+
+	// Map from topic -> partition -> broker ID -> replica size
+	replicaSizes := map[string]map[int]map[int]int64{}
+	for _, replicaLogDir := range replicaLogDirs {
+		// Future replicas are the targets of in-progress moves between log dirs
+		if replicaLogDir.IsFuture {
+			continue
+		}
+		if _, ok := replicaSizes[replicaLogDir.Topic]; !ok {
+			replicaSizes[replicaLogDir.Topic] = map[int]map[int]int64{}
+		}
+		if _, ok := replicaSizes[replicaLogDir.Topic][replicaLogDir.Partition]; !ok {
+			replicaSizes[replicaLogDir.Topic][replicaLogDir.Partition] = map[int]int64{}
+		}
+		replicaSizes[replicaLogDir.Topic][replicaLogDir.Partition][replicaLogDir.BrokerID] =
+			replicaLogDir.Size
+	}
+
+	progresses := []ReassignmentProgress{}
+
+	for _, reassignment := range reassignments {
+		partition := partitions[reassignment.Topic][reassignment.Partition]
+		sizes := replicaSizes[reassignment.Topic][reassignment.Partition]
+
+		progress := ReassignmentProgress{
+			PartitionReassignment: reassignment,
+			Leader:                partition.Leader,
+			LeaderSize:            -1,
+			AddingSizes:           map[int]int64{},
+			InSyncAddingReplicas:  []int{},
+		}
+		if leaderSize, ok := sizes[partition.Leader]; ok {
+			progress.LeaderSize = leaderSize
+		}
+
+		isrSet := map[int]struct{}{}
+		for _, replica := range partition.ISR {
+			isrSet[replica] = struct{}{}
+		}
+
+		for _, replica := range reassignment.AddingReplicas {
+			if size, ok := sizes[replica]; ok {
+				progress.AddingSizes[replica] = size
+			}
+			if _, ok := isrSet[replica]; ok {
+				progress.InSyncAddingReplicas = append(progress.InSyncAddingReplicas, replica)
+			}
+		}
+
+		progresses = append(progresses, progress)
+	}
+
+	return progresses
+}
@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReassignmentFromTarget(t *testing.T) {
+	assert.Equal(
+		t,
+		PartitionReassignment{
+			Topic:            "topic1",
+			Partition:        2,
+			Replicas:         []int{3, 4, 1},
+			AddingReplicas:   []int{4},
+			RemovingReplicas: []int{1},
+		},
+		reassignmentFromTarget("topic1", 2, []int{1, 3}, []int{3, 4}),
+	)
+	assert.Equal(
+		t,
+		PartitionReassignment{
+			Topic:            "topic1",
+			Partition:        0,
+			Replicas:         []int{2, 1},
+			AddingReplicas:   []int{},
+			RemovingReplicas: []int{},
+		},
+		reassignmentFromTarget("topic1", 0, []int{1, 2}, []int{2, 1}),
+	)
+
+	reassignments := []PartitionReassignment{
+		{Topic: "topic2", Partition: 0},
+		{Topic: "topic1", Partition: 3},
+		{Topic: "topic1", Partition: 1},
+	}
+	SortReassignments(reassignments)
+	assert.Equal(
+		t,
+		[]PartitionReassignment{
+			{Topic: "topic1", Partition: 1},
+			{Topic: "topic1", Partition: 3},
+			{Topic: "topic2", Partition: 0},
+		},
+		reassignments,
+	)
+}
+
+func TestGetReassignmentProgress(t *testing.T) {
+	reassignments := []PartitionReassignment{
+		{
+			Topic:            "topic1",
+			Partition:        0,
+			Replicas:         []int{3, 4, 1, 2},
+			AddingReplicas:   []int{3, 4},
+			RemovingReplicas: []int{1, 2},
+		},
+		{
+			Topic:            "topic1",
+			Partition:        1,
+			Replicas:         []int{2, 1},
+			AddingReplicas:   []int{},
+			RemovingReplicas: []int{1},
+		},
+		{
+			Topic:            "topic2",
+			Partition:        0,
+			Replicas:         []int{2, 1},
+			AddingReplicas:   []int{2},
+			RemovingReplicas: []int{1},
+		},
+	}
+	topics := []TopicInfo{
+		{
+			Name: "topic1",
+			Partitions: []PartitionInfo{
+				{ID: 0, Leader: 1, Replicas: []int{3, 4, 1, 2}, ISR: []int{1, 2, 3}},
+				{ID: 1, Leader: 2, Replicas: []int{2, 1}, ISR: []int{2, 1}},
+			},
+		},
+		{
+			Name: "topic2",
+			Partitions: []PartitionInfo{
+				{ID: 0, Leader: 1, Replicas: []int{2, 1}, ISR: []int{1}},
+			},
+		},
+	}
+	replicaLogDirs := []ReplicaLogDirInfo{
+		{Topic: "topic1", Partition: 0, BrokerID: 1, Size: 1000},
+		{Topic: "topic1", Partition: 0, BrokerID: 3, Size: 1000},
+		{Topic: "topic1", Partition: 0, BrokerID: 4, Size: 250},
+		{Topic: "topic1", Partition: 0, BrokerID: 4, Size: 999, IsFuture: true},
+		{Topic: "topic2", Partition: 0, BrokerID: 2, Size: 100},
+	}
+
+	progresses := GetReassignmentProgress(reassignments, topics, replicaLogDirs)
+	assert.Equal(t, 3, len(progresses))
+
+	assert.Equal(t, 1, progresses[0].Leader)
+	assert.Equal(t, int64(1000), progresses[0].LeaderSize)
+	assert.Equal(t, map[int]int64{3: 1000, 4: 250}, progresses[0].AddingSizes)
+	assert.Equal(t, []int{3}, progresses[0].InSyncAddingReplicas)
+	assert.Equal(t, int64(750), progresses[0].RemainingBytes())
+	assert.InDelta(t, 0.625, progresses[0].Fraction(), 0.0001)
+
+	assert.Equal(t, int64(0), progresses[1].RemainingBytes())
+	assert.Equal(t, 1.0, progresses[1].Fraction())
+
+	// The leader size isn't known, so neither is the progress
+	assert.Equal(t, int64(-1), progresses[2].LeaderSize)
+	assert.Equal(t, int64(-1), progresses[2].RemainingBytes())
+	assert.Equal(t, -1.0, progresses[2].Fraction())
+}
@@ -0,0 +1,410 @@
+package admin
+
+import (
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/zk"
+)
+
+// ClientFactory creates a new admin client. It also returns the paths of the files, e.g. TLS
+// certificates, keys, and configs with credentials, that the client was built from.
+type ClientFactory func(ctx context.Context) (Client, []string, error)
+
+// ReloadingClient is a Client that rebuilds the client that it wraps whenever any of the files
+// that the latter was built from change, e.g. when certificates or credentials are rotated. This
+// lets long-running processes like the exporter pick up new credentials without restarting.
+//
+// The files are checked at most once per check interval, at the start of a call; files are
+// compared by their contents, so rotations via symlink swaps (as done for Kubernetes secrets)
+// are detected as well. If the new client can't be built, e.g. because a file is only partially
+// written, the current one is kept and the rebuild is retried on the next check.
+type ReloadingClient struct {
+	factory       ClientFactory
+	checkInterval time.Duration
+
+	mutex        sync.RWMutex
+	client       Client
+	fingerprints map[string][sha256.Size]byte
+	lastCheck    time.Time
+}
+
+var _ Client = (*ReloadingClient)(nil)
+
+// NewReloadingClient returns a ReloadingClient that builds its clients with the argument
+// factory. The first client is built immediately.
+func NewReloadingClient(
+	ctx context.Context,
+	factory ClientFactory,
+	checkInterval time.Duration,
+) (*ReloadingClient, error) {
+	client, paths, err := factory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReloadingClient{
+		factory:       factory,
+		checkInterval: checkInterval,
+		client:        client,
+		fingerprints:  fileFingerprints(paths),
+		lastCheck:     time.Now(),
+	}, nil
+}
+
+// Reload rebuilds the wrapped client if any of its files have changed since it was built. It
+// returns whether the client was rebuilt. Calls that are in progress are allowed to finish
+// before the current client is closed.
+func (c *ReloadingClient) Reload(ctx context.Context) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lastCheck = time.Now()
+
+	changedPaths := []string{}
+	for path, fingerprint := range c.fingerprints {
+		if fileFingerprint(path) != fingerprint {
+			changedPaths = append(changedPaths, path)
+		}
+	}
+	if len(changedPaths) == 0 {
+		return false, nil
+	}
+
+	log.Infof("Rebuilding admin client because files changed: %+v", changedPaths)
+
+	client, paths, err := c.factory(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := c.client.Close(); err != nil {
+		log.Warnf("Error closing previous admin client: %+v", err)
+	}
+
+	c.client = client
+	c.fingerprints = fileFingerprints(paths)
+	return true, nil
+}
+
+// acquire returns the current client after reloading it if the check interval has passed. The
+// returned function must be called once the caller is done with the client.
+func (c *ReloadingClient) acquire(ctx context.Context) (Client, func()) {
+	c.mutex.RLock()
+	checkDue := c.checkInterval > 0 && time.Since(c.lastCheck) >= c.checkInterval
+	c.mutex.RUnlock()
+
+	if checkDue {
+		if _, err := c.Reload(ctx); err != nil {
+			log.Warnf("Could not rebuild admin client; continuing with the previous one: %+v", err)
+		}
+	}
+
+	c.mutex.RLock()
+	return c.client, c.mutex.RUnlock
+}
+
+// GetClusterID gets the ID of the cluster.
+func (c *ReloadingClient) GetClusterID(ctx context.Context) (string, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetClusterID(ctx)
+}
+
+// GetBrokers gets information about all brokers in the cluster.
+func (c *ReloadingClient) GetBrokers(ctx context.Context, ids []int) ([]BrokerInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetBrokers(ctx, ids)
+}
+
+// GetBrokerConfigs gets the full configuration, including defaults, of the argument brokers.
+func (c *ReloadingClient) GetBrokerConfigs(
+	ctx context.Context,
+	ids []int,
+) ([]BrokerConfigs, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetBrokerConfigs(ctx, ids)
+}
+
+// GetReplicaLogDirs gets the on-disk details of the replicas of the argument topics on the
+// argument brokers.
+func (c *ReloadingClient) GetReplicaLogDirs(
+	ctx context.Context,
+	topics []string,
+	ids []int,
+) ([]ReplicaLogDirInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetReplicaLogDirs(ctx, topics, ids)
+}
+
+// GetLogDirs gets the details of each log dir on the argument brokers.
+func (c *ReloadingClient) GetLogDirs(ctx context.Context, ids []int) ([]LogDirInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetLogDirs(ctx, ids)
+}
+
+// GetControllerID gets the ID of the broker that's currently the cluster controller.
+func (c *ReloadingClient) GetControllerID(ctx context.Context) (int, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetControllerID(ctx)
+}
+
+// GetBrokerIDs get the IDs of all brokers in the cluster.
+func (c *ReloadingClient) GetBrokerIDs(ctx context.Context) ([]int, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetBrokerIDs(ctx)
+}
+
+// GetACLs gets the ACLs in the cluster that match the argument filter.
+func (c *ReloadingClient) GetACLs(ctx context.Context, filter kafka.ACLFilter) ([]ACLInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetACLs(ctx, filter)
+}
+
+// CreateACLs creates the argument ACLs in the cluster.
+func (c *ReloadingClient) CreateACLs(ctx context.Context, acls []ACLInfo) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.CreateACLs(ctx, acls)
+}
+
+// DeleteACLs deletes the ACLs in the cluster that match the argument filter.
+func (c *ReloadingClient) DeleteACLs(
+	ctx context.Context,
+	filter kafka.ACLFilter,
+) ([]ACLInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.DeleteACLs(ctx, filter)
+}
+
+// GetQuotas gets all of the user and client ID quotas in the cluster.
+func (c *ReloadingClient) GetQuotas(ctx context.Context) ([]QuotaInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetQuotas(ctx)
+}
+
+// UpdateQuotas applies the argument quota updates to the cluster.
+func (c *ReloadingClient) UpdateQuotas(ctx context.Context, updates []QuotaUpdate) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.UpdateQuotas(ctx, updates)
+}
+
+// GetScramCredentials gets the SCRAM credentials of the argument users.
+func (c *ReloadingClient) GetScramCredentials(
+	ctx context.Context,
+	users []string,
+) ([]ScramCredentialInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetScramCredentials(ctx, users)
+}
+
+// UpsertScramCredentials creates or replaces the argument SCRAM credentials.
+func (c *ReloadingClient) UpsertScramCredentials(
+	ctx context.Context,
+	upserts []ScramCredentialUpsert,
+) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.UpsertScramCredentials(ctx, upserts)
+}
+
+// GetConnector gets the Connector instance of the current client. Since the connector is closed
+// when the client is rebuilt, callers shouldn't hold onto it across reloads.
+func (c *ReloadingClient) GetConnector() *Connector {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.client.GetConnector()
+}
+
+// GetTopics gets full information about each topic in the cluster.
+func (c *ReloadingClient) GetTopics(
+	ctx context.Context,
+	names []string,
+	detailed bool,
+) ([]TopicInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetTopics(ctx, names, detailed)
+}
+
+// GetTopicNames gets just the names of each topic in the cluster.
+func (c *ReloadingClient) GetTopicNames(ctx context.Context) ([]string, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetTopicNames(ctx)
+}
+
+// GetTopic gets the details of a single topic in the cluster.
+func (c *ReloadingClient) GetTopic(
+	ctx context.Context,
+	name string,
+	detailed bool,
+) (TopicInfo, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetTopic(ctx, name, detailed)
+}
+
+// GetPartitionReassignments gets the in-progress partition reassignments in the cluster.
+func (c *ReloadingClient) GetPartitionReassignments(
+	ctx context.Context,
+	topic string,
+) ([]PartitionReassignment, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.GetPartitionReassignments(ctx, topic)
+}
+
+// UpdateTopicConfig updates the configuration for the argument topic.
+func (c *ReloadingClient) UpdateTopicConfig(
+	ctx context.Context,
+	name string,
+	configEntries []kafka.ConfigEntry,
+	overwrite bool,
+) ([]string, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.UpdateTopicConfig(ctx, name, configEntries, overwrite)
+}
+
+// UpdateBrokerConfig updates the configuration for the argument broker.
+func (c *ReloadingClient) UpdateBrokerConfig(
+	ctx context.Context,
+	id int,
+	configEntries []kafka.ConfigEntry,
+	overwrite bool,
+) ([]string, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.UpdateBrokerConfig(ctx, id, configEntries, overwrite)
+}
+
+// CreateTopic creates a topic in the cluster.
+func (c *ReloadingClient) CreateTopic(ctx context.Context, config kafka.TopicConfig) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.CreateTopic(ctx, config)
+}
+
+// DeleteTopic deletes a topic from the cluster.
+func (c *ReloadingClient) DeleteTopic(ctx context.Context, name string) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.DeleteTopic(ctx, name)
+}
+
+// AssignPartitions sets the replica broker IDs for one or more partitions in a topic.
+func (c *ReloadingClient) AssignPartitions(
+	ctx context.Context,
+	topic string,
+	assignments []PartitionAssignment,
+) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.AssignPartitions(ctx, topic, assignments)
+}
+
+// CancelPartitionReassignments cancels the argument in-progress partition reassignments.
+func (c *ReloadingClient) CancelPartitionReassignments(
+	ctx context.Context,
+	reassignments []PartitionReassignment,
+) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.CancelPartitionReassignments(ctx, reassignments)
+}
+
+// AddPartitions extends a topic by adding one or more new partitions to it.
+func (c *ReloadingClient) AddPartitions(
+	ctx context.Context,
+	topic string,
+	newAssignments []PartitionAssignment,
+) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.AddPartitions(ctx, topic, newAssignments)
+}
+
+// RunLeaderElection triggers a leader election for one or more partitions in a topic.
+func (c *ReloadingClient) RunLeaderElection(
+	ctx context.Context,
+	topic string,
+	partitions []int,
+) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.RunLeaderElection(ctx, topic, partitions)
+}
+
+// ElectLeaders triggers leader elections of the argument type for one or more partitions.
+func (c *ReloadingClient) ElectLeaders(
+	ctx context.Context,
+	electionType LeaderElectionType,
+	partitions []PartitionInfo,
+) error {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.ElectLeaders(ctx, electionType, partitions)
+}
+
+// AcquireLock acquires a lock that can be used to prevent simultaneous changes to a topic.
+func (c *ReloadingClient) AcquireLock(ctx context.Context, path string) (zk.Lock, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.AcquireLock(ctx, path)
+}
+
+// LockHeld returns whether a lock is currently held for the given path.
+func (c *ReloadingClient) LockHeld(ctx context.Context, path string) (bool, error) {
+	client, release := c.acquire(ctx)
+	defer release()
+	return client.LockHeld(ctx, path)
+}
+
+// GetSupportedFeatures gets the features supported by the cluster for the current client.
+func (c *ReloadingClient) GetSupportedFeatures() SupportedFeatures {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.client.GetSupportedFeatures()
+}
+
+// Close closes the current client.
+func (c *ReloadingClient) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.client.Close()
+}
+
+func fileFingerprints(paths []string) map[string][sha256.Size]byte {
+	fingerprints := map[string][sha256.Size]byte{}
+	for _, path := range paths {
+		fingerprints[path] = fileFingerprint(path)
+	}
+	return fingerprints
+}
+
+// fileFingerprint returns the hash of the contents of the argument file. Files that can't be
+// read are given an empty fingerprint so that they're considered changed once they can be.
+func fileFingerprint(path string) [sha256.Size]byte {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Could not read %s: %+v", path, err)
+		return [sha256.Size]byte{}
+	}
+	return sha256.Sum256(contents)
+}
@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReloadClient struct {
+	Client
+
+	clusterID string
+	closed    bool
+}
+
+func (c *fakeReloadClient) GetClusterID(ctx context.Context) (string, error) {
+	return c.clusterID, nil
+}
+
+func (c *fakeReloadClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestReloadingClient(t *testing.T) {
+	ctx := context.Background()
+
+	certPath := filepath.Join(t.TempDir(), "client.crt")
+	require.NoError(t, ioutil.WriteFile(certPath, []byte("cert-1"), 0644))
+
+	clients := []*fakeReloadClient{}
+	var factoryErr error
+
+	reloadingClient, err := NewReloadingClient(
+		ctx,
+		func(ctx context.Context) (Client, []string, error) {
+			if factoryErr != nil {
+				return nil, nil, factoryErr
+			}
+			client := &fakeReloadClient{
+				clusterID: string(rune('a' + len(clients))),
+			}
+			clients = append(clients, client)
+			return client, []string{certPath}, nil
+		},
+		time.Hour,
+	)
+	require.NoError(t, err)
+
+	clusterID, err := reloadingClient.GetClusterID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", clusterID)
+
+	reloaded, err := reloadingClient.Reload(ctx)
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// Rotating the file rebuilds the client and closes the previous one
+	require.NoError(t, ioutil.WriteFile(certPath, []byte("cert-2"), 0644))
+	reloaded, err = reloadingClient.Reload(ctx)
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.True(t, clients[0].closed)
+
+	clusterID, err = reloadingClient.GetClusterID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "b", clusterID)
+
+	// Errors building the new client keep the current one
+	require.NoError(t, ioutil.WriteFile(certPath, []byte("cert-3"), 0644))
+	factoryErr = errors.New("bad cert")
+	_, err = reloadingClient.Reload(ctx)
+	assert.Error(t, err)
+	assert.False(t, clients[1].closed)
+
+	factoryErr = nil
+	reloaded, err = reloadingClient.Reload(ctx)
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, 3, len(clients))
+
+	require.NoError(t, reloadingClient.Close())
+	assert.True(t, clients[2].closed)
+}
+
+func TestReloadingClientCheckInterval(t *testing.T) {
+	ctx := context.Background()
+
+	certPath := filepath.Join(t.TempDir(), "client.crt")
+	require.NoError(t, ioutil.WriteFile(certPath, []byte("cert-1"), 0644))
+
+	builds := 0
+	reloadingClient, err := NewReloadingClient(
+		ctx,
+		func(ctx context.Context) (Client, []string, error) {
+			builds++
+			return &fakeReloadClient{}, []string{certPath}, nil
+		},
+		time.Millisecond,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(certPath, []byte("cert-2"), 0644))
+	time.Sleep(5 * time.Millisecond)
+
+	// Calls check the files once the interval has passed
+	_, err = reloadingClient.GetClusterID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, builds)
+}
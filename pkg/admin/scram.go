@@ -0,0 +1,244 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// ScramMechanismSHA256 is the name of the SCRAM-SHA-256 mechanism.
+	ScramMechanismSHA256 = "SCRAM-SHA-256"
+
+	// ScramMechanismSHA512 is the name of the SCRAM-SHA-512 mechanism.
+	ScramMechanismSHA512 = "SCRAM-SHA-512"
+
+	// MinScramIterations and MaxScramIterations are the bounds that the brokers place on the
+	// number of iterations in a SCRAM credential.
+	MinScramIterations = 4096
+	MaxScramIterations = 16384
+
+	scramSaltLen = 32
+)
+
+// AllScramMechanisms contains the names of all of the supported SCRAM mechanisms.
+var AllScramMechanisms = []string{
+	ScramMechanismSHA256,
+	ScramMechanismSHA512,
+}
+
+// ScramCredentialInfo stores the non-secret details of a single SCRAM credential for a user. A
+// user can have one credential per mechanism.
+type ScramCredentialInfo struct {
+	User       string `json:"user"`
+	Mechanism  string `json:"mechanism"`
+	Iterations int    `json:"iterations"`
+}
+
+// ScramCredentialUpsert creates or replaces the SCRAM credential of a single user for a single
+// mechanism. The password is salted and hashed on the client side, so it's never sent to the
+// brokers.
+type ScramCredentialUpsert struct {
+	User       string
+	Mechanism  string
+	Iterations int
+	Password   string
+}
+
+// SortScramCredentials sorts the argument credentials by user and then mechanism.
+func SortScramCredentials(credentials []ScramCredentialInfo) {
+	sort.Slice(credentials, func(a, b int) bool {
+		if credentials[a].User != credentials[b].User {
+			return credentials[a].User < credentials[b].User
+		}
+		return credentials[a].Mechanism < credentials[b].Mechanism
+	})
+}
+
+// describeScramCredentials gets the SCRAM credentials of the argument users via the
+// DescribeUserScramCredentials API. Users without any credentials are omitted from the result.
+// It's shared by both client implementations.
+func describeScramCredentials(
+	ctx context.Context,
+	client *kafka.Client,
+	users []string,
+) ([]ScramCredentialInfo, error) {
+	req := &kafka.DescribeUserScramCredentialsRequest{
+		Users: []kafka.UserScramCredentialsUser{},
+	}
+	for _, user := range users {
+		req.Users = append(req.Users, kafka.UserScramCredentialsUser{Name: user})
+	}
+	log.Debugf("DescribeUserScramCredentials request: %+v", req)
+
+	resp, err := client.DescribeUserScramCredentials(ctx, req)
+	log.Debugf("DescribeUserScramCredentials response: %+v (%+v)", resp, err)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("Error describing SCRAM credentials: %+v", resp.Error)
+	}
+
+	credentials := []ScramCredentialInfo{}
+	var describeErr error
+
+	for _, result := range resp.Results {
+		if result.Error != nil {
+			// The brokers return an error for users that don't have any credentials yet
+			if result.Error == kafka.ResourceNotFound {
+				continue
+			}
+			describeErr = multierror.Append(
+				describeErr,
+				fmt.Errorf(
+					"Error describing SCRAM credentials for %s: %+v",
+					result.User,
+					result.Error,
+				),
+			)
+			continue
+		}
+
+		for _, info := range result.CredentialInfos {
+			credentials = append(
+				credentials,
+				ScramCredentialInfo{
+					User:       result.User,
+					Mechanism:  scramMechanismName(info.Mechanism),
+					Iterations: info.Iterations,
+				},
+			)
+		}
+	}
+	if describeErr != nil {
+		return nil, describeErr
+	}
+
+	SortScramCredentials(credentials)
+	return credentials, nil
+}
+
+// alterScramCredentials applies the argument upserts via the AlterUserScramCredentials API.
+// It's shared by both client implementations.
+func alterScramCredentials(
+	ctx context.Context,
+	client *kafka.Client,
+	upserts []ScramCredentialUpsert,
+) error {
+	req := &kafka.AlterUserScramCredentialsRequest{
+		Upsertions: []kafka.UserScramCredentialsUpsertion{},
+	}
+
+	for _, upsert := range upserts {
+		upsertion, err := scramUpsertion(upsert)
+		if err != nil {
+			return err
+		}
+		req.Upsertions = append(req.Upsertions, upsertion)
+	}
+
+	// Only log the request size since it contains the salted passwords
+	log.Debugf("AlterUserScramCredentials request with %d upsertion(s)", len(req.Upsertions))
+
+	resp, err := client.AlterUserScramCredentials(ctx, req)
+	log.Debugf("AlterUserScramCredentials response: %+v (%+v)", resp, err)
+	if err != nil {
+		return err
+	}
+
+	var alterErr error
+	for _, result := range resp.Results {
+		if result.Error != nil {
+			alterErr = multierror.Append(
+				alterErr,
+				fmt.Errorf(
+					"Error updating SCRAM credentials for %s: %+v",
+					result.User,
+					result.Error,
+				),
+			)
+		}
+	}
+
+	return alterErr
+}
+
+// scramUpsertion converts the argument upsert into the format used by the
+// AlterUserScramCredentials API. As in kafka-configs.sh, the password is salted with a random
+// salt and hashed with the mechanism's hash function via PBKDF2.
+func scramUpsertion(upsert ScramCredentialUpsert) (kafka.UserScramCredentialsUpsertion, error) {
+	var mechanism kafka.ScramMechanism
+	var hashFunc func() hash.Hash
+	var keyLen int
+
+	switch upsert.Mechanism {
+	case ScramMechanismSHA256:
+		mechanism = kafka.ScramMechanismSha256
+		hashFunc = sha256.New
+		keyLen = sha256.Size
+	case ScramMechanismSHA512:
+		mechanism = kafka.ScramMechanismSha512
+		hashFunc = sha512.New
+		keyLen = sha512.Size
+	default:
+		return kafka.UserScramCredentialsUpsertion{}, fmt.Errorf(
+			"SCRAM mechanism for %s must be in %+v",
+			upsert.User,
+			AllScramMechanisms,
+		)
+	}
+
+	if upsert.Iterations < MinScramIterations || upsert.Iterations > MaxScramIterations {
+		return kafka.UserScramCredentialsUpsertion{}, fmt.Errorf(
+			"SCRAM iterations for %s must be between %d and %d",
+			upsert.User,
+			MinScramIterations,
+			MaxScramIterations,
+		)
+	}
+	if upsert.Password == "" {
+		return kafka.UserScramCredentialsUpsertion{}, fmt.Errorf(
+			"SCRAM password for %s is empty",
+			upsert.User,
+		)
+	}
+
+	salt := make([]byte, scramSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return kafka.UserScramCredentialsUpsertion{}, err
+	}
+
+	return kafka.UserScramCredentialsUpsertion{
+		Name:       upsert.User,
+		Mechanism:  mechanism,
+		Iterations: upsert.Iterations,
+		Salt:       salt,
+		SaltedPassword: pbkdf2.Key(
+			[]byte(upsert.Password),
+			salt,
+			upsert.Iterations,
+			keyLen,
+			hashFunc,
+		),
+	}, nil
+}
+
+func scramMechanismName(mechanism kafka.ScramMechanism) string {
+	switch mechanism {
+	case kafka.ScramMechanismSha256:
+		return ScramMechanismSHA256
+	case kafka.ScramMechanismSha512:
+		return ScramMechanismSHA512
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", mechanism)
+	}
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func TestScramUpsertion(t *testing.T) {
+	upsertion, err := scramUpsertion(
+		ScramCredentialUpsert{
+			User:       "alice",
+			Mechanism:  ScramMechanismSHA256,
+			Iterations: 4096,
+			Password:   "test-password",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", upsertion.Name)
+	assert.Equal(t, kafka.ScramMechanismSha256, upsertion.Mechanism)
+	assert.Equal(t, 4096, upsertion.Iterations)
+	assert.Equal(t, scramSaltLen, len(upsertion.Salt))
+	assert.Equal(
+		t,
+		pbkdf2.Key(
+			[]byte("test-password"),
+			upsertion.Salt,
+			4096,
+			sha256.Size,
+			sha256.New,
+		),
+		upsertion.SaltedPassword,
+	)
+
+	upsertion, err = scramUpsertion(
+		ScramCredentialUpsert{
+			User:       "bob",
+			Mechanism:  ScramMechanismSHA512,
+			Iterations: 8192,
+			Password:   "test-password",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, kafka.ScramMechanismSha512, upsertion.Mechanism)
+	assert.Equal(t, sha512.Size, len(upsertion.SaltedPassword))
+
+	// Salts are random, so the same password shouldn't produce the same salted password
+	other, err := scramUpsertion(
+		ScramCredentialUpsert{
+			User:       "bob",
+			Mechanism:  ScramMechanismSHA512,
+			Iterations: 8192,
+			Password:   "test-password",
+		},
+	)
+	require.NoError(t, err)
+	assert.NotEqual(t, upsertion.SaltedPassword, other.SaltedPassword)
+
+	_, err = scramUpsertion(
+		ScramCredentialUpsert{
+			User:       "alice",
+			Mechanism:  "PLAIN",
+			Iterations: 4096,
+			Password:   "test-password",
+		},
+	)
+	assert.Error(t, err)
+
+	_, err = scramUpsertion(
+		ScramCredentialUpsert{
+			User:       "alice",
+			Mechanism:  ScramMechanismSHA256,
+			Iterations: 100,
+			Password:   "test-password",
+		},
+	)
+	assert.Error(t, err)
+
+	_, err = scramUpsertion(
+		ScramCredentialUpsert{
+			User:       "alice",
+			Mechanism:  ScramMechanismSHA256,
+			Iterations: 4096,
+		},
+	)
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+package admin
+
+import (
+	"sort"
+)
+
+// BrokerStorage summarizes the disk usage of a single broker across all of its log dirs.
+type BrokerStorage struct {
+	BrokerID int    `json:"brokerID"`
+	Rack     string `json:"rack"`
+	LogDirs  int    `json:"logDirs"`
+	Replicas int    `json:"replicas"`
+
+	// Bytes is the total size of the (non-future) replicas on the broker.
+	Bytes int64 `json:"bytes"`
+
+	// TotalBytes and UsableBytes are summed across the volumes of the broker's log dirs; they're
+	// zero if the broker doesn't report them.
+	TotalBytes  int64 `json:"totalBytes"`
+	UsableBytes int64 `json:"usableBytes"`
+
+	// OfflineLogDirs is the number of log dirs that the broker returned errors for.
+	OfflineLogDirs int `json:"offlineLogDirs"`
+}
+
+// UsedFraction returns the fraction of the broker's disk capacity that's in use, or -1 if the
+// capacity isn't known.
+func (b BrokerStorage) UsedFraction() float64 {
+	return usedFraction(b.TotalBytes, b.UsableBytes)
+}
+
+// TopicStorage summarizes the disk usage of a single topic across the brokers in a cluster.
+type TopicStorage struct {
+	Topic    string `json:"topic"`
+	Replicas int    `json:"replicas"`
+	Bytes    int64  `json:"bytes"`
+
+	// BrokerBytes is the size of the topic's replicas on each broker, keyed by broker ID.
+	BrokerBytes map[int]int64 `json:"brokerBytes"`
+}
+
+// LogDirUsedFraction returns the fraction of the argument log dir's volume that's in use, or -1
+// if the capacity isn't known.
+func LogDirUsedFraction(logDir LogDirInfo) float64 {
+	return usedFraction(logDir.TotalBytes, logDir.UsableBytes)
+}
+
+// LogDirReplicaBytes returns the total size of the (non-future) replicas in the argument log
+// dir.
+func LogDirReplicaBytes(logDir LogDirInfo) int64 {
+	var total int64
+	for _, replica := range logDir.Replicas {
+		// Future replicas are the targets of in-progress moves between log dirs
+		if replica.IsFuture {
+			continue
+		}
+		total += replica.Size
+	}
+	return total
+}
+
+// GetBrokerStorage summarizes the disk usage of each of the argument brokers from the argument
+// log dirs. The results are sorted by broker ID.
+func GetBrokerStorage(brokers []BrokerInfo, logDirs []LogDirInfo) []BrokerStorage {
+	storageByID := map[int]*BrokerStorage{}
+	for _, broker := range brokers {
+		storageByID[broker.ID] = &BrokerStorage{
+			BrokerID: broker.ID,
+			Rack:     broker.Rack,
+		}
+	}
+
+	for _, logDir := range logDirs {
+		storage, ok := storageByID[logDir.BrokerID]
+		if !ok {
+			storage = &BrokerStorage{BrokerID: logDir.BrokerID}
+			storageByID[logDir.BrokerID] = storage
+		}
+
+		storage.LogDirs++
+		if logDir.Error != "" {
+			storage.OfflineLogDirs++
+		}
+		storage.TotalBytes += logDir.TotalBytes
+		storage.UsableBytes += logDir.UsableBytes
+		storage.Bytes += LogDirReplicaBytes(logDir)
+
+		for _, replica := range logDir.Replicas {
+			if !replica.IsFuture {
+				storage.Replicas++
+			}
+		}
+	}
+
+	storages := []BrokerStorage{}
+	for _, storage := range storageByID {
+		storages = append(storages, *storage)
+	}
+	sort.Slice(storages, func(a, b int) bool {
+		return storages[a].BrokerID < storages[b].BrokerID
+	})
+
+	return storages
+}
+
+// GetTopicStorage summarizes the disk usage of each topic from the argument log dirs. The
+// results are sorted by size, with the largest topics first.
+func GetTopicStorage(logDirs []LogDirInfo) []TopicStorage {
+	storageByTopic := map[string]*TopicStorage{}
+
+	for _, logDir := range logDirs {
+		for _, replica := range logDir.Replicas {
+			if replica.IsFuture {
+				continue
+			}
+
+			storage, ok := storageByTopic[replica.Topic]
+			if !ok {
+				storage = &TopicStorage{
+					Topic:       replica.Topic,
+					BrokerBytes: map[int]int64{},
+				}
+				storageByTopic[replica.Topic] = storage
+			}
+
+			storage.Replicas++
+			storage.Bytes += replica.Size
+			storage.BrokerBytes[replica.BrokerID] += replica.Size
+		}
+	}
+
+	storages := []TopicStorage{}
+	for _, storage := range storageByTopic {
+		storages = append(storages, *storage)
+	}
+	sort.Slice(storages, func(a, b int) bool {
+		if storages[a].Bytes != storages[b].Bytes {
+			return storages[a].Bytes > storages[b].Bytes
+		}
+		return storages[a].Topic < storages[b].Topic
+	})
+
+	return storages
+}
+
+// GetPartitionSizes returns the sizes of the partitions of the argument topic from the argument
+// log dirs, keyed by partition ID. Since the replicas of a partition can differ slightly in
+// size, the size of the largest one is used.
+func GetPartitionSizes(logDirs []LogDirInfo, topic string) map[int]int64 {
+	sizes := map[int]int64{}
+
+	for _, logDir := range logDirs {
+		for _, replica := range logDir.Replicas {
+			if replica.IsFuture || replica.Topic != topic {
+				continue
+			}
+			if size, ok := sizes[replica.Partition]; !ok || replica.Size > size {
+				sizes[replica.Partition] = replica.Size
+			}
+		}
+	}
+
+	return sizes
+}
+
+// GetOtherTopicsBrokerBytes returns the total size of the replicas on each broker in the
+// argument log dirs, keyed by broker ID, excluding the ones of the argument topic.
+func GetOtherTopicsBrokerBytes(logDirs []LogDirInfo, topic string) map[int]int64 {
+	brokerBytes := map[int]int64{}
+
+	for _, logDir := range logDirs {
+		if _, ok := brokerBytes[logDir.BrokerID]; !ok {
+			brokerBytes[logDir.BrokerID] = 0
+		}
+
+		for _, replica := range logDir.Replicas {
+			if replica.IsFuture || replica.Topic == topic {
+				continue
+			}
+			brokerBytes[logDir.BrokerID] += replica.Size
+		}
+	}
+
+	return brokerBytes
+}
+
+func usedFraction(totalBytes int64, usableBytes int64) float64 {
+	if totalBytes <= 0 {
+		return -1
+	}
+	return float64(totalBytes-usableBytes) / float64(totalBytes)
+}
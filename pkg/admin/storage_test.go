@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStorage(t *testing.T) {
+	brokers := []BrokerInfo{
+		{
+			ID:   1,
+			Rack: "rack1",
+		},
+		{
+			ID:   2,
+			Rack: "rack2",
+		},
+	}
+	logDirs := []LogDirInfo{
+		{
+			BrokerID:    1,
+			Path:        "/data1",
+			TotalBytes:  1000,
+			UsableBytes: 400,
+			Replicas: []ReplicaLogDirInfo{
+				{Topic: "topic1", Partition: 0, BrokerID: 1, Size: 100},
+				{Topic: "topic2", Partition: 0, BrokerID: 1, Size: 300},
+				{Topic: "topic2", Partition: 1, BrokerID: 1, Size: 50, IsFuture: true},
+			},
+		},
+		{
+			BrokerID:    1,
+			Path:        "/data2",
+			TotalBytes:  1000,
+			UsableBytes: 800,
+			Replicas: []ReplicaLogDirInfo{
+				{Topic: "topic2", Partition: 1, BrokerID: 1, Size: 50},
+			},
+		},
+		{
+			BrokerID: 2,
+			Path:     "/data1",
+			Replicas: []ReplicaLogDirInfo{
+				{Topic: "topic1", Partition: 0, BrokerID: 2, Size: 120},
+			},
+		},
+		{
+			BrokerID: 2,
+			Path:     "/data2",
+			Error:    "KAFKA_STORAGE_ERROR",
+			Replicas: []ReplicaLogDirInfo{},
+		},
+	}
+
+	brokerStorages := GetBrokerStorage(brokers, logDirs)
+	assert.Equal(
+		t,
+		[]BrokerStorage{
+			{
+				BrokerID:    1,
+				Rack:        "rack1",
+				LogDirs:     2,
+				Replicas:    3,
+				Bytes:       450,
+				TotalBytes:  2000,
+				UsableBytes: 1200,
+			},
+			{
+				BrokerID:       2,
+				Rack:           "rack2",
+				LogDirs:        2,
+				Replicas:       1,
+				Bytes:          120,
+				OfflineLogDirs: 1,
+			},
+		},
+		brokerStorages,
+	)
+	assert.InDelta(t, 0.4, brokerStorages[0].UsedFraction(), 0.0001)
+	assert.Equal(t, -1.0, brokerStorages[1].UsedFraction())
+	assert.InDelta(t, 0.6, LogDirUsedFraction(logDirs[0]), 0.0001)
+	assert.Equal(t, int64(400), LogDirReplicaBytes(logDirs[0]))
+
+	assert.Equal(
+		t,
+		[]TopicStorage{
+			{
+				Topic:    "topic2",
+				Replicas: 2,
+				Bytes:    350,
+				BrokerBytes: map[int]int64{
+					1: 350,
+				},
+			},
+			{
+				Topic:    "topic1",
+				Replicas: 2,
+				Bytes:    220,
+				BrokerBytes: map[int]int64{
+					1: 100,
+					2: 120,
+				},
+			},
+		},
+		GetTopicStorage(logDirs),
+	)
+
+	assert.Equal(t, map[int]int64{0: 120}, GetPartitionSizes(logDirs, "topic1"))
+	assert.Equal(t, map[int]int64{0: 300, 1: 50}, GetPartitionSizes(logDirs, "topic2"))
+	assert.Equal(t, map[int]int64{1: 350, 2: 0}, GetOtherTopicsBrokerBytes(logDirs, "topic1"))
+}
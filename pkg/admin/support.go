@@ -4,16 +4,16 @@ package admin
 type SupportedFeatures struct {
 	// Reads indicates whether the client supports reading basic cluster information
 	// (metadata, configs, etc.).
-	Reads bool
+	Reads bool `json:"reads"`
 
 	// Applies indicates whether the client supports the functionality required for applying
 	// (e.g., changing configs, electing leaders, etc.).
-	Applies bool
+	Applies bool `json:"applies"`
 
 	// Locks indicates whether the client supports locking.
-	Locks bool
+	Locks bool `json:"locks"`
 
 	// DynamicBrokerConfigs indicates whether the client can return dynamic broker configs
 	// like leader.replication.throttled.rate.
-	DynamicBrokerConfigs bool
+	DynamicBrokerConfigs bool `json:"dynamicBrokerConfigs"`
 }
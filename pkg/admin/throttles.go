@@ -75,6 +75,21 @@ func PartitionThrottleConfigEntries(
 	}
 }
 
+// ClearedPartitionThrottleConfigEntries returns the topic config entries that remove all
+// partition throttles from a topic.
+func ClearedPartitionThrottleConfigEntries() []kafka.ConfigEntry {
+	return []kafka.ConfigEntry{
+		{
+			ConfigName:  LeaderReplicasThrottledKey,
+			ConfigValue: "",
+		},
+		{
+			ConfigName:  FollowerReplicasThrottledKey,
+			ConfigValue: "",
+		},
+	}
+}
+
 // BrokerThrottle represents a throttle being applied to a single broker.
 type BrokerThrottle struct {
 	Broker        int
@@ -97,6 +112,21 @@ func (b BrokerThrottle) ConfigEntries() []kafka.ConfigEntry {
 	}
 }
 
+// ClearedBrokerThrottleConfigEntries returns the broker config entries that remove the
+// throttle from a broker.
+func ClearedBrokerThrottleConfigEntries() []kafka.ConfigEntry {
+	return []kafka.ConfigEntry{
+		{
+			ConfigName:  LeaderThrottledKey,
+			ConfigValue: "",
+		},
+		{
+			ConfigName:  FollowerThrottledKey,
+			ConfigValue: "",
+		},
+	}
+}
+
 // LeaderPartitionThrottles returns a slice of PartitionThrottles that we should apply
 // on the leader side.
 //
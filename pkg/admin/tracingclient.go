@@ -0,0 +1,411 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/tracing"
+	"github.com/segmentio/topicctl/pkg/zk"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TracingClient is a Client that records a tracing span for each call to the client that it
+// wraps.
+type TracingClient struct {
+	client Client
+}
+
+var _ Client = (*TracingClient)(nil)
+
+// NewTracingClient returns a TracingClient that wraps the argument client.
+func NewTracingClient(client Client) *TracingClient {
+	return &TracingClient{
+		client: client,
+	}
+}
+
+// GetClusterID gets the ID of the cluster.
+func (c *TracingClient) GetClusterID(ctx context.Context) (string, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.GetClusterID")
+	clusterID, err := c.client.GetClusterID(ctx)
+	tracing.EndSpan(span, err)
+	return clusterID, err
+}
+
+// GetBrokers gets information about all brokers in the cluster.
+func (c *TracingClient) GetBrokers(ctx context.Context, ids []int) ([]BrokerInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.GetBrokers", attribute.IntSlice("broker.ids", ids))
+	brokers, err := c.client.GetBrokers(ctx, ids)
+	tracing.EndSpan(span, err)
+	return brokers, err
+}
+
+// GetBrokerConfigs gets the full configuration, including defaults, of the argument brokers.
+func (c *TracingClient) GetBrokerConfigs(
+	ctx context.Context,
+	ids []int,
+) ([]BrokerConfigs, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.GetBrokerConfigs",
+		attribute.IntSlice("broker.ids", ids),
+	)
+	brokerConfigs, err := c.client.GetBrokerConfigs(ctx, ids)
+	tracing.EndSpan(span, err)
+	return brokerConfigs, err
+}
+
+// GetReplicaLogDirs gets the on-disk details of the replicas of the argument topics on the
+// argument brokers.
+func (c *TracingClient) GetReplicaLogDirs(
+	ctx context.Context,
+	topics []string,
+	ids []int,
+) ([]ReplicaLogDirInfo, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.GetReplicaLogDirs",
+		attribute.StringSlice("topics", topics),
+		attribute.IntSlice("broker.ids", ids),
+	)
+	replicaLogDirs, err := c.client.GetReplicaLogDirs(ctx, topics, ids)
+	tracing.EndSpan(span, err)
+	return replicaLogDirs, err
+}
+
+// GetLogDirs gets the details of each log dir on the argument brokers.
+func (c *TracingClient) GetLogDirs(ctx context.Context, ids []int) ([]LogDirInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.GetLogDirs", attribute.IntSlice("broker.ids", ids))
+	logDirs, err := c.client.GetLogDirs(ctx, ids)
+	tracing.EndSpan(span, err)
+	return logDirs, err
+}
+
+// GetControllerID gets the ID of the broker that's currently the cluster controller.
+func (c *TracingClient) GetControllerID(ctx context.Context) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.GetControllerID")
+	controllerID, err := c.client.GetControllerID(ctx)
+	tracing.EndSpan(span, err)
+	return controllerID, err
+}
+
+// GetBrokerIDs get the IDs of all brokers in the cluster.
+func (c *TracingClient) GetBrokerIDs(ctx context.Context) ([]int, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.GetBrokerIDs")
+	brokerIDs, err := c.client.GetBrokerIDs(ctx)
+	tracing.EndSpan(span, err)
+	return brokerIDs, err
+}
+
+// GetACLs gets the ACLs in the cluster that match the argument filter.
+func (c *TracingClient) GetACLs(ctx context.Context, filter kafka.ACLFilter) ([]ACLInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.GetACLs")
+	acls, err := c.client.GetACLs(ctx, filter)
+	tracing.EndSpan(span, err)
+	return acls, err
+}
+
+// CreateACLs creates the argument ACLs in the cluster.
+func (c *TracingClient) CreateACLs(ctx context.Context, acls []ACLInfo) error {
+	ctx, span := tracing.StartSpan(ctx, "admin.CreateACLs", attribute.Int("acls", len(acls)))
+	err := c.client.CreateACLs(ctx, acls)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// DeleteACLs deletes the ACLs in the cluster that match the argument filter.
+func (c *TracingClient) DeleteACLs(
+	ctx context.Context,
+	filter kafka.ACLFilter,
+) ([]ACLInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.DeleteACLs")
+	acls, err := c.client.DeleteACLs(ctx, filter)
+	tracing.EndSpan(span, err)
+	return acls, err
+}
+
+// GetQuotas gets all of the user and client ID quotas in the cluster.
+func (c *TracingClient) GetQuotas(ctx context.Context) ([]QuotaInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.GetQuotas")
+	quotas, err := c.client.GetQuotas(ctx)
+	tracing.EndSpan(span, err)
+	return quotas, err
+}
+
+// UpdateQuotas applies the argument quota updates to the cluster.
+func (c *TracingClient) UpdateQuotas(ctx context.Context, updates []QuotaUpdate) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.UpdateQuotas",
+		attribute.Int("updates", len(updates)),
+	)
+	err := c.client.UpdateQuotas(ctx, updates)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// GetScramCredentials gets the SCRAM credentials of the argument users.
+func (c *TracingClient) GetScramCredentials(
+	ctx context.Context,
+	users []string,
+) ([]ScramCredentialInfo, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.GetScramCredentials",
+		attribute.Int("users", len(users)),
+	)
+	credentials, err := c.client.GetScramCredentials(ctx, users)
+	tracing.EndSpan(span, err)
+	return credentials, err
+}
+
+// UpsertScramCredentials creates or replaces the argument SCRAM credentials.
+func (c *TracingClient) UpsertScramCredentials(
+	ctx context.Context,
+	upserts []ScramCredentialUpsert,
+) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.UpsertScramCredentials",
+		attribute.Int("upserts", len(upserts)),
+	)
+	err := c.client.UpsertScramCredentials(ctx, upserts)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// GetConnector gets the Connector instance for this cluster.
+func (c *TracingClient) GetConnector() *Connector {
+	return c.client.GetConnector()
+}
+
+// GetTopics gets full information about each topic in the cluster.
+func (c *TracingClient) GetTopics(
+	ctx context.Context,
+	names []string,
+	detailed bool,
+) ([]TopicInfo, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.GetTopics",
+		attribute.StringSlice("topics", names),
+		attribute.Bool("detailed", detailed),
+	)
+	topics, err := c.client.GetTopics(ctx, names, detailed)
+	tracing.EndSpan(span, err)
+	return topics, err
+}
+
+// GetTopicNames gets just the names of each topic in the cluster.
+func (c *TracingClient) GetTopicNames(ctx context.Context) ([]string, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.GetTopicNames")
+	names, err := c.client.GetTopicNames(ctx)
+	tracing.EndSpan(span, err)
+	return names, err
+}
+
+// GetTopic gets the details of a single topic in the cluster.
+func (c *TracingClient) GetTopic(
+	ctx context.Context,
+	name string,
+	detailed bool,
+) (TopicInfo, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.GetTopic",
+		attribute.String("topic", name),
+		attribute.Bool("detailed", detailed),
+	)
+	topic, err := c.client.GetTopic(ctx, name, detailed)
+	tracing.EndSpan(span, err)
+	return topic, err
+}
+
+// GetPartitionReassignments gets the in-progress partition reassignments in the cluster.
+func (c *TracingClient) GetPartitionReassignments(
+	ctx context.Context,
+	topic string,
+) ([]PartitionReassignment, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.GetPartitionReassignments",
+		attribute.String("topic", topic),
+	)
+	reassignments, err := c.client.GetPartitionReassignments(ctx, topic)
+	tracing.EndSpan(span, err)
+	return reassignments, err
+}
+
+// UpdateTopicConfig updates the configuration for the argument topic.
+func (c *TracingClient) UpdateTopicConfig(
+	ctx context.Context,
+	name string,
+	configEntries []kafka.ConfigEntry,
+	overwrite bool,
+) ([]string, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.UpdateTopicConfig",
+		attribute.String("topic", name),
+		attribute.StringSlice("config.keys", configEntryNames(configEntries)),
+	)
+	updatedKeys, err := c.client.UpdateTopicConfig(ctx, name, configEntries, overwrite)
+	tracing.EndSpan(span, err)
+	return updatedKeys, err
+}
+
+// UpdateBrokerConfig updates the configuration for the argument broker.
+func (c *TracingClient) UpdateBrokerConfig(
+	ctx context.Context,
+	id int,
+	configEntries []kafka.ConfigEntry,
+	overwrite bool,
+) ([]string, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.UpdateBrokerConfig",
+		attribute.Int("broker.id", id),
+		attribute.StringSlice("config.keys", configEntryNames(configEntries)),
+	)
+	updatedKeys, err := c.client.UpdateBrokerConfig(ctx, id, configEntries, overwrite)
+	tracing.EndSpan(span, err)
+	return updatedKeys, err
+}
+
+// CreateTopic creates a topic in the cluster.
+func (c *TracingClient) CreateTopic(ctx context.Context, config kafka.TopicConfig) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.CreateTopic",
+		attribute.String("topic", config.Topic),
+		attribute.Int("partitions", config.NumPartitions),
+	)
+	err := c.client.CreateTopic(ctx, config)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// DeleteTopic deletes a topic from the cluster.
+func (c *TracingClient) DeleteTopic(ctx context.Context, name string) error {
+	ctx, span := tracing.StartSpan(ctx, "admin.DeleteTopic", attribute.String("topic", name))
+	err := c.client.DeleteTopic(ctx, name)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// AssignPartitions sets the replica broker IDs for one or more partitions in a topic.
+func (c *TracingClient) AssignPartitions(
+	ctx context.Context,
+	topic string,
+	assignments []PartitionAssignment,
+) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.AssignPartitions",
+		attribute.String("topic", topic),
+		attribute.Int("partitions", len(assignments)),
+	)
+	err := c.client.AssignPartitions(ctx, topic, assignments)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// CancelPartitionReassignments cancels the argument in-progress partition reassignments.
+func (c *TracingClient) CancelPartitionReassignments(
+	ctx context.Context,
+	reassignments []PartitionReassignment,
+) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.CancelPartitionReassignments",
+		attribute.Int("partitions", len(reassignments)),
+	)
+	err := c.client.CancelPartitionReassignments(ctx, reassignments)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// AddPartitions extends a topic by adding one or more new partitions to it.
+func (c *TracingClient) AddPartitions(
+	ctx context.Context,
+	topic string,
+	newAssignments []PartitionAssignment,
+) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.AddPartitions",
+		attribute.String("topic", topic),
+		attribute.Int("partitions", len(newAssignments)),
+	)
+	err := c.client.AddPartitions(ctx, topic, newAssignments)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// RunLeaderElection triggers a leader election for one or more partitions in a topic.
+func (c *TracingClient) RunLeaderElection(
+	ctx context.Context,
+	topic string,
+	partitions []int,
+) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.RunLeaderElection",
+		attribute.String("topic", topic),
+		attribute.IntSlice("partition.ids", partitions),
+	)
+	err := c.client.RunLeaderElection(ctx, topic, partitions)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// ElectLeaders triggers leader elections of the argument type for one or more partitions.
+func (c *TracingClient) ElectLeaders(
+	ctx context.Context,
+	electionType LeaderElectionType,
+	partitions []PartitionInfo,
+) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"admin.ElectLeaders",
+		attribute.String("election.type", string(electionType)),
+		attribute.Int("partitions", len(partitions)),
+	)
+	err := c.client.ElectLeaders(ctx, electionType, partitions)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// AcquireLock acquires a lock that can be used to prevent simultaneous changes to a topic.
+func (c *TracingClient) AcquireLock(ctx context.Context, path string) (zk.Lock, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.AcquireLock", attribute.String("path", path))
+	lock, err := c.client.AcquireLock(ctx, path)
+	tracing.EndSpan(span, err)
+	return lock, err
+}
+
+// LockHeld returns whether a lock is currently held for the given path.
+func (c *TracingClient) LockHeld(ctx context.Context, path string) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "admin.LockHeld", attribute.String("path", path))
+	held, err := c.client.LockHeld(ctx, path)
+	tracing.EndSpan(span, err)
+	return held, err
+}
+
+// GetSupportedFeatures gets the features supported by the cluster for this client.
+func (c *TracingClient) GetSupportedFeatures() SupportedFeatures {
+	return c.client.GetSupportedFeatures()
+}
+
+// Close closes the client.
+func (c *TracingClient) Close() error {
+	return c.client.Close()
+}
+
+func configEntryNames(configEntries []kafka.ConfigEntry) []string {
+	names := []string{}
+	for _, configEntry := range configEntries {
+		names = append(names, configEntry.ConfigName)
+	}
+	return names
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"time"
@@ -43,6 +44,36 @@ type BrokerInfo struct {
 	Version          int               `json:"version"`
 	Timestamp        time.Time         `json:"timestamp"`
 	Config           map[string]string `json:"config"`
+
+	// Weight is the relative capacity of the broker, as set in the cluster config; replicas and
+	// leaders are spread across the brokers in proportion to their weights. Zero means that the
+	// weight isn't set, in which case the broker is treated as having a weight of 1.
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// CapacityWeight returns the weight of the broker, or 1 if it isn't set.
+func (b BrokerInfo) CapacityWeight() float64 {
+	if b.Weight <= 0 {
+		return 1.0
+	}
+	return b.Weight
+}
+
+// BrokerConfigs contains the full configuration of a single broker, as returned by the
+// DescribeConfigs API.
+type BrokerConfigs struct {
+	BrokerID int                 `json:"brokerID"`
+	Entries  []BrokerConfigEntry `json:"entries"`
+}
+
+// BrokerConfigEntry is a single config value for a broker along with the source that it
+// came from.
+type BrokerConfigEntry struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Source    string `json:"source"`
+	ReadOnly  bool   `json:"readOnly"`
+	Sensitive bool   `json:"sensitive"`
 }
 
 // TopicInfo represents the information stored about a topic in zookeeper.
@@ -66,6 +97,72 @@ type PartitionInfo struct {
 	LeaderEpoch     int    `json:"leaderEpoch"`
 }
 
+// ReplicaLogDirInfo contains the on-disk details of a single partition replica, as returned by
+// the DescribeLogDirs API.
+type ReplicaLogDirInfo struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	BrokerID  int    `json:"brokerID"`
+	LogDir    string `json:"logDir"`
+	Size      int64  `json:"size"`
+	OffsetLag int64  `json:"offsetLag"`
+	IsFuture  bool   `json:"isFuture"`
+}
+
+// LogDirInfo contains the details of a single log dir on a broker, as returned by the
+// DescribeLogDirs API.
+type LogDirInfo struct {
+	BrokerID int    `json:"brokerID"`
+	Path     string `json:"path"`
+	Error    string `json:"error,omitempty"`
+
+	// TotalBytes and UsableBytes are the capacity and free space of the volume that the log dir
+	// is on. These are only reported by brokers running 3.3 or later, and are zero otherwise.
+	TotalBytes  int64 `json:"totalBytes"`
+	UsableBytes int64 `json:"usableBytes"`
+
+	Replicas []ReplicaLogDirInfo `json:"replicas"`
+}
+
+// PartitionDetails extends PartitionInfo with the on-disk size of each replica and the time of
+// the latest message in the partition.
+type PartitionDetails struct {
+	PartitionInfo
+
+	// ReplicaSizes maps from the ID of each replica broker to the size of the replica in bytes.
+	// Replicas with unknown sizes are omitted.
+	ReplicaSizes map[int]int64 `json:"replicaSizes"`
+
+	// LastModified is the time of the latest message in the partition; it's zero if the
+	// partition is empty.
+	LastModified time.Time `json:"lastModified"`
+}
+
+// PartitionSortKey is a string type that identifies how partition details are sorted.
+type PartitionSortKey string
+
+const (
+	// PartitionSortKeyID sorts partitions by ID. This is the default.
+	PartitionSortKeyID PartitionSortKey = "id"
+
+	// PartitionSortKeyLeader sorts partitions by leader broker ID.
+	PartitionSortKeyLeader PartitionSortKey = "leader"
+
+	// PartitionSortKeySize sorts partitions by the size of their largest replica.
+	PartitionSortKeySize PartitionSortKey = "size"
+
+	// PartitionSortKeyLastModified sorts partitions by the time of their latest message.
+	PartitionSortKeyLastModified PartitionSortKey = "last-modified"
+)
+
+// AllPartitionSortKeys contains all of the supported partition sort keys.
+var AllPartitionSortKeys = []PartitionSortKey{
+	PartitionSortKeyID,
+	PartitionSortKeyLeader,
+	PartitionSortKeySize,
+	PartitionSortKeyLastModified,
+}
+
 // PartitionAssignment contains the actual or desired assignment of
 // replicas in a topic partition.
 type PartitionAssignment struct {
@@ -78,6 +175,12 @@ type zkClusterID struct {
 	ID      string `json:"id"`
 }
 
+type zkController struct {
+	Version   int    `json:"version"`
+	BrokerID  int    `json:"brokerid"`
+	Timestamp string `json:"timestamp"`
+}
+
 type zkBrokerInfo struct {
 	Endpoints    []string `json:"endpoints"`
 	Host         string   `json:"host"`
@@ -136,6 +239,67 @@ type zkChangeNotification struct {
 	EntityPath string `json:"entity_path"`
 }
 
+const (
+	// ConfigSourceDefault is the source of configs that aren't set anywhere.
+	ConfigSourceDefault = "DEFAULT_CONFIG"
+
+	// ConfigSourceStaticBroker is the source of configs set in the broker properties file.
+	ConfigSourceStaticBroker = "STATIC_BROKER_CONFIG"
+
+	// ConfigSourceDynamicDefaultBroker is the source of dynamic configs that are set for all
+	// brokers in the cluster.
+	ConfigSourceDynamicDefaultBroker = "DYNAMIC_DEFAULT_BROKER_CONFIG"
+
+	// ConfigSourceDynamicBroker is the source of dynamic configs set for a specific broker.
+	ConfigSourceDynamicBroker = "DYNAMIC_BROKER_CONFIG"
+
+	// ConfigSourceDynamicBrokerLogger is the source of dynamic broker logger configs.
+	ConfigSourceDynamicBrokerLogger = "DYNAMIC_BROKER_LOGGER_CONFIG"
+
+	// ConfigSourceUnknown is used if the broker doesn't report a source.
+	ConfigSourceUnknown = "UNKNOWN"
+)
+
+// IsDefault returns whether the config entry is set to its default value.
+func (b BrokerConfigEntry) IsDefault() bool {
+	return b.Source == ConfigSourceDefault
+}
+
+// DriftedBrokerConfigs returns the names of the configs that are set dynamically on at least
+// one broker and that don't have the same value on all of the argument brokers. Configs that
+// differ between brokers only because of their static properties files aren't included since
+// those are often expected to differ (e.g., broker.id).
+func DriftedBrokerConfigs(brokerConfigs []BrokerConfigs) []string {
+	dynamicNames := map[string]struct{}{}
+	values := map[string]map[string]struct{}{}
+	counts := map[string]int{}
+
+	for _, brokerConfig := range brokerConfigs {
+		for _, entry := range brokerConfig.Entries {
+			if entry.Source == ConfigSourceDynamicBroker {
+				dynamicNames[entry.Name] = struct{}{}
+			}
+			if _, ok := values[entry.Name]; !ok {
+				values[entry.Name] = map[string]struct{}{}
+			}
+			values[entry.Name][entry.Value] = struct{}{}
+			counts[entry.Name]++
+		}
+	}
+
+	drifted := []string{}
+	for name := range dynamicNames {
+		if len(values[name]) > 1 || counts[name] < len(brokerConfigs) {
+			drifted = append(drifted, name)
+		}
+	}
+	sort.Slice(drifted, func(a, b int) bool {
+		return drifted[a] < drifted[b]
+	})
+
+	return drifted
+}
+
 // Addr returns the address of the current BrokerInfo.
 func (b BrokerInfo) Addr() string {
 	return fmt.Sprintf("%s:%d", b.Host, b.Port)
@@ -185,6 +349,50 @@ func BrokerRacks(brokers []BrokerInfo) map[int]string {
 	return brokerRacks
 }
 
+// OverrideBrokerRacks replaces the racks of the argument brokers with the values in the
+// argument overrides map (keyed by broker ID), if set. This is useful for clusters whose
+// brokers don't report their racks.
+func OverrideBrokerRacks(brokers []BrokerInfo, rackOverrides map[int]string) {
+	for b := 0; b < len(brokers); b++ {
+		if rack, ok := rackOverrides[brokers[b].ID]; ok {
+			brokers[b].Rack = rack
+		}
+	}
+}
+
+// OverrideBrokerWeights sets the weights of the argument brokers to the values in the argument
+// weights map (keyed by broker ID), if set.
+func OverrideBrokerWeights(brokers []BrokerInfo, weights map[int]float64) {
+	for b := 0; b < len(brokers); b++ {
+		if weight, ok := weights[brokers[b].ID]; ok {
+			brokers[b].Weight = weight
+		}
+	}
+}
+
+// BrokerWeights returns a mapping of broker ID -> capacity weight, with brokers that don't have
+// their weights set mapped to 1.
+func BrokerWeights(brokers []BrokerInfo) map[int]float64 {
+	brokerWeights := map[int]float64{}
+
+	for _, broker := range brokers {
+		brokerWeights[broker.ID] = broker.CapacityWeight()
+	}
+
+	return brokerWeights
+}
+
+// HasBrokerWeights returns whether any of the argument brokers has a weight other than 1.
+func HasBrokerWeights(brokers []BrokerInfo) bool {
+	for _, broker := range brokers {
+		if broker.CapacityWeight() != 1.0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 // BrokersPerRack returns a mapping of rack -> broker IDs.
 func BrokersPerRack(brokers []BrokerInfo) map[string][]int {
 	brokersPerRack := map[string][]int{}
@@ -441,6 +649,59 @@ func ThrottledTopicNames(topics []TopicInfo) []string {
 	return throttledNames
 }
 
+// TopicFilter is a filter on the topics in a cluster. Unset fields match all topics.
+type TopicFilter struct {
+	// NameRegexp, if set, only matches the topics whose names match it.
+	NameRegexp *regexp.Regexp
+
+	// Names, if non-nil, only matches the topics with these names.
+	Names map[string]struct{}
+
+	// UnderReplicated only matches the topics with at least one partition whose ISR doesn't
+	// include all of its replicas.
+	UnderReplicated bool
+
+	// Throttled only matches the topics that have throttles on them.
+	Throttled bool
+
+	// WrongLeaders only matches the topics with at least one partition whose leader isn't its
+	// preferred replica.
+	WrongLeaders bool
+}
+
+// Matches returns whether the argument topic matches the filter.
+func (f TopicFilter) Matches(topic TopicInfo) bool {
+	if f.NameRegexp != nil && !f.NameRegexp.MatchString(topic.Name) {
+		return false
+	}
+	if _, ok := f.Names[topic.Name]; f.Names != nil && !ok {
+		return false
+	}
+	if f.UnderReplicated && len(topic.OutOfSyncPartitions(nil)) == 0 {
+		return false
+	}
+	if f.Throttled && !topic.IsThrottled() {
+		return false
+	}
+	if f.WrongLeaders && len(topic.WrongLeaderPartitions(nil)) == 0 {
+		return false
+	}
+	return true
+}
+
+// FilterTopics returns the topics in the argument slice that match the argument filter.
+func FilterTopics(topics []TopicInfo, filter TopicFilter) []TopicInfo {
+	filtered := []TopicInfo{}
+
+	for _, topic := range topics {
+		if filter.Matches(topic) {
+			filtered = append(filtered, topic)
+		}
+	}
+
+	return filtered
+}
+
 // Racks returns a slice of all racks for the partition replicas.
 func (p PartitionInfo) Racks(brokerRacks map[int]string) ([]string, error) {
 	racks := []string{}
@@ -473,6 +734,79 @@ func (p PartitionInfo) NumRacks(brokerRacks map[int]string) (int, error) {
 	return len(racksMap), nil
 }
 
+// HasPreferredLeader returns whether the partition leader is its preferred (i.e., first)
+// replica.
+func (p PartitionInfo) HasPreferredLeader() bool {
+	return len(p.Replicas) > 0 && p.Leader == p.Replicas[0]
+}
+
+// MaxReplicaSize returns the size of the largest replica in the partition, or 0 if no sizes
+// are known.
+func (p PartitionDetails) MaxReplicaSize() int64 {
+	var maxSize int64
+
+	for _, size := range p.ReplicaSizes {
+		if size > maxSize {
+			maxSize = size
+		}
+	}
+
+	return maxSize
+}
+
+// SortPartitionDetails sorts the argument partitions in place by the argument key. Ties are
+// broken by partition ID.
+func SortPartitionDetails(
+	partitions []PartitionDetails,
+	sortKey PartitionSortKey,
+	descending bool,
+) error {
+	var less func(a, b PartitionDetails) bool
+
+	switch sortKey {
+	case "", PartitionSortKeyID:
+		less = func(a, b PartitionDetails) bool {
+			return false
+		}
+	case PartitionSortKeyLeader:
+		less = func(a, b PartitionDetails) bool {
+			return a.Leader < b.Leader
+		}
+	case PartitionSortKeySize:
+		less = func(a, b PartitionDetails) bool {
+			return a.MaxReplicaSize() < b.MaxReplicaSize()
+		}
+	case PartitionSortKeyLastModified:
+		less = func(a, b PartitionDetails) bool {
+			return a.LastModified.Before(b.LastModified)
+		}
+	default:
+		return fmt.Errorf(
+			"Unrecognized partition sort key '%s'; choices are %+v",
+			sortKey,
+			AllPartitionSortKeys,
+		)
+	}
+
+	sort.Slice(partitions, func(a, b int) bool {
+		partitionA := partitions[a]
+		partitionB := partitions[b]
+
+		if descending {
+			partitionA, partitionB = partitionB, partitionA
+		}
+		if less(partitionA, partitionB) {
+			return true
+		}
+		if less(partitionB, partitionA) {
+			return false
+		}
+		return partitionA.ID < partitionB.ID
+	})
+
+	return nil
+}
+
 // ToAssignments converts a topic to a slice of partition assignments.
 func (t TopicInfo) ToAssignments() []PartitionAssignment {
 	assignments := []PartitionAssignment{}
@@ -501,6 +835,17 @@ func PartitionIDs(partitions []PartitionInfo) []int {
 	return ids
 }
 
+// AssignmentIDs returns the partition IDs from the argument assignments.
+func AssignmentIDs(assignments []PartitionAssignment) []int {
+	ids := []int{}
+
+	for _, assignment := range assignments {
+		ids = append(ids, assignment.ID)
+	}
+
+	return ids
+}
+
 // Index returns the index of the argument replica, or -1 if it can't
 // be found.
 func (a PartitionAssignment) Index(replica int) int {
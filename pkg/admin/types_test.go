@@ -1,10 +1,12 @@
 package admin
 
 import (
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBrokerHelpers(t *testing.T) {
@@ -93,6 +95,140 @@ func TestBrokerHelpers(t *testing.T) {
 	)
 }
 
+func TestOverrideBrokerRacks(t *testing.T) {
+	brokers := []BrokerInfo{
+		{
+			ID: 1,
+		},
+		{
+			ID:   2,
+			Rack: "rack2",
+		},
+		{
+			ID:   3,
+			Rack: "rack3",
+		},
+	}
+	OverrideBrokerRacks(
+		brokers,
+		map[int]string{
+			1: "rack1",
+			3: "rack-override",
+			4: "rack4",
+		},
+	)
+
+	assert.Equal(
+		t,
+		map[int]string{
+			1: "rack1",
+			2: "rack2",
+			3: "rack-override",
+		},
+		BrokerRacks(brokers),
+	)
+}
+
+func TestOverrideBrokerWeights(t *testing.T) {
+	brokers := []BrokerInfo{
+		{
+			ID: 1,
+		},
+		{
+			ID: 2,
+		},
+		{
+			ID: 3,
+		},
+	}
+	assert.False(t, HasBrokerWeights(brokers))
+
+	OverrideBrokerWeights(
+		brokers,
+		map[int]float64{
+			1: 2.0,
+			3: 0.5,
+			4: 3.0,
+		},
+	)
+
+	assert.True(t, HasBrokerWeights(brokers))
+	assert.Equal(
+		t,
+		map[int]float64{
+			1: 2.0,
+			2: 1.0,
+			3: 0.5,
+		},
+		BrokerWeights(brokers),
+	)
+}
+
+func TestDriftedBrokerConfigs(t *testing.T) {
+	brokerConfigs := []BrokerConfigs{
+		{
+			BrokerID: 1,
+			Entries: []BrokerConfigEntry{
+				{
+					Name:   "broker.id",
+					Value:  "1",
+					Source: ConfigSourceStaticBroker,
+				},
+				{
+					Name:   "leader.replication.throttled.rate",
+					Value:  "1000",
+					Source: ConfigSourceDynamicBroker,
+				},
+				{
+					Name:   "log.cleaner.threads",
+					Value:  "2",
+					Source: ConfigSourceDynamicBroker,
+				},
+				{
+					Name:   "num.io.threads",
+					Value:  "16",
+					Source: ConfigSourceDynamicBroker,
+				},
+			},
+		},
+		{
+			BrokerID: 2,
+			Entries: []BrokerConfigEntry{
+				{
+					Name:   "broker.id",
+					Value:  "2",
+					Source: ConfigSourceStaticBroker,
+				},
+				{
+					Name:   "leader.replication.throttled.rate",
+					Value:  "2000",
+					Source: ConfigSourceDynamicBroker,
+				},
+				{
+					Name:   "log.cleaner.threads",
+					Value:  "1",
+					Source: ConfigSourceDefault,
+				},
+				{
+					Name:   "num.io.threads",
+					Value:  "16",
+					Source: ConfigSourceDynamicBroker,
+				},
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]string{
+			"leader.replication.throttled.rate",
+			"log.cleaner.threads",
+		},
+		DriftedBrokerConfigs(brokerConfigs),
+	)
+	assert.Equal(t, []string{}, DriftedBrokerConfigs(brokerConfigs[:1]))
+}
+
 func TestTopicRackHelpers(t *testing.T) {
 	testBrokers := []BrokerInfo{
 		{
@@ -294,6 +430,141 @@ func TestTopicSyncHelpers(t *testing.T) {
 	)
 }
 
+func TestFilterTopics(t *testing.T) {
+	topics := []TopicInfo{
+		{
+			Name: "payments-events",
+			Partitions: []PartitionInfo{
+				{ID: 0, Leader: 1, Replicas: []int{1, 2}, ISR: []int{1}},
+			},
+		},
+		{
+			Name: "payments-state",
+			Config: map[string]string{
+				LeaderReplicasThrottledKey: "0:1",
+			},
+			Partitions: []PartitionInfo{
+				{ID: 0, Leader: 2, Replicas: []int{1, 2}, ISR: []int{1, 2}},
+			},
+		},
+		{
+			Name: "orders",
+			Partitions: []PartitionInfo{
+				{ID: 0, Leader: 1, Replicas: []int{1, 2}, ISR: []int{2, 1}},
+			},
+		},
+	}
+
+	topicNames := func(topics []TopicInfo) []string {
+		names := []string{}
+		for _, topic := range topics {
+			names = append(names, topic.Name)
+		}
+		return names
+	}
+
+	assert.Equal(
+		t,
+		[]string{"payments-events", "payments-state", "orders"},
+		topicNames(FilterTopics(topics, TopicFilter{})),
+	)
+	assert.Equal(
+		t,
+		[]string{"payments-events", "payments-state"},
+		topicNames(
+			FilterTopics(topics, TopicFilter{NameRegexp: regexp.MustCompile("^payments-")}),
+		),
+	)
+	assert.Equal(
+		t,
+		[]string{"orders"},
+		topicNames(
+			FilterTopics(topics, TopicFilter{Names: map[string]struct{}{"orders": {}}}),
+		),
+	)
+	assert.Equal(
+		t,
+		[]string{},
+		topicNames(FilterTopics(topics, TopicFilter{Names: map[string]struct{}{}})),
+	)
+	assert.Equal(
+		t,
+		[]string{"payments-events"},
+		topicNames(FilterTopics(topics, TopicFilter{UnderReplicated: true})),
+	)
+	assert.Equal(
+		t,
+		[]string{"payments-state"},
+		topicNames(FilterTopics(topics, TopicFilter{Throttled: true})),
+	)
+	assert.Equal(
+		t,
+		[]string{"payments-state"},
+		topicNames(FilterTopics(topics, TopicFilter{WrongLeaders: true})),
+	)
+	assert.Equal(
+		t,
+		[]string{},
+		topicNames(
+			FilterTopics(
+				topics,
+				TopicFilter{
+					NameRegexp:      regexp.MustCompile("^payments-"),
+					UnderReplicated: true,
+					Throttled:       true,
+				},
+			),
+		),
+	)
+}
+
+func TestSortPartitionDetails(t *testing.T) {
+	now := time.Now()
+	partitions := []PartitionDetails{
+		{
+			PartitionInfo: PartitionInfo{ID: 0, Leader: 3, Replicas: []int{3, 1}},
+			ReplicaSizes:  map[int]int64{3: 200, 1: 100},
+			LastModified:  now.Add(-time.Hour),
+		},
+		{
+			PartitionInfo: PartitionInfo{ID: 1, Leader: 1, Replicas: []int{2, 1}},
+			ReplicaSizes:  map[int]int64{2: 50},
+			LastModified:  now,
+		},
+		{
+			PartitionInfo: PartitionInfo{ID: 2, Leader: 1, Replicas: []int{1, 2}},
+			ReplicaSizes:  map[int]int64{1: 500, 2: 500},
+		},
+	}
+
+	assert.True(t, partitions[0].HasPreferredLeader())
+	assert.False(t, partitions[1].HasPreferredLeader())
+	assert.Equal(t, int64(200), partitions[0].MaxReplicaSize())
+	assert.Equal(t, int64(0), PartitionDetails{}.MaxReplicaSize())
+
+	partitionIDs := func() []int {
+		ids := []int{}
+		for _, partition := range partitions {
+			ids = append(ids, partition.ID)
+		}
+		return ids
+	}
+
+	require.NoError(t, SortPartitionDetails(partitions, PartitionSortKeyLeader, false))
+	assert.Equal(t, []int{1, 2, 0}, partitionIDs())
+
+	require.NoError(t, SortPartitionDetails(partitions, PartitionSortKeySize, true))
+	assert.Equal(t, []int{2, 0, 1}, partitionIDs())
+
+	require.NoError(t, SortPartitionDetails(partitions, PartitionSortKeyLastModified, false))
+	assert.Equal(t, []int{2, 0, 1}, partitionIDs())
+
+	require.NoError(t, SortPartitionDetails(partitions, PartitionSortKeyID, false))
+	assert.Equal(t, []int{0, 1, 2}, partitionIDs())
+
+	assert.Error(t, SortPartitionDetails(partitions, "bad-key", false))
+}
+
 func TestPartitionAssignmentHelpers(t *testing.T) {
 	testTopic := TopicInfo{
 		Config: map[string]string{
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -16,7 +17,6 @@ import (
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/util"
 	"github.com/segmentio/topicctl/pkg/zk"
-	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -26,6 +26,7 @@ const (
 	brokersPath       = "/brokers/ids"
 	topicsPath        = "/brokers/topics"
 	clusterIDPath     = "/cluster/id"
+	controllerPath    = "/controller"
 	brokerConfigsPath = "/config/brokers"
 	configChangesPath = "/config/changes/config_change_"
 	topicConfigsPath  = "/config/topics"
@@ -50,6 +51,8 @@ type ZKAdminClient struct {
 	Connector      *Connector
 	sess           *session.Session
 	readOnly       bool
+	brokerRacks    map[int]string
+	brokerWeights  map[int]float64
 }
 
 var _ Client = (*ZKAdminClient)(nil)
@@ -62,6 +65,17 @@ type ZKAdminClientConfig struct {
 	ExpectedClusterID string
 	Sess              *session.Session
 	ReadOnly          bool
+
+	// BrokerRacks is an optional mapping from broker ID to rack that overrides the racks
+	// stored in zookeeper.
+	BrokerRacks map[int]string
+
+	// BrokerWeights is an optional mapping from broker ID to capacity weight.
+	BrokerWeights map[int]float64
+
+	// TLS stores the TLS settings for the connections to zookeeper. They don't apply to the
+	// connections to the brokers.
+	TLS TLSConfig
 }
 
 // NewZKAdminClient creates and returns a new Client instance.
@@ -69,12 +83,22 @@ func NewZKAdminClient(
 	ctx context.Context,
 	config ZKAdminClientConfig,
 ) (*ZKAdminClient, error) {
+	var tlsConfig *tls.Config
+	if config.TLS.Enabled {
+		var err error
+		tlsConfig, err = newTLSConfig(config.TLS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	zkClient, err := zk.NewPooledClient(
 		config.ZKAddrs,
 		time.Minute,
 		&zk.DebugLogger{},
 		10,
 		config.ReadOnly,
+		tlsConfig,
 	)
 	if err != nil {
 		return nil, err
@@ -93,10 +117,12 @@ func NewZKAdminClient(
 	}
 
 	client := &ZKAdminClient{
-		zkClient: zkClient,
-		zkPrefix: zkPrefix,
-		sess:     config.Sess,
-		readOnly: config.ReadOnly,
+		zkClient:      zkClient,
+		zkPrefix:      zkPrefix,
+		sess:          config.Sess,
+		readOnly:      config.ReadOnly,
+		brokerRacks:   config.BrokerRacks,
+		brokerWeights: config.BrokerWeights,
 	}
 
 	if config.ExpectedClusterID != "" {
@@ -158,6 +184,22 @@ func (c *ZKAdminClient) GetClusterID(
 	return zkClusterIDObj.ID, nil
 }
 
+// GetControllerID gets the ID of the cluster controller from zookeeper. It returns -1 if no
+// broker currently holds the controller node.
+func (c *ZKAdminClient) GetControllerID(ctx context.Context) (int, error) {
+	zkControllerObj := zkController{}
+	_, err := c.zkClient.GetJSON(ctx, c.zNode(controllerPath), &zkControllerObj)
+	if err != nil {
+		// The node is ephemeral, so it won't exist during a controller election
+		if strings.Contains(err.Error(), "node does not exist") {
+			return -1, nil
+		}
+		return 0, err
+	}
+
+	return zkControllerObj.BrokerID, nil
+}
+
 // GetBrokers gets information on one or more cluster brokers from zookeeper.
 // If the argument ids is unset, then it fetches all brokers.
 func (c *ZKAdminClient) GetBrokers(
@@ -264,9 +306,75 @@ func (c *ZKAdminClient) GetBrokers(
 		return brokers[i].ID < brokers[j].ID
 	})
 
+	OverrideBrokerRacks(brokers, c.brokerRacks)
+	OverrideBrokerWeights(brokers, c.brokerWeights)
+
 	return brokers, nil
 }
 
+// GetACLs gets the ACLs in the cluster that match the argument filter.
+func (c *ZKAdminClient) GetACLs(
+	ctx context.Context,
+	filter kafka.ACLFilter,
+) ([]ACLInfo, error) {
+	return describeACLs(ctx, c.Connector.KafkaClient, filter)
+}
+
+// CreateACLs creates the argument ACLs in the cluster.
+func (c *ZKAdminClient) CreateACLs(ctx context.Context, acls []ACLInfo) error {
+	if c.readOnly {
+		return errors.New("Cannot create ACLs in read-only mode")
+	}
+
+	return createACLs(ctx, c.Connector.KafkaClient, acls)
+}
+
+// DeleteACLs deletes the ACLs in the cluster that match the argument filter.
+func (c *ZKAdminClient) DeleteACLs(
+	ctx context.Context,
+	filter kafka.ACLFilter,
+) ([]ACLInfo, error) {
+	if c.readOnly {
+		return nil, errors.New("Cannot delete ACLs in read-only mode")
+	}
+
+	return deleteACLs(ctx, c.Connector.KafkaClient, filter)
+}
+
+// GetQuotas gets all of the user and client ID quotas in the cluster.
+func (c *ZKAdminClient) GetQuotas(ctx context.Context) ([]QuotaInfo, error) {
+	return describeQuotas(ctx, c.Connector.KafkaClient)
+}
+
+// UpdateQuotas applies the argument quota updates to the cluster.
+func (c *ZKAdminClient) UpdateQuotas(ctx context.Context, updates []QuotaUpdate) error {
+	if c.readOnly {
+		return errors.New("Cannot update quotas in read-only mode")
+	}
+
+	return alterQuotas(ctx, c.Connector.KafkaClient, updates)
+}
+
+// GetScramCredentials gets the SCRAM credentials of the argument users.
+func (c *ZKAdminClient) GetScramCredentials(
+	ctx context.Context,
+	users []string,
+) ([]ScramCredentialInfo, error) {
+	return describeScramCredentials(ctx, c.Connector.KafkaClient, users)
+}
+
+// UpsertScramCredentials creates or replaces the argument SCRAM credentials.
+func (c *ZKAdminClient) UpsertScramCredentials(
+	ctx context.Context,
+	upserts []ScramCredentialUpsert,
+) error {
+	if c.readOnly {
+		return errors.New("Cannot update SCRAM credentials in read-only mode")
+	}
+
+	return alterScramCredentials(ctx, c.Connector.KafkaClient, upserts)
+}
+
 // GetBrokerIDs returns a slice of all broker IDs.
 func (c *ZKAdminClient) GetBrokerIDs(ctx context.Context) ([]int, error) {
 	zPath := c.zNode(brokersPath)
@@ -559,6 +667,129 @@ func (c *ZKAdminClient) UpdateBrokerConfig(
 		c.zkClient.CreateJSON(ctx, c.zNode(configChangesPath), changeObj, true)
 }
 
+// GetBrokerConfigs gets the full configuration, including defaults, of the argument brokers.
+// Unlike GetBrokers, this uses the DescribeConfigs API exposed by the brokers since zookeeper
+// only stores the dynamic configs.
+func (c *ZKAdminClient) GetBrokerConfigs(
+	ctx context.Context,
+	ids []int,
+) ([]BrokerConfigs, error) {
+	if len(ids) == 0 {
+		var err error
+		ids, err = c.GetBrokerIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return describeBrokerConfigs(ctx, c.Connector.KafkaClient, ids)
+}
+
+// GetReplicaLogDirs gets the on-disk details, including the sizes, of the replicas of the
+// argument topics via the DescribeLogDirs API.
+func (c *ZKAdminClient) GetReplicaLogDirs(
+	ctx context.Context,
+	topics []string,
+	ids []int,
+) ([]ReplicaLogDirInfo, error) {
+	if len(ids) == 0 {
+		var err error
+		ids, err = c.GetBrokerIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return describeReplicaLogDirs(ctx, c.Connector.KafkaClient, topics, ids)
+}
+
+// GetLogDirs gets the details of each log dir on the argument brokers via the DescribeLogDirs
+// API.
+func (c *ZKAdminClient) GetLogDirs(ctx context.Context, ids []int) ([]LogDirInfo, error) {
+	if len(ids) == 0 {
+		var err error
+		ids, err = c.GetBrokerIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return describeLogDirs(ctx, c.Connector.KafkaClient, nil, ids)
+}
+
+// GetPartitionReassignments gets the in-progress partition reassignments in the cluster from
+// the zookeeper reassignment node. Since this node only has the target replicas of each
+// partition, the current replicas are fetched to determine which replicas are being added and
+// removed.
+func (c *ZKAdminClient) GetPartitionReassignments(
+	ctx context.Context,
+	topic string,
+) ([]PartitionReassignment, error) {
+	inProgress, err := c.assignmentInProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reassignments := []PartitionReassignment{}
+	if !inProgress {
+		return reassignments, nil
+	}
+
+	zkAssignmentObj := zkAssignment{}
+	_, err = c.zkClient.GetJSON(ctx, c.zNode(assignmentPath), &zkAssignmentObj)
+	if err != nil {
+		// The reassignment may have finished since the existence check
+		if strings.Contains(err.Error(), "node does not exist") {
+			return reassignments, nil
+		}
+		return nil, err
+	}
+
+	topicNames := []string{}
+	topicNamesMap := map[string]struct{}{}
+	for _, partition := range zkAssignmentObj.Partitions {
+		if topic != "" && partition.Topic != topic {
+			continue
+		}
+		if _, ok := topicNamesMap[partition.Topic]; !ok {
+			topicNames = append(topicNames, partition.Topic)
+			topicNamesMap[partition.Topic] = struct{}{}
+		}
+	}
+	if len(topicNames) == 0 {
+		return reassignments, nil
+	}
+
+	topics, err := c.GetTopics(ctx, topicNames, false)
+	if err != nil {
+		return nil, err
+	}
+	currentReplicas := map[string]map[int][]int{}
+	for _, topicInfo := range topics {
+		currentReplicas[topicInfo.Name] = map[int][]int{}
+		for _, partition := range topicInfo.Partitions {
+			currentReplicas[topicInfo.Name][partition.ID] = partition.Replicas
+		}
+	}
+
+	for _, partition := range zkAssignmentObj.Partitions {
+		if topic != "" && partition.Topic != topic {
+			continue
+		}
+		reassignments = append(
+			reassignments,
+			reassignmentFromTarget(
+				partition.Topic,
+				partition.Partition,
+				currentReplicas[partition.Topic][partition.Partition],
+				partition.Replicas,
+			),
+		)
+	}
+
+	SortReassignments(reassignments)
+	return reassignments, nil
+}
+
 // CreateTopic creates a new topic with the argument config. It uses
 // the topic creation API exposed on the controller broker.
 func (c *ZKAdminClient) CreateTopic(
@@ -578,6 +809,25 @@ func (c *ZKAdminClient) CreateTopic(
 	return err
 }
 
+// DeleteTopic deletes a topic. Like CreateTopic, it uses the API exposed
+// on the controller broker instead of writing to zookeeper directly.
+func (c *ZKAdminClient) DeleteTopic(ctx context.Context, name string) error {
+	if c.readOnly {
+		return errors.New("Cannot delete topic in read-only mode")
+	}
+
+	req := kafka.DeleteTopicsRequest{
+		Topics: []string{name},
+	}
+	log.Debugf("Deleting topic %s", name)
+
+	resp, err := c.Connector.KafkaClient.DeleteTopics(ctx, &req)
+	if err != nil {
+		return err
+	}
+	return topicDeletionError(resp.Errors[name])
+}
+
 // AssignPartitions notifies the cluster to begin a partition reassignment.
 // This should only be used for existing partitions; to create new partitions,
 // use the AddPartitions method.
@@ -620,6 +870,68 @@ func (c *ZKAdminClient) AssignPartitions(
 	)
 }
 
+// CancelPartitionReassignments cancels the argument in-progress partition reassignments. If
+// the cluster supports the AlterPartitionReassignments API (i.e., versions >= 2.4), then that's
+// used. Otherwise, the zookeeper reassignment node is deleted. Older controllers can't roll back
+// moves that they've already started, but removing the node clears the pending reassignment
+// state so that it isn't resumed, e.g. after a controller failover. Since the node covers all
+// pending partitions, this fallback is only allowed if all of them are being cancelled.
+func (c *ZKAdminClient) CancelPartitionReassignments(
+	ctx context.Context,
+	reassignments []PartitionReassignment,
+) error {
+	if c.readOnly {
+		return errors.New("Cannot cancel partition reassignments in read-only mode")
+	}
+	if len(reassignments) == 0 {
+		return nil
+	}
+
+	apiVersions, err := c.Connector.KafkaClient.ApiVersions(ctx, &kafka.ApiVersionsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, apiKey := range apiVersions.ApiKeys {
+		if apiKey.ApiName == "AlterPartitionReassignments" {
+			return cancelPartitionReassignments(ctx, c.Connector.KafkaClient, reassignments)
+		}
+	}
+
+	allReassignments, err := c.GetPartitionReassignments(ctx, "")
+	if err != nil {
+		return err
+	}
+	cancelling := map[string]map[int]struct{}{}
+	for _, reassignment := range reassignments {
+		if _, ok := cancelling[reassignment.Topic]; !ok {
+			cancelling[reassignment.Topic] = map[int]struct{}{}
+		}
+		cancelling[reassignment.Topic][reassignment.Partition] = struct{}{}
+	}
+	for _, reassignment := range allReassignments {
+		if _, ok := cancelling[reassignment.Topic][reassignment.Partition]; !ok {
+			return fmt.Errorf(
+				"Cluster does not support cancelling individual reassignments; topic %s, partition %d would also be cancelled",
+				reassignment.Topic,
+				reassignment.Partition,
+			)
+		}
+	}
+
+	zNode := c.zNode(assignmentPath)
+	log.Infof("Deleting reassignment config at zk path %s", zNode)
+	log.Warn(
+		"Cluster does not support rolling back reassignments; partitions that the controller has already started moving may still finish",
+	)
+
+	err = c.zkClient.Delete(ctx, zNode, -1)
+	if err != nil && strings.Contains(err.Error(), "node does not exist") {
+		// The reassignments finished in the meantime
+		return nil
+	}
+	return err
+}
+
 // AddPartitions adds one or more partitions to an existing topic. Unlike
 // AssignPartitions, this directly updates the topic's partition config in
 // zookeeper.
@@ -718,6 +1030,63 @@ func (c *ZKAdminClient) RunLeaderElection(
 	)
 }
 
+// ElectLeaders triggers leader elections of the argument type for one or more partitions,
+// which can be in different topics. The ElectLeaders API is used if the cluster supports it.
+// Otherwise, preferred elections are triggered through zookeeper; unclean elections aren't
+// possible in this case.
+func (c *ZKAdminClient) ElectLeaders(
+	ctx context.Context,
+	electionType LeaderElectionType,
+	partitions []PartitionInfo,
+) error {
+	if c.readOnly {
+		return errors.New("Cannot run leader election in read-only mode")
+	}
+	if len(partitions) == 0 {
+		return nil
+	}
+
+	supported, err := supportsElectionType(ctx, c.Connector.KafkaClient, electionType)
+	if err != nil {
+		return err
+	}
+	if supported {
+		return electLeaders(ctx, c.Connector.KafkaClient, electionType, partitions)
+	}
+	if electionType != LeaderElectionTypePreferred {
+		return ErrUncleanElectionsUnsupported
+	}
+
+	zkElectionObj := zkElection{
+		Version:    1,
+		Partitions: []zkElectionTopicPartition{},
+	}
+
+	for _, partition := range partitions {
+		zkElectionObj.Partitions = append(
+			zkElectionObj.Partitions,
+			zkElectionTopicPartition{
+				Topic:     partition.Topic,
+				Partition: partition.ID,
+			},
+		)
+	}
+
+	zNode := c.zNode(electionPath)
+	log.Infof(
+		"Writing leader election config to zk path %s: %+v",
+		zNode,
+		zkElectionObj,
+	)
+
+	return c.zkClient.CreateJSON(
+		ctx,
+		zNode,
+		zkElectionObj,
+		false,
+	)
+}
+
 // AcquireLock acquires and returns a lock from the underlying zookeeper client.
 // The Unlock method should be called on the lock when it's safe to release.
 func (c *ZKAdminClient) AcquireLock(
@@ -0,0 +1,138 @@
+package apply
+
+import (
+	"bytes"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// ACLResourceDiff stores the ACL changes needed to make a single resource in the cluster match
+// an ACL config.
+type ACLResourceDiff struct {
+	Resource    config.ACLResource
+	MissingACLs []admin.ACLInfo
+	ExtraACLs   []admin.ACLInfo
+}
+
+// ACLResourceDiffs compares the desired ACLs in an ACL config against the current ones in the
+// cluster and returns the diffs for the resources that don't match, in config order. Current
+// ACLs on resources that aren't in the config are ignored.
+func ACLResourceDiffs(
+	aclConfig config.ACLConfig,
+	currentACLs []admin.ACLInfo,
+) ([]ACLResourceDiff, error) {
+	resources, desiredACLs, err := aclConfig.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	currentByResource := map[config.ACLResource][]admin.ACLInfo{}
+	for _, acl := range currentACLs {
+		resource := config.ACLResource{
+			Type:        acl.ResourceType,
+			Name:        acl.ResourceName,
+			PatternType: acl.PatternType,
+		}
+		currentByResource[resource] = append(currentByResource[resource], acl)
+	}
+
+	diffs := []ACLResourceDiff{}
+
+	for _, resource := range resources {
+		missingACLs, extraACLs := admin.ACLDiffs(
+			desiredACLs[resource],
+			currentByResource[resource],
+		)
+		if len(missingACLs) > 0 || len(extraACLs) > 0 {
+			diffs = append(
+				diffs,
+				ACLResourceDiff{
+					Resource:    resource,
+					MissingACLs: missingACLs,
+					ExtraACLs:   extraACLs,
+				},
+			)
+		}
+	}
+
+	return diffs, nil
+}
+
+// FormatACLResourceDiffs generates a table that summarizes the ACLs that applying an ACL config
+// would create and delete, grouped by resource.
+func FormatACLResourceDiffs(diffs []ACLResourceDiff) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Change",
+			"Resource Type",
+			"Resource Name",
+			"Pattern Type",
+			"Principal",
+			"Host",
+			"Operation",
+			"Permission",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	createStr := "create"
+	deleteStr := "delete"
+	if util.ColorEnabled() {
+		createStr = color.New(color.FgGreen).Sprint(createStr)
+		deleteStr = color.New(color.FgRed).Sprint(deleteStr)
+	}
+
+	appendRow := func(changeStr string, acl admin.ACLInfo) {
+		table.Append(
+			[]string{
+				changeStr,
+				acl.ResourceType.String(),
+				acl.ResourceName,
+				acl.PatternType.String(),
+				acl.Principal,
+				acl.Host,
+				acl.Operation.String(),
+				acl.PermissionType.String(),
+			},
+		)
+	}
+
+	for _, diff := range diffs {
+		for _, acl := range diff.MissingACLs {
+			appendRow(createStr, acl)
+		}
+		for _, acl := range diff.ExtraACLs {
+			appendRow(deleteStr, acl)
+		}
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
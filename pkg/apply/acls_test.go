@@ -0,0 +1,120 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLResourceDiffs(t *testing.T) {
+	aclConfig := config.ACLConfig{
+		Spec: config.ACLConfigSpec{
+			Resources: []config.ACLResourceConfig{
+				{
+					Type: "group",
+					Name: "test-group",
+					ACLs: []config.ACLEntryConfig{
+						{
+							Principal:  "User:alice",
+							Operations: []string{"read"},
+						},
+					},
+				},
+				{
+					Type: "cluster",
+					ACLs: []config.ACLEntryConfig{
+						{
+							Principal:  "User:alice",
+							Operations: []string{"idempotentWrite"},
+						},
+					},
+				},
+				{
+					Type: "transactionalId",
+					Name: "test-txn",
+					ACLs: []config.ACLEntryConfig{},
+				},
+			},
+		},
+	}
+
+	groupRead := admin.ACLInfo{
+		ResourceType:   kafka.ResourceTypeGroup,
+		ResourceName:   "test-group",
+		PatternType:    kafka.PatternTypeLiteral,
+		Principal:      "User:alice",
+		Host:           "*",
+		Operation:      kafka.ACLOperationTypeRead,
+		PermissionType: kafka.ACLPermissionTypeAllow,
+	}
+	groupDescribe := groupRead
+	groupDescribe.Operation = kafka.ACLOperationTypeDescribe
+
+	clusterWrite := admin.ACLInfo{
+		ResourceType:   kafka.ResourceTypeCluster,
+		ResourceName:   "kafka-cluster",
+		PatternType:    kafka.PatternTypeLiteral,
+		Principal:      "User:alice",
+		Host:           "*",
+		Operation:      kafka.ACLOperationTypeIdempotentWrite,
+		PermissionType: kafka.ACLPermissionTypeAllow,
+	}
+
+	txnDeny := admin.ACLInfo{
+		ResourceType:   kafka.ResourceTypeTransactionalID,
+		ResourceName:   "test-txn",
+		PatternType:    kafka.PatternTypeLiteral,
+		Principal:      "User:bob",
+		Host:           "*",
+		Operation:      kafka.ACLOperationTypeWrite,
+		PermissionType: kafka.ACLPermissionTypeDeny,
+	}
+
+	// ACLs on resources that aren't in the config should be ignored
+	prefixedGroupRead := groupRead
+	prefixedGroupRead.PatternType = kafka.PatternTypePrefixed
+
+	diffs, err := ACLResourceDiffs(
+		aclConfig,
+		[]admin.ACLInfo{
+			clusterWrite,
+			groupDescribe,
+			prefixedGroupRead,
+			txnDeny,
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]ACLResourceDiff{
+			{
+				Resource: config.ACLResource{
+					Type:        kafka.ResourceTypeGroup,
+					Name:        "test-group",
+					PatternType: kafka.PatternTypeLiteral,
+				},
+				MissingACLs: []admin.ACLInfo{groupRead},
+				ExtraACLs:   []admin.ACLInfo{groupDescribe},
+			},
+			{
+				Resource: config.ACLResource{
+					Type:        kafka.ResourceTypeTransactionalID,
+					Name:        "test-txn",
+					PatternType: kafka.PatternTypeLiteral,
+				},
+				MissingACLs: []admin.ACLInfo{},
+				ExtraACLs:   []admin.ACLInfo{txnDeny},
+			},
+		},
+		diffs,
+	)
+
+	formatted := FormatACLResourceDiffs(diffs)
+	assert.Contains(t, formatted, "test-group")
+	assert.Contains(t, formatted, "test-txn")
+	assert.NotContains(t, formatted, "kafka-cluster")
+}
@@ -4,31 +4,40 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
-	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/apply/assigners"
 	"github.com/segmentio/topicctl/pkg/apply/extenders"
 	"github.com/segmentio/topicctl/pkg/apply/pickers"
 	"github.com/segmentio/topicctl/pkg/apply/rebalancers"
+	"github.com/segmentio/topicctl/pkg/audit"
 	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/segmentio/topicctl/pkg/messages"
+	"github.com/segmentio/topicctl/pkg/metrics"
+	"github.com/segmentio/topicctl/pkg/progress"
 	"github.com/segmentio/topicctl/pkg/util"
 	"github.com/segmentio/topicctl/pkg/zk"
-	log "github.com/sirupsen/logrus"
 )
 
 // TopicApplierConfig contains the configuration for a TopicApplier struct.
 type TopicApplierConfig struct {
+	AllowProtected             bool
 	BrokerThrottleMBsOverride  int
 	BrokersToRemove            []int
 	ClusterConfig              config.ClusterConfig
 	DryRun                     bool
+	MoveBudget                 *rebalancers.MoveBudget
 	PartitionBatchSizeOverride int
+	PartitionThroughputs       metrics.PartitionThroughputs
+	Progress                   *progress.Reporter
 	Rebalance                  bool
 	RetentionDropStepDuration  time.Duration
 	SkipConfirm                bool
@@ -91,6 +100,12 @@ func NewTopicApplier(
 		throttleBytes = 120000000
 	}
 
+	// Expand any ACL roles and principal names so that the topic ACLs can be validated and diffed
+	topicConfig := applierConfig.TopicConfig
+	if err := topicConfig.ResolveACLs(applierConfig.ClusterConfig); err != nil {
+		return nil, err
+	}
+
 	return &TopicApplier{
 		adminClient:   adminClient,
 		config:        applierConfig,
@@ -98,7 +113,7 @@ func NewTopicApplier(
 		clusterConfig: applierConfig.ClusterConfig,
 		maxBatchSize:  maxBatchSize,
 		throttleBytes: throttleBytes,
-		topicConfig:   applierConfig.TopicConfig,
+		topicConfig:   topicConfig,
 		topicName:     applierConfig.TopicConfig.Meta.Name,
 	}, nil
 }
@@ -117,32 +132,154 @@ func NewTopicApplier(
 //   c. Check partition count and extend if needed
 //   d. Check partition placement and update/migrate if needed
 //   e. Check partition leaders and update if needed
+//
+// If a progress reporter is configured, events are sent to it at the start and end of the run
+// and around each batch of partition updates.
 func (t *TopicApplier) Apply(ctx context.Context) error {
-	log.Info("Validating configs...")
-	brokerRacks := admin.DistinctRacks(t.brokers)
+	t.reportProgress(progress.Event{Type: progress.EventTypeStarted})
+	err := t.apply(ctx)
+	t.reportDone(err)
+	return err
+}
 
-	if err := t.clusterConfig.Validate(); err != nil {
+func (t *TopicApplier) apply(ctx context.Context) error {
+	if err := t.validateConfigs(); err != nil {
 		return err
 	}
 
-	if err := t.topicConfig.Validate(len(brokerRacks)); err != nil {
+	log.Info("Checking if topic already exists...")
+
+	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, true)
+	if err != nil {
+		if err == admin.ErrTopicDoesNotExist {
+			return t.applyNewTopic(ctx)
+		}
 		return err
 	}
-	if err := config.CheckConsistency(t.topicConfig, t.clusterConfig); err != nil {
+
+	return t.applyExistingTopic(ctx, topicInfo)
+}
+
+// Rebalance rebalances the replicas and leaders of the configured topic across the brokers in
+// the cluster. Unlike Apply with the Rebalance option set, it doesn't make any other changes
+// to the topic, e.g. to its settings, partition count, or placement.
+func (t *TopicApplier) Rebalance(ctx context.Context) error {
+	t.reportProgress(progress.Event{Type: progress.EventTypeStarted})
+	err := t.rebalance(ctx)
+	t.reportDone(err)
+	return err
+}
+
+func (t *TopicApplier) rebalance(ctx context.Context) error {
+	if err := t.validateConfigs(); err != nil {
 		return err
 	}
 
-	log.Info("Checking if topic already exists...")
+	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, true)
+	if err != nil {
+		if err == admin.ErrTopicDoesNotExist {
+			return fmt.Errorf(
+				"Topic %s does not exist; it must be created via apply before it can be rebalanced",
+				t.topicName,
+			)
+		}
+		return err
+	}
+
+	log.Infof("Rebalancing existing topic '%s'", t.topicName)
+
+	if err := t.checkExistingState(ctx, topicInfo); err != nil {
+		return err
+	}
+
+	if err := t.updateBalance(ctx, t.maxBatchSize); err != nil {
+		return err
+	}
+
+	return t.updateLeaders(ctx, -1)
+}
+
+// IncreasePartitions adds partitions to the configured topic until it has the number in its
+// config. Unlike Apply, it doesn't make any other changes to the topic, e.g. to its settings,
+// retention, or placement.
+func (t *TopicApplier) IncreasePartitions(ctx context.Context) error {
+	t.reportProgress(progress.Event{Type: progress.EventTypeStarted})
+	err := t.increasePartitions(ctx)
+	t.reportDone(err)
+	return err
+}
+
+func (t *TopicApplier) increasePartitions(ctx context.Context) error {
+	if err := t.validateConfigs(); err != nil {
+		return err
+	}
 
 	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, true)
 	if err != nil {
 		if err == admin.ErrTopicDoesNotExist {
-			return t.applyNewTopic(ctx)
+			return fmt.Errorf(
+				"Topic %s does not exist; it must be created via apply before its partitions can be increased",
+				t.topicName,
+			)
 		}
 		return err
 	}
 
-	return t.applyExistingTopic(ctx, topicInfo)
+	log.Infof("Increasing partitions in existing topic '%s'", t.topicName)
+
+	if err := t.checkExistingState(ctx, topicInfo); err != nil {
+		return err
+	}
+
+	return t.updatePartitions(ctx, topicInfo)
+}
+
+// RebalanceSummary evaluates the balance of the configured topic and returns a summary of the
+// changes that Rebalance would make to it. It doesn't make any changes to the topic itself.
+//
+// Since the rebalancer uses a randomized picker, the exact replica moves made by Rebalance can
+// differ from the ones counted here, but there will always be none if the topic is balanced.
+func (t *TopicApplier) RebalanceSummary(ctx context.Context) (TopicRebalanceSummary, error) {
+	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, true)
+	if err != nil {
+		return TopicRebalanceSummary{}, err
+	}
+
+	currAssignments, desiredAssignments, err := t.rebalancedAssignments(ctx, false)
+	if err != nil {
+		return TopicRebalanceSummary{}, err
+	}
+
+	return TopicRebalanceSummary{
+		Topic:      t.topicName,
+		Partitions: len(topicInfo.Partitions),
+		PartitionMoves: len(
+			admin.AssignmentsToUpdate(currAssignments, desiredAssignments),
+		),
+		WrongLeaders: len(topicInfo.WrongLeaderPartitions(nil)),
+	}, nil
+}
+
+func (t *TopicApplier) validateConfigs() error {
+	log.Info("Validating configs...")
+	brokerRacks := admin.DistinctRacks(t.brokers)
+
+	if err := t.clusterConfig.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.topicConfig.Validate(len(brokerRacks)); err != nil {
+		return err
+	}
+
+	placementConfig := t.topicConfig.Spec.PlacementConfig
+	if placementConfig.Strategy == config.PlacementStrategyCustom {
+		if _, err := assigners.GetAssignerPlugin(placementConfig.Assigner); err != nil {
+			return err
+		}
+	}
+
+	return config.CheckConsistency(t.topicConfig, t.clusterConfig)
 }
 
 func (t *TopicApplier) applyNewTopic(ctx context.Context) error {
@@ -153,7 +290,14 @@ func (t *TopicApplier) applyNewTopic(ctx context.Context) error {
 
 	if t.config.DryRun {
 		log.Infof("Would create topic with config %+v", newTopicConfig)
-		return nil
+		for _, baseline := range t.topicConfig.Spec.OffsetBaselines {
+			log.Infof(
+				"Would set the offsets of group %s to the %s offsets",
+				baseline.Group,
+				baselineDescription(baseline),
+			)
+		}
+		return t.updateACLs(ctx)
 	}
 
 	log.Infof(
@@ -176,6 +320,10 @@ func (t *TopicApplier) applyNewTopic(ctx context.Context) error {
 		return err
 	}
 
+	if err := t.updateACLs(ctx); err != nil {
+		return err
+	}
+
 	// Just do a short sleep to ensure that zk is updated before we check
 	if err := interruptableSleep(ctx, t.config.SleepLoopDuration/5); err != nil {
 		return err
@@ -189,7 +337,7 @@ func (t *TopicApplier) applyNewTopic(ctx context.Context) error {
 		return err
 	}
 
-	return nil
+	return t.updateOffsetBaselines(ctx)
 }
 
 func (t *TopicApplier) applyExistingTopic(
@@ -206,6 +354,10 @@ func (t *TopicApplier) applyExistingTopic(
 		return err
 	}
 
+	if err := t.updateACLs(ctx); err != nil {
+		return err
+	}
+
 	if err := t.updateReplication(ctx, topicInfo); err != nil {
 		return err
 	}
@@ -245,7 +397,7 @@ func (t *TopicApplier) applyExistingTopic(
 		}
 	}
 
-	return nil
+	return t.updateOffsetBaselines(ctx)
 }
 
 func (t *TopicApplier) checkExistingState(
@@ -447,6 +599,206 @@ func (t *TopicApplier) updateSettings(
 	return nil
 }
 
+// updateACLs creates the ACLs in the topic config that are missing from the cluster and deletes
+// the literal allow ACLs on the topic that aren't in the config. It's a no-op if the topic config
+// doesn't have an acls section.
+func (t *TopicApplier) updateACLs(ctx context.Context) error {
+	if !t.topicConfig.ManagesACLs() {
+		return nil
+	}
+
+	log.Infof("Checking topic ACLs...")
+
+	missingACLs, extraACLs, err := t.aclDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	if len(missingACLs) == 0 && len(extraACLs) == 0 {
+		log.Infof("ACLs are up-to-date")
+		return nil
+	}
+
+	log.Infof(
+		"Found %d ACL(s) to create and %d to delete:\n%s",
+		len(missingACLs),
+		len(extraACLs),
+		FormatACLDiffs(missingACLs, extraACLs),
+	)
+
+	if t.config.DryRun {
+		log.Infof("Skipping update because dryRun is set to true")
+		return nil
+	}
+
+	ok, _ := Confirm(
+		"OK to update the topic ACLs to match the topic config?",
+		t.config.SkipConfirm,
+	)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+	log.Infof("OK, updating")
+
+	if len(missingACLs) > 0 {
+		if err := t.adminClient.CreateACLs(ctx, missingACLs); err != nil {
+			return err
+		}
+	}
+	for _, acl := range extraACLs {
+		if _, err := t.adminClient.DeleteACLs(ctx, acl.Filter()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateOffsetBaselines sets the offsets of the consumer groups in the topic config's offset
+// baselines. Groups that have committed offsets in the topic or that have members are skipped, so
+// baselines only ever affect consumers that haven't started yet.
+func (t *TopicApplier) updateOffsetBaselines(ctx context.Context) error {
+	if len(t.topicConfig.Spec.OffsetBaselines) == 0 {
+		return nil
+	}
+
+	log.Infof("Checking consumer group offset baselines...")
+
+	connector := t.adminClient.GetConnector()
+	if connector == nil {
+		return errors.New("Admin client does not support setting consumer group offsets")
+	}
+
+	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, false)
+	if err != nil {
+		return err
+	}
+	partitions := topicInfo.PartitionIDs()
+
+	for _, baseline := range t.topicConfig.Spec.OffsetBaselines {
+		states, err := groups.GetPartitionOffsetStates(
+			ctx,
+			connector,
+			t.topicName,
+			baseline.Group,
+			partitions,
+		)
+		if err != nil {
+			return err
+		}
+		if HasCommittedOffsets(states) {
+			log.Infof(
+				"Group %s already has committed offsets in the topic; not applying its baseline",
+				baseline.Group,
+			)
+			continue
+		}
+
+		groupDetails, err := groups.GetGroupDetails(ctx, connector, baseline.Group)
+		if err != nil {
+			return err
+		}
+		if len(groupDetails.Members) > 0 {
+			log.Warnf(
+				"Group %s has %d member(s) but no committed offsets in the topic; not applying its baseline",
+				baseline.Group,
+				len(groupDetails.Members),
+			)
+			continue
+		}
+
+		var timeOffsets []messages.TimeOffset
+		if baseline.Position == config.OffsetBaselinePositionTimestamp {
+			at, err := baseline.Time()
+			if err != nil {
+				return err
+			}
+			timeOffsets, err = messages.GetTimeOffsets(ctx, connector, t.topicName, partitions, at)
+			if err != nil {
+				return err
+			}
+		}
+
+		partitionOffsets := BaselineOffsets(baseline, states, timeOffsets)
+		if err := groups.CheckOffsets(states, partitionOffsets); err != nil {
+			return err
+		}
+
+		log.Infof(
+			"Group %s doesn't have any committed offsets in the topic; will set them to the %s offsets:\n%s",
+			baseline.Group,
+			baselineDescription(baseline),
+			groups.FormatOffsetResets(states, partitionOffsets),
+		)
+
+		if t.config.DryRun {
+			log.Infof("Skipping update because dryRun is set to true")
+			continue
+		}
+
+		ok, _ := Confirm("OK to set the group offsets?", t.config.SkipConfirm)
+		if !ok {
+			return errors.New("Stopping because of user response")
+		}
+		log.Infof("OK, updating")
+
+		err = groups.ResetOffsets(
+			ctx,
+			connector,
+			t.topicName,
+			baseline.Group,
+			partitionOffsets,
+		)
+		t.recordOffsetResets(baseline.Group, states, partitionOffsets, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordOffsetResets records the argument offset commits for the argument group in the audit
+// log, if the admin client is audited. The offsets are committed with the client's connector, so
+// they aren't recorded by the client itself.
+func (t *TopicApplier) recordOffsetResets(
+	group string,
+	states []groups.PartitionOffsetState,
+	partitionOffsets map[int]int64,
+	err error,
+) {
+	auditClient, ok := t.adminClient.(*audit.Client)
+	if !ok {
+		return
+	}
+
+	before := map[string]string{}
+	after := map[string]string{}
+	for _, state := range states {
+		if offset, ok := partitionOffsets[state.Partition]; ok {
+			before[strconv.Itoa(state.Partition)] = strconv.FormatInt(state.CommittedOffset, 10)
+			after[strconv.Itoa(state.Partition)] = strconv.FormatInt(offset, 10)
+		}
+	}
+
+	auditClient.Record(
+		audit.Entry{
+			Operation: audit.OperationResetOffsets,
+			Topic:     t.topicName,
+			Details:   fmt.Sprintf("group %s", group),
+			Before:    before,
+			After:     after,
+		},
+		err,
+	)
+}
+
+func baselineDescription(baseline config.TopicOffsetBaseline) string {
+	if baseline.Position == config.OffsetBaselinePositionTimestamp {
+		return fmt.Sprintf("%s (%s)", baseline.Position, baseline.Timestamp)
+	}
+	return string(baseline.Position)
+}
+
 func (t *TopicApplier) updateReplication(
 	ctx context.Context,
 	topicInfo admin.TopicInfo,
@@ -480,6 +832,10 @@ func (t *TopicApplier) updatePartitions(
 			currPartitions,
 		)
 	} else if currPartitions < t.topicConfig.Spec.Partitions {
+		if err := t.checkProtected("change its partition count"); err != nil {
+			return err
+		}
+
 		lock, path, err := t.acquireClusterLock(ctx)
 		if err != nil {
 			return err
@@ -500,6 +856,27 @@ func (t *TopicApplier) updatePartitions(
 	return nil
 }
 
+// checkProtected returns an error if the topic is protected and protected topic changes aren't
+// allowed. This is also the case for dry-runs so that the refusal shows up before the real
+// apply.
+func (t *TopicApplier) checkProtected(change string) error {
+	if t.config.AllowProtected {
+		return nil
+	}
+
+	reason := t.topicConfig.ProtectedReason(t.clusterConfig)
+	if reason == "" {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"Refusing to %s because topic %s is protected (%s); set --allow-protected to override",
+		change,
+		t.topicName,
+		reason,
+	)
+}
+
 func (t *TopicApplier) updatePartitionsHelper(
 	ctx context.Context,
 	desiredPlacement config.PlacementStrategy,
@@ -517,39 +894,10 @@ func (t *TopicApplier) updatePartitionsHelper(
 		desiredPlacement,
 	)
 
-	picker, err := t.getPicker(ctx)
-	if err != nil {
-		return err
-	}
-
-	var extender extenders.Extender
-
-	switch desiredPlacement {
-	case config.PlacementStrategyStatic:
-		extender = &extenders.StaticExtender{
-			Assignments: admin.ReplicasToAssignments(
-				t.topicConfig.Spec.PlacementConfig.StaticAssignments,
-			),
-		}
-	case config.PlacementStrategyInRack:
-		extender = extenders.NewBalancedExtender(
-			t.brokers,
-			true,
-			picker,
-		)
-	case config.PlacementStrategyBalancedLeaders, config.PlacementStrategyAny:
-		extender = extenders.NewBalancedExtender(
-			t.brokers,
-			false,
-			picker,
-		)
-	default:
-		return fmt.Errorf("Cannot extend using strategy %s", desiredPlacement)
-	}
-
-	desiredAssignments, err := extender.Extend(
-		t.topicName,
+	desiredAssignments, err := t.extendedAssignments(
+		ctx,
 		currAssignments,
+		desiredPlacement,
 		extraPartitions,
 	)
 	if err != nil {
@@ -579,22 +927,86 @@ func (t *TopicApplier) updatePartitionsHelper(
 		return errors.New("Stopping because of user response")
 	}
 
-	err = t.updatePartitionsIteration(ctx, currAssignments, desiredAssignments, true)
+	batch := progress.Event{
+		Step:       progress.StepPartitions,
+		Batch:      1,
+		Batches:    1,
+		Total:      len(desiredAssignments),
+		Partitions: admin.AssignmentIDs(desiredAssignments),
+	}
+	t.reportBatch(batch, progress.EventTypeBatchStarted)
+
+	err = t.updatePartitionsIteration(ctx, currAssignments, desiredAssignments, true, batch)
 	if err != nil {
 		return err
 	}
 
+	batch.Completed = batch.Total
+	t.reportBatch(batch, progress.EventTypeBatchCompleted)
+
 	topicInfo, err = t.adminClient.GetTopic(ctx, t.topicName, true)
 	if err != nil {
 		return err
 	}
 
-	log.Infof(
-		"Update complete; new partition status:\n%s",
-		admin.FormatTopicPartitions(topicInfo.Partitions, t.brokers),
+	log.Infof(
+		"Update complete; new partition status:\n%s",
+		admin.FormatTopicPartitions(topicInfo.Partitions, t.brokers),
+	)
+
+	return nil
+}
+
+// extendedAssignments returns the argument assignments with extraPartitions new partitions
+// added to the end in accordance with the argument placement strategy.
+func (t *TopicApplier) extendedAssignments(
+	ctx context.Context,
+	currAssignments []admin.PartitionAssignment,
+	desiredPlacement config.PlacementStrategy,
+	extraPartitions int,
+) ([]admin.PartitionAssignment, error) {
+	picker, err := t.getPicker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var extender extenders.Extender
+
+	switch desiredPlacement {
+	case config.PlacementStrategyStatic:
+		extender = &extenders.StaticExtender{
+			Assignments: admin.ReplicasToAssignments(
+				t.topicConfig.Spec.PlacementConfig.StaticAssignments,
+			),
+		}
+	case config.PlacementStrategyInRack:
+		extender = extenders.NewBalancedExtender(
+			t.brokers,
+			true,
+			picker,
+		)
+	case config.PlacementStrategyBalancedLeaders,
+		config.PlacementStrategyAny,
+		config.PlacementStrategyBalancedSize,
+		config.PlacementStrategyBalancedLeaderLoad,
+		config.PlacementStrategyStaticRackCounts,
+		config.PlacementStrategyCustom:
+		// For static-rack-counts, balanced-leader-load, and custom, the new partitions are fixed
+		// up in the subsequent placement update.
+		extender = extenders.NewBalancedExtender(
+			t.brokers,
+			false,
+			picker,
+		)
+	default:
+		return nil, fmt.Errorf("Cannot extend using strategy %s", desiredPlacement)
+	}
+
+	return extender.Extend(
+		t.topicName,
+		currAssignments,
+		extraPartitions,
 	)
-
-	return nil
 }
 
 func (t *TopicApplier) updatePlacement(
@@ -611,11 +1023,7 @@ func (t *TopicApplier) updatePlacement(
 	}
 	currAssignments := topicInfo.ToAssignments()
 
-	result, err := assigners.EvaluateAssignments(
-		currAssignments,
-		t.brokers,
-		t.topicConfig.Spec.PlacementConfig,
-	)
+	result, err := t.placementSatisfied(ctx, currAssignments)
 	if err != nil {
 		return err
 	}
@@ -628,6 +1036,10 @@ func (t *TopicApplier) updatePlacement(
 	}
 
 	if !newTopic {
+		if err := t.checkProtected("change its partition placement"); err != nil {
+			return err
+		}
+
 		// New topics don't use throttles, so there's no reason to lock
 		lock, path, err := t.acquireClusterLock(ctx)
 		if err != nil {
@@ -647,9 +1059,14 @@ func (t *TopicApplier) updatePlacement(
 	}
 
 	switch desiredPlacement {
-	case config.PlacementStrategyStatic,
+	case config.PlacementStrategyAny,
+		config.PlacementStrategyBalancedSize,
+		config.PlacementStrategyBalancedLeaderLoad,
+		config.PlacementStrategyStatic,
 		config.PlacementStrategyStaticInRack,
-		config.PlacementStrategyBalancedLeaders:
+		config.PlacementStrategyStaticRackCounts,
+		config.PlacementStrategyBalancedLeaders,
+		config.PlacementStrategyCustom:
 		return t.updatePlacementHelper(
 			ctx,
 			desiredPlacement,
@@ -702,12 +1119,76 @@ func (t *TopicApplier) updateBalance(
 ) error {
 	log.Info("Running rebalance...")
 
-	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, true)
+	currAssignments, desiredAssignments, err := t.rebalancedAssignments(ctx, true)
 	if err != nil {
 		return err
 	}
+
+	assignmentsToUpdate := admin.AssignmentsToUpdate(
+		currAssignments,
+		desiredAssignments,
+	)
+	if len(assignmentsToUpdate) == 0 {
+		return nil
+	}
+	if err := t.checkProtected("rebalance its replicas"); err != nil {
+		return err
+	}
+
+	return t.updatePlacementRunner(
+		ctx,
+		currAssignments,
+		desiredAssignments,
+		batchSize,
+		false,
+	)
+}
+
+// rebalancedAssignments returns the current assignments of the topic along with the ones that
+// would make it balanced. If spendBudget is set, the replica moves are recorded in the move
+// budget of the applier config, if there is one.
+func (t *TopicApplier) rebalancedAssignments(
+	ctx context.Context,
+	spendBudget bool,
+) ([]admin.PartitionAssignment, []admin.PartitionAssignment, error) {
+	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, true)
+	if err != nil {
+		return nil, nil, err
+	}
 	currAssignments := topicInfo.ToAssignments()
 
+	if t.config.MoveBudget != nil {
+		desiredAssignments, err := t.incrementalAssignments(ctx, currAssignments, spendBudget)
+		if err != nil {
+			return nil, nil, err
+		}
+		return currAssignments, desiredAssignments, nil
+	}
+
+	strategy := t.topicConfig.Spec.PlacementConfig.Strategy
+
+	if t.config.PartitionThroughputs != nil {
+		if supportsWeightedRebalance(strategy) {
+			desiredAssignments, err := t.throughputBalancedAssignments(ctx, currAssignments)
+			if err != nil {
+				return nil, nil, err
+			}
+			return currAssignments, desiredAssignments, nil
+		}
+
+		log.Warnf(
+			"Throughput rebalances aren't supported for strategy '%s'; rebalancing topic %s by replica counts instead",
+			strategy,
+			t.topicName,
+		)
+	} else if strategy == config.PlacementStrategyBalancedSize {
+		desiredAssignments, err := t.sizeBalancedAssignments(ctx, currAssignments)
+		if err != nil {
+			return nil, nil, err
+		}
+		return currAssignments, desiredAssignments, nil
+	}
+
 	// TODO: Make these parameters configurable?
 	rebalancer := rebalancers.NewFrequencyRebalancer(
 		t.brokers,
@@ -720,24 +1201,207 @@ func (t *TopicApplier) updateBalance(
 		t.config.BrokersToRemove,
 	)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	assignmentsToUpdate := admin.AssignmentsToUpdate(
+	return currAssignments, desiredAssignments, nil
+}
+
+// sizeBalancedAssignments returns the assignments that even out the on-disk size of the replicas
+// on each broker, based on the current sizes of the topic's partitions and of the other topics
+// on each broker. Replicas on the brokers that are being removed are moved off of them.
+func (t *TopicApplier) sizeBalancedAssignments(
+	ctx context.Context,
+	currAssignments []admin.PartitionAssignment,
+) ([]admin.PartitionAssignment, error) {
+	log.Info("Getting log dirs for balanced-size rebalance")
+
+	logDirs, err := t.adminClient.GetLogDirs(ctx, admin.BrokerIDs(t.brokers))
+	if err != nil {
+		return nil, fmt.Errorf("Error getting log dirs to compare replica sizes: %+v", err)
+	}
+
+	return t.weightedAssignments(
+		ctx,
 		currAssignments,
-		desiredAssignments,
+		admin.GetPartitionSizes(logDirs, t.topicName),
+		admin.GetOtherTopicsBrokerBytes(logDirs, t.topicName),
 	)
-	if len(assignmentsToUpdate) == 0 {
-		return nil
+}
+
+// incrementalAssignments returns the assignments that improve the balance of the topic within
+// what's left of the move budget in the applier config, moving the replicas with the best
+// improvement per byte first. Replicas are weighted by throughput if partition throughputs are
+// set, by size for balanced-size topics, and equally otherwise. The budget can be shared with the
+// appliers of other topics, so the moves are spent from it if spendBudget is set.
+func (t *TopicApplier) incrementalAssignments(
+	ctx context.Context,
+	currAssignments []admin.PartitionAssignment,
+	spendBudget bool,
+) ([]admin.PartitionAssignment, error) {
+	if t.config.MoveBudget.Exhausted() && len(t.config.BrokersToRemove) == 0 {
+		log.Infof("Move budget has been used up, so not moving any replicas in %s", t.topicName)
+		return admin.CopyAssignments(currAssignments), nil
 	}
 
-	return t.updatePlacementRunner(
-		ctx,
+	log.Info("Getting log dirs for incremental rebalance")
+
+	logDirs, err := t.adminClient.GetLogDirs(ctx, admin.BrokerIDs(t.brokers))
+	if err != nil {
+		return nil, fmt.Errorf("Error getting log dirs to compare replica sizes: %+v", err)
+	}
+	sizes := admin.GetPartitionSizes(logDirs, t.topicName)
+
+	var weights map[int]int64
+	var baseWeights map[int]int64
+
+	if t.config.PartitionThroughputs != nil {
+		weights, baseWeights, err = t.throughputWeights(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else if t.topicConfig.Spec.PlacementConfig.Strategy == config.PlacementStrategyBalancedSize {
+		weights = sizes
+		baseWeights = admin.GetOtherTopicsBrokerBytes(logDirs, t.topicName)
+	} else {
+		weights = map[int]int64{}
+		for _, assignment := range currAssignments {
+			weights[assignment.ID] = 1
+		}
+	}
+
+	rebalancer := rebalancers.NewIncrementalRebalancer(
+		t.brokers,
+		t.topicConfig.Spec.PlacementConfig,
+		weights,
+		baseWeights,
+		sizes,
+		t.config.MoveBudget,
+	)
+	desiredAssignments, err := rebalancer.Rebalance(
+		t.topicName,
 		currAssignments,
-		desiredAssignments,
-		batchSize,
-		false,
+		t.config.BrokersToRemove,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if spendBudget {
+		t.config.MoveBudget.Spend(
+			rebalancers.ReplicaMoves(currAssignments, desiredAssignments, sizes),
+		)
+	}
+	return desiredAssignments, nil
+}
+
+// throughputBalancedAssignments returns the assignments that even out the throughput of the
+// replicas on each broker, based on the partition throughputs in the applier config.
+func (t *TopicApplier) throughputBalancedAssignments(
+	ctx context.Context,
+	currAssignments []admin.PartitionAssignment,
+) ([]admin.PartitionAssignment, error) {
+	weights, baseWeights, err := t.throughputWeights(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.weightedAssignments(ctx, currAssignments, weights, baseWeights)
+}
+
+// throughputWeights returns the weights of the topic's partitions and the base weights of each
+// broker from the other topics, based on the partition throughputs in the applier config. Each
+// replica of a partition is weighted by the partition's throughput since the followers have to
+// fetch everything that's written to the leader.
+func (t *TopicApplier) throughputWeights(
+	ctx context.Context,
+) (map[int]int64, map[int]int64, error) {
+	log.Info("Getting all topics for throughput rebalance")
+
+	topics, err := t.adminClient.GetTopics(ctx, nil, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	baseWeights := map[int]int64{}
+	for _, topic := range topics {
+		if topic.Name == t.topicName {
+			continue
+		}
+		for _, partition := range topic.Partitions {
+			weight := throughputWeight(t.config.PartitionThroughputs[topic.Name][partition.ID])
+			for _, replica := range partition.Replicas {
+				baseWeights[replica] += weight
+			}
+		}
+	}
+
+	weights := map[int]int64{}
+	for partition, throughput := range t.config.PartitionThroughputs[t.topicName] {
+		weights[partition] = throughputWeight(throughput)
+	}
+
+	return weights, baseWeights, nil
+}
+
+// weightedAssignments returns the assignments that even out the total weight of the replicas on
+// each broker, as determined by a BalancedSizeAssigner, with the pins in the topic config
+// applied on top. Replicas on the brokers that are being removed are moved off of them.
+func (t *TopicApplier) weightedAssignments(
+	ctx context.Context,
+	currAssignments []admin.PartitionAssignment,
+	weights map[int]int64,
+	baseWeights map[int]int64,
+) ([]admin.PartitionAssignment, error) {
+	toRemoveMap := map[int]struct{}{}
+	for _, brokerID := range t.config.BrokersToRemove {
+		toRemoveMap[brokerID] = struct{}{}
+	}
+
+	brokers := []admin.BrokerInfo{}
+	for _, broker := range t.brokers {
+		if _, ok := toRemoveMap[broker.ID]; !ok {
+			brokers = append(brokers, broker)
+		}
+	}
+
+	var assigner assigners.Assigner = assigners.NewBalancedSizeAssigner(
+		brokers,
+		weights,
+		baseWeights,
 	)
+
+	if pins := t.topicConfig.Spec.PlacementConfig.Pins; len(pins) > 0 {
+		picker, err := t.getPicker(ctx)
+		if err != nil {
+			return nil, err
+		}
+		assigner = assigners.NewPinnedAssigner(assigner, brokers, pins, picker)
+	}
+
+	return assigner.Assign(t.topicName, currAssignments)
+}
+
+// supportsWeightedRebalance returns whether the replicas of topics with the argument placement
+// strategy can be moved by a BalancedSizeAssigner without breaking the strategy. This is the case
+// if the strategy only constrains the number of racks that each partition's replicas are in.
+func supportsWeightedRebalance(strategy config.PlacementStrategy) bool {
+	switch strategy {
+	case config.PlacementStrategyAny,
+		config.PlacementStrategyBalancedSize,
+		config.PlacementStrategyBalancedLeaderLoad,
+		config.PlacementStrategyCrossRack:
+		return true
+	default:
+		return false
+	}
+}
+
+// throughputWeight converts the argument throughput to an integer weight for a
+// BalancedSizeAssigner. Throughputs are scaled up first so that low ones aren't all rounded to
+// zero.
+func throughputWeight(throughput float64) int64 {
+	return int64(math.Round(throughput * 1000))
 }
 
 func (t *TopicApplier) updatePlacementHelper(
@@ -748,22 +1412,103 @@ func (t *TopicApplier) updatePlacementHelper(
 ) error {
 	log.Infof("Trying to get the partitions consistent with '%s'", desiredPlacement)
 
-	var assigner assigners.Assigner
-
 	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, true)
 	if err != nil {
 		return err
 	}
 	currAssignments := topicInfo.ToAssignments()
 
-	picker, err := t.getPicker(ctx)
+	desiredAssignments, err := t.placedAssignments(ctx, currAssignments, desiredPlacement)
 	if err != nil {
 		return err
 	}
 
+	return t.updatePlacementRunner(
+		ctx,
+		currAssignments,
+		desiredAssignments,
+		batchSize,
+		newTopic,
+	)
+}
+
+// placementSatisfied returns whether the argument assignments are consistent with the placement
+// config of the topic. For balanced-leader-load, this depends on the other topics in the cluster,
+// so the assignments are only consistent if the assigner wouldn't change them.
+func (t *TopicApplier) placementSatisfied(
+	ctx context.Context,
+	assignments []admin.PartitionAssignment,
+) (bool, error) {
+	placementConfig := t.topicConfig.Spec.PlacementConfig
+
+	satisfied, err := assigners.EvaluateAssignments(assignments, t.brokers, placementConfig)
+	if err != nil || !satisfied {
+		return satisfied, err
+	}
+	if placementConfig.Strategy != config.PlacementStrategyBalancedLeaderLoad {
+		return true, nil
+	}
+
+	placed, err := t.placedAssignments(ctx, assignments, placementConfig.Strategy)
+	if err != nil {
+		return false, err
+	}
+	return len(admin.AssignmentsToUpdate(assignments, placed)) == 0, nil
+}
+
+// otherLeaderCounts returns the number of partitions that each broker is the preferred leader of
+// in the managed topics in the cluster other than the one being applied.
+func (t *TopicApplier) otherLeaderCounts(ctx context.Context) (map[int]int, error) {
+	log.Info("Getting all topics for balanced-leader-load placement")
+
+	topics, err := t.adminClient.GetTopics(ctx, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[int]int{}
+	for _, topic := range topics {
+		if topic.Name == t.topicName || t.clusterConfig.IsUnmanagedTopic(topic.Name) {
+			continue
+		}
+		for _, partition := range topic.Partitions {
+			if len(partition.Replicas) > 0 {
+				counts[partition.Replicas[0]]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+// placedAssignments returns the assignments that make the argument ones consistent with the
+// argument placement strategy and the pins (if any) in the topic config.
+func (t *TopicApplier) placedAssignments(
+	ctx context.Context,
+	currAssignments []admin.PartitionAssignment,
+	desiredPlacement config.PlacementStrategy,
+) ([]admin.PartitionAssignment, error) {
+	var assigner assigners.Assigner
+
+	picker, err := t.getPicker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	switch desiredPlacement {
+	case config.PlacementStrategyAny, config.PlacementStrategyBalancedSize:
+		// Any placement is ok, so only the pins (if any) need to be applied
+		assigner = &assigners.StaticAssigner{
+			Assignments: currAssignments,
+		}
 	case config.PlacementStrategyBalancedLeaders:
 		assigner = assigners.NewBalancedLeaderAssigner(t.brokers, picker)
+	case config.PlacementStrategyBalancedLeaderLoad:
+		baseLeaders, err := t.otherLeaderCounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		assigner = assigners.NewBalancedLeaderLoadAssigner(t.brokers, baseLeaders)
 	case config.PlacementStrategyInRack:
 		assigner = assigners.NewSingleRackAssigner(t.brokers, picker)
 	case config.PlacementStrategyCrossRack:
@@ -780,22 +1525,34 @@ func (t *TopicApplier) updatePlacementHelper(
 			t.topicConfig.Spec.PlacementConfig.StaticRackAssignments,
 			picker,
 		)
+	case config.PlacementStrategyStaticRackCounts:
+		assigner = assigners.NewStaticRackCountAssigner(
+			t.brokers,
+			t.topicConfig.Spec.PlacementConfig.StaticRackReplicaCounts,
+			picker,
+		)
+	case config.PlacementStrategyCustom:
+		plugin, err := assigners.GetAssignerPlugin(t.topicConfig.Spec.PlacementConfig.Assigner)
+		if err != nil {
+			return nil, err
+		}
+		assigner, err = plugin.NewAssigner(
+			t.brokers,
+			picker,
+			t.topicConfig.Spec.PlacementConfig.AssignerOptions,
+		)
+		if err != nil {
+			return nil, err
+		}
 	default:
-		return fmt.Errorf("Cannot update using strategy %s", desiredPlacement)
+		return nil, fmt.Errorf("Cannot update using strategy %s", desiredPlacement)
 	}
 
-	desiredAssignments, err := assigner.Assign(t.topicName, currAssignments)
-	if err != nil {
-		return err
+	if pins := t.topicConfig.Spec.PlacementConfig.Pins; len(pins) > 0 {
+		assigner = assigners.NewPinnedAssigner(assigner, t.brokers, pins, picker)
 	}
 
-	return t.updatePlacementRunner(
-		ctx,
-		currAssignments,
-		desiredAssignments,
-		batchSize,
-		newTopic,
-	)
+	return assigner.Assign(t.topicName, currAssignments)
 }
 
 func (t *TopicApplier) updatePlacementRunner(
@@ -854,6 +1611,8 @@ func (t *TopicApplier) updatePlacementRunner(
 		)
 	}
 
+	numBatches := (len(assignmentsToUpdate) + batchSize - 1) / batchSize
+
 	for i := 0; i < len(assignmentsToUpdate); i += batchSize {
 		end := i + batchSize
 
@@ -861,16 +1620,30 @@ func (t *TopicApplier) updatePlacementRunner(
 			end = len(assignmentsToUpdate)
 		}
 
+		batch := progress.Event{
+			Step:       progress.StepPlacement,
+			Batch:      i/batchSize + 1,
+			Batches:    numBatches,
+			Completed:  i,
+			Total:      len(assignmentsToUpdate),
+			Partitions: admin.AssignmentIDs(assignmentsToUpdate[i:end]),
+		}
+		t.reportBatch(batch, progress.EventTypeBatchStarted)
+
 		err := t.updatePartitionsIteration(
 			ctx,
 			currDiffAssignments[i:end],
 			assignmentsToUpdate[i:end],
 			newTopic,
+			batch,
 		)
 		if err != nil {
 			return err
 		}
 
+		batch.Completed = end
+		t.reportBatch(batch, progress.EventTypeBatchCompleted)
+
 		ok, _ := Confirm("OK to continue?", t.config.SkipConfirm)
 		if !ok {
 			return errors.New("Stopping because of user response")
@@ -895,6 +1668,7 @@ func (t *TopicApplier) updatePartitionsIteration(
 	currAssignments []admin.PartitionAssignment,
 	assignmentsToUpdate []admin.PartitionAssignment,
 	newTopic bool,
+	batch progress.Event,
 ) error {
 	idsToUpdate := []int{}
 	for _, assignment := range assignmentsToUpdate {
@@ -985,6 +1759,9 @@ outerLoop:
 			}
 			log.Infof(">>> Not ready: %+v", notReady)
 
+			batch.NotReady = admin.PartitionIDs(notReady)
+			t.reportBatch(batch, progress.EventTypeBatchWaiting)
+
 			log.Infof(
 				"%d/%d partitions have not picked up the update and/or have out-of-sync replicas:\n%s",
 				len(notReady),
@@ -1084,16 +1861,7 @@ func (t *TopicApplier) removeThottles(
 		_, topicErr := t.adminClient.UpdateTopicConfig(
 			ctx,
 			t.topicName,
-			[]kafka.ConfigEntry{
-				{
-					ConfigName:  admin.LeaderReplicasThrottledKey,
-					ConfigValue: "",
-				},
-				{
-					ConfigName:  admin.FollowerReplicasThrottledKey,
-					ConfigValue: "",
-				},
-			},
+			admin.ClearedPartitionThrottleConfigEntries(),
 			true,
 		)
 		if topicErr != nil {
@@ -1110,16 +1878,7 @@ func (t *TopicApplier) removeThottles(
 		_, brokerErr := t.adminClient.UpdateBrokerConfig(
 			ctx,
 			throttledBroker,
-			[]kafka.ConfigEntry{
-				{
-					ConfigName:  admin.LeaderThrottledKey,
-					ConfigValue: "",
-				},
-				{
-					ConfigName:  admin.FollowerThrottledKey,
-					ConfigValue: "",
-				},
-			},
+			admin.ClearedBrokerThrottleConfigEntries(),
 			true,
 		)
 		if brokerErr != nil {
@@ -1191,6 +1950,7 @@ func (t *TopicApplier) updateLeaders(
 		}
 
 		partitionIDs := admin.PartitionIDs(wrongLeaders)
+		numBatches := (len(partitionIDs) + batchSize - 1) / batchSize
 
 		for i := 0; i < len(partitionIDs); i += batchSize {
 			end := i + batchSize
@@ -1199,13 +1959,27 @@ func (t *TopicApplier) updateLeaders(
 				end = len(partitionIDs)
 			}
 
+			batch := progress.Event{
+				Step:       progress.StepLeaders,
+				Batch:      i/batchSize + 1,
+				Batches:    numBatches,
+				Completed:  i,
+				Total:      len(partitionIDs),
+				Partitions: partitionIDs[i:end],
+			}
+			t.reportBatch(batch, progress.EventTypeBatchStarted)
+
 			err := t.updateLeadersIteration(
 				ctx,
 				partitionIDs[i:end],
+				batch,
 			)
 			if err != nil {
 				return err
 			}
+
+			batch.Completed = end
+			t.reportBatch(batch, progress.EventTypeBatchCompleted)
 		}
 	}
 
@@ -1215,6 +1989,7 @@ func (t *TopicApplier) updateLeaders(
 func (t *TopicApplier) updateLeadersIteration(
 	ctx context.Context,
 	electionPartitions []int,
+	batch progress.Event,
 ) error {
 	log.Infof("Running leader elections for partitions %+v", electionPartitions)
 
@@ -1258,6 +2033,9 @@ outerLoop:
 				admin.FormatTopicPartitions(wrongLeaders, t.brokers),
 			)
 
+			batch.NotReady = admin.PartitionIDs(wrongLeaders)
+			t.reportBatch(batch, progress.EventTypeBatchWaiting)
+
 			log.Infof("Sleeping for %s", t.config.SleepLoopDuration.String())
 		case <-ctx.Done():
 			return ctx.Err()
@@ -1267,6 +2045,35 @@ outerLoop:
 	return nil
 }
 
+// reportProgress fills in the topic details of the argument event and sends it to the
+// configured progress reporter, if there is one.
+func (t *TopicApplier) reportProgress(event progress.Event) {
+	event.Cluster = t.clusterConfig.Meta.Name
+	event.Topic = t.topicName
+	event.DryRun = t.config.DryRun
+	t.config.Progress.Report(event)
+}
+
+// reportBatch reports the argument batch event with the argument type.
+func (t *TopicApplier) reportBatch(batch progress.Event, eventType progress.EventType) {
+	batch.Type = eventType
+	t.reportProgress(batch)
+}
+
+// reportDone reports the completion or failure of an apply or rebalance.
+func (t *TopicApplier) reportDone(err error) {
+	if err != nil {
+		t.reportProgress(
+			progress.Event{
+				Type:  progress.EventTypeFailed,
+				Error: fmt.Sprintf("%+v", err),
+			},
+		)
+		return
+	}
+	t.reportProgress(progress.Event{Type: progress.EventTypeCompleted})
+}
+
 func (t *TopicApplier) acquireClusterLock(ctx context.Context) (zk.Lock, string, error) {
 	if t.config.DryRun || t.clusterConfig.Spec.ZKLockPath == "" {
 		return nil, "", nil
@@ -1362,6 +2169,10 @@ func (t *TopicApplier) getPicker(ctx context.Context) (pickers.Picker, error) {
 		)
 	}
 
+	if admin.HasBrokerWeights(t.brokers) {
+		picker = pickers.NewWeightedPicker(picker, admin.BrokerWeights(t.brokers))
+	}
+
 	return picker, nil
 }
 
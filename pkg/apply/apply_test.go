@@ -9,6 +9,7 @@ import (
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
 	"github.com/segmentio/topicctl/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -368,6 +369,115 @@ func TestApplyExtendPartitions(t *testing.T) {
 	assert.False(t, topicInfo.IsThrottled())
 }
 
+func TestApplyProtected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	topicName := util.RandomString("apply-topic-protected-", 6)
+	topicConfig := config.TopicConfig{
+		Meta: config.TopicMeta{
+			Name:        topicName,
+			Cluster:     "test-cluster",
+			Region:      "test-region",
+			Environment: "test-environment",
+			Protected:   true,
+		},
+		Spec: config.TopicSpec{
+			Partitions:        3,
+			ReplicationFactor: 2,
+			RetentionMinutes:  500,
+			PlacementConfig: config.TopicPlacementConfig{
+				Strategy: config.PlacementStrategyAny,
+			},
+			MigrationConfig: &config.TopicMigrationConfig{
+				ThrottleMB:         2,
+				PartitionBatchSize: 3,
+			},
+		},
+	}
+
+	// Protected topics can still be created
+	applier := testApplier(ctx, t, topicConfig)
+	defer applier.adminClient.Close()
+	err := applier.Apply(ctx)
+	require.NoError(t, err)
+
+	// Extending the partitions is refused, including in dry-runs
+	applier.topicConfig.Spec.Partitions = 6
+	applier.config.DryRun = true
+	err = applier.Apply(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is protected")
+
+	applier.config.DryRun = false
+	err = applier.Apply(ctx)
+	require.Error(t, err)
+
+	topicInfo, err := applier.adminClient.GetTopic(ctx, topicName, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(topicInfo.Partitions))
+
+	applier.config.AllowProtected = true
+	err = applier.Apply(ctx)
+	require.NoError(t, err)
+
+	topicInfo, err = applier.adminClient.GetTopic(ctx, topicName, true)
+	require.NoError(t, err)
+	assert.Equal(t, 6, len(topicInfo.Partitions))
+}
+
+func TestApplyOffsetBaselines(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	topicName := util.RandomString("apply-topic-baselines-", 6)
+	groupID := util.RandomString("apply-group-baselines-", 6)
+	topicConfig := config.TopicConfig{
+		Meta: config.TopicMeta{
+			Name:        topicName,
+			Cluster:     "test-cluster",
+			Region:      "test-region",
+			Environment: "test-environment",
+		},
+		Spec: config.TopicSpec{
+			Partitions:        2,
+			ReplicationFactor: 2,
+			RetentionMinutes:  500,
+			PlacementConfig: config.TopicPlacementConfig{
+				Strategy: config.PlacementStrategyAny,
+			},
+			OffsetBaselines: []config.TopicOffsetBaseline{
+				{
+					Group:    groupID,
+					Position: config.OffsetBaselinePositionLatest,
+				},
+			},
+		},
+	}
+
+	applier := testApplier(ctx, t, topicConfig)
+	defer applier.adminClient.Close()
+	err := applier.Apply(ctx)
+	require.NoError(t, err)
+
+	states, err := groups.GetPartitionOffsetStates(
+		ctx,
+		applier.adminClient.GetConnector(),
+		topicName,
+		groupID,
+		[]int{0, 1},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(states))
+	for _, state := range states {
+		assert.Equal(t, state.EndOffset, state.CommittedOffset)
+	}
+
+	// Later applies leave the committed offsets alone
+	err = applier.Apply(ctx)
+	require.NoError(t, err)
+}
+
 func TestApplyExistingThrottles(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
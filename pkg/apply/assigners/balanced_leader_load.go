@@ -0,0 +1,136 @@
+package assigners
+
+import (
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// BalancedLeaderLoadAssigner is an Assigner that reorders the replicas of each partition so that
+// the preferred leaders (i.e., the first replicas) are spread as evenly as possible across the
+// brokers, counting the preferred leaders of other topics as well as the ones of the topic being
+// assigned. The set of replicas in each partition isn't changed, so no data needs to be moved.
+// The algorithm is:
+//
+//	while true:
+//	  for each partition:
+//	    for each non-leader replica:
+//	      score the swap of the leader and the replica by how much it improves the balance
+//	  if no swap improves the balance, stop
+//	  make the swap with the best score
+//
+// The balance is measured by the sum of the squared leader counts of the brokers, with the sum
+// of the squared leader counts of the racks used to break ties. Since each swap either reduces
+// the first sum or keeps it the same and reduces the second one, the loop always terminates.
+//
+// If the brokers have capacity weights, each broker's squared leader count is divided by its
+// weight, so that the brokers end up with leaders in proportion to their weights.
+type BalancedLeaderLoadAssigner struct {
+	brokers       []admin.BrokerInfo
+	brokerRacks   map[int]string
+	brokerWeights map[int]float64
+	baseLeaders   map[int]int
+}
+
+var _ Assigner = (*BalancedLeaderLoadAssigner)(nil)
+
+// NewBalancedLeaderLoadAssigner creates and returns a BalancedLeaderLoadAssigner instance. The
+// baseLeaders map has the number of partitions of other topics that each broker is the
+// preferred leader of; brokers that are missing from it are assumed to have none.
+func NewBalancedLeaderLoadAssigner(
+	brokers []admin.BrokerInfo,
+	baseLeaders map[int]int,
+) *BalancedLeaderLoadAssigner {
+	return &BalancedLeaderLoadAssigner{
+		brokers:       brokers,
+		brokerRacks:   admin.BrokerRacks(brokers),
+		brokerWeights: admin.BrokerWeights(brokers),
+		baseLeaders:   baseLeaders,
+	}
+}
+
+// Assign returns a new partition assignment according to the assigner-specific logic.
+func (b *BalancedLeaderLoadAssigner) Assign(
+	topic string,
+	curr []admin.PartitionAssignment,
+) ([]admin.PartitionAssignment, error) {
+	if err := admin.CheckAssignments(curr); err != nil {
+		return nil, err
+	}
+
+	desired := admin.CopyAssignments(curr)
+
+	brokerLeaders := map[int]int{}
+	for _, broker := range b.brokers {
+		brokerLeaders[broker.ID] = b.baseLeaders[broker.ID]
+	}
+	rackLeaders := map[string]int{}
+	for brokerID, count := range brokerLeaders {
+		rackLeaders[b.brokerRacks[brokerID]] += count
+	}
+	for _, assignment := range desired {
+		leader := assignment.Replicas[0]
+		brokerLeaders[leader]++
+		rackLeaders[b.brokerRacks[leader]]++
+	}
+
+	for {
+		bestPartition := -1
+		bestIndex := -1
+		var bestBrokerDelta float64
+		var bestRackDelta int
+
+		for a, assignment := range desired {
+			leader := assignment.Replicas[0]
+			leaderRack := b.brokerRacks[leader]
+
+			for r := 1; r < len(assignment.Replicas); r++ {
+				replica := assignment.Replicas[r]
+				replicaRack := b.brokerRacks[replica]
+
+				// These are the changes in the sums of squared counts if the replica becomes
+				// the leader, with the broker ones divided by the broker weights.
+				brokerDelta := float64(2*brokerLeaders[replica]+1)/b.weight(replica) -
+					float64(2*brokerLeaders[leader]-1)/b.weight(leader)
+				rackDelta := 0
+				if replicaRack != leaderRack {
+					rackDelta = 2 * (rackLeaders[replicaRack] - rackLeaders[leaderRack] + 1)
+				}
+
+				if brokerDelta > 0 || (brokerDelta == 0 && rackDelta >= 0) {
+					continue
+				}
+				if bestPartition == -1 ||
+					brokerDelta < bestBrokerDelta ||
+					(brokerDelta == bestBrokerDelta && rackDelta < bestRackDelta) {
+					bestPartition = a
+					bestIndex = r
+					bestBrokerDelta = brokerDelta
+					bestRackDelta = rackDelta
+				}
+			}
+		}
+
+		if bestPartition == -1 {
+			break
+		}
+
+		replicas := desired[bestPartition].Replicas
+		leader := replicas[0]
+		replica := replicas[bestIndex]
+
+		replicas[0], replicas[bestIndex] = replica, leader
+		brokerLeaders[leader]--
+		brokerLeaders[replica]++
+		rackLeaders[b.brokerRacks[leader]]--
+		rackLeaders[b.brokerRacks[replica]]++
+	}
+
+	return desired, nil
+}
+
+// weight returns the capacity weight of the argument broker, or 1 if it isn't known.
+func (b *BalancedLeaderLoadAssigner) weight(brokerID int) float64 {
+	if weight, ok := b.brokerWeights[brokerID]; ok {
+		return weight
+	}
+	return 1.0
+}
@@ -0,0 +1,124 @@
+package assigners
+
+import (
+	"testing"
+)
+
+func TestBalancedLeaderLoadAssigner(t *testing.T) {
+	brokers := testBrokers(4, 2)
+
+	testCases := []struct {
+		assignerTestCase
+		baseLeaders map[int]int
+	}{
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Already balanced",
+				curr: [][]int{
+					{1, 2},
+					{2, 3},
+					{3, 4},
+					{4, 1},
+				},
+				expected: [][]int{
+					{1, 2},
+					{2, 3},
+					{3, 4},
+					{4, 1},
+				},
+			},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Leaders swapped within partitions",
+				curr: [][]int{
+					{1, 2},
+					{1, 3},
+					{1, 4},
+					{1, 2},
+				},
+				expected: [][]int{
+					{2, 1},
+					{3, 1},
+					{4, 1},
+					{1, 2},
+				},
+			},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Other topics are considered",
+				curr: [][]int{
+					{1, 2},
+					{3, 4},
+				},
+				expected: [][]int{
+					{2, 1},
+					{4, 3},
+				},
+			},
+			baseLeaders: map[int]int{1: 3, 2: 0, 3: 3, 4: 0},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Racks break ties",
+				curr: [][]int{
+					// Brokers 1 and 3 are in zone1 and brokers 2 and 4 are in zone2
+					{1, 2},
+					{3, 4},
+				},
+				expected: [][]int{
+					// Swapping the first partition doesn't change the broker balance, but it
+					// does improve the rack one
+					{2, 1},
+					{4, 3},
+				},
+			},
+			baseLeaders: map[int]int{1: 1, 2: 1, 3: 2, 4: 0},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Replica sets can't be changed",
+				curr: [][]int{
+					{1, 2},
+					{2, 1},
+					{1, 2},
+					{2, 1},
+				},
+				expected: [][]int{
+					{1, 2},
+					{2, 1},
+					{1, 2},
+					{2, 1},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase.evaluate(t, NewBalancedLeaderLoadAssigner(brokers, testCase.baseLeaders))
+	}
+}
+
+func TestBalancedLeaderLoadAssignerBrokerWeights(t *testing.T) {
+	brokers := testBrokers(4, 2)
+	brokers[0].Weight = 4.0
+
+	testCase := assignerTestCase{
+		description: "Broker weights",
+		curr: [][]int{
+			{2, 1},
+			{3, 1},
+			{4, 1},
+			{2, 1},
+		},
+		// Broker 1 has four times the capacity of the others, so it leads half of the partitions
+		expected: [][]int{
+			{1, 2},
+			{1, 3},
+			{4, 1},
+			{2, 1},
+		},
+	}
+	testCase.evaluate(t, NewBalancedLeaderLoadAssigner(brokers, nil))
+}
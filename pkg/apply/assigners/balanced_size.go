@@ -0,0 +1,260 @@
+package assigners
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// BalancedSizeAssigner is an Assigner that moves replicas between brokers so that the total
+// on-disk size of the replicas on each broker is as even as possible. Unlike the other
+// assigners, which only look at replica counts, it weights each replica by the size of its
+// partition. The algorithm is:
+//
+//	for each replica on a broker that isn't in the broker list (e.g., one being removed):
+//	  replace it with the broker with the fewest bytes that isn't already in the partition
+//
+//	while true:
+//	  for each broker, in order of decreasing bytes:
+//	    for each broker with fewer bytes, in order of increasing bytes:
+//	      find the replica on the first broker whose move to the second one reduces the gap
+//	        between them the most
+//	      if there is one, make the move and continue to the next while loop iteration
+//	  if no move made, stop
+//
+// A move from a broker with A bytes to one with B bytes of a partition with S bytes is only
+// made if 0 < S < A - B, so each move strictly reduces the sum of squared broker sizes and the
+// loop always terminates. Empty partitions are never moved. Moves that would reduce the number
+// of racks that a partition's replicas are spread across are also skipped; beyond that, racks
+// and leaders aren't considered.
+//
+// If the brokers have capacity weights, the bytes on each broker are divided by its weight
+// wherever they're compared, so that the brokers end up with bytes in proportion to their
+// weights. In that case, a move is made if it reduces the sum of the squared broker sizes divided
+// by the broker weights.
+//
+// The sizes don't have to be in bytes; throughput rebalances, for instance, use the throughputs of
+// the partitions instead.
+type BalancedSizeAssigner struct {
+	brokers        []admin.BrokerInfo
+	brokerRacks    map[int]string
+	brokerWeights  map[int]float64
+	partitionSizes map[int]int64
+	baseBytes      map[int]int64
+}
+
+var _ Assigner = (*BalancedSizeAssigner)(nil)
+
+// NewBalancedSizeAssigner creates and returns a BalancedSizeAssigner instance. The
+// partitionSizes map is keyed by partition ID, and the baseBytes map has the bytes on each
+// broker that aren't from the topic being assigned, e.g. from other topics; partitions and
+// brokers that are missing from these are assumed to have zero bytes.
+func NewBalancedSizeAssigner(
+	brokers []admin.BrokerInfo,
+	partitionSizes map[int]int64,
+	baseBytes map[int]int64,
+) *BalancedSizeAssigner {
+	return &BalancedSizeAssigner{
+		brokers:        brokers,
+		brokerRacks:    admin.BrokerRacks(brokers),
+		brokerWeights:  admin.BrokerWeights(brokers),
+		partitionSizes: partitionSizes,
+		baseBytes:      baseBytes,
+	}
+}
+
+// Assign returns a new partition assignment according to the assigner-specific logic.
+func (s *BalancedSizeAssigner) Assign(
+	topic string,
+	curr []admin.PartitionAssignment,
+) ([]admin.PartitionAssignment, error) {
+	if err := admin.CheckAssignments(curr); err != nil {
+		return nil, err
+	}
+	if len(s.brokers) < len(curr[0].Replicas) {
+		return nil, fmt.Errorf(
+			"Do not have enough brokers (%d) for replication factor (%d)",
+			len(s.brokers),
+			len(curr[0].Replicas),
+		)
+	}
+
+	desired := admin.CopyAssignments(curr)
+
+	brokerBytes := map[int]int64{}
+	for _, broker := range s.brokers {
+		brokerBytes[broker.ID] = s.baseBytes[broker.ID]
+	}
+	for _, assignment := range desired {
+		for _, replica := range assignment.Replicas {
+			if _, ok := brokerBytes[replica]; ok {
+				brokerBytes[replica] += s.partitionSizes[assignment.ID]
+			}
+		}
+	}
+
+	// First, move the replicas that are on brokers that can't be used
+	for a, assignment := range desired {
+		for r, replica := range assignment.Replicas {
+			if _, ok := brokerBytes[replica]; ok {
+				continue
+			}
+
+			// Prefer brokers that keep the partition in as many racks as possible
+			target := -1
+			for _, checkRacks := range []bool{true, false} {
+				for _, broker := range s.sortedBrokers(brokerBytes, true) {
+					if desired[a].Index(broker) != -1 {
+						continue
+					}
+					if checkRacks && !s.keepsRacks(desired[a], r, broker) {
+						continue
+					}
+					target = broker
+					break
+				}
+				if target != -1 {
+					break
+				}
+			}
+			if target == -1 {
+				return nil, fmt.Errorf(
+					"Could not find a feasible replacement for broker %d in partition %d",
+					replica,
+					assignment.ID,
+				)
+			}
+
+			desired[a].Replicas[r] = target
+			brokerBytes[target] += s.partitionSizes[assignment.ID]
+		}
+	}
+
+	// Then, make moves until the balance can't be improved any further
+	for {
+		if !s.makeMove(desired, brokerBytes) {
+			break
+		}
+	}
+
+	return desired, nil
+}
+
+// makeMove makes the best move off of the broker with the most bytes that has one, updating
+// desired and brokerBytes in place. It returns whether a move was made.
+func (s *BalancedSizeAssigner) makeMove(
+	desired []admin.PartitionAssignment,
+	brokerBytes map[int]int64,
+) bool {
+	descBrokers := s.sortedBrokers(brokerBytes, false)
+	ascBrokers := s.sortedBrokers(brokerBytes, true)
+
+	for _, upper := range descBrokers {
+		for _, lower := range ascBrokers {
+			if s.weightedBytes(brokerBytes, upper) <= s.weightedBytes(brokerBytes, lower) {
+				break
+			}
+
+			bestPartition := -1
+			bestIndex := -1
+			var bestReduction float64
+
+			for a, assignment := range desired {
+				size := s.partitionSizes[assignment.ID]
+				if size <= 0 || assignment.Index(lower) != -1 {
+					continue
+				}
+
+				index := assignment.Index(upper)
+				if index == -1 || !s.keepsRacks(assignment, index, lower) {
+					continue
+				}
+
+				// This is the reduction in the sum of squared broker sizes, divided by the
+				// broker weights; ties favor follower replicas so that leaders are moved less
+				// often.
+				reduction := s.moveReduction(brokerBytes, size, upper, lower)
+				if reduction <= 0 {
+					continue
+				}
+				if reduction > bestReduction ||
+					(reduction == bestReduction && index > bestIndex) {
+					bestPartition = a
+					bestIndex = index
+					bestReduction = reduction
+				}
+			}
+
+			if bestPartition != -1 {
+				size := s.partitionSizes[desired[bestPartition].ID]
+				desired[bestPartition].Replicas[bestIndex] = lower
+				brokerBytes[upper] -= size
+				brokerBytes[lower] += size
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// moveReduction returns the reduction in the sum of the squared broker sizes, each divided by the
+// broker's weight, from moving a replica with the argument size from the upper broker to the
+// lower one.
+func (s *BalancedSizeAssigner) moveReduction(
+	brokerBytes map[int]int64,
+	size int64,
+	upper int,
+	lower int,
+) float64 {
+	moved := float64(size)
+	upperBytes := float64(brokerBytes[upper])
+	lowerBytes := float64(brokerBytes[lower])
+
+	return moved*(2*upperBytes-moved)/s.brokerWeights[upper] -
+		moved*(2*lowerBytes+moved)/s.brokerWeights[lower]
+}
+
+// weightedBytes returns the bytes on the argument broker divided by its weight.
+func (s *BalancedSizeAssigner) weightedBytes(brokerBytes map[int]int64, broker int) float64 {
+	return float64(brokerBytes[broker]) / s.brokerWeights[broker]
+}
+
+// keepsRacks returns whether replacing the replica at the argument index with the argument
+// broker keeps the partition's replicas spread across at least as many racks as before.
+func (s *BalancedSizeAssigner) keepsRacks(
+	assignment admin.PartitionAssignment,
+	index int,
+	broker int,
+) bool {
+	updated := assignment.Copy()
+	updated.Replicas[index] = broker
+
+	return len(updated.DistinctRacks(s.brokerRacks)) >=
+		len(assignment.DistinctRacks(s.brokerRacks))
+}
+
+// sortedBrokers returns the IDs of the brokers in brokerBytes sorted by their bytes divided by
+// their weights, breaking ties by ID.
+func (s *BalancedSizeAssigner) sortedBrokers(brokerBytes map[int]int64, asc bool) []int {
+	brokers := []int{}
+	for broker := range brokerBytes {
+		brokers = append(brokers, broker)
+	}
+
+	sort.Slice(brokers, func(a, b int) bool {
+		aBytes := s.weightedBytes(brokerBytes, brokers[a])
+		bBytes := s.weightedBytes(brokerBytes, brokers[b])
+
+		if aBytes == bBytes {
+			return brokers[a] < brokers[b]
+		}
+		if asc {
+			return aBytes < bBytes
+		}
+		return aBytes > bBytes
+	})
+
+	return brokers
+}
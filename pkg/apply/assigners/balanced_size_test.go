@@ -0,0 +1,224 @@
+package assigners
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalancedSizeAssigner(t *testing.T) {
+	brokers := testBrokers(4, 2)
+
+	testCases := []struct {
+		assignerTestCase
+		partitionSizes map[int]int64
+		baseBytes      map[int]int64
+	}{
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Already balanced",
+				curr: [][]int{
+					{1, 2},
+					{3, 4},
+				},
+				expected: [][]int{
+					{1, 2},
+					{3, 4},
+				},
+			},
+			partitionSizes: map[int]int64{0: 100, 1: 100},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Empty partitions aren't moved",
+				curr: [][]int{
+					{1, 2},
+					{1, 2},
+				},
+				expected: [][]int{
+					{1, 2},
+					{1, 2},
+				},
+			},
+			partitionSizes: map[int]int64{},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Balanced counts but not bytes",
+				curr: [][]int{
+					{1, 2},
+					{3, 4},
+					{1, 4},
+					{3, 2},
+				},
+				expected: [][]int{
+					{1, 2},
+					{3, 4},
+					{3, 4},
+					{3, 4},
+				},
+			},
+			partitionSizes: map[int]int64{0: 1000, 1: 10, 2: 10, 3: 10},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Other topics are considered",
+				curr: [][]int{
+					{1, 2},
+					{3, 4},
+				},
+				expected: [][]int{
+					{1, 4},
+					{3, 4},
+				},
+			},
+			partitionSizes: map[int]int64{0: 100, 1: 100},
+			baseBytes:      map[int]int64{1: 0, 2: 300, 3: 0, 4: 0},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Followers moved before leaders",
+				curr: [][]int{
+					{1, 3},
+					{3, 1},
+				},
+				expected: [][]int{
+					{1, 4},
+					{3, 2},
+				},
+			},
+			partitionSizes: map[int]int64{0: 50, 1: 50},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Racks not reduced",
+				curr: [][]int{
+					// Brokers 1 and 3 are in zone1 and brokers 2 and 4 are in zone2
+					{1, 2},
+					{1, 4},
+				},
+				expected: [][]int{
+					{3, 2},
+					{1, 4},
+				},
+			},
+			partitionSizes: map[int]int64{0: 100, 1: 100},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Removed broker",
+				curr: [][]int{
+					{1, 5},
+					{3, 4},
+				},
+				expected: [][]int{
+					{1, 2},
+					{3, 4},
+				},
+			},
+			partitionSizes: map[int]int64{0: 100, 1: 100},
+		},
+		{
+			assignerTestCase: assignerTestCase{
+				description: "Not enough brokers",
+				curr: [][]int{
+					{1, 2, 3, 4, 5},
+				},
+				err: errors.New("Do not have enough brokers"),
+			},
+			partitionSizes: map[int]int64{0: 100},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase.evaluate(
+			t,
+			NewBalancedSizeAssigner(brokers, testCase.partitionSizes, testCase.baseBytes),
+		)
+	}
+}
+
+func TestBalancedSizeAssignerEvensBytes(t *testing.T) {
+	brokers := testBrokers(6, 3)
+	partitionSizes := map[int]int64{
+		0: 500,
+		1: 400,
+		2: 300,
+		3: 200,
+		4: 100,
+		5: 100,
+	}
+
+	// All of the large partitions start out on the same brokers
+	curr := admin.ReplicasToAssignments(
+		[][]int{
+			{1, 2},
+			{1, 2},
+			{1, 2},
+			{3, 4},
+			{5, 6},
+			{5, 6},
+		},
+	)
+
+	desired, err := NewBalancedSizeAssigner(brokers, partitionSizes, nil).Assign("topic", curr)
+	require.NoError(t, err)
+	require.NoError(t, admin.CheckAssignments(desired))
+
+	brokerBytes := map[int]int64{}
+	for _, assignment := range desired {
+		assert.Equal(t, 2, len(assignment.Replicas))
+		for _, replica := range assignment.Replicas {
+			brokerBytes[replica] += partitionSizes[assignment.ID]
+		}
+	}
+
+	var minBytes, maxBytes int64 = -1, 0
+	for _, broker := range brokers {
+		bytes := brokerBytes[broker.ID]
+		if minBytes < 0 || bytes < minBytes {
+			minBytes = bytes
+		}
+		if bytes > maxBytes {
+			maxBytes = bytes
+		}
+	}
+
+	// Brokers 1 and 2 started out with 1200 bytes each and the others with 200 bytes each
+	assert.LessOrEqual(t, maxBytes-minBytes, int64(200))
+	assert.Less(t, maxBytes, int64(1200))
+}
+
+func TestBalancedSizeAssignerBrokerWeights(t *testing.T) {
+	brokers := testBrokers(4, 2)
+	brokers[0].Weight = 3.0
+
+	testCase := assignerTestCase{
+		description: "Broker weights",
+		curr: [][]int{
+			{1, 2},
+			{3, 4},
+			{1, 2},
+			{3, 4},
+		},
+		// Broker 1 has three times the capacity of the others, so it takes a replica from one of
+		// the zone1 brokers; it can't take any more without moving partitions out of zone2
+		expected: [][]int{
+			{1, 2},
+			{1, 4},
+			{1, 2},
+			{3, 4},
+		},
+	}
+	testCase.evaluate(
+		t,
+		NewBalancedSizeAssigner(
+			brokers,
+			map[int]int64{0: 100, 1: 100, 2: 100, 3: 100},
+			nil,
+		),
+	)
+}
@@ -18,12 +18,19 @@ func EvaluateAssignments(
 	if err := admin.CheckAssignments(assignments); err != nil {
 		return false, err
 	}
+	if len(PinViolations(assignments, brokers, placementConfig.Pins)) > 0 {
+		return false, nil
+	}
 
 	minRacks, maxRacks, leaderRackCounts := minMaxRacks(assignments, brokers)
 	balanced := balancedLeaders(leaderRackCounts)
 
 	switch placementConfig.Strategy {
-	case config.PlacementStrategyAny:
+	case config.PlacementStrategyAny,
+		config.PlacementStrategyBalancedSize,
+		config.PlacementStrategyBalancedLeaderLoad:
+		// The partition sizes and the leaders of the other topics in the cluster aren't known
+		// here, so these strategies are evaluated by the applier instead.
 		return true, nil
 	case config.PlacementStrategyStatic:
 		replicas, err := admin.AssignmentsToReplicas(assignments)
@@ -52,6 +59,26 @@ func EvaluateAssignments(
 			}
 		}
 
+		return true, nil
+	case config.PlacementStrategyStaticRackCounts:
+		brokerRacks := admin.BrokerRacks(brokers)
+
+		for _, assignment := range assignments {
+			rackCounts := map[string]int{}
+			for _, replica := range assignment.Replicas {
+				rackCounts[brokerRacks[replica]]++
+			}
+
+			if len(rackCounts) != len(placementConfig.StaticRackReplicaCounts) {
+				return false, nil
+			}
+			for rack, count := range placementConfig.StaticRackReplicaCounts {
+				if rackCounts[rack] != count {
+					return false, nil
+				}
+			}
+		}
+
 		return true, nil
 	case config.PlacementStrategyBalancedLeaders:
 		return balanced, nil
@@ -65,6 +92,12 @@ func EvaluateAssignments(
 			}
 		}
 		return true, nil
+	case config.PlacementStrategyCustom:
+		plugin, err := GetAssignerPlugin(placementConfig.Assigner)
+		if err != nil {
+			return false, err
+		}
+		return plugin.Evaluate(assignments, brokers, placementConfig.AssignerOptions)
 	default:
 		return false, fmt.Errorf(
 			"Unrecognized placementStrategy: %s",
@@ -26,6 +26,7 @@ func TestEvaluateAssignmentsNonStatic(t *testing.T) {
 			},
 			expectedResults: map[config.PlacementStrategy]bool{
 				config.PlacementStrategyAny:             true,
+				config.PlacementStrategyBalancedSize:    true,
 				config.PlacementStrategyStatic:          false,
 				config.PlacementStrategyStaticInRack:    false,
 				config.PlacementStrategyBalancedLeaders: false,
@@ -85,11 +86,28 @@ func TestEvaluateAssignmentsNonStatic(t *testing.T) {
 				{1, 4, 6},
 			},
 			expectedResults: map[config.PlacementStrategy]bool{
-				config.PlacementStrategyAny:             true,
-				config.PlacementStrategyStatic:          false,
-				config.PlacementStrategyStaticInRack:    false,
-				config.PlacementStrategyBalancedLeaders: false,
-				config.PlacementStrategyInRack:          false,
+				config.PlacementStrategyAny:              true,
+				config.PlacementStrategyStatic:           false,
+				config.PlacementStrategyStaticInRack:     false,
+				config.PlacementStrategyStaticRackCounts: false,
+				config.PlacementStrategyBalancedLeaders:  false,
+				config.PlacementStrategyInRack:           false,
+			},
+		},
+		{
+			// Matches static rack counts set in test run loop below
+			replicaSlices: [][]int{
+				{1, 4, 2},
+				{7, 10, 5},
+				{4, 2, 1},
+			},
+			expectedResults: map[config.PlacementStrategy]bool{
+				config.PlacementStrategyAny:              true,
+				config.PlacementStrategyStatic:           false,
+				config.PlacementStrategyStaticInRack:     false,
+				config.PlacementStrategyStaticRackCounts: true,
+				config.PlacementStrategyBalancedLeaders:  false,
+				config.PlacementStrategyInRack:           false,
 			},
 		},
 		{
@@ -153,6 +171,10 @@ func TestEvaluateAssignmentsNonStatic(t *testing.T) {
 						"zone3",
 						"zone1",
 					},
+					StaticRackReplicaCounts: map[string]int{
+						"zone1": 2,
+						"zone2": 1,
+					},
 				},
 			)
 			if testCase.expectedErr[strategy] {
@@ -0,0 +1,153 @@
+package assigners
+
+import (
+	"fmt"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply/pickers"
+	"github.com/segmentio/topicctl/pkg/config"
+)
+
+// PinnedAssigner is an Assigner that wraps another assigner and then adjusts the results so
+// that they're consistent with a set of partition leader pins.
+//
+// The following algorithm is used:
+//
+// run the wrapped assigner
+//
+// then:
+//
+// for each pin:
+//   if the current leader satisfies the pin:
+//     do nothing
+//   else if another replica in the partition satisfies the pin:
+//     swap that replica into the leader position
+//   else:
+//     replace the leader with the pinned broker or, in the case of a rack pin, use the picker
+//     to pick a broker from the pinned rack
+//
+// Note that the last case can make the result inconsistent with the placement strategy of
+// the wrapped assigner, so pins should be chosen with the strategy in mind.
+type PinnedAssigner struct {
+	assigner       Assigner
+	pins           []config.PartitionPin
+	brokerRacks    map[int]string
+	brokersPerRack map[string][]int
+	picker         pickers.Picker
+}
+
+var _ Assigner = (*PinnedAssigner)(nil)
+
+// NewPinnedAssigner returns a new PinnedAssigner instance.
+func NewPinnedAssigner(
+	assigner Assigner,
+	brokers []admin.BrokerInfo,
+	pins []config.PartitionPin,
+	picker pickers.Picker,
+) *PinnedAssigner {
+	return &PinnedAssigner{
+		assigner:       assigner,
+		pins:           pins,
+		brokerRacks:    admin.BrokerRacks(brokers),
+		brokersPerRack: admin.BrokersPerRack(brokers),
+		picker:         picker,
+	}
+}
+
+// Assign returns a new partition assignment according to the assigner-specific logic.
+func (p *PinnedAssigner) Assign(
+	topic string,
+	curr []admin.PartitionAssignment,
+) ([]admin.PartitionAssignment, error) {
+	assigned, err := p.assigner.Assign(topic, curr)
+	if err != nil {
+		return nil, err
+	}
+	desired := admin.CopyAssignments(assigned)
+
+	for _, pin := range p.pins {
+		if pin.Partition < 0 || pin.Partition >= len(desired) {
+			return nil, fmt.Errorf("Pinned partition %d is not in topic", pin.Partition)
+		}
+		replicas := desired[pin.Partition].Replicas
+
+		if pin.Leader != nil {
+			leader := *pin.Leader
+			if _, ok := p.brokerRacks[leader]; !ok {
+				return nil, fmt.Errorf(
+					"Pinned leader %d for partition %d is not a valid broker",
+					leader,
+					pin.Partition,
+				)
+			}
+
+			index := desired[pin.Partition].Index(leader)
+			if index > 0 {
+				replicas[0], replicas[index] = replicas[index], replicas[0]
+			} else if index < 0 {
+				replicas[0] = leader
+			}
+		} else if pin.LeaderRack != "" {
+			if len(p.brokersPerRack[pin.LeaderRack]) == 0 {
+				return nil, fmt.Errorf(
+					"Could not find any brokers for pinned rack %s",
+					pin.LeaderRack,
+				)
+			}
+			if p.brokerRacks[replicas[0]] == pin.LeaderRack {
+				continue
+			}
+
+			swapped := false
+			for r := 1; r < len(replicas); r++ {
+				if p.brokerRacks[replicas[r]] == pin.LeaderRack {
+					replicas[0], replicas[r] = replicas[r], replicas[0]
+					swapped = true
+					break
+				}
+			}
+
+			if !swapped {
+				err := p.picker.PickNew(
+					topic,
+					p.brokersPerRack[pin.LeaderRack],
+					desired,
+					pin.Partition,
+					0,
+				)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return desired, nil
+}
+
+// PinViolations returns the pins that aren't satisfied by the argument assignments.
+func PinViolations(
+	assignments []admin.PartitionAssignment,
+	brokers []admin.BrokerInfo,
+	pins []config.PartitionPin,
+) []config.PartitionPin {
+	brokerRacks := admin.BrokerRacks(brokers)
+	violations := []config.PartitionPin{}
+
+	for _, pin := range pins {
+		if pin.Partition < 0 || pin.Partition >= len(assignments) ||
+			len(assignments[pin.Partition].Replicas) == 0 {
+			violations = append(violations, pin)
+			continue
+		}
+		leader := assignments[pin.Partition].Replicas[0]
+
+		if pin.Leader != nil && leader != *pin.Leader {
+			violations = append(violations, pin)
+		} else if pin.LeaderRack != "" && brokerRacks[leader] != pin.LeaderRack {
+			violations = append(violations, pin)
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,146 @@
+package assigners
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply/pickers"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinnedAssigner(t *testing.T) {
+	brokers := testBrokers(12, 3)
+
+	leader7 := 7
+	leader9 := 9
+	leader100 := 100
+
+	type pinnedTestCase struct {
+		pins []config.PartitionPin
+		assignerTestCase
+	}
+
+	curr := [][]int{
+		{1, 4, 7},
+		{2, 5, 8},
+		{3, 6, 9},
+	}
+
+	testCases := []pinnedTestCase{
+		{
+			assignerTestCase: assignerTestCase{
+				description: "No pins",
+				curr:        curr,
+				expected:    curr,
+			},
+		},
+		{
+			pins: []config.PartitionPin{
+				{
+					Partition: 0,
+					Leader:    &leader7,
+				},
+				{
+					Partition:  1,
+					LeaderRack: "zone3",
+				},
+				{
+					Partition: 2,
+					Leader:    &leader9,
+				},
+			},
+			assignerTestCase: assignerTestCase{
+				description: "Leader and rack pins",
+				curr:        curr,
+				expected: [][]int{
+					{7, 4, 1},
+					{6, 5, 8},
+					{9, 6, 3},
+				},
+			},
+		},
+		{
+			pins: []config.PartitionPin{
+				{
+					Partition:  0,
+					LeaderRack: "zone1",
+				},
+				{
+					Partition: 1,
+					Leader:    &leader7,
+				},
+			},
+			assignerTestCase: assignerTestCase{
+				description: "Pins replace replicas not in partition",
+				curr:        curr,
+				expected: [][]int{
+					{1, 4, 7},
+					{7, 5, 8},
+					{3, 6, 9},
+				},
+			},
+		},
+		{
+			pins: []config.PartitionPin{
+				{
+					Partition: 0,
+					Leader:    &leader100,
+				},
+			},
+			assignerTestCase: assignerTestCase{
+				description: "Invalid broker",
+				curr:        curr,
+				err:         errors.New("invalid broker"),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		assigner := NewPinnedAssigner(
+			&StaticAssigner{
+				Assignments: admin.ReplicasToAssignments(curr),
+			},
+			brokers,
+			testCase.pins,
+			pickers.NewLowestIndexPicker(),
+		)
+		testCase.checker = func(result []admin.PartitionAssignment) bool {
+			return len(PinViolations(result, brokers, testCase.pins)) == 0
+		}
+		testCase.evaluate(t, assigner)
+	}
+}
+
+func TestPinViolations(t *testing.T) {
+	brokers := testBrokers(12, 3)
+	leader2 := 2
+
+	pins := []config.PartitionPin{
+		{
+			Partition: 0,
+			Leader:    &leader2,
+		},
+		{
+			Partition:  1,
+			LeaderRack: "zone2",
+		},
+		{
+			Partition:  5,
+			LeaderRack: "zone2",
+		},
+	}
+
+	violations := PinViolations(
+		admin.ReplicasToAssignments(
+			[][]int{
+				{2, 4, 7},
+				{1, 5, 8},
+			},
+		),
+		brokers,
+		pins,
+	)
+	assert.Equal(t, []config.PartitionPin{pins[1], pins[2]}, violations)
+}
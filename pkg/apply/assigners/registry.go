@@ -0,0 +1,90 @@
+package assigners
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply/pickers"
+)
+
+// AssignerPlugin is a custom placement algorithm, e.g. one that enforces company-specific
+// topology rules, that topic configs can use via the custom placement strategy. Plugins are
+// compiled into the topicctl binary and registered by name with RegisterAssignerPlugin, typically
+// from the init function of the package that defines them.
+type AssignerPlugin interface {
+	// NewAssigner returns an Assigner that makes the assignments of a topic consistent with the
+	// plugin's rules. The options are the assignerOptions from the topic's placement config.
+	NewAssigner(
+		brokers []admin.BrokerInfo,
+		picker pickers.Picker,
+		options map[string]string,
+	) (Assigner, error)
+
+	// Evaluate returns whether the argument assignments are already consistent with the plugin's
+	// rules. It's used to decide whether a topic's placement needs to be updated and to check
+	// the replacements made during rebalances.
+	Evaluate(
+		assignments []admin.PartitionAssignment,
+		brokers []admin.BrokerInfo,
+		options map[string]string,
+	) (bool, error)
+}
+
+var (
+	pluginsMutex sync.RWMutex
+	plugins      = map[string]AssignerPlugin{}
+)
+
+// RegisterAssignerPlugin makes the argument plugin available under the argument name. It panics
+// if the name is empty or already registered, or if the plugin is nil.
+func RegisterAssignerPlugin(name string, plugin AssignerPlugin) {
+	pluginsMutex.Lock()
+	defer pluginsMutex.Unlock()
+
+	if name == "" {
+		panic("Assigner plugin name must be non-empty")
+	}
+	if plugin == nil {
+		panic(fmt.Sprintf("Assigner plugin %s is nil", name))
+	}
+	if _, ok := plugins[name]; ok {
+		panic(fmt.Sprintf("Assigner plugin %s is already registered", name))
+	}
+
+	plugins[name] = plugin
+}
+
+// GetAssignerPlugin returns the plugin registered under the argument name.
+func GetAssignerPlugin(name string) (AssignerPlugin, error) {
+	pluginsMutex.RLock()
+	defer pluginsMutex.RUnlock()
+
+	plugin, ok := plugins[name]
+	if !ok {
+		return nil, fmt.Errorf(
+			"Assigner plugin '%s' is not registered; registered plugins are %+v",
+			name,
+			assignerPluginNames(),
+		)
+	}
+	return plugin, nil
+}
+
+// AssignerPluginNames returns the names of all of the registered plugins, in sorted order.
+func AssignerPluginNames() []string {
+	pluginsMutex.RLock()
+	defer pluginsMutex.RUnlock()
+
+	return assignerPluginNames()
+}
+
+func assignerPluginNames() []string {
+	names := []string{}
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
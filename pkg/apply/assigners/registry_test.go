@@ -0,0 +1,176 @@
+package assigners
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply/pickers"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sortedReplicasPlugin is a test plugin that requires the replicas in each partition to be
+// sorted by broker ID, in the order set by the "order" option.
+type sortedReplicasPlugin struct{}
+
+type sortedReplicasAssigner struct {
+	desc bool
+}
+
+func (p sortedReplicasPlugin) NewAssigner(
+	brokers []admin.BrokerInfo,
+	picker pickers.Picker,
+	options map[string]string,
+) (Assigner, error) {
+	desc, err := sortedReplicasDesc(options)
+	if err != nil {
+		return nil, err
+	}
+	return &sortedReplicasAssigner{desc: desc}, nil
+}
+
+func (p sortedReplicasPlugin) Evaluate(
+	assignments []admin.PartitionAssignment,
+	brokers []admin.BrokerInfo,
+	options map[string]string,
+) (bool, error) {
+	desc, err := sortedReplicasDesc(options)
+	if err != nil {
+		return false, err
+	}
+	for _, assignment := range assignments {
+		if !sort.SliceIsSorted(assignment.Replicas, func(a, b int) bool {
+			return (assignment.Replicas[a] < assignment.Replicas[b]) != desc
+		}) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *sortedReplicasAssigner) Assign(
+	topic string,
+	curr []admin.PartitionAssignment,
+) ([]admin.PartitionAssignment, error) {
+	desired := admin.CopyAssignments(curr)
+	for _, assignment := range desired {
+		replicas := assignment.Replicas
+		sort.Slice(replicas, func(a, b int) bool {
+			return (replicas[a] < replicas[b]) != s.desc
+		})
+	}
+	return desired, nil
+}
+
+func sortedReplicasDesc(options map[string]string) (bool, error) {
+	switch options["order"] {
+	case "", "asc":
+		return false, nil
+	case "desc":
+		return true, nil
+	default:
+		return false, fmt.Errorf("Unrecognized order: %s", options["order"])
+	}
+}
+
+func init() {
+	RegisterAssignerPlugin("test-sorted-replicas", sortedReplicasPlugin{})
+}
+
+func TestAssignerPluginRegistry(t *testing.T) {
+	plugin, err := GetAssignerPlugin("test-sorted-replicas")
+	require.NoError(t, err)
+	assert.Equal(t, sortedReplicasPlugin{}, plugin)
+	assert.Contains(t, AssignerPluginNames(), "test-sorted-replicas")
+
+	_, err = GetAssignerPlugin("test-missing")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		RegisterAssignerPlugin("test-sorted-replicas", sortedReplicasPlugin{})
+	})
+	assert.Panics(t, func() {
+		RegisterAssignerPlugin("", sortedReplicasPlugin{})
+	})
+	assert.Panics(t, func() {
+		RegisterAssignerPlugin("test-nil", nil)
+	})
+}
+
+func TestAssignerPluginAssign(t *testing.T) {
+	plugin, err := GetAssignerPlugin("test-sorted-replicas")
+	require.NoError(t, err)
+
+	testCases := []assignerTestCase{
+		{
+			description: "already sorted",
+			curr:        [][]int{{1, 2, 3}, {2, 3, 4}},
+			expected:    [][]int{{1, 2, 3}, {2, 3, 4}},
+		},
+		{
+			description: "unsorted",
+			curr:        [][]int{{3, 1, 2}, {4, 2, 3}},
+			expected:    [][]int{{1, 2, 3}, {2, 3, 4}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		assigner, err := plugin.NewAssigner(
+			testBrokers(4, 2),
+			pickers.NewLowestIndexPicker(),
+			nil,
+		)
+		require.NoError(t, err)
+		testCase.evaluate(t, assigner)
+	}
+
+	_, err = plugin.NewAssigner(
+		testBrokers(4, 2),
+		pickers.NewLowestIndexPicker(),
+		map[string]string{"order": "random"},
+	)
+	assert.Error(t, err)
+}
+
+func TestEvaluateAssignmentsCustom(t *testing.T) {
+	brokers := testBrokers(4, 2)
+	assignments := admin.ReplicasToAssignments([][]int{{1, 2, 3}, {2, 3, 4}})
+
+	ok, err := EvaluateAssignments(
+		assignments,
+		brokers,
+		config.TopicPlacementConfig{
+			Strategy: config.PlacementStrategyCustom,
+			Assigner: "test-sorted-replicas",
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = EvaluateAssignments(
+		assignments,
+		brokers,
+		config.TopicPlacementConfig{
+			Strategy: config.PlacementStrategyCustom,
+			Assigner: "test-sorted-replicas",
+			AssignerOptions: map[string]string{
+				"order": "desc",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = EvaluateAssignments(
+		assignments,
+		brokers,
+		config.TopicPlacementConfig{
+			Strategy: config.PlacementStrategyCustom,
+			Assigner: "test-missing",
+		},
+	)
+	assert.Error(t, err)
+}
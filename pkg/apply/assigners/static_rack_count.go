@@ -0,0 +1,146 @@
+package assigners
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply/pickers"
+)
+
+// StaticRackCountAssigner is an Assigner that places a fixed number of replicas for each
+// partition in each rack, e.g. 2 replicas in us-east-1a and 1 replica in us-east-1b. This might
+// be useful for cases where we want more copies of the data in the racks that are closest to
+// the consumers, but don't care about the specific brokers within each rack.
+//
+// The following algorithm is used:
+//
+// for each partition:
+//   for each replica:
+//     if replica's rack already has its configured count of replicas in the partition:
+//       change the replica to a placeholder (-1)
+//
+// then:
+//
+// for each partition:
+//   for each replica:
+//     if replica set to the placeholder:
+//       use picker to pick a broker from the (alphabetically) first rack that doesn't
+//       have its configured count of replicas
+//
+// In the case of ties, the lowest indexed broker is picked (if randomize is false) or
+// a repeatably random choice (if randomize is true).
+type StaticRackCountAssigner struct {
+	rackCounts     map[string]int
+	brokers        []admin.BrokerInfo
+	brokerRacks    map[int]string
+	brokersPerRack map[string][]int
+	picker         pickers.Picker
+}
+
+var _ Assigner = (*StaticRackCountAssigner)(nil)
+
+// NewStaticRackCountAssigner returns a new StaticRackCountAssigner instance.
+func NewStaticRackCountAssigner(
+	brokers []admin.BrokerInfo,
+	rackCounts map[string]int,
+	picker pickers.Picker,
+) *StaticRackCountAssigner {
+	return &StaticRackCountAssigner{
+		rackCounts:     rackCounts,
+		brokers:        brokers,
+		brokerRacks:    admin.BrokerRacks(brokers),
+		brokersPerRack: admin.BrokersPerRack(brokers),
+		picker:         picker,
+	}
+}
+
+// Assign returns a new partition assignment according to the assigner-specific logic.
+func (s *StaticRackCountAssigner) Assign(
+	topic string,
+	curr []admin.PartitionAssignment,
+) ([]admin.PartitionAssignment, error) {
+	if err := admin.CheckAssignments(curr); err != nil {
+		return nil, err
+	}
+
+	// Check to make sure that the counts are consistent with the replication factor and that
+	// there are enough brokers per rack.
+	racks := []string{}
+	totalCount := 0
+
+	for rack, count := range s.rackCounts {
+		rackBrokers := len(s.brokersPerRack[rack])
+
+		if rackBrokers == 0 {
+			return nil, fmt.Errorf("Could not find any brokers for rack %s", rack)
+		} else if rackBrokers < count {
+			return nil, fmt.Errorf(
+				"Rack %s does not have enough brokers for %d replicas",
+				rack,
+				count,
+			)
+		}
+
+		racks = append(racks, rack)
+		totalCount += count
+	}
+	sort.Strings(racks)
+
+	if totalCount != len(curr[0].Replicas) {
+		return nil, fmt.Errorf(
+			"Rack replica counts (%d total) do not match replication factor (%d)",
+			totalCount,
+			len(curr[0].Replicas),
+		)
+	}
+
+	desired := admin.CopyAssignments(curr)
+
+	for i := 0; i < len(desired); i++ {
+		remaining := map[string]int{}
+		for rack, count := range s.rackCounts {
+			remaining[rack] = count
+		}
+
+		// First, null-out any replicas that are in racks that are full (or not in the config)
+		for j := 0; j < len(desired[i].Replicas); j++ {
+			rack := s.brokerRacks[desired[i].Replicas[j]]
+
+			if remaining[rack] > 0 {
+				remaining[rack]--
+			} else {
+				desired[i].Replicas[j] = -1
+			}
+		}
+
+		// Then, go back and replace the marked replicas with ones from racks that still need
+		// more replicas
+		for j := 0; j < len(desired[i].Replicas); j++ {
+			if desired[i].Replicas[j] != -1 {
+				continue
+			}
+
+			for _, rack := range racks {
+				if remaining[rack] == 0 {
+					continue
+				}
+
+				err := s.picker.PickNew(
+					topic,
+					s.brokersPerRack[rack],
+					desired,
+					i,
+					j,
+				)
+				if err != nil {
+					return nil, err
+				}
+				remaining[rack]--
+				break
+			}
+		}
+	}
+
+	return desired, nil
+}
@@ -0,0 +1,91 @@
+package assigners
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply/pickers"
+	"github.com/segmentio/topicctl/pkg/config"
+)
+
+func TestStaticRackCountAssigner(t *testing.T) {
+	brokers := testBrokers(12, 3)
+
+	rackCounts := map[string]int{
+		"zone1": 2,
+		"zone2": 1,
+	}
+
+	assigner := NewStaticRackCountAssigner(
+		brokers,
+		rackCounts,
+		pickers.NewLowestIndexPicker(),
+	)
+	checker := func(result []admin.PartitionAssignment) bool {
+		ok, _ := EvaluateAssignments(
+			result,
+			brokers,
+			config.TopicPlacementConfig{
+				Strategy:                config.PlacementStrategyStaticRackCounts,
+				StaticRackReplicaCounts: rackCounts,
+			},
+		)
+		return ok
+	}
+
+	testCases := []assignerTestCase{
+		{
+			description: "Already correct counts",
+			curr: [][]int{
+				{1, 4, 2},
+				{5, 7, 10},
+				{4, 8, 1},
+			},
+			expected: [][]int{
+				{1, 4, 2},
+				{5, 7, 10},
+				{4, 8, 1},
+			},
+			checker: checker,
+		},
+		{
+			description: "Replica in unconfigured rack",
+			curr: [][]int{
+				{1, 4, 2},
+				{5, 7, 10},
+				{1, 2, 3},
+			},
+			expected: [][]int{
+				{1, 4, 2},
+				{5, 7, 10},
+				{1, 2, 4},
+			},
+			checker: checker,
+		},
+		{
+			description: "Too many replicas in one rack",
+			curr: [][]int{
+				{1, 4, 7},
+				{2, 5, 8},
+			},
+			expected: [][]int{
+				{1, 4, 2},
+				{2, 1, 4},
+			},
+			checker: checker,
+		},
+		{
+			description: "Wrong replication factor",
+			curr: [][]int{
+				{1, 4},
+				{2, 5},
+			},
+			err: errors.New("counts do not match replication factor"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase.evaluate(t, assigner)
+	}
+}
@@ -0,0 +1,55 @@
+package apply
+
+import (
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/segmentio/topicctl/pkg/messages"
+)
+
+// HasCommittedOffsets returns whether the group that the argument states are for has committed
+// offsets in any of the partitions.
+func HasCommittedOffsets(states []groups.PartitionOffsetState) bool {
+	for _, state := range states {
+		if state.CommittedOffset >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// BaselineOffsets returns the offsets that the argument baseline sets in each of the argument
+// partitions. The time offsets are only used for the timestamp position; they should be the
+// offsets for the baseline's timestamp.
+func BaselineOffsets(
+	baseline config.TopicOffsetBaseline,
+	states []groups.PartitionOffsetState,
+	timeOffsets []messages.TimeOffset,
+) map[int]int64 {
+	partitionOffsets := map[int]int64{}
+
+	switch baseline.Position {
+	case config.OffsetBaselinePositionEarliest:
+		for _, state := range states {
+			partitionOffsets[state.Partition] = state.FirstOffset
+		}
+	case config.OffsetBaselinePositionLatest:
+		for _, state := range states {
+			partitionOffsets[state.Partition] = state.EndOffset
+		}
+	case config.OffsetBaselinePositionTimestamp:
+		statesMap := map[int]groups.PartitionOffsetState{}
+		for _, state := range states {
+			statesMap[state.Partition] = state
+		}
+		for _, timeOffset := range timeOffsets {
+			state, ok := statesMap[timeOffset.Partition]
+			if !ok {
+				continue
+			}
+			// The bounds may have moved since the time offsets were fetched
+			partitionOffsets[timeOffset.Partition] = state.Clamp(timeOffset.Offset)
+		}
+	}
+
+	return partitionOffsets
+}
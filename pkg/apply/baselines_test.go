@@ -0,0 +1,74 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/segmentio/topicctl/pkg/messages"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasCommittedOffsets(t *testing.T) {
+	assert.False(t, HasCommittedOffsets(nil))
+	assert.False(
+		t,
+		HasCommittedOffsets(
+			[]groups.PartitionOffsetState{
+				{Partition: 0, CommittedOffset: -1},
+				{Partition: 1, CommittedOffset: -1},
+			},
+		),
+	)
+	assert.True(
+		t,
+		HasCommittedOffsets(
+			[]groups.PartitionOffsetState{
+				{Partition: 0, CommittedOffset: -1},
+				{Partition: 1, CommittedOffset: 0},
+			},
+		),
+	)
+}
+
+func TestBaselineOffsets(t *testing.T) {
+	states := []groups.PartitionOffsetState{
+		{Partition: 0, CommittedOffset: -1, FirstOffset: 5, EndOffset: 20},
+		{Partition: 1, CommittedOffset: -1, FirstOffset: 10, EndOffset: 30},
+	}
+
+	assert.Equal(
+		t,
+		map[int]int64{0: 5, 1: 10},
+		BaselineOffsets(
+			config.TopicOffsetBaseline{Position: config.OffsetBaselinePositionEarliest},
+			states,
+			nil,
+		),
+	)
+	assert.Equal(
+		t,
+		map[int]int64{0: 20, 1: 30},
+		BaselineOffsets(
+			config.TopicOffsetBaseline{Position: config.OffsetBaselinePositionLatest},
+			states,
+			nil,
+		),
+	)
+	assert.Equal(
+		t,
+		map[int]int64{0: 12, 1: 30},
+		BaselineOffsets(
+			config.TopicOffsetBaseline{
+				Position:  config.OffsetBaselinePositionTimestamp,
+				Timestamp: "2021-06-01",
+			},
+			states,
+			[]messages.TimeOffset{
+				{Partition: 0, Offset: 12, EndOffset: 20},
+				{Partition: 1, Offset: 35, EndOffset: 35},
+				{Partition: 2, Offset: 3, EndOffset: 3},
+			},
+		),
+	)
+}
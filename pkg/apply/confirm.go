@@ -3,8 +3,6 @@ package apply
 import (
 	"fmt"
 	"strings"
-
-	log "github.com/sirupsen/logrus"
 )
 
 // Confirm shows the argument prompt to the user and returns a boolean based on whether or not
@@ -30,3 +28,23 @@ func Confirm(prompt string, skip bool) (bool, error) {
 
 	return true, nil
 }
+
+// ConfirmTyped shows the argument prompt to the user and only returns true if the user types
+// the expected value exactly. It's used for destructive operations where a plain "yes" is too
+// easy to enter by accident.
+func ConfirmTyped(prompt string, expected string) (bool, error) {
+	fmt.Printf("%s (type '%s' to confirm) ", prompt, expected)
+
+	var response string
+	_, err := fmt.Scanln(&response)
+	if err != nil {
+		log.Warnf("Got error reading response, not continuing: %+v", err)
+		return false, err
+	}
+	if strings.TrimSpace(response) != expected {
+		log.Infof("Response did not match, not continuing")
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -0,0 +1,216 @@
+package apply
+
+import (
+	"context"
+	"sort"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+)
+
+// TopicDiff summarizes the differences between a topic config and the current state of the
+// topic in the cluster, i.e. the changes that an apply run would make to the topic.
+type TopicDiff struct {
+	Topic string
+
+	// New is set if the topic doesn't exist in the cluster yet, in which case NewTopicConfig is
+	// the config that it would be created with and the remaining fields are empty.
+	New            bool
+	NewTopicConfig kafka.TopicConfig
+
+	// TopicSettings are the settings in the topic config, including the retention, and
+	// ClusterSettings are the ones currently set on the topic in the cluster.
+	TopicSettings   config.TopicSettings
+	ClusterSettings map[string]string
+
+	// SettingsDiffKeys are the settings whose values differ between the config and the cluster,
+	// and MissingKeys are the ones that are set in the cluster but not in the config. Apply leaves
+	// the latter as-is.
+	SettingsDiffKeys []string
+	MissingKeys      []string
+
+	CurrReplication    int
+	DesiredReplication int
+	CurrPartitions     int
+	DesiredPartitions  int
+
+	// CurrAssignments are the current replica assignments of the topic and DesiredAssignments
+	// are the ones after any new partitions are added and the placement strategy is applied.
+	CurrAssignments    []admin.PartitionAssignment
+	DesiredAssignments []admin.PartitionAssignment
+
+	// WrongLeaders are the IDs of the partitions whose leader isn't currently the preferred one.
+	WrongLeaders []int
+
+	// MissingACLs are the ACLs in the topic config that aren't set in the cluster, and ExtraACLs
+	// are the literal allow ACLs on the topic in the cluster that aren't in the config. Both are empty
+	// if the topic config doesn't manage ACLs. Unlike the other fields, these are also set for
+	// new topics.
+	MissingACLs []admin.ACLInfo
+	ExtraACLs   []admin.ACLInfo
+}
+
+// HasDiffs returns whether applying the topic config would change the topic.
+func (t TopicDiff) HasDiffs() bool {
+	_, changedAssignments := t.ChangedAssignments()
+
+	return t.New ||
+		len(t.SettingsDiffKeys) > 0 ||
+		t.CurrReplication != t.DesiredReplication ||
+		t.CurrPartitions != t.DesiredPartitions ||
+		len(changedAssignments) > 0 ||
+		len(t.WrongLeaders) > 0 ||
+		t.HasACLDiffs()
+}
+
+// HasACLDiffs returns whether applying the topic config would change the topic's ACLs.
+func (t TopicDiff) HasACLDiffs() bool {
+	return len(t.MissingACLs) > 0 || len(t.ExtraACLs) > 0
+}
+
+// ChangedAssignments returns the current and desired assignments of the partitions whose
+// replicas would change, including any new partitions.
+func (t TopicDiff) ChangedAssignments() (
+	[]admin.PartitionAssignment,
+	[]admin.PartitionAssignment,
+) {
+	currByID := map[int]admin.PartitionAssignment{}
+	for _, assignment := range t.CurrAssignments {
+		currByID[assignment.ID] = assignment
+	}
+
+	currChanged := []admin.PartitionAssignment{}
+	desiredChanged := admin.AssignmentsToUpdate(t.CurrAssignments, t.DesiredAssignments)
+	for _, assignment := range desiredChanged {
+		if curr, ok := currByID[assignment.ID]; ok {
+			currChanged = append(currChanged, curr)
+		}
+	}
+
+	return currChanged, desiredChanged
+}
+
+// Diff compares the topic config against the current state of the topic in the cluster and
+// returns the differences. Unlike Apply with DryRun set, it doesn't acquire any locks or
+// prompt the user, and it never changes the cluster.
+//
+// If the placement needs to be updated, the desired assignments are generated the same way
+// as in Apply. Since some pickers are randomized, the assignments made by a subsequent apply
+// can differ from these in the exact brokers chosen.
+func (t *TopicApplier) Diff(ctx context.Context) (TopicDiff, error) {
+	diff := TopicDiff{
+		Topic: t.topicName,
+	}
+
+	if err := t.validateConfigs(); err != nil {
+		return diff, err
+	}
+
+	var err error
+	diff.MissingACLs, diff.ExtraACLs, err = t.aclDiffs(ctx)
+	if err != nil {
+		return diff, err
+	}
+
+	topicInfo, err := t.adminClient.GetTopic(ctx, t.topicName, true)
+	if err != nil {
+		if err == admin.ErrTopicDoesNotExist {
+			diff.New = true
+			diff.NewTopicConfig, err = t.topicConfig.ToNewTopicConfig()
+			return diff, err
+		}
+		return diff, err
+	}
+
+	diff.TopicSettings = t.topicConfig.Spec.Settings.Copy()
+	if t.topicConfig.Spec.RetentionMinutes > 0 {
+		diff.TopicSettings[admin.RetentionKey] = t.topicConfig.Spec.RetentionMinutes * 60000
+	}
+	diff.ClusterSettings = topicInfo.Config
+	diff.SettingsDiffKeys, diff.MissingKeys, err = diff.TopicSettings.ConfigMapDiffs(
+		topicInfo.Config,
+	)
+	if err != nil {
+		return diff, err
+	}
+	sort.Strings(diff.SettingsDiffKeys)
+	sort.Strings(diff.MissingKeys)
+
+	diff.CurrReplication = topicInfo.MaxISR()
+	diff.DesiredReplication = t.topicConfig.Spec.ReplicationFactor
+	diff.CurrPartitions = len(topicInfo.Partitions)
+	diff.DesiredPartitions = t.topicConfig.Spec.Partitions
+
+	diff.CurrAssignments = topicInfo.ToAssignments()
+	diff.DesiredAssignments = diff.CurrAssignments
+
+	extraPartitions := diff.DesiredPartitions - diff.CurrPartitions
+	if extraPartitions > 0 {
+		diff.DesiredAssignments, err = t.extendedAssignments(
+			ctx,
+			diff.CurrAssignments,
+			t.topicConfig.Spec.PlacementConfig.Strategy,
+			extraPartitions,
+		)
+		if err != nil {
+			return diff, err
+		}
+	}
+
+	satisfied, err := t.placementSatisfied(ctx, diff.DesiredAssignments)
+	if err != nil {
+		return diff, err
+	}
+	if !satisfied {
+		diff.DesiredAssignments, err = t.placedAssignments(
+			ctx,
+			diff.DesiredAssignments,
+			t.topicConfig.Spec.PlacementConfig.Strategy,
+		)
+		if err != nil {
+			return diff, err
+		}
+	}
+
+	diff.WrongLeaders = []int{}
+	for _, partition := range topicInfo.WrongLeaderPartitions(nil) {
+		diff.WrongLeaders = append(diff.WrongLeaders, partition.ID)
+	}
+
+	return diff, nil
+}
+
+// aclDiffs returns the ACLs in the topic config that are missing from the cluster and the
+// literal allow ACLs on the topic in the cluster that aren't in the topic config. Prefixed ACLs
+// are ignored since they can cover other topics too, as are deny ACLs since the config can't
+// describe them. If the topic config doesn't manage ACLs, no diffs are returned.
+func (t *TopicApplier) aclDiffs(
+	ctx context.Context,
+) ([]admin.ACLInfo, []admin.ACLInfo, error) {
+	if !t.topicConfig.ManagesACLs() {
+		return nil, nil, nil
+	}
+
+	desiredACLs, err := t.topicConfig.ACLs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currACLs, err := t.adminClient.GetACLs(
+		ctx,
+		kafka.ACLFilter{
+			ResourceTypeFilter:        kafka.ResourceTypeTopic,
+			ResourceNameFilter:        t.topicName,
+			ResourcePatternTypeFilter: kafka.PatternTypeLiteral,
+			Operation:                 kafka.ACLOperationTypeAny,
+			PermissionType:            kafka.ACLPermissionTypeAllow,
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	missingACLs, extraACLs := admin.ACLDiffs(desiredACLs, currACLs)
+	return missingACLs, extraACLs, nil
+}
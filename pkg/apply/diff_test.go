@@ -0,0 +1,54 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicDiffChangedAssignments(t *testing.T) {
+	diff := TopicDiff{
+		Topic:              "topic1",
+		CurrReplication:    2,
+		DesiredReplication: 2,
+		CurrPartitions:     2,
+		DesiredPartitions:  2,
+		CurrAssignments: []admin.PartitionAssignment{
+			{ID: 0, Replicas: []int{1, 2}},
+			{ID: 1, Replicas: []int{2, 3}},
+		},
+		DesiredAssignments: []admin.PartitionAssignment{
+			{ID: 0, Replicas: []int{1, 2}},
+			{ID: 1, Replicas: []int{2, 3}},
+		},
+	}
+	assert.False(t, diff.HasDiffs())
+
+	diff.DesiredPartitions = 3
+	diff.DesiredAssignments = []admin.PartitionAssignment{
+		{ID: 0, Replicas: []int{1, 2}},
+		{ID: 1, Replicas: []int{3, 2}},
+		{ID: 2, Replicas: []int{1, 3}},
+	}
+	assert.True(t, diff.HasDiffs())
+
+	currChanged, desiredChanged := diff.ChangedAssignments()
+	assert.Equal(
+		t,
+		[]admin.PartitionAssignment{
+			{ID: 1, Replicas: []int{2, 3}},
+		},
+		currChanged,
+	)
+	assert.Equal(
+		t,
+		[]admin.PartitionAssignment{
+			{ID: 1, Replicas: []int{3, 2}},
+			{ID: 2, Replicas: []int{1, 3}},
+		},
+		desiredChanged,
+	)
+
+	assert.True(t, TopicDiff{Topic: "topic2", New: true}.HasDiffs())
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/apply/pickers"
-	log "github.com/sirupsen/logrus"
 )
 
 // BalancedExtender adds extra partition assignments in a "balanced" way. The current
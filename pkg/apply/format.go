@@ -7,10 +7,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/config"
-	log "github.com/sirupsen/logrus"
+	"github.com/segmentio/topicctl/pkg/util"
 )
 
 // FormatNewTopicConfig generates a pretty string representation of a kafka-go
@@ -26,7 +28,8 @@ func FormatNewTopicConfig(config kafka.TopicConfig) string {
 }
 
 // FormatSettingsDiff generates a table that summarizes the differences between
-// the topic settings from a topic config and the settings from ZK.
+// the topic settings from a topic config and the settings from ZK. The words that
+// differ between the current and new values are highlighted.
 func FormatSettingsDiff(
 	topicSettings config.TopicSettings,
 	configMap map[string]string,
@@ -80,6 +83,8 @@ func FormatSettingsDiff(
 			valueStr = fmt.Sprintf("%s%s", valueStr, timeSuffix(valueStr))
 		}
 
+		configValueStr, valueStr = util.HighlightWordDiff(configValueStr, valueStr)
+
 		row := []string{
 			diffKey,
 			configValueStr,
@@ -146,6 +151,137 @@ func FormatMissingKeys(
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
+// FormatACLDiffs generates a table that summarizes the ACLs that an apply would create because
+// they're in the topic config but not the cluster, and delete because they're in the cluster but
+// not the topic config.
+func FormatACLDiffs(missingACLs []admin.ACLInfo, extraACLs []admin.ACLInfo) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+
+	headers := []string{
+		"Change",
+		"Principal",
+		"Host",
+		"Operation",
+		"Permission",
+	}
+
+	table.SetHeader(headers)
+
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	createStr := "create"
+	deleteStr := "delete"
+	if util.ColorEnabled() {
+		createStr = color.New(color.FgGreen).Sprint(createStr)
+		deleteStr = color.New(color.FgRed).Sprint(deleteStr)
+	}
+
+	appendRow := func(changeStr string, acl admin.ACLInfo) {
+		table.Append(
+			[]string{
+				changeStr,
+				acl.Principal,
+				acl.Host,
+				acl.Operation.String(),
+				acl.PermissionType.String(),
+			},
+		)
+	}
+
+	for _, acl := range missingACLs {
+		appendRow(createStr, acl)
+	}
+	for _, acl := range extraACLs {
+		appendRow(deleteStr, acl)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// TopicRebalanceSummary summarizes the changes needed to restore the balance of a single topic.
+type TopicRebalanceSummary struct {
+	Topic      string
+	Partitions int
+
+	// PartitionMoves is the number of partitions whose replicas need to be reassigned.
+	PartitionMoves int
+
+	// WrongLeaders is the number of partitions whose leader isn't the preferred one.
+	WrongLeaders int
+}
+
+// Balanced returns whether the topic doesn't need any changes to be balanced.
+func (t TopicRebalanceSummary) Balanced() bool {
+	return t.PartitionMoves == 0 && t.WrongLeaders == 0
+}
+
+// FormatRebalanceSummaries generates a table that summarizes the changes needed to rebalance
+// each of the argument topics.
+func FormatRebalanceSummaries(summaries []TopicRebalanceSummary) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Partitions",
+			"Partitions To Move",
+			"Wrong Leaders",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, summary := range summaries {
+		table.Append(
+			[]string{
+				summary.Topic,
+				fmt.Sprintf("%d", summary.Partitions),
+				fmt.Sprintf("%d", summary.PartitionMoves),
+				fmt.Sprintf("%d", summary.WrongLeaders),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
 func timeSuffix(msStr string) string {
 	msInt, err := strconv.ParseInt(msStr, 10, 64)
 	if err != nil {
@@ -0,0 +1,284 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGitHubAPIURL = "https://api.github.com"
+	githubTimeout       = 10 * time.Second
+	githubCommentsPage  = 100
+)
+
+var pullRequestRefRegexp = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// GitHubPullRequest identifies a GitHub pull request that apply results can be posted to.
+type GitHubPullRequest struct {
+	APIURL string
+	Token  string
+
+	// Repo is the full name of the repository, e.g. "segmentio/topicctl".
+	Repo   string
+	Number int
+
+	httpClient *http.Client
+}
+
+type githubComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// GitHubPullRequestFromEnv gets the pull request that triggered the current GitHub Actions run
+// from the environment. The token is taken from TOPICCTL_GITHUB_TOKEN or GITHUB_TOKEN, and the
+// repo from GITHUB_REPOSITORY. The pull request number is taken from TOPICCTL_GITHUB_PR if it's
+// set, then from the event in GITHUB_EVENT_PATH, and finally from GITHUB_REF.
+func GitHubPullRequestFromEnv() (GitHubPullRequest, error) {
+	pullRequest := GitHubPullRequest{
+		APIURL: os.Getenv("GITHUB_API_URL"),
+		Token:  os.Getenv("TOPICCTL_GITHUB_TOKEN"),
+		Repo:   os.Getenv("GITHUB_REPOSITORY"),
+		httpClient: &http.Client{
+			Timeout: githubTimeout,
+		},
+	}
+	if pullRequest.APIURL == "" {
+		pullRequest.APIURL = defaultGitHubAPIURL
+	}
+	if pullRequest.Token == "" {
+		pullRequest.Token = os.Getenv("GITHUB_TOKEN")
+	}
+
+	if pullRequest.Token == "" {
+		return pullRequest, errors.New(
+			"Must set TOPICCTL_GITHUB_TOKEN or GITHUB_TOKEN to comment on pull requests",
+		)
+	}
+	if pullRequest.Repo == "" {
+		return pullRequest, errors.New(
+			"Must set GITHUB_REPOSITORY to comment on pull requests",
+		)
+	}
+
+	number, err := pullRequestNumberFromEnv()
+	if err != nil {
+		return pullRequest, err
+	}
+	pullRequest.Number = number
+
+	return pullRequest, nil
+}
+
+func pullRequestNumberFromEnv() (int, error) {
+	if numberStr := os.Getenv("TOPICCTL_GITHUB_PR"); numberStr != "" {
+		number, err := strconv.Atoi(numberStr)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid TOPICCTL_GITHUB_PR value %s: %+v", numberStr, err)
+		}
+		return number, nil
+	}
+
+	if eventPath := os.Getenv("GITHUB_EVENT_PATH"); eventPath != "" {
+		contents, err := ioutil.ReadFile(eventPath)
+		if err != nil {
+			return 0, err
+		}
+
+		event := struct {
+			PullRequest *struct {
+				Number int `json:"number"`
+			} `json:"pull_request"`
+		}{}
+		if err := json.Unmarshal(contents, &event); err != nil {
+			return 0, fmt.Errorf("Error parsing GitHub event in %s: %+v", eventPath, err)
+		}
+		if event.PullRequest != nil && event.PullRequest.Number > 0 {
+			return event.PullRequest.Number, nil
+		}
+	}
+
+	if matches := pullRequestRefRegexp.FindStringSubmatch(os.Getenv("GITHUB_REF")); matches != nil {
+		return strconv.Atoi(matches[1])
+	}
+
+	return 0, errors.New(
+		"Could not determine the pull request number; set TOPICCTL_GITHUB_PR or run in a pull_request workflow",
+	)
+}
+
+// UpsertComment updates the existing comment on the pull request that contains the argument
+// marker to have the argument body, which should also contain the marker. If there's no such
+// comment, a new one is posted if create is set.
+func (g GitHubPullRequest) UpsertComment(
+	ctx context.Context,
+	marker string,
+	body string,
+	create bool,
+) error {
+	existing, err := g.findComment(ctx, marker)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]string{"body": body}
+
+	if existing != nil {
+		log.Infof("Updating comment %d on pull request %s#%d", existing.ID, g.Repo, g.Number)
+		return g.request(
+			ctx,
+			http.MethodPatch,
+			fmt.Sprintf("/repos/%s/issues/comments/%d", g.Repo, existing.ID),
+			payload,
+			nil,
+		)
+	} else if !create {
+		return nil
+	}
+
+	log.Infof("Posting comment on pull request %s#%d", g.Repo, g.Number)
+	return g.request(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("/repos/%s/issues/%d/comments", g.Repo, g.Number),
+		payload,
+		nil,
+	)
+}
+
+// PostApplyReport comments on the pull request with the argument report. There's a separate
+// comment for each cluster in the report, which is updated by later runs so that the pull
+// request only has the latest results for each cluster. Comments are only posted for clusters
+// that have changes, but existing comments are always updated.
+func (g GitHubPullRequest) PostApplyReport(ctx context.Context, report ApplyReport) error {
+	for _, cluster := range report.Clusters() {
+		clusterReport := report.ForCluster(cluster)
+
+		markdown, err := clusterReport.ToMarkdown()
+		if err != nil {
+			return err
+		}
+
+		marker := fmt.Sprintf("<!-- topicctl-apply-report cluster=%s -->", cluster)
+		if err := g.UpsertComment(
+			ctx,
+			marker,
+			fmt.Sprintf("%s\n%s", marker, markdown),
+			clusterReport.HasChanges(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g GitHubPullRequest) findComment(
+	ctx context.Context,
+	marker string,
+) (*githubComment, error) {
+	for page := 1; ; page++ {
+		comments := []githubComment{}
+		if err := g.request(
+			ctx,
+			http.MethodGet,
+			fmt.Sprintf(
+				"/repos/%s/issues/%d/comments?per_page=%d&page=%d",
+				g.Repo,
+				g.Number,
+				githubCommentsPage,
+				page,
+			),
+			nil,
+			&comments,
+		); err != nil {
+			return nil, err
+		}
+
+		for _, comment := range comments {
+			if strings.Contains(comment.Body, marker) {
+				return &comment, nil
+			}
+		}
+		if len(comments) < githubCommentsPage {
+			return nil, nil
+		}
+	}
+}
+
+func (g GitHubPullRequest) request(
+	ctx context.Context,
+	method string,
+	path string,
+	payload interface{},
+	result interface{},
+) error {
+	var reqBody *bytes.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(payloadBytes)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		method,
+		strings.TrimRight(g.APIURL, "/")+path,
+		reqBody,
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.Token))
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := g.httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: githubTimeout}
+	}
+
+	log.Debugf("Sending GitHub request: %s %s", method, path)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf(
+			"Got status %d from GitHub for %s %s: %s",
+			resp.StatusCode,
+			method,
+			path,
+			strings.TrimSpace(string(body)),
+		)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("Error parsing GitHub response for %s: %+v", path, err)
+		}
+	}
+	return nil
+}
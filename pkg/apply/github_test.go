@@ -0,0 +1,144 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubPullRequestFromEnv(t *testing.T) {
+	t.Setenv("TOPICCTL_GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_REPOSITORY", "segmentio/topicctl")
+	t.Setenv("GITHUB_API_URL", "")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GITHUB_REF", "")
+	t.Setenv("TOPICCTL_GITHUB_PR", "")
+
+	_, err := GitHubPullRequestFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	_, err = GitHubPullRequestFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("GITHUB_REF", "refs/pull/12/merge")
+	pullRequest, err := GitHubPullRequestFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, defaultGitHubAPIURL, pullRequest.APIURL)
+	assert.Equal(t, "test-token", pullRequest.Token)
+	assert.Equal(t, "segmentio/topicctl", pullRequest.Repo)
+	assert.Equal(t, 12, pullRequest.Number)
+
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(
+		t,
+		ioutil.WriteFile(eventPath, []byte(`{"pull_request": {"number": 34}}`), 0644),
+	)
+	t.Setenv("GITHUB_EVENT_PATH", eventPath)
+	pullRequest, err = GitHubPullRequestFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 34, pullRequest.Number)
+
+	t.Setenv("TOPICCTL_GITHUB_PR", "56")
+	t.Setenv("TOPICCTL_GITHUB_TOKEN", "other-token")
+	pullRequest, err = GitHubPullRequestFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 56, pullRequest.Number)
+	assert.Equal(t, "other-token", pullRequest.Token)
+}
+
+func TestGitHubPullRequestPostApplyReport(t *testing.T) {
+	var lock sync.Mutex
+	comments := map[int64]string{
+		1: "An unrelated comment",
+		2: "<!-- topicctl-apply-report cluster=cluster1 -->\nOld results",
+	}
+	nextID := int64(3)
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+			payload := map[string]string{}
+			if r.Method != http.MethodGet {
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			}
+
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/repos/test/repo/issues/5/comments":
+				results := []githubComment{}
+				if r.URL.Query().Get("page") == "1" {
+					for id := int64(1); id < nextID; id++ {
+						if body, ok := comments[id]; ok {
+							results = append(results, githubComment{ID: id, Body: body})
+						}
+					}
+				}
+				json.NewEncoder(w).Encode(results)
+			case r.Method == http.MethodPost && r.URL.Path == "/repos/test/repo/issues/5/comments":
+				comments[nextID] = payload["body"]
+				nextID++
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, "{}")
+			case r.Method == http.MethodPatch &&
+				strings.HasPrefix(r.URL.Path, "/repos/test/repo/issues/comments/"):
+				var id int64
+				fmt.Sscanf(
+					strings.TrimPrefix(r.URL.Path, "/repos/test/repo/issues/comments/"),
+					"%d",
+					&id,
+				)
+				comments[id] = payload["body"]
+				fmt.Fprint(w, "{}")
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	pullRequest := GitHubPullRequest{
+		APIURL: server.URL,
+		Token:  "test-token",
+		Repo:   "test/repo",
+		Number: 5,
+	}
+
+	report := ApplyReport{DryRun: true}
+	for _, cluster := range []string{"cluster1", "cluster2", "cluster3"} {
+		diff := TopicDiff{
+			Topic:             "topic1",
+			CurrPartitions:    2,
+			DesiredPartitions: 2,
+		}
+		if cluster != "cluster3" {
+			diff.DesiredPartitions = 3
+		}
+		report.AddResult(cluster, "test-env", diff, nil)
+	}
+
+	require.NoError(t, pullRequest.PostApplyReport(context.Background(), report))
+
+	// The existing comment for cluster1 is updated, a new one is posted for cluster2, and
+	// nothing is posted for cluster3 since it doesn't have any changes
+	assert.Equal(t, 3, len(comments))
+	assert.Equal(t, "An unrelated comment", comments[1])
+	assert.Contains(t, comments[2], "<!-- topicctl-apply-report cluster=cluster1 -->")
+	assert.Contains(t, comments[2], "# topicctl apply report for cluster cluster1 (dry-run)")
+	assert.Contains(t, comments[3], "<!-- topicctl-apply-report cluster=cluster2 -->")
+	assert.Contains(t, comments[3], "| Partitions | 2 | 3 |")
+}
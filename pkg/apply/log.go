@@ -0,0 +1,5 @@
+package apply
+
+import "github.com/segmentio/topicctl/pkg/logging"
+
+var log = logging.Logger(logging.SubsystemApply)
@@ -0,0 +1,273 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/config"
+)
+
+const notificationTimeout = 10 * time.Second
+
+// ApplyNotification is the payload that's sent to webhooks with the json format when a topic is
+// changed by apply.
+type ApplyNotification struct {
+	Event       config.NotificationEvent `json:"event"`
+	Cluster     string                   `json:"cluster"`
+	Environment string                   `json:"environment"`
+	Topic       string                   `json:"topic"`
+	DryRun      bool                     `json:"dryRun"`
+	Changes     []string                 `json:"changes"`
+	Error       string                   `json:"error,omitempty"`
+	Time        time.Time                `json:"time"`
+}
+
+type slackNotification struct {
+	Text string `json:"text"`
+}
+
+// Notifier sends apply notifications to the webhooks configured in a cluster config.
+type Notifier struct {
+	clusterConfig config.ClusterConfig
+	dryRun        bool
+	httpClient    *http.Client
+}
+
+// NewNotifier returns a Notifier for the webhooks in the argument cluster config.
+func NewNotifier(clusterConfig config.ClusterConfig, dryRun bool) *Notifier {
+	return &Notifier{
+		clusterConfig: clusterConfig,
+		dryRun:        dryRun,
+		httpClient: &http.Client{
+			Timeout: notificationTimeout,
+		},
+	}
+}
+
+// Enabled returns whether any webhooks are configured.
+func (n *Notifier) Enabled() bool {
+	return len(n.clusterConfig.Spec.Notifications) > 0
+}
+
+// Notify sends a notification for the argument event and topic diff to each webhook that's
+// configured for it. The argument error should be set for failure events. Errors sending the
+// notifications are logged instead of returned so that they don't interrupt the apply.
+func (n *Notifier) Notify(
+	ctx context.Context,
+	event config.NotificationEvent,
+	diff TopicDiff,
+	applyErr error,
+) {
+	notification := ApplyNotification{
+		Event:       event,
+		Cluster:     n.clusterConfig.Meta.Name,
+		Environment: n.clusterConfig.Meta.Environment,
+		Topic:       diff.Topic,
+		DryRun:      n.dryRun,
+		Changes:     DiffChanges(diff),
+		Time:        time.Now().UTC(),
+	}
+	if applyErr != nil {
+		notification.Error = fmt.Sprintf("%+v", applyErr)
+	}
+
+	for _, notificationConfig := range n.clusterConfig.Spec.Notifications {
+		if !notificationConfig.NotifiesFor(event, n.dryRun) {
+			continue
+		}
+
+		if err := n.send(ctx, notificationConfig, notification); err != nil {
+			log.Warnf(
+				"Could not send %s notification for topic %s: %+v",
+				event,
+				diff.Topic,
+				err,
+			)
+		}
+	}
+}
+
+func (n *Notifier) send(
+	ctx context.Context,
+	notificationConfig config.NotificationConfig,
+	notification ApplyNotification,
+) error {
+	var payload interface{}
+
+	switch notificationConfig.GetFormat() {
+	case config.NotificationFormatSlack:
+		payload = slackNotification{
+			Text: notification.SlackText(),
+		}
+	default:
+		payload = notification
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		notificationConfig.URL,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf(
+			"Webhook returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(respBody)),
+		)
+	}
+
+	return nil
+}
+
+// SlackText returns a text summary of the notification for Slack-compatible webhooks.
+func (a ApplyNotification) SlackText() string {
+	var verb string
+	switch a.Event {
+	case config.NotificationEventStart:
+		verb = "Applying changes to"
+	case config.NotificationEventSuccess:
+		verb = "Applied changes to"
+	case config.NotificationEventFailure:
+		verb = "Failed to apply changes to"
+	default:
+		verb = "Changes to"
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(
+		buf,
+		"%s topic `%s` in cluster `%s` (%s)",
+		verb,
+		a.Topic,
+		a.Cluster,
+		a.Environment,
+	)
+	if a.DryRun {
+		fmt.Fprintf(buf, " [dry-run]")
+	}
+	fmt.Fprintf(buf, "\n")
+
+	for _, change := range a.Changes {
+		fmt.Fprintf(buf, "• %s\n", change)
+	}
+	if a.Error != "" {
+		fmt.Fprintf(buf, "```%s```\n", a.Error)
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// DiffChanges returns a short, human-readable line for each of the changes in the argument
+// topic diff.
+func DiffChanges(diff TopicDiff) []string {
+	changes := []string{}
+
+	if diff.New {
+		changes = append(
+			changes,
+			fmt.Sprintf(
+				"Create topic with %d partition(s) and a replication factor of %d",
+				diff.NewTopicConfig.NumPartitions,
+				diff.NewTopicConfig.ReplicationFactor,
+			),
+		)
+		return append(changes, aclChanges(diff)...)
+	}
+
+	for _, key := range diff.SettingsDiffKeys {
+		var newValue string
+		if diff.TopicSettings.HasKey(key) {
+			newValue, _ = diff.TopicSettings.GetValueStr(key)
+		}
+
+		changes = append(
+			changes,
+			fmt.Sprintf(
+				"Update setting %s: %s -> %s",
+				key,
+				valueOrNone(diff.ClusterSettings[key]),
+				valueOrNone(newValue),
+			),
+		)
+	}
+
+	changes = append(changes, aclChanges(diff)...)
+
+	if diff.CurrPartitions != diff.DesiredPartitions {
+		changes = append(
+			changes,
+			fmt.Sprintf(
+				"Update partitions: %d -> %d",
+				diff.CurrPartitions,
+				diff.DesiredPartitions,
+			),
+		)
+	}
+	if diff.CurrReplication != diff.DesiredReplication {
+		changes = append(
+			changes,
+			fmt.Sprintf(
+				"Update replication factor: %d -> %d",
+				diff.CurrReplication,
+				diff.DesiredReplication,
+			),
+		)
+	}
+
+	if _, changedAssignments := diff.ChangedAssignments(); len(changedAssignments) > 0 {
+		changes = append(
+			changes,
+			fmt.Sprintf("Reassign %d partition(s)", len(changedAssignments)),
+		)
+	}
+	if len(diff.WrongLeaders) > 0 {
+		changes = append(
+			changes,
+			fmt.Sprintf("Elect preferred leaders in %d partition(s)", len(diff.WrongLeaders)),
+		)
+	}
+
+	return changes
+}
+
+func aclChanges(diff TopicDiff) []string {
+	changes := []string{}
+	for _, acl := range diff.MissingACLs {
+		changes = append(changes, fmt.Sprintf("Create ACL: %s", acl))
+	}
+	for _, acl := range diff.ExtraACLs {
+		changes = append(changes, fmt.Sprintf("Delete ACL: %s", acl))
+	}
+	return changes
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
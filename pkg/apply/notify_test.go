@@ -0,0 +1,171 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffChanges(t *testing.T) {
+	assert.Equal(
+		t,
+		[]string{"Create topic with 3 partition(s) and a replication factor of 2"},
+		DiffChanges(
+			TopicDiff{
+				Topic: "test-topic",
+				New:   true,
+				NewTopicConfig: kafka.TopicConfig{
+					NumPartitions:     3,
+					ReplicationFactor: 2,
+				},
+			},
+		),
+	)
+
+	assert.Equal(
+		t,
+		[]string{
+			"Update setting cleanup.policy: delete -> compact",
+			"Update setting retention.ms: 3600000 -> (none)",
+			"Create ACL: Allow Read on Topic test-topic (Literal) from host * for User:alice",
+			"Delete ACL: Allow Write on Topic test-topic (Literal) from host * for User:bob",
+			"Update partitions: 2 -> 4",
+			"Elect preferred leaders in 1 partition(s)",
+		},
+		DiffChanges(
+			TopicDiff{
+				Topic: "test-topic",
+				TopicSettings: config.TopicSettings{
+					"cleanup.policy": "compact",
+				},
+				ClusterSettings: map[string]string{
+					"cleanup.policy": "delete",
+					"retention.ms":   "3600000",
+				},
+				SettingsDiffKeys:   []string{"cleanup.policy", "retention.ms"},
+				CurrPartitions:     2,
+				DesiredPartitions:  4,
+				CurrReplication:    2,
+				DesiredReplication: 2,
+				WrongLeaders:       []int{1},
+				MissingACLs: []admin.ACLInfo{
+					testACL("User:alice", kafka.ACLOperationTypeRead),
+				},
+				ExtraACLs: []admin.ACLInfo{
+					testACL("User:bob", kafka.ACLOperationTypeWrite),
+				},
+			},
+		),
+	)
+}
+
+func testACL(principal string, operation kafka.ACLOperationType) admin.ACLInfo {
+	return admin.ACLInfo{
+		ResourceType:   kafka.ResourceTypeTopic,
+		ResourceName:   "test-topic",
+		PatternType:    kafka.PatternTypeLiteral,
+		Principal:      principal,
+		Host:           "*",
+		Operation:      operation,
+		PermissionType: kafka.ACLPermissionTypeAllow,
+	}
+}
+
+func TestNotifierNotify(t *testing.T) {
+	var mutex sync.Mutex
+	bodies := map[string][]map[string]interface{}{}
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			payload := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal(body, &payload))
+
+			mutex.Lock()
+			bodies[r.URL.Path] = append(bodies[r.URL.Path], payload)
+			mutex.Unlock()
+
+			if r.URL.Path == "/broken" {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}),
+	)
+	defer server.Close()
+
+	clusterConfig := config.ClusterConfig{
+		Meta: config.ClusterMeta{
+			Name:        "test-cluster",
+			Environment: "test-env",
+		},
+		Spec: config.ClusterSpec{
+			Notifications: []config.NotificationConfig{
+				{
+					URL: server.URL + "/json",
+				},
+				{
+					URL:    server.URL + "/slack",
+					Format: config.NotificationFormatSlack,
+					Events: []config.NotificationEvent{config.NotificationEventFailure},
+				},
+				{
+					URL: server.URL + "/broken",
+				},
+			},
+		},
+	}
+	diff := TopicDiff{
+		Topic:             "test-topic",
+		CurrPartitions:    2,
+		DesiredPartitions: 4,
+	}
+
+	notifier := NewNotifier(clusterConfig, false)
+	require.True(t, notifier.Enabled())
+
+	ctx := context.Background()
+	notifier.Notify(ctx, config.NotificationEventStart, diff, nil)
+	notifier.Notify(ctx, config.NotificationEventFailure, diff, errors.New("test error"))
+
+	require.Equal(t, 2, len(bodies["/json"]))
+	assert.Equal(t, "start", bodies["/json"][0]["event"])
+	assert.Equal(t, "test-cluster", bodies["/json"][0]["cluster"])
+	assert.Equal(t, "test-env", bodies["/json"][0]["environment"])
+	assert.Equal(t, "test-topic", bodies["/json"][0]["topic"])
+	assert.Equal(
+		t,
+		[]interface{}{"Update partitions: 2 -> 4"},
+		bodies["/json"][0]["changes"],
+	)
+	assert.Nil(t, bodies["/json"][0]["error"])
+	assert.Equal(t, "failure", bodies["/json"][1]["event"])
+	assert.Equal(t, "test error", bodies["/json"][1]["error"])
+
+	require.Equal(t, 1, len(bodies["/slack"]))
+	assert.Equal(
+		t,
+		"Failed to apply changes to topic `test-topic` in cluster `test-cluster` (test-env)\n"+
+			"• Update partitions: 2 -> 4\n"+
+			"```test error```",
+		bodies["/slack"][0]["text"],
+	)
+
+	// Failed webhooks are only logged
+	assert.Equal(t, 2, len(bodies["/broken"]))
+
+	dryRunNotifier := NewNotifier(clusterConfig, true)
+	dryRunNotifier.Notify(ctx, config.NotificationEventStart, diff, nil)
+	assert.Equal(t, 2, len(bodies["/json"]))
+}
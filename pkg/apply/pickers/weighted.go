@@ -0,0 +1,119 @@
+package pickers
+
+import (
+	"sort"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// WeightedPicker is a picker that wraps another one to account for the capacity weights of the
+// brokers. Instead of the raw broker frequencies in each position, it compares the frequencies
+// divided by the broker weights, so that a broker with a weight of 2 ends up with about twice as
+// many replicas in each position as a broker with a weight of 1. The wrapped picker is used to
+// choose among the brokers that are tied after weighting.
+type WeightedPicker struct {
+	picker        Picker
+	brokerWeights map[int]float64
+}
+
+var _ Picker = (*WeightedPicker)(nil)
+
+// NewWeightedPicker returns a new WeightedPicker instance. The brokerWeights map is keyed by
+// broker ID; brokers that are missing from it are assumed to have a weight of 1.
+func NewWeightedPicker(picker Picker, brokerWeights map[int]float64) *WeightedPicker {
+	return &WeightedPicker{
+		picker:        picker,
+		brokerWeights: brokerWeights,
+	}
+}
+
+// PickNew updates the replica for the argument partition and index, using the choices in
+// brokerChoices.
+func (w *WeightedPicker) PickNew(
+	topic string,
+	brokerChoices []int,
+	curr []admin.PartitionAssignment,
+	partition int,
+	index int,
+) error {
+	brokerCounts := map[int]int{}
+	for p := 0; p < len(curr); p++ {
+		brokerCounts[curr[p].Replicas[index]]++
+	}
+
+	// Only offer the wrapped picker the feasible choices that would have the lowest weighted
+	// counts after the pick
+	tiedChoices := []int{}
+	var minWeighted float64
+
+	for _, choice := range brokerChoices {
+		if curr[partition].Index(choice) != -1 {
+			continue
+		}
+
+		weighted := float64(brokerCounts[choice]+1) / w.weight(choice)
+		if len(tiedChoices) == 0 || weighted < minWeighted {
+			tiedChoices = []int{choice}
+			minWeighted = weighted
+		} else if weighted == minWeighted {
+			tiedChoices = append(tiedChoices, choice)
+		}
+	}
+
+	if len(tiedChoices) == 0 {
+		return ErrNoFeasibleChoice
+	}
+
+	return w.picker.PickNew(topic, tiedChoices, curr, partition, index)
+}
+
+// SortRemovals sorts the argument partitions in order of priority for removing the broker
+// at the argument index.
+func (w *WeightedPicker) SortRemovals(
+	topic string,
+	partitionChoices []int,
+	curr []admin.PartitionAssignment,
+	index int,
+) error {
+	if err := w.picker.SortRemovals(topic, partitionChoices, curr, index); err != nil {
+		return err
+	}
+
+	brokerCounts := map[int]int{}
+	for _, partition := range partitionChoices {
+		brokerCounts[curr[partition].Replicas[index]]++
+	}
+
+	// Re-sort by the weighted counts, descending, keeping the order of the wrapped picker for
+	// ties; like the other pickers, empty slots go first.
+	sort.SliceStable(partitionChoices, func(a, b int) bool {
+		aReplica := curr[partitionChoices[a]].Replicas[index]
+		bReplica := curr[partitionChoices[b]].Replicas[index]
+
+		if aReplica < 0 || bReplica < 0 {
+			return aReplica < 0 && bReplica >= 0
+		}
+
+		return float64(brokerCounts[aReplica])/w.weight(aReplica) >
+			float64(brokerCounts[bReplica])/w.weight(bReplica)
+	})
+
+	return nil
+}
+
+// ScoreBroker returns an integer score for the given broker at the provided partition and index.
+func (w *WeightedPicker) ScoreBroker(
+	topic string,
+	brokerID int,
+	partition int,
+	index int,
+) int {
+	return w.picker.ScoreBroker(topic, brokerID, partition, index)
+}
+
+func (w *WeightedPicker) weight(brokerID int) float64 {
+	if weight, ok := w.brokerWeights[brokerID]; ok && weight > 0 {
+		return weight
+	}
+	return 1.0
+}
@@ -0,0 +1,121 @@
+package pickers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedPickerPickNew(t *testing.T) {
+	picker := NewWeightedPicker(
+		NewLowestIndexPicker(),
+		map[int]float64{
+			1: 2.0,
+			2: 2.0,
+		},
+	)
+
+	testCases := []pickNewTestCase{
+		{
+			description:   "Heavier broker picked",
+			topic:         "test-topic",
+			brokerChoices: []int{1, 3},
+			curr: [][]int{
+				{1, 5},
+				{1, 6},
+				{2, 5},
+				{3, 6},
+				{-1, 5},
+			},
+			partition: 4,
+			index:     0,
+			// Broker 3 would have 2 replicas for a weight of 1, and broker 1 would have 3 for a
+			// weight of 2
+			expectedChoice: 1,
+		},
+		{
+			description:   "Ties broken by wrapped picker",
+			topic:         "test-topic",
+			brokerChoices: []int{1, 2, 3},
+			curr: [][]int{
+				{3, 5},
+				{-1, 6},
+			},
+			partition:      1,
+			index:          0,
+			expectedChoice: 1,
+		},
+		{
+			description:   "Brokers in partition skipped",
+			topic:         "test-topic",
+			brokerChoices: []int{1, 3},
+			curr: [][]int{
+				{3, 5},
+				{-1, 1},
+			},
+			partition:      1,
+			index:          0,
+			expectedChoice: 3,
+		},
+		{
+			description:   "Not feasible",
+			topic:         "test-topic",
+			brokerChoices: []int{1},
+			curr: [][]int{
+				{3, 5},
+				{-1, 1},
+			},
+			partition:   1,
+			index:       0,
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase.evaluate(t, picker)
+	}
+}
+
+func TestWeightedPickerSortRemovals(t *testing.T) {
+	picker := NewWeightedPicker(
+		NewLowestIndexPicker(),
+		map[int]float64{
+			1: 2.0,
+		},
+	)
+
+	testCases := []sortRemovalsTestCase{
+		{
+			description:      "Weighted counts",
+			topic:            "test-topic",
+			partitionChoices: []int{0, 1, 2, 3, 4},
+			curr: [][]int{
+				{1, 5},
+				{1, 6},
+				{1, 5},
+				{2, 6},
+				{2, 5},
+			},
+			index: 0,
+			// Broker 1 has more replicas, but fewer per unit of weight
+			expectedOrdering: []int{3, 4, 0, 1, 2},
+		},
+		{
+			description:      "Not feasible",
+			topic:            "test-topic",
+			partitionChoices: []int{},
+			curr: [][]int{
+				{1, 5},
+			},
+			index:       0,
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase.evaluate(t, picker)
+	}
+
+	// Scores aren't weighted
+	assert.Equal(t, 4, picker.ScoreBroker("test-topic", 4, 0, 0))
+}
@@ -0,0 +1,162 @@
+package apply
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+)
+
+// QuotaUpdates compares the desired quotas from a cluster config against the current ones in
+// the cluster and returns the updates needed to make them match. Only the entities in the
+// config are updated; within each of these, keys that are set in the cluster but not in the
+// config are removed. The results are sorted by user and then client ID.
+func QuotaUpdates(
+	quotaConfigs []config.QuotaConfig,
+	currentQuotas []admin.QuotaInfo,
+) []admin.QuotaUpdate {
+	currentByEntity := map[admin.QuotaEntity]map[string]float64{}
+	for _, currentQuota := range currentQuotas {
+		currentByEntity[currentQuota.Entity] = currentQuota.Values
+	}
+
+	updates := []admin.QuotaUpdate{}
+
+	for _, quotaConfig := range quotaConfigs {
+		currentValues := currentByEntity[quotaConfig.Entity()]
+
+		update := admin.QuotaUpdate{
+			Entity:      quotaConfig.Entity(),
+			Values:      map[string]float64{},
+			RemovedKeys: []string{},
+		}
+
+		for key, value := range quotaConfig.Values {
+			currentValue, ok := currentValues[key]
+			if !ok || currentValue != value {
+				update.Values[key] = value
+			}
+		}
+		for key := range currentValues {
+			if _, ok := quotaConfig.Values[key]; !ok {
+				update.RemovedKeys = append(update.RemovedKeys, key)
+			}
+		}
+		sort.Strings(update.RemovedKeys)
+
+		if len(update.Values) > 0 || len(update.RemovedKeys) > 0 {
+			updates = append(updates, update)
+		}
+	}
+
+	sort.Slice(updates, func(a, b int) bool {
+		entityA := updates[a].Entity
+		entityB := updates[b].Entity
+
+		if entityA.User != entityB.User {
+			return entityA.User < entityB.User
+		}
+		return entityA.ClientID < entityB.ClientID
+	})
+
+	return updates
+}
+
+// UnmanagedQuotas returns the current quotas whose entities aren't in the argument configs.
+func UnmanagedQuotas(
+	quotaConfigs []config.QuotaConfig,
+	currentQuotas []admin.QuotaInfo,
+) []admin.QuotaInfo {
+	managed := map[admin.QuotaEntity]struct{}{}
+	for _, quotaConfig := range quotaConfigs {
+		managed[quotaConfig.Entity()] = struct{}{}
+	}
+
+	unmanaged := []admin.QuotaInfo{}
+	for _, currentQuota := range currentQuotas {
+		if _, ok := managed[currentQuota.Entity]; !ok {
+			unmanaged = append(unmanaged, currentQuota)
+		}
+	}
+
+	return unmanaged
+}
+
+// FormatQuotaUpdates generates a table that summarizes the argument quota updates, including
+// the current values in the cluster.
+func FormatQuotaUpdates(
+	updates []admin.QuotaUpdate,
+	currentQuotas []admin.QuotaInfo,
+) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Entity",
+			"Key",
+			"Cluster Value (Curr)",
+			"Config Value (New)",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	currentByEntity := map[admin.QuotaEntity]map[string]float64{}
+	for _, currentQuota := range currentQuotas {
+		currentByEntity[currentQuota.Entity] = currentQuota.Values
+	}
+
+	for _, update := range updates {
+		currentValues := currentByEntity[update.Entity]
+
+		keys := []string{}
+		for key := range update.Values {
+			keys = append(keys, key)
+		}
+		keys = append(keys, update.RemovedKeys...)
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var currentStr, newStr string
+			if currentValue, ok := currentValues[key]; ok {
+				currentStr = strconv.FormatFloat(currentValue, 'f', -1, 64)
+			}
+			if newValue, ok := update.Values[key]; ok {
+				newStr = strconv.FormatFloat(newValue, 'f', -1, 64)
+			} else {
+				newStr = "(removed)"
+			}
+
+			table.Append(
+				[]string{
+					update.Entity.String(),
+					key,
+					currentStr,
+					newStr,
+				},
+			)
+		}
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
@@ -0,0 +1,89 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaUpdates(t *testing.T) {
+	quotaConfigs := []config.QuotaConfig{
+		{
+			User: "user2",
+			Values: map[string]float64{
+				admin.QuotaKeyProducerByteRate: 2048,
+				admin.QuotaKeyConsumerByteRate: 4096,
+			},
+		},
+		{
+			User: "user1",
+			Values: map[string]float64{
+				admin.QuotaKeyProducerByteRate: 1024,
+			},
+		},
+		{
+			ClientID: admin.QuotaEntityDefault,
+			Values: map[string]float64{
+				admin.QuotaKeyRequestPercentage: 50,
+			},
+		},
+	}
+	currentQuotas := []admin.QuotaInfo{
+		{
+			Entity: admin.QuotaEntity{ClientID: admin.QuotaEntityDefault},
+			Values: map[string]float64{
+				admin.QuotaKeyRequestPercentage: 50,
+			},
+		},
+		{
+			Entity: admin.QuotaEntity{User: "user2"},
+			Values: map[string]float64{
+				admin.QuotaKeyProducerByteRate:  1024,
+				admin.QuotaKeyRequestPercentage: 25,
+			},
+		},
+		{
+			Entity: admin.QuotaEntity{User: "user3"},
+			Values: map[string]float64{
+				admin.QuotaKeyProducerByteRate: 1024,
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]admin.QuotaUpdate{
+			{
+				Entity: admin.QuotaEntity{User: "user1"},
+				Values: map[string]float64{
+					admin.QuotaKeyProducerByteRate: 1024,
+				},
+				RemovedKeys: []string{},
+			},
+			{
+				Entity: admin.QuotaEntity{User: "user2"},
+				Values: map[string]float64{
+					admin.QuotaKeyProducerByteRate: 2048,
+					admin.QuotaKeyConsumerByteRate: 4096,
+				},
+				RemovedKeys: []string{admin.QuotaKeyRequestPercentage},
+			},
+		},
+		QuotaUpdates(quotaConfigs, currentQuotas),
+	)
+	assert.Equal(
+		t,
+		[]admin.QuotaInfo{
+			{
+				Entity: admin.QuotaEntity{User: "user3"},
+				Values: map[string]float64{
+					admin.QuotaKeyProducerByteRate: 1024,
+				},
+			},
+		},
+		UnmanagedQuotas(quotaConfigs, currentQuotas),
+	)
+	assert.Equal(t, []admin.QuotaUpdate{}, QuotaUpdates(quotaConfigs[2:], currentQuotas))
+}
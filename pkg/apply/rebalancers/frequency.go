@@ -35,6 +35,9 @@ import (
 // The picker passed in to the rebalancer is used to sort the partitions for each broker (if it
 // appears more than once for the current index) and also to break ties when sorting and
 // partitioning the brokers.
+//
+// If the brokers have capacity weights, all of the counts are compared after dividing them by the
+// broker weights, so that the replicas are spread in proportion to the weights.
 type FrequencyRebalancer struct {
 	brokers         []admin.BrokerInfo
 	picker          pickers.Picker
@@ -129,6 +132,9 @@ type brokerCount struct {
 	partitions  []int
 	toBeRemoved bool
 
+	// weight is the capacity weight of the broker; zero means that it isn't set
+	weight float64
+
 	// score is used for breaking ties among brokers with the same count
 	score int
 }
@@ -140,12 +146,37 @@ func (b brokerCount) isSmaller(other brokerCount) bool {
 	} else if b.toBeRemoved && !other.toBeRemoved {
 		return false
 	} else {
-		// Then, within each toBeRemoved partition, by the count for this index, followed by the
-		// total count for the topic, followed by the score
-		return b.indexCount < other.indexCount ||
-			(b.indexCount == other.indexCount && b.totalCount < other.totalCount) ||
-			(b.indexCount == other.indexCount && b.totalCount == other.totalCount && b.score < other.score)
+		// Then, within each toBeRemoved partition, by the weighted count for this index, followed
+		// by the weighted total count for the topic, followed by the score
+		indexCmp := compareWeighted(b.indexCount, b.weight, other.indexCount, other.weight)
+		totalCmp := compareWeighted(b.totalCount, b.weight, other.totalCount, other.weight)
+
+		return indexCmp < 0 ||
+			(indexCmp == 0 && totalCmp < 0) ||
+			(indexCmp == 0 && totalCmp == 0 && b.score < other.score)
+	}
+}
+
+// compareWeighted compares aCount / aWeight with bCount / bWeight, returning -1, 0, or 1 if the
+// former is smaller, equal, or larger, respectively. Weights that aren't positive are treated as
+// 1.
+func compareWeighted(aCount int, aWeight float64, bCount int, bWeight float64) int {
+	if aWeight <= 0 {
+		aWeight = 1.0
+	}
+	if bWeight <= 0 {
+		bWeight = 1.0
 	}
+
+	aValue := float64(aCount) * bWeight
+	bValue := float64(bCount) * aWeight
+
+	if aValue < bValue {
+		return -1
+	} else if aValue > bValue {
+		return 1
+	}
+	return 0
 }
 
 func (f *FrequencyRebalancer) brokerCounts(
@@ -183,6 +214,7 @@ func (f *FrequencyRebalancer) brokerCounts(
 				totalCount:  allPositionBrokerCounts[broker.ID],
 				partitions:  indexPartitions[broker.ID],
 				toBeRemoved: toBeRemoved,
+				weight:      broker.Weight,
 				score:       f.picker.ScoreBroker(topic, broker.ID, 0, index),
 			},
 		)
@@ -221,12 +253,26 @@ func (f *FrequencyRebalancer) shouldTryReplace(
 	} else if lowerCount.toBeRemoved {
 		// Never try to insert a broker to be removed back into a partition
 		return false
-	} else if lowerCount.indexCount+1 < higherCount.indexCount {
+	}
+
+	indexCmp := compareWeighted(
+		lowerCount.indexCount+1,
+		lowerCount.weight,
+		higherCount.indexCount,
+		higherCount.weight,
+	)
+
+	if indexCmp < 0 {
 		// Doing this replacement will strictly improve the in-index balance
 		return true
-	} else if lowerCount.indexCount+1 == higherCount.indexCount {
+	} else if indexCmp == 0 {
 		// Doing this placement is neutral from an in-index perspective, look at the total topic
-		if lowerCount.totalCount+1 < higherCount.totalCount {
+		if compareWeighted(
+			lowerCount.totalCount+1,
+			lowerCount.weight,
+			higherCount.totalCount,
+			higherCount.weight,
+		) < 0 {
 			return true
 		}
 	}
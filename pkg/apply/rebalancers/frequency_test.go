@@ -220,6 +220,47 @@ func TestFrequencyRebalancerAnyInRack(t *testing.T) {
 	}
 }
 
+func TestFrequencyRebalancerAnyWeighted(t *testing.T) {
+	brokers := testBrokers(4, 2)
+	brokers[0].Weight = 3.0
+
+	rebalancer := NewFrequencyRebalancer(
+		brokers,
+		pickers.NewLowestIndexPicker(),
+		config.TopicPlacementConfig{
+			Strategy: config.PlacementStrategyAny,
+		},
+	)
+
+	testCases := []rebalancerTestCase{
+		{
+			description: "Weighted",
+			curr: [][]int{
+				{1, 2},
+				{2, 3},
+				{3, 4},
+				{4, 1},
+				{1, 2},
+				{2, 3},
+			},
+			// Broker 1 ends up leading half of the partitions; it would take more follower
+			// replicas too, but the partitions with followers on broker 2 are already on it
+			expected: [][]int{
+				{1, 2},
+				{1, 3},
+				{3, 4},
+				{4, 1},
+				{1, 2},
+				{2, 1},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase.evaluate(t, rebalancer)
+	}
+}
+
 func TestBrokerCounts(t *testing.T) {
 	brokers := testBrokers(6, 3)
 	rebalancer := NewFrequencyRebalancer(
@@ -455,4 +496,9 @@ func TestBrokerCountShouldReplace(t *testing.T) {
 	assert.True(t, rebalancer.shouldTryReplace(brokerCount1, brokerCount4))
 	assert.False(t, rebalancer.shouldTryReplace(brokerCount3, brokerCount4))
 	assert.True(t, rebalancer.shouldTryReplace(brokerCount4, brokerCount5))
+
+	// A broker with twice the weight can take a replica from one with the same count
+	brokerCount1.weight = 2.0
+	assert.True(t, rebalancer.shouldTryReplace(brokerCount1, brokerCount2))
+	assert.False(t, rebalancer.shouldTryReplace(brokerCount2, brokerCount1))
 }
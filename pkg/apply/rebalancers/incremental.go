@@ -0,0 +1,375 @@
+package rebalancers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply/assigners"
+	"github.com/segmentio/topicctl/pkg/config"
+)
+
+// MoveBudget limits the replica moves made by one or more IncrementalRebalancers. Zero values
+// mean that there's no limit. The moves that have been made are recorded with Spend so that a
+// budget can be shared by the rebalances of several topics.
+type MoveBudget struct {
+	// MaxMoves is the maximum number of replicas that can be moved.
+	MaxMoves int
+
+	// MaxBytes is the maximum total size of the replicas that can be moved.
+	MaxBytes int64
+
+	movedReplicas int
+	movedBytes    int64
+}
+
+// Spend records that the argument number of replicas, with the argument total size, have been
+// moved, which reduces what's left of the budget for later rebalances.
+func (b *MoveBudget) Spend(replicas int, bytes int64) {
+	b.movedReplicas += replicas
+	b.movedBytes += bytes
+}
+
+// Exhausted returns whether the budget has been used up by the moves recorded so far.
+func (b *MoveBudget) Exhausted() bool {
+	return (b.MaxMoves > 0 && b.movedReplicas >= b.MaxMoves) ||
+		(b.MaxBytes > 0 && b.movedBytes >= b.MaxBytes)
+}
+
+// allows returns whether the argument number of additional replicas, with the argument total
+// size, can be moved on top of the moves recorded so far.
+func (b *MoveBudget) allows(replicas int, bytes int64) bool {
+	if b.MaxMoves > 0 && b.movedReplicas+replicas > b.MaxMoves {
+		return false
+	}
+	if b.MaxBytes > 0 && b.movedBytes+bytes > b.MaxBytes {
+		return false
+	}
+	return true
+}
+
+// ReplicaMoves returns the number of replicas that are added to partitions in going from the
+// argument current assignments to the desired ones, along with their total size based on the
+// argument partition sizes.
+func ReplicaMoves(
+	curr []admin.PartitionAssignment,
+	desired []admin.PartitionAssignment,
+	sizes map[int]int64,
+) (int, int64) {
+	currByID := map[int]admin.PartitionAssignment{}
+	for _, assignment := range curr {
+		currByID[assignment.ID] = assignment
+	}
+
+	var replicas int
+	var bytes int64
+
+	for _, assignment := range desired {
+		currAssignment := currByID[assignment.ID]
+		for _, replica := range assignment.Replicas {
+			if currAssignment.Index(replica) == -1 {
+				replicas++
+				bytes += sizes[assignment.ID]
+			}
+		}
+	}
+
+	return replicas, bytes
+}
+
+// IncrementalRebalancer is a Rebalancer that improves the balance of a topic with as little data
+// movement as possible instead of recomputing its assignments from scratch. Each replica is
+// weighted, e.g. by 1 to balance replica counts or by its partition's size or throughput, and the
+// balance is measured by the sum of the squared total weights of the brokers, each divided by the
+// capacity weight of the broker so that higher-capacity brokers take proportionally more. The
+// algorithm is:
+//
+//	for each replica on a broker that's being removed:
+//	  move it to the broker with the lowest weight per capacity that keeps the placement
+//	    consistent
+//
+//	while the budget allows:
+//	  for each replica that hasn't been moved yet:
+//	    for each broker that isn't already in the replica's partition:
+//	      score the move by the reduction in the sum of squares per byte moved
+//	  make the move with the best score that keeps the placement consistent
+//	  if there isn't one, stop
+//
+// Moves are only made if they strictly improve the balance, and each replica is moved at most
+// once, so the loop always terminates. Moves that aren't consistent with the topic's placement
+// config (including its pins) are skipped, as are moves that would spread a partition across fewer
+// racks unless there's no other way to move a replica off of a removed broker. The moves off of
+// the removed brokers count towards the budget; an error is returned if the budget isn't large
+// enough for them. Rebalance doesn't spend the budget itself; that's up to the caller once the
+// moves are made.
+type IncrementalRebalancer struct {
+	brokers         []admin.BrokerInfo
+	brokerRacks     map[int]string
+	capacities      map[int]float64
+	placementConfig config.TopicPlacementConfig
+	weights         map[int]int64
+	baseWeights     map[int]int64
+	sizes           map[int]int64
+	budget          *MoveBudget
+}
+
+var _ Rebalancer = (*IncrementalRebalancer)(nil)
+
+// NewIncrementalRebalancer creates and returns an IncrementalRebalancer instance. The weights and
+// sizes maps are keyed by partition ID, and the baseWeights map has the weights of each broker
+// that aren't from the topic being rebalanced; partitions and brokers that are missing from these
+// are assumed to have zero weight or size.
+func NewIncrementalRebalancer(
+	brokers []admin.BrokerInfo,
+	placementConfig config.TopicPlacementConfig,
+	weights map[int]int64,
+	baseWeights map[int]int64,
+	sizes map[int]int64,
+	budget *MoveBudget,
+) *IncrementalRebalancer {
+	return &IncrementalRebalancer{
+		brokers:         brokers,
+		brokerRacks:     admin.BrokerRacks(brokers),
+		capacities:      admin.BrokerWeights(brokers),
+		placementConfig: placementConfig,
+		weights:         weights,
+		baseWeights:     baseWeights,
+		sizes:           sizes,
+		budget:          budget,
+	}
+}
+
+type incrementalMove struct {
+	partition int
+	index     int
+	broker    int
+	score     float64
+}
+
+// Rebalance rebalances the argument partition assignments according to the algorithm
+// described earlier.
+func (r *IncrementalRebalancer) Rebalance(
+	topic string,
+	curr []admin.PartitionAssignment,
+	brokersToRemove []int,
+) ([]admin.PartitionAssignment, error) {
+	ok, err := assigners.EvaluateAssignments(curr, r.brokers, r.placementConfig)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("Starting assignments do not satisfy placement config")
+	}
+
+	desired := admin.CopyAssignments(curr)
+
+	toRemoveMap := map[int]struct{}{}
+	for _, brokerID := range brokersToRemove {
+		toRemoveMap[brokerID] = struct{}{}
+	}
+
+	brokerWeights := map[int]int64{}
+	for _, broker := range r.brokers {
+		if _, ok := toRemoveMap[broker.ID]; !ok {
+			brokerWeights[broker.ID] = r.baseWeights[broker.ID]
+		}
+	}
+	for _, assignment := range desired {
+		for _, replica := range assignment.Replicas {
+			if _, ok := brokerWeights[replica]; ok {
+				brokerWeights[replica] += r.weights[assignment.ID]
+			}
+		}
+	}
+
+	var moves int
+	var movedBytes int64
+	moved := map[[2]int]struct{}{}
+
+	makeMove := func(move incrementalMove) {
+		assignment := desired[move.partition]
+		source := assignment.Replicas[move.index]
+
+		assignment.Replicas[move.index] = move.broker
+		if _, ok := brokerWeights[source]; ok {
+			brokerWeights[source] -= r.weights[assignment.ID]
+		}
+		brokerWeights[move.broker] += r.weights[assignment.ID]
+
+		moves++
+		movedBytes += r.sizes[assignment.ID]
+		moved[[2]int{move.partition, move.index}] = struct{}{}
+	}
+
+	// First, move the replicas that are on the brokers being removed
+	for a, assignment := range desired {
+		for i, replica := range assignment.Replicas {
+			if _, ok := toRemoveMap[replica]; !ok {
+				continue
+			}
+			if !r.withinBudget(moves, movedBytes, r.sizes[assignment.ID]) {
+				return nil, fmt.Errorf(
+					"Move budget is too small to move the replicas off of brokers %+v",
+					brokersToRemove,
+				)
+			}
+
+			candidates := []incrementalMove{}
+			for _, broker := range r.sortedBrokers(brokerWeights) {
+				candidates = append(
+					candidates,
+					incrementalMove{partition: a, index: i, broker: broker},
+				)
+			}
+
+			// Prefer brokers that keep the partition in as many racks as possible
+			move, ok := r.firstConsistentMove(desired, candidates, true)
+			if !ok {
+				move, ok = r.firstConsistentMove(desired, candidates, false)
+			}
+			if !ok {
+				return nil, fmt.Errorf(
+					"Could not find a feasible replacement for broker %d in partition %d",
+					replica,
+					assignment.ID,
+				)
+			}
+			makeMove(move)
+		}
+	}
+
+	// Then, make the most efficient moves until the budget runs out or the balance can't be
+	// improved any further
+	for {
+		candidates := []incrementalMove{}
+
+		for a, assignment := range desired {
+			weight := r.weights[assignment.ID]
+			size := r.sizes[assignment.ID]
+			if weight <= 0 || !r.withinBudget(moves, movedBytes, size) {
+				continue
+			}
+
+			// Empty partitions are cheap to move but not free, so treat them like tiny ones
+			cost := size
+			if cost < 1 {
+				cost = 1
+			}
+
+			for i, replica := range assignment.Replicas {
+				if _, ok := moved[[2]int{a, i}]; ok {
+					continue
+				}
+
+				for broker, brokerWeight := range brokerWeights {
+					if assignment.Index(broker) != -1 {
+						continue
+					}
+
+					// This is the reduction in the sum of squared broker weights, divided by
+					// the broker capacities
+					w := float64(weight)
+					source := float64(brokerWeights[replica])
+					dest := float64(brokerWeight)
+					reduction := w*(2*source-w)/r.capacities[replica] -
+						w*(2*dest+w)/r.capacities[broker]
+					if reduction <= 0 {
+						continue
+					}
+
+					candidates = append(
+						candidates,
+						incrementalMove{
+							partition: a,
+							index:     i,
+							broker:    broker,
+							score:     reduction / float64(cost),
+						},
+					)
+				}
+			}
+		}
+
+		// Ties favor follower replicas so that leaders are moved less often
+		sort.Slice(candidates, func(a, b int) bool {
+			if candidates[a].score != candidates[b].score {
+				return candidates[a].score > candidates[b].score
+			}
+			if candidates[a].index != candidates[b].index {
+				return candidates[a].index > candidates[b].index
+			}
+			if candidates[a].partition != candidates[b].partition {
+				return candidates[a].partition < candidates[b].partition
+			}
+			return candidates[a].broker < candidates[b].broker
+		})
+
+		move, ok := r.firstConsistentMove(desired, candidates, true)
+		if !ok {
+			break
+		}
+		makeMove(move)
+	}
+
+	return desired, nil
+}
+
+// withinBudget returns whether a move of a replica with the argument size fits in the budget,
+// given the moves made so far.
+func (r *IncrementalRebalancer) withinBudget(moves int, movedBytes int64, size int64) bool {
+	return r.budget.allows(moves+1, movedBytes+size)
+}
+
+// firstConsistentMove returns the first of the argument moves that keeps the assignments
+// consistent with the placement config and, if checkRacks is set, doesn't reduce the number of
+// racks of its partition.
+func (r *IncrementalRebalancer) firstConsistentMove(
+	desired []admin.PartitionAssignment,
+	candidates []incrementalMove,
+	checkRacks bool,
+) (incrementalMove, bool) {
+	for _, candidate := range candidates {
+		assignment := desired[candidate.partition]
+		if assignment.Index(candidate.broker) != -1 {
+			continue
+		}
+
+		source := assignment.Replicas[candidate.index]
+		racksBefore := len(assignment.DistinctRacks(r.brokerRacks))
+
+		assignment.Replicas[candidate.index] = candidate.broker
+		racksAfter := len(assignment.DistinctRacks(r.brokerRacks))
+
+		ok := !checkRacks || racksAfter >= racksBefore
+		if ok {
+			ok, _ = assigners.EvaluateAssignments(desired, r.brokers, r.placementConfig)
+		}
+		assignment.Replicas[candidate.index] = source
+
+		if ok {
+			return candidate, true
+		}
+	}
+
+	return incrementalMove{}, false
+}
+
+// sortedBrokers returns the IDs of the brokers in brokerWeights sorted by increasing weight per
+// unit of capacity, breaking ties by ID.
+func (r *IncrementalRebalancer) sortedBrokers(brokerWeights map[int]int64) []int {
+	brokers := []int{}
+	for broker := range brokerWeights {
+		brokers = append(brokers, broker)
+	}
+
+	sort.Slice(brokers, func(a, b int) bool {
+		aWeight := float64(brokerWeights[brokers[a]]) / r.capacities[brokers[a]]
+		bWeight := float64(brokerWeights[brokers[b]]) / r.capacities[brokers[b]]
+
+		if aWeight == bWeight {
+			return brokers[a] < brokers[b]
+		}
+		return aWeight < bWeight
+	})
+
+	return brokers
+}
@@ -0,0 +1,306 @@
+package rebalancers
+
+import (
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrementalRebalancer(t *testing.T) {
+	brokers := testBrokers(4, 2)
+
+	// Each replica is weighted by 1 so that the replica counts are balanced, but partition 1 is
+	// by far the cheapest to move
+	weights := map[int]int64{0: 1, 1: 1, 2: 1, 3: 1}
+	sizes := map[int]int64{0: 100, 1: 10, 2: 50, 3: 10}
+
+	type testCase struct {
+		rebalancerTestCase
+		budget MoveBudget
+	}
+
+	testCases := []testCase{
+		{
+			rebalancerTestCase: rebalancerTestCase{
+				description: "Unlimited budget",
+				curr: [][]int{
+					{1, 2},
+					{1, 2},
+					{1, 2},
+					{3, 4},
+				},
+				// Moving both replicas of the smallest partition balances the counts
+				expected: [][]int{
+					{1, 2},
+					{3, 4},
+					{1, 2},
+					{3, 4},
+				},
+			},
+		},
+		{
+			rebalancerTestCase: rebalancerTestCase{
+				description: "Move budget",
+				curr: [][]int{
+					{1, 2},
+					{1, 2},
+					{1, 2},
+					{3, 4},
+				},
+				// Only the follower is moved
+				expected: [][]int{
+					{1, 2},
+					{1, 4},
+					{1, 2},
+					{3, 4},
+				},
+			},
+			budget: MoveBudget{MaxMoves: 1},
+		},
+		{
+			rebalancerTestCase: rebalancerTestCase{
+				description: "Bytes budget",
+				curr: [][]int{
+					{1, 2},
+					{1, 2},
+					{1, 2},
+					{3, 4},
+				},
+				expected: [][]int{
+					{1, 2},
+					{1, 4},
+					{1, 2},
+					{3, 4},
+				},
+			},
+			budget: MoveBudget{MaxBytes: 15},
+		},
+		{
+			rebalancerTestCase: rebalancerTestCase{
+				description: "Already balanced",
+				curr: [][]int{
+					{1, 2},
+					{3, 4},
+					{1, 2},
+					{3, 4},
+				},
+				expected: [][]int{
+					{1, 2},
+					{3, 4},
+					{1, 2},
+					{3, 4},
+				},
+			},
+		},
+		{
+			rebalancerTestCase: rebalancerTestCase{
+				description: "Removals",
+				curr: [][]int{
+					{1, 2},
+					{1, 2},
+					{1, 2},
+					{3, 4},
+				},
+				toRemove: []int{4},
+				// Broker 4 is replaced by the only other broker in its rack, and then the
+				// smallest partition's leader is moved off of broker 1
+				expected: [][]int{
+					{1, 2},
+					{3, 2},
+					{1, 2},
+					{3, 2},
+				},
+			},
+		},
+		{
+			rebalancerTestCase: rebalancerTestCase{
+				description: "Removals within budget",
+				curr: [][]int{
+					{1, 2},
+					{1, 2},
+					{1, 2},
+					{3, 4},
+				},
+				toRemove: []int{4},
+				expected: [][]int{
+					{1, 2},
+					{1, 2},
+					{1, 2},
+					{3, 2},
+				},
+			},
+			budget: MoveBudget{MaxMoves: 1},
+		},
+		{
+			rebalancerTestCase: rebalancerTestCase{
+				description: "Removals over budget",
+				curr: [][]int{
+					{1, 2},
+					{1, 2},
+					{1, 2},
+					{3, 4},
+				},
+				toRemove: []int{1},
+				err:      assert.AnError,
+			},
+			budget: MoveBudget{MaxMoves: 2},
+		},
+	}
+
+	for _, testCase := range testCases {
+		rebalancer := NewIncrementalRebalancer(
+			brokers,
+			config.TopicPlacementConfig{
+				Strategy: config.PlacementStrategyAny,
+			},
+			weights,
+			nil,
+			sizes,
+			&testCase.budget,
+		)
+		testCase.evaluate(t, rebalancer)
+	}
+}
+
+func TestIncrementalRebalancerPins(t *testing.T) {
+	brokers := testBrokers(4, 2)
+	weights := map[int]int64{0: 1, 1: 1, 2: 1, 3: 1}
+	sizes := map[int]int64{0: 100, 1: 10, 2: 50, 3: 10}
+
+	leader := 1
+	rebalancer := NewIncrementalRebalancer(
+		brokers,
+		config.TopicPlacementConfig{
+			Strategy: config.PlacementStrategyCrossRack,
+			Pins: []config.PartitionPin{
+				{
+					Partition: 1,
+					Leader:    &leader,
+				},
+			},
+		},
+		weights,
+		nil,
+		sizes,
+		&MoveBudget{},
+	)
+
+	testCase := rebalancerTestCase{
+		description: "Pinned leader",
+		curr: [][]int{
+			{1, 2},
+			{1, 2},
+			{1, 2},
+			{3, 4},
+		},
+		// The leader of partition 1 can't be moved, so the next cheapest partition's leader is
+		// moved instead
+		expected: [][]int{
+			{1, 2},
+			{1, 4},
+			{3, 2},
+			{3, 4},
+		},
+	}
+	testCase.evaluate(t, rebalancer)
+}
+
+func TestIncrementalRebalancerBrokerWeights(t *testing.T) {
+	brokers := testBrokers(4, 2)
+	brokers[0].Weight = 2.0
+
+	weights := map[int]int64{0: 1, 1: 1, 2: 1, 3: 1}
+	sizes := map[int]int64{0: 100, 1: 10, 2: 50, 3: 10}
+
+	rebalancer := NewIncrementalRebalancer(
+		brokers,
+		config.TopicPlacementConfig{
+			Strategy: config.PlacementStrategyCrossRack,
+		},
+		weights,
+		nil,
+		sizes,
+		&MoveBudget{},
+	)
+
+	testCase := rebalancerTestCase{
+		description: "Broker weights",
+		curr: [][]int{
+			{1, 2},
+			{3, 4},
+			{1, 2},
+			{3, 4},
+		},
+		// Broker 1 has twice the capacity of the others, so it takes a replica from broker 3
+		expected: [][]int{
+			{1, 2},
+			{1, 4},
+			{1, 2},
+			{3, 4},
+		},
+	}
+	testCase.evaluate(t, rebalancer)
+}
+
+func TestIncrementalRebalancerSharedBudget(t *testing.T) {
+	brokers := testBrokers(4, 2)
+	weights := map[int]int64{0: 1, 1: 1, 2: 1, 3: 1}
+	sizes := map[int]int64{0: 100, 1: 10, 2: 50, 3: 10}
+
+	// One of the two moves was already made in an earlier topic
+	budget := &MoveBudget{MaxMoves: 2}
+	budget.Spend(1, 100)
+	assert.False(t, budget.Exhausted())
+
+	rebalancer := NewIncrementalRebalancer(
+		brokers,
+		config.TopicPlacementConfig{
+			Strategy: config.PlacementStrategyAny,
+		},
+		weights,
+		nil,
+		sizes,
+		budget,
+	)
+
+	curr := [][]int{
+		{1, 2},
+		{1, 2},
+		{1, 2},
+		{3, 4},
+	}
+	expected := [][]int{
+		{1, 2},
+		{1, 4},
+		{1, 2},
+		{3, 4},
+	}
+
+	testCase := rebalancerTestCase{
+		description: "Shared budget",
+		curr:        curr,
+		expected:    expected,
+	}
+	testCase.evaluate(t, rebalancer)
+
+	replicas, bytes := ReplicaMoves(
+		admin.ReplicasToAssignments(curr),
+		admin.ReplicasToAssignments(expected),
+		sizes,
+	)
+	assert.Equal(t, 1, replicas)
+	assert.Equal(t, int64(10), bytes)
+
+	budget.Spend(replicas, bytes)
+	assert.True(t, budget.Exhausted())
+
+	// Nothing is moved once the budget is used up
+	testCase = rebalancerTestCase{
+		description: "Exhausted budget",
+		curr:        expected,
+		expected:    expected,
+	}
+	testCase.evaluate(t, rebalancer)
+}
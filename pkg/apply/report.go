@@ -0,0 +1,464 @@
+package apply
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// ApplyOutcome is a string type that describes what happened to a topic in an apply run.
+type ApplyOutcome string
+
+const (
+	// ApplyOutcomeUnchanged means that the topic already matched its config.
+	ApplyOutcomeUnchanged ApplyOutcome = "unchanged"
+
+	// ApplyOutcomePending means that the topic has changes that weren't applied because the run
+	// was a dry-run.
+	ApplyOutcomePending ApplyOutcome = "pending"
+
+	// ApplyOutcomeApplied means that the topic's changes were applied successfully.
+	ApplyOutcomeApplied ApplyOutcome = "applied"
+
+	// ApplyOutcomeFailed means that there was an error applying the topic's changes.
+	ApplyOutcomeFailed ApplyOutcome = "failed"
+)
+
+// TopicApplyResult contains the changes that an apply run made, or would make, to a single
+// topic, along with the outcome of the run.
+type TopicApplyResult struct {
+	Cluster     string
+	Environment string
+	Diff        TopicDiff
+	Outcome     ApplyOutcome
+
+	// Error is set if the outcome is ApplyOutcomeFailed.
+	Error error
+}
+
+// ACLApplyResult contains the ACL changes that an apply run made, or would make, for a single
+// ACL config, along with the outcome of the run.
+type ACLApplyResult struct {
+	Cluster     string
+	Environment string
+	Name        string
+	Diffs       []ACLResourceDiff
+	Outcome     ApplyOutcome
+
+	// Error is set if the outcome is ApplyOutcomeFailed.
+	Error error
+}
+
+// ApplyReport collects the results of applying one or more topic configs, and optionally ACL
+// configs, so that they can be summarized, e.g. in a change ticket.
+type ApplyReport struct {
+	DryRun     bool
+	Results    []TopicApplyResult
+	ACLResults []ACLApplyResult
+}
+
+// AddResult adds the result for a single topic to the report. The outcome is determined from
+// the argument diff, the report's DryRun setting, and the argument error from the apply.
+func (r *ApplyReport) AddResult(
+	cluster string,
+	environment string,
+	diff TopicDiff,
+	err error,
+) {
+	result := TopicApplyResult{
+		Cluster:     cluster,
+		Environment: environment,
+		Diff:        diff,
+		Error:       err,
+	}
+
+	switch {
+	case err != nil:
+		result.Outcome = ApplyOutcomeFailed
+	case !diff.HasDiffs():
+		result.Outcome = ApplyOutcomeUnchanged
+	case r.DryRun:
+		result.Outcome = ApplyOutcomePending
+	default:
+		result.Outcome = ApplyOutcomeApplied
+	}
+
+	r.Results = append(r.Results, result)
+}
+
+// AddACLResult adds the result for a single ACL config to the report. The outcome is determined
+// in the same way as in AddResult.
+func (r *ApplyReport) AddACLResult(
+	cluster string,
+	environment string,
+	name string,
+	diffs []ACLResourceDiff,
+	err error,
+) {
+	result := ACLApplyResult{
+		Cluster:     cluster,
+		Environment: environment,
+		Name:        name,
+		Diffs:       diffs,
+		Error:       err,
+	}
+
+	switch {
+	case err != nil:
+		result.Outcome = ApplyOutcomeFailed
+	case len(diffs) == 0:
+		result.Outcome = ApplyOutcomeUnchanged
+	case r.DryRun:
+		result.Outcome = ApplyOutcomePending
+	default:
+		result.Outcome = ApplyOutcomeApplied
+	}
+
+	r.ACLResults = append(r.ACLResults, result)
+}
+
+// Clusters returns the names of the clusters that the topics and ACL configs in the report are
+// in, sorted by name.
+func (r ApplyReport) Clusters() []string {
+	clustersMap := map[string]struct{}{}
+	for _, result := range r.Results {
+		clustersMap[result.Cluster] = struct{}{}
+	}
+	for _, result := range r.ACLResults {
+		clustersMap[result.Cluster] = struct{}{}
+	}
+
+	clusters := []string{}
+	for cluster := range clustersMap {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// ForCluster returns a copy of the report with just the results for the argument cluster.
+func (r ApplyReport) ForCluster(cluster string) ApplyReport {
+	clusterReport := ApplyReport{
+		DryRun:  r.DryRun,
+		Results: []TopicApplyResult{},
+	}
+	for _, result := range r.Results {
+		if result.Cluster == cluster {
+			clusterReport.Results = append(clusterReport.Results, result)
+		}
+	}
+	for _, result := range r.ACLResults {
+		if result.Cluster == cluster {
+			clusterReport.ACLResults = append(clusterReport.ACLResults, result)
+		}
+	}
+	return clusterReport
+}
+
+// HasChanges returns whether any of the topics or ACL configs in the report didn't already match
+// the cluster.
+func (r ApplyReport) HasChanges() bool {
+	for _, result := range r.Results {
+		if result.Outcome != ApplyOutcomeUnchanged {
+			return true
+		}
+	}
+	for _, result := range r.ACLResults {
+		if result.Outcome != ApplyOutcomeUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// ToMarkdown renders the report as a Markdown document with a summary table followed by a
+// section for each topic that has changes. ACL configs, if any, get their own summary table and
+// sections after the topics.
+func (r ApplyReport) ToMarkdown() (string, error) {
+	buf := &bytes.Buffer{}
+
+	title := "topicctl apply report"
+	if clusters := r.Clusters(); len(clusters) == 1 {
+		title = fmt.Sprintf("%s for cluster %s", title, clusters[0])
+	}
+	if r.DryRun {
+		title = fmt.Sprintf("%s (dry-run)", title)
+	}
+	fmt.Fprintf(buf, "# %s\n\n", title)
+
+	if len(r.Results) == 0 && len(r.ACLResults) == 0 {
+		fmt.Fprintf(buf, "No topics were processed.\n")
+		return buf.String(), nil
+	}
+
+	if len(r.Results) > 0 {
+		fmt.Fprintf(buf, "| Topic | Cluster | Environment | Outcome |\n")
+		fmt.Fprintf(buf, "| --- | --- | --- | --- |\n")
+		for _, result := range r.Results {
+			writeMarkdownRow(
+				buf,
+				result.Diff.Topic,
+				result.Cluster,
+				result.Environment,
+				string(result.Outcome),
+			)
+		}
+	}
+
+	if len(r.ACLResults) > 0 {
+		if len(r.Results) > 0 {
+			fmt.Fprintf(buf, "\n")
+		}
+		fmt.Fprintf(buf, "| ACL config | Cluster | Environment | Outcome |\n")
+		fmt.Fprintf(buf, "| --- | --- | --- | --- |\n")
+		for _, result := range r.ACLResults {
+			writeMarkdownRow(
+				buf,
+				result.Name,
+				result.Cluster,
+				result.Environment,
+				string(result.Outcome),
+			)
+		}
+	}
+
+	for _, result := range r.Results {
+		if result.Outcome == ApplyOutcomeUnchanged {
+			continue
+		}
+		if err := writeTopicMarkdown(buf, result); err != nil {
+			return "", err
+		}
+	}
+
+	for _, result := range r.ACLResults {
+		if result.Outcome == ApplyOutcomeUnchanged {
+			continue
+		}
+		writeACLConfigMarkdown(buf, result)
+	}
+
+	return buf.String(), nil
+}
+
+func writeACLConfigMarkdown(buf *bytes.Buffer, result ACLApplyResult) {
+	fmt.Fprintf(buf, "\n## ACLs %s (%s)\n\n", result.Name, result.Cluster)
+	fmt.Fprintf(buf, "Outcome: **%s**\n", result.Outcome)
+
+	if result.Error != nil {
+		fmt.Fprintf(buf, "\n```\n%+v\n```\n", result.Error)
+	}
+	if len(result.Diffs) == 0 {
+		return
+	}
+
+	fmt.Fprintf(buf, "\n### ACL changes\n\n")
+	fmt.Fprintf(buf, "| Change | Resource | Principal | Host | Operation | Permission |\n")
+	fmt.Fprintf(buf, "| --- | --- | --- | --- | --- | --- |\n")
+
+	for _, diff := range result.Diffs {
+		for _, acl := range diff.MissingACLs {
+			writeMarkdownRow(
+				buf,
+				"create",
+				diff.Resource.String(),
+				acl.Principal,
+				acl.Host,
+				acl.Operation.String(),
+				acl.PermissionType.String(),
+			)
+		}
+		for _, acl := range diff.ExtraACLs {
+			writeMarkdownRow(
+				buf,
+				"delete",
+				diff.Resource.String(),
+				acl.Principal,
+				acl.Host,
+				acl.Operation.String(),
+				acl.PermissionType.String(),
+			)
+		}
+	}
+}
+
+func writeTopicMarkdown(buf *bytes.Buffer, result TopicApplyResult) error {
+	diff := result.Diff
+
+	fmt.Fprintf(buf, "\n## %s (%s)\n\n", diff.Topic, result.Cluster)
+	fmt.Fprintf(buf, "Outcome: **%s**\n", result.Outcome)
+
+	if result.Error != nil {
+		fmt.Fprintf(buf, "\n```\n%+v\n```\n", result.Error)
+	}
+
+	if diff.New {
+		fmt.Fprintf(
+			buf,
+			"\nNew topic with %d partition(s) and a replication factor of %d.\n",
+			diff.NewTopicConfig.NumPartitions,
+			diff.NewTopicConfig.ReplicationFactor,
+		)
+
+		if len(diff.NewTopicConfig.ConfigEntries) > 0 {
+			entries := append(
+				[]kafka.ConfigEntry{},
+				diff.NewTopicConfig.ConfigEntries...,
+			)
+			sort.Slice(entries, func(a, b int) bool {
+				return entries[a].ConfigName < entries[b].ConfigName
+			})
+
+			fmt.Fprintf(buf, "\n### Settings\n\n")
+			fmt.Fprintf(buf, "| Setting | Value |\n")
+			fmt.Fprintf(buf, "| --- | --- |\n")
+			for _, entry := range entries {
+				writeMarkdownRow(buf, entry.ConfigName, entry.ConfigValue)
+			}
+		}
+		writeACLsMarkdown(buf, diff)
+		return nil
+	}
+
+	if len(diff.SettingsDiffKeys) > 0 {
+		fmt.Fprintf(buf, "\n### Setting changes\n\n")
+		fmt.Fprintf(buf, "| Setting | Current | New |\n")
+		fmt.Fprintf(buf, "| --- | --- | --- |\n")
+
+		for _, key := range diff.SettingsDiffKeys {
+			currValue := diff.ClusterSettings[key]
+
+			var newValue string
+			if diff.TopicSettings.HasKey(key) {
+				var err error
+				newValue, err = diff.TopicSettings.GetValueStr(key)
+				if err != nil {
+					return err
+				}
+			}
+
+			if strings.HasSuffix(key, ".ms") {
+				currValue = fmt.Sprintf("%s%s", currValue, timeSuffix(currValue))
+				newValue = fmt.Sprintf("%s%s", newValue, timeSuffix(newValue))
+			}
+
+			writeMarkdownRow(buf, key, currValue, newValue)
+		}
+	}
+
+	writeACLsMarkdown(buf, diff)
+
+	if diff.CurrPartitions != diff.DesiredPartitions ||
+		diff.CurrReplication != diff.DesiredReplication {
+		fmt.Fprintf(buf, "\n### Partitions\n\n")
+		fmt.Fprintf(buf, "| | Current | New |\n")
+		fmt.Fprintf(buf, "| --- | --- | --- |\n")
+		writeMarkdownRow(
+			buf,
+			"Partitions",
+			fmt.Sprintf("%d", diff.CurrPartitions),
+			fmt.Sprintf("%d", diff.DesiredPartitions),
+		)
+		writeMarkdownRow(
+			buf,
+			"Replication factor",
+			fmt.Sprintf("%d", diff.CurrReplication),
+			fmt.Sprintf("%d", diff.DesiredReplication),
+		)
+
+		if diff.CurrPartitions > diff.DesiredPartitions {
+			fmt.Fprintf(
+				buf,
+				"\nThe partition count can't be decreased by topicctl.\n",
+			)
+		}
+		if diff.CurrReplication != diff.DesiredReplication {
+			fmt.Fprintf(
+				buf,
+				"\nThe replication factor can't be changed by topicctl.\n",
+			)
+		}
+	}
+
+	currChanged, desiredChanged := diff.ChangedAssignments()
+	if len(desiredChanged) > 0 {
+		currByID := map[int]admin.PartitionAssignment{}
+		for _, assignment := range currChanged {
+			currByID[assignment.ID] = assignment
+		}
+
+		fmt.Fprintf(buf, "\n### Replica moves (%d partition(s))\n\n", len(desiredChanged))
+		fmt.Fprintf(buf, "| Partition | Current replicas | New replicas |\n")
+		fmt.Fprintf(buf, "| --- | --- | --- |\n")
+
+		for _, desired := range desiredChanged {
+			var currReplicas string
+			if curr, ok := currByID[desired.ID]; ok {
+				currReplicas = fmt.Sprintf("%+v", curr.Replicas)
+			} else {
+				currReplicas = "(new)"
+			}
+
+			writeMarkdownRow(
+				buf,
+				fmt.Sprintf("%d", desired.ID),
+				currReplicas,
+				fmt.Sprintf("%+v", desired.Replicas),
+			)
+		}
+	}
+
+	if len(diff.WrongLeaders) > 0 {
+		fmt.Fprintf(
+			buf,
+			"\n### Leader elections\n\nPartitions whose leader isn't the preferred one (%d): %+v\n",
+			len(diff.WrongLeaders),
+			diff.WrongLeaders,
+		)
+	}
+
+	return nil
+}
+
+func writeACLsMarkdown(buf *bytes.Buffer, diff TopicDiff) {
+	if !diff.HasACLDiffs() {
+		return
+	}
+
+	fmt.Fprintf(buf, "\n### ACL changes\n\n")
+	fmt.Fprintf(buf, "| Change | Principal | Host | Operation | Permission |\n")
+	fmt.Fprintf(buf, "| --- | --- | --- | --- | --- |\n")
+
+	for _, acl := range diff.MissingACLs {
+		writeMarkdownRow(
+			buf,
+			"create",
+			acl.Principal,
+			acl.Host,
+			acl.Operation.String(),
+			acl.PermissionType.String(),
+		)
+	}
+	for _, acl := range diff.ExtraACLs {
+		writeMarkdownRow(
+			buf,
+			"delete",
+			acl.Principal,
+			acl.Host,
+			acl.Operation.String(),
+			acl.PermissionType.String(),
+		)
+	}
+}
+
+func writeMarkdownRow(buf *bytes.Buffer, cells ...string) {
+	escaped := []string{}
+	for _, cell := range cells {
+		escaped = append(escaped, strings.ReplaceAll(cell, "|", `\|`))
+	}
+	fmt.Fprintf(buf, "| %s |\n", strings.Join(escaped, " | "))
+}
@@ -0,0 +1,231 @@
+package apply
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyReportToMarkdown(t *testing.T) {
+	report := &ApplyReport{DryRun: true}
+
+	report.AddResult(
+		"test-cluster",
+		"test-env",
+		TopicDiff{
+			Topic:              "topic-unchanged",
+			CurrReplication:    2,
+			DesiredReplication: 2,
+			CurrPartitions:     2,
+			DesiredPartitions:  2,
+		},
+		nil,
+	)
+	report.AddResult(
+		"test-cluster",
+		"test-env",
+		TopicDiff{
+			Topic: "topic-changed",
+			TopicSettings: config.TopicSettings{
+				"cleanup.policy": "compact",
+				"retention.ms":   7200000,
+			},
+			ClusterSettings: map[string]string{
+				"cleanup.policy": "delete",
+				"retention.ms":   "3600000",
+			},
+			SettingsDiffKeys:   []string{"cleanup.policy", "retention.ms"},
+			CurrReplication:    2,
+			DesiredReplication: 2,
+			CurrPartitions:     2,
+			DesiredPartitions:  3,
+			CurrAssignments: []admin.PartitionAssignment{
+				{ID: 0, Replicas: []int{1, 2}},
+				{ID: 1, Replicas: []int{2, 3}},
+			},
+			DesiredAssignments: []admin.PartitionAssignment{
+				{ID: 0, Replicas: []int{1, 2}},
+				{ID: 1, Replicas: []int{3, 2}},
+				{ID: 2, Replicas: []int{1, 3}},
+			},
+			WrongLeaders: []int{1},
+		},
+		nil,
+	)
+	report.AddResult(
+		"test-cluster",
+		"test-env",
+		TopicDiff{
+			Topic: "topic-new",
+			New:   true,
+			NewTopicConfig: kafka.TopicConfig{
+				Topic:             "topic-new",
+				NumPartitions:     6,
+				ReplicationFactor: 3,
+				ConfigEntries: []kafka.ConfigEntry{
+					{ConfigName: "retention.ms", ConfigValue: "60000"},
+					{ConfigName: "cleanup.policy", ConfigValue: "compact|delete"},
+				},
+			},
+			MissingACLs: []admin.ACLInfo{
+				testACL("User:alice", kafka.ACLOperationTypeRead),
+			},
+		},
+		errors.New("Could not create topic"),
+	)
+
+	require.Equal(t, 3, len(report.Results))
+	assert.Equal(t, ApplyOutcomeUnchanged, report.Results[0].Outcome)
+	assert.Equal(t, ApplyOutcomePending, report.Results[1].Outcome)
+	assert.Equal(t, ApplyOutcomeFailed, report.Results[2].Outcome)
+
+	assert.Equal(t, []string{"test-cluster"}, report.Clusters())
+	assert.True(t, report.HasChanges())
+	assert.Equal(t, 0, len(report.ForCluster("other-cluster").Results))
+	assert.False(t, report.ForCluster("other-cluster").HasChanges())
+
+	markdown, err := report.ToMarkdown()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"# topicctl apply report for cluster test-cluster (dry-run)\n"+
+			"\n"+
+			"| Topic | Cluster | Environment | Outcome |\n"+
+			"| --- | --- | --- | --- |\n"+
+			"| topic-unchanged | test-cluster | test-env | unchanged |\n"+
+			"| topic-changed | test-cluster | test-env | pending |\n"+
+			"| topic-new | test-cluster | test-env | failed |\n"+
+			"\n"+
+			"## topic-changed (test-cluster)\n"+
+			"\n"+
+			"Outcome: **pending**\n"+
+			"\n"+
+			"### Setting changes\n"+
+			"\n"+
+			"| Setting | Current | New |\n"+
+			"| --- | --- | --- |\n"+
+			"| cleanup.policy | delete | compact |\n"+
+			"| retention.ms | 3600000 (60 min) | 7200000 (120 min) |\n"+
+			"\n"+
+			"### Partitions\n"+
+			"\n"+
+			"| | Current | New |\n"+
+			"| --- | --- | --- |\n"+
+			"| Partitions | 2 | 3 |\n"+
+			"| Replication factor | 2 | 2 |\n"+
+			"\n"+
+			"### Replica moves (2 partition(s))\n"+
+			"\n"+
+			"| Partition | Current replicas | New replicas |\n"+
+			"| --- | --- | --- |\n"+
+			"| 1 | [2 3] | [3 2] |\n"+
+			"| 2 | (new) | [1 3] |\n"+
+			"\n"+
+			"### Leader elections\n"+
+			"\n"+
+			"Partitions whose leader isn't the preferred one (1): [1]\n"+
+			"\n"+
+			"## topic-new (test-cluster)\n"+
+			"\n"+
+			"Outcome: **failed**\n"+
+			"\n"+
+			"```\n"+
+			"Could not create topic\n"+
+			"```\n"+
+			"\n"+
+			"New topic with 6 partition(s) and a replication factor of 3.\n"+
+			"\n"+
+			"### Settings\n"+
+			"\n"+
+			"| Setting | Value |\n"+
+			"| --- | --- |\n"+
+			"| cleanup.policy | compact\\|delete |\n"+
+			"| retention.ms | 60000 |\n"+
+			"\n"+
+			"### ACL changes\n"+
+			"\n"+
+			"| Change | Principal | Host | Operation | Permission |\n"+
+			"| --- | --- | --- | --- | --- |\n"+
+			"| create | User:alice | * | Read | Allow |\n",
+		markdown,
+	)
+}
+
+func TestApplyReportACLResults(t *testing.T) {
+	report := &ApplyReport{DryRun: true}
+
+	report.AddACLResult("test-cluster", "test-env", "acls-unchanged", nil, nil)
+	report.AddACLResult(
+		"test-cluster",
+		"test-env",
+		"acls-changed",
+		[]ACLResourceDiff{
+			{
+				Resource: config.ACLResource{
+					Type:        kafka.ResourceTypeGroup,
+					Name:        "payments-",
+					PatternType: kafka.PatternTypePrefixed,
+				},
+				MissingACLs: []admin.ACLInfo{
+					testACL("User:alice", kafka.ACLOperationTypeRead),
+				},
+				ExtraACLs: []admin.ACLInfo{
+					testACL("User:bob", kafka.ACLOperationTypeRead),
+				},
+			},
+		},
+		nil,
+	)
+	report.AddACLResult(
+		"other-cluster",
+		"test-env",
+		"acls-failed",
+		nil,
+		errors.New("Could not get ACLs"),
+	)
+
+	require.Equal(t, 3, len(report.ACLResults))
+	assert.Equal(t, ApplyOutcomeUnchanged, report.ACLResults[0].Outcome)
+	assert.Equal(t, ApplyOutcomePending, report.ACLResults[1].Outcome)
+	assert.Equal(t, ApplyOutcomeFailed, report.ACLResults[2].Outcome)
+
+	assert.Equal(t, []string{"other-cluster", "test-cluster"}, report.Clusters())
+	assert.True(t, report.HasChanges())
+
+	clusterReport := report.ForCluster("test-cluster")
+	require.Equal(t, 2, len(clusterReport.ACLResults))
+	assert.True(t, clusterReport.HasChanges())
+
+	markdown, err := clusterReport.ToMarkdown()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"# topicctl apply report for cluster test-cluster (dry-run)\n"+
+			"\n"+
+			"| ACL config | Cluster | Environment | Outcome |\n"+
+			"| --- | --- | --- | --- |\n"+
+			"| acls-unchanged | test-cluster | test-env | unchanged |\n"+
+			"| acls-changed | test-cluster | test-env | pending |\n"+
+			"\n"+
+			"## ACLs acls-changed (test-cluster)\n"+
+			"\n"+
+			"Outcome: **pending**\n"+
+			"\n"+
+			"### ACL changes\n"+
+			"\n"+
+			"| Change | Resource | Principal | Host | Operation | Permission |\n"+
+			"| --- | --- | --- | --- | --- | --- |\n"+
+			"| create | Group payments- (Prefixed) | User:alice | * | Read | Allow |\n"+
+			"| delete | Group payments- (Prefixed) | User:bob | * | Read | Allow |\n",
+		markdown,
+	)
+
+	unchangedReport := ApplyReport{}
+	unchangedReport.AddACLResult("test-cluster", "test-env", "acls-unchanged", nil, nil)
+	assert.False(t, unchangedReport.HasChanges())
+}
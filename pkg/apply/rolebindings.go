@@ -0,0 +1,153 @@
+package apply
+
+import (
+	"bytes"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/rbac"
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// RoleBindingDiff stores the role binding changes needed to make the bindings of a single
+// principal in the cluster match a role binding config.
+type RoleBindingDiff struct {
+	Principal       string
+	MissingBindings []rbac.RoleBinding
+	ExtraBindings   []rbac.RoleBinding
+}
+
+// RoleBindingDiffs compares the desired role bindings in a role binding config against the
+// current ones in the cluster and returns the diffs for the principals that don't match, in
+// config order. Current bindings of principals that aren't in the config are ignored.
+func RoleBindingDiffs(
+	roleBindingConfig config.RoleBindingConfig,
+	currentBindings []rbac.RoleBinding,
+) ([]RoleBindingDiff, error) {
+	desiredBindings, err := roleBindingConfig.RoleBindings()
+	if err != nil {
+		return nil, err
+	}
+
+	desiredByPrincipal := map[string][]rbac.RoleBinding{}
+	for _, binding := range desiredBindings {
+		desiredByPrincipal[binding.Principal] = append(
+			desiredByPrincipal[binding.Principal],
+			binding,
+		)
+	}
+	currentByPrincipal := map[string][]rbac.RoleBinding{}
+	for _, binding := range currentBindings {
+		currentByPrincipal[binding.Principal] = append(
+			currentByPrincipal[binding.Principal],
+			binding,
+		)
+	}
+
+	diffs := []RoleBindingDiff{}
+
+	for _, principal := range roleBindingConfig.PrincipalNames() {
+		diff := RoleBindingDiff{
+			Principal: principal,
+			MissingBindings: subtractRoleBindings(
+				desiredByPrincipal[principal],
+				currentByPrincipal[principal],
+			),
+			ExtraBindings: subtractRoleBindings(
+				currentByPrincipal[principal],
+				desiredByPrincipal[principal],
+			),
+		}
+		if len(diff.MissingBindings) > 0 || len(diff.ExtraBindings) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+// FormatRoleBindingDiffs generates a table that summarizes the role bindings that applying a
+// role binding config would create and delete, grouped by principal.
+func FormatRoleBindingDiffs(diffs []RoleBindingDiff) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Change",
+			"Principal",
+			"Role",
+			"Resource Type",
+			"Resource Name",
+			"Pattern Type",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	createStr := "create"
+	deleteStr := "delete"
+	if util.ColorEnabled() {
+		createStr = color.New(color.FgGreen).Sprint(createStr)
+		deleteStr = color.New(color.FgRed).Sprint(deleteStr)
+	}
+
+	appendRow := func(changeStr string, binding rbac.RoleBinding) {
+		table.Append(
+			[]string{
+				changeStr,
+				binding.Principal,
+				binding.Role,
+				binding.ResourceType,
+				binding.Name,
+				binding.PatternType,
+			},
+		)
+	}
+
+	for _, diff := range diffs {
+		for _, binding := range diff.MissingBindings {
+			appendRow(createStr, binding)
+		}
+		for _, binding := range diff.ExtraBindings {
+			appendRow(deleteStr, binding)
+		}
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// subtractRoleBindings returns the bindings in a that aren't in b, preserving the order of a.
+func subtractRoleBindings(a []rbac.RoleBinding, b []rbac.RoleBinding) []rbac.RoleBinding {
+	inB := map[rbac.RoleBinding]struct{}{}
+	for _, binding := range b {
+		inB[binding] = struct{}{}
+	}
+
+	result := []rbac.RoleBinding{}
+	for _, binding := range a {
+		if _, ok := inB[binding]; !ok {
+			result = append(result, binding)
+		}
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleBindingDiffs(t *testing.T) {
+	roleBindingConfig := config.RoleBindingConfig{
+		Spec: config.RoleBindingConfigSpec{
+			Principals: []config.RoleBindingPrincipalConfig{
+				{
+					Principal: "User:bob",
+					Bindings: []config.RoleBindingEntryConfig{
+						{
+							Role: "Operator",
+						},
+					},
+				},
+				{
+					Principal: "User:alice",
+					Bindings: []config.RoleBindingEntryConfig{
+						{
+							Role:         "DeveloperRead",
+							ResourceType: "topic",
+							Name:         "orders",
+						},
+						{
+							Role:         "DeveloperWrite",
+							ResourceType: "topic",
+							Name:         "orders-",
+							PatternType:  "prefixed",
+						},
+					},
+				},
+				{
+					Principal: "User:carol",
+				},
+			},
+		},
+	}
+
+	currentBindings := []rbac.RoleBinding{
+		{
+			Principal:    "User:alice",
+			Role:         "DeveloperRead",
+			ResourceType: rbac.ResourceTypeTopic,
+			Name:         "orders",
+			PatternType:  rbac.PatternTypeLiteral,
+		},
+		{
+			Principal:    "User:alice",
+			Role:         "ClusterAdmin",
+			ResourceType: rbac.ResourceTypeCluster,
+		},
+		{
+			Principal:    "User:bob",
+			Role:         "Operator",
+			ResourceType: rbac.ResourceTypeCluster,
+		},
+		{
+			Principal:    "User:carol",
+			Role:         "DeveloperRead",
+			ResourceType: rbac.ResourceTypeGroup,
+			Name:         "carol",
+			PatternType:  rbac.PatternTypeLiteral,
+		},
+		{
+			// Principals that aren't in the config are ignored
+			Principal:    "User:dave",
+			Role:         "SystemAdmin",
+			ResourceType: rbac.ResourceTypeCluster,
+		},
+	}
+
+	diffs, err := RoleBindingDiffs(roleBindingConfig, currentBindings)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]RoleBindingDiff{
+			{
+				Principal: "User:alice",
+				MissingBindings: []rbac.RoleBinding{
+					{
+						Principal:    "User:alice",
+						Role:         "DeveloperWrite",
+						ResourceType: rbac.ResourceTypeTopic,
+						Name:         "orders-",
+						PatternType:  rbac.PatternTypePrefixed,
+					},
+				},
+				ExtraBindings: []rbac.RoleBinding{
+					{
+						Principal:    "User:alice",
+						Role:         "ClusterAdmin",
+						ResourceType: rbac.ResourceTypeCluster,
+					},
+				},
+			},
+			{
+				Principal:       "User:carol",
+				MissingBindings: []rbac.RoleBinding{},
+				ExtraBindings: []rbac.RoleBinding{
+					{
+						Principal:    "User:carol",
+						Role:         "DeveloperRead",
+						ResourceType: rbac.ResourceTypeGroup,
+						Name:         "carol",
+						PatternType:  rbac.PatternTypeLiteral,
+					},
+				},
+			},
+		},
+		diffs,
+	)
+
+	table := FormatRoleBindingDiffs(diffs)
+	assert.Contains(t, table, "DeveloperWrite")
+	assert.Contains(t, table, "ClusterAdmin")
+	assert.NotContains(t, table, "SystemAdmin")
+}
@@ -0,0 +1,136 @@
+package apply
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// ScramCredentialChange is a SCRAM credential in a user config that needs to be created or
+// updated in the cluster.
+type ScramCredentialChange struct {
+	User config.UserEntryConfig
+
+	// Desired are the desired details of the credential, with defaults applied.
+	Desired admin.ScramCredentialInfo
+
+	// Current is the current credential in the cluster, or nil if there isn't one.
+	Current *admin.ScramCredentialInfo
+}
+
+// ScramCredentialChanges compares the credentials in a user config against the current ones in
+// the cluster and returns the ones that need to be created or updated, in config order. Since
+// the current passwords can't be read back from the cluster, existing credentials are only
+// updated if their iterations don't match the config or if updatePasswords is set.
+func ScramCredentialChanges(
+	userConfig config.UserConfig,
+	currentCredentials []admin.ScramCredentialInfo,
+	updatePasswords bool,
+) []ScramCredentialChange {
+	currentByKey := map[admin.ScramCredentialInfo]admin.ScramCredentialInfo{}
+	for _, credential := range currentCredentials {
+		key := admin.ScramCredentialInfo{
+			User:      credential.User,
+			Mechanism: credential.Mechanism,
+		}
+		currentByKey[key] = credential
+	}
+
+	changes := []ScramCredentialChange{}
+
+	for u, desired := range userConfig.Credentials() {
+		key := admin.ScramCredentialInfo{
+			User:      desired.User,
+			Mechanism: desired.Mechanism,
+		}
+
+		change := ScramCredentialChange{
+			User:    userConfig.Spec.Users[u],
+			Desired: desired,
+		}
+
+		current, ok := currentByKey[key]
+		if ok {
+			if current.Iterations == desired.Iterations && !updatePasswords {
+				continue
+			}
+			change.Current = &current
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// FormatScramCredentialChanges generates a table that summarizes the SCRAM credentials that
+// applying a user config would create or update. The passwords themselves are never included.
+func FormatScramCredentialChanges(changes []ScramCredentialChange) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Change",
+			"User",
+			"Mechanism",
+			"Iterations (Curr)",
+			"Iterations (New)",
+			"Password Source",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	createStr := "create"
+	updateStr := "update"
+	if util.ColorEnabled() {
+		createStr = color.New(color.FgGreen).Sprint(createStr)
+		updateStr = color.New(color.FgYellow).Sprint(updateStr)
+	}
+
+	for _, change := range changes {
+		changeStr := createStr
+		var currentStr string
+		if change.Current != nil {
+			changeStr = updateStr
+			currentStr = strconv.Itoa(change.Current.Iterations)
+		}
+
+		table.Append(
+			[]string{
+				changeStr,
+				change.Desired.User,
+				change.Desired.Mechanism,
+				currentStr,
+				strconv.Itoa(change.Desired.Iterations),
+				change.User.Password.String(),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
@@ -0,0 +1,94 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScramCredentialChanges(t *testing.T) {
+	userConfig := config.UserConfig{
+		Spec: config.UserConfigSpec{
+			Users: []config.UserEntryConfig{
+				{
+					Name:      "alice",
+					Mechanism: "SCRAM-SHA-512",
+					Password: config.SecretRef{
+						FromEnv: "ALICE_PASSWORD",
+					},
+				},
+				{
+					Name:       "bob",
+					Mechanism:  "SCRAM-SHA-256",
+					Iterations: 8192,
+					Password: config.SecretRef{
+						FromEnv: "BOB_PASSWORD",
+					},
+				},
+				{
+					Name:      "carol",
+					Mechanism: "SCRAM-SHA-512",
+					Password: config.SecretRef{
+						FromEnv: "CAROL_PASSWORD",
+					},
+				},
+			},
+		},
+	}
+
+	bobCurrent := admin.ScramCredentialInfo{
+		User:       "bob",
+		Mechanism:  "SCRAM-SHA-256",
+		Iterations: 4096,
+	}
+	carolCurrent := admin.ScramCredentialInfo{
+		User:       "carol",
+		Mechanism:  "SCRAM-SHA-512",
+		Iterations: 4096,
+	}
+	currentCredentials := []admin.ScramCredentialInfo{
+		bobCurrent,
+		carolCurrent,
+		{
+			User:       "alice",
+			Mechanism:  "SCRAM-SHA-256",
+			Iterations: 4096,
+		},
+		{
+			User:       "dave",
+			Mechanism:  "SCRAM-SHA-512",
+			Iterations: 4096,
+		},
+	}
+
+	// Carol's credential already matches, and dave isn't in the config
+	assert.Equal(
+		t,
+		[]ScramCredentialChange{
+			{
+				User: userConfig.Spec.Users[0],
+				Desired: admin.ScramCredentialInfo{
+					User:       "alice",
+					Mechanism:  "SCRAM-SHA-512",
+					Iterations: 4096,
+				},
+			},
+			{
+				User: userConfig.Spec.Users[1],
+				Desired: admin.ScramCredentialInfo{
+					User:       "bob",
+					Mechanism:  "SCRAM-SHA-256",
+					Iterations: 8192,
+				},
+				Current: &bobCurrent,
+			},
+		},
+		ScramCredentialChanges(userConfig, currentCredentials, false),
+	)
+
+	changes := ScramCredentialChanges(userConfig, currentCredentials, true)
+	assert.Equal(t, 3, len(changes))
+	assert.Equal(t, &carolCurrent, changes[2].Current)
+}
@@ -0,0 +1,229 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultLogFile is the name of the file, in the user's home directory, that the audit log is
+// written to by default.
+const DefaultLogFile = ".topicctl_audit.log"
+
+// DefaultLogPath returns the path of DefaultLogFile in the user's home directory. If the home
+// directory can't be determined, the file is put in the current directory instead.
+func DefaultLogPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return DefaultLogFile
+	}
+	return filepath.Join(homeDir, DefaultLogFile)
+}
+
+// Operation is a string type that identifies the kind of mutation in an audit log entry.
+type Operation string
+
+// The operations that are recorded in the audit log.
+const (
+	OperationCreateTopic        Operation = "create-topic"
+	OperationDeleteTopic        Operation = "delete-topic"
+	OperationUpdateTopicConfig  Operation = "update-topic-config"
+	OperationUpdateBrokerConfig Operation = "update-broker-config"
+	OperationAssignPartitions   Operation = "assign-partitions"
+	OperationAddPartitions      Operation = "add-partitions"
+	OperationCancelReassignment Operation = "cancel-reassignment"
+	OperationElectLeaders       Operation = "elect-leaders"
+	OperationCreateACLs         Operation = "create-acls"
+	OperationDeleteACLs         Operation = "delete-acls"
+	OperationUpdateQuotas       Operation = "update-quotas"
+	OperationUpsertCredentials  Operation = "upsert-scram-credentials"
+	OperationCreateRoleBinding  Operation = "create-role-binding"
+	OperationDeleteRoleBinding  Operation = "delete-role-binding"
+	OperationResetOffsets       Operation = "reset-offsets"
+	OperationDeleteGroup        Operation = "delete-group"
+	OperationDeleteGroupOffsets Operation = "delete-group-offsets"
+	OperationCopyOffsets        Operation = "copy-offsets"
+	OperationRestoreOffsets     Operation = "restore-offsets"
+
+	OperationCruiseControlRebalance     Operation = "cruise-control-rebalance"
+	OperationCruiseControlRemoveBrokers Operation = "cruise-control-remove-brokers"
+)
+
+// AllOperations are all of the operations that are recorded in the audit log.
+var AllOperations = []Operation{
+	OperationCreateTopic,
+	OperationDeleteTopic,
+	OperationUpdateTopicConfig,
+	OperationUpdateBrokerConfig,
+	OperationAssignPartitions,
+	OperationAddPartitions,
+	OperationCancelReassignment,
+	OperationElectLeaders,
+	OperationCreateACLs,
+	OperationDeleteACLs,
+	OperationUpdateQuotas,
+	OperationUpsertCredentials,
+	OperationCreateRoleBinding,
+	OperationDeleteRoleBinding,
+	OperationResetOffsets,
+	OperationDeleteGroup,
+	OperationDeleteGroupOffsets,
+	OperationCopyOffsets,
+	OperationRestoreOffsets,
+	OperationCruiseControlRebalance,
+	OperationCruiseControlRemoveBrokers,
+}
+
+// Entry is a single mutation in the audit log.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`
+	Operation Operation `json:"operation"`
+	Cluster   string    `json:"cluster"`
+
+	// Topic is the topic that was changed, if the operation applies to a single topic.
+	Topic string `json:"topic,omitempty"`
+
+	// Details identifies the other resources that were changed, e.g. a broker or consumer group.
+	Details string `json:"details,omitempty"`
+
+	// Before and After contain the values that were changed, keyed by config name, partition, or
+	// similar. Before is empty for operations that only add things.
+	Before map[string]string `json:"before,omitempty"`
+	After  map[string]string `json:"after,omitempty"`
+
+	// Error is set if the mutation failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Filter restricts the entries returned by ReadEntries. Unset fields match all entries.
+type Filter struct {
+	Cluster   string
+	Topic     string
+	Operation Operation
+	Since     time.Time
+
+	// Limit is the maximum number of entries to return; if the filter matches more, the most
+	// recent ones are returned.
+	Limit int
+}
+
+// Matches returns whether the argument entry matches the filter, ignoring the limit.
+func (f Filter) Matches(entry Entry) bool {
+	return (f.Cluster == "" || entry.Cluster == f.Cluster) &&
+		(f.Topic == "" || entry.Topic == f.Topic) &&
+		(f.Operation == "" || entry.Operation == f.Operation) &&
+		(f.Since.IsZero() || !entry.Time.Before(f.Since))
+}
+
+// Log is an append-only audit log file in which each line is a JSON-encoded Entry.
+type Log struct {
+	path string
+	user string
+
+	mutex sync.Mutex
+}
+
+// NewLog returns a Log that writes to the argument path.
+func NewLog(path string) *Log {
+	return &Log{
+		path: path,
+		user: currentUser(),
+	}
+}
+
+// Path returns the path of the log file.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record appends the argument entry to the log. The time and user are filled in if they're not
+// set.
+func (l *Log) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	if entry.User == "" {
+		entry.User = l.user
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// ReadEntries returns the entries in the argument audit log file that match the argument
+// filter, in the order that they were recorded. A missing file is treated as an empty log.
+func ReadEntries(path string, filter Filter) ([]Entry, error) {
+	entries := []Entry{}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	lineNum := 0
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNum++
+
+			var entry Entry
+			if jsonErr := json.Unmarshal(line, &entry); jsonErr != nil {
+				return nil, fmt.Errorf(
+					"Could not parse line %d of audit log %s: %+v",
+					lineNum,
+					path,
+					jsonErr,
+				)
+			}
+			if filter.Matches(entry) {
+				entries = append(entries, entry)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+
+	return entries, nil
+}
+
+func currentUser() string {
+	if currUser, err := user.Current(); err == nil && currUser.Username != "" {
+		return currUser.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog := NewLog(path)
+
+	entries, err := ReadEntries(path, Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	baseTime := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(
+		t,
+		auditLog.Record(
+			Entry{
+				Time:      baseTime,
+				Operation: OperationCreateTopic,
+				Cluster:   "cluster1",
+				Topic:     "topic1",
+				After:     map[string]string{"partitions": "3"},
+			},
+		),
+	)
+	require.NoError(
+		t,
+		auditLog.Record(
+			Entry{
+				Time:      baseTime.Add(time.Hour),
+				User:      "other-user",
+				Operation: OperationUpdateTopicConfig,
+				Cluster:   "cluster1",
+				Topic:     "topic2",
+				Before:    map[string]string{"retention.ms": "100"},
+				After:     map[string]string{"retention.ms": "200"},
+			},
+		),
+	)
+	require.NoError(
+		t,
+		auditLog.Record(
+			Entry{
+				Time:      baseTime.Add(2 * time.Hour),
+				Operation: OperationUpdateTopicConfig,
+				Cluster:   "cluster2",
+				Topic:     "topic1",
+				Error:     "request failed",
+			},
+		),
+	)
+
+	entries, err = ReadEntries(path, Filter{})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(entries))
+	assert.Equal(t, auditLog.user, entries[0].User)
+	assert.Equal(t, "other-user", entries[1].User)
+	assert.Equal(t, map[string]string{"retention.ms": "100"}, entries[1].Before)
+	assert.Equal(t, map[string]string{"retention.ms": "200"}, entries[1].After)
+	assert.Equal(t, "request failed", entries[2].Error)
+
+	type testCase struct {
+		description    string
+		filter         Filter
+		expectedTopics []string
+	}
+
+	testCases := []testCase{
+		{
+			description:    "cluster",
+			filter:         Filter{Cluster: "cluster1"},
+			expectedTopics: []string{"topic1", "topic2"},
+		},
+		{
+			description:    "topic",
+			filter:         Filter{Topic: "topic1"},
+			expectedTopics: []string{"topic1", "topic1"},
+		},
+		{
+			description:    "operation",
+			filter:         Filter{Operation: OperationCreateTopic},
+			expectedTopics: []string{"topic1"},
+		},
+		{
+			description:    "since",
+			filter:         Filter{Since: baseTime.Add(time.Hour)},
+			expectedTopics: []string{"topic2", "topic1"},
+		},
+		{
+			description:    "limit",
+			filter:         Filter{Limit: 1},
+			expectedTopics: []string{"topic1"},
+		},
+		{
+			description:    "no matches",
+			filter:         Filter{Cluster: "cluster1", Topic: "topic3"},
+			expectedTopics: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		entries, err := ReadEntries(path, testCase.filter)
+		require.NoError(t, err, testCase.description)
+
+		topics := []string{}
+		for _, entry := range entries {
+			topics = append(topics, entry.Topic)
+		}
+		assert.Equal(t, testCase.expectedTopics, topics, testCase.description)
+	}
+
+	limited, err := ReadEntries(path, Filter{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "cluster2", limited[0].Cluster)
+}
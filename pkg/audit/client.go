@@ -0,0 +1,454 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	log "github.com/sirupsen/logrus"
+)
+
+// Client is an admin.Client that records each of the mutations made through the client that it
+// wraps in an audit log. The current values are fetched before each mutation so that they can
+// be recorded alongside the new ones. Read-only calls are passed through as-is.
+type Client struct {
+	admin.Client
+
+	auditLog *Log
+	cluster  string
+}
+
+var _ admin.Client = (*Client)(nil)
+
+// NewClient returns a Client that wraps the argument admin client and records its mutations in
+// the argument log under the argument cluster name.
+func NewClient(client admin.Client, auditLog *Log, cluster string) *Client {
+	return &Client{
+		Client:   client,
+		auditLog: auditLog,
+		cluster:  cluster,
+	}
+}
+
+// UpdateTopicConfig updates the configuration for the argument topic.
+func (c *Client) UpdateTopicConfig(
+	ctx context.Context,
+	name string,
+	configEntries []kafka.ConfigEntry,
+	overwrite bool,
+) ([]string, error) {
+	var currConfig map[string]string
+	if topicInfo, err := c.Client.GetTopic(ctx, name, false); err == nil {
+		currConfig = topicInfo.Config
+	}
+
+	updatedKeys, err := c.Client.UpdateTopicConfig(ctx, name, configEntries, overwrite)
+	c.Record(
+		Entry{
+			Operation: OperationUpdateTopicConfig,
+			Topic:     name,
+			Before:    configValues(currConfig, configEntries),
+			After:     configEntryValues(configEntries),
+		},
+		err,
+	)
+	return updatedKeys, err
+}
+
+// UpdateBrokerConfig updates the configuration for the argument broker.
+func (c *Client) UpdateBrokerConfig(
+	ctx context.Context,
+	id int,
+	configEntries []kafka.ConfigEntry,
+	overwrite bool,
+) ([]string, error) {
+	var currConfig map[string]string
+	if brokers, err := c.Client.GetBrokers(ctx, []int{id}); err == nil && len(brokers) == 1 {
+		currConfig = brokers[0].Config
+	}
+
+	updatedKeys, err := c.Client.UpdateBrokerConfig(ctx, id, configEntries, overwrite)
+	c.Record(
+		Entry{
+			Operation: OperationUpdateBrokerConfig,
+			Details:   fmt.Sprintf("broker %d", id),
+			Before:    configValues(currConfig, configEntries),
+			After:     configEntryValues(configEntries),
+		},
+		err,
+	)
+	return updatedKeys, err
+}
+
+// CreateTopic creates a topic in the cluster.
+func (c *Client) CreateTopic(ctx context.Context, config kafka.TopicConfig) error {
+	after := configEntryValues(config.ConfigEntries)
+	after["partitions"] = strconv.Itoa(config.NumPartitions)
+	after["replicationFactor"] = strconv.Itoa(config.ReplicationFactor)
+
+	err := c.Client.CreateTopic(ctx, config)
+	c.Record(
+		Entry{
+			Operation: OperationCreateTopic,
+			Topic:     config.Topic,
+			After:     after,
+		},
+		err,
+	)
+	return err
+}
+
+// DeleteTopic deletes a topic from the cluster.
+func (c *Client) DeleteTopic(ctx context.Context, name string) error {
+	var before map[string]string
+	if topicInfo, err := c.Client.GetTopic(ctx, name, false); err == nil {
+		before = map[string]string{
+			"partitions":        strconv.Itoa(len(topicInfo.Partitions)),
+			"replicationFactor": strconv.Itoa(topicInfo.MaxReplication()),
+		}
+	}
+
+	err := c.Client.DeleteTopic(ctx, name)
+	c.Record(
+		Entry{
+			Operation: OperationDeleteTopic,
+			Topic:     name,
+			Before:    before,
+		},
+		err,
+	)
+	return err
+}
+
+// AssignPartitions sets the replica broker IDs for one or more partitions in a topic.
+func (c *Client) AssignPartitions(
+	ctx context.Context,
+	topic string,
+	assignments []admin.PartitionAssignment,
+) error {
+	var before map[string]string
+	if topicInfo, err := c.Client.GetTopic(ctx, topic, false); err == nil {
+		before = assignmentValues(topicInfo.ToAssignments(), assignments)
+	}
+
+	err := c.Client.AssignPartitions(ctx, topic, assignments)
+	c.Record(
+		Entry{
+			Operation: OperationAssignPartitions,
+			Topic:     topic,
+			Before:    before,
+			After:     assignmentValues(assignments, nil),
+		},
+		err,
+	)
+	return err
+}
+
+// AddPartitions extends a topic by adding one or more new partitions to it.
+func (c *Client) AddPartitions(
+	ctx context.Context,
+	topic string,
+	newAssignments []admin.PartitionAssignment,
+) error {
+	err := c.Client.AddPartitions(ctx, topic, newAssignments)
+	c.Record(
+		Entry{
+			Operation: OperationAddPartitions,
+			Topic:     topic,
+			After:     assignmentValues(newAssignments, nil),
+		},
+		err,
+	)
+	return err
+}
+
+// CancelPartitionReassignments cancels the argument in-progress partition reassignments.
+func (c *Client) CancelPartitionReassignments(
+	ctx context.Context,
+	reassignments []admin.PartitionReassignment,
+) error {
+	err := c.Client.CancelPartitionReassignments(ctx, reassignments)
+
+	// Record a separate entry for each topic so that they can be filtered by topic
+	topicReassignments := map[string][]admin.PartitionReassignment{}
+	for _, reassignment := range reassignments {
+		topicReassignments[reassignment.Topic] = append(
+			topicReassignments[reassignment.Topic],
+			reassignment,
+		)
+	}
+	topics := []string{}
+	for topic := range topicReassignments {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		before := map[string]string{}
+		for _, reassignment := range topicReassignments[topic] {
+			before[strconv.Itoa(reassignment.Partition)] = fmt.Sprintf(
+				"adding %s, removing %s",
+				intsStr(reassignment.AddingReplicas),
+				intsStr(reassignment.RemovingReplicas),
+			)
+		}
+
+		c.Record(
+			Entry{
+				Operation: OperationCancelReassignment,
+				Topic:     topic,
+				Before:    before,
+			},
+			err,
+		)
+	}
+	return err
+}
+
+// RunLeaderElection triggers a leader election for one or more partitions in a topic.
+func (c *Client) RunLeaderElection(
+	ctx context.Context,
+	topic string,
+	partitions []int,
+) error {
+	err := c.Client.RunLeaderElection(ctx, topic, partitions)
+	c.Record(
+		Entry{
+			Operation: OperationElectLeaders,
+			Topic:     topic,
+			Details: fmt.Sprintf(
+				"%s election of partitions %s",
+				admin.LeaderElectionTypePreferred,
+				intsStr(partitions),
+			),
+		},
+		err,
+	)
+	return err
+}
+
+// ElectLeaders triggers leader elections of the argument type for one or more partitions.
+func (c *Client) ElectLeaders(
+	ctx context.Context,
+	electionType admin.LeaderElectionType,
+	partitions []admin.PartitionInfo,
+) error {
+	err := c.Client.ElectLeaders(ctx, electionType, partitions)
+
+	topicPartitions := map[string][]int{}
+	for _, partition := range partitions {
+		topicPartitions[partition.Topic] = append(topicPartitions[partition.Topic], partition.ID)
+	}
+	topics := []string{}
+	for topic := range topicPartitions {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		c.Record(
+			Entry{
+				Operation: OperationElectLeaders,
+				Topic:     topic,
+				Details: fmt.Sprintf(
+					"%s election of partitions %s",
+					electionType,
+					intsStr(topicPartitions[topic]),
+				),
+			},
+			err,
+		)
+	}
+	return err
+}
+
+// CreateACLs creates the argument ACLs in the cluster.
+func (c *Client) CreateACLs(ctx context.Context, acls []admin.ACLInfo) error {
+	err := c.Client.CreateACLs(ctx, acls)
+	c.Record(
+		Entry{
+			Operation: OperationCreateACLs,
+			After:     aclValues(acls),
+		},
+		err,
+	)
+	return err
+}
+
+// DeleteACLs deletes the ACLs in the cluster that match the argument filter.
+func (c *Client) DeleteACLs(
+	ctx context.Context,
+	filter kafka.ACLFilter,
+) ([]admin.ACLInfo, error) {
+	deleted, err := c.Client.DeleteACLs(ctx, filter)
+	c.Record(
+		Entry{
+			Operation: OperationDeleteACLs,
+			Before:    aclValues(deleted),
+		},
+		err,
+	)
+	return deleted, err
+}
+
+// UpdateQuotas applies the argument quota updates to the cluster.
+func (c *Client) UpdateQuotas(ctx context.Context, updates []admin.QuotaUpdate) error {
+	currValues := map[string]map[string]float64{}
+	if quotas, err := c.Client.GetQuotas(ctx); err == nil {
+		for _, quota := range quotas {
+			currValues[quota.Entity.String()] = quota.Values
+		}
+	}
+
+	err := c.Client.UpdateQuotas(ctx, updates)
+	for _, update := range updates {
+		entity := update.Entity.String()
+		before := map[string]string{}
+		after := map[string]string{}
+
+		for key, value := range update.Values {
+			if currValue, ok := currValues[entity][key]; ok {
+				before[key] = formatQuota(currValue)
+			}
+			after[key] = formatQuota(value)
+		}
+		for _, key := range update.RemovedKeys {
+			if currValue, ok := currValues[entity][key]; ok {
+				before[key] = formatQuota(currValue)
+			}
+		}
+
+		c.Record(
+			Entry{
+				Operation: OperationUpdateQuotas,
+				Details:   entity,
+				Before:    before,
+				After:     after,
+			},
+			err,
+		)
+	}
+	return err
+}
+
+// UpsertScramCredentials creates or replaces the argument SCRAM credentials. Only the
+// mechanisms and iteration counts are recorded; the passwords are never written to the log.
+func (c *Client) UpsertScramCredentials(
+	ctx context.Context,
+	upserts []admin.ScramCredentialUpsert,
+) error {
+	users := []string{}
+	for _, upsert := range upserts {
+		users = append(users, upsert.User)
+	}
+
+	currIterations := map[string]map[string]int{}
+	if credentials, err := c.Client.GetScramCredentials(ctx, users); err == nil {
+		for _, credential := range credentials {
+			if _, ok := currIterations[credential.User]; !ok {
+				currIterations[credential.User] = map[string]int{}
+			}
+			currIterations[credential.User][credential.Mechanism] = credential.Iterations
+		}
+	}
+
+	err := c.Client.UpsertScramCredentials(ctx, upserts)
+	for _, upsert := range upserts {
+		before := map[string]string{}
+		if iterations, ok := currIterations[upsert.User][upsert.Mechanism]; ok {
+			before[upsert.Mechanism] = fmt.Sprintf("iterations=%d", iterations)
+		}
+
+		c.Record(
+			Entry{
+				Operation: OperationUpsertCredentials,
+				Details:   fmt.Sprintf("user=%s", upsert.User),
+				Before:    before,
+				After: map[string]string{
+					upsert.Mechanism: fmt.Sprintf("iterations=%d", upsert.Iterations),
+				},
+			},
+			err,
+		)
+	}
+	return err
+}
+
+// Record adds the cluster and argument error to the argument entry and appends it to the audit
+// log. Errors writing to the log are logged instead of returned since the mutation has already
+// happened at this point. This is also used for mutations that are made with the connector of
+// the wrapped client, e.g. consumer group offset commits, instead of through the client itself.
+func (c *Client) Record(entry Entry, err error) {
+	entry.Cluster = c.cluster
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if recordErr := c.auditLog.Record(entry); recordErr != nil {
+		log.Warnf("Could not write to audit log %s: %+v", c.auditLog.Path(), recordErr)
+	}
+}
+
+// configValues returns the values in the argument config for the keys in the argument entries.
+func configValues(config map[string]string, configEntries []kafka.ConfigEntry) map[string]string {
+	values := map[string]string{}
+	for _, configEntry := range configEntries {
+		if value, ok := config[configEntry.ConfigName]; ok {
+			values[configEntry.ConfigName] = value
+		}
+	}
+	return values
+}
+
+func configEntryValues(configEntries []kafka.ConfigEntry) map[string]string {
+	values := map[string]string{}
+	for _, configEntry := range configEntries {
+		values[configEntry.ConfigName] = configEntry.ConfigValue
+	}
+	return values
+}
+
+// assignmentValues returns the replicas of each of the argument assignments, keyed by partition.
+// If subset is set, only the partitions in it are included.
+func assignmentValues(
+	assignments []admin.PartitionAssignment,
+	subset []admin.PartitionAssignment,
+) map[string]string {
+	subsetIDs := map[int]struct{}{}
+	for _, assignment := range subset {
+		subsetIDs[assignment.ID] = struct{}{}
+	}
+
+	values := map[string]string{}
+	for _, assignment := range assignments {
+		if _, ok := subsetIDs[assignment.ID]; !ok && len(subset) > 0 {
+			continue
+		}
+		values[strconv.Itoa(assignment.ID)] = intsStr(assignment.Replicas)
+	}
+	return values
+}
+
+func aclValues(acls []admin.ACLInfo) map[string]string {
+	values := map[string]string{}
+	for a, acl := range acls {
+		values[strconv.Itoa(a)] = acl.String()
+	}
+	return values
+}
+
+func formatQuota(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func intsStr(values []int) string {
+	strs := []string{}
+	for _, value := range values {
+		strs = append(strs, strconv.Itoa(value))
+	}
+	return strings.Join(strs, ",")
+}
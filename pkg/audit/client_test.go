@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	admin.Client
+
+	topic     admin.TopicInfo
+	updateErr error
+}
+
+func (f *fakeClient) GetTopic(
+	ctx context.Context,
+	name string,
+	detailed bool,
+) (admin.TopicInfo, error) {
+	return f.topic, nil
+}
+
+func (f *fakeClient) UpdateTopicConfig(
+	ctx context.Context,
+	name string,
+	configEntries []kafka.ConfigEntry,
+	overwrite bool,
+) ([]string, error) {
+	return nil, f.updateErr
+}
+
+func (f *fakeClient) AssignPartitions(
+	ctx context.Context,
+	topic string,
+	assignments []admin.PartitionAssignment,
+) error {
+	return nil
+}
+
+func TestClientRecordsMutations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	fake := &fakeClient{
+		topic: admin.TopicInfo{
+			Name: "test-topic",
+			Config: map[string]string{
+				"retention.ms":   "100",
+				"cleanup.policy": "delete",
+			},
+			Partitions: []admin.PartitionInfo{
+				{Topic: "test-topic", ID: 0, Replicas: []int{1, 2}},
+				{Topic: "test-topic", ID: 1, Replicas: []int{2, 3}},
+			},
+		},
+	}
+	client := NewClient(fake, NewLog(path), "test-cluster")
+
+	_, err := client.UpdateTopicConfig(
+		ctx,
+		"test-topic",
+		[]kafka.ConfigEntry{
+			{ConfigName: "retention.ms", ConfigValue: "200"},
+			{ConfigName: "segment.bytes", ConfigValue: "1000"},
+		},
+		true,
+	)
+	require.NoError(t, err)
+
+	err = client.AssignPartitions(
+		ctx,
+		"test-topic",
+		[]admin.PartitionAssignment{{ID: 1, Replicas: []int{3, 4}}},
+	)
+	require.NoError(t, err)
+
+	fake.updateErr = errors.New("update failed")
+	_, err = client.UpdateTopicConfig(
+		ctx,
+		"test-topic",
+		[]kafka.ConfigEntry{{ConfigName: "retention.ms", ConfigValue: "300"}},
+		true,
+	)
+	require.Error(t, err)
+
+	client.Record(
+		Entry{
+			Operation: OperationResetOffsets,
+			Topic:     "test-topic",
+			Details:   "group test-group",
+			After:     map[string]string{"0": "10"},
+		},
+		nil,
+	)
+
+	entries, err := ReadEntries(path, Filter{})
+	require.NoError(t, err)
+	require.Equal(t, 4, len(entries))
+
+	assert.Equal(t, OperationUpdateTopicConfig, entries[0].Operation)
+	assert.Equal(t, "test-cluster", entries[0].Cluster)
+	assert.Equal(t, "test-topic", entries[0].Topic)
+	assert.Equal(t, map[string]string{"retention.ms": "100"}, entries[0].Before)
+	assert.Equal(
+		t,
+		map[string]string{"retention.ms": "200", "segment.bytes": "1000"},
+		entries[0].After,
+	)
+	assert.Equal(t, "", entries[0].Error)
+
+	assert.Equal(t, OperationAssignPartitions, entries[1].Operation)
+	assert.Equal(t, map[string]string{"1": "2,3"}, entries[1].Before)
+	assert.Equal(t, map[string]string{"1": "3,4"}, entries[1].After)
+
+	assert.Equal(t, "update failed", entries[2].Error)
+
+	assert.Equal(t, OperationResetOffsets, entries[3].Operation)
+	assert.Equal(t, "test-cluster", entries[3].Cluster)
+	assert.Equal(t, map[string]string{"0": "10"}, entries[3].After)
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+)
+
+// FormatEntries creates a pretty table that lists the argument audit log entries.
+func FormatEntries(entries []Entry) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Time",
+			"User",
+			"Operation",
+			"Cluster",
+			"Target",
+			"Changes",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, entry := range entries {
+		targets := []string{}
+		if entry.Topic != "" {
+			targets = append(targets, entry.Topic)
+		}
+		if entry.Details != "" {
+			targets = append(targets, entry.Details)
+		}
+
+		changes := changeLines(entry)
+		if entry.Error != "" {
+			changes = append(changes, color.New(color.FgRed).Sprintf("Error: %s", entry.Error))
+		}
+
+		table.Append(
+			[]string{
+				entry.Time.Local().Format(time.RFC3339),
+				entry.User,
+				string(entry.Operation),
+				entry.Cluster,
+				strings.Join(targets, "\n"),
+				strings.Join(changes, "\n"),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// changeLines returns a line for each of the keys in the before and after values of the
+// argument entry.
+func changeLines(entry Entry) []string {
+	keysMap := map[string]struct{}{}
+	for key := range entry.Before {
+		keysMap[key] = struct{}{}
+	}
+	for key := range entry.After {
+		keysMap[key] = struct{}{}
+	}
+
+	keys := []string{}
+	for key := range keysMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := []string{}
+	for _, key := range keys {
+		before, hasBefore := entry.Before[key]
+		after, hasAfter := entry.After[key]
+
+		switch {
+		case hasBefore && hasAfter:
+			lines = append(lines, fmt.Sprintf("%s: %s -> %s", key, before, after))
+		case hasBefore:
+			lines = append(lines, fmt.Sprintf("%s: %s -> (removed)", key, before))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %s", key, after))
+		}
+	}
+
+	return lines
+}
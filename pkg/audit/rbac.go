@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/topicctl/pkg/rbac"
+	log "github.com/sirupsen/logrus"
+)
+
+// RoleBindingClient is an rbac.Client that records the role bindings created and deleted through
+// the client that it wraps in an audit log. Read-only calls are passed through as-is.
+type RoleBindingClient struct {
+	rbac.Client
+
+	auditLog *Log
+	cluster  string
+}
+
+var _ rbac.Client = (*RoleBindingClient)(nil)
+
+// NewRoleBindingClient returns a RoleBindingClient that wraps the argument client and records
+// its mutations in the argument log under the argument cluster name.
+func NewRoleBindingClient(client rbac.Client, auditLog *Log, cluster string) *RoleBindingClient {
+	return &RoleBindingClient{
+		Client:   client,
+		auditLog: auditLog,
+		cluster:  cluster,
+	}
+}
+
+// CreateRoleBinding creates the argument binding.
+func (c *RoleBindingClient) CreateRoleBinding(ctx context.Context, binding rbac.RoleBinding) error {
+	err := c.Client.CreateRoleBinding(ctx, binding)
+	c.record(
+		Entry{
+			Operation: OperationCreateRoleBinding,
+			Details:   fmt.Sprintf("principal %s", binding.Principal),
+			After:     roleBindingValues(binding),
+		},
+		err,
+	)
+	return err
+}
+
+// DeleteRoleBinding deletes the argument binding.
+func (c *RoleBindingClient) DeleteRoleBinding(ctx context.Context, binding rbac.RoleBinding) error {
+	err := c.Client.DeleteRoleBinding(ctx, binding)
+	c.record(
+		Entry{
+			Operation: OperationDeleteRoleBinding,
+			Details:   fmt.Sprintf("principal %s", binding.Principal),
+			Before:    roleBindingValues(binding),
+		},
+		err,
+	)
+	return err
+}
+
+func (c *RoleBindingClient) record(entry Entry, err error) {
+	entry.Cluster = c.cluster
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if recordErr := c.auditLog.Record(entry); recordErr != nil {
+		log.Warnf("Could not write to audit log %s: %+v", c.auditLog.Path(), recordErr)
+	}
+}
+
+func roleBindingValues(binding rbac.RoleBinding) map[string]string {
+	return map[string]string{
+		"role":     binding.Role,
+		"resource": binding.Resource(),
+	}
+}
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRoleBindingClient struct {
+	rbac.Client
+
+	deleteErr error
+}
+
+func (f *fakeRoleBindingClient) CreateRoleBinding(
+	ctx context.Context,
+	binding rbac.RoleBinding,
+) error {
+	return nil
+}
+
+func (f *fakeRoleBindingClient) DeleteRoleBinding(
+	ctx context.Context,
+	binding rbac.RoleBinding,
+) error {
+	return f.deleteErr
+}
+
+func TestRoleBindingClientRecordsMutations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	fake := &fakeRoleBindingClient{deleteErr: errors.New("delete failed")}
+	client := NewRoleBindingClient(fake, NewLog(path), "test-cluster")
+
+	binding := rbac.RoleBinding{
+		Principal:    "User:payments-service",
+		Role:         "DeveloperRead",
+		ResourceType: "Topic",
+		Name:         "payments-",
+		PatternType:  "PREFIXED",
+	}
+	require.NoError(t, client.CreateRoleBinding(ctx, binding))
+	require.Error(t, client.DeleteRoleBinding(ctx, binding))
+
+	entries, err := ReadEntries(path, Filter{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(entries))
+
+	assert.Equal(t, OperationCreateRoleBinding, entries[0].Operation)
+	assert.Equal(t, "test-cluster", entries[0].Cluster)
+	assert.Equal(t, "principal User:payments-service", entries[0].Details)
+	assert.Equal(
+		t,
+		map[string]string{"role": "DeveloperRead", "resource": "Topic:PREFIXED:payments-"},
+		entries[0].After,
+	)
+	assert.Equal(t, "", entries[0].Error)
+
+	assert.Equal(t, OperationDeleteRoleBinding, entries[1].Operation)
+	assert.Equal(
+		t,
+		map[string]string{"role": "DeveloperRead", "resource": "Topic:PREFIXED:payments-"},
+		entries[1].Before,
+	)
+	assert.Equal(t, "delete failed", entries[1].Error)
+}
@@ -0,0 +1,235 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+)
+
+// CheckACLsConfig contains all of the context necessary to check a single ACL config.
+type CheckACLsConfig struct {
+	AdminClient   admin.Client
+	ClusterConfig config.ClusterConfig
+	ACLConfig     config.ACLConfig
+	ValidateOnly  bool
+}
+
+// ACLDrift stores the differences between the ACLs declared for a resource and the ones in the
+// cluster.
+type ACLDrift struct {
+	// Missing are the declared ACLs that aren't in the cluster.
+	Missing []admin.ACLInfo
+
+	// Extra are the ACLs in the cluster that aren't declared.
+	Extra []admin.ACLInfo
+
+	// Broad are the undeclared allow ACLs that apply to the resource, possibly via a wildcard or
+	// prefixed pattern, and grant all operations or apply to all principals or resource names.
+	// ACLs that are also in Extra aren't repeated here.
+	Broad []admin.ACLInfo
+}
+
+// GetACLDrift compares the argument desired ACLs against the managed ones in the cluster, i.e.
+// the ones that apply would reconcile against them, and all of the ones that apply to the
+// resource.
+func GetACLDrift(
+	desiredACLs []admin.ACLInfo,
+	managedACLs []admin.ACLInfo,
+	matchingACLs []admin.ACLInfo,
+) ACLDrift {
+	missing, extra := admin.ACLDiffs(desiredACLs, managedACLs)
+
+	excluded := map[admin.ACLInfo]struct{}{}
+	for _, acl := range desiredACLs {
+		excluded[acl] = struct{}{}
+	}
+	for _, acl := range extra {
+		excluded[acl] = struct{}{}
+	}
+
+	broad := []admin.ACLInfo{}
+	for _, acl := range admin.BroadACLs(matchingACLs) {
+		if _, ok := excluded[acl]; !ok {
+			broad = append(broad, acl)
+			excluded[acl] = struct{}{}
+		}
+	}
+
+	return ACLDrift{
+		Missing: missing,
+		Extra:   extra,
+		Broad:   broad,
+	}
+}
+
+// Empty returns whether there's no drift.
+func (d ACLDrift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Broad) == 0
+}
+
+// Merge returns the combination of this drift and the argument one.
+func (d ACLDrift) Merge(other ACLDrift) ACLDrift {
+	return ACLDrift{
+		Missing: append(append([]admin.ACLInfo{}, d.Missing...), other.Missing...),
+		Extra:   append(append([]admin.ACLInfo{}, d.Extra...), other.Extra...),
+		Broad:   append(append([]admin.ACLInfo{}, d.Broad...), other.Broad...),
+	}
+}
+
+// String returns a description of the drift that's suitable for a check result.
+func (d ACLDrift) String() string {
+	elements := []string{
+		fmt.Sprintf(
+			"%d missing, %d extra, and %d overly-broad ACL(s)",
+			len(d.Missing),
+			len(d.Extra),
+			len(d.Broad),
+		),
+	}
+
+	appendACLs := func(label string, acls []admin.ACLInfo) {
+		if len(acls) == 0 {
+			return
+		}
+		aclStrs := []string{}
+		for _, acl := range acls {
+			aclStrs = append(aclStrs, acl.String())
+		}
+		elements = append(elements, fmt.Sprintf("%s: %s", label, strings.Join(aclStrs, ", ")))
+	}
+
+	appendACLs("missing", d.Missing)
+	appendACLs("extra", d.Extra)
+	appendACLs("overly broad", d.Broad)
+
+	return strings.Join(elements, "; ")
+}
+
+// topicACLDrift gets the ACL drift for a topic whose config has an acls section. As in apply,
+// only the literal allow ACLs on the topic are compared against the config, but overly-broad
+// grants that come from prefixed or wildcard ACLs are also flagged.
+func topicACLDrift(
+	ctx context.Context,
+	adminClient admin.Client,
+	topicConfig config.TopicConfig,
+) (ACLDrift, error) {
+	desiredACLs, err := topicConfig.ACLs()
+	if err != nil {
+		return ACLDrift{}, err
+	}
+
+	managedACLs, err := adminClient.GetACLs(
+		ctx,
+		kafka.ACLFilter{
+			ResourceTypeFilter:        kafka.ResourceTypeTopic,
+			ResourceNameFilter:        topicConfig.Meta.Name,
+			ResourcePatternTypeFilter: kafka.PatternTypeLiteral,
+			Operation:                 kafka.ACLOperationTypeAny,
+			PermissionType:            kafka.ACLPermissionTypeAllow,
+		},
+	)
+	if err != nil {
+		return ACLDrift{}, err
+	}
+
+	matchingACLs, err := adminClient.GetACLs(
+		ctx,
+		kafka.ACLFilter{
+			ResourceTypeFilter:        kafka.ResourceTypeTopic,
+			ResourceNameFilter:        topicConfig.Meta.Name,
+			ResourcePatternTypeFilter: kafka.PatternTypeMatch,
+			Operation:                 kafka.ACLOperationTypeAny,
+			PermissionType:            kafka.ACLPermissionTypeAllow,
+		},
+	)
+	if err != nil {
+		return ACLDrift{}, err
+	}
+
+	return GetACLDrift(desiredACLs, managedACLs, matchingACLs), nil
+}
+
+// CheckACLs runs the checks for an ACL config and returns the results. As with CheckTopic, an
+// error is only returned for problems that aren't specific to the config.
+func CheckACLs(ctx context.Context, checkConfig CheckACLsConfig) (TopicCheckResults, error) {
+	results := TopicCheckResults{}
+	aclConfig := checkConfig.ACLConfig
+
+	// Check config
+	results.AppendResult(
+		TopicCheckResult{
+			Name: CheckNameConfigCorrect,
+		},
+	)
+	if err := aclConfig.ResolveACLs(checkConfig.ClusterConfig); err != nil {
+		results.UpdateLastResult(false, fmt.Sprintf("ACL error: %+v", err))
+		return results, nil
+	}
+	if err := aclConfig.Validate(); err != nil {
+		results.UpdateLastResult(false, fmt.Sprintf("config validation error: %+v", err))
+		return results, nil
+	}
+	results.UpdateLastResult(true, "")
+
+	// Check config/cluster consistency
+	results.AppendResult(
+		TopicCheckResult{
+			Name: CheckNameConfigsConsistent,
+		},
+	)
+	if err := config.CheckACLConsistency(aclConfig, checkConfig.ClusterConfig); err != nil {
+		results.UpdateLastResult(false, fmt.Sprintf("config consistency error: %+v", err))
+		return results, nil
+	}
+	results.UpdateLastResult(true, "")
+
+	if checkConfig.ValidateOnly {
+		return results, nil
+	}
+
+	// Check ACLs
+	resources, desiredACLs, err := aclConfig.Resources()
+	if err != nil {
+		return results, err
+	}
+
+	drift := ACLDrift{}
+
+	for _, resource := range resources {
+		managedACLs, err := checkConfig.AdminClient.GetACLs(ctx, resource.Filter())
+		if err != nil {
+			return results, err
+		}
+
+		matchingACLs := managedACLs
+		if resource.PatternType == kafka.PatternTypeLiteral {
+			matchFilter := resource.Filter()
+			matchFilter.ResourcePatternTypeFilter = kafka.PatternTypeMatch
+			matchFilter.PermissionType = kafka.ACLPermissionTypeAllow
+
+			matchingACLs, err = checkConfig.AdminClient.GetACLs(ctx, matchFilter)
+			if err != nil {
+				return results, err
+			}
+		}
+
+		drift = drift.Merge(GetACLDrift(desiredACLs[resource], managedACLs, matchingACLs))
+	}
+
+	results.AppendResult(
+		TopicCheckResult{
+			Name: CheckNameACLsCorrect,
+		},
+	)
+	if drift.Empty() {
+		results.UpdateLastResult(true, "")
+	} else {
+		results.UpdateLastResult(false, drift.String())
+	}
+
+	return results, nil
+}
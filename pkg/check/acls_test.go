@@ -0,0 +1,66 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetACLDrift(t *testing.T) {
+	readACL := admin.ACLInfo{
+		ResourceType:   kafka.ResourceTypeTopic,
+		ResourceName:   "test-topic",
+		PatternType:    kafka.PatternTypeLiteral,
+		Principal:      "User:alice",
+		Host:           "*",
+		Operation:      kafka.ACLOperationTypeRead,
+		PermissionType: kafka.ACLPermissionTypeAllow,
+	}
+	writeACL := readACL
+	writeACL.Operation = kafka.ACLOperationTypeWrite
+	allACL := readACL
+	allACL.Principal = "User:bob"
+	allACL.Operation = kafka.ACLOperationTypeAll
+	wildcardACL := readACL
+	wildcardACL.ResourceName = "*"
+
+	drift := GetACLDrift(
+		[]admin.ACLInfo{readACL, writeACL},
+		[]admin.ACLInfo{readACL, allACL},
+		[]admin.ACLInfo{readACL, allACL, wildcardACL},
+	)
+	assert.Equal(
+		t,
+		ACLDrift{
+			Missing: []admin.ACLInfo{writeACL},
+			Extra:   []admin.ACLInfo{allACL},
+			Broad:   []admin.ACLInfo{wildcardACL},
+		},
+		drift,
+	)
+	assert.False(t, drift.Empty())
+	assert.Equal(
+		t,
+		"1 missing, 1 extra, and 1 overly-broad ACL(s); "+
+			"missing: Allow Write on Topic test-topic (Literal) from host * for User:alice; "+
+			"extra: Allow All on Topic test-topic (Literal) from host * for User:bob; "+
+			"overly broad: Allow Read on Topic * (Literal) from host * for User:alice",
+		drift.String(),
+	)
+
+	// Declared ACLs are never considered overly broad
+	drift = GetACLDrift(
+		[]admin.ACLInfo{allACL},
+		[]admin.ACLInfo{allACL},
+		[]admin.ACLInfo{allACL},
+	)
+	assert.True(t, drift.Empty())
+
+	merged := GetACLDrift(nil, []admin.ACLInfo{readACL}, nil).Merge(
+		GetACLDrift([]admin.ACLInfo{writeACL}, nil, nil),
+	)
+	assert.Equal(t, []admin.ACLInfo{writeACL}, merged.Missing)
+	assert.Equal(t, []admin.ACLInfo{readACL}, merged.Extra)
+}
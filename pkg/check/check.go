@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply/assigners"
 	"github.com/segmentio/topicctl/pkg/config"
 	tconfig "github.com/segmentio/topicctl/pkg/config"
 )
@@ -18,6 +20,11 @@ type CheckConfig struct {
 	NumRacks      int
 	TopicConfig   config.TopicConfig
 	ValidateOnly  bool
+
+	// CheckAssignments is set if the checks should include whether the consumer groups with
+	// members subscribed to the topic have assignment setups that waste capacity or cause churn.
+	// This requires describing every group in the cluster.
+	CheckAssignments bool
 }
 
 // CheckTopic runs the topic check and returns a result. If there's a non-topic-specific error
@@ -35,6 +42,22 @@ func CheckTopic(ctx context.Context, config CheckConfig) (TopicCheckResults, err
 			Name: CheckNameConfigCorrect,
 		},
 	)
+	if err := config.TopicConfig.ResolveSettingsProfile(config.ClusterConfig); err != nil {
+		results.UpdateLastResult(
+			false,
+			fmt.Sprintf("settings profile error: %+v", err),
+		)
+		// Don't bother with remaining checks
+		return results, nil
+	}
+	if err := config.TopicConfig.ResolveACLs(config.ClusterConfig); err != nil {
+		results.UpdateLastResult(
+			false,
+			fmt.Sprintf("ACL error: %+v", err),
+		)
+		// Don't bother with remaining checks
+		return results, nil
+	}
 	if err := config.TopicConfig.Validate(config.NumRacks); err == nil {
 		results.UpdateLastResult(true, "")
 	} else {
@@ -63,6 +86,26 @@ func CheckTopic(ctx context.Context, config CheckConfig) (TopicCheckResults, err
 		return results, nil
 	}
 
+	// Warn about deprecated topics that are past their removal dates; these don't fail the check
+	if deprecation := config.TopicConfig.Meta.Deprecated; deprecation != nil {
+		results.AppendResult(
+			TopicCheckResult{
+				Name: CheckNameDeprecationNotExpired,
+			},
+		)
+		if deprecation.PastRemoveAfter(time.Now()) {
+			results.UpdateLastResultWarning(
+				fmt.Sprintf(
+					"topic is deprecated (%s) and is past its removal date (%s)",
+					deprecation.Reason,
+					deprecation.RemoveAfter,
+				),
+			)
+		} else {
+			results.UpdateLastResult(true, "")
+		}
+	}
+
 	if config.ValidateOnly {
 		return results, nil
 	}
@@ -207,6 +250,115 @@ func CheckTopic(ctx context.Context, config CheckConfig) (TopicCheckResults, err
 		)
 	}
 
+	// Check partition pins
+	if pins := config.TopicConfig.Spec.PlacementConfig.Pins; len(pins) > 0 && !topicDoesNotExist {
+		results.AppendResult(
+			TopicCheckResult{
+				Name: CheckNamePinsSatisfied,
+			},
+		)
+		violations := assigners.PinViolations(topicInfo.ToAssignments(), brokers, pins)
+
+		if len(violations) == 0 {
+			results.UpdateLastResult(true, "")
+		} else {
+			violatedPartitions := []int{}
+			for _, violation := range violations {
+				violatedPartitions = append(violatedPartitions, violation.Partition)
+			}
+
+			results.UpdateLastResult(
+				false,
+				fmt.Sprintf(
+					"%d/%d pins are not satisfied (partitions %+v)",
+					len(violations),
+					len(pins),
+					violatedPartitions,
+				),
+			)
+		}
+	}
+
+	// Check ACLs
+	if config.TopicConfig.ManagesACLs() {
+		results.AppendResult(
+			TopicCheckResult{
+				Name: CheckNameACLsCorrect,
+			},
+		)
+		drift, err := topicACLDrift(ctx, config.AdminClient, config.TopicConfig)
+		if err != nil {
+			return results, err
+		}
+
+		if drift.Empty() {
+			results.UpdateLastResult(true, "")
+		} else {
+			results.UpdateLastResult(false, drift.String())
+		}
+	}
+
+	// Check consumer lag
+	if len(config.TopicConfig.Spec.LagThresholds) > 0 && !topicDoesNotExist {
+		results.AppendResult(
+			TopicCheckResult{
+				Name: CheckNameLagWithinThresholds,
+			},
+		)
+		violations := topicLagViolations(ctx, config.AdminClient, config.TopicConfig)
+
+		if len(violations) == 0 {
+			results.UpdateLastResult(true, "")
+		} else {
+			results.UpdateLastResult(false, formatLagViolations(violations))
+		}
+	}
+
+	// Check consumers
+	if (len(config.TopicConfig.Meta.ConsumerGroups) > 0 ||
+		config.ClusterConfig.Spec.RequireDeclaredConsumerGroups) && !topicDoesNotExist {
+		results.AppendResult(
+			TopicCheckResult{
+				Name: CheckNameConsumerGroupsDeclared,
+			},
+		)
+		undeclared, err := topicUndeclaredConsumers(ctx, config.AdminClient, config.TopicConfig)
+
+		if err != nil {
+			results.UpdateLastResult(false, fmt.Sprintf("could not get consumers: %+v", err))
+		} else if len(undeclared) == 0 {
+			results.UpdateLastResult(true, "")
+		} else {
+			results.UpdateLastResult(
+				false,
+				formatUndeclaredConsumers(config.ClusterConfig, undeclared),
+			)
+		}
+	}
+
+	// Check consumer assignments
+	if config.CheckAssignments && !topicDoesNotExist {
+		results.AppendResult(
+			TopicCheckResult{
+				Name: CheckNameConsumerAssignments,
+			},
+		)
+		warnings, err := topicAssignmentWarnings(
+			ctx,
+			config.AdminClient,
+			config.TopicConfig.Meta.Name,
+			len(topicInfo.Partitions),
+		)
+
+		if err != nil {
+			results.UpdateLastResult(false, fmt.Sprintf("could not get consumers: %+v", err))
+		} else if len(warnings) == 0 {
+			results.UpdateLastResult(true, "")
+		} else {
+			results.UpdateLastResult(false, formatAssignmentWarnings(warnings))
+		}
+	}
+
 	// Check leaders
 	if config.CheckLeaders {
 		results.AppendResult(
@@ -0,0 +1,137 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+)
+
+// UndeclaredConsumers returns the IDs of the groups in the argument topic consumers that have
+// active members but aren't in the consumer groups of the argument topic config, in the same
+// order as the topic consumers.
+func UndeclaredConsumers(
+	topicConfig config.TopicConfig,
+	topicConsumers []groups.TopicConsumer,
+) []string {
+	undeclared := []string{}
+
+	for _, topicConsumer := range topicConsumers {
+		if topicConsumer.ActiveMembers == 0 {
+			continue
+		}
+		if !topicConfig.DeclaresConsumerGroup(topicConsumer.GroupID) {
+			undeclared = append(undeclared, topicConsumer.GroupID)
+		}
+	}
+
+	return undeclared
+}
+
+// topicUndeclaredConsumers gets the consumers of the argument topic from the cluster and returns
+// the active ones that aren't declared in its config.
+func topicUndeclaredConsumers(
+	ctx context.Context,
+	adminClient admin.Client,
+	topicConfig config.TopicConfig,
+) ([]string, error) {
+	connector := adminClient.GetConnector()
+	if connector == nil {
+		return nil, fmt.Errorf("admin client does not support getting consumer groups")
+	}
+
+	topicConsumers, err := groups.GetTopicConsumers(ctx, connector, topicConfig.Meta.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return UndeclaredConsumers(topicConfig, topicConsumers), nil
+}
+
+// formatUndeclaredConsumers describes the argument undeclared groups, including their owners
+// in the argument cluster config if they have any.
+func formatUndeclaredConsumers(clusterConfig config.ClusterConfig, undeclared []string) string {
+	groupStrs := []string{}
+	for _, group := range undeclared {
+		if owner := clusterConfig.ConsumerGroupOwner(group); owner != "" {
+			groupStrs = append(groupStrs, fmt.Sprintf("%s (owner %s)", group, owner))
+		} else {
+			groupStrs = append(groupStrs, group)
+		}
+	}
+
+	return fmt.Sprintf(
+		"%d active group(s) not in consumerGroups: %s",
+		len(undeclared),
+		strings.Join(groupStrs, ", "),
+	)
+}
+
+// topicAssignmentWarnings gets the groups that have members subscribed to the argument topic and
+// returns the assignment warnings for each one that has any, keyed by group ID. See
+// groups.AssignmentWarnings for details.
+func topicAssignmentWarnings(
+	ctx context.Context,
+	adminClient admin.Client,
+	topic string,
+	numPartitions int,
+) (map[string][]string, error) {
+	connector := adminClient.GetConnector()
+	if connector == nil {
+		return nil, fmt.Errorf("admin client does not support getting consumer groups")
+	}
+
+	groupCoordinators, err := groups.GetGroups(ctx, connector)
+	if err != nil {
+		return nil, err
+	}
+
+	groupWarnings := map[string][]string{}
+
+	for _, groupCoordinator := range groupCoordinators {
+		groupDetails, err := groups.GetGroupDetails(ctx, connector, groupCoordinator.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := groupDetails.TopicsMap()[topic]; !ok {
+			continue
+		}
+
+		warnings := groups.AssignmentWarnings(
+			*groupDetails,
+			map[string]int{topic: numPartitions},
+		)
+		if len(warnings) > 0 {
+			groupWarnings[groupCoordinator.GroupID] = warnings
+		}
+	}
+
+	return groupWarnings, nil
+}
+
+// formatAssignmentWarnings describes the argument assignment warnings, sorted by group ID.
+func formatAssignmentWarnings(groupWarnings map[string][]string) string {
+	groupIDs := []string{}
+	for groupID := range groupWarnings {
+		groupIDs = append(groupIDs, groupID)
+	}
+	sort.Strings(groupIDs)
+
+	groupStrs := []string{}
+	for _, groupID := range groupIDs {
+		groupStrs = append(
+			groupStrs,
+			fmt.Sprintf("%s: %s", groupID, strings.Join(groupWarnings[groupID], "; ")),
+		)
+	}
+
+	return fmt.Sprintf(
+		"%d group(s) with assignment problems: %s",
+		len(groupIDs),
+		strings.Join(groupStrs, ", "),
+	)
+}
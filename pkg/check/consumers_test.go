@@ -0,0 +1,61 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAssignmentWarnings(t *testing.T) {
+	assert.Equal(
+		t,
+		"2 group(s) with assignment problems: group-1: warning 1; warning 2, group-2: warning 3",
+		formatAssignmentWarnings(
+			map[string][]string{
+				"group-2": {"warning 3"},
+				"group-1": {"warning 1", "warning 2"},
+			},
+		),
+	)
+}
+
+func TestUndeclaredConsumers(t *testing.T) {
+	topicConfig := config.TopicConfig{
+		Meta: config.TopicMeta{
+			Name:           "test-topic",
+			ConsumerGroups: []string{"group-1", "group-2"},
+		},
+	}
+	clusterConfig := config.ClusterConfig{
+		Spec: config.ClusterSpec{
+			ConsumerGroupOwners: map[string]string{
+				"group-1": "payments",
+				"group-5": "billing",
+			},
+		},
+	}
+
+	topicConsumers := []groups.TopicConsumer{
+		{GroupID: "group-1", ActiveMembers: 2},
+		{GroupID: "group-2", ActiveMembers: 0},
+		{GroupID: "group-3", ActiveMembers: 1},
+		{GroupID: "group-4", ActiveMembers: 0},
+		{GroupID: "group-5", ActiveMembers: 3},
+	}
+
+	undeclared := UndeclaredConsumers(topicConfig, topicConsumers)
+	assert.Equal(t, []string{"group-3", "group-5"}, undeclared)
+	assert.Equal(
+		t,
+		"2 active group(s) not in consumerGroups: group-3, group-5 (owner billing)",
+		formatUndeclaredConsumers(clusterConfig, undeclared),
+	)
+
+	assert.Equal(
+		t,
+		[]string{"group-1", "group-3", "group-5"},
+		UndeclaredConsumers(config.TopicConfig{}, topicConsumers),
+	)
+}
@@ -40,8 +40,10 @@ func FormatResults(results TopicCheckResults) string {
 
 	for _, result := range results.Results {
 		var checkPrinter func(f string, a ...interface{}) string
-		if result.OK || !util.InTerminal() {
+		if result.OK || !util.ColorEnabled() {
 			checkPrinter = fmt.Sprintf
+		} else if result.Warning {
+			checkPrinter = color.New(color.FgYellow).SprintfFunc()
 		} else {
 			checkPrinter = color.New(color.FgRed).SprintfFunc()
 		}
@@ -50,6 +52,8 @@ func FormatResults(results TopicCheckResults) string {
 
 		if result.OK {
 			okStr = "✓"
+		} else if result.Warning {
+			okStr = "!"
 		} else {
 			okStr = "✗"
 		}
@@ -0,0 +1,139 @@
+package check
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// JUnitReport collects topic check results so that they can be written out in the JUnit XML
+// format that most CI systems know how to display. Each topic is a test suite, and each check
+// that was run on it is a test case within that suite.
+type JUnitReport struct {
+	suites []junitTestSuite
+}
+
+var _ Report = (*JUnitReport)(nil)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+
+	// SystemOut has the details of warnings, which JUnit doesn't have a separate element for.
+	SystemOut string `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// AddTopicResults adds the results of checking the argument topic to the report.
+func (r *JUnitReport) AddTopicResults(
+	clusterName string,
+	topicName string,
+	configPath string,
+	results TopicCheckResults,
+) {
+	suite := r.newSuite(clusterName, topicName)
+
+	for _, result := range results.Results {
+		if result.Warning {
+			suite.addWarningCase(string(result.Name), configPath, result.Description)
+		} else {
+			suite.addCase(string(result.Name), configPath, result.OK, result.Description)
+		}
+	}
+
+	r.suites = append(r.suites, suite)
+}
+
+// AddConfigFailure adds a failed test case for a problem with a topic config file.
+func (r *JUnitReport) AddConfigFailure(
+	clusterName string,
+	configPath string,
+	checkName string,
+	err error,
+) {
+	suite := r.newSuite(clusterName, configPath)
+	suite.addCase(checkName, configPath, false, fmt.Sprintf("%+v", err))
+	r.suites = append(r.suites, suite)
+}
+
+// Render converts the report into an XML string.
+func (r *JUnitReport) Render() (string, error) {
+	root := junitTestSuites{
+		Name:   "topicctl check",
+		Suites: r.suites,
+	}
+	if root.Suites == nil {
+		root.Suites = []junitTestSuite{}
+	}
+
+	for _, suite := range root.Suites {
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+	}
+
+	outBytes, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(outBytes) + "\n", nil
+}
+
+func (r *JUnitReport) newSuite(clusterName string, name string) junitTestSuite {
+	return junitTestSuite{
+		Name:  fmt.Sprintf("%s/%s", clusterName, name),
+		Cases: []junitTestCase{},
+	}
+}
+
+func (s *junitTestSuite) addCase(name string, file string, ok bool, description string) {
+	testCase := junitTestCase{
+		Name:      name,
+		ClassName: s.Name,
+		File:      file,
+	}
+	if !ok {
+		testCase.Failure = &junitFailure{
+			Message: fmt.Sprintf("Check %s failed", name),
+			Content: description,
+		}
+		s.Failures++
+	}
+
+	s.Tests++
+	s.Cases = append(s.Cases, testCase)
+}
+
+// addWarningCase adds a passing test case with the warning details in its output, so that
+// warnings are visible without failing the suite.
+func (s *junitTestSuite) addWarningCase(name string, file string, description string) {
+	s.Tests++
+	s.Cases = append(
+		s.Cases,
+		junitTestCase{
+			Name:      name,
+			ClassName: s.Name,
+			File:      file,
+			SystemOut: fmt.Sprintf("Warning: %s", description),
+		},
+	)
+}
@@ -0,0 +1,92 @@
+package check
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJUnitReport(t *testing.T) {
+	report := &JUnitReport{}
+	report.AddTopicResults(
+		"test-cluster",
+		"topic-a",
+		"topics/topic-a.yaml",
+		TopicCheckResults{
+			Results: []TopicCheckResult{
+				{
+					Name: CheckNameTopicExists,
+					OK:   true,
+				},
+				{
+					Name:        CheckNamePartitionCountCorrect,
+					OK:          false,
+					Description: "Partition count in config (3) does not match cluster (2) & <more>",
+				},
+			},
+		},
+	)
+	report.AddConfigFailure(
+		"test-cluster",
+		"topics/topic-b.yaml",
+		"config file name",
+		errors.New("Topic name does not match file name"),
+	)
+
+	xmlStr, err := report.Render()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="topicctl check" tests="3" failures="2">
+  <testsuite name="test-cluster/topic-a" tests="2" failures="1">
+    <testcase name="topic exists" classname="test-cluster/topic-a" file="topics/topic-a.yaml"></testcase>
+    <testcase name="partition count correct" classname="test-cluster/topic-a" file="topics/topic-a.yaml">
+      <failure message="Check partition count correct failed">Partition count in config (3) does not match cluster (2) &amp; &lt;more&gt;</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="test-cluster/topics/topic-b.yaml" tests="1" failures="1">
+    <testcase name="config file name" classname="test-cluster/topics/topic-b.yaml" file="topics/topic-b.yaml">
+      <failure message="Check config file name failed">Topic name does not match file name</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		xmlStr,
+	)
+
+	emptyXMLStr, err := (&JUnitReport{}).Render()
+	require.NoError(t, err)
+	assert.Contains(t, emptyXMLStr, `tests="0" failures="0"`)
+}
+
+func TestJUnitReportWarnings(t *testing.T) {
+	report := &JUnitReport{}
+	report.AddTopicResults(
+		"test-cluster",
+		"topic-a",
+		"topics/topic-a.yaml",
+		TopicCheckResults{
+			Results: []TopicCheckResult{
+				{
+					Name:        CheckNameDeprecationNotExpired,
+					OK:          false,
+					Warning:     true,
+					Description: "topic is deprecated (old) and is past its removal date (2022-01-31)",
+				},
+			},
+		},
+	)
+
+	xmlStr, err := report.Render()
+	require.NoError(t, err)
+	assert.Contains(t, xmlStr, `<testsuites name="topicctl check" tests="1" failures="0">`)
+	assert.Contains(
+		t,
+		xmlStr,
+		"<system-out>Warning: topic is deprecated (old) and is past its removal date (2022-01-31)</system-out>",
+	)
+	assert.NotContains(t, xmlStr, "<failure")
+}
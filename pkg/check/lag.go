@@ -0,0 +1,114 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// LagViolations returns descriptions of the ways in which the argument member lags, which should
+// be for the group in the argument threshold, exceed the threshold. The result is empty if the
+// group is within the threshold.
+func LagViolations(
+	threshold config.TopicLagThreshold,
+	memberLags []groups.MemberPartitionLag,
+) []string {
+	violations := []string{}
+
+	if threshold.MaxLagMessages > 0 {
+		var totalLag int64
+		for _, memberLag := range memberLags {
+			if memberLag.OffsetLag() > 0 {
+				totalLag += memberLag.OffsetLag()
+			}
+		}
+		if totalLag > threshold.MaxLagMessages {
+			violations = append(
+				violations,
+				fmt.Sprintf(
+					"group %s is %d messages behind (max %d)",
+					threshold.Group,
+					totalLag,
+					threshold.MaxLagMessages,
+				),
+			)
+		}
+	}
+
+	maxLagDuration, err := threshold.MaxLagDuration()
+	if err == nil && maxLagDuration > 0 {
+		var maxTimeLag time.Duration
+		maxTimeLagPartition := -1
+
+		for _, memberLag := range memberLags {
+			// The time of the group's last consumed message isn't always available; see
+			// FormatMemberLags.
+			if memberLag.OffsetLag() <= 0 || memberLag.MemberTime.IsZero() {
+				continue
+			}
+			if memberLag.TimeLag() > maxTimeLag {
+				maxTimeLag = memberLag.TimeLag()
+				maxTimeLagPartition = memberLag.Partition
+			}
+		}
+		if maxTimeLag > maxLagDuration {
+			violations = append(
+				violations,
+				fmt.Sprintf(
+					"group %s is %s behind in partition %d (max %s)",
+					threshold.Group,
+					util.PrettyDuration(maxTimeLag),
+					maxTimeLagPartition,
+					threshold.MaxLagTime,
+				),
+			)
+		}
+	}
+
+	return violations
+}
+
+// topicLagViolations checks the lag of each of the groups in the argument topic config's lag
+// thresholds. Groups whose lags can't be fetched, e.g. because they don't exist, are treated
+// as violations.
+func topicLagViolations(
+	ctx context.Context,
+	adminClient admin.Client,
+	topicConfig config.TopicConfig,
+) []string {
+	connector := adminClient.GetConnector()
+	if connector == nil {
+		return []string{"admin client does not support getting consumer lag"}
+	}
+
+	violations := []string{}
+
+	for _, threshold := range topicConfig.Spec.LagThresholds {
+		memberLags, err := groups.GetMemberLags(
+			ctx,
+			connector,
+			topicConfig.Meta.Name,
+			threshold.Group,
+		)
+		if err != nil {
+			violations = append(
+				violations,
+				fmt.Sprintf("could not get lag for group %s: %+v", threshold.Group, err),
+			)
+			continue
+		}
+		violations = append(violations, LagViolations(threshold, memberLags)...)
+	}
+
+	return violations
+}
+
+func formatLagViolations(violations []string) string {
+	return strings.Join(violations, "; ")
+}
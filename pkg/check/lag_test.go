@@ -0,0 +1,77 @@
+package check
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLagViolations(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	memberLags := []groups.MemberPartitionLag{
+		{
+			Partition:    0,
+			MemberOffset: 100,
+			MemberTime:   now.Add(-10 * time.Minute),
+			NewestOffset: 600,
+			NewestTime:   now,
+		},
+		{
+			Partition:    1,
+			MemberOffset: 200,
+			MemberTime:   now.Add(-30 * time.Minute),
+			NewestOffset: 700,
+			NewestTime:   now,
+		},
+		{
+			// Caught up, so the time lag is ignored
+			Partition:    2,
+			MemberOffset: 50,
+			MemberTime:   now.Add(-2 * time.Hour),
+			NewestOffset: 50,
+			NewestTime:   now,
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]string{},
+		LagViolations(
+			config.TopicLagThreshold{
+				Group:          "test-group",
+				MaxLagMessages: 1000,
+				MaxLagTime:     "1h",
+			},
+			memberLags,
+		),
+	)
+	assert.Equal(
+		t,
+		[]string{
+			"group test-group is 1000 messages behind (max 500)",
+			"group test-group is 30m behind in partition 1 (max 15m)",
+		},
+		LagViolations(
+			config.TopicLagThreshold{
+				Group:          "test-group",
+				MaxLagMessages: 500,
+				MaxLagTime:     "15m",
+			},
+			memberLags,
+		),
+	)
+	assert.Equal(
+		t,
+		[]string{"group test-group is 1000 messages behind (max 999)"},
+		LagViolations(
+			config.TopicLagThreshold{
+				Group:          "test-group",
+				MaxLagMessages: 999,
+			},
+			memberLags,
+		),
+	)
+}
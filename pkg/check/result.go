@@ -5,17 +5,43 @@ type CheckName string
 
 const (
 	// All possible CheckName values.
+	CheckNameACLsCorrect              CheckName = "acls correct"
 	CheckNameConfigsConsistent        CheckName = "configs consistent"
 	CheckNameConfigCorrect            CheckName = "config correct"
 	CheckNameConfigSettingsCorrect    CheckName = "config settings correct"
+	CheckNameConsumerAssignments      CheckName = "consumer assignments healthy"
+	CheckNameConsumerGroupsDeclared   CheckName = "consumer groups declared"
+	CheckNameDeprecationNotExpired    CheckName = "deprecation not expired"
+	CheckNameLagWithinThresholds      CheckName = "lag within thresholds"
 	CheckNameLeadersCorrect           CheckName = "leaders correct"
 	CheckNamePartitionCountCorrect    CheckName = "partition count correct"
+	CheckNamePinsSatisfied            CheckName = "partition pins satisfied"
 	CheckNameReplicasInSync           CheckName = "replicas in-sync"
 	CheckNameReplicationFactorCorrect CheckName = "replication factor correct"
 	CheckNameThrottlesClear           CheckName = "throttles clear"
 	CheckNameTopicExists              CheckName = "topic exists"
 )
 
+// Report collects the results of checking topic configs so that they can be written out in a
+// machine-readable format.
+type Report interface {
+	// AddTopicResults adds the results of checking the argument topic, which is defined in the
+	// argument config file, to the report.
+	AddTopicResults(
+		clusterName string,
+		topicName string,
+		configPath string,
+		results TopicCheckResults,
+	)
+
+	// AddConfigFailure adds a failure for a problem with a topic config file that prevented its
+	// topics from being checked, e.g. a file name that doesn't match the topic name.
+	AddConfigFailure(clusterName string, configPath string, checkName string, err error)
+
+	// Render converts the report into a string.
+	Render() (string, error)
+}
+
 // TopicCheckResults stores the result of checking a single topic.
 type TopicCheckResults struct {
 	Results []TopicCheckResult
@@ -26,12 +52,21 @@ type TopicCheckResult struct {
 	Name        CheckName
 	OK          bool
 	Description string
+
+	// Warning is set if the check isn't OK but shouldn't fail the overall check, e.g. for a
+	// deprecated topic that's past its removal date.
+	Warning bool
 }
 
-// AllOK returns true if all subresults are OK, otherwise it returns false.
+// Failed returns whether the check failed, i.e. it isn't OK and isn't just a warning.
+func (r TopicCheckResult) Failed() bool {
+	return !r.OK && !r.Warning
+}
+
+// AllOK returns true if all subresults are OK or only warnings, otherwise it returns false.
 func (r *TopicCheckResults) AllOK() bool {
 	for _, result := range r.Results {
-		if !result.OK {
+		if result.Failed() {
 			return false
 		}
 	}
@@ -49,3 +84,10 @@ func (r *TopicCheckResults) UpdateLastResult(ok bool, description string) {
 	r.Results[len(r.Results)-1].OK = ok
 	r.Results[len(r.Results)-1].Description = description
 }
+
+// UpdateLastResultWarning marks the most recently added result as a warning with the argument
+// description.
+func (r *TopicCheckResults) UpdateLastResultWarning(description string) {
+	r.UpdateLastResult(false, description)
+	r.Results[len(r.Results)-1].Warning = true
+}
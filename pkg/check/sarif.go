@@ -0,0 +1,325 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/segmentio/topicctl/pkg/version"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// checkConfigKeys maps each check to the topic config key that's most relevant to it. SARIF
+// results for these checks point at the line with the key instead of the topic's name.
+var checkConfigKeys = map[CheckName]string{
+	CheckNameACLsCorrect:              "acls",
+	CheckNameConfigSettingsCorrect:    "settings",
+	CheckNameConsumerGroupsDeclared:   "consumerGroups",
+	CheckNameDeprecationNotExpired:    "deprecated",
+	CheckNameLagWithinThresholds:      "lagThresholds",
+	CheckNamePartitionCountCorrect:    "partitions",
+	CheckNamePinsSatisfied:            "pins",
+	CheckNameReplicationFactorCorrect: "replicationFactor",
+}
+
+var yamlDocSeparatorRegexp = regexp.MustCompile(`^---\s*$`)
+
+// SARIFReport collects topic check failures so that they can be written out in the SARIF format,
+// which GitHub code scanning uses to annotate the offending lines in pull requests. Each failed
+// check is a result that points at the config file that defines the topic.
+type SARIFReport struct {
+	results []sarifResult
+	rules   map[string]sarifRule
+
+	// fileLines caches the lines of the config files that have been read, keyed by path
+	fileLines map[string][]string
+}
+
+var _ Report = (*SARIFReport)(nil)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// AddTopicResults adds a result for each failed check, or check with a warning, of the argument
+// topic to the report.
+func (r *SARIFReport) AddTopicResults(
+	clusterName string,
+	topicName string,
+	configPath string,
+	results TopicCheckResults,
+) {
+	for _, result := range results.Results {
+		if result.OK {
+			continue
+		}
+
+		level := "error"
+		status := "failed"
+		if result.Warning {
+			level = "warning"
+			status = "has a warning"
+		}
+
+		message := fmt.Sprintf(
+			"Check %s %s for topic %s in cluster %s",
+			result.Name,
+			status,
+			topicName,
+			clusterName,
+		)
+		if result.Description != "" {
+			message = fmt.Sprintf("%s: %s", message, result.Description)
+		}
+
+		r.addResult(
+			string(result.Name),
+			level,
+			configPath,
+			r.topicConfigLine(configPath, topicName, checkConfigKeys[result.Name]),
+			message,
+		)
+	}
+}
+
+// AddConfigFailure adds a result for a problem with a topic config file.
+func (r *SARIFReport) AddConfigFailure(
+	clusterName string,
+	configPath string,
+	checkName string,
+	err error,
+) {
+	r.addResult(
+		checkName,
+		"error",
+		configPath,
+		0,
+		fmt.Sprintf("Check %s failed in cluster %s: %+v", checkName, clusterName, err),
+	)
+}
+
+// Render converts the report into a JSON string.
+func (r *SARIFReport) Render() (string, error) {
+	rules := []sarifRule{}
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(a, b int) bool {
+		return rules[a].ID < rules[b].ID
+	})
+
+	results := r.results
+	if results == nil {
+		results = []sarifResult{}
+	}
+
+	outBytes, err := json.MarshalIndent(
+		sarifLog{
+			Schema:  sarifSchema,
+			Version: sarifVersion,
+			Runs: []sarifRun{
+				{
+					Tool: sarifTool{
+						Driver: sarifDriver{
+							Name:           "topicctl",
+							InformationURI: "https://github.com/segmentio/topicctl",
+							Version:        version.Version,
+							Rules:          rules,
+						},
+					},
+					Results: results,
+				},
+			},
+		},
+		"",
+		"  ",
+	)
+	if err != nil {
+		return "", err
+	}
+	return string(outBytes) + "\n", nil
+}
+
+func (r *SARIFReport) addResult(
+	checkName string,
+	level string,
+	configPath string,
+	line int,
+	message string,
+) {
+	ruleID := strings.ReplaceAll(checkName, " ", "-")
+
+	if r.rules == nil {
+		r.rules = map[string]sarifRule{}
+	}
+	r.rules[ruleID] = sarifRule{
+		ID:   ruleID,
+		Name: checkName,
+		ShortDescription: sarifMessage{
+			Text: fmt.Sprintf("Topic check %s", checkName),
+		},
+	}
+
+	location := sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{
+			URI: filepath.ToSlash(configPath),
+		},
+	}
+	if line > 0 {
+		location.Region = &sarifRegion{StartLine: line}
+	}
+
+	r.results = append(
+		r.results,
+		sarifResult{
+			RuleID:  ruleID,
+			Level:   level,
+			Message: sarifMessage{Text: message},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: location,
+				},
+			},
+		},
+	)
+}
+
+// topicConfigLine returns the 1-based line number of the argument key in the YAML document that
+// defines the argument topic. If the key is empty or isn't in the document, the line with the
+// topic's name is returned instead. It returns 0 if the topic can't be found.
+func (r *SARIFReport) topicConfigLine(configPath string, topicName string, key string) int {
+	lines, err := r.readLines(configPath)
+	if err != nil {
+		log.Warnf("Could not read topic config %s: %+v", configPath, err)
+		return 0
+	}
+
+	docStart := 0
+	nameLine := 0
+
+	for l := 0; l <= len(lines); l++ {
+		if l < len(lines) && !yamlDocSeparatorRegexp.MatchString(lines[l]) {
+			if nameLine == 0 && yamlLineValue(lines[l], "name") == topicName {
+				nameLine = l + 1
+			}
+			continue
+		}
+
+		// End of a document
+		if nameLine > 0 {
+			if key != "" {
+				for k := docStart; k < l; k++ {
+					if _, ok := yamlLineKey(lines[k], key); ok {
+						return k + 1
+					}
+				}
+			}
+			return nameLine
+		}
+		docStart = l + 1
+	}
+
+	return 0
+}
+
+func (r *SARIFReport) readLines(path string) ([]string, error) {
+	if lines, ok := r.fileLines[path]; ok {
+		return lines, nil
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(contents), "\n")
+
+	if r.fileLines == nil {
+		r.fileLines = map[string][]string{}
+	}
+	r.fileLines[path] = lines
+	return lines, nil
+}
+
+// yamlLineKey returns the remainder of the argument YAML line after the argument key, and
+// whether the line has the key.
+func yamlLineKey(line string, key string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, key+":") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(trimmed, key+":")), true
+}
+
+// yamlLineValue returns the unquoted scalar value for the argument key in the argument YAML line,
+// or an empty string if the line doesn't have the key.
+func yamlLineValue(line string, key string) string {
+	value, ok := yamlLineKey(line, key)
+	if !ok {
+		return ""
+	}
+	if index := strings.Index(value, " #"); index >= 0 {
+		value = strings.TrimSpace(value[:index])
+	}
+	return strings.Trim(value, `"'`)
+}
@@ -0,0 +1,188 @@
+package check
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sarifTestTopicConfig = `meta:
+  name: topic-a
+  cluster: test-cluster
+spec:
+  partitions: 3
+  replicationFactor: 2
+---
+meta:
+  name: "topic-b" # second topic
+  cluster: test-cluster
+spec:
+  partitions: 3
+  replicationFactor: 2
+  settings:
+    cleanup.policy: compact
+`
+
+func TestSARIFReport(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "topics.yaml")
+	require.NoError(
+		t,
+		ioutil.WriteFile(configPath, []byte(sarifTestTopicConfig), 0644),
+	)
+
+	report := &SARIFReport{}
+	report.AddTopicResults(
+		"test-cluster",
+		"topic-a",
+		configPath,
+		TopicCheckResults{
+			Results: []TopicCheckResult{
+				{
+					Name: CheckNameTopicExists,
+					OK:   true,
+				},
+				{
+					Name:        CheckNamePartitionCountCorrect,
+					OK:          false,
+					Description: "Partition count in config (3) does not match cluster (2)",
+				},
+				{
+					Name: CheckNameReplicasInSync,
+					OK:   false,
+				},
+			},
+		},
+	)
+	report.AddTopicResults(
+		"test-cluster",
+		"topic-b",
+		configPath,
+		TopicCheckResults{
+			Results: []TopicCheckResult{
+				{
+					Name: CheckNameConfigSettingsCorrect,
+					OK:   false,
+				},
+				{
+					Name: CheckNamePinsSatisfied,
+					OK:   false,
+				},
+			},
+		},
+	)
+	report.AddConfigFailure(
+		"test-cluster",
+		configPath,
+		"file name correct",
+		errors.New("File name is wrong"),
+	)
+
+	jsonStr, err := report.Render()
+	require.NoError(t, err)
+
+	sarif := sarifLog{}
+	require.NoError(t, json.Unmarshal([]byte(jsonStr), &sarif))
+	assert.Equal(t, sarifVersion, sarif.Version)
+	require.Equal(t, 1, len(sarif.Runs))
+
+	ruleIDs := []string{}
+	for _, rule := range sarif.Runs[0].Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"config-settings-correct",
+			"file-name-correct",
+			"partition-count-correct",
+			"partition-pins-satisfied",
+			"replicas-in-sync",
+		},
+		ruleIDs,
+	)
+
+	type resultSummary struct {
+		ruleID string
+		line   int
+	}
+	summaries := []resultSummary{}
+	for _, result := range sarif.Runs[0].Results {
+		require.Equal(t, 1, len(result.Locations))
+		location := result.Locations[0].PhysicalLocation
+		assert.Equal(t, filepath.ToSlash(configPath), location.ArtifactLocation.URI)
+
+		summary := resultSummary{ruleID: result.RuleID}
+		if location.Region != nil {
+			summary.line = location.Region.StartLine
+		}
+		summaries = append(summaries, summary)
+	}
+	assert.Equal(
+		t,
+		[]resultSummary{
+			{ruleID: "partition-count-correct", line: 5},
+			{ruleID: "replicas-in-sync", line: 2},
+			{ruleID: "config-settings-correct", line: 14},
+			// No pins in the config, so the topic name is used
+			{ruleID: "partition-pins-satisfied", line: 9},
+			{ruleID: "file-name-correct", line: 0},
+		},
+		summaries,
+	)
+	assert.Equal(
+		t,
+		"Check partition count correct failed for topic topic-a in cluster test-cluster: Partition count in config (3) does not match cluster (2)",
+		sarif.Runs[0].Results[0].Message.Text,
+	)
+}
+
+func TestSARIFReportWarnings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "topics.yaml")
+	require.NoError(
+		t,
+		ioutil.WriteFile(
+			configPath,
+			[]byte(`meta:
+  name: topic-a
+  cluster: test-cluster
+  deprecated:
+    reason: old
+    removeAfter: 2022-01-31
+`),
+			0644,
+		),
+	)
+
+	report := &SARIFReport{}
+	report.AddTopicResults(
+		"test-cluster",
+		"topic-a",
+		configPath,
+		TopicCheckResults{
+			Results: []TopicCheckResult{
+				{
+					Name:        CheckNameDeprecationNotExpired,
+					OK:          false,
+					Warning:     true,
+					Description: "topic is deprecated (old) and is past its removal date (2022-01-31)",
+				},
+			},
+		},
+	)
+
+	require.Equal(t, 1, len(report.results))
+	result := report.results[0]
+	assert.Equal(t, "warning", result.Level)
+	assert.Equal(t, "deprecation-not-expired", result.RuleID)
+	assert.Equal(
+		t,
+		"Check deprecation not expired has a warning for topic topic-a in cluster test-cluster: topic is deprecated (old) and is past its removal date (2022-01-31)",
+		result.Message.Text,
+	)
+	assert.Equal(t, 4, result.Locations[0].PhysicalLocation.Region.StartLine)
+}
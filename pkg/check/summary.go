@@ -0,0 +1,283 @@
+package check
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// SummaryReport collects topic check results so that they can be printed as a single matrix
+// with a row for each topic and a column for each check, followed by the details of each failure
+// and warning and the totals. This is easier to scan than the per-topic tables when checking
+// hundreds of topics.
+type SummaryReport struct {
+	// OnlyFailures is set if only the topics with failed checks should be included in the matrix.
+	// The totals still cover all of the topics.
+	OnlyFailures bool
+
+	checkNames []string
+	rows       []summaryRow
+}
+
+var _ Report = (*SummaryReport)(nil)
+
+type summaryRow struct {
+	cluster    string
+	name       string
+	configPath string
+	results    map[string]TopicCheckResult
+	ok         bool
+	warning    bool
+}
+
+// AddTopicResults adds the results of checking the argument topic to the report.
+func (r *SummaryReport) AddTopicResults(
+	clusterName string,
+	topicName string,
+	configPath string,
+	results TopicCheckResults,
+) {
+	row := r.newRow(clusterName, topicName, configPath)
+	for _, result := range results.Results {
+		r.addResult(&row, result)
+	}
+	r.rows = append(r.rows, row)
+}
+
+// AddConfigFailure adds a row with a single failed check for a problem with a topic config file.
+func (r *SummaryReport) AddConfigFailure(
+	clusterName string,
+	configPath string,
+	checkName string,
+	err error,
+) {
+	row := r.newRow(clusterName, configPath, configPath)
+	r.addResult(
+		&row,
+		TopicCheckResult{
+			Name:        CheckName(checkName),
+			OK:          false,
+			Description: fmt.Sprintf("%+v", err),
+		},
+	)
+	r.rows = append(r.rows, row)
+}
+
+// Render converts the report into a string containing the summary matrix, tables with the
+// details of each failed check and each warning, and a line with the totals.
+func (r *SummaryReport) Render() (string, error) {
+	failedCount := 0
+	warningCount := 0
+	for _, row := range r.rows {
+		if !row.ok {
+			failedCount++
+		}
+		if row.warning {
+			warningCount++
+		}
+	}
+
+	buf := &bytes.Buffer{}
+
+	if len(r.rows) > 0 && !(r.OnlyFailures && failedCount == 0) {
+		fmt.Fprintf(buf, "Check summary:\n%s\n", r.formatMatrix())
+	}
+	if failedCount > 0 {
+		fmt.Fprintf(buf, "Failed checks:\n%s\n", r.formatDetails(TopicCheckResult.Failed))
+	}
+	if warningCount > 0 {
+		fmt.Fprintf(
+			buf,
+			"Warnings:\n%s\n",
+			r.formatDetails(func(result TopicCheckResult) bool { return result.Warning }),
+		)
+	}
+
+	fmt.Fprintf(
+		buf,
+		"Checked %d topic(s): %d OK, %d failed",
+		len(r.rows),
+		len(r.rows)-failedCount,
+		failedCount,
+	)
+	if warningCount > 0 {
+		fmt.Fprintf(buf, ", %d with warnings", warningCount)
+	}
+	fmt.Fprintln(buf)
+
+	return buf.String(), nil
+}
+
+func (r *SummaryReport) newRow(clusterName string, name string, configPath string) summaryRow {
+	return summaryRow{
+		cluster:    clusterName,
+		name:       name,
+		configPath: configPath,
+		results:    map[string]TopicCheckResult{},
+		ok:         true,
+	}
+}
+
+func (r *SummaryReport) addResult(row *summaryRow, result TopicCheckResult) {
+	checkName := string(result.Name)
+
+	if _, ok := row.results[checkName]; !ok {
+		found := false
+		for _, name := range r.checkNames {
+			if name == checkName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.checkNames = append(r.checkNames, checkName)
+		}
+	}
+
+	row.results[checkName] = result
+	if result.Failed() {
+		row.ok = false
+	}
+	if result.Warning {
+		row.warning = true
+	}
+}
+
+// formatMatrix generates a table with a row for each topic and a column for each check. Checks
+// that weren't run on a topic, e.g. because an earlier one failed, are left blank. The last row
+// has the number of failures of each check.
+func (r *SummaryReport) formatMatrix() string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+
+	headers := []string{"Cluster", "Topic"}
+	alignments := []int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT}
+	for _, checkName := range r.checkNames {
+		headers = append(headers, checkName)
+		alignments = append(alignments, tablewriter.ALIGN_CENTER)
+	}
+
+	table.SetHeader(headers)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	table.SetColumnAlignment(alignments)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	failedPrinter := fmt.Sprintf
+	warningPrinter := fmt.Sprintf
+	if util.ColorEnabled() {
+		failedPrinter = color.New(color.FgRed).SprintfFunc()
+		warningPrinter = color.New(color.FgYellow).SprintfFunc()
+	}
+
+	checkFailures := map[string]int{}
+
+	for _, row := range r.rows {
+		for _, checkName := range r.checkNames {
+			if result, ok := row.results[checkName]; ok && result.Failed() {
+				checkFailures[checkName]++
+			}
+		}
+		if r.OnlyFailures && row.ok {
+			continue
+		}
+
+		topicStr := row.name
+		if !row.ok {
+			topicStr = failedPrinter("%s", row.name)
+		}
+		values := []string{row.cluster, topicStr}
+
+		for _, checkName := range r.checkNames {
+			result, ok := row.results[checkName]
+			switch {
+			case !ok:
+				values = append(values, "")
+			case result.OK:
+				values = append(values, "✓")
+			case result.Warning:
+				values = append(values, warningPrinter("!"))
+			default:
+				values = append(values, failedPrinter("✗"))
+			}
+		}
+
+		table.Append(values)
+	}
+
+	totals := []string{"", "Failures"}
+	for _, checkName := range r.checkNames {
+		totals = append(totals, fmt.Sprintf("%d", checkFailures[checkName]))
+	}
+	table.Append(totals)
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// formatDetails generates a table with the details of each check result that the argument
+// function returns true for, e.g. the failed ones.
+func (r *SummaryReport) formatDetails(include func(result TopicCheckResult) bool) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Cluster",
+			"Topic",
+			"Config",
+			"Check",
+			"Details",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, row := range r.rows {
+		for _, checkName := range r.checkNames {
+			result, ok := row.results[checkName]
+			if !ok || !include(result) {
+				continue
+			}
+			table.Append(
+				[]string{
+					row.cluster,
+					row.name,
+					row.configPath,
+					checkName,
+					result.Description,
+				},
+			)
+		}
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
@@ -0,0 +1,162 @@
+package check
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSummaryReport(onlyFailures bool) *SummaryReport {
+	report := &SummaryReport{OnlyFailures: onlyFailures}
+	report.AddTopicResults(
+		"test-cluster",
+		"topic-a",
+		"topics/topic-a.yaml",
+		TopicCheckResults{
+			Results: []TopicCheckResult{
+				{
+					Name: CheckNameTopicExists,
+					OK:   true,
+				},
+				{
+					Name: CheckNamePartitionCountCorrect,
+					OK:   true,
+				},
+			},
+		},
+	)
+	report.AddTopicResults(
+		"test-cluster",
+		"topic-b",
+		"topics/topic-b.yaml",
+		TopicCheckResults{
+			Results: []TopicCheckResult{
+				{
+					Name: CheckNameTopicExists,
+					OK:   true,
+				},
+				{
+					Name:        CheckNamePartitionCountCorrect,
+					OK:          false,
+					Description: "Partition count in config (3) does not match cluster (2)",
+				},
+			},
+		},
+	)
+	report.AddConfigFailure(
+		"test-cluster",
+		"topics/topic-c.yaml",
+		"file name correct",
+		errors.New("Topic name does not match file name"),
+	)
+	return report
+}
+
+func TestSummaryReport(t *testing.T) {
+	util.DisableColor()
+
+	summary, err := testSummaryReport(false).Render()
+	require.NoError(t, err)
+
+	lines := []string{}
+	for _, line := range strings.Split(summary, "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"Check summary:",
+			"---------------+---------------------+--------------+-------------------------+--------------------",
+			"Cluster | Topic | topic exists | partition count correct | file name correct",
+			"---------------+---------------------+--------------+-------------------------+--------------------",
+			"test-cluster | topic-a | ✓ | ✓ |",
+			"test-cluster | topic-b | ✓ | ✗ |",
+			"test-cluster | topics/topic-c.yaml | | | ✗",
+			"| Failures | 0 | 1 | 1",
+			"---------------+---------------------+--------------+-------------------------+--------------------",
+			"Failed checks:",
+			"---------------+---------------------+---------------------+-------------------------+-----------------------------------------------------------",
+			"CLUSTER | TOPIC | CONFIG | CHECK | DETAILS",
+			"---------------+---------------------+---------------------+-------------------------+-----------------------------------------------------------",
+			"test-cluster | topic-b | topics/topic-b.yaml | partition count correct | Partition count in config (3) does not match cluster (2)",
+			"test-cluster | topics/topic-c.yaml | topics/topic-c.yaml | file name correct | Topic name does not match file name",
+			"---------------+---------------------+---------------------+-------------------------+-----------------------------------------------------------",
+			"Checked 3 topic(s): 1 OK, 2 failed",
+			"",
+		},
+		lines,
+	)
+
+	onlyFailuresSummary, err := testSummaryReport(true).Render()
+	require.NoError(t, err)
+	assert.NotContains(t, onlyFailuresSummary, "topic-a")
+	assert.Contains(t, onlyFailuresSummary, "topic-b")
+	assert.Contains(t, onlyFailuresSummary, "Checked 3 topic(s): 1 OK, 2 failed")
+
+	emptySummary, err := (&SummaryReport{OnlyFailures: true}).Render()
+	require.NoError(t, err)
+	assert.Equal(t, "Checked 0 topic(s): 0 OK, 0 failed\n", emptySummary)
+}
+
+func TestSummaryReportWarnings(t *testing.T) {
+	util.DisableColor()
+
+	report := &SummaryReport{}
+	report.AddTopicResults(
+		"test-cluster",
+		"topic-a",
+		"topics/topic-a.yaml",
+		TopicCheckResults{
+			Results: []TopicCheckResult{
+				{
+					Name: CheckNameTopicExists,
+					OK:   true,
+				},
+				{
+					Name:        CheckNameDeprecationNotExpired,
+					OK:          false,
+					Warning:     true,
+					Description: "topic is deprecated (old) and is past its removal date (2022-01-31)",
+				},
+			},
+		},
+	)
+
+	summary, err := report.Render()
+	require.NoError(t, err)
+
+	assert.Contains(t, summary, "Warnings:")
+	assert.NotContains(t, summary, "Failed checks:")
+	assert.Contains(t, summary, "past its removal date (2022-01-31)")
+	assert.Contains(t, summary, "Checked 1 topic(s): 1 OK, 0 failed, 1 with warnings\n")
+
+	lines := []string{}
+	for _, line := range strings.Split(summary, "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	assert.Contains(t, lines, "test-cluster | topic-a | ✓ | !")
+	assert.Contains(t, lines, "| Failures | 0 | 0")
+}
+
+func TestTopicCheckResultsAllOK(t *testing.T) {
+	results := TopicCheckResults{
+		Results: []TopicCheckResult{
+			{
+				Name: CheckNameTopicExists,
+				OK:   true,
+			},
+			{
+				Name:    CheckNameDeprecationNotExpired,
+				Warning: true,
+			},
+		},
+	}
+	assert.True(t, results.AllOK())
+
+	results.AppendResult(TopicCheckResult{Name: CheckNameReplicasInSync})
+	assert.False(t, results.AllOK())
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,25 +14,44 @@ import (
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/ghodss/yaml"
+	"github.com/hashicorp/go-multierror"
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/audit"
 	"github.com/segmentio/topicctl/pkg/check"
 	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/cruisecontrol"
+	"github.com/segmentio/topicctl/pkg/events"
 	"github.com/segmentio/topicctl/pkg/groups"
 	"github.com/segmentio/topicctl/pkg/messages"
+	"github.com/segmentio/topicctl/pkg/rbac"
+	"github.com/segmentio/topicctl/pkg/report"
+	"github.com/segmentio/topicctl/pkg/tracing"
+	"github.com/segmentio/topicctl/pkg/util"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
 	spinnerCharSet  = 36
 	spinnerDuration = 200 * time.Millisecond
+
+	// maxImbalancedTopics is the number of imbalanced topics shown in the balance output unless
+	// the full flag is set.
+	maxImbalancedTopics = 10
+
+	// maxStorageTopics is the number of topics shown in the storage breakdown unless the full
+	// flag is set.
+	maxStorageTopics = 20
 )
 
 // CLIRunner is a utility that runs commands from either the command-line or the repl.
 type CLIRunner struct {
-	adminClient admin.Client
-	printer     func(f string, a ...interface{})
-	spinnerObj  *spinner.Spinner
+	adminClient  admin.Client
+	printer      func(f string, a ...interface{})
+	spinnerObj   *spinner.Spinner
+	outputFormat OutputFormat
 }
 
 // NewCLIRunner creates and returns a new CLIRunner instance.
@@ -61,6 +81,12 @@ func NewCLIRunner(
 	return cliRunner
 }
 
+// SetOutputFormat sets the format that the results of get commands are printed in. Structured
+// formats (i.e., everything except OutputFormatTable) are printed to stdout.
+func (c *CLIRunner) SetOutputFormat(outputFormat OutputFormat) {
+	c.outputFormat = outputFormat
+}
+
 // GetBrokers gets all brokers and prints out a summary for the user.
 func (c *CLIRunner) GetBrokers(ctx context.Context, full bool) error {
 	c.startSpinner()
@@ -71,16 +97,42 @@ func (c *CLIRunner) GetBrokers(ctx context.Context, full bool) error {
 		return err
 	}
 
+	if c.structured() {
+		return c.printStructured(brokers, brokers)
+	}
+
 	c.printer("Brokers:\n%s", admin.FormatBrokers(brokers, full))
 	c.printer("Brokers per rack:\n%s", admin.FormatBrokersPerRack(brokers))
 
 	return nil
 }
 
-// ApplyTopic does an apply run according to the spec in the argument config.
+// ApplyTopic does an apply run according to the spec in the argument config. If report is set,
+// the changes to the topic and the outcome of the run are added to it. If the topic has changes,
+// the notification webhooks in the cluster config are notified before and after they're applied.
+// The outcome is also published to the events topic in the cluster config, if one is set.
 func (c *CLIRunner) ApplyTopic(
 	ctx context.Context,
 	applierConfig apply.TopicApplierConfig,
+	report *apply.ApplyReport,
+) error {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"apply topic",
+		attribute.String("topic", applierConfig.TopicConfig.Meta.Name),
+		attribute.String("cluster", applierConfig.ClusterConfig.Meta.Name),
+		attribute.String("environment", applierConfig.ClusterConfig.Meta.Environment),
+		attribute.Bool("dry_run", applierConfig.DryRun),
+	)
+	err := c.applyTopic(ctx, applierConfig, report)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+func (c *CLIRunner) applyTopic(
+	ctx context.Context,
+	applierConfig apply.TopicApplierConfig,
+	report *apply.ApplyReport,
 ) error {
 	applier, err := apply.NewTopicApplier(
 		ctx,
@@ -91,6 +143,18 @@ func (c *CLIRunner) ApplyTopic(
 		return err
 	}
 
+	notifier := apply.NewNotifier(applierConfig.ClusterConfig, applierConfig.DryRun)
+	publisher := events.NewPublisher(applierConfig.ClusterConfig)
+
+	// Get the changes before applying them so that they can be reported
+	var diff apply.TopicDiff
+	if report != nil || notifier.Enabled() || publisher.Enabled() {
+		diff, err = applier.Diff(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	c.printer(
 		"Starting apply for topic %s in environment %s, cluster %s",
 		applierConfig.TopicConfig.Meta.Name,
@@ -98,7 +162,31 @@ func (c *CLIRunner) ApplyTopic(
 		applierConfig.TopicConfig.Meta.Cluster,
 	)
 
+	notify := notifier.Enabled() && diff.HasDiffs()
+	if notify {
+		notifier.Notify(ctx, config.NotificationEventStart, diff, nil)
+	}
+
 	err = applier.Apply(ctx)
+	if notify {
+		if err != nil {
+			notifier.Notify(ctx, config.NotificationEventFailure, diff, err)
+		} else {
+			notifier.Notify(ctx, config.NotificationEventSuccess, diff, nil)
+		}
+	}
+	if report != nil {
+		report.AddResult(
+			applierConfig.TopicConfig.Meta.Cluster,
+			applierConfig.TopicConfig.Meta.Environment,
+			diff,
+			err,
+		)
+	}
+	publisher.Publish(
+		ctx,
+		events.ApplyEvent(applierConfig.ClusterConfig, diff, applierConfig.DryRun, err),
+	)
 	if err != nil {
 		return err
 	}
@@ -107,379 +195,3527 @@ func (c *CLIRunner) ApplyTopic(
 	return nil
 }
 
-// BootstrapTopics creates configs for one or more topics based on their current state in the
-// cluster.
-func (c *CLIRunner) BootstrapTopics(
+// RebalanceTopics evaluates the replica and leader balance of the argument managed topics and
+// then rebalances the ones that need it. The argument applier config is used as a template for
+// each topic; its TopicConfig is ignored. Topics that don't exist in the cluster are skipped. If
+// the applier config has a move budget, it's shared by all of the topics, so the summaries, which
+// are computed before anything is moved, can overstate the moves in the later topics.
+func (c *CLIRunner) RebalanceTopics(
 	ctx context.Context,
-	topics []string,
-	clusterConfig config.ClusterConfig,
-	matchRegexpStr string,
-	excludeRegexpStr string,
-	outputDir string,
-	overwrite bool,
+	topicConfigs []config.TopicConfig,
+	applierConfig apply.TopicApplierConfig,
 ) error {
-	topicInfoObjs, err := c.adminClient.GetTopics(ctx, topics, false)
+	c.startSpinner()
+
+	topicNames, err := c.adminClient.GetTopicNames(ctx)
 	if err != nil {
+		c.stopSpinner()
 		return err
 	}
+	topicNamesMap := map[string]struct{}{}
+	for _, topicName := range topicNames {
+		topicNamesMap[topicName] = struct{}{}
+	}
 
-	matchRegexp, err := regexp.Compile(matchRegexpStr)
+	managedTopicNames := []string{}
+	managedTopicConfigs := []config.TopicConfig{}
+
+	for _, topicConfig := range topicConfigs {
+		if _, ok := topicNamesMap[topicConfig.Meta.Name]; !ok {
+			log.Infof("Skipping topic %s because it doesn't exist in the cluster", topicConfig.Meta.Name)
+			continue
+		}
+		managedTopicNames = append(managedTopicNames, topicConfig.Meta.Name)
+		managedTopicConfigs = append(managedTopicConfigs, topicConfig)
+	}
+
+	if len(managedTopicConfigs) == 0 {
+		c.stopSpinner()
+		c.printer("No managed topics found in the cluster")
+		return nil
+	}
+
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
 	if err != nil {
+		c.stopSpinner()
 		return err
 	}
-	excludeRegexp, err := regexp.Compile(excludeRegexpStr)
+	topics, err := c.adminClient.GetTopics(ctx, managedTopicNames, false)
 	if err != nil {
+		c.stopSpinner()
 		return err
 	}
 
-	topicConfigs := []config.TopicConfig{}
-
-	for _, topicInfo := range topicInfoObjs {
-		if strings.HasPrefix(topicInfo.Name, "__") {
-			// Never include underscore topics
-			continue
-		} else if !matchRegexp.MatchString(topicInfo.Name) {
-			continue
-		} else if excludeRegexp.MatchString(topicInfo.Name) {
-			continue
-		}
+	appliers := []*apply.TopicApplier{}
+	summaries := []apply.TopicRebalanceSummary{}
 
-		topicConfig := config.TopicConfigFromTopicInfo(
-			clusterConfig,
-			topicInfo,
-		)
-		topicConfigs = append(topicConfigs, topicConfig)
-	}
+	for _, topicConfig := range managedTopicConfigs {
+		topicApplierConfig := applierConfig
+		topicApplierConfig.TopicConfig = topicConfig
 
-	for _, topicConfig := range topicConfigs {
-		yamlStr, err := topicConfig.ToYAML()
+		applier, err := apply.NewTopicApplier(ctx, c.adminClient, topicApplierConfig)
+		if err != nil {
+			c.stopSpinner()
+			return err
+		}
+		summary, err := applier.RebalanceSummary(ctx)
 		if err != nil {
+			c.stopSpinner()
 			return err
 		}
+		if summary.Balanced() {
+			continue
+		}
 
-		if outputDir != "" {
-			outputPath := filepath.Join(
-				outputDir,
-				fmt.Sprintf("%s.yaml", topicConfig.Meta.Name),
-			)
-
-			var isNew bool
+		appliers = append(appliers, applier)
+		summaries = append(summaries, summary)
+	}
+	c.stopSpinner()
 
-			_, err := os.Stat(outputPath)
-			if err != nil && !os.IsNotExist(err) {
-				return err
-			}
-			isNew = os.IsNotExist(err)
+	c.printer(
+		"Broker replicas across %d managed topic(s):\n%s",
+		len(managedTopicConfigs),
+		admin.FormatBrokerReplicas(brokers, topics),
+	)
 
-			if isNew || overwrite {
-				log.Infof("Writing config to %s", outputPath)
-				err = ioutil.WriteFile(outputPath, []byte(yamlStr), 0644)
-				if err != nil {
-					return err
-				}
-			} else {
-				log.Infof("Skipping over existing config %s", outputPath)
-			}
-		} else {
-			log.Infof("Config for topic %s:\n%s", topicConfig.Meta.Name, yamlStr)
-		}
+	if len(summaries) == 0 {
+		c.printer("All managed topics are balanced")
+		return nil
 	}
 
-	return nil
-}
+	c.printer(
+		"Found %d unbalanced topic(s):\n%s",
+		len(summaries),
+		apply.FormatRebalanceSummaries(summaries),
+	)
 
-// CheckTopic runs a topic check against a single topic and prints a summary of the results out.
-func (c *CLIRunner) CheckTopic(
-	ctx context.Context,
-	checkConfig check.CheckConfig,
-) (bool, error) {
-	results, err := check.CheckTopic(ctx, checkConfig)
+	ok, _ := apply.Confirm(
+		fmt.Sprintf("OK to rebalance %d topic(s)?", len(summaries)),
+		applierConfig.SkipConfirm || applierConfig.DryRun,
+	)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
 
-	if results.AllOK() {
-		c.printer(
-			"Topic %s (cluster=%s, env=%s) OK",
-			checkConfig.TopicConfig.Meta.Name,
-			checkConfig.ClusterConfig.Meta.Name,
-			checkConfig.ClusterConfig.Meta.Environment,
-		)
-	} else {
+	for a, applier := range appliers {
 		c.printer(
-			"Check failed for topic %s (cluster=%s, env=%s):\n%s",
-			checkConfig.TopicConfig.Meta.Name,
-			checkConfig.ClusterConfig.Meta.Name,
-			checkConfig.ClusterConfig.Meta.Environment,
-			check.FormatResults(results),
+			"Starting rebalance for topic %s (%d/%d)",
+			summaries[a].Topic,
+			a+1,
+			len(appliers),
 		)
-	}
 
-	return results.AllOK(), err
-}
-
-// GetBrokerBalance evaluates the balance of the brokers for a single topic and prints a summary
-// out for user inspection.
-func (c *CLIRunner) GetBrokerBalance(ctx context.Context, topicName string) error {
-	c.startSpinner()
+		if err := applier.Rebalance(ctx); err != nil {
+			return err
+		}
+	}
 
-	brokers, err := c.adminClient.GetBrokers(ctx, nil)
-	if err != nil {
-		c.stopSpinner()
-		return err
+	if applierConfig.DryRun {
+		c.printer("Rebalance dry-run completed successfully!")
+		return nil
 	}
 
-	var topicNames []string
-	if topicName != "" {
-		topicNames = []string{topicName}
+	c.printer("Rebalance completed successfully!")
+	return nil
+}
+
+// DrainBroker moves all of the replicas in the argument managed topics off of the argument
+// broker so that it can be decommissioned. The replicas on the broker and their total size are
+// shown first, and then the affected topics are rebalanced with the broker removed, using the
+// argument applier config as a template. Once that's done, the broker is checked again and an
+// error is returned if it still has any replicas, e.g. in topics that aren't managed.
+func (c *CLIRunner) DrainBroker(
+	ctx context.Context,
+	brokerID int,
+	topicConfigs []config.TopicConfig,
+	applierConfig apply.TopicApplierConfig,
+) error {
+	managedTopics := map[string]struct{}{}
+	for _, topicConfig := range topicConfigs {
+		managedTopics[topicConfig.Meta.Name] = struct{}{}
 	}
 
-	topics, err := c.adminClient.GetTopics(ctx, topicNames, false)
+	plan, err := c.getBrokerDrainPlan(ctx, brokerID, managedTopics)
 	if err != nil {
-		c.stopSpinner()
 		return err
 	}
+	if plan.Empty() {
+		c.printer("Broker %d doesn't have any replicas; it can be decommissioned", brokerID)
+		return nil
+	}
 
-	c.stopSpinner()
+	c.printer(
+		"Replicas on broker %d:\n%s",
+		brokerID,
+		admin.FormatBrokerDrainPlan(plan),
+	)
 
-	c.printer("Broker replicas:\n%s", admin.FormatBrokerReplicas(brokers, topics))
-	c.printer("Broker rack replicas:\n%s", admin.FormatBrokerRackReplicas(brokers, topics))
+	size, complete := plan.Size()
+	sizeStr := util.PrettyBytes(size)
+	if !complete {
+		sizeStr = fmt.Sprintf("at least %s", sizeStr)
+	}
+	c.printer(
+		"Draining broker %d will move %d replica(s) in %d topic(s), totalling %s",
+		brokerID,
+		plan.Replicas(),
+		len(plan.Topics),
+		sizeStr,
+	)
 
-	return nil
-}
+	unmanagedTopics := plan.UnmanagedTopics()
+	if len(unmanagedTopics) > 0 {
+		log.Warnf(
+			"Topic(s) %+v don't have configs, so their replicas can't be moved; the broker won't be empty until they're moved separately",
+			unmanagedTopics,
+		)
+	}
 
-// GetConfig fetches the config for a broker or topic and prints it out for user inspection.
-func (c *CLIRunner) GetConfig(ctx context.Context, brokerOrTopic string) error {
-	c.startSpinner()
+	drainConfigs := []config.TopicConfig{}
+	for _, topicConfig := range topicConfigs {
+		for _, topicPlan := range plan.Topics {
+			if topicPlan.Topic == topicConfig.Meta.Name {
+				drainConfigs = append(drainConfigs, topicConfig)
+				break
+			}
+		}
+	}
+	if len(drainConfigs) == 0 {
+		return fmt.Errorf("None of the topics with replicas on broker %d are managed", brokerID)
+	}
 
-	brokerIDs, err := c.adminClient.GetBrokerIDs(ctx)
-	if err != nil {
-		c.stopSpinner()
+	applierConfig.Rebalance = true
+	applierConfig.BrokersToRemove = append(
+		util.CopyInts(applierConfig.BrokersToRemove),
+		brokerID,
+	)
+	if err := c.RebalanceTopics(ctx, drainConfigs, applierConfig); err != nil {
 		return err
 	}
+	if applierConfig.DryRun {
+		return nil
+	}
 
-	topicNames, err := c.adminClient.GetTopicNames(ctx)
+	plan, err = c.getBrokerDrainPlan(ctx, brokerID, managedTopics)
 	if err != nil {
-		c.stopSpinner()
 		return err
 	}
+	if !plan.Empty() {
+		c.printer(
+			"Replicas remaining on broker %d:\n%s",
+			brokerID,
+			admin.FormatBrokerDrainPlan(plan),
+		)
+		return fmt.Errorf(
+			"Broker %d still has %d replica(s)",
+			brokerID,
+			plan.Replicas(),
+		)
+	}
 
-	for _, brokerID := range brokerIDs {
-		if fmt.Sprintf("%d", brokerID) == brokerOrTopic {
-			brokers, err := c.adminClient.GetBrokers(ctx, []int{brokerID})
-			if err != nil {
-				c.stopSpinner()
-				return err
-			}
-			if len(brokers) != 1 {
-				c.stopSpinner()
-				return fmt.Errorf("Could not find broker %d", brokerID)
-			}
-			c.stopSpinner()
+	c.printer("Broker %d is empty and can be decommissioned", brokerID)
+	return nil
+}
 
-			c.printer(
-				"Config for broker %d:\n%s",
-				brokerID,
-				admin.FormatConfig(brokers[0].Config),
-			)
-			return nil
-		}
+// RebalanceWithCruiseControl delegates a rebalance of the cluster, or the removal of the argument
+// brokers if any are set, to Cruise Control. The proposals are computed with a dry-run first so
+// that their summary can be shown before anything is moved. Unless the argument request is a
+// dry-run, they're then submitted for execution and the resulting task is polled until it's done.
+// The ID of the executing task is returned, or an empty string if nothing was executed.
+func (c *CLIRunner) RebalanceWithCruiseControl(
+	ctx context.Context,
+	ccClient *cruisecontrol.Client,
+	request cruisecontrol.OptimizationRequest,
+	brokersToRemove []int,
+	skipConfirm bool,
+) (string, error) {
+	description := "rebalance"
+	if len(brokersToRemove) > 0 {
+		description = fmt.Sprintf("removal of broker(s) %+v", brokersToRemove)
 	}
 
-	for _, topicName := range topicNames {
-		if topicName == brokerOrTopic {
-			topics, err := c.adminClient.GetTopics(ctx, []string{topicName}, false)
-			if err != nil {
-				c.stopSpinner()
-				return err
-			}
-			if len(topics) != 1 {
-				c.stopSpinner()
-				return fmt.Errorf("Could not find topic %s", topicName)
-			}
-			c.stopSpinner()
-
-			c.printer(
-				"Config for topic %s:\n%s",
-				topicName,
-				admin.FormatConfig(topics[0].Config),
-			)
-			return nil
+	optimize := func(
+		optimizationRequest cruisecontrol.OptimizationRequest,
+	) (cruisecontrol.OptimizationResult, error) {
+		if len(brokersToRemove) > 0 {
+			return ccClient.RemoveBrokers(ctx, brokersToRemove, optimizationRequest)
 		}
+		return ccClient.Rebalance(ctx, optimizationRequest)
 	}
 
-	c.stopSpinner()
-	return fmt.Errorf("Could not find broker or topic named %s", brokerOrTopic)
-}
+	proposalRequest := request
+	proposalRequest.DryRun = true
 
-// GetGroups fetches all consumer groups and prints them out for user inspection.
-func (c *CLIRunner) GetGroups(ctx context.Context) error {
 	c.startSpinner()
-
-	groupCoordinators, err := groups.GetGroups(ctx, c.adminClient.GetConnector())
+	proposal, err := optimize(proposalRequest)
 	c.stopSpinner()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	c.printer("Groups:\n%s", groups.FormatGroupCoordinators(groupCoordinators))
-	return nil
+	c.printer(
+		"Cruise Control proposals for the %s:\n%s",
+		description,
+		cruisecontrol.FormatOptimizationSummary(proposal.Summary),
+	)
+
+	if proposal.Summary.Empty() {
+		c.printer("Cruise Control didn't propose any changes")
+		return "", nil
+	}
+	if request.DryRun {
+		c.printer("Cruise Control %s dry-run completed successfully!", description)
+		return "", nil
+	}
+
+	ok, _ := apply.Confirm(
+		fmt.Sprintf("OK to have Cruise Control execute the %s?", description),
+		skipConfirm,
+	)
+	if !ok {
+		return "", errors.New("Stopping because of user response")
+	}
+
+	result, err := optimize(request)
+	if err != nil {
+		return "", err
+	}
+	if result.TaskID == "" {
+		return "", errors.New("Cruise Control didn't return a task ID for the execution")
+	}
+
+	c.printer("Cruise Control is executing the %s in task %s", description, result.TaskID)
+	if _, err := ccClient.WaitForTask(ctx, result.TaskID); err != nil {
+		return result.TaskID, err
+	}
+
+	c.printer("Cruise Control %s completed successfully!", description)
+	return result.TaskID, nil
 }
 
-// GetGroupMembers fetches and prints out information about every member in a consumer group.
-func (c *CLIRunner) GetGroupMembers(ctx context.Context, groupID string, full bool) error {
+// getBrokerDrainPlan fetches the replicas on the argument broker and their sizes. Sizes aren't
+// critical, so a warning is logged if they can't be fetched.
+func (c *CLIRunner) getBrokerDrainPlan(
+	ctx context.Context,
+	brokerID int,
+	managedTopics map[string]struct{},
+) (admin.BrokerDrainPlan, error) {
 	c.startSpinner()
+	defer c.stopSpinner()
 
-	groupDetails, err := groups.GetGroupDetails(
-		ctx,
-		c.adminClient.GetConnector(),
-		groupID,
-	)
-	c.stopSpinner()
+	brokers, err := c.adminClient.GetBrokers(ctx, []int{brokerID})
 	if err != nil {
-		return err
+		return admin.BrokerDrainPlan{}, err
+	}
+	if len(brokers) == 0 {
+		return admin.BrokerDrainPlan{}, fmt.Errorf("Broker %d not found", brokerID)
+	}
+
+	topics, err := c.adminClient.GetTopics(ctx, nil, false)
+	if err != nil {
+		return admin.BrokerDrainPlan{}, err
+	}
+
+	plan := admin.GetBrokerDrainPlan(brokerID, topics, nil, managedTopics)
+	if plan.Empty() {
+		return plan, nil
+	}
+
+	topicNames := []string{}
+	for _, topicPlan := range plan.Topics {
+		topicNames = append(topicNames, topicPlan.Topic)
+	}
+
+	replicaLogDirs, err := c.adminClient.GetReplicaLogDirs(ctx, topicNames, []int{brokerID})
+	if err != nil {
+		log.Warnf("Could not get replica sizes: %+v", err)
+		return plan, nil
+	}
+
+	return admin.GetBrokerDrainPlan(brokerID, topics, replicaLogDirs, managedTopics), nil
+}
+
+// BootstrapTopics creates configs for one or more topics based on their current state in the
+// cluster. If includeGroups is set, snapshots of the offsets that each consumer group has
+// committed in these topics are also created, and if includeACLs is set, the ACLs in the cluster
+// are written out as well.
+func (c *CLIRunner) BootstrapTopics(
+	ctx context.Context,
+	topics []string,
+	clusterConfig config.ClusterConfig,
+	matchRegexpStr string,
+	excludeRegexpStr string,
+	outputDir string,
+	overwrite bool,
+	includeGroups bool,
+	includeACLs bool,
+) error {
+	topicConfigs, err := c.managedTopicConfigs(
+		ctx,
+		topics,
+		clusterConfig,
+		matchRegexpStr,
+		excludeRegexpStr,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, topicConfig := range topicConfigs {
+		yamlStr, err := topicConfig.ToYAML()
+		if err != nil {
+			return err
+		}
+
+		if outputDir != "" {
+			outputPath := filepath.Join(
+				outputDir,
+				fmt.Sprintf("%s.yaml", topicConfig.Meta.Name),
+			)
+			if err := writeConfigFile(outputPath, yamlStr, overwrite); err != nil {
+				return err
+			}
+		} else {
+			log.Infof("Config for topic %s:\n%s", topicConfig.Meta.Name, yamlStr)
+		}
+	}
+
+	if includeGroups {
+		topicNames := map[string]struct{}{}
+		for _, topicConfig := range topicConfigs {
+			topicNames[topicConfig.Meta.Name] = struct{}{}
+		}
+
+		if err := c.bootstrapGroups(ctx, topicNames, outputDir, overwrite); err != nil {
+			return err
+		}
+	}
+
+	if includeACLs {
+		acls, err := c.getAllACLs(ctx)
+		if err != nil {
+			return err
+		}
+		aclsYAML, err := yaml.Marshal(acls)
+		if err != nil {
+			return err
+		}
+
+		if outputDir != "" {
+			if err := writeConfigFile(
+				filepath.Join(outputDir, "acls.yaml"),
+				string(aclsYAML),
+				overwrite,
+			); err != nil {
+				return err
+			}
+		} else {
+			log.Infof("ACLs:\n%s", string(aclsYAML))
+		}
+	}
+
+	return nil
+}
+
+// bootstrapGroups writes out a snapshot of the committed offsets of each consumer group in the
+// argument topics. The snapshots go into the groups subdirectory of outputDir if it's set.
+// Groups that haven't committed offsets in any of the topics are skipped.
+func (c *CLIRunner) bootstrapGroups(
+	ctx context.Context,
+	topicNames map[string]struct{},
+	outputDir string,
+	overwrite bool,
+) error {
+	if len(topicNames) == 0 {
+		return nil
+	}
+
+	groupCoordinators, err := groups.GetGroups(ctx, c.adminClient.GetConnector())
+	if err != nil {
+		return err
+	}
+
+	groupsDir := filepath.Join(outputDir, "groups")
+	if outputDir != "" {
+		if err := os.MkdirAll(groupsDir, 0755); err != nil {
+			return err
+		}
+	}
+
+	capturedAt := time.Now()
+
+	for _, groupCoordinator := range groupCoordinators {
+		partitionLags, err := groups.GetGroupLags(
+			ctx,
+			c.adminClient.GetConnector(),
+			groupCoordinator.GroupID,
+		)
+		if err != nil {
+			log.Warnf(
+				"Could not get offsets for group %s: %+v",
+				groupCoordinator.GroupID,
+				err,
+			)
+			continue
+		}
+
+		snapshot := groups.SnapshotGroupOffsets(
+			groupCoordinator.GroupID,
+			partitionLags,
+			topicNames,
+			capturedAt,
+		)
+		if len(snapshot.Topics) == 0 {
+			log.Debugf(
+				"Skipping over group %s since it has no offsets in the bootstrapped topics",
+				groupCoordinator.GroupID,
+			)
+			continue
+		}
+
+		yamlStr, err := snapshot.ToYAML()
+		if err != nil {
+			return err
+		}
+
+		if outputDir != "" {
+			// Unlike topic names, group IDs can contain slashes
+			outputPath := filepath.Join(
+				groupsDir,
+				fmt.Sprintf("%s.yaml", strings.ReplaceAll(snapshot.GroupID, "/", "_")),
+			)
+			if err := writeConfigFile(outputPath, yamlStr, overwrite); err != nil {
+				return err
+			}
+		} else {
+			log.Infof("Offsets for group %s:\n%s", snapshot.GroupID, yamlStr)
+		}
+	}
+
+	return nil
+}
+
+// getAllACLs gets all of the ACLs in the cluster.
+func (c *CLIRunner) getAllACLs(ctx context.Context) ([]admin.ACLInfo, error) {
+	// A blank filter matches all ACLs
+	kafkaFilter, err := admin.ACLFilter{}.KafkaFilter()
+	if err != nil {
+		return nil, err
+	}
+	return c.adminClient.GetACLs(ctx, kafkaFilter)
+}
+
+// ExportCluster writes out the state of the cluster as topicctl configs in the argument output
+// directory, in the same layout that apply uses. The cluster config is written to cluster.yaml,
+// and a config for each managed topic is written to the topics subdirectory. If includeQuotas is
+// set, the quotas in the cluster replace the ones in the exported cluster config, and if
+// includeACLs is set, the ACLs in the cluster are written to acls.yaml.
+func (c *CLIRunner) ExportCluster(
+	ctx context.Context,
+	clusterConfig config.ClusterConfig,
+	outputDir string,
+	includeACLs bool,
+	includeQuotas bool,
+	overwrite bool,
+) error {
+	c.startSpinner()
+	topicConfigs, err := c.managedTopicConfigs(ctx, nil, clusterConfig, ".*", ".^")
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	var quotas []admin.QuotaInfo
+	if includeQuotas {
+		quotas, err = c.adminClient.GetQuotas(ctx)
+		if err != nil {
+			c.stopSpinner()
+			return err
+		}
+	}
+
+	var acls []admin.ACLInfo
+	if includeACLs {
+		acls, err = c.getAllACLs(ctx)
+		if err != nil {
+			c.stopSpinner()
+			return err
+		}
+	}
+	c.stopSpinner()
+
+	topicsDir := filepath.Join(outputDir, "topics")
+	if err := os.MkdirAll(topicsDir, 0755); err != nil {
+		return err
+	}
+
+	if includeQuotas {
+		clusterConfig.Spec.Quotas = []config.QuotaConfig{}
+		for _, quota := range quotas {
+			clusterConfig.Spec.Quotas = append(
+				clusterConfig.Spec.Quotas,
+				config.QuotaConfigFromQuotaInfo(quota),
+			)
+		}
+	}
+
+	clusterYAML, err := clusterConfig.ToYAML()
+	if err != nil {
+		return err
+	}
+	if err := writeConfigFile(
+		filepath.Join(outputDir, "cluster.yaml"),
+		clusterYAML,
+		overwrite,
+	); err != nil {
+		return err
+	}
+
+	for _, topicConfig := range topicConfigs {
+		topicConfig.Meta.Description = "Exported via topicctl export"
+
+		topicYAML, err := topicConfig.ToYAML()
+		if err != nil {
+			return err
+		}
+		if err := writeConfigFile(
+			filepath.Join(topicsDir, fmt.Sprintf("%s.yaml", topicConfig.Meta.Name)),
+			topicYAML,
+			overwrite,
+		); err != nil {
+			return err
+		}
+	}
+
+	if includeACLs {
+		aclsYAML, err := yaml.Marshal(acls)
+		if err != nil {
+			return err
+		}
+		if err := writeConfigFile(
+			filepath.Join(outputDir, "acls.yaml"),
+			string(aclsYAML),
+			overwrite,
+		); err != nil {
+			return err
+		}
+	}
+
+	c.printer(
+		"Exported %d topic(s), %d quota(s), and %d ACL(s) from cluster %s to %s",
+		len(topicConfigs),
+		len(quotas),
+		len(acls),
+		clusterConfig.Meta.Name,
+		outputDir,
+	)
+	return nil
+}
+
+// managedTopicConfigs generates topic configs for the topics in the cluster that match the
+// argument regexps, skipping internal and unmanaged topics. If topics is non-empty, only
+// those topics are considered.
+func (c *CLIRunner) managedTopicConfigs(
+	ctx context.Context,
+	topics []string,
+	clusterConfig config.ClusterConfig,
+	matchRegexpStr string,
+	excludeRegexpStr string,
+) ([]config.TopicConfig, error) {
+	topicInfoObjs, err := c.adminClient.GetTopics(ctx, topics, false)
+	if err != nil {
+		return nil, err
+	}
+
+	matchRegexp, err := regexp.Compile(matchRegexpStr)
+	if err != nil {
+		return nil, err
+	}
+	excludeRegexp, err := regexp.Compile(excludeRegexpStr)
+	if err != nil {
+		return nil, err
+	}
+
+	topicConfigs := []config.TopicConfig{}
+
+	for _, topicInfo := range topicInfoObjs {
+		if strings.HasPrefix(topicInfo.Name, "__") {
+			// Never include underscore topics
+			continue
+		} else if !matchRegexp.MatchString(topicInfo.Name) {
+			continue
+		} else if excludeRegexp.MatchString(topicInfo.Name) {
+			continue
+		} else if clusterConfig.IsUnmanagedTopic(topicInfo.Name) {
+			log.Debugf("Skipping over unmanaged topic %s", topicInfo.Name)
+			continue
+		}
+
+		topicConfig := config.TopicConfigFromTopicInfo(
+			clusterConfig,
+			topicInfo,
+		)
+		topicConfigs = append(topicConfigs, topicConfig)
+	}
+
+	return topicConfigs, nil
+}
+
+// writeConfigFile writes the argument contents to outputPath. Existing files are skipped
+// unless overwrite is set.
+func writeConfigFile(outputPath string, contents string, overwrite bool) error {
+	_, err := os.Stat(outputPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	isNew := os.IsNotExist(err)
+
+	if isNew || overwrite {
+		log.Infof("Writing config to %s", outputPath)
+		return ioutil.WriteFile(outputPath, []byte(contents), 0644)
+	}
+
+	log.Infof("Skipping over existing config %s", outputPath)
+	return nil
+}
+
+// CheckTopic runs a topic check against a single topic, prints a summary of the results out, and
+// returns them. Unless only validating, the results are also published to the events topic in the
+// cluster config, if one is set.
+func (c *CLIRunner) CheckTopic(
+	ctx context.Context,
+	checkConfig check.CheckConfig,
+) (check.TopicCheckResults, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"check topic",
+		attribute.String("topic", checkConfig.TopicConfig.Meta.Name),
+		attribute.String("cluster", checkConfig.ClusterConfig.Meta.Name),
+		attribute.String("environment", checkConfig.ClusterConfig.Meta.Environment),
+		attribute.Bool("validate_only", checkConfig.ValidateOnly),
+	)
+	results, err := check.CheckTopic(ctx, checkConfig)
+	span.SetAttributes(attribute.Bool("ok", results.AllOK()))
+	tracing.EndSpan(span, err)
+
+	if !checkConfig.ValidateOnly {
+		events.NewPublisher(checkConfig.ClusterConfig).Publish(
+			ctx,
+			events.CheckEvent(
+				checkConfig.ClusterConfig,
+				checkConfig.TopicConfig.Meta.Name,
+				results,
+				err,
+			),
+		)
+	}
+
+	if results.AllOK() {
+		c.printer(
+			"Topic %s (cluster=%s, env=%s) OK",
+			checkConfig.TopicConfig.Meta.Name,
+			checkConfig.ClusterConfig.Meta.Name,
+			checkConfig.ClusterConfig.Meta.Environment,
+		)
+	} else {
+		c.printer(
+			"Check failed for topic %s (cluster=%s, env=%s):\n%s",
+			checkConfig.TopicConfig.Meta.Name,
+			checkConfig.ClusterConfig.Meta.Name,
+			checkConfig.ClusterConfig.Meta.Environment,
+			check.FormatResults(results),
+		)
+	}
+
+	return results, err
+}
+
+// CheckACLs runs the checks for a single ACL config, prints a summary of the results out, and
+// returns them.
+func (c *CLIRunner) CheckACLs(
+	ctx context.Context,
+	checkConfig check.CheckACLsConfig,
+) (check.TopicCheckResults, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"check acls",
+		attribute.String("acls", checkConfig.ACLConfig.Meta.Name),
+		attribute.String("cluster", checkConfig.ClusterConfig.Meta.Name),
+		attribute.String("environment", checkConfig.ClusterConfig.Meta.Environment),
+		attribute.Bool("validate_only", checkConfig.ValidateOnly),
+	)
+	results, err := check.CheckACLs(ctx, checkConfig)
+	span.SetAttributes(attribute.Bool("ok", results.AllOK()))
+	tracing.EndSpan(span, err)
+
+	if results.AllOK() {
+		c.printer(
+			"ACLs %s (cluster=%s, env=%s) OK",
+			checkConfig.ACLConfig.Meta.Name,
+			checkConfig.ClusterConfig.Meta.Name,
+			checkConfig.ClusterConfig.Meta.Environment,
+		)
+	} else {
+		c.printer(
+			"Check failed for ACLs %s (cluster=%s, env=%s):\n%s",
+			checkConfig.ACLConfig.Meta.Name,
+			checkConfig.ClusterConfig.Meta.Name,
+			checkConfig.ClusterConfig.Meta.Environment,
+			check.FormatResults(results),
+		)
+	}
+
+	return results, err
+}
+
+// IncreasePartitions adds partitions to the topic in the argument applier config until it has the
+// number in the config, without applying any of the config's other changes.
+func (c *CLIRunner) IncreasePartitions(
+	ctx context.Context,
+	applierConfig apply.TopicApplierConfig,
+) error {
+	applier, err := apply.NewTopicApplier(
+		ctx,
+		c.adminClient,
+		applierConfig,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.printer(
+		"Starting partition increase for topic %s in environment %s, cluster %s",
+		applierConfig.TopicConfig.Meta.Name,
+		applierConfig.TopicConfig.Meta.Environment,
+		applierConfig.TopicConfig.Meta.Cluster,
+	)
+
+	if err := applier.IncreasePartitions(ctx); err != nil {
+		return err
+	}
+
+	c.printer("Partition increase completed successfully!")
+	return nil
+}
+
+// DiffTopic compares the topic config in the argument applier config against the current state
+// of the topic in the cluster and prints out the changes that an apply would make. It returns
+// whether there are any differences.
+func (c *CLIRunner) DiffTopic(
+	ctx context.Context,
+	applierConfig apply.TopicApplierConfig,
+) (bool, error) {
+	applier, err := apply.NewTopicApplier(
+		ctx,
+		c.adminClient,
+		applierConfig,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	diff, err := applier.Diff(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	topicConfig := applierConfig.TopicConfig
+
+	if !diff.HasDiffs() {
+		c.printer(
+			"Topic %s (cluster=%s, env=%s) matches its config",
+			diff.Topic,
+			topicConfig.Meta.Cluster,
+			topicConfig.Meta.Environment,
+		)
+		return false, nil
+	}
+
+	if diff.New {
+		c.printer(
+			"Topic %s (cluster=%s, env=%s) does not exist; it would be created with this config:\n%s",
+			diff.Topic,
+			topicConfig.Meta.Cluster,
+			topicConfig.Meta.Environment,
+			apply.FormatNewTopicConfig(diff.NewTopicConfig),
+		)
+		c.printACLDiffs(diff)
+		return true, nil
+	}
+
+	c.printer(
+		"Found differences for topic %s (cluster=%s, env=%s)",
+		diff.Topic,
+		topicConfig.Meta.Cluster,
+		topicConfig.Meta.Environment,
+	)
+
+	if len(diff.SettingsDiffKeys) > 0 {
+		diffsTable, err := apply.FormatSettingsDiff(
+			diff.TopicSettings,
+			diff.ClusterSettings,
+			diff.SettingsDiffKeys,
+		)
+		if err != nil {
+			return true, err
+		}
+		c.printer(
+			"Settings with different values (%d):\n%s",
+			len(diff.SettingsDiffKeys),
+			diffsTable,
+		)
+	}
+
+	if len(diff.MissingKeys) > 0 {
+		c.printer(
+			"Settings set in cluster but missing from config (%d); these are left as-is by apply:\n%s",
+			len(diff.MissingKeys),
+			apply.FormatMissingKeys(diff.ClusterSettings, diff.MissingKeys),
+		)
+	}
+
+	c.printACLDiffs(diff)
+
+	if diff.CurrReplication != diff.DesiredReplication {
+		c.printer(
+			"Replication in topic config (%d) is not equal to observed max ISR (%d); this cannot be resolved by topicctl",
+			diff.DesiredReplication,
+			diff.CurrReplication,
+		)
+	}
+
+	if diff.CurrPartitions > diff.DesiredPartitions {
+		c.printer(
+			"Fewer partitions in topic config (%d) than observed (%d); this cannot be resolved by topicctl",
+			diff.DesiredPartitions,
+			diff.CurrPartitions,
+		)
+	} else if diff.CurrPartitions < diff.DesiredPartitions {
+		c.printer(
+			"Partition count would be increased from %d to %d",
+			diff.CurrPartitions,
+			diff.DesiredPartitions,
+		)
+	}
+
+	currChanged, desiredChanged := diff.ChangedAssignments()
+	if len(desiredChanged) > 0 {
+		brokers, err := c.adminClient.GetBrokers(ctx, nil)
+		if err != nil {
+			return true, err
+		}
+
+		c.printer(
+			"Partition placement changes for '%s' strategy (%d partition(s)):\n%s",
+			topicConfig.Spec.PlacementConfig.Strategy,
+			len(desiredChanged),
+			admin.FormatAssignentDiffs(currChanged, desiredChanged, brokers),
+		)
+	}
+
+	if len(diff.WrongLeaders) > 0 {
+		c.printer(
+			"Partitions whose leader isn't the preferred one (%d): %+v",
+			len(diff.WrongLeaders),
+			diff.WrongLeaders,
+		)
+	}
+
+	return true, nil
+}
+
+// printACLDiffs prints out the ACLs that an apply would create or delete for the topic in the
+// argument diff, if there are any.
+func (c *CLIRunner) printACLDiffs(diff apply.TopicDiff) {
+	if !diff.HasACLDiffs() {
+		return
+	}
+
+	c.printer(
+		"ACLs to create (%d) and delete (%d):\n%s",
+		len(diff.MissingACLs),
+		len(diff.ExtraACLs),
+		apply.FormatACLDiffs(diff.MissingACLs, diff.ExtraACLs),
+	)
+}
+
+// GetACLs fetches the ACLs in the cluster that match the argument filter and prints them out.
+func (c *CLIRunner) GetACLs(
+	ctx context.Context,
+	filter admin.ACLFilter,
+) error {
+	kafkaFilter, err := filter.KafkaFilter()
+	if err != nil {
+		return err
+	}
+
+	c.startSpinner()
+	acls, err := c.adminClient.GetACLs(ctx, kafkaFilter)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if c.structured() {
+		return c.printStructured(acls, acls)
+	}
+
+	c.printer("ACLs (%d):\n%s", len(acls), admin.FormatACLs(acls))
+	return nil
+}
+
+// CreateACLs creates the argument ACLs after getting confirmation from the user. ACLs that
+// already exist are skipped, so running the same command twice is a no-op.
+func (c *CLIRunner) CreateACLs(
+	ctx context.Context,
+	acls []admin.ACLInfo,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	c.startSpinner()
+
+	newACLs := []admin.ACLInfo{}
+	for _, acl := range acls {
+		existing, err := c.adminClient.GetACLs(ctx, acl.Filter())
+		if err != nil {
+			c.stopSpinner()
+			return err
+		}
+		if len(existing) > 0 {
+			log.Infof("ACL already exists, skipping: %s", acl)
+			continue
+		}
+		newACLs = append(newACLs, acl)
+	}
+	c.stopSpinner()
+
+	if len(newACLs) == 0 {
+		c.printer("All %d ACL(s) already exist", len(acls))
+		return nil
+	}
+
+	c.printer("ACLs to create (%d):\n%s", len(newACLs), admin.FormatACLs(newACLs))
+
+	if dryRun {
+		c.printer("Skipping create because dry-run is set")
+		return nil
+	}
+
+	ok, _ := apply.Confirm(
+		fmt.Sprintf("OK to create %d ACL(s)?", len(newACLs)),
+		skipConfirm,
+	)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	err := c.adminClient.CreateACLs(ctx, newACLs)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	c.printer("Created %d ACL(s)", len(newACLs))
+	return nil
+}
+
+// DeleteACLs deletes the ACLs that match the argument filter after getting confirmation from
+// the user. If no ACLs match, then nothing is done.
+func (c *CLIRunner) DeleteACLs(
+	ctx context.Context,
+	filter admin.ACLFilter,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	kafkaFilter, err := filter.KafkaFilter()
+	if err != nil {
+		return err
+	}
+
+	c.startSpinner()
+	acls, err := c.adminClient.GetACLs(ctx, kafkaFilter)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if len(acls) == 0 {
+		c.printer("No matching ACLs found")
+		return nil
+	}
+
+	c.printer("ACLs to delete (%d):\n%s", len(acls), admin.FormatACLs(acls))
+
+	if dryRun {
+		c.printer("Skipping delete because dry-run is set")
+		return nil
+	}
+
+	ok, _ := apply.Confirm(
+		fmt.Sprintf("OK to delete %d ACL(s)?", len(acls)),
+		skipConfirm,
+	)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	deleted, err := c.adminClient.DeleteACLs(ctx, kafkaFilter)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	c.printer("Deleted %d ACL(s)", len(deleted))
+	return nil
+}
+
+// GetQuotas fetches all of the user and client ID quotas in the cluster and prints them out.
+func (c *CLIRunner) GetQuotas(ctx context.Context) error {
+	c.startSpinner()
+	quotas, err := c.adminClient.GetQuotas(ctx)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if c.structured() {
+		return c.printStructured(quotas, quotas)
+	}
+
+	c.printer("Quotas (%d):\n%s", len(quotas), admin.FormatQuotas(quotas))
+	return nil
+}
+
+// GenerateReport builds a report on the state of the cluster and managed topics in the argument
+// config and writes it in the argument format to outputPath, or to stdout if that's empty.
+func (c *CLIRunner) GenerateReport(
+	ctx context.Context,
+	buildConfig report.BuildConfig,
+	format report.OutputFormat,
+	outputPath string,
+) error {
+	c.startSpinner()
+	clusterReport, err := report.Build(ctx, buildConfig)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	contents, err := report.Render(clusterReport, format)
+	if err != nil {
+		return err
+	}
+
+	if outputPath == "" {
+		_, err = fmt.Print(contents)
+		return err
+	}
+
+	if err := ioutil.WriteFile(outputPath, []byte(contents), 0644); err != nil {
+		return err
+	}
+	c.printer(
+		"Wrote report for cluster %s (%d drifted topic(s), %d failed check(s)) to %s",
+		clusterReport.Cluster.Name,
+		clusterReport.DriftedTopics(),
+		clusterReport.FailedChecks(),
+		outputPath,
+	)
+	return nil
+}
+
+// GetAuditLog prints out the entries in the argument audit log file that match the argument
+// filter. This doesn't need an admin client.
+func (c *CLIRunner) GetAuditLog(path string, filter audit.Filter) error {
+	entries, err := audit.ReadEntries(path, filter)
+	if err != nil {
+		return err
+	}
+
+	if c.structured() {
+		return c.printStructured(entries, entries)
+	}
+
+	c.printer(
+		"Audit log entries in %s (%d):\n%s",
+		path,
+		len(entries),
+		audit.FormatEntries(entries),
+	)
+	return nil
+}
+
+// ApplyQuotas updates the quotas in the cluster to match the ones in the argument cluster
+// config after getting confirmation from the user. Quotas for entities that aren't in the
+// config are reported but not changed.
+func (c *CLIRunner) ApplyQuotas(
+	ctx context.Context,
+	clusterConfig config.ClusterConfig,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	c.startSpinner()
+	currentQuotas, err := c.adminClient.GetQuotas(ctx)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	unmanaged := apply.UnmanagedQuotas(clusterConfig.Spec.Quotas, currentQuotas)
+	if len(unmanaged) > 0 {
+		log.Warnf(
+			"Found %d quota(s) in the cluster for entities that aren't in the cluster config; these will be left alone:\n%s",
+			len(unmanaged),
+			admin.FormatQuotas(unmanaged),
+		)
+	}
+
+	updates := apply.QuotaUpdates(clusterConfig.Spec.Quotas, currentQuotas)
+	if len(updates) == 0 {
+		c.printer("Quotas in cluster match the config; nothing to do")
+		return nil
+	}
+
+	c.printer(
+		"Found quota differences for %d entity(ies):\n%s",
+		len(updates),
+		apply.FormatQuotaUpdates(updates, currentQuotas),
+	)
+
+	if dryRun {
+		c.printer("Skipping update because dry-run is set")
+		return nil
+	}
+
+	ok, _ := apply.Confirm("OK to update quotas?", skipConfirm)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	err = c.adminClient.UpdateQuotas(ctx, updates)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	c.printer("Quotas updated successfully!")
+	return nil
+}
+
+// ApplyACLs reconciles the ACLs on the resources in an ACL config against the ones in the
+// cluster. ACLs on resources that aren't in the config are left alone. If report is set, the
+// ACL changes and the outcome of the run are added to it.
+func (c *CLIRunner) ApplyACLs(
+	ctx context.Context,
+	aclConfig config.ACLConfig,
+	dryRun bool,
+	skipConfirm bool,
+	report *apply.ApplyReport,
+) error {
+	diffs, err := c.applyACLs(ctx, aclConfig, dryRun, skipConfirm)
+	if report != nil {
+		report.AddACLResult(
+			aclConfig.Meta.Cluster,
+			aclConfig.Meta.Environment,
+			aclConfig.Meta.Name,
+			diffs,
+			err,
+		)
+	}
+	return err
+}
+
+func (c *CLIRunner) applyACLs(
+	ctx context.Context,
+	aclConfig config.ACLConfig,
+	dryRun bool,
+	skipConfirm bool,
+) ([]apply.ACLResourceDiff, error) {
+	resources, _, err := aclConfig.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	c.startSpinner()
+	currentACLs := []admin.ACLInfo{}
+	for _, resource := range resources {
+		resourceACLs, err := c.adminClient.GetACLs(ctx, resource.Filter())
+		if err != nil {
+			c.stopSpinner()
+			return nil, err
+		}
+		currentACLs = append(currentACLs, resourceACLs...)
+	}
+	c.stopSpinner()
+
+	diffs, err := apply.ACLResourceDiffs(aclConfig, currentACLs)
+	if err != nil {
+		return nil, err
+	}
+	if len(diffs) == 0 {
+		c.printer("ACLs in cluster match the config; nothing to do")
+		return diffs, nil
+	}
+
+	missingACLs := []admin.ACLInfo{}
+	extraACLs := []admin.ACLInfo{}
+	for _, diff := range diffs {
+		missingACLs = append(missingACLs, diff.MissingACLs...)
+		extraACLs = append(extraACLs, diff.ExtraACLs...)
+	}
+
+	c.printer(
+		"Found %d ACL(s) to create and %d to delete across %d resource(s):\n%s",
+		len(missingACLs),
+		len(extraACLs),
+		len(diffs),
+		apply.FormatACLResourceDiffs(diffs),
+	)
+
+	if dryRun {
+		c.printer("Skipping update because dry-run is set")
+		return diffs, nil
+	}
+
+	ok, _ := apply.Confirm("OK to update ACLs to match the config?", skipConfirm)
+	if !ok {
+		return diffs, errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	if len(missingACLs) > 0 {
+		err = c.adminClient.CreateACLs(ctx, missingACLs)
+	}
+	for _, acl := range extraACLs {
+		if err != nil {
+			break
+		}
+		_, err = c.adminClient.DeleteACLs(ctx, acl.Filter())
+	}
+	c.stopSpinner()
+	if err != nil {
+		return diffs, err
+	}
+
+	c.printer("ACLs updated successfully!")
+	return diffs, nil
+}
+
+// ApplyUsers creates or updates the SCRAM credentials in a user config. Users that aren't in the
+// config are left alone. The passwords are only resolved, from the environment or the commands
+// in the config, if the credentials are actually going to be updated.
+func (c *CLIRunner) ApplyUsers(
+	ctx context.Context,
+	userConfig config.UserConfig,
+	updatePasswords bool,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	userNames := []string{}
+	for _, user := range userConfig.Spec.Users {
+		userNames = append(userNames, user.Name)
+	}
+
+	c.startSpinner()
+	currentCredentials, err := c.adminClient.GetScramCredentials(ctx, userNames)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	changes := apply.ScramCredentialChanges(userConfig, currentCredentials, updatePasswords)
+	if len(changes) == 0 {
+		c.printer("SCRAM credentials in cluster match the config; nothing to do")
+		return nil
+	}
+
+	c.printer(
+		"Found %d SCRAM credential(s) to create or update:\n%s",
+		len(changes),
+		apply.FormatScramCredentialChanges(changes),
+	)
+
+	if dryRun {
+		c.printer("Skipping update because dry-run is set")
+		return nil
+	}
+
+	upserts := []admin.ScramCredentialUpsert{}
+	for _, change := range changes {
+		password, err := change.User.Password.Resolve(ctx)
+		if err != nil {
+			return fmt.Errorf("Could not get password for user %s: %+v", change.Desired.User, err)
+		}
+
+		upserts = append(
+			upserts,
+			admin.ScramCredentialUpsert{
+				User:       change.Desired.User,
+				Mechanism:  change.Desired.Mechanism,
+				Iterations: change.Desired.Iterations,
+				Password:   password,
+			},
+		)
+	}
+
+	ok, _ := apply.Confirm("OK to update SCRAM credentials?", skipConfirm)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	err = c.adminClient.UpsertScramCredentials(ctx, upserts)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	c.printer("SCRAM credentials updated successfully!")
+	return nil
+}
+
+// ApplyRoleBindings reconciles the Confluent RBAC role bindings of the principals in a role
+// binding config against the config, creating missing bindings and deleting extra ones. Unlike
+// the other apply methods, this goes through the argument RBAC client rather than the admin
+// client.
+func (c *CLIRunner) ApplyRoleBindings(
+	ctx context.Context,
+	rbacClient rbac.Client,
+	roleBindingConfig config.RoleBindingConfig,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	c.startSpinner()
+	currentBindings, err := rbacClient.GetRoleBindings(ctx, roleBindingConfig.PrincipalNames())
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	diffs, err := apply.RoleBindingDiffs(roleBindingConfig, currentBindings)
+	if err != nil {
+		return err
+	}
+	if len(diffs) == 0 {
+		c.printer("Role bindings in cluster match the config; nothing to do")
+		return nil
+	}
+
+	missingBindings := []rbac.RoleBinding{}
+	extraBindings := []rbac.RoleBinding{}
+	for _, diff := range diffs {
+		missingBindings = append(missingBindings, diff.MissingBindings...)
+		extraBindings = append(extraBindings, diff.ExtraBindings...)
+	}
+
+	c.printer(
+		"Found %d role binding(s) to create and %d to delete across %d principal(s):\n%s",
+		len(missingBindings),
+		len(extraBindings),
+		len(diffs),
+		apply.FormatRoleBindingDiffs(diffs),
+	)
+
+	if dryRun {
+		c.printer("Skipping update because dry-run is set")
+		return nil
+	}
+
+	ok, _ := apply.Confirm("OK to update role bindings to match the config?", skipConfirm)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	for _, binding := range missingBindings {
+		if err = rbacClient.CreateRoleBinding(ctx, binding); err != nil {
+			break
+		}
+	}
+	for _, binding := range extraBindings {
+		if err != nil {
+			break
+		}
+		err = rbacClient.DeleteRoleBinding(ctx, binding)
+	}
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	c.printer("Role bindings updated successfully!")
+	return nil
+}
+
+// GetBrokerBalance evaluates the balance of the brokers for a single topic or the cluster as a
+// whole and prints a summary out for user inspection. Unless full is set, only the most
+// imbalanced topics are shown.
+func (c *CLIRunner) GetBrokerBalance(ctx context.Context, topicName string, full bool) error {
+	c.startSpinner()
+
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	var topicNames []string
+	if topicName != "" {
+		topicNames = []string{topicName}
+	}
+
+	topics, err := c.adminClient.GetTopics(ctx, topicNames, false)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	// Sizes aren't critical, so don't fail if the brokers don't support getting them
+	replicaLogDirs, err := c.adminClient.GetReplicaLogDirs(ctx, topicNames, nil)
+	if err != nil {
+		log.Warnf("Could not get replica sizes: %+v", err)
+		replicaLogDirs = nil
+	}
+
+	c.stopSpinner()
+
+	balance := admin.GetClusterBalance(brokers, topics, replicaLogDirs)
+
+	if c.structured() {
+		return c.printStructured(balance, balance.Brokers)
+	}
+
+	c.printer("Broker replicas:\n%s", admin.FormatBrokerReplicas(brokers, topics))
+	c.printer("Broker rack replicas:\n%s", admin.FormatBrokerRackReplicas(brokers, topics))
+	c.printer("Broker load:\n%s", admin.FormatBrokerLoads(balance))
+	c.printer(
+		"Balance skew (overall score: %.1f):\n%s",
+		balance.Score(),
+		admin.FormatBalanceSkews(balance),
+	)
+
+	if len(balance.TopicImbalances) == 0 {
+		c.printer("All topics are balanced")
+		return nil
+	}
+
+	maxTopics := maxImbalancedTopics
+	if full {
+		maxTopics = 0
+	}
+	if maxTopics > 0 && len(balance.TopicImbalances) > maxTopics {
+		c.printer(
+			"Most imbalanced topics (showing %d of %d; use --full to see all):\n%s",
+			maxTopics,
+			len(balance.TopicImbalances),
+			admin.FormatTopicImbalances(balance, maxTopics),
+		)
+	} else {
+		c.printer(
+			"Imbalanced topics:\n%s",
+			admin.FormatTopicImbalances(balance, maxTopics),
+		)
+	}
+
+	return nil
+}
+
+// GetBrokerConfigs fetches the full configs of the brokers in the cluster and prints them out
+// along with any dynamic configs that differ between brokers. If brokerID is non-negative, only
+// the configs for that broker are shown, but drift is still evaluated across the cluster.
+func (c *CLIRunner) GetBrokerConfigs(ctx context.Context, brokerID int, full bool) error {
+	c.startSpinner()
+	brokerConfigs, err := c.adminClient.GetBrokerConfigs(ctx, nil)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	drifted := admin.DriftedBrokerConfigs(brokerConfigs)
+
+	displayConfigs := brokerConfigs
+	if brokerID >= 0 {
+		displayConfigs = nil
+		for _, brokerConfig := range brokerConfigs {
+			if brokerConfig.BrokerID == brokerID {
+				displayConfigs = []admin.BrokerConfigs{brokerConfig}
+				break
+			}
+		}
+		if displayConfigs == nil {
+			return fmt.Errorf("Broker %d not found in cluster", brokerID)
+		}
+	}
+
+	if c.structured() {
+		type brokerConfigRow struct {
+			BrokerID int `json:"brokerID"`
+			admin.BrokerConfigEntry
+		}
+
+		rows := []brokerConfigRow{}
+		for _, brokerConfig := range displayConfigs {
+			for _, entry := range brokerConfig.Entries {
+				rows = append(
+					rows,
+					brokerConfigRow{
+						BrokerID:          brokerConfig.BrokerID,
+						BrokerConfigEntry: entry,
+					},
+				)
+			}
+		}
+
+		return c.printStructured(
+			struct {
+				Brokers []admin.BrokerConfigs `json:"brokers"`
+				Drifted []string              `json:"drifted"`
+			}{
+				Brokers: displayConfigs,
+				Drifted: drifted,
+			},
+			rows,
+		)
+	}
+
+	c.printer(
+		"Broker configs:\n%s",
+		admin.FormatBrokerConfigs(displayConfigs, drifted, full),
+	)
+
+	if len(drifted) == 0 {
+		c.printer("No dynamic configs differ between brokers")
+	} else {
+		c.printer(
+			"Dynamic configs that differ between brokers:\n%s",
+			admin.FormatBrokerConfigDrift(brokerConfigs, drifted),
+		)
+	}
+
+	return nil
+}
+
+// GetStorage fetches the log dirs of the brokers in the cluster and prints out the disk usage
+// of each broker and log dir. If brokerID is non-negative, only that broker is included. If
+// byTopic is set, the largest topics are also shown.
+func (c *CLIRunner) GetStorage(
+	ctx context.Context,
+	brokerID int,
+	byTopic bool,
+	full bool,
+) error {
+	var brokerIDs []int
+	if brokerID >= 0 {
+		brokerIDs = []int{brokerID}
+	}
+
+	c.startSpinner()
+	brokers, err := c.adminClient.GetBrokers(ctx, brokerIDs)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+	if brokerID >= 0 && len(brokers) == 0 {
+		c.stopSpinner()
+		return fmt.Errorf("Broker %d not found in cluster", brokerID)
+	}
+
+	logDirs, err := c.adminClient.GetLogDirs(ctx, brokerIDs)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	brokerStorages := admin.GetBrokerStorage(brokers, logDirs)
+
+	if c.structured() {
+		storage := struct {
+			Brokers []admin.BrokerStorage `json:"brokers"`
+			LogDirs []admin.LogDirInfo    `json:"logDirs"`
+			Topics  []admin.TopicStorage  `json:"topics,omitempty"`
+		}{
+			Brokers: brokerStorages,
+			LogDirs: logDirs,
+		}
+		if byTopic {
+			// Topics are the primary list when they're requested, so that the CSV for capacity
+			// planning has one row per topic
+			storage.Topics = admin.GetTopicStorage(logDirs)
+			return c.printStructured(storage, storage.Topics)
+		}
+
+		return c.printStructured(storage, brokerStorageRows(brokerStorages))
+	}
+
+	c.printer(
+		"Broker storage:\n%s",
+		admin.FormatBrokerStorage(brokerStorages),
+	)
+	c.printer("Log dirs:\n%s", admin.FormatLogDirs(logDirs))
+
+	for _, logDir := range logDirs {
+		if logDir.TotalBytes == 0 && logDir.Error == "" {
+			log.Info("Disk totals are only reported by brokers running Kafka 3.3 or later")
+			break
+		}
+	}
+
+	if !byTopic {
+		return nil
+	}
+
+	topicStorages := admin.GetTopicStorage(logDirs)
+	maxTopics := maxStorageTopics
+	if full {
+		maxTopics = 0
+	}
+	if maxTopics > 0 && len(topicStorages) > maxTopics {
+		c.printer(
+			"Largest topics (showing %d of %d; use --full to see all):\n%s",
+			maxTopics,
+			len(topicStorages),
+			admin.FormatTopicStorage(topicStorages, maxTopics),
+		)
+	} else {
+		c.printer(
+			"Topics:\n%s",
+			admin.FormatTopicStorage(topicStorages, maxTopics),
+		)
+	}
+
+	return nil
+}
+
+// GetConfig fetches the config for a broker or topic and prints it out for user inspection.
+func (c *CLIRunner) GetConfig(ctx context.Context, brokerOrTopic string) error {
+	c.startSpinner()
+
+	brokerIDs, err := c.adminClient.GetBrokerIDs(ctx)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	topicNames, err := c.adminClient.GetTopicNames(ctx)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	for _, brokerID := range brokerIDs {
+		if fmt.Sprintf("%d", brokerID) == brokerOrTopic {
+			brokers, err := c.adminClient.GetBrokers(ctx, []int{brokerID})
+			if err != nil {
+				c.stopSpinner()
+				return err
+			}
+			if len(brokers) != 1 {
+				c.stopSpinner()
+				return fmt.Errorf("Could not find broker %d", brokerID)
+			}
+			c.stopSpinner()
+
+			if c.structured() {
+				return c.printStructured(brokers[0].Config, configRows(brokers[0].Config))
+			}
+
+			c.printer(
+				"Config for broker %d:\n%s",
+				brokerID,
+				admin.FormatConfig(brokers[0].Config),
+			)
+			return nil
+		}
+	}
+
+	for _, topicName := range topicNames {
+		if topicName == brokerOrTopic {
+			topics, err := c.adminClient.GetTopics(ctx, []string{topicName}, false)
+			if err != nil {
+				c.stopSpinner()
+				return err
+			}
+			if len(topics) != 1 {
+				c.stopSpinner()
+				return fmt.Errorf("Could not find topic %s", topicName)
+			}
+			c.stopSpinner()
+
+			if c.structured() {
+				return c.printStructured(topics[0].Config, configRows(topics[0].Config))
+			}
+
+			c.printer(
+				"Config for topic %s:\n%s",
+				topicName,
+				admin.FormatConfig(topics[0].Config),
+			)
+			return nil
+		}
+	}
+
+	c.stopSpinner()
+	return fmt.Errorf("Could not find broker or topic named %s", brokerOrTopic)
+}
+
+// GetGroups fetches all consumer groups, or just the ones consuming the argument topic if it's
+// set, and prints them out for user inspection.
+func (c *CLIRunner) GetGroups(ctx context.Context, topic string) error {
+	c.startSpinner()
+
+	groupSummaries, err := groups.GetGroupSummaries(ctx, c.adminClient.GetConnector(), topic)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if c.structured() {
+		return c.printStructured(groupSummaries, groupSummaries)
+	}
+
+	if topic != "" {
+		c.printer(
+			"Groups consuming topic %s:\n%s",
+			topic,
+			groups.FormatGroupSummaries(groupSummaries),
+		)
+	} else {
+		c.printer("Groups:\n%s", groups.FormatGroupSummaries(groupSummaries))
+	}
+	return nil
+}
+
+// GetGroupMembers fetches and prints out information about every member in a consumer group.
+func (c *CLIRunner) GetGroupMembers(ctx context.Context, groupID string, full bool) error {
+	c.startSpinner()
+
+	groupDetails, err := groups.GetGroupDetails(
+		ctx,
+		c.adminClient.GetConnector(),
+		groupID,
+	)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if c.structured() {
+		return c.printStructured(groupDetails, groupDetails.Members)
 	}
 
 	c.printer("Group state: %s", groupDetails.State)
+	if groupDetails.AssignmentStrategy != "" {
+		c.printer(
+			"Assignment strategy: %s (protocol type: %s)",
+			groupDetails.AssignmentStrategy,
+			groupDetails.ProtocolType,
+		)
+	}
+	c.printer(
+		"Group members (%d):\n%s",
+		len(groupDetails.Members),
+		groups.FormatGroupMembers(groupDetails.Members, full),
+	)
+	c.printer(
+		"Member frequency by partition count:\n%s",
+		groups.FormatMemberPartitionCounts(groupDetails.Members),
+	)
+	c.printStaticMembership(*groupDetails)
+
+	return nil
+}
+
+// printStaticMembership prints how many of the members in the argument group use static
+// membership, and warns if the group mixes static and dynamic members.
+func (c *CLIRunner) printStaticMembership(groupDetails groups.GroupDetails) {
+	staticMembers := groupDetails.StaticMembers()
+	if staticMembers == 0 {
+		return
+	}
+
+	c.printer(
+		"Static members (with group.instance.id): %d of %d",
+		staticMembers,
+		len(groupDetails.Members),
+	)
+	if groupDetails.MixedMembership() {
+		log.Warnf(
+			"Group %s mixes static and dynamic members; restarts of the %d dynamic member(s) trigger rebalances and can cause confusing assignments",
+			groupDetails.GroupID,
+			len(groupDetails.Members)-staticMembers,
+		)
+	}
+}
+
+// GetMemberLags fetches and prints a summary of the consumer group lag for each partition
+// in a single topic.
+func (c *CLIRunner) GetMemberLags(
+	ctx context.Context,
+	topic string,
+	groupID string,
+	full bool,
+	sortByValues bool,
+) error {
+	c.startSpinner()
+
+	// Check that topic exists before getting offsets; otherwise, the topic get
+	// created as part of the lag check.
+	_, err := c.adminClient.GetTopic(ctx, topic, false)
+	if err != nil {
+		c.stopSpinner()
+		return fmt.Errorf("Error fetching topic info: %+v", err)
+	}
+
+	memberLags, err := groups.GetMemberLags(
+		ctx,
+		c.adminClient.GetConnector(),
+		topic,
+		groupID,
+	)
+	c.stopSpinner()
+
+	if err != nil {
+		return err
+	}
+
+	if sortByValues {
+		sort.Slice(memberLags, func(a, b int) bool {
+			return memberLags[a].TimeLag() < memberLags[b].TimeLag()
+		})
+	}
+
+	if c.structured() {
+		type memberLagRow struct {
+			groups.MemberPartitionLag
+			OffsetLag      int64   `json:"offsetLag"`
+			TimeLagSeconds float64 `json:"timeLagSeconds"`
+		}
+
+		rows := []memberLagRow{}
+		for _, memberLag := range memberLags {
+			rows = append(
+				rows,
+				memberLagRow{
+					MemberPartitionLag: memberLag,
+					OffsetLag:          memberLag.OffsetLag(),
+					TimeLagSeconds:     memberLag.TimeLag().Seconds(),
+				},
+			)
+		}
+		return c.printStructured(rows, rows)
+	}
+
+	c.printer(
+		"Group member lags:\n%s",
+		groups.FormatMemberLags(memberLags, full),
+	)
+	return nil
+}
+
+// GetGroupLags fetches and prints a summary of the lag for each topic partition that a
+// consumer group has committed offsets for. If total is set, then the per-topic and overall
+// totals are also printed. If rateWindow is positive, the lags are sampled a second time after
+// that long, and the resulting consume and produce rates are printed along with the estimated
+// time for the group to catch up. If maxLag is non-negative, then an error is returned if the
+// total lag exceeds it.
+func (c *CLIRunner) GetGroupLags(
+	ctx context.Context,
+	groupID string,
+	total bool,
+	maxLag int64,
+	rateWindow time.Duration,
+) error {
+	c.startSpinner()
+	partitionLags, err := groups.GetGroupLags(ctx, c.adminClient.GetConnector(), groupID)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	var lagRates []groups.LagRate
+	if rateWindow > 0 && len(partitionLags) > 0 {
+		if !c.structured() {
+			c.printer("Sampling lags again in %s to estimate rates", rateWindow)
+		}
+		c.startSpinner()
+		firstLags := partitionLags
+
+		select {
+		case <-ctx.Done():
+			c.stopSpinner()
+			return ctx.Err()
+		case <-time.After(rateWindow):
+		}
+
+		partitionLags, err = groups.GetGroupLags(ctx, c.adminClient.GetConnector(), groupID)
+		c.stopSpinner()
+		if err != nil {
+			return err
+		}
+		lagRates = groups.EstimateLagRates(firstLags, partitionLags, rateWindow)
+	}
+
+	if c.structured() {
+		type partitionLagRow struct {
+			groups.PartitionLag
+			Lag int64 `json:"lag"`
+		}
+
+		rows := []partitionLagRow{}
+		for _, partitionLag := range partitionLags {
+			rows = append(
+				rows,
+				partitionLagRow{
+					PartitionLag: partitionLag,
+					Lag:          partitionLag.Lag(),
+				},
+			)
+		}
+
+		if lagRates != nil {
+			type lagRateRow struct {
+				groups.LagRate
+				WindowSeconds  float64  `json:"windowSeconds"`
+				ConsumeRate    float64  `json:"consumeRate"`
+				ProduceRate    float64  `json:"produceRate"`
+				CatchUpSeconds *float64 `json:"catchUpSeconds"`
+			}
+
+			rateRows := []lagRateRow{}
+			for _, lagRate := range lagRates {
+				rateRow := lagRateRow{
+					LagRate:       lagRate,
+					WindowSeconds: lagRate.Window.Seconds(),
+					ConsumeRate:   lagRate.ConsumeRate(),
+					ProduceRate:   lagRate.ProduceRate(),
+				}
+				if catchUpTime, ok := lagRate.CatchUpTime(); ok {
+					catchUpSeconds := catchUpTime.Seconds()
+					rateRow.CatchUpSeconds = &catchUpSeconds
+				}
+				rateRows = append(rateRows, rateRow)
+			}
+
+			// Use a wrapper so that the output is still a single document
+			value := struct {
+				Partitions []partitionLagRow `json:"partitions"`
+				Rates      []lagRateRow      `json:"rates"`
+			}{
+				Partitions: rows,
+				Rates:      rateRows,
+			}
+			if err := c.printStructured(value, rateRows); err != nil {
+				return err
+			}
+		} else if err := c.printStructured(rows, rows); err != nil {
+			return err
+		}
+	} else if len(partitionLags) == 0 {
+		c.printer("Group %s has no committed offsets", groupID)
+		return nil
+	} else {
+		c.printer(
+			"Lags for group %s:\n%s",
+			groupID,
+			groups.FormatPartitionLags(partitionLags),
+		)
+
+		if total {
+			c.printer(
+				"Lag totals for group %s:\n%s",
+				groupID,
+				groups.FormatPartitionLagTotals(partitionLags),
+			)
+		}
+
+		if lagRates != nil {
+			c.printer(
+				"Lag rates for group %s over %s:\n%s",
+				groupID,
+				rateWindow,
+				groups.FormatLagRates(lagRates),
+			)
+		}
+	}
+
+	if totalLag := groups.TotalLag(partitionLags); maxLag >= 0 && totalLag > maxLag {
+		return fmt.Errorf(
+			"Total lag for group %s (%d) is greater than the max lag (%d)",
+			groupID,
+			totalLag,
+			maxLag,
+		)
+	}
+
+	return nil
+}
+
+// GetReassignments fetches the in-progress partition reassignments in the cluster, or just
+// those for the argument topic if it's set, and prints out their estimated progress.
+func (c *CLIRunner) GetReassignments(ctx context.Context, topic string) error {
+	c.startSpinner()
+
+	reassignments, err := c.adminClient.GetPartitionReassignments(ctx, topic)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	topicNames := []string{}
+	topicNamesMap := map[string]struct{}{}
+	for _, reassignment := range reassignments {
+		if _, ok := topicNamesMap[reassignment.Topic]; !ok {
+			topicNames = append(topicNames, reassignment.Topic)
+			topicNamesMap[reassignment.Topic] = struct{}{}
+		}
+	}
+
+	var topics []admin.TopicInfo
+	var replicaLogDirs []admin.ReplicaLogDirInfo
+
+	if len(topicNames) > 0 {
+		topics, err = c.adminClient.GetTopics(ctx, topicNames, false)
+		if err != nil {
+			c.stopSpinner()
+			return err
+		}
+
+		// Sizes aren't critical, so don't fail if the brokers don't support getting them
+		replicaLogDirs, err = c.adminClient.GetReplicaLogDirs(ctx, topicNames, nil)
+		if err != nil {
+			log.Warnf("Could not get replica sizes: %+v", err)
+			replicaLogDirs = nil
+		}
+	}
+	c.stopSpinner()
+
+	progresses := admin.GetReassignmentProgress(reassignments, topics, replicaLogDirs)
+
+	if c.structured() {
+		type reassignmentProgressRow struct {
+			admin.ReassignmentProgress
+			RemainingBytes int64   `json:"remainingBytes"`
+			Fraction       float64 `json:"fraction"`
+		}
+
+		rows := []reassignmentProgressRow{}
+		for _, progress := range progresses {
+			rows = append(
+				rows,
+				reassignmentProgressRow{
+					ReassignmentProgress: progress,
+					RemainingBytes:       progress.RemainingBytes(),
+					Fraction:             progress.Fraction(),
+				},
+			)
+		}
+		return c.printStructured(rows, rows)
+	}
+
+	if len(progresses) == 0 {
+		c.printer("No partition reassignments in progress")
+		return nil
+	}
+
+	c.printer(
+		"Partition reassignments (%d partition(s) in %d topic(s); * = in sync):\n%s",
+		len(progresses),
+		len(topicNames),
+		admin.FormatReassignmentProgress(progresses),
+	)
+	return nil
+}
+
+// CancelReassignments cancels the in-progress partition reassignments for the argument topic,
+// or all topics if topic is empty, and then removes the throttles that were set for them.
+// Broker throttles are only removed once there are no reassignments or throttled topics left
+// in the cluster, since they're shared by all of the topics on each broker.
+func (c *CLIRunner) CancelReassignments(
+	ctx context.Context,
+	topic string,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	c.startSpinner()
+	reassignments, err := c.adminClient.GetPartitionReassignments(ctx, topic)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if len(reassignments) == 0 {
+		c.printer("No partition reassignments in progress")
+		return nil
+	}
+
+	topicNames := []string{}
+	topicNamesMap := map[string]struct{}{}
+	for _, reassignment := range reassignments {
+		if _, ok := topicNamesMap[reassignment.Topic]; !ok {
+			topicNames = append(topicNames, reassignment.Topic)
+			topicNamesMap[reassignment.Topic] = struct{}{}
+		}
+	}
+
+	c.printer(
+		"Partition reassignments to cancel (%d partition(s) in %d topic(s)):\n%s",
+		len(reassignments),
+		len(topicNames),
+		admin.FormatPartitionReassignments(reassignments),
+	)
+
+	if dryRun {
+		c.printer("Skipping cancel because dry-run is set")
+		return nil
+	}
+
+	ok, _ := apply.Confirm(
+		fmt.Sprintf("OK to cancel %d partition reassignment(s)?", len(reassignments)),
+		skipConfirm,
+	)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	err = c.adminClient.CancelPartitionReassignments(ctx, reassignments)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+	c.printer("Cancelled %d partition reassignment(s)", len(reassignments))
+
+	return c.removeReassignmentThrottles(ctx, topicNames)
+}
+
+// removeReassignmentThrottles removes the throttles from the argument topics and, if nothing
+// else in the cluster is still being reassigned or throttled, from the brokers.
+func (c *CLIRunner) removeReassignmentThrottles(
+	ctx context.Context,
+	topicNames []string,
+) error {
+	c.startSpinner()
+	topics, err := c.adminClient.GetTopics(ctx, topicNames, false)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	var throttleErr error
+
+	for _, topicInfo := range topics {
+		if !topicInfo.IsThrottled() {
+			continue
+		}
+
+		c.printer("Removing throttles from topic %s", topicInfo.Name)
+		_, err := c.adminClient.UpdateTopicConfig(
+			ctx,
+			topicInfo.Name,
+			admin.ClearedPartitionThrottleConfigEntries(),
+			true,
+		)
+		if err != nil {
+			log.Warnf("Error removing throttles from topic %s: %+v", topicInfo.Name, err)
+			throttleErr = multierror.Append(throttleErr, err)
+		}
+	}
+
+	c.startSpinner()
+	remainingReassignments, err := c.adminClient.GetPartitionReassignments(ctx, "")
+	if err != nil {
+		c.stopSpinner()
+		return multierror.Append(throttleErr, err)
+	}
+	allTopics, err := c.adminClient.GetTopics(ctx, nil, false)
+	if err != nil {
+		c.stopSpinner()
+		return multierror.Append(throttleErr, err)
+	}
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	c.stopSpinner()
+	if err != nil {
+		return multierror.Append(throttleErr, err)
+	}
+
+	throttledTopics := admin.ThrottledTopicNames(allTopics)
+	throttledBrokers := admin.ThrottledBrokerIDs(brokers)
+
+	if len(throttledBrokers) == 0 {
+		return throttleErr
+	}
+	if len(remainingReassignments) > 0 || len(throttledTopics) > 0 {
+		c.printer(
+			"Not removing throttles from brokers %+v because other reassignments (%d) or throttled topics (%d) remain",
+			throttledBrokers,
+			len(remainingReassignments),
+			len(throttledTopics),
+		)
+		return throttleErr
+	}
+
+	for _, brokerID := range throttledBrokers {
+		c.printer("Removing throttle from broker %d", brokerID)
+		_, err := c.adminClient.UpdateBrokerConfig(
+			ctx,
+			brokerID,
+			admin.ClearedBrokerThrottleConfigEntries(),
+			true,
+		)
+		if err != nil {
+			log.Warnf("Error removing throttle from broker %d: %+v", brokerID, err)
+			throttleErr = multierror.Append(throttleErr, err)
+		}
+	}
+
+	return throttleErr
+}
+
+// ElectLeaders runs leader elections of the argument type for the partitions in the argument
+// topic, or all topics if topic is empty, that need them. Preferred elections move leadership
+// back to the first replica of each partition, e.g. after a broker restart. Unclean elections
+// bring leaderless partitions back online at the risk of losing data, so they always require a
+// typed confirmation.
+func (c *CLIRunner) ElectLeaders(
+	ctx context.Context,
+	topic string,
+	electionType admin.LeaderElectionType,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	var topicNames []string
+	if topic != "" {
+		topicNames = []string{topic}
+	}
+
+	c.startSpinner()
+	topics, err := c.adminClient.GetTopics(ctx, topicNames, false)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+	if topic != "" && len(topics) == 0 {
+		return fmt.Errorf("Topic %s not found", topic)
+	}
+
+	var partitions []admin.PartitionInfo
+
+	switch electionType {
+	case admin.LeaderElectionTypePreferred:
+		partitions = admin.PreferredLeaderElectionPartitions(topics, brokers)
+		if len(partitions) == 0 {
+			c.printer("All partitions with in-sync preferred replicas already have the preferred leader")
+			return nil
+		}
+	case admin.LeaderElectionTypeUnclean:
+		partitions = admin.UncleanLeaderElectionPartitions(topics, brokers)
+		if len(partitions) == 0 {
+			c.printer("No leaderless partitions with live replicas found")
+			return nil
+		}
+	default:
+		return fmt.Errorf("Unrecognized leader election type: %s", electionType)
+	}
+
+	c.printer(
+		"Partitions to run %s leader elections for (%d):\n%s",
+		electionType,
+		len(partitions),
+		admin.FormatLeaderElectionPartitions(partitions),
+	)
+
+	if dryRun {
+		c.printer("Skipping leader elections because dry-run is set")
+		return nil
+	}
+
+	var ok bool
+
+	if electionType == admin.LeaderElectionTypeUnclean {
+		log.Warn("Unclean leader elections can pick replicas that are out-of-sync as leaders.")
+		log.Warn("Any messages that weren't replicated to them are permanently lost, and consumers may see offsets go backwards.")
+		log.Warn("Only continue if the in-sync replicas can't be brought back in a reasonable amount of time.")
+
+		ok, _ = apply.ConfirmTyped(
+			fmt.Sprintf(
+				"Run unclean leader elections for %d partition(s), possibly losing data?",
+				len(partitions),
+			),
+			string(admin.LeaderElectionTypeUnclean),
+		)
+	} else {
+		ok, _ = apply.Confirm(
+			fmt.Sprintf("OK to run leader elections for %d partition(s)?", len(partitions)),
+			skipConfirm,
+		)
+	}
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	err = c.adminClient.ElectLeaders(ctx, electionType, partitions)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	c.printer(
+		"Triggered %s leader elections for %d partition(s); run get partitions to check the new leaders",
+		electionType,
+		len(partitions),
+	)
+	return nil
+}
+
+// GetCompat fetches the supported API versions from each broker in the cluster and prints out
+// which topicctl features are available.
+func (c *CLIRunner) GetCompat(ctx context.Context) error {
+	c.startSpinner()
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+	brokerVersions := admin.GetBrokerAPIVersions(
+		ctx,
+		c.adminClient.GetConnector().KafkaClient,
+		brokers,
+	)
+	c.stopSpinner()
+
+	results := admin.GetCompatResults(brokerVersions)
+	clientFeatures := c.adminClient.GetSupportedFeatures()
+
+	if c.structured() {
+		return c.printStructured(
+			struct {
+				Brokers        []admin.BrokerAPIVersions `json:"brokers"`
+				Features       []admin.CompatResult      `json:"features"`
+				ClientFeatures admin.SupportedFeatures   `json:"clientFeatures"`
+			}{
+				Brokers:        brokerVersions,
+				Features:       results,
+				ClientFeatures: clientFeatures,
+			},
+			results,
+		)
+	}
+
+	for _, broker := range brokerVersions {
+		if broker.Error != "" {
+			log.Warnf("Could not get API versions from broker %d: %s", broker.BrokerID, broker.Error)
+		}
+	}
+
+	c.printer(
+		"Features supported by the brokers (%d):\n%s",
+		len(brokerVersions),
+		admin.FormatCompatResults(results),
+	)
+
+	if !clientFeatures.Applies {
+		c.printer("Applying is not supported with the current client and cluster")
+	}
+	if !clientFeatures.Locks {
+		c.printer("Apply locking is not supported with the current client; it requires zookeeper access")
+	}
+
+	return nil
+}
+
+// GetHealth fetches the controller, brokers, topics, and in-progress reassignments in the
+// cluster and prints out a summary of its health. An error is returned if the cluster is
+// unhealthy.
+func (c *CLIRunner) GetHealth(ctx context.Context) error {
+	c.startSpinner()
+
+	controllerID, err := c.adminClient.GetControllerID(ctx)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	topics, err := c.adminClient.GetTopics(ctx, nil, false)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	// Older clusters don't support listing reassignments, so don't fail if this doesn't work
+	reassignments, err := c.adminClient.GetPartitionReassignments(ctx, "")
+	if err != nil {
+		log.Warnf("Could not get partition reassignments: %+v", err)
+		reassignments = nil
+	}
+	c.stopSpinner()
+
+	health := admin.GetClusterHealth(controllerID, brokers, topics, reassignments)
+
+	if c.structured() {
+		checks := health.Checks()
+		if err := c.printStructured(
+			struct {
+				admin.ClusterHealth
+				Healthy bool                `json:"healthy"`
+				Checks  []admin.HealthCheck `json:"checks"`
+			}{
+				ClusterHealth: health,
+				Healthy:       health.Healthy(),
+				Checks:        checks,
+			},
+			checks,
+		); err != nil {
+			return err
+		}
+	} else {
+		c.printer("Cluster health:\n%s", admin.FormatClusterHealth(health))
+
+		if len(health.Reassignments) > 0 {
+			c.printer(
+				"In-progress reassignments:\n%s",
+				admin.FormatPartitionReassignments(health.Reassignments),
+			)
+		}
+	}
+
+	if problems := health.Problems(); len(problems) > 0 {
+		return fmt.Errorf("Cluster is unhealthy: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// GetMetadata fetches the cluster ID, controller, and brokers in the cluster, along with the API
+// versions of each broker, and prints out a summary for quick orientation.
+func (c *CLIRunner) GetMetadata(ctx context.Context) error {
+	c.startSpinner()
+
+	clusterID, err := c.adminClient.GetClusterID(ctx)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	controllerID, err := c.adminClient.GetControllerID(ctx)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+	brokerVersions := admin.GetBrokerAPIVersions(
+		ctx,
+		c.adminClient.GetConnector().KafkaClient,
+		brokers,
+	)
+	c.stopSpinner()
+
+	metadata := admin.GetClusterMetadata(clusterID, controllerID, brokers, brokerVersions)
+
+	if c.structured() {
+		return c.printStructured(metadata, metadata.Brokers)
+	}
+
+	for _, broker := range metadata.Brokers {
+		if broker.Error != "" {
+			log.Warnf("Could not get API versions from broker %d: %s", broker.ID, broker.Error)
+		}
+	}
+
+	c.printer("Cluster metadata:\n%s", admin.FormatClusterMetadata(metadata))
+	return nil
+}
+
+// GetPartitions fetches the details of each partition in a topic, including the sizes of their
+// replicas and the times of their latest messages, and prints out a summary for user
+// inspection. The partitions are sorted by the argument key.
+func (c *CLIRunner) GetPartitions(
+	ctx context.Context,
+	topic string,
+	sortKey admin.PartitionSortKey,
+	descending bool,
+) error {
+	c.startSpinner()
+
+	topicInfo, err := c.adminClient.GetTopic(ctx, topic, true)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	partitionDetails, _, err := c.getPartitionDetails(ctx, topicInfo)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if err := admin.SortPartitionDetails(partitionDetails, sortKey, descending); err != nil {
+		return err
+	}
+
+	if c.structured() {
+		return c.printStructured(partitionDetails, partitionDetailsRows(partitionDetails))
+	}
+
+	c.printer(
+		"Partitions for topic %s:\n%s",
+		topic,
+		admin.FormatPartitionDetails(partitionDetails, brokers, time.Now()),
+	)
+
+	return nil
+}
+
+// DescribeTopic prints out a report for a single topic that combines its config overrides,
+// partition layout, throttles, in-progress reassignments, and size. If checkConfig is non-nil,
+// the topic is also checked against its config and the results are included.
+func (c *CLIRunner) DescribeTopic(
+	ctx context.Context,
+	topic string,
+	checkConfig *check.CheckConfig,
+) error {
+	c.startSpinner()
+
+	topicInfo, err := c.adminClient.GetTopic(ctx, topic, true)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	partitionDetails, replicaLogDirs, err := c.getPartitionDetails(ctx, topicInfo)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+
+	// Older clusters don't support listing reassignments, so don't fail if this doesn't work
+	reassignments, err := c.adminClient.GetPartitionReassignments(ctx, topic)
+	if err != nil {
+		log.Warnf("Could not get partition reassignments: %+v", err)
+		reassignments = nil
+	}
+
+	var checkResults *check.TopicCheckResults
+	if checkConfig != nil {
+		results, err := check.CheckTopic(ctx, *checkConfig)
+		if err != nil {
+			c.stopSpinner()
+			return err
+		}
+		checkResults = &results
+	}
+
+	c.stopSpinner()
+
+	c.printer(
+		"Topic %s:\n%s",
+		topic,
+		admin.FormatTopicSummary(topicInfo, brokers, replicaLogDirs, reassignments),
+	)
+
+	if len(topicInfo.Config) == 0 {
+		c.printer("No config overrides")
+	} else {
+		c.printer("Config overrides:\n%s", admin.FormatConfig(topicInfo.Config))
+	}
+
+	c.printer(
+		"Partitions:\n%s",
+		admin.FormatPartitionDetails(partitionDetails, brokers, time.Now()),
+	)
+
+	if topicInfo.IsThrottled() {
+		throttlesStr, err := admin.FormatTopicThrottles(topicInfo, brokers)
+		if err != nil {
+			return err
+		}
+		c.printer("Throttles:\n%s", throttlesStr)
+	}
+
+	if len(reassignments) > 0 {
+		c.printer(
+			"Reassignments in progress:\n%s",
+			admin.FormatPartitionReassignments(reassignments),
+		)
+	}
+
+	if checkResults == nil {
+		c.printer("No topic config found, so checks were skipped")
+	} else if checkResults.AllOK() {
+		c.printer("All checks passed for config of topic %s", topic)
+	} else {
+		c.printer("Check results:\n%s", check.FormatResults(*checkResults))
+	}
+
+	return nil
+}
+
+// DescribeGroup prints out a report for a single consumer group that combines its state,
+// protocol, members, and how evenly their partitions are assigned. If sampleWindow is positive,
+// the group is sampled again after that long and any membership or assignment changes in between
+// are flagged as a sign of frequent rebalances; the DescribeGroups API doesn't expose the group's
+// generation, so that's the closest available proxy for generation churn.
+func (c *CLIRunner) DescribeGroup(
+	ctx context.Context,
+	groupID string,
+	sampleWindow time.Duration,
+) error {
+	c.startSpinner()
+
+	groupDetails, err := groups.GetGroupDetails(ctx, c.adminClient.GetConnector(), groupID)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+	if groupDetails.State == "Dead" {
+		c.stopSpinner()
+		return fmt.Errorf("Group %s does not exist", groupID)
+	}
+
+	topicPartitions := map[string]int{}
+	for topic := range groupDetails.TopicsMap() {
+		topicInfo, err := c.adminClient.GetTopic(ctx, topic, false)
+		if err != nil {
+			log.Warnf("Could not get partitions of topic %s: %+v", topic, err)
+			continue
+		}
+		topicPartitions[topic] = len(topicInfo.Partitions)
+	}
+	c.stopSpinner()
+
+	c.printer("Group %s state: %s", groupID, groupDetails.State)
+	if groupDetails.ProtocolType != "" {
+		c.printer(
+			"Assignment strategy: %s (protocol type: %s)",
+			groupDetails.AssignmentStrategy,
+			groupDetails.ProtocolType,
+		)
+	}
+
+	if len(groupDetails.Members) == 0 {
+		c.printer("Group has no members")
+		return nil
+	}
+
+	c.printer(
+		"Group members (%d):\n%s",
+		len(groupDetails.Members),
+		groups.FormatGroupMembers(groupDetails.Members, false),
+	)
+	c.printStaticMembership(*groupDetails)
+
+	assignments := groups.TopicAssignments(*groupDetails, topicPartitions)
+	c.printer("Topic assignments:\n%s", groups.FormatTopicAssignments(assignments))
+
+	for _, warning := range groups.AssignmentWarnings(*groupDetails, topicPartitions) {
+		c.printer("Assignment warning: %s", warning)
+	}
+
+	for _, assignment := range assignments {
+		if assignment.Uneven() {
+			c.printer(
+				"Partitions of topic %s are unevenly assigned: members have between %d and %d each",
+				assignment.Topic,
+				assignment.MinPerMember,
+				assignment.MaxPerMember,
+			)
+		}
+		if unassigned := assignment.Unassigned(); unassigned > 0 {
+			c.printer(
+				"%d partition(s) of topic %s are not assigned to any member",
+				unassigned,
+				assignment.Topic,
+			)
+		}
+	}
+
+	if sampleWindow <= 0 {
+		return nil
+	}
+
+	c.printer("Sampling group again in %s to check for rebalances", sampleWindow)
+	c.startSpinner()
+
+	select {
+	case <-ctx.Done():
+		c.stopSpinner()
+		return ctx.Err()
+	case <-time.After(sampleWindow):
+	}
+
+	secondDetails, err := groups.GetGroupDetails(ctx, c.adminClient.GetConnector(), groupID)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	changes := groups.CompareMembers(*groupDetails, *secondDetails)
+	rebalancing := groups.IsRebalancing(groupDetails.State) ||
+		groups.IsRebalancing(secondDetails.State)
+
+	if changes.Empty() && !rebalancing {
+		c.printer("No rebalances detected in %s", sampleWindow)
+		return nil
+	}
+
+	c.printer("Group %s rebalanced or was rebalancing within %s:", groupID, sampleWindow)
+	if rebalancing {
+		c.printer("  State went from %s to %s", groupDetails.State, secondDetails.State)
+	}
+	if len(changes.Joined) > 0 {
+		c.printer("  Members joined: %s", strings.Join(changes.Joined, ", "))
+	}
+	if len(changes.Left) > 0 {
+		c.printer("  Members left: %s", strings.Join(changes.Left, ", "))
+	}
+	if len(changes.Reassigned) > 0 {
+		c.printer("  Members reassigned: %s", strings.Join(changes.Reassigned, ", "))
+	}
+
+	return nil
+}
+
+// GetOffsets fetches details about all partition offsets in a single topic and prints out
+// a summary. If atTime is non-nil, the offsets in each partition at that time are also
+// printed out.
+func (c *CLIRunner) GetOffsets(ctx context.Context, topic string, atTime *time.Time) error {
+	c.startSpinner()
+
+	// Check that topic exists before getting offsets; otherwise, the topic might
+	// be created as part of the bounds check.
+	topicInfo, err := c.adminClient.GetTopic(ctx, topic, false)
+	if err != nil {
+		c.stopSpinner()
+		return fmt.Errorf("Error fetching topic info: %+v", err)
+	}
+
+	bounds, err := messages.GetAllPartitionBounds(
+		ctx,
+		c.adminClient.GetConnector(),
+		topic,
+		nil,
+	)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if c.structured() {
+		return c.printStructuredOffsets(ctx, topicInfo, bounds, atTime)
+	}
+
+	c.printer(
+		"Partition bounds for topic %s:\n%s",
+		topic,
+		messages.FormatBounds(bounds),
+	)
+
+	formattedTotals := messages.FormatBoundTotals(bounds)
+
+	if formattedTotals != "" {
+		c.printer(
+			"Total bounds for topic %s across all partitions:\n%s",
+			topic,
+			formattedTotals,
+		)
+	}
+
+	if atTime != nil {
+		c.startSpinner()
+		timeOffsets, err := messages.GetTimeOffsets(
+			ctx,
+			c.adminClient.GetConnector(),
+			topic,
+			topicInfo.PartitionIDs(),
+			*atTime,
+		)
+		c.stopSpinner()
+		if err != nil {
+			return err
+		}
+
+		c.printer(
+			"Offsets for topic %s at %s:\n%s",
+			topic,
+			atTime.Format(time.RFC3339),
+			messages.FormatTimeOffsets(timeOffsets),
+		)
+	}
+
+	return nil
+}
+
+// printStructuredOffsets prints out the bounds of each partition in the argument topic and, if
+// atTime is non-nil, the offset in each partition at that time.
+func (c *CLIRunner) printStructuredOffsets(
+	ctx context.Context,
+	topicInfo admin.TopicInfo,
+	bounds []messages.Bounds,
+	atTime *time.Time,
+) error {
+	if atTime == nil {
+		return c.printStructured(bounds, bounds)
+	}
+
+	c.startSpinner()
+	timeOffsets, err := messages.GetTimeOffsets(
+		ctx,
+		c.adminClient.GetConnector(),
+		topicInfo.Name,
+		topicInfo.PartitionIDs(),
+		*atTime,
+	)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	type offsetsRow struct {
+		messages.Bounds
+		TimeOffset int64 `json:"timeOffset"`
+	}
+
+	rows := []offsetsRow{}
+	for _, bound := range bounds {
+		row := offsetsRow{Bounds: bound, TimeOffset: -1}
+		for _, timeOffset := range timeOffsets {
+			if timeOffset.Partition == bound.Partition {
+				row.TimeOffset = timeOffset.Offset
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return c.printStructured(
+		struct {
+			Bounds      []messages.Bounds     `json:"bounds"`
+			TimeOffsets []messages.TimeOffset `json:"timeOffsets"`
+		}{
+			Bounds:      bounds,
+			TimeOffsets: timeOffsets,
+		},
+		rows,
+	)
+}
+
+// GetTopics fetches the details of each topic in the cluster and prints out a summary. Only
+// the topics that match the argument filter are included.
+func (c *CLIRunner) GetTopics(
+	ctx context.Context,
+	full bool,
+	filter admin.TopicFilter,
+) error {
+	c.startSpinner()
+
+	topics, err := c.adminClient.GetTopics(ctx, nil, false)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+	brokers, err := c.adminClient.GetBrokers(ctx, nil)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	topics = admin.FilterTopics(topics, filter)
+
+	if c.structured() {
+		return c.printStructured(topics, topics)
+	}
+
+	c.printer("Topics:\n%s", admin.FormatTopics(topics, brokers, full))
+
+	return nil
+}
+
+// DeleteTopic deletes a topic after verifying that no consumer groups have been active in it
+// during the argument window and getting a typed confirmation from the user.
+func (c *CLIRunner) DeleteTopic(
+	ctx context.Context,
+	topic string,
+	consumerWindow time.Duration,
+) error {
+	c.startSpinner()
+
+	_, err := c.adminClient.GetTopic(ctx, topic, false)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+	topicConsumers, err := groups.GetTopicConsumers(
+		ctx,
+		c.adminClient.GetConnector(),
+		topic,
+	)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	activeConsumers := []groups.TopicConsumer{}
+	for _, topicConsumer := range topicConsumers {
+		if topicConsumer.ActiveSince(now.Add(-consumerWindow)) {
+			activeConsumers = append(activeConsumers, topicConsumer)
+		}
+	}
+
+	if len(activeConsumers) > 0 {
+		c.printer(
+			"Consumer groups active in topic %s:\n%s",
+			topic,
+			groups.FormatTopicConsumers(activeConsumers, now),
+		)
+		return fmt.Errorf(
+			"Not deleting topic %s because %d consumer group(s) have been active in it in the last %s",
+			topic,
+			len(activeConsumers),
+			consumerWindow,
+		)
+	}
+
+	if len(topicConsumers) > 0 {
+		c.printer(
+			"Inactive consumer groups with offsets in topic %s:\n%s",
+			topic,
+			groups.FormatTopicConsumers(topicConsumers, now),
+		)
+	}
+
+	ok, _ := apply.ConfirmTyped(
+		fmt.Sprintf("Permanently delete topic %s and all of its data?", topic),
+		topic,
+	)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	err = c.adminClient.DeleteTopic(ctx, topic)
+	c.stopSpinner()
+	if err == admin.ErrTopicDeletionDisabled {
+		return fmt.Errorf(
+			"Could not delete topic %s: %+v; set delete.topic.enable=true on the brokers to allow deletions",
+			topic,
+			err,
+		)
+	} else if err != nil {
+		return err
+	}
+
+	c.printer("Deleted topic %s", topic)
+
+	return nil
+}
+
+// DeleteGroup deletes a consumer group or, if topics are set, only its committed offsets in those
+// topics. Groups with members that would be affected are refused unless force is set; brokers
+// reject deleting groups with members and offsets in topics that the members are subscribed to,
+// so force is only useful for members that are about to leave.
+func (c *CLIRunner) DeleteGroup(
+	ctx context.Context,
+	groupID string,
+	topics []string,
+	force bool,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	c.startSpinner()
+	groupDetails, err := groups.GetGroupDetails(ctx, c.adminClient.GetConnector(), groupID)
+	if err != nil {
+		c.stopSpinner()
+		return err
+	}
+	if groupDetails.State == "Dead" {
+		c.stopSpinner()
+		return fmt.Errorf("Group %s does not exist", groupID)
+	}
+	partitionLags, err := groups.GetGroupLags(ctx, c.adminClient.GetConnector(), groupID)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	members := groupDetails.Members
+	if len(topics) > 0 {
+		topicsMap := map[string]struct{}{}
+		for _, topic := range topics {
+			topicsMap[topic] = struct{}{}
+		}
+
+		topicLags := []groups.PartitionLag{}
+		for _, partitionLag := range partitionLags {
+			if _, ok := topicsMap[partitionLag.Topic]; ok {
+				topicLags = append(topicLags, partitionLag)
+			}
+		}
+		partitionLags = topicLags
+
+		// Only the members that consume from the topics block the deletion
+		topicMembers := []groups.MemberInfo{}
+		for _, member := range members {
+			for _, topic := range member.Topics() {
+				if _, ok := topicsMap[topic]; ok {
+					topicMembers = append(topicMembers, member)
+					break
+				}
+			}
+		}
+		members = topicMembers
+	}
+
+	if len(members) > 0 {
+		c.printer(
+			"Members of group %s:\n%s",
+			groupID,
+			groups.FormatGroupMembers(members, false),
+		)
+		if !force {
+			return fmt.Errorf(
+				"Not deleting from group %s because it has %d member(s); stop its consumers or use --force",
+				groupID,
+				len(members),
+			)
+		}
+		log.Warnf(
+			"Group %s has %d member(s); continuing because --force is set, but the brokers may reject the deletion",
+			groupID,
+			len(members),
+		)
+	}
+
+	if len(topics) > 0 {
+		if len(partitionLags) == 0 {
+			c.printer("Group %s doesn't have any committed offsets in topics %+v", groupID, topics)
+			return nil
+		}
+		c.printer(
+			"Committed offsets of group %s to delete:\n%s",
+			groupID,
+			groups.FormatPartitionLags(partitionLags),
+		)
+	} else if len(partitionLags) > 0 {
+		c.printer(
+			"Committed offsets of group %s, which will be deleted with it:\n%s",
+			groupID,
+			groups.FormatPartitionLags(partitionLags),
+		)
+	}
+
+	if dryRun {
+		c.printer("Skipping delete because dry-run is set")
+		return nil
+	}
+
+	var confirmMsg string
+	if len(topics) > 0 {
+		confirmMsg = fmt.Sprintf(
+			"OK to delete the offsets of group %s in %d partition(s)?",
+			groupID,
+			len(partitionLags),
+		)
+	} else {
+		confirmMsg = fmt.Sprintf("OK to delete group %s?", groupID)
+	}
+	ok, _ := apply.Confirm(confirmMsg, skipConfirm)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
+
+	c.startSpinner()
+	if len(topics) > 0 {
+		topicPartitions := map[string][]int{}
+		for _, partitionLag := range partitionLags {
+			topicPartitions[partitionLag.Topic] = append(
+				topicPartitions[partitionLag.Topic],
+				partitionLag.Partition,
+			)
+		}
+		err = groups.DeleteGroupOffsets(
+			ctx,
+			c.adminClient.GetConnector(),
+			groupID,
+			topicPartitions,
+		)
+	} else {
+		err = groups.DeleteGroup(ctx, c.adminClient.GetConnector(), groupID)
+	}
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if len(topics) > 0 {
+		c.printer("Deleted the offsets of group %s in %d partition(s)", groupID, len(partitionLags))
+	} else {
+		c.printer("Deleted group %s", groupID)
+	}
+
+	return nil
+}
+
+// CleanupGroups finds the consumer groups that are empty and haven't consumed anything in the
+// last idleFor and deletes them after getting confirmation. The IDs of the deleted groups are
+// returned, even if there's an error partway through.
+func (c *CLIRunner) CleanupGroups(
+	ctx context.Context,
+	idleFor time.Duration,
+	dryRun bool,
+	skipConfirm bool,
+) ([]string, error) {
+	now := time.Now()
+
+	c.startSpinner()
+	idleGroups, err := groups.GetIdleGroups(ctx, c.adminClient.GetConnector(), now.Add(-idleFor))
+	c.stopSpinner()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(idleGroups) == 0 {
+		c.printer("No groups have been idle for %s", util.PrettyDuration(idleFor))
+		return nil, nil
+	}
+
 	c.printer(
-		"Group members (%d):\n%s",
-		len(groupDetails.Members),
-		groups.FormatGroupMembers(groupDetails.Members, full),
+		"Groups that have been idle for %s (%d):\n%s",
+		util.PrettyDuration(idleFor),
+		len(idleGroups),
+		groups.FormatIdleGroups(idleGroups, now),
 	)
-	c.printer(
-		"Member frequency by partition count:\n%s",
-		groups.FormatMemberPartitionCounts(groupDetails.Members),
+
+	if dryRun {
+		c.printer("Skipping delete because dry-run is set")
+		return nil, nil
+	}
+
+	ok, _ := apply.Confirm(
+		fmt.Sprintf("OK to delete %d idle group(s)?", len(idleGroups)),
+		skipConfirm,
 	)
+	if !ok {
+		return nil, errors.New("Stopping because of user response")
+	}
 
-	return nil
+	deleted := []string{}
+	var deleteErrs error
+
+	for _, idleGroup := range idleGroups {
+		c.startSpinner()
+		err := groups.DeleteGroup(ctx, c.adminClient.GetConnector(), idleGroup.GroupID)
+		c.stopSpinner()
+		if err != nil {
+			deleteErrs = multierror.Append(deleteErrs, err)
+			continue
+		}
+
+		c.printer("Deleted group %s", idleGroup.GroupID)
+		deleted = append(deleted, idleGroup.GroupID)
+	}
+
+	return deleted, deleteErrs
 }
 
-// GetMemberLags fetches and prints a summary of the consumer group lag for each partition
-// in a single topic.
-func (c *CLIRunner) GetMemberLags(
+// CopyOffsets copies the committed offsets of the source group to the destination group, so that
+// consumers in the latter pick up exactly where the ones in the former left off. If topics is
+// non-empty, only the offsets in those topics are copied. The destination group can't have any
+// members since they'd override the copied offsets.
+func (c *CLIRunner) CopyOffsets(
 	ctx context.Context,
-	topic string,
-	groupID string,
-	full bool,
-	sortByValues bool,
+	fromGroupID string,
+	toGroupID string,
+	topics []string,
+	dryRun bool,
+	skipConfirm bool,
 ) error {
-	c.startSpinner()
+	if fromGroupID == toGroupID {
+		return errors.New("Source and destination groups must be different")
+	}
 
-	// Check that topic exists before getting offsets; otherwise, the topic get
-	// created as part of the lag check.
-	_, err := c.adminClient.GetTopic(ctx, topic, false)
+	connector := c.adminClient.GetConnector()
+
+	c.startSpinner()
+	fromDetails, err := groups.GetGroupDetails(ctx, connector, fromGroupID)
 	if err != nil {
 		c.stopSpinner()
-		return fmt.Errorf("Error fetching topic info: %+v", err)
+		return err
 	}
-
-	memberLags, err := groups.GetMemberLags(
-		ctx,
-		c.adminClient.GetConnector(),
-		topic,
-		groupID,
-	)
+	if fromDetails.State == "Dead" {
+		c.stopSpinner()
+		return fmt.Errorf("Group %s does not exist", fromGroupID)
+	}
+	partitionLags, err := groups.GetGroupLags(ctx, connector, fromGroupID)
 	c.stopSpinner()
-
 	if err != nil {
 		return err
 	}
 
-	if sortByValues {
-		sort.Slice(memberLags, func(a, b int) bool {
-			return memberLags[a].TimeLag() < memberLags[b].TimeLag()
-		})
+	if len(fromDetails.Members) > 0 {
+		log.Warnf(
+			"Group %s has %d member(s), so its offsets may move after they're copied; stop its consumers for an exact handoff",
+			fromGroupID,
+			len(fromDetails.Members),
+		)
 	}
 
-	c.printer(
-		"Group member lags:\n%s",
-		groups.FormatMemberLags(memberLags, full),
-	)
-	return nil
+	topicsMap := map[string]struct{}{}
+	for _, topic := range topics {
+		topicsMap[topic] = struct{}{}
+	}
+	snapshot := groups.SnapshotGroupOffsets(fromGroupID, partitionLags, topicsMap, time.Now())
+	if len(snapshot.Topics) == 0 {
+		if len(topics) > 0 {
+			c.printer(
+				"Group %s doesn't have any committed offsets in topics %+v",
+				fromGroupID,
+				topics,
+			)
+		} else {
+			c.printer("Group %s doesn't have any committed offsets", fromGroupID)
+		}
+		return nil
+	}
+
+	return c.commitSnapshotOffsets(ctx, snapshot, toGroupID, false, dryRun, skipConfirm)
 }
 
-// GetPartitions fetches the details of each partition in a topic and prints out a summary for
-// user inspection.
-func (c *CLIRunner) GetPartitions(ctx context.Context, topic string) error {
+// ExportOffsets writes a JSON snapshot of the argument group's committed offsets to outputPath,
+// or to stdout if that's empty. If topics is non-empty, only the offsets in those topics are
+// included. The snapshot can be restored later via RestoreOffsets.
+func (c *CLIRunner) ExportOffsets(
+	ctx context.Context,
+	groupID string,
+	topics []string,
+	outputPath string,
+) error {
 	c.startSpinner()
-
-	topicInfo, err := c.adminClient.GetTopic(ctx, topic, true)
+	partitionLags, err := groups.GetGroupLags(ctx, c.adminClient.GetConnector(), groupID)
+	c.stopSpinner()
 	if err != nil {
-		c.stopSpinner()
 		return err
 	}
 
-	brokers, err := c.adminClient.GetBrokers(ctx, nil)
-	c.stopSpinner()
+	topicsMap := map[string]struct{}{}
+	for _, topic := range topics {
+		topicsMap[topic] = struct{}{}
+	}
+	snapshot := groups.SnapshotGroupOffsets(groupID, partitionLags, topicsMap, time.Now())
+	if len(snapshot.Topics) == 0 {
+		return fmt.Errorf("Group %s doesn't have any committed offsets to export", groupID)
+	}
+
+	contents, err := snapshot.ToJSON()
 	if err != nil {
 		return err
 	}
 
+	if outputPath == "" {
+		_, err = fmt.Print(contents)
+		return err
+	}
+
+	if err := ioutil.WriteFile(outputPath, []byte(contents), 0644); err != nil {
+		return err
+	}
 	c.printer(
-		"Partitions for topic %s:\n%s",
-		topic,
-		admin.FormatTopicPartitions(topicInfo.Partitions, brokers),
+		"Wrote offsets of group %s in %d topic(s) to %s",
+		groupID,
+		len(snapshot.Topics),
+		outputPath,
 	)
 
 	return nil
 }
 
-// GetOffsets fetches details about all partition offsets in a single topic and prints out
-// a summary.
-func (c *CLIRunner) GetOffsets(ctx context.Context, topic string) error {
-	c.startSpinner()
+// RestoreOffsets commits the offsets in the argument snapshot to the argument group, or to the
+// group in the snapshot if that's empty. If topics is non-empty, only the offsets in those topics
+// are restored. The offsets are validated against the current bounds of each partition first; if
+// clamp is set, the ones that are out of bounds are moved to the nearest bound instead of
+// causing an error. This is useful when restoring offsets in a different cluster.
+func (c *CLIRunner) RestoreOffsets(
+	ctx context.Context,
+	snapshot groups.GroupOffsetsSnapshot,
+	groupID string,
+	topics []string,
+	clamp bool,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	if groupID == "" {
+		groupID = snapshot.GroupID
+	}
 
-	// Check that topic exists before getting offsets; otherwise, the topic might
-	// be created as part of the bounds check.
-	_, err := c.adminClient.GetTopic(ctx, topic, false)
-	if err != nil {
-		c.stopSpinner()
-		return fmt.Errorf("Error fetching topic info: %+v", err)
+	if len(topics) > 0 {
+		var err error
+		snapshot, err = snapshot.FilterTopics(topics)
+		if err != nil {
+			return err
+		}
+	}
+	if len(snapshot.Topics) == 0 {
+		return fmt.Errorf("Offsets snapshot for group %s doesn't have any topics", snapshot.GroupID)
 	}
 
-	bounds, err := messages.GetAllPartitionBounds(
-		ctx,
-		c.adminClient.GetConnector(),
-		topic,
-		nil,
+	c.printer(
+		"Restoring offsets of group %s captured at %s",
+		snapshot.GroupID,
+		snapshot.CapturedAt.Format(time.RFC3339),
 	)
-	c.stopSpinner()
+
+	return c.commitSnapshotOffsets(ctx, snapshot, groupID, clamp, dryRun, skipConfirm)
+}
+
+// commitSnapshotOffsets commits the offsets in the argument snapshot to the argument group after
+// validating them, showing a preview, and getting confirmation. The group can't have any
+// members since they'd override the new offsets.
+func (c *CLIRunner) commitSnapshotOffsets(
+	ctx context.Context,
+	snapshot groups.GroupOffsetsSnapshot,
+	groupID string,
+	clamp bool,
+	dryRun bool,
+	skipConfirm bool,
+) error {
+	connector := c.adminClient.GetConnector()
+
+	c.startSpinner()
+	groupDetails, err := groups.GetGroupDetails(ctx, connector, groupID)
 	if err != nil {
+		c.stopSpinner()
 		return err
 	}
 
-	c.printer(
-		"Partition bounds for topic %s:\n%s",
-		topic,
-		messages.FormatBounds(bounds),
-	)
+	topicStates := map[string][]groups.PartitionOffsetState{}
+	for _, topicOffsets := range snapshot.Topics {
+		partitions := []int{}
+		for partition := range topicOffsets.Offsets {
+			partitions = append(partitions, partition)
+		}
+		sort.Ints(partitions)
+
+		states, err := groups.GetPartitionOffsetStates(
+			ctx,
+			connector,
+			topicOffsets.Topic,
+			groupID,
+			partitions,
+		)
+		if err != nil {
+			c.stopSpinner()
+			return fmt.Errorf(
+				"Error getting offsets in topic %s: %+v",
+				topicOffsets.Topic,
+				err,
+			)
+		}
+		topicStates[topicOffsets.Topic] = states
+	}
+	c.stopSpinner()
 
-	formattedTotals := messages.FormatBoundTotals(bounds)
+	if len(groupDetails.Members) > 0 {
+		c.printer(
+			"Members of group %s:\n%s",
+			groupID,
+			groups.FormatGroupMembers(groupDetails.Members, false),
+		)
+		return fmt.Errorf(
+			"Not committing offsets to group %s because it has %d member(s); stop its consumers first",
+			groupID,
+			len(groupDetails.Members),
+		)
+	}
+
+	topicOffsets := map[string]map[int]int64{}
+	for _, snapshotTopic := range snapshot.Topics {
+		states := topicStates[snapshotTopic.Topic]
+		offsets := snapshotTopic.Offsets
+		if clamp {
+			offsets = groups.ClampedOffsets(states, offsets)
+		}
+		if err := groups.CheckOffsets(states, offsets); err != nil {
+			return fmt.Errorf("Invalid offsets in topic %s: %+v", snapshotTopic.Topic, err)
+		}
+		topicOffsets[snapshotTopic.Topic] = offsets
 
-	if formattedTotals != "" {
 		c.printer(
-			"Total bounds for topic %s across all partitions:\n%s",
-			topic,
-			formattedTotals,
+			"New offsets of group %s in topic %s:\n%s",
+			groupID,
+			snapshotTopic.Topic,
+			groups.FormatOffsetResets(states, offsets),
 		)
 	}
 
-	return nil
-}
+	if dryRun {
+		c.printer("Skipping commit because dry-run is set")
+		return nil
+	}
 
-// GetTopics fetches the details of each topic in the cluster and prints out a summary.
-func (c *CLIRunner) GetTopics(ctx context.Context, full bool) error {
-	c.startSpinner()
+	ok, _ := apply.Confirm(
+		fmt.Sprintf(
+			"OK to commit the offsets of group %s in %d topic(s)?",
+			groupID,
+			len(snapshot.Topics),
+		),
+		skipConfirm,
+	)
+	if !ok {
+		return errors.New("Stopping because of user response")
+	}
 
-	topics, err := c.adminClient.GetTopics(ctx, nil, false)
-	if err != nil {
+	for _, snapshotTopic := range snapshot.Topics {
+		c.startSpinner()
+		err := groups.ResetOffsets(
+			ctx,
+			connector,
+			snapshotTopic.Topic,
+			groupID,
+			topicOffsets[snapshotTopic.Topic],
+		)
 		c.stopSpinner()
-		return err
-	}
-	brokers, err := c.adminClient.GetBrokers(ctx, nil)
-	c.stopSpinner()
-	if err != nil {
-		return err
+		if err != nil {
+			return fmt.Errorf(
+				"Error committing offsets in topic %s: %+v",
+				snapshotTopic.Topic,
+				err,
+			)
+		}
+		c.printer("Committed offsets in topic %s", snapshotTopic.Topic)
 	}
 
-	c.printer("Topics:\n%s", admin.FormatTopics(topics, brokers, full))
+	c.printer("Success")
 
 	return nil
 }
@@ -509,7 +3745,69 @@ func (c *CLIRunner) ResetOffsets(
 	return nil
 }
 
-// Tail prints out a stream of the latest messages in a topic.
+// Produce writes the argument records to a topic. If partition is non-negative, all records are
+// written to that partition; otherwise, they're assigned by the argument partitioner. If the
+// argument key or value encoders are nil, the corresponding bytes are written as-is.
+func (c *CLIRunner) Produce(
+	ctx context.Context,
+	topic string,
+	records []messages.ProduceRecord,
+	partitioner messages.PartitionerType,
+	partition int,
+	keyEncoder messages.Encoder,
+	valueEncoder messages.Encoder,
+) error {
+	if len(records) == 0 {
+		return errors.New("No records to produce")
+	}
+
+	c.startSpinner()
+	topicInfo, err := c.adminClient.GetTopic(ctx, topic, false)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if partition >= 0 {
+		var found bool
+		for _, partitionID := range topicInfo.PartitionIDs() {
+			if partitionID == partition {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf(
+				"Partition %d is not in topic %s (partitions: %+v)",
+				partition,
+				topic,
+				topicInfo.PartitionIDs(),
+			)
+		}
+	}
+
+	balancer, err := messages.NewBalancer(partitioner, partition)
+	if err != nil {
+		return err
+	}
+
+	producer := messages.NewTopicProducer(c.adminClient.GetConnector(), topic, balancer)
+	producer.SetEncoders(keyEncoder, valueEncoder)
+
+	c.startSpinner()
+	err = producer.Produce(ctx, records)
+	c.stopSpinner()
+	if err != nil {
+		return err
+	}
+
+	c.printer("Wrote %d message(s) to topic %s", len(records), topic)
+	return nil
+}
+
+// Tail prints out a stream of the latest messages in a topic. If the argument key or value
+// decoders are nil, the corresponding bytes are printed as strings. If filter is non-nil, only
+// the messages that match it (and filterRegexp, if set) are printed.
 func (c *CLIRunner) Tail(
 	ctx context.Context,
 	topic string,
@@ -517,7 +3815,10 @@ func (c *CLIRunner) Tail(
 	partitions []int,
 	maxMessages int,
 	filterRegexp string,
+	filter *messages.MessageFilter,
 	raw bool,
+	keyDecoder messages.Decoder,
+	valueDecoder messages.Decoder,
 ) error {
 	var err error
 	if len(partitions) == 0 {
@@ -538,8 +3839,10 @@ func (c *CLIRunner) Tail(
 		10e3,
 		10e6,
 	)
+	tailer.SetDecoders(keyDecoder, valueDecoder)
+	tailer.SetFilter(filter)
 	stats, err := tailer.LogMessages(ctx, maxMessages, filterRegexp, raw)
-	filtered := filterRegexp != ""
+	filtered := filterRegexp != "" || filter != nil
 
 	if !raw {
 		c.printer("Tail stats:\n%s", messages.FormatTailStats(stats, filtered))
@@ -548,6 +3851,62 @@ func (c *CLIRunner) Tail(
 	return err
 }
 
+// getPartitionDetails gets the replica sizes and latest message times of the partitions in the
+// argument topic. The replica log dirs are also returned; these are empty if the brokers don't
+// support getting them.
+func (c *CLIRunner) getPartitionDetails(
+	ctx context.Context,
+	topicInfo admin.TopicInfo,
+) ([]admin.PartitionDetails, []admin.ReplicaLogDirInfo, error) {
+	// Sizes aren't critical, so don't fail if the brokers don't support getting them
+	replicaLogDirs, err := c.adminClient.GetReplicaLogDirs(ctx, []string{topicInfo.Name}, nil)
+	if err != nil {
+		log.Warnf("Could not get replica sizes: %+v", err)
+		replicaLogDirs = nil
+	}
+
+	bounds, err := messages.GetAllPartitionBounds(
+		ctx,
+		c.adminClient.GetConnector(),
+		topicInfo.Name,
+		nil,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replicaSizes := map[int]map[int]int64{}
+	for _, replicaLogDir := range replicaLogDirs {
+		// Future replicas are the targets of in-progress moves between log dirs
+		if replicaLogDir.IsFuture {
+			continue
+		}
+		if _, ok := replicaSizes[replicaLogDir.Partition]; !ok {
+			replicaSizes[replicaLogDir.Partition] = map[int]int64{}
+		}
+		replicaSizes[replicaLogDir.Partition][replicaLogDir.BrokerID] = replicaLogDir.Size
+	}
+
+	lastTimes := map[int]time.Time{}
+	for _, bound := range bounds {
+		lastTimes[bound.Partition] = bound.LastTime
+	}
+
+	partitionDetails := []admin.PartitionDetails{}
+	for _, partition := range topicInfo.Partitions {
+		partitionDetails = append(
+			partitionDetails,
+			admin.PartitionDetails{
+				PartitionInfo: partition,
+				ReplicaSizes:  replicaSizes[partition.ID],
+				LastModified:  lastTimes[partition.ID],
+			},
+		)
+	}
+
+	return partitionDetails, replicaLogDirs, nil
+}
+
 func (c *CLIRunner) startSpinner() {
 	if c.spinnerObj != nil {
 		c.spinnerObj.Start()
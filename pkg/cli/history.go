@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultReplHistoryFile is the name of the file in the user's home directory that repl
+	// commands are saved to by default.
+	DefaultReplHistoryFile = ".topicctl_history"
+
+	// maxReplHistory is the maximum number of commands that are kept in the history file.
+	maxReplHistory = 1000
+)
+
+// loadReplHistory reads the commands in the argument history file, oldest first. A missing file
+// is treated as an empty history. If the file has more than maxReplHistory commands, it's
+// rewritten with just the newest ones so that it doesn't grow forever.
+func loadReplHistory(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	history := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if command := strings.TrimSpace(scanner.Text()); command != "" {
+			history = append(history, command)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading history file %s: %+v", path, err)
+	}
+
+	if len(history) > maxReplHistory {
+		history = history[len(history)-maxReplHistory:]
+
+		contents := strings.Join(history, "\n") + "\n"
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	return history, nil
+}
+
+// appendReplHistory adds the argument command to the end of the argument history file, creating
+// the file if needed.
+func appendReplHistory(path string, command string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintln(file, command)
+	return err
+}
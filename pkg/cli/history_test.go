@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultReplHistoryFile)
+
+	history, err := loadReplHistory(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, history)
+
+	require.NoError(t, appendReplHistory(path, "get brokers"))
+	require.NoError(t, appendReplHistory(path, "get topics"))
+
+	history, err = loadReplHistory(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"get brokers", "get topics"}, history)
+
+	for i := 0; i < maxReplHistory; i++ {
+		require.NoError(t, appendReplHistory(path, fmt.Sprintf("tail topic-%d", i)))
+	}
+
+	history, err = loadReplHistory(path)
+	require.NoError(t, err)
+	require.Equal(t, maxReplHistory, len(history))
+	assert.Equal(t, "tail topic-0", history[0])
+	assert.Equal(t, fmt.Sprintf("tail topic-%d", maxReplHistory-1), history[maxReplHistory-1])
+
+	// The file should have been truncated to the newest commands
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, maxReplHistory, len(strings.Split(strings.TrimSpace(string(contents)), "\n")))
+}
@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// OutputFormat is a string type that identifies how the results of get commands are printed.
+type OutputFormat string
+
+const (
+	// OutputFormatTable prints results as human-readable tables. This is the default.
+	OutputFormatTable OutputFormat = "table"
+
+	// OutputFormatJSON prints results as indented JSON.
+	OutputFormatJSON OutputFormat = "json"
+
+	// OutputFormatYAML prints results as YAML.
+	OutputFormatYAML OutputFormat = "yaml"
+
+	// OutputFormatCSV prints the primary list in the results as CSV, with one row per item.
+	OutputFormatCSV OutputFormat = "csv"
+)
+
+// AllOutputFormats contains all of the supported output formats.
+var AllOutputFormats = []OutputFormat{
+	OutputFormatTable,
+	OutputFormatJSON,
+	OutputFormatYAML,
+	OutputFormatCSV,
+}
+
+// ParseOutputFormat converts the argument string into an OutputFormat. An empty string is
+// treated as OutputFormatTable.
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	if value == "" {
+		return OutputFormatTable, nil
+	}
+
+	for _, format := range AllOutputFormats {
+		if string(format) == value {
+			return format, nil
+		}
+	}
+
+	formatStrs := []string{}
+	for _, format := range AllOutputFormats {
+		formatStrs = append(formatStrs, string(format))
+	}
+
+	return "", fmt.Errorf(
+		"Unrecognized output format %s; must be one of: %s",
+		value,
+		strings.Join(formatStrs, ", "),
+	)
+}
+
+// configRow is a single key and value in a broker or topic config, used for structured output.
+type configRow struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// configRows converts the argument config into a slice of rows sorted by key.
+func configRows(config map[string]string) []configRow {
+	rows := []configRow{}
+	for name, value := range config {
+		rows = append(rows, configRow{Name: name, Value: value})
+	}
+	sort.Slice(rows, func(a, b int) bool {
+		return rows[a].Name < rows[b].Name
+	})
+	return rows
+}
+
+// brokerStorageRow is the storage of a single broker along with the fraction of its capacity
+// that's in use, used for CSV output.
+type brokerStorageRow struct {
+	admin.BrokerStorage
+	UsedFraction float64 `json:"usedFraction"`
+}
+
+// brokerStorageRows converts the argument broker storages into CSV rows.
+func brokerStorageRows(brokerStorages []admin.BrokerStorage) []brokerStorageRow {
+	rows := []brokerStorageRow{}
+	for _, brokerStorage := range brokerStorages {
+		rows = append(
+			rows,
+			brokerStorageRow{
+				BrokerStorage: brokerStorage,
+				UsedFraction:  brokerStorage.UsedFraction(),
+			},
+		)
+	}
+	return rows
+}
+
+// partitionDetailsRow is the details of a single partition along with the size of its largest
+// replica, used for CSV output.
+type partitionDetailsRow struct {
+	admin.PartitionDetails
+	MaxReplicaSize int64 `json:"maxReplicaSize"`
+}
+
+// partitionDetailsRows converts the argument partition details into CSV rows.
+func partitionDetailsRows(partitionDetails []admin.PartitionDetails) []partitionDetailsRow {
+	rows := []partitionDetailsRow{}
+	for _, details := range partitionDetails {
+		rows = append(
+			rows,
+			partitionDetailsRow{
+				PartitionDetails: details,
+				MaxReplicaSize:   details.MaxReplicaSize(),
+			},
+		)
+	}
+	return rows
+}
+
+// structured returns whether the runner prints results in a machine-readable format instead of
+// as tables.
+func (c *CLIRunner) structured() bool {
+	return c.outputFormat != "" && c.outputFormat != OutputFormatTable
+}
+
+// printStructured prints out the argument results to stdout in the runner's output format.
+// value is used for the JSON and YAML formats, and rows, which must be a slice of structs, is
+// used for the CSV one.
+func (c *CLIRunner) printStructured(value interface{}, rows interface{}) error {
+	return writeStructured(os.Stdout, c.outputFormat, value, rows)
+}
+
+func writeStructured(
+	w io.Writer,
+	format OutputFormat,
+	value interface{},
+	rows interface{},
+) error {
+	switch format {
+	case OutputFormatJSON:
+		jsonBytes, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(jsonBytes))
+		return err
+	case OutputFormatYAML:
+		yamlBytes, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(yamlBytes)
+		return err
+	case OutputFormatCSV:
+		return writeCSV(w, rows)
+	default:
+		return fmt.Errorf("Unsupported structured output format: %s", format)
+	}
+}
+
+// writeCSV writes the argument slice of structs as CSV, with a header row containing the JSON
+// names of the struct fields. Embedded structs are flattened, and values that aren't scalars
+// (e.g., slices and maps) are encoded as JSON.
+func writeCSV(w io.Writer, rows interface{}) error {
+	rowsValue := reflect.ValueOf(rows)
+	if rowsValue.Kind() != reflect.Slice {
+		return fmt.Errorf("CSV rows must be a slice, got %T", rows)
+	}
+
+	elemType := rowsValue.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("CSV rows must be structs, got %s", elemType)
+	}
+
+	fields := csvFields(elemType, nil)
+
+	csvWriter := csv.NewWriter(w)
+
+	header := []string{}
+	for _, field := range fields {
+		header = append(header, field.name)
+	}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+
+	for i := 0; i < rowsValue.Len(); i++ {
+		rowValue := rowsValue.Index(i)
+		if rowValue.Kind() == reflect.Ptr {
+			if rowValue.IsNil() {
+				continue
+			}
+			rowValue = rowValue.Elem()
+		}
+
+		record := []string{}
+		for _, field := range fields {
+			cell, err := csvCell(rowValue.FieldByIndex(field.index))
+			if err != nil {
+				return err
+			}
+			record = append(record, cell)
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+func csvFields(structType reflect.Type, parentIndex []int) []csvField {
+	fields := []csvField{}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		index := append(append([]int{}, parentIndex...), i)
+
+		tagName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tagName == "-" {
+			continue
+		}
+
+		// Like in encoding/json, the fields of embedded structs are promoted even if the
+		// struct type itself is unexported
+		if field.Anonymous && tagName == "" &&
+			field.Type.Kind() == reflect.Struct &&
+			field.Type != reflect.TypeOf(time.Time{}) {
+			fields = append(fields, csvFields(field.Type, index)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			// Unexported
+			continue
+		}
+
+		name := tagName
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, csvField{name: name, index: index})
+	}
+
+	return fields
+}
+
+func csvCell(value reflect.Value) (string, error) {
+	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+
+	if timeValue, ok := value.Interface().(time.Time); ok {
+		if timeValue.IsZero() {
+			return "", nil
+		}
+		return timeValue.Format(time.RFC3339), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%v", value.Interface()), nil
+	case reflect.Slice, reflect.Map:
+		if value.IsNil() {
+			return "", nil
+		}
+	}
+
+	jsonBytes, err := json.Marshal(value.Interface())
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
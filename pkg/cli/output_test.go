@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	outputFormat, err := ParseOutputFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, OutputFormatTable, outputFormat)
+
+	outputFormat, err = ParseOutputFormat("yaml")
+	require.NoError(t, err)
+	assert.Equal(t, OutputFormatYAML, outputFormat)
+
+	_, err = ParseOutputFormat("xml")
+	assert.Error(t, err)
+}
+
+type testInner struct {
+	ID       int   `json:"id"`
+	Replicas []int `json:"replicas"`
+}
+
+type testRow struct {
+	testInner
+	Name       string            `json:"name"`
+	Config     map[string]string `json:"config"`
+	Updated    time.Time         `json:"updated"`
+	Skipped    string            `json:"-"`
+	Untagged   bool
+	unexported string
+}
+
+func TestWriteStructured(t *testing.T) {
+	rows := []testRow{
+		{
+			testInner: testInner{ID: 1, Replicas: []int{1, 2}},
+			Name:      "row, 1",
+			Config:    map[string]string{"key": "value"},
+			Updated:   time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			Skipped:   "skipped",
+			Untagged:  true,
+		},
+		{
+			testInner: testInner{ID: 2},
+			Name:      "row2",
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeStructured(buf, OutputFormatCSV, nil, rows))
+	assert.Equal(
+		t,
+		"id,replicas,name,config,updated,Untagged\n"+
+			"1,\"[1,2]\",\"row, 1\",\"{\"\"key\"\":\"\"value\"\"}\",2020-01-02T03:04:05Z,true\n"+
+			"2,,row2,,,false\n",
+		buf.String(),
+	)
+
+	buf = &bytes.Buffer{}
+	require.NoError(
+		t,
+		writeStructured(buf, OutputFormatCSV, nil, configRows(map[string]string{"b": "2", "a": "1"})),
+	)
+	assert.Equal(t, "name,value\na,1\nb,2\n", buf.String())
+
+	buf = &bytes.Buffer{}
+	require.NoError(t, writeStructured(buf, OutputFormatYAML, rows[1:], nil))
+	assert.Equal(
+		t,
+		"- Untagged: false\n"+
+			"  config: null\n"+
+			"  id: 2\n"+
+			"  name: row2\n"+
+			"  replicas: null\n"+
+			"  updated: \"0001-01-01T00:00:00Z\"\n",
+		buf.String(),
+	)
+
+	buf = &bytes.Buffer{}
+	require.NoError(t, writeStructured(buf, OutputFormatJSON, map[string]int{"a": 1}, nil))
+	assert.Equal(t, "{\n  \"a\": 1\n}\n", buf.String())
+
+	assert.Error(t, writeStructured(buf, OutputFormatCSV, nil, []string{"a"}))
+	assert.Error(t, writeStructured(buf, OutputFormatTable, rows, rows))
+}
+
+func TestWriteCSVCapacityRows(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(
+		t,
+		writeCSV(
+			buf,
+			brokerStorageRows(
+				[]admin.BrokerStorage{
+					{
+						BrokerID:    1,
+						Rack:        "rack1",
+						LogDirs:     2,
+						Replicas:    10,
+						Bytes:       600,
+						TotalBytes:  1000,
+						UsableBytes: 250,
+					},
+					{
+						BrokerID: 2,
+						Rack:     "rack2",
+						LogDirs:  1,
+						Replicas: 5,
+						Bytes:    300,
+					},
+				},
+			),
+		),
+	)
+	assert.Equal(
+		t,
+		"brokerID,rack,logDirs,replicas,bytes,totalBytes,usableBytes,offlineLogDirs,usedFraction\n"+
+			"1,rack1,2,10,600,1000,250,0,0.75\n"+
+			"2,rack2,1,5,300,0,0,0,-1\n",
+		buf.String(),
+	)
+
+	buf = &bytes.Buffer{}
+	require.NoError(
+		t,
+		writeCSV(
+			buf,
+			partitionDetailsRows(
+				[]admin.PartitionDetails{
+					{
+						PartitionInfo: admin.PartitionInfo{
+							Topic:    "test-topic",
+							ID:       0,
+							Leader:   1,
+							Replicas: []int{1, 2},
+							ISR:      []int{1, 2},
+						},
+						ReplicaSizes: map[int]int64{1: 100, 2: 150},
+						LastModified: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+					},
+					{
+						PartitionInfo: admin.PartitionInfo{
+							Topic:    "test-topic",
+							ID:       1,
+							Leader:   2,
+							Replicas: []int{2, 1},
+							ISR:      []int{2},
+						},
+					},
+				},
+			),
+		),
+	)
+	assert.Equal(
+		t,
+		"topic,ID,leader,version,replicas,isr,controllerEpoch,leaderEpoch,replicaSizes,"+
+			"lastModified,maxReplicaSize\n"+
+			"test-topic,0,1,0,\"[1,2]\",\"[1,2]\",0,0,\"{\"\"1\"\":100,\"\"2\"\":150}\","+
+			"2020-01-02T03:04:05Z,150\n"+
+			"test-topic,1,2,0,\"[2,1]\",[2],0,0,,,0\n",
+		buf.String(),
+	)
+}
@@ -3,18 +3,22 @@ package cli
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"sort"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/c-bata/go-prompt"
 	"github.com/olekukonko/tablewriter"
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/segmentio/topicctl/pkg/util"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -28,6 +32,10 @@ var (
 			Text:        "tail",
 			Description: "Tail all messages in a topic",
 		},
+		{
+			Text:        "use",
+			Description: "Switch to another cluster",
+		},
 		{
 			Text:        "help",
 			Description: "Show all commands",
@@ -39,10 +47,18 @@ var (
 	}
 
 	getSuggestions = []prompt.Suggest{
+		{
+			Text:        "acls",
+			Description: "Get ACLs in the cluster, optionally filtered",
+		},
 		{
 			Text:        "balance",
 			Description: "Get positions of all brokers in a topic or across entire cluster",
 		},
+		{
+			Text:        "broker-configs",
+			Description: "Get full broker configs and any drift between brokers",
+		},
 		{
 			Text:        "brokers",
 			Description: "Get all brokers",
@@ -55,6 +71,14 @@ var (
 			Text:        "groups",
 			Description: "Get all consumer groups",
 		},
+		{
+			Text:        "health",
+			Description: "Get a summary of the cluster's health",
+		},
+		{
+			Text:        "lag",
+			Description: "Get the lag for all topic partitions consumed by a consumer group",
+		},
 		{
 			Text:        "lags",
 			Description: "Get partition lags for all members of a consumer group",
@@ -71,6 +95,18 @@ var (
 			Text:        "offsets",
 			Description: "Get the offset ranges for all partitions in a topic",
 		},
+		{
+			Text:        "quotas",
+			Description: "Get all client quotas",
+		},
+		{
+			Text:        "reassignments",
+			Description: "Get in-progress partition reassignments and their progress",
+		},
+		{
+			Text:        "storage",
+			Description: "Get disk usage per broker and log dir",
+		},
 		{
 			Text:        "topics",
 			Description: "Get all topics",
@@ -80,19 +116,82 @@ var (
 	helpTableStr = helpTable()
 )
 
+// ReplConfig contains the optional settings for a Repl.
+type ReplConfig struct {
+	// ClusterName is the name of the initial cluster, if known. It's shown in the prompt.
+	ClusterName string
+
+	// ClusterNames are the clusters that are suggested for the use command.
+	ClusterNames []string
+
+	// LoadCluster creates a client for the argument cluster, which is either one of the
+	// ClusterNames or a path to a cluster config, and returns it along with the cluster's name.
+	// If it's nil, the use command isn't supported.
+	LoadCluster func(ctx context.Context, cluster string) (string, admin.Client, error)
+
+	// HistoryPath is the file that commands are saved to so that they're available in later
+	// sessions. If it's empty, the history isn't persisted.
+	HistoryPath string
+}
+
 // Repl manages the repl mode for topicctl.
 type Repl struct {
+	adminClient               admin.Client
 	cliRunner                 *CLIRunner
+	clusterName               string
+	config                    ReplConfig
+	history                   []string
 	brokerAndTopicSuggestions []prompt.Suggest
+	clusterSuggestions        []prompt.Suggest
 	topicSuggestions          []prompt.Suggest
 	groupSuggestions          []prompt.Suggest
 }
 
-// NewRepl initializes and returns a Repl instance.
+// NewRepl initializes and returns a Repl instance. The repl takes ownership of the argument
+// client, which is closed when switching to another cluster or when Close is called.
 func NewRepl(
 	ctx context.Context,
 	adminClient admin.Client,
+	config ReplConfig,
 ) (*Repl, error) {
+	repl := &Repl{
+		config:             config,
+		clusterSuggestions: []prompt.Suggest{},
+		history:            []string{},
+	}
+
+	for _, clusterName := range config.ClusterNames {
+		repl.clusterSuggestions = append(
+			repl.clusterSuggestions,
+			prompt.Suggest{
+				Text:        clusterName,
+				Description: fmt.Sprintf("Cluster %s", clusterName),
+			},
+		)
+	}
+
+	if config.HistoryPath != "" {
+		history, err := loadReplHistory(config.HistoryPath)
+		if err != nil {
+			log.Warnf("Could not load repl history: %+v", err)
+		} else {
+			repl.history = history
+		}
+	}
+
+	if err := repl.setCluster(ctx, config.ClusterName, adminClient); err != nil {
+		return nil, err
+	}
+	return repl, nil
+}
+
+// setCluster loads the auto-complete suggestions for the argument cluster and then switches
+// the repl to it.
+func (r *Repl) setCluster(
+	ctx context.Context,
+	clusterName string,
+	adminClient admin.Client,
+) error {
 	cliRunner := NewCLIRunner(
 		adminClient,
 		func(f string, a ...interface{}) {
@@ -108,7 +207,7 @@ func NewRepl(
 	topicNames, err := adminClient.GetTopicNames(ctx)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 	sort.Slice(topicNames, func(a, b int) bool {
 		return topicNames[a] < topicNames[b]
@@ -128,7 +227,7 @@ func NewRepl(
 	log.Debug("Loading brokers for auto-complete")
 	brokerIDs, err := adminClient.GetBrokerIDs(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	sort.Slice(brokerIDs, func(a, b int) bool {
 		return brokerIDs[a] < brokerIDs[b]
@@ -176,12 +275,40 @@ func NewRepl(
 		)
 	}
 
-	return &Repl{
-		cliRunner:                 cliRunner,
-		brokerAndTopicSuggestions: brokerAndTopicSuggestions,
-		topicSuggestions:          topicSuggestions,
-		groupSuggestions:          groupSuggestions,
-	}, nil
+	if r.adminClient != nil {
+		if err := r.adminClient.Close(); err != nil {
+			log.Warnf("Could not close client for previous cluster: %+v", err)
+		}
+	}
+
+	r.adminClient = adminClient
+	r.cliRunner = cliRunner
+	r.clusterName = clusterName
+	r.brokerAndTopicSuggestions = brokerAndTopicSuggestions
+	r.topicSuggestions = topicSuggestions
+	r.groupSuggestions = groupSuggestions
+	return nil
+}
+
+// useCluster switches the repl to the argument cluster, which is either a cluster name or a
+// path to a cluster config.
+func (r *Repl) useCluster(ctx context.Context, cluster string) error {
+	if r.config.LoadCluster == nil {
+		return errors.New("Switching clusters isn't supported in this repl")
+	}
+
+	clusterName, adminClient, err := r.config.LoadCluster(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := r.setCluster(ctx, clusterName, adminClient); err != nil {
+		adminClient.Close()
+		return err
+	}
+
+	r.cliRunner.printer("Switched to cluster %s", clusterName)
+	return nil
 }
 
 // Run starts the repl main loop.
@@ -192,10 +319,33 @@ func (r *Repl) Run() {
 		r.executor,
 		r.completer,
 		prompt.OptionPrefix(">>> "),
+		prompt.OptionLivePrefix(r.livePrefix),
+		prompt.OptionHistory(r.history),
 	)
 	promptObj.Run()
 }
 
+// Close closes the client for the current cluster.
+func (r *Repl) Close() error {
+	return r.adminClient.Close()
+}
+
+func (r *Repl) livePrefix() (string, bool) {
+	if r.clusterName == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s >>> ", r.clusterName), true
+}
+
+func (r *Repl) saveHistory(in string) {
+	if r.config.HistoryPath == "" {
+		return
+	}
+	if err := appendReplHistory(r.config.HistoryPath, in); err != nil {
+		log.Warnf("Could not save repl history: %+v", err)
+	}
+}
+
 func (r *Repl) executor(in string) {
 	in = strings.TrimSpace(in)
 
@@ -214,10 +364,12 @@ func (r *Repl) executor(in string) {
 	if len(command.args) == 0 {
 		return
 	}
+	r.saveHistory(in)
 
 	switch command.args[0] {
 	case "exit":
 		fmt.Println("Bye!")
+		r.Close()
 		os.Exit(0)
 	case "get":
 		if len(command.args) == 1 {
@@ -225,9 +377,52 @@ func (r *Repl) executor(in string) {
 			return
 		}
 
+		// The output format applies to all get commands, so handle it before checking the
+		// command-specific flags
+		outputFormat, err := ParseOutputFormat(command.flags["output"])
+		if err != nil {
+			log.Errorf("Error: %+v", err)
+			return
+		}
+		delete(command.flags, "output")
+		r.cliRunner.SetOutputFormat(outputFormat)
+		defer r.cliRunner.SetOutputFormat(OutputFormatTable)
+
 		switch command.args[1] {
+		case "acls":
+			if err := command.checkArgs(
+				2,
+				3,
+				map[string]struct{}{
+					"operation":     {},
+					"pattern-type":  {},
+					"principal":     {},
+					"resource-type": {},
+				},
+			); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+			var resourceName string
+			if len(command.args) == 3 {
+				resourceName = command.args[2]
+			}
+
+			if err := r.cliRunner.GetACLs(
+				ctx,
+				admin.ACLFilter{
+					ResourceType: command.flags["resource-type"],
+					ResourceName: resourceName,
+					PatternType:  command.flags["pattern-type"],
+					Principal:    command.flags["principal"],
+					Operation:    command.flags["operation"],
+				},
+			); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
 		case "balance":
-			if err := command.checkArgs(2, 3, nil); err != nil {
+			if err := command.checkArgs(2, 3, map[string]struct{}{"full": {}}); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
@@ -236,7 +431,34 @@ func (r *Repl) executor(in string) {
 				topicName = command.args[2]
 			}
 
-			if err := r.cliRunner.GetBrokerBalance(ctx, topicName); err != nil {
+			if err := r.cliRunner.GetBrokerBalance(
+				ctx,
+				topicName,
+				command.getBoolValue("full"),
+			); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+		case "broker-configs":
+			if err := command.checkArgs(2, 3, map[string]struct{}{"full": {}}); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+			brokerID := -1
+			if len(command.args) == 3 {
+				var err error
+				brokerID, err = strconv.Atoi(command.args[2])
+				if err != nil {
+					log.Errorf("Error: Broker ID must be an integer: %+v", err)
+					return
+				}
+			}
+
+			if err := r.cliRunner.GetBrokerConfigs(
+				ctx,
+				brokerID,
+				command.getBoolValue("full"),
+			); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
@@ -259,11 +481,35 @@ func (r *Repl) executor(in string) {
 				return
 			}
 		case "groups":
+			if err := command.checkArgs(2, 2, map[string]struct{}{"topic": {}}); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+			if err := r.cliRunner.GetGroups(ctx, command.flags["topic"]); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+		case "health":
 			if err := command.checkArgs(2, 2, nil); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
-			if err := r.cliRunner.GetGroups(ctx); err != nil {
+			if err := r.cliRunner.GetHealth(ctx); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+		case "lag":
+			if err := command.checkArgs(3, 3, map[string]struct{}{"total": {}}); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+			if err := r.cliRunner.GetGroupLags(
+				ctx,
+				command.args[2],
+				command.getBoolValue("total"),
+				-1,
+				0,
+			); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
@@ -300,20 +546,91 @@ func (r *Repl) executor(in string) {
 				return
 			}
 		case "partitions":
-			if err := command.checkArgs(3, 3, nil); err != nil {
+			if err := command.checkArgs(
+				3,
+				3,
+				map[string]struct{}{"sort-by": {}, "desc": {}},
+			); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
-			if err := r.cliRunner.GetPartitions(ctx, command.args[2]); err != nil {
+			if err := r.cliRunner.GetPartitions(
+				ctx,
+				command.args[2],
+				admin.PartitionSortKey(command.flags["sort-by"]),
+				command.getBoolValue("desc"),
+			); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
 		case "offsets":
-			if err := command.checkArgs(3, 3, nil); err != nil {
+			if err := command.checkArgs(3, 3, map[string]struct{}{"at-time": {}}); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+
+			var atTime *time.Time
+			if atTimeStr := command.flags["at-time"]; atTimeStr != "" {
+				parsedTime, err := util.ParseTime(atTimeStr, time.Now())
+				if err != nil {
+					log.Errorf("Error: %+v", err)
+					return
+				}
+				atTime = &parsedTime
+			}
+
+			if err := r.cliRunner.GetOffsets(ctx, command.args[2], atTime); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
-			if err := r.cliRunner.GetOffsets(ctx, command.args[2]); err != nil {
+		case "quotas":
+			if err := command.checkArgs(2, 2, nil); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+			if err := r.cliRunner.GetQuotas(ctx); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+		case "reassignments":
+			if err := command.checkArgs(2, 3, nil); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+			var topicName string
+			if len(command.args) == 3 {
+				topicName = command.args[2]
+			}
+
+			if err := r.cliRunner.GetReassignments(ctx, topicName); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+		case "storage":
+			if err := command.checkArgs(
+				2,
+				3,
+				map[string]struct{}{"by-topic": {}, "full": {}},
+			); err != nil {
+				log.Errorf("Error: %+v", err)
+				return
+			}
+			brokerID := -1
+			if len(command.args) == 3 {
+				var err error
+				brokerID, err = strconv.Atoi(command.args[2])
+				if err != nil {
+					log.Errorf("Error: Broker ID must be an integer: %+v", err)
+					return
+				}
+			}
+
+			if err := r.cliRunner.GetStorage(
+				ctx,
+				brokerID,
+				command.getBoolValue("by-topic"),
+				command.getBoolValue("full"),
+			); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
@@ -322,7 +639,7 @@ func (r *Repl) executor(in string) {
 				log.Errorf("Error: %+v", err)
 				return
 			}
-			if err := r.cliRunner.GetTopics(ctx, false); err != nil {
+			if err := r.cliRunner.GetTopics(ctx, false, admin.TopicFilter{}); err != nil {
 				log.Errorf("Error: %+v", err)
 				return
 			}
@@ -362,11 +679,22 @@ func (r *Repl) executor(in string) {
 			nil,
 			-1,
 			filterRegexp,
+			nil,
 			command.getBoolValue("raw"),
+			nil,
+			nil,
 		)
 		if err != nil {
 			log.Errorf("Error: %+v", err)
 		}
+	case "use":
+		if err := command.checkArgs(2, 2, nil); err != nil {
+			log.Errorf("Error: %+v", err)
+			return
+		}
+		if err := r.useCluster(ctx, command.args[1]); err != nil {
+			log.Errorf("Error: %+v", err)
+		}
 	default:
 		if len(in) > 0 {
 			log.Error("Unrecognized input. Run 'help' for details on available commands.")
@@ -388,16 +716,20 @@ func (r *Repl) completer(doc prompt.Document) []prompt.Suggest {
 			(words[1] == "balance" ||
 				words[1] == "lags" ||
 				words[1] == "partitions" ||
-				words[1] == "offsets") {
+				words[1] == "offsets" ||
+				words[1] == "reassignments") {
 			suggestions = r.topicSuggestions
 		} else if len(words) == 4 && words[0] == "get" && words[1] == "lags" {
 			suggestions = r.groupSuggestions
-		} else if len(words) == 3 && words[0] == "get" && words[1] == "members" {
+		} else if len(words) == 3 && words[0] == "get" &&
+			(words[1] == "lag" || words[1] == "members") {
 			suggestions = r.groupSuggestions
 		} else if len(words) == 3 && words[0] == "get" && words[1] == "config" {
 			suggestions = r.brokerAndTopicSuggestions
 		} else if len(words) == 2 && words[0] == "tail" {
 			suggestions = r.topicSuggestions
+		} else if len(words) == 2 && words[0] == "use" {
+			suggestions = r.clusterSuggestions
 		}
 	}
 
@@ -432,8 +764,16 @@ func helpTable() string {
 	table.AppendBulk(
 		[][]string{
 			{
-				"  get balance [optional topic]",
-				"Get positions of all brokers in topic or across cluster",
+				"  get acls [optional resource name] [--principal=principal] [--resource-type=type] [--pattern-type=type] [--operation=operation]",
+				"Get ACLs in the cluster",
+			},
+			{
+				"  get balance [optional topic] [--full]",
+				"Get replica, leader, and size balance of brokers in topic or across cluster",
+			},
+			{
+				"  get broker-configs [optional broker ID] [--full]",
+				"Get full broker configs and any drift between brokers",
 			},
 			{
 				"  get brokers [--full]",
@@ -444,8 +784,16 @@ func helpTable() string {
 				"Get config for a broker or topic",
 			},
 			{
-				"  get groups",
-				"Get all consumer groups",
+				"  get groups [--topic=topic]",
+				"Get all consumer groups, or those consuming a topic",
+			},
+			{
+				"  get health",
+				"Get a summary of the cluster's health",
+			},
+			{
+				"  get lag [group] [--total]",
+				"Get the lag for all topic partitions consumed by a consumer group",
 			},
 			{
 				"  get lags [topic] [group] [--full] [--sort-values]",
@@ -456,21 +804,41 @@ func helpTable() string {
 				"Get the members of a consumer group",
 			},
 			{
-				"  get partitions [topic]",
-				"Get all partitions for a topic",
+				"  get partitions [topic] [--sort-by=key] [--desc]",
+				"Get all partitions for a topic along with their sizes",
 			},
 			{
-				"  get offsets [topic]",
+				"  get offsets [topic] [--at-time=time]",
 				"Get the offset ranges for all partitions in a topic",
 			},
+			{
+				"  get quotas",
+				"Get all client quotas",
+			},
+			{
+				"  get reassignments [optional topic]",
+				"Get in-progress partition reassignments and their estimated progress",
+			},
+			{
+				"  get storage [optional broker ID] [--by-topic] [--full]",
+				"Get disk usage per broker and log dir, and optionally the largest topics",
+			},
 			{
 				"  get topics",
 				"Get all topics",
 			},
+			{
+				"  get [resource type] ... [--output=format]",
+				"Print the results of any get command as a table (default), json, yaml, or csv",
+			},
 			{
 				"  tail [topic] [optional filter regexp] [--raw]",
 				"Tail all messages in a topic",
 			},
+			{
+				"  use [cluster name or config path]",
+				"Switch to another cluster",
+			},
 			{
 				"  exit",
 				"Exit the repl",
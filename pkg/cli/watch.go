@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// clearScreen moves the cursor to the top left of the terminal and clears it.
+const clearScreen = "\033[H\033[2J"
+
+// Watch calls the argument function every interval until the context is done, reprinting its
+// output to the argument writer each time. The function should send all of its output to the
+// printer that it's passed. When writing to a terminal, the screen is cleared before each
+// update and the lines that weren't in the previous output are highlighted.
+//
+// Errors from the function are printed instead of its output so that transient problems, e.g.
+// a broker restarting, don't stop the watch.
+func Watch(
+	ctx context.Context,
+	interval time.Duration,
+	out io.Writer,
+	run func(printer func(f string, a ...interface{})) error,
+) error {
+	inTerminal := util.InTerminal()
+	colorEnabled := util.ColorEnabled()
+
+	highlightColor := color.New(color.ReverseVideo)
+	highlight := func(line string) string {
+		if !colorEnabled {
+			return line
+		}
+		return highlightColor.Sprint(line)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prevOutput string
+
+	for {
+		buf := &bytes.Buffer{}
+		printer := func(f string, a ...interface{}) {
+			fmt.Fprintf(buf, f, a...)
+			fmt.Fprintln(buf)
+		}
+
+		if err := run(printer); err != nil {
+			fmt.Fprintf(buf, "Error: %+v\n", err)
+		}
+		output := buf.String()
+
+		if inTerminal {
+			fmt.Fprint(out, clearScreen)
+		}
+		fmt.Fprintf(
+			out,
+			"Every %s, updated at %s (ctrl-c to exit)\n\n%s\n",
+			interval,
+			time.Now().Format("15:04:05"),
+			highlightChangedLines(prevOutput, output, highlight),
+		)
+		prevOutput = output
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// highlightChangedLines applies the argument highlight function to each line in curr that
+// doesn't appear in prev. Nothing is highlighted if prev is empty, i.e. on the first update.
+// Lines are matched by content rather than position so that rows moving up or down in a table
+// aren't treated as changes.
+func highlightChangedLines(
+	prev string,
+	curr string,
+	highlight func(line string) string,
+) string {
+	if prev == "" {
+		return curr
+	}
+
+	prevLines := map[string]int{}
+	for _, line := range strings.Split(prev, "\n") {
+		prevLines[line]++
+	}
+
+	lines := strings.Split(curr, "\n")
+	for l, line := range lines {
+		if prevLines[line] > 0 {
+			prevLines[line]--
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines[l] = highlight(line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlightChangedLines(t *testing.T) {
+	highlight := func(line string) string {
+		return "[" + line + "]"
+	}
+
+	assert.Equal(
+		t,
+		"topic1 1\ntopic2 2",
+		highlightChangedLines("", "topic1 1\ntopic2 2", highlight),
+	)
+	assert.Equal(
+		t,
+		"topic2 2\n[topic1 3]\n\n[topic3 1]",
+		highlightChangedLines(
+			"topic1 1\ntopic2 2\n",
+			"topic2 2\ntopic1 3\n\ntopic3 1",
+			highlight,
+		),
+	)
+
+	// Repeated lines are only matched as many times as they appeared before
+	assert.Equal(
+		t,
+		"row\n[row]",
+		highlightChangedLines("row", "row\nrow", highlight),
+	)
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+
+	out := &bytes.Buffer{}
+	err := Watch(
+		ctx,
+		time.Millisecond,
+		out,
+		func(printer func(f string, a ...interface{})) error {
+			runs++
+			if runs == 2 {
+				return errors.New("broker unavailable")
+			}
+			if runs == 3 {
+				cancel()
+			}
+			printer("run %d", runs)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 3, runs)
+	assert.Contains(t, out.String(), "run 1\n")
+	assert.Contains(t, out.String(), "Error: broker unavailable\n")
+	assert.Contains(t, out.String(), "run 3\n")
+}
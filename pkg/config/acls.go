@@ -0,0 +1,240 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// ACLConfig represents the desired ACLs for a set of resources that don't belong to any single
+// topic, e.g. consumer groups, transactional IDs, or the cluster itself. ACLs on individual
+// topics should go in the acls section of the associated topic configs instead.
+type ACLConfig struct {
+	// APIVersion is the version of the config format. If unset, the config is assumed to be
+	// in the original (v0) format.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	Meta ACLMeta       `json:"meta"`
+	Spec ACLConfigSpec `json:"spec"`
+}
+
+// ACLMeta stores the metadata associated with a set of ACLs.
+type ACLMeta struct {
+	Name        string `json:"name"`
+	Cluster     string `json:"cluster"`
+	Region      string `json:"region"`
+	Environment string `json:"environment"`
+	Description string `json:"description"`
+}
+
+// ACLConfigSpec stores the resources whose ACLs are managed by an ACL config.
+type ACLConfigSpec struct {
+	Resources []ACLResourceConfig `json:"resources"`
+}
+
+// ACLResourceConfig stores the desired ACLs for a single resource. When the config is applied,
+// the ACLs on the resource in the cluster are reconciled against these; ACLs that aren't in the
+// config are deleted.
+type ACLResourceConfig struct {
+	// Type is the resource type, i.e. cluster, group, or transactionalId.
+	Type string `json:"type"`
+
+	// Name is the resource name. It's ignored for cluster resources, which are always named
+	// "kafka-cluster".
+	Name string `json:"name,omitempty"`
+
+	// PatternType is either literal (the default) or prefixed.
+	PatternType string `json:"patternType,omitempty"`
+
+	ACLs []ACLEntryConfig `json:"acls"`
+}
+
+// ACLEntryConfig allows or denies a principal one or more operations on a resource. A separate
+// ACL is created in the cluster for each operation.
+type ACLEntryConfig struct {
+	// Principal is the principal that the ACLs apply to, e.g. User:alice.
+	Principal string `json:"principal"`
+
+	// Operations are the names of the operations, e.g. read, describe, or idempotentWrite.
+	Operations []string `json:"operations,omitempty"`
+
+	// Role is the name of an ACL role, e.g. producer or consumer, whose operations on the
+	// resource type are included in addition to the ones above. See ResolveACLs for details.
+	Role string `json:"role,omitempty"`
+
+	// Host is the host that the principal connects from; it defaults to "*".
+	Host string `json:"host,omitempty"`
+
+	// Permission is either allow (the default) or deny.
+	Permission string `json:"permission,omitempty"`
+}
+
+var allACLResourceTypes = []string{
+	"cluster",
+	"group",
+	"transactionalId",
+}
+
+// ACLResource identifies a single resource whose ACLs are managed by an ACL config.
+type ACLResource struct {
+	Type        kafka.ResourceType
+	Name        string
+	PatternType kafka.PatternType
+}
+
+// String returns a human-readable version of the resource.
+func (r ACLResource) String() string {
+	return fmt.Sprintf("%s %s (%s)", r.Type, r.Name, r.PatternType)
+}
+
+// Filter returns a filter that matches all of the ACLs on the resource.
+func (r ACLResource) Filter() kafka.ACLFilter {
+	return kafka.ACLFilter{
+		ResourceTypeFilter:        r.Type,
+		ResourceNameFilter:        r.Name,
+		ResourcePatternTypeFilter: r.PatternType,
+		Operation:                 kafka.ACLOperationTypeAny,
+		PermissionType:            kafka.ACLPermissionTypeAny,
+	}
+}
+
+// Resources returns the resources in the config along with the desired ACLs for each one. The
+// ACLs for each resource are sorted via admin.SortACLs.
+func (a ACLConfig) Resources() ([]ACLResource, map[ACLResource][]admin.ACLInfo, error) {
+	resources := []ACLResource{}
+	resourceACLs := map[ACLResource][]admin.ACLInfo{}
+
+	for _, resourceConfig := range a.Spec.Resources {
+		resource, acls, err := resourceConfig.resolve()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if _, ok := resourceACLs[resource]; ok {
+			return nil, nil, fmt.Errorf("Resource %s is declared more than once", resource)
+		}
+		resources = append(resources, resource)
+		resourceACLs[resource] = acls
+	}
+
+	return resources, resourceACLs, nil
+}
+
+func (r ACLResourceConfig) resolve() (ACLResource, []admin.ACLInfo, error) {
+	validType := false
+	for _, resourceType := range allACLResourceTypes {
+		if r.Type == resourceType {
+			validType = true
+			break
+		}
+	}
+	if !validType {
+		return ACLResource{}, nil, fmt.Errorf(
+			"Resource type '%s' must be in %+v",
+			r.Type,
+			allACLResourceTypes,
+		)
+	}
+
+	acls := []admin.ACLInfo{}
+	seen := map[admin.ACLInfo]struct{}{}
+
+	for _, entry := range r.ACLs {
+		if entry.Role != "" {
+			return ACLResource{}, nil, fmt.Errorf(
+				"ACL role '%s' for principal '%s' has not been resolved",
+				entry.Role,
+				entry.Principal,
+			)
+		}
+
+		entryACLs, err := admin.ACLSpec{
+			ResourceType:   r.Type,
+			ResourceName:   r.Name,
+			PatternType:    r.PatternType,
+			Principals:     []string{entry.Principal},
+			Host:           entry.Host,
+			Operations:     entry.Operations,
+			PermissionType: entry.Permission,
+		}.ACLs()
+		if err != nil {
+			return ACLResource{}, nil, fmt.Errorf(
+				"Invalid ACL for principal '%s' on %s '%s': %+v",
+				entry.Principal,
+				r.Type,
+				r.Name,
+				err,
+			)
+		}
+
+		for _, acl := range entryACLs {
+			if _, ok := seen[acl]; ok {
+				continue
+			}
+			seen[acl] = struct{}{}
+			acls = append(acls, acl)
+		}
+	}
+
+	resource := ACLResource{
+		Name:        r.Name,
+		PatternType: kafka.PatternTypeLiteral,
+	}
+	if err := resource.Type.UnmarshalText([]byte(r.Type)); err != nil {
+		return ACLResource{}, nil, err
+	}
+	if resource.Type == kafka.ResourceTypeCluster {
+		resource.Name = "kafka-cluster"
+	}
+	if r.PatternType != "" {
+		if err := resource.PatternType.UnmarshalText([]byte(r.PatternType)); err != nil ||
+			(resource.PatternType != kafka.PatternTypeLiteral &&
+				resource.PatternType != kafka.PatternTypePrefixed) {
+			return ACLResource{}, nil, fmt.Errorf(
+				"Invalid pattern type '%s'; must be literal or prefixed",
+				r.PatternType,
+			)
+		}
+	}
+	if resource.Name == "" {
+		return ACLResource{}, nil, fmt.Errorf("Name must be set for %s resources", r.Type)
+	}
+
+	admin.SortACLs(acls)
+	return resource, acls, nil
+}
+
+// Validate evaluates whether the ACL config is valid.
+func (a ACLConfig) Validate() error {
+	var err error
+
+	if a.APIVersion != "" && !isValidAPIVersion(a.APIVersion) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("APIVersion must be in %+v", allAPIVersions),
+		)
+	}
+	if a.Meta.Name == "" {
+		err = multierror.Append(err, errors.New("Name must be set"))
+	}
+	if a.Meta.Cluster == "" {
+		err = multierror.Append(err, errors.New("Cluster must be set"))
+	}
+	if a.Meta.Region == "" {
+		err = multierror.Append(err, errors.New("Region must be set"))
+	}
+	if a.Meta.Environment == "" {
+		err = multierror.Append(err, errors.New("Environment must be set"))
+	}
+	if len(a.Spec.Resources) == 0 {
+		err = multierror.Append(err, errors.New("At least one resource must be set"))
+	}
+	if _, _, resourcesErr := a.Resources(); resourcesErr != nil {
+		err = multierror.Append(err, resourcesErr)
+	}
+
+	return err
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLConfigValidate(t *testing.T) {
+	type testCase struct {
+		description string
+		resources   []ACLResourceConfig
+		expError    bool
+	}
+
+	testCases := []testCase{
+		{
+			description: "all valid",
+			resources: []ACLResourceConfig{
+				{
+					Type: "cluster",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal:  "User:alice",
+							Operations: []string{"idempotentWrite"},
+						},
+					},
+				},
+				{
+					Type:        "group",
+					Name:        "group-",
+					PatternType: "prefixed",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal:  "User:alice",
+							Operations: []string{"read"},
+							Permission: "deny",
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "empty acls",
+			resources: []ACLResourceConfig{
+				{
+					Type: "group",
+					Name: "test-group",
+					ACLs: []ACLEntryConfig{},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "no resources",
+			resources:   []ACLResourceConfig{},
+			expError:    true,
+		},
+		{
+			description: "topic resource",
+			resources: []ACLResourceConfig{
+				{
+					Type: "topic",
+					Name: "test-topic",
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "missing name",
+			resources: []ACLResourceConfig{
+				{
+					Type: "transactionalId",
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "bad pattern type",
+			resources: []ACLResourceConfig{
+				{
+					Type:        "group",
+					Name:        "test-group",
+					PatternType: "match",
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "bad operation",
+			resources: []ACLResourceConfig{
+				{
+					Type: "group",
+					Name: "test-group",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal:  "User:alice",
+							Operations: []string{"explode"},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "duplicate resource",
+			resources: []ACLResourceConfig{
+				{
+					Type: "group",
+					Name: "test-group",
+				},
+				{
+					Type:        "group",
+					Name:        "test-group",
+					PatternType: "literal",
+				},
+			},
+			expError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		aclConfig := ACLConfig{
+			Meta: ACLMeta{
+				Name:        "test-acls",
+				Cluster:     "test-cluster",
+				Region:      "test-region",
+				Environment: "test-environment",
+			},
+			Spec: ACLConfigSpec{
+				Resources: testCase.resources,
+			},
+		}
+
+		err := aclConfig.Validate()
+		if testCase.expError {
+			assert.Error(t, err, testCase.description)
+		} else {
+			assert.NoError(t, err, testCase.description)
+		}
+	}
+}
+
+func TestACLConfigResources(t *testing.T) {
+	aclConfig := ACLConfig{
+		Spec: ACLConfigSpec{
+			Resources: []ACLResourceConfig{
+				{
+					Type: "transactionalId",
+					Name: "test-txn",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal:  "User:bob",
+							Operations: []string{"write"},
+						},
+						{
+							Principal:  "User:alice",
+							Operations: []string{"write", "describe"},
+						},
+						{
+							Principal:  "User:alice",
+							Operations: []string{"write"},
+						},
+					},
+				},
+				{
+					Type: "cluster",
+					Name: "ignored",
+				},
+			},
+		},
+	}
+
+	resources, resourceACLs, err := aclConfig.Resources()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]ACLResource{
+			{
+				Type:        kafka.ResourceTypeTransactionalID,
+				Name:        "test-txn",
+				PatternType: kafka.PatternTypeLiteral,
+			},
+			{
+				Type:        kafka.ResourceTypeCluster,
+				Name:        "kafka-cluster",
+				PatternType: kafka.PatternTypeLiteral,
+			},
+		},
+		resources,
+	)
+
+	aclStrs := []string{}
+	for _, acl := range resourceACLs[resources[0]] {
+		aclStrs = append(aclStrs, acl.String())
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"Allow Write on Transactionalid test-txn (Literal) from host * for User:alice",
+			"Allow Describe on Transactionalid test-txn (Literal) from host * for User:alice",
+			"Allow Write on Transactionalid test-txn (Literal) from host * for User:bob",
+		},
+		aclStrs,
+	)
+	assert.Equal(t, 0, len(resourceACLs[resources[1]]))
+}
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/ghodss/yaml"
 	"github.com/hashicorp/go-multierror"
 	"github.com/segmentio/topicctl/pkg/admin"
 	log "github.com/sirupsen/logrus"
@@ -17,6 +21,10 @@ import (
 // or more topic configs. These configs should reflect the reality of what's been
 // set up externally; there's no way to "apply" these at the moment.
 type ClusterConfig struct {
+	// APIVersion is the version of the config format. If unset, the config is assumed to be
+	// in the original (v0) format.
+	APIVersion string `json:"apiVersion,omitempty"`
+
 	Meta ClusterMeta `json:"meta"`
 	Spec ClusterSpec `json:"spec"`
 
@@ -52,6 +60,10 @@ type ClusterSpec struct {
 	// no locking will be used on apply operations.
 	ZKLockPath string `json:"zkLockPath"`
 
+	// ZKTLS stores how we should use TLS with zookeeper connections, if appropriate. Only
+	// applies if using the zk admin.
+	ZKTLS *TLSConfig `json:"zkTLS,omitempty"`
+
 	// ClusterID is the value of the [prefix]/cluster/id node in zookeeper. If set, it's used
 	// to validate that the cluster we're communicating with is the right one. If blank,
 	// this check isn't done.
@@ -72,6 +84,88 @@ type ClusterSpec struct {
 	// SASL stores how we should use SASL with broker connections, if appropriate. Only
 	// applies if using the broker admin.
 	SASL SASLConfig `json:"sasl"`
+
+	// Listeners are additional named broker listeners, e.g. ones that use a different port or
+	// SASL mechanism than the connection settings above. They're only used for the purposes that
+	// select them in ListenerPurposes.
+	Listeners map[string]ListenerConfig `json:"listeners,omitempty"`
+
+	// ListenerPurposes selects the listener that's used for each kind of connection, e.g. admin
+	// operations vs. tailing. If unset, all connections use the settings above.
+	ListenerPurposes *ListenerPurposes `json:"listenerPurposes,omitempty"`
+
+	// BrokerRacks is an optional mapping from broker ID to rack. If set, these values are used
+	// instead of the racks reported by the brokers, e.g. for clusters whose brokers don't have
+	// broker.rack set.
+	BrokerRacks map[int]string `json:"brokerRacks,omitempty"`
+
+	// BrokerWeights is an optional mapping from broker ID to the relative capacity of the broker,
+	// e.g. 2 for brokers with twice the disk and CPU of the others. Replicas and leaders are
+	// spread across the brokers in proportion to their weights; brokers that aren't listed have a
+	// weight of 1.
+	BrokerWeights map[int]float64 `json:"brokerWeights,omitempty"`
+
+	// UnmanagedTopicPatterns are regexps for the names of topics that are intentionally not
+	// managed by topicctl, e.g. Kafka Streams changelogs or Kafka Connect offsets topics. Topics
+	// matching any of these are skipped by bulk operations and can't be applied.
+	UnmanagedTopicPatterns []string `json:"unmanagedTopicPatterns,omitempty"`
+
+	// ProtectedTopicPatterns are regexps for the names of topics that are protected, in addition
+	// to the ones whose configs set meta.protected. Changes to the partitions, replication, or
+	// placement of protected topics, and deleting them, require an explicit --allow-protected.
+	ProtectedTopicPatterns []string `json:"protectedTopicPatterns,omitempty"`
+
+	// ConsumerGroupOwners maps consumer group IDs to the teams that own them. Along with the
+	// consumerGroups in topic configs, this tracks who reads each topic.
+	ConsumerGroupOwners map[string]string `json:"consumerGroupOwners,omitempty"`
+
+	// RequireDeclaredConsumerGroups makes the check subcommand verify that every group with
+	// active members in a topic is in the consumerGroups of the topic's config, even for topics
+	// whose configs don't list any.
+	RequireDeclaredConsumerGroups bool `json:"requireDeclaredConsumerGroups,omitempty"`
+
+	// TopicFileNaming is the policy for how topic config files must be named relative to the
+	// topics in them. If unset, file names are unconstrained.
+	TopicFileNaming TopicFileNamingPolicy `json:"topicFileNaming,omitempty"`
+
+	// SettingsProfiles are named sets of topic settings that topic configs in this cluster can
+	// reference via their profile field. These are in addition to the built-in profiles and
+	// take precedence over them if the names are the same.
+	SettingsProfiles map[string]TopicSettings `json:"settingsProfiles,omitempty"`
+
+	// ACLRoles are named bundles of ACL operations that topic and ACL configs in this cluster can
+	// reference via the role field of their ACLs. These are in addition to the built-in roles and
+	// take precedence over them if the names are the same.
+	ACLRoles map[string]ACLRole `json:"aclRoles,omitempty"`
+
+	// Principals maps symbolic names, e.g. team-payments, to the principals that they stand for in
+	// this cluster, e.g. User:CN=payments-prod. ACLs in topic and ACL configs can use these names
+	// instead of [type]:[name] principals so that the same configs work across environments.
+	Principals map[string]string `json:"principals,omitempty"`
+
+	// Quotas are the desired produce, fetch, and request quotas for users and client IDs in
+	// this cluster. These are applied via the apply-quotas subcommand; entities that aren't
+	// listed here are left alone.
+	Quotas []QuotaConfig `json:"quotas,omitempty"`
+
+	// Notifications are webhooks that are notified when apply changes topics in this cluster.
+	Notifications []NotificationConfig `json:"notifications,omitempty"`
+
+	// Events, if set, is where structured check results and apply outcomes for the topics in
+	// this cluster are published.
+	Events *EventsConfig `json:"events,omitempty"`
+
+	// RBAC, if set, is how the Confluent RBAC role bindings of this cluster are managed via the
+	// apply-role-bindings subcommand.
+	RBAC *RBACConfig `json:"rbac,omitempty"`
+
+	// BrokerMetrics, if set, is where the per-partition throughput metrics of the brokers are
+	// scraped from for throughput-aware rebalances.
+	BrokerMetrics *BrokerMetricsConfig `json:"brokerMetrics,omitempty"`
+
+	// CruiseControl, if set, is the Cruise Control instance that the rebalances and broker drains
+	// of this cluster are delegated to.
+	CruiseControl *CruiseControlConfig `json:"cruiseControl,omitempty"`
 }
 
 // TLSConfig contains the details required to use TLS in communication with broker clients.
@@ -102,21 +196,78 @@ type SASLConfig struct {
 	// Enabled is whether SASL is enabled.
 	Enabled bool `json:"enabled"`
 
-	// Mechanism is the name of the SASL mechanism. Valid values are AWS-MSK-IAM, PLAIN,
-	// SCRAM-SHA-256, and SCRAM-SHA-512 (case insensitive).
+	// Mechanism is the name of the SASL mechanism. Valid values are AWS-MSK-IAM, GSSAPI,
+	// OAUTHBEARER, PLAIN, SCRAM-SHA-256, and SCRAM-SHA-512 (case insensitive).
 	Mechanism string `json:"mechanism"`
 
-	// Username is the SASL username. Ignored if mechanism is AWS-MSK-IAM.
+	// Username is the SASL username, or the OAuth client ID if mechanism is OAUTHBEARER. Ignored
+	// if mechanism is AWS-MSK-IAM.
 	Username string `json:"username"`
 
-	// Password is the SASL password. Ignored if mechanism is AWS-MSK-IAM.
+	// Password is the SASL password, or the OAuth client secret if mechanism is OAUTHBEARER.
+	// Ignored if mechanism is AWS-MSK-IAM.
 	Password string `json:"password"`
+
+	// PasswordFrom, if set, references the environment variable or command that the SASL
+	// password is fetched from instead of storing it in the config. It can't be set along with
+	// Password.
+	PasswordFrom *SecretRef `json:"passwordFrom,omitempty"`
+
+	// GSSAPI stores the Kerberos settings. It's required if mechanism is GSSAPI and ignored
+	// otherwise.
+	GSSAPI *GSSAPIConfig `json:"gssapi,omitempty"`
+
+	// OAuth stores the settings for getting tokens from the identity provider. It's required if
+	// mechanism is OAUTHBEARER and ignored otherwise.
+	OAuth *OAuthConfig `json:"oauth,omitempty"`
+}
+
+// OAuthConfig contains the details required to get tokens via the OAuth client credentials flow
+// for SASL OAUTHBEARER.
+type OAuthConfig struct {
+	// TokenURL is the token endpoint of the identity provider, e.g.
+	// https://login.example.com/oauth2/token.
+	TokenURL string `json:"tokenURL"`
+
+	// Scopes are the scopes to request (optional).
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Extensions are SASL extensions to send to the brokers along with the token (optional),
+	// e.g. logicalCluster and identityPoolId for Confluent Cloud.
+	Extensions map[string]string `json:"extensions,omitempty"`
+}
+
+// GSSAPIConfig contains the Kerberos details required to authenticate via SASL GSSAPI.
+type GSSAPIConfig struct {
+	// Principal is the client principal, e.g. topicctl@EXAMPLE.COM. If the realm is omitted,
+	// the default realm in the Kerberos config is used.
+	Principal string `json:"principal"`
+
+	// KeytabPath is the path to a keytab with the principal's keys. If it's unset, the SASL
+	// password (or passwordFrom) is used to log in instead.
+	KeytabPath string `json:"keytabPath,omitempty"`
+
+	// KerberosConfigPath is the path to the krb5.conf file with the realm and KDC settings. It
+	// defaults to /etc/krb5.conf.
+	KerberosConfigPath string `json:"kerberosConfigPath,omitempty"`
+
+	// ServiceName is the Kerberos service name of the brokers. It defaults to kafka.
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// DisablePAFXFAST disables PA-FX-FAST pre-authentication, e.g. for Active Directory KDCs.
+	DisablePAFXFAST bool `json:"disablePAFXFAST,omitempty"`
 }
 
 // Validate evaluates whether the cluster config is valid.
 func (c ClusterConfig) Validate() error {
 	var err error
 
+	if c.APIVersion != "" && !isValidAPIVersion(c.APIVersion) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("APIVersion must be in %+v", allAPIVersions),
+		)
+	}
 	if c.Meta.Name == "" {
 		err = multierror.Append(err, errors.New("Name must be set"))
 	}
@@ -154,17 +305,204 @@ func (c ClusterConfig) Validate() error {
 			errors.New("SASL not supported with zk access mode; omit zk addresses to fix"),
 		)
 	}
+	if c.Spec.ZKTLS != nil && c.Spec.ZKTLS.Enabled && len(c.Spec.ZKAddrs) == 0 {
+		err = multierror.Append(
+			err,
+			errors.New("ZK TLS requires zk access mode; set zk addresses or omit zkTLS to fix"),
+		)
+	}
+
+	for _, pattern := range c.Spec.UnmanagedTopicPatterns {
+		if _, compileErr := regexp.Compile(pattern); compileErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid unmanaged topic pattern '%s': %+v", pattern, compileErr),
+			)
+		}
+	}
+
+	for _, pattern := range c.Spec.ProtectedTopicPatterns {
+		if _, compileErr := regexp.Compile(pattern); compileErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid protected topic pattern '%s': %+v", pattern, compileErr),
+			)
+		}
+	}
+
+	ownedGroups := []string{}
+	for group := range c.Spec.ConsumerGroupOwners {
+		ownedGroups = append(ownedGroups, group)
+	}
+	sort.Strings(ownedGroups)
+
+	for _, group := range ownedGroups {
+		if len(group) > maxConsumerGroupLength || !consumerGroupRegexp.MatchString(group) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Consumer group owner key '%s' must be 1-%d characters from [a-zA-Z0-9._-]",
+					group,
+					maxConsumerGroupLength,
+				),
+			)
+		}
+		if c.Spec.ConsumerGroupOwners[group] == "" {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Owner of consumer group '%s' must be non-empty", group),
+			)
+		}
+	}
+
+	if c.Spec.TopicFileNaming != "" && !isValidTopicFileNamingPolicy(c.Spec.TopicFileNaming) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("TopicFileNaming must be in %+v", allTopicFileNamingPolicies),
+		)
+	}
+
+	for brokerID, rack := range c.Spec.BrokerRacks {
+		if brokerID < 0 {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Broker ID %d in brokerRacks must be >= 0", brokerID),
+			)
+		}
+		if rack == "" {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Rack for broker %d in brokerRacks must be set", brokerID),
+			)
+		}
+	}
+
+	for brokerID, weight := range c.Spec.BrokerWeights {
+		if brokerID < 0 {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Broker ID %d in brokerWeights must be >= 0", brokerID),
+			)
+		}
+		if weight <= 0 {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Weight for broker %d in brokerWeights must be positive", brokerID),
+			)
+		}
+	}
+
+	for name, settings := range c.Spec.SettingsProfiles {
+		if settingsErr := settings.Validate(); settingsErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid settings in profile %s: %+v", name, settingsErr),
+			)
+		}
+	}
+
+	for name, role := range c.Spec.ACLRoles {
+		if roleErr := role.Validate(); roleErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid ACL role %s: %+v", name, roleErr),
+			)
+		}
+	}
+
+	for name, principal := range c.Spec.Principals {
+		if name == "" || strings.Contains(name, ":") {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Principal name '%s' must be non-empty and not contain ':'", name),
+			)
+		}
+		if !strings.Contains(principal, ":") {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Principal '%s' for name %s must be in the form [type]:[name], e.g. User:alice",
+					principal,
+					name,
+				),
+			)
+		}
+	}
+
+	quotaEntities := map[admin.QuotaEntity]struct{}{}
+	for _, quota := range c.Spec.Quotas {
+		if quotaErr := quota.Validate(); quotaErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid quota for %s: %+v", quota.Entity(), quotaErr),
+			)
+		}
+		if _, ok := quotaEntities[quota.Entity()]; ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Quota for %s is set more than once", quota.Entity()),
+			)
+		}
+		quotaEntities[quota.Entity()] = struct{}{}
+	}
+
+	for n, notification := range c.Spec.Notifications {
+		if notificationErr := notification.Validate(); notificationErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid notification %d: %+v", n, notificationErr),
+			)
+		}
+	}
+
+	if c.Spec.Events != nil {
+		if eventsErr := c.Spec.Events.Validate(); eventsErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid events config: %+v", eventsErr),
+			)
+		}
+	}
+
+	if listenersErr := c.validateListeners(); listenersErr != nil {
+		err = multierror.Append(err, listenersErr)
+	}
+	if c.Spec.RBAC != nil {
+		if rbacErr := c.Spec.RBAC.Validate(); rbacErr != nil {
+			err = multierror.Append(err, fmt.Errorf("Invalid rbac config: %+v", rbacErr))
+		}
+	}
+	if c.Spec.BrokerMetrics != nil {
+		if metricsErr := c.Spec.BrokerMetrics.Validate(); metricsErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid brokerMetrics config: %+v", metricsErr),
+			)
+		}
+	}
+	if c.Spec.CruiseControl != nil {
+		if ccErr := c.Spec.CruiseControl.Validate(); ccErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid cruiseControl config: %+v", ccErr),
+			)
+		}
+	}
 
 	if c.Spec.SASL.Enabled {
-		saslMechanism, saslErr := admin.SASLNameToMechanism(c.Spec.SASL.Mechanism)
-		if saslErr != nil {
+		if saslErr := c.Spec.SASL.Validate(); saslErr != nil {
 			err = multierror.Append(err, saslErr)
 		}
 
+		saslMechanism, _ := admin.SASLNameToMechanism(c.Spec.SASL.Mechanism)
+
 		if saslMechanism == admin.SASLMechanismAWSMSKIAM &&
 			(c.Spec.SASL.Username != "" || c.Spec.SASL.Password != "") {
 			log.Warn("Username and password are ignored if using SASL AWS-MSK-IAM")
 		}
+		if saslMechanism == admin.SASLMechanismGSSAPI && c.Spec.SASL.Username != "" {
+			log.Warn("Username is ignored if using SASL GSSAPI; set gssapi.principal instead")
+		}
 	}
 
 	return err
@@ -181,6 +519,7 @@ func (c ClusterConfig) GetDefaultRetentionDropStepDuration() (time.Duration, err
 }
 
 // NewAdminClient returns a new admin client using the parameters in the current cluster config.
+// The connection settings of the listener selected for admin connections, if any, are used.
 func (c ClusterConfig) NewAdminClient(
 	ctx context.Context,
 	sess *session.Session,
@@ -188,57 +527,27 @@ func (c ClusterConfig) NewAdminClient(
 	usernameOverride string,
 	passwordOverride string,
 ) (admin.Client, error) {
+	c, err := c.ForPurpose(ListenerPurposeAdmin)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(c.Spec.ZKAddrs) == 0 {
 		log.Debug("No ZK addresses provided, using broker admin client")
 
-		var saslUsername string
-		var saslPassword string
-		if usernameOverride != "" {
-			log.Debugf("Setting SASL username from override value")
-			saslUsername = usernameOverride
-		} else {
-			saslUsername = c.Spec.SASL.Username
-		}
-
-		if passwordOverride != "" {
-			log.Debugf("Setting SASL password from override value")
-			saslPassword = passwordOverride
-		} else {
-			saslPassword = c.Spec.SASL.Password
-		}
-
-		var saslMechanism admin.SASLMechanism
-		var err error
-
-		if c.Spec.SASL.Mechanism != "" {
-			saslMechanism, err = admin.SASLNameToMechanism(c.Spec.SASL.Mechanism)
-			if err != nil {
-				return nil, err
-			}
+		connectorConfig, err := c.connectorConfig(ctx, usernameOverride, passwordOverride)
+		if err != nil {
+			return nil, err
 		}
 
 		return admin.NewBrokerAdminClient(
 			ctx,
 			admin.BrokerAdminClientConfig{
-				ConnectorConfig: admin.ConnectorConfig{
-					BrokerAddr: c.Spec.BootstrapAddrs[0],
-					TLS: admin.TLSConfig{
-						Enabled:    c.Spec.TLS.Enabled,
-						CACertPath: c.absPath(c.Spec.TLS.CACertPath),
-						CertPath:   c.absPath(c.Spec.TLS.CertPath),
-						KeyPath:    c.absPath(c.Spec.TLS.KeyPath),
-						ServerName: c.Spec.TLS.ServerName,
-						SkipVerify: c.Spec.TLS.SkipVerify,
-					},
-					SASL: admin.SASLConfig{
-						Enabled:   c.Spec.SASL.Enabled,
-						Mechanism: saslMechanism,
-						Username:  saslUsername,
-						Password:  saslPassword,
-					},
-				},
+				ConnectorConfig:   connectorConfig,
 				ExpectedClusterID: c.Spec.ClusterID,
 				ReadOnly:          readOnly,
+				BrokerRacks:       c.Spec.BrokerRacks,
+				BrokerWeights:     c.Spec.BrokerWeights,
 			},
 		)
 	} else {
@@ -252,11 +561,181 @@ func (c ClusterConfig) NewAdminClient(
 				ExpectedClusterID: c.Spec.ClusterID,
 				Sess:              sess,
 				ReadOnly:          readOnly,
+				BrokerRacks:       c.Spec.BrokerRacks,
+				BrokerWeights:     c.Spec.BrokerWeights,
+				TLS:               c.zkTLSConfig(),
 			},
 		)
 	}
 }
 
+// NewConnector returns a new connector to the first bootstrap address in the current cluster
+// config, using its TLS and SASL settings. Unlike NewAdminClient, this never uses zookeeper.
+func (c ClusterConfig) NewConnector() (*admin.Connector, error) {
+	c, err := c.ForPurpose(ListenerPurposeAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	connectorConfig, err := c.connectorConfig(context.Background(), "", "")
+	if err != nil {
+		return nil, err
+	}
+	return admin.NewConnector(connectorConfig)
+}
+
+func (c ClusterConfig) connectorConfig(
+	ctx context.Context,
+	usernameOverride string,
+	passwordOverride string,
+) (admin.ConnectorConfig, error) {
+	var saslUsername string
+	var saslPassword string
+	if usernameOverride != "" {
+		log.Debugf("Setting SASL username from override value")
+		saslUsername = usernameOverride
+	} else {
+		saslUsername = c.Spec.SASL.Username
+	}
+
+	if passwordOverride != "" {
+		log.Debugf("Setting SASL password from override value")
+		saslPassword = passwordOverride
+	} else if c.Spec.SASL.Enabled && c.Spec.SASL.PasswordFrom != nil {
+		log.Debugf("Setting SASL password from %s", c.Spec.SASL.PasswordFrom)
+		var err error
+		saslPassword, err = c.Spec.SASL.PasswordFrom.Resolve(ctx)
+		if err != nil {
+			return admin.ConnectorConfig{}, fmt.Errorf("Could not get SASL password: %+v", err)
+		}
+	} else {
+		saslPassword = c.Spec.SASL.Password
+	}
+
+	var saslMechanism admin.SASLMechanism
+	var err error
+
+	if c.Spec.SASL.Mechanism != "" {
+		saslMechanism, err = admin.SASLNameToMechanism(c.Spec.SASL.Mechanism)
+		if err != nil {
+			return admin.ConnectorConfig{}, err
+		}
+	}
+
+	return admin.ConnectorConfig{
+		BrokerAddr: c.Spec.BootstrapAddrs[0],
+		TLS: admin.TLSConfig{
+			Enabled:    c.Spec.TLS.Enabled,
+			CACertPath: c.absPath(c.Spec.TLS.CACertPath),
+			CertPath:   c.absPath(c.Spec.TLS.CertPath),
+			KeyPath:    c.absPath(c.Spec.TLS.KeyPath),
+			ServerName: c.Spec.TLS.ServerName,
+			SkipVerify: c.Spec.TLS.SkipVerify,
+		},
+		SASL: admin.SASLConfig{
+			Enabled:   c.Spec.SASL.Enabled,
+			Mechanism: saslMechanism,
+			Username:  saslUsername,
+			Password:  saslPassword,
+			GSSAPI:    c.gssapiConfig(),
+			OAuth:     c.oauthConfig(),
+		},
+	}, nil
+}
+
+// CredentialFiles returns the absolute paths of the TLS certificate and key files and the
+// Kerberos keytab and config files that are used for connections of the argument purpose, if
+// any.
+func (c ClusterConfig) CredentialFiles(purpose string) ([]string, error) {
+	c, err := c.ForPurpose(purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	relPaths := []string{}
+	if c.Spec.TLS.Enabled {
+		relPaths = append(
+			relPaths,
+			c.Spec.TLS.CACertPath,
+			c.Spec.TLS.CertPath,
+			c.Spec.TLS.KeyPath,
+		)
+	}
+	if c.Spec.ZKTLS != nil && c.Spec.ZKTLS.Enabled && len(c.Spec.ZKAddrs) > 0 {
+		relPaths = append(
+			relPaths,
+			c.Spec.ZKTLS.CACertPath,
+			c.Spec.ZKTLS.CertPath,
+			c.Spec.ZKTLS.KeyPath,
+		)
+	}
+	if c.Spec.SASL.Enabled && c.Spec.SASL.GSSAPI != nil {
+		saslMechanism, _ := admin.SASLNameToMechanism(c.Spec.SASL.Mechanism)
+		if saslMechanism == admin.SASLMechanismGSSAPI {
+			kerberosConfigPath := c.Spec.SASL.GSSAPI.KerberosConfigPath
+			if kerberosConfigPath == "" {
+				kerberosConfigPath = admin.DefaultKerberosConfigPath
+			}
+			relPaths = append(relPaths, c.Spec.SASL.GSSAPI.KeytabPath, kerberosConfigPath)
+		}
+	}
+
+	paths := []string{}
+	for _, path := range relPaths {
+		if path == "" {
+			continue
+		}
+		absPath, err := filepath.Abs(c.absPath(path))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, absPath)
+	}
+
+	return paths, nil
+}
+
+func (c ClusterConfig) zkTLSConfig() admin.TLSConfig {
+	if c.Spec.ZKTLS == nil {
+		return admin.TLSConfig{}
+	}
+
+	return admin.TLSConfig{
+		Enabled:    c.Spec.ZKTLS.Enabled,
+		CACertPath: c.absPath(c.Spec.ZKTLS.CACertPath),
+		CertPath:   c.absPath(c.Spec.ZKTLS.CertPath),
+		KeyPath:    c.absPath(c.Spec.ZKTLS.KeyPath),
+		ServerName: c.Spec.ZKTLS.ServerName,
+		SkipVerify: c.Spec.ZKTLS.SkipVerify,
+	}
+}
+
+func (c ClusterConfig) gssapiConfig() admin.GSSAPIConfig {
+	if c.Spec.SASL.GSSAPI == nil {
+		return admin.GSSAPIConfig{}
+	}
+
+	return admin.GSSAPIConfig{
+		Principal:          c.Spec.SASL.GSSAPI.Principal,
+		KeytabPath:         c.absPath(c.Spec.SASL.GSSAPI.KeytabPath),
+		KerberosConfigPath: c.absPath(c.Spec.SASL.GSSAPI.KerberosConfigPath),
+		ServiceName:        c.Spec.SASL.GSSAPI.ServiceName,
+		DisablePAFXFAST:    c.Spec.SASL.GSSAPI.DisablePAFXFAST,
+	}
+}
+
+func (c ClusterConfig) oauthConfig() admin.OAuthConfig {
+	if c.Spec.SASL.OAuth == nil {
+		return admin.OAuthConfig{}
+	}
+
+	return admin.OAuthConfig{
+		TokenURL:   c.Spec.SASL.OAuth.TokenURL,
+		Scopes:     c.Spec.SASL.OAuth.Scopes,
+		Extensions: c.Spec.SASL.OAuth.Extensions,
+	}
+}
+
 func (c ClusterConfig) absPath(relPath string) string {
 	if relPath == "" || c.RootDir == "" || filepath.IsAbs(relPath) {
 		return relPath
@@ -264,3 +743,71 @@ func (c ClusterConfig) absPath(relPath string) string {
 
 	return filepath.Join(c.RootDir, relPath)
 }
+
+// ToYAML converts the current ClusterConfig to a YAML string. Relative TLS paths are made
+// absolute so that the result can be written to a different directory than the one that the
+// config was loaded from.
+func (c ClusterConfig) ToYAML() (string, error) {
+	c.Spec.TLS.CACertPath = c.absPath(c.Spec.TLS.CACertPath)
+	c.Spec.TLS.CertPath = c.absPath(c.Spec.TLS.CertPath)
+	c.Spec.TLS.KeyPath = c.absPath(c.Spec.TLS.KeyPath)
+	if c.Spec.ZKTLS != nil {
+		zkTLSConfig := *c.Spec.ZKTLS
+		zkTLSConfig.CACertPath = c.absPath(zkTLSConfig.CACertPath)
+		zkTLSConfig.CertPath = c.absPath(zkTLSConfig.CertPath)
+		zkTLSConfig.KeyPath = c.absPath(zkTLSConfig.KeyPath)
+		c.Spec.ZKTLS = &zkTLSConfig
+	}
+	if c.Spec.SASL.GSSAPI != nil {
+		gssapiConfig := *c.Spec.SASL.GSSAPI
+		gssapiConfig.KeytabPath = c.absPath(gssapiConfig.KeytabPath)
+		gssapiConfig.KerberosConfigPath = c.absPath(gssapiConfig.KerberosConfigPath)
+		c.Spec.SASL.GSSAPI = &gssapiConfig
+	}
+
+	outBytes, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(outBytes), nil
+}
+
+// UnmanagedTopicPattern returns the first pattern in the cluster's unmanaged topic patterns that
+// matches the argument topic name, or an empty string if none match. Invalid patterns are
+// ignored; these are caught during validation.
+func (c ClusterConfig) UnmanagedTopicPattern(topic string) string {
+	return matchingTopicPattern(c.Spec.UnmanagedTopicPatterns, topic)
+}
+
+// IsUnmanagedTopic returns whether the argument topic matches any of the cluster's unmanaged
+// topic patterns.
+func (c ClusterConfig) IsUnmanagedTopic(topic string) bool {
+	return c.UnmanagedTopicPattern(topic) != ""
+}
+
+// ProtectedTopicPattern returns the first pattern in the cluster's protected topic patterns that
+// matches the argument topic name, or an empty string if none match. Invalid patterns are
+// ignored; these are caught during validation.
+func (c ClusterConfig) ProtectedTopicPattern(topic string) string {
+	return matchingTopicPattern(c.Spec.ProtectedTopicPatterns, topic)
+}
+
+// ConsumerGroupOwner returns the owner of the argument consumer group in the cluster's consumer
+// group owners, or an empty string if it doesn't have one.
+func (c ClusterConfig) ConsumerGroupOwner(group string) string {
+	return c.Spec.ConsumerGroupOwners[group]
+}
+
+func matchingTopicPattern(patterns []string, topic string) string {
+	for _, pattern := range patterns {
+		patternRegexp, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		if patternRegexp.MatchString(topic) {
+			return pattern
+		}
+	}
+
+	return ""
+}
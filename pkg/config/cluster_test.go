@@ -1,9 +1,14 @@
 package config
 
 import (
+	"fmt"
+	"regexp"
 	"testing"
 
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/cruisecontrol"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClusterValidate(t *testing.T) {
@@ -92,6 +97,839 @@ func TestClusterValidate(t *testing.T) {
 			},
 			expError: true,
 		},
+		{
+			description: "broker rack overrides",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ZKAddrs:        []string{"zk-addr"},
+					BrokerRacks: map[int]string{
+						1: "rack1",
+						2: "rack2",
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "bad unmanaged topic pattern",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs:         []string{"broker-addr"},
+					ZKAddrs:                []string{"zk-addr"},
+					UnmanagedTopicPatterns: []string{"-changelog$", "(bad"},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "bad protected topic pattern",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs:         []string{"broker-addr"},
+					ZKAddrs:                []string{"zk-addr"},
+					ProtectedTopicPatterns: []string{"^payments-", "(bad"},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good consumer group owners",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ZKAddrs:        []string{"zk-addr"},
+					ConsumerGroupOwners: map[string]string{
+						"payments-service": "payments",
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "bad consumer group owner key",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ZKAddrs:        []string{"zk-addr"},
+					ConsumerGroupOwners: map[string]string{
+						"payments service": "payments",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "empty consumer group owner",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ZKAddrs:        []string{"zk-addr"},
+					ConsumerGroupOwners: map[string]string{
+						"payments-service": "",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "bad topic file naming policy",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs:  []string{"broker-addr"},
+					ZKAddrs:         []string{"zk-addr"},
+					TopicFileNaming: "non-existent-policy",
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "bad broker rack overrides",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ZKAddrs:        []string{"zk-addr"},
+					BrokerRacks: map[int]string{
+						-1: "rack1",
+						2:  "",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good broker weights",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ZKAddrs:        []string{"zk-addr"},
+					BrokerWeights: map[int]float64{
+						1: 2.0,
+						2: 0.5,
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "bad broker weights",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ZKAddrs:        []string{"zk-addr"},
+					BrokerWeights: map[int]float64{
+						-1: 2.0,
+						2:  0,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good quotas",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					Quotas: []QuotaConfig{
+						{
+							User: "user1",
+							Values: map[string]float64{
+								"producer_byte_rate": 1024,
+							},
+						},
+						{
+							User:     "user1",
+							ClientID: "<default>",
+							Values: map[string]float64{
+								"request_percentage": 50,
+							},
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "bad quotas",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					Quotas: []QuotaConfig{
+						{
+							User: "user1",
+							Values: map[string]float64{
+								"bad_key": 1024,
+							},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "duplicate quotas",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					Quotas: []QuotaConfig{
+						{
+							User: "user1",
+							Values: map[string]float64{
+								"producer_byte_rate": 1024,
+							},
+						},
+						{
+							User: "user1",
+							Values: map[string]float64{
+								"consumer_byte_rate": 1024,
+							},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good notifications",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					Notifications: []NotificationConfig{
+						{
+							URL:    "https://hooks.slack.com/services/test",
+							Format: NotificationFormatSlack,
+							Events: []NotificationEvent{NotificationEventFailure},
+						},
+						{
+							URL: "http://webhooks.example.com/topicctl",
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "bad notifications",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					Notifications: []NotificationConfig{
+						{
+							URL:    "ftp://webhooks.example.com",
+							Format: "teams",
+							Events: []NotificationEvent{"finish"},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good events",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					Events: &EventsConfig{
+						Topic:         "topicctl-events",
+						ClusterConfig: "../ops/cluster.yaml",
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "bad events",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					Events:         &EventsConfig{},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good acl roles and principals",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ACLRoles: map[string]ACLRole{
+						"auditor": {
+							"topic": {"describe", "describeConfigs"},
+						},
+					},
+					Principals: map[string]string{
+						"team-payments": "User:CN=payments-prod",
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "bad acl roles",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					ACLRoles: map[string]ACLRole{
+						"auditor": {
+							"topics": {"describe"},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "bad principals",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					Principals: map[string]string{
+						"team-payments": "payments-prod",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good listeners",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					Listeners: map[string]ListenerConfig{
+						"internal": {
+							Port: 9094,
+							SASL: &SASLConfig{
+								Enabled:   true,
+								Mechanism: "SCRAM-SHA-512",
+								Username:  "admin",
+								PasswordFrom: &SecretRef{
+									FromEnv: "ADMIN_PASSWORD",
+								},
+							},
+						},
+					},
+					ListenerPurposes: &ListenerPurposes{
+						Admin: "internal",
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "unknown listener",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					Listeners: map[string]ListenerConfig{
+						"internal": {
+							Port: 9094,
+						},
+					},
+					ListenerPurposes: &ListenerPurposes{
+						Tail: "external",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "bad listener",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					Listeners: map[string]ListenerConfig{
+						"internal": {
+							BootstrapAddrs: []string{"broker-addr:9094"},
+							Port:           9094,
+							SASL: &SASLConfig{
+								Enabled:   true,
+								Mechanism: "SCRAM-SHA-512",
+								Password:  "password",
+								PasswordFrom: &SecretRef{
+									FromEnv: "ADMIN_PASSWORD",
+								},
+							},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "zk tls in zk mode",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					ZKAddrs:        []string{"zk-addr:2182"},
+					ZKTLS: &TLSConfig{
+						Enabled:    true,
+						CACertPath: "certs/zk-ca.crt",
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "zk tls without zk addresses",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					ZKTLS: &TLSConfig{
+						Enabled:    true,
+						CACertPath: "certs/zk-ca.crt",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "listener sasl in zk mode",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					ZKAddrs:        []string{"zk-addr"},
+					Listeners: map[string]ListenerConfig{
+						"internal": {
+							SASL: &SASLConfig{
+								Enabled:   true,
+								Mechanism: "PLAIN",
+							},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good gssapi",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					SASL: SASLConfig{
+						Enabled:   true,
+						Mechanism: "GSSAPI",
+						GSSAPI: &GSSAPIConfig{
+							Principal:  "topicctl@EXAMPLE.COM",
+							KeytabPath: "topicctl.keytab",
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "gssapi without principal",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					SASL: SASLConfig{
+						Enabled:   true,
+						Mechanism: "GSSAPI",
+						GSSAPI: &GSSAPIConfig{
+							KeytabPath: "topicctl.keytab",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "gssapi without keytab or password",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					SASL: SASLConfig{
+						Enabled:   true,
+						Mechanism: "GSSAPI",
+						GSSAPI: &GSSAPIConfig{
+							Principal: "topicctl@EXAMPLE.COM",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good oauthbearer",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					SASL: SASLConfig{
+						Enabled:   true,
+						Mechanism: "OAUTHBEARER",
+						Username:  "test-client",
+						Password:  "test-secret",
+						OAuth: &OAuthConfig{
+							TokenURL: "https://idp.example.com/oauth2/token",
+							Scopes:   []string{"kafka"},
+							Extensions: map[string]string{
+								"logicalCluster": "lkc-123",
+							},
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "oauthbearer without oauth settings",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					SASL: SASLConfig{
+						Enabled:   true,
+						Mechanism: "OAUTHBEARER",
+						Username:  "test-client",
+						Password:  "test-secret",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "oauthbearer with bad token URL",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					SASL: SASLConfig{
+						Enabled:   true,
+						Mechanism: "OAUTHBEARER",
+						Username:  "test-client",
+						Password:  "test-secret",
+						OAuth: &OAuthConfig{
+							TokenURL: "idp.example.com",
+							Scopes:   []string{"kafka"},
+							Extensions: map[string]string{
+								"logicalCluster": "lkc-123",
+							},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "oauthbearer without client secret",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					SASL: SASLConfig{
+						Enabled:   true,
+						Mechanism: "OAUTHBEARER",
+						Username:  "test-client",
+						OAuth: &OAuthConfig{
+							TokenURL: "https://idp.example.com/oauth2/token",
+							Scopes:   []string{"kafka"},
+							Extensions: map[string]string{
+								"logicalCluster": "lkc-123",
+							},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "oauthbearer with bad extension key",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "test-description",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr:9092"},
+					SASL: SASLConfig{
+						Enabled:   true,
+						Mechanism: "OAUTHBEARER",
+						Username:  "test-client",
+						Password:  "test-secret",
+						OAuth: &OAuthConfig{
+							TokenURL: "https://idp.example.com/oauth2/token",
+							Scopes:   []string{"kafka"},
+							Extensions: map[string]string{
+								"auth": "lkc-123",
+							},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "valid broker metrics",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					BrokerMetrics: &BrokerMetricsConfig{
+						URLTemplate:    "http://{host}:7071/metrics",
+						Metric:         "kafka_log_log_logendoffset",
+						SampleInterval: "1m",
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "invalid broker metrics",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					BrokerMetrics: &BrokerMetricsConfig{
+						URLTemplate:    "{host}:7071",
+						Type:           "histogram",
+						SampleInterval: "soon",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "valid cruise control",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					CruiseControl: &CruiseControlConfig{
+						URL:          "http://cruise-control:9090/kafkacruisecontrol",
+						Goals:        []string{"RackAwareGoal"},
+						PollInterval: "30s",
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "invalid cruise control",
+			clusterConfig: ClusterConfig{
+				Meta: ClusterMeta{
+					Name:        "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: ClusterSpec{
+					BootstrapAddrs: []string{"broker-addr"},
+					CruiseControl: &CruiseControlConfig{
+						URL:          "cruise-control:9090",
+						PollInterval: "-1s",
+					},
+				},
+			},
+			expError: true,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -103,3 +941,224 @@ func TestClusterValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestClusterCruiseControlRequest(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			DefaultThrottleMB: 50,
+			CruiseControl: &CruiseControlConfig{
+				URL:            "http://cruise-control:9090/kafkacruisecontrol",
+				Goals:          []string{"RackAwareGoal", "DiskCapacityGoal"},
+				ExcludedTopics: "__.*",
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		cruisecontrol.OptimizationRequest{
+			DryRun:              true,
+			Goals:               []string{"RackAwareGoal", "DiskCapacityGoal"},
+			ExcludedTopics:      "__.*",
+			ReplicationThrottle: 50000000,
+		},
+		clusterConfig.CruiseControlRequest(true, 0),
+	)
+	assert.Equal(
+		t,
+		cruisecontrol.OptimizationRequest{
+			Goals:               []string{"RackAwareGoal", "DiskCapacityGoal"},
+			ExcludedTopics:      "__.*",
+			ReplicationThrottle: 10000000,
+		},
+		clusterConfig.CruiseControlRequest(false, 10),
+	)
+}
+
+func TestClusterCruiseControlExcludedTopics(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name: "test-cluster",
+		},
+		Spec: ClusterSpec{
+			CruiseControl: &CruiseControlConfig{
+				URL:            "http://cruise-control:9090/kafkacruisecontrol",
+				ExcludedTopics: "__.*",
+			},
+			ProtectedTopicPatterns: []string{"^payments-"},
+		},
+	}
+
+	topicConfigs := []TopicConfig{
+		{
+			Meta: TopicMeta{
+				Name:      "orders.v1",
+				Protected: true,
+			},
+		},
+		{
+			Meta: TopicMeta{
+				Name: "search",
+			},
+		},
+		{
+			Meta: TopicMeta{
+				Name: "payments-ledger",
+			},
+		},
+		{
+			Meta: TopicMeta{
+				Name:      "audit",
+				Protected: true,
+			},
+		},
+	}
+
+	excluded := clusterConfig.CruiseControlExcludedTopics(topicConfigs, false)
+	assert.Equal(
+		t,
+		`(?:__.*)|(?:.*(?:^payments-).*)|(?:^(?:audit|orders\.v1|payments-ledger)$)`,
+		excluded,
+	)
+
+	// Cruise Control matches the regexp against whole topic names
+	excludedRegexp := regexp.MustCompile(fmt.Sprintf("^(?:%s)$", excluded))
+	for topic, expected := range map[string]bool{
+		"__consumer_offsets": true,
+		"audit":              true,
+		"audit-v2":           false,
+		"orders.v1":          true,
+		"ordersXv1":          false,
+		"payments-ledger":    true,
+		"payments-refunds":   true,
+		"search":             false,
+	} {
+		assert.Equal(t, expected, excludedRegexp.MatchString(topic), topic)
+	}
+
+	// Only the configured regexp is used if protected topics are allowed
+	assert.Equal(t, "__.*", clusterConfig.CruiseControlExcludedTopics(topicConfigs, true))
+
+	clusterConfig.Spec.CruiseControl.ExcludedTopics = ""
+	clusterConfig.Spec.ProtectedTopicPatterns = []string{"-audit$"}
+	assert.Equal(
+		t,
+		`(?:.*(?:-audit$).*)|(?:^(?:audit|orders\.v1)$)`,
+		clusterConfig.CruiseControlExcludedTopics(topicConfigs, false),
+	)
+
+	clusterConfig.Spec.ProtectedTopicPatterns = nil
+	assert.Equal(
+		t,
+		`^(?:audit|orders\.v1)$`,
+		clusterConfig.CruiseControlExcludedTopics(topicConfigs, false),
+	)
+	assert.Equal(t, "", clusterConfig.CruiseControlExcludedTopics(topicConfigs, true))
+}
+
+func TestClusterUnmanagedTopics(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			UnmanagedTopicPatterns: []string{
+				"-changelog$",
+				"^connect-offsets$",
+				"^mm2-",
+			},
+		},
+	}
+
+	assert.True(t, clusterConfig.IsUnmanagedTopic("my-app-store-changelog"))
+	assert.True(t, clusterConfig.IsUnmanagedTopic("connect-offsets"))
+	assert.True(t, clusterConfig.IsUnmanagedTopic("mm2-offset-syncs.source.internal"))
+	assert.False(t, clusterConfig.IsUnmanagedTopic("connect-offsets-v2"))
+	assert.False(t, clusterConfig.IsUnmanagedTopic("my-topic"))
+	assert.Equal(t, "^mm2-", clusterConfig.UnmanagedTopicPattern("mm2-configs.source.internal"))
+	assert.Equal(t, "", clusterConfig.UnmanagedTopicPattern("my-topic"))
+}
+
+func TestClusterToYAML(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name:        "test-cluster",
+			Region:      "test-region",
+			Environment: "test-env",
+		},
+		Spec: ClusterSpec{
+			BootstrapAddrs: []string{"broker1:9092"},
+			TLS: TLSConfig{
+				Enabled:    true,
+				CACertPath: "certs/ca.pem",
+				CertPath:   "/etc/certs/client.pem",
+			},
+			Quotas: []QuotaConfig{
+				QuotaConfigFromQuotaInfo(
+					admin.QuotaInfo{
+						Entity: admin.QuotaEntity{User: "user1"},
+						Values: map[string]float64{
+							admin.QuotaKeyProducerByteRate: 1024,
+						},
+					},
+				),
+			},
+		},
+		RootDir: "/configs/test-cluster",
+	}
+
+	yamlStr, err := clusterConfig.ToYAML()
+	require.NoError(t, err)
+
+	loadedConfig, err := LoadClusterBytes([]byte(yamlStr))
+	require.NoError(t, err)
+
+	expectedConfig := clusterConfig
+	expectedConfig.RootDir = ""
+	expectedConfig.Spec.TLS.CACertPath = "/configs/test-cluster/certs/ca.pem"
+	assert.Equal(t, expectedConfig, loadedConfig)
+
+	// The original config isn't modified
+	assert.Equal(t, "certs/ca.pem", clusterConfig.Spec.TLS.CACertPath)
+}
+
+func TestNotificationConfigNotifiesFor(t *testing.T) {
+	allEvents := NotificationConfig{URL: "https://webhooks.example.com"}
+	assert.Equal(t, NotificationFormatJSON, allEvents.GetFormat())
+	assert.True(t, allEvents.NotifiesFor(NotificationEventStart, false))
+	assert.True(t, allEvents.NotifiesFor(NotificationEventFailure, false))
+	assert.False(t, allEvents.NotifiesFor(NotificationEventStart, true))
+
+	failuresOnly := NotificationConfig{
+		URL:     "https://webhooks.example.com",
+		Format:  NotificationFormatSlack,
+		Events:  []NotificationEvent{NotificationEventFailure},
+		DryRuns: true,
+	}
+	assert.Equal(t, NotificationFormatSlack, failuresOnly.GetFormat())
+	assert.False(t, failuresOnly.NotifiesFor(NotificationEventStart, false))
+	assert.True(t, failuresOnly.NotifiesFor(NotificationEventFailure, false))
+	assert.True(t, failuresOnly.NotifiesFor(NotificationEventFailure, true))
+}
+
+func TestClusterEventsClusterConfig(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name: "other-cluster",
+		},
+		Spec: ClusterSpec{
+			BootstrapAddrs: []string{"broker-addr"},
+		},
+		RootDir: "testdata/test-cluster-json",
+	}
+
+	_, err := clusterConfig.EventsClusterConfig()
+	assert.Error(t, err)
+
+	clusterConfig.Spec.Events = &EventsConfig{Topic: "topicctl-events"}
+	eventsClusterConfig, err := clusterConfig.EventsClusterConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "other-cluster", eventsClusterConfig.Meta.Name)
+
+	clusterConfig.Spec.Events.ClusterConfig = "../test-cluster/cluster.yaml"
+	eventsClusterConfig, err = clusterConfig.EventsClusterConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "test-cluster", eventsClusterConfig.Meta.Name)
+}
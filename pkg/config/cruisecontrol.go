@@ -0,0 +1,203 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/topicctl/pkg/cruisecontrol"
+	log "github.com/sirupsen/logrus"
+)
+
+// CruiseControlConfig stores the settings for delegating the rebalances and broker drains of a
+// cluster to Cruise Control. If it's set, the rebalance and drain subcommands submit their
+// changes to Cruise Control instead of moving replicas themselves; topic configs and settings are
+// still applied by topicctl.
+type CruiseControlConfig struct {
+	// URL is the base URL of the Cruise Control REST API, including the path prefix, e.g.
+	// http://cruise-control.example.com:9090/kafkacruisecontrol.
+	URL string `json:"url"`
+
+	// Username and Password are the basic auth credentials for the API, if it requires them.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// PasswordFrom, if set, references the environment variable or command that the password is
+	// fetched from instead of storing it in the config. It can't be set along with Password.
+	PasswordFrom *SecretRef `json:"passwordFrom,omitempty"`
+
+	// CACertPath is the path to the CA certs for the API (optional).
+	CACertPath string `json:"caCertPath,omitempty"`
+
+	// SkipVerify indicates whether verification of the API certificate should be skipped.
+	SkipVerify bool `json:"skipVerify,omitempty"`
+
+	// Goals are the names of the goals that the proposals are optimized for; if unset, the
+	// default goals of the Cruise Control instance are used.
+	Goals []string `json:"goals,omitempty"`
+
+	// ExcludedTopics is a regexp of the topics whose replicas Cruise Control shouldn't move. Cruise
+	// Control matches it against whole topic names, so e.g. __.* is needed for a prefix.
+	ExcludedTopics string `json:"excludedTopics,omitempty"`
+
+	// PollInterval is the time between the checks on the status of submitted tasks, e.g. 30s. It
+	// defaults to 10s.
+	PollInterval string `json:"pollInterval,omitempty"`
+}
+
+// Validate evaluates whether the Cruise Control config is valid.
+func (c CruiseControlConfig) Validate() error {
+	var err error
+
+	parsed, urlErr := url.Parse(c.URL)
+	if c.URL == "" || urlErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		err = multierror.Append(err, fmt.Errorf("URL '%s' must be an http(s) URL", c.URL))
+	}
+	if c.PasswordFrom != nil {
+		if c.Password != "" {
+			err = multierror.Append(
+				err,
+				errors.New("Only one of password or passwordFrom can be set"),
+			)
+		}
+		if secretErr := c.PasswordFrom.Validate(); secretErr != nil {
+			err = multierror.Append(err, fmt.Errorf("Invalid passwordFrom: %+v", secretErr))
+		}
+	}
+	if c.PollInterval != "" {
+		interval, parseErr := time.ParseDuration(c.PollInterval)
+		if parseErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Error parsing poll interval: %+v", parseErr),
+			)
+		} else if interval <= 0 {
+			err = multierror.Append(err, errors.New("PollInterval must be positive"))
+		}
+	}
+
+	return err
+}
+
+// NewCruiseControlClient returns a new client for the Cruise Control instance of the cluster,
+// using the settings in the cruiseControl section of the cluster config.
+func (c ClusterConfig) NewCruiseControlClient(
+	ctx context.Context,
+) (*cruisecontrol.Client, error) {
+	if c.Spec.CruiseControl == nil {
+		return nil, fmt.Errorf(
+			"Cluster config for %s doesn't have a cruiseControl section",
+			c.Meta.Name,
+		)
+	}
+	ccConfig := c.Spec.CruiseControl
+
+	password := ccConfig.Password
+	if ccConfig.PasswordFrom != nil {
+		log.Debugf("Setting Cruise Control password from %s", ccConfig.PasswordFrom)
+		var err error
+		password, err = ccConfig.PasswordFrom.Resolve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Could not get Cruise Control password: %+v", err)
+		}
+	}
+
+	var pollInterval time.Duration
+	if ccConfig.PollInterval != "" {
+		var err error
+		pollInterval, err = time.ParseDuration(ccConfig.PollInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cruisecontrol.NewClient(
+		cruisecontrol.Config{
+			URL:          ccConfig.URL,
+			Username:     ccConfig.Username,
+			Password:     password,
+			CACertPath:   c.absPath(ccConfig.CACertPath),
+			SkipVerify:   ccConfig.SkipVerify,
+			PollInterval: pollInterval,
+		},
+	)
+}
+
+// CruiseControlRequest returns the parameters of the Cruise Control optimizations for the
+// cluster. The throttle is the argument one, in MB/sec, if it's positive, or the default
+// throttle of the cluster otherwise.
+func (c ClusterConfig) CruiseControlRequest(
+	dryRun bool,
+	throttleMBsOverride int,
+) cruisecontrol.OptimizationRequest {
+	request := cruisecontrol.OptimizationRequest{
+		DryRun: dryRun,
+	}
+	if c.Spec.CruiseControl != nil {
+		request.Goals = c.Spec.CruiseControl.Goals
+		request.ExcludedTopics = c.Spec.CruiseControl.ExcludedTopics
+	}
+
+	if throttleMBsOverride > 0 {
+		request.ReplicationThrottle = int64(throttleMBsOverride) * 1000000
+	} else if c.Spec.DefaultThrottleMB > 0 {
+		request.ReplicationThrottle = c.Spec.DefaultThrottleMB * 1000000
+	}
+
+	return request
+}
+
+// CruiseControlExcludedTopics returns the regexp of the topics whose replicas Cruise Control
+// shouldn't move. This matches the topics in the cluster's excludedTopics setting and, unless
+// allowProtected is set, the protected topics, i.e. the ones in the argument topic configs that
+// are protected and the ones that match the cluster's protected topic patterns.
+//
+// Cruise Control matches the regexp against whole topic names, whereas the protected topic
+// patterns match anywhere in a name, so each of the latter is wrapped to allow any characters
+// around it.
+func (c ClusterConfig) CruiseControlExcludedTopics(
+	topicConfigs []TopicConfig,
+	allowProtected bool,
+) string {
+	patterns := []string{}
+	if c.Spec.CruiseControl != nil && c.Spec.CruiseControl.ExcludedTopics != "" {
+		patterns = append(patterns, c.Spec.CruiseControl.ExcludedTopics)
+	}
+
+	if !allowProtected {
+		for _, pattern := range c.Spec.ProtectedTopicPatterns {
+			patterns = append(patterns, fmt.Sprintf(".*(?:%s).*", pattern))
+		}
+
+		protectedTopics := []string{}
+		for _, topicConfig := range topicConfigs {
+			if topicConfig.IsProtected(c) {
+				protectedTopics = append(protectedTopics, regexp.QuoteMeta(topicConfig.Meta.Name))
+			}
+		}
+		sort.Strings(protectedTopics)
+
+		if len(protectedTopics) > 0 {
+			patterns = append(
+				patterns,
+				fmt.Sprintf("^(?:%s)$", strings.Join(protectedTopics, "|")),
+			)
+		}
+	}
+
+	if len(patterns) == 1 {
+		return patterns[0]
+	}
+
+	groups := []string{}
+	for _, pattern := range patterns {
+		groups = append(groups, fmt.Sprintf("(?:%s)", pattern))
+	}
+	return strings.Join(groups, "|")
+}
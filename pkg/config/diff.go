@@ -0,0 +1,327 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigDiffStatus describes how a config differs between two config trees.
+type ConfigDiffStatus string
+
+const (
+	// ConfigDiffStatusAdded is used for configs that are only in the second tree.
+	ConfigDiffStatusAdded ConfigDiffStatus = "added"
+
+	// ConfigDiffStatusRemoved is used for configs that are only in the first tree.
+	ConfigDiffStatusRemoved ConfigDiffStatus = "removed"
+
+	// ConfigDiffStatusChanged is used for configs that are in both trees but have different
+	// field values.
+	ConfigDiffStatusChanged ConfigDiffStatus = "changed"
+)
+
+// ConfigDiff describes the differences in a single cluster or topic config between two config
+// trees.
+type ConfigDiff struct {
+	// Key identifies the config, e.g. "cluster my-cluster" or "topic my-cluster/my-topic".
+	Key        string
+	Status     ConfigDiffStatus
+	FieldDiffs []FieldDiff
+}
+
+// FieldDiff is a difference in a single (flattened) config field. Values are JSON-formatted;
+// an empty value means that the field isn't set.
+type FieldDiff struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// ConfigTree contains all of the cluster and topic configs found in a directory.
+type ConfigTree struct {
+	// Clusters is keyed by cluster name.
+	Clusters map[string]ClusterConfig
+
+	// Topics is keyed by cluster name and topic name, separated by a slash.
+	Topics map[string]TopicConfig
+}
+
+// LoadConfigTree walks the argument directory and loads all of the cluster and topic configs
+// in it. The same layout that apply expects is assumed: cluster configs are named cluster.yaml
+// (or cluster.json, etc.), and topic configs are the config files in the subdirectories of the
+// cluster config directories. Other files, including ones without a top-level meta key (e.g.,
+// include fragments), are ignored.
+//
+// Settings profiles are resolved and defaults are set on the topic configs so that only
+// semantic differences are reported when comparing trees.
+func LoadConfigTree(dir string) (ConfigTree, error) {
+	tree := ConfigTree{
+		Clusters: map[string]ClusterConfig{},
+		Topics:   map[string]TopicConfig{},
+	}
+
+	clusterPaths := []string{}
+	topicPaths := []string{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		if !IsConfigPath(path) {
+			return nil
+		}
+
+		if IsClusterConfigPath(path) {
+			clusterPaths = append(clusterPaths, path)
+		} else if _, err := os.Stat(clusterPathForTopic(path)); err == nil {
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if hasMetaKey(contents, ConfigFormatForPath(path)) {
+				topicPaths = append(topicPaths, path)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return tree, err
+	}
+
+	clustersByPath := map[string]ClusterConfig{}
+
+	for _, clusterPath := range clusterPaths {
+		clusterConfig, err := LoadClusterFile(clusterPath, false)
+		if err != nil {
+			return tree, fmt.Errorf("Error loading cluster config %s: %+v", clusterPath, err)
+		}
+		clustersByPath[clusterPath] = clusterConfig
+
+		if _, ok := tree.Clusters[clusterConfig.Meta.Name]; ok {
+			return tree, fmt.Errorf(
+				"Cluster %s is configured more than once in %s",
+				clusterConfig.Meta.Name,
+				dir,
+			)
+		}
+		tree.Clusters[clusterConfig.Meta.Name] = clusterConfig
+	}
+
+	for _, topicPath := range topicPaths {
+		topicConfigs, err := LoadTopicsFile(topicPath)
+		if err != nil {
+			return tree, fmt.Errorf("Error loading topic config %s: %+v", topicPath, err)
+		}
+		clusterConfig := clustersByPath[clusterPathForTopic(topicPath)]
+
+		for _, topicConfig := range topicConfigs {
+			if err := topicConfig.ResolveSettingsProfile(clusterConfig); err != nil {
+				return tree, err
+			}
+			if err := topicConfig.ResolveACLs(clusterConfig); err != nil {
+				return tree, err
+			}
+			topicConfig.SetDefaults()
+
+			key := fmt.Sprintf("%s/%s", topicConfig.Meta.Cluster, topicConfig.Meta.Name)
+			if _, ok := tree.Topics[key]; ok {
+				return tree, fmt.Errorf("Topic %s is configured more than once in %s", key, dir)
+			}
+			tree.Topics[key] = topicConfig
+		}
+	}
+
+	return tree, nil
+}
+
+// DiffConfigTrees returns the differences between the configs in two trees, sorted by key.
+func DiffConfigTrees(oldTree ConfigTree, newTree ConfigTree) ([]ConfigDiff, error) {
+	oldConfigs := map[string]interface{}{}
+	newConfigs := map[string]interface{}{}
+
+	for name, clusterConfig := range oldTree.Clusters {
+		oldConfigs[fmt.Sprintf("cluster %s", name)] = clusterConfig
+	}
+	for name, clusterConfig := range newTree.Clusters {
+		newConfigs[fmt.Sprintf("cluster %s", name)] = clusterConfig
+	}
+	for key, topicConfig := range oldTree.Topics {
+		oldConfigs[fmt.Sprintf("topic %s", key)] = topicConfig
+	}
+	for key, topicConfig := range newTree.Topics {
+		newConfigs[fmt.Sprintf("topic %s", key)] = topicConfig
+	}
+
+	allKeys := map[string]struct{}{}
+	for key := range oldConfigs {
+		allKeys[key] = struct{}{}
+	}
+	for key := range newConfigs {
+		allKeys[key] = struct{}{}
+	}
+
+	sortedKeys := []string{}
+	for key := range allKeys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	diffs := []ConfigDiff{}
+
+	for _, key := range sortedKeys {
+		oldConfig, inOld := oldConfigs[key]
+		newConfig, inNew := newConfigs[key]
+
+		var oldFields, newFields map[string]string
+		var err error
+
+		if inOld {
+			oldFields, err = flattenConfig(oldConfig)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if inNew {
+			newFields, err = flattenConfig(newConfig)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		fieldDiffs := diffFields(oldFields, newFields)
+
+		switch {
+		case !inOld:
+			diffs = append(
+				diffs,
+				ConfigDiff{Key: key, Status: ConfigDiffStatusAdded, FieldDiffs: fieldDiffs},
+			)
+		case !inNew:
+			diffs = append(
+				diffs,
+				ConfigDiff{Key: key, Status: ConfigDiffStatusRemoved, FieldDiffs: fieldDiffs},
+			)
+		case len(fieldDiffs) > 0:
+			diffs = append(
+				diffs,
+				ConfigDiff{Key: key, Status: ConfigDiffStatusChanged, FieldDiffs: fieldDiffs},
+			)
+		}
+	}
+
+	return diffs, nil
+}
+
+func diffFields(oldFields map[string]string, newFields map[string]string) []FieldDiff {
+	allFields := map[string]struct{}{}
+	for field := range oldFields {
+		allFields[field] = struct{}{}
+	}
+	for field := range newFields {
+		allFields[field] = struct{}{}
+	}
+
+	sortedFields := []string{}
+	for field := range allFields {
+		sortedFields = append(sortedFields, field)
+	}
+	sort.Strings(sortedFields)
+
+	fieldDiffs := []FieldDiff{}
+
+	for _, field := range sortedFields {
+		oldValue := oldFields[field]
+		newValue := newFields[field]
+		if oldValue == newValue {
+			continue
+		}
+
+		if strings.HasSuffix(field, ".password") {
+			// Don't leak secrets into the output
+			oldValue = redactValue(oldValue)
+			newValue = redactValue(newValue)
+		}
+
+		fieldDiffs = append(
+			fieldDiffs,
+			FieldDiff{
+				Field:    field,
+				OldValue: oldValue,
+				NewValue: newValue,
+			},
+		)
+	}
+
+	return fieldDiffs
+}
+
+// flattenConfig converts the argument config to a map from dotted field paths (e.g.,
+// "spec.placement.strategy") to JSON-formatted values. Lists are treated as single values.
+// The apiVersion field is dropped since configs are migrated at load time.
+func flattenConfig(config interface{}) (map[string]string, error) {
+	jsonBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := map[string]interface{}{}
+	if err := decodeJSONUseNumber(jsonBytes, &obj); err != nil {
+		return nil, err
+	}
+	delete(obj, apiVersionKey)
+
+	fields := map[string]string{}
+	if err := flattenValue("", obj, fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func flattenValue(prefix string, value interface{}, fields map[string]string) error {
+	if valueMap, ok := value.(map[string]interface{}); ok {
+		for key, subValue := range valueMap {
+			subPrefix := key
+			if prefix != "" {
+				subPrefix = strings.Join([]string{prefix, key}, ".")
+			}
+			if err := flattenValue(subPrefix, subValue, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if value == nil || reflect.DeepEqual(value, []interface{}{}) {
+		// Treat nulls and empty lists the same as unset values
+		return nil
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	fields[prefix] = string(valueBytes)
+	return nil
+}
+
+func redactValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "<redacted>"
+}
+
+func clusterPathForTopic(topicPath string) string {
+	return ClusterConfigPathForDir(filepath.Join(filepath.Dir(topicPath), ".."))
+}
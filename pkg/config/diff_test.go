@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfigTrees(t *testing.T) {
+	oldTree, err := LoadConfigTree("testdata/diff/old")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(oldTree.Clusters))
+	assert.Equal(t, 2, len(oldTree.Topics))
+
+	newTree, err := LoadConfigTree("testdata/diff/new")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(newTree.Clusters))
+	assert.Equal(t, 2, len(newTree.Topics))
+
+	diffs, err := DiffConfigTrees(oldTree, newTree)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(diffs))
+
+	assert.Equal(
+		t,
+		ConfigDiff{
+			Key:    "cluster test-cluster",
+			Status: ConfigDiffStatusChanged,
+			FieldDiffs: []FieldDiff{
+				{
+					Field:    "spec.sasl.password",
+					OldValue: "<redacted>",
+					NewValue: "<redacted>",
+				},
+			},
+		},
+		diffs[0],
+	)
+	assert.Equal(t, "topic test-cluster/topic-added", diffs[1].Key)
+	assert.Equal(t, ConfigDiffStatusAdded, diffs[1].Status)
+
+	// The placement strategy change is just a migration, so it shouldn't show up
+	assert.Equal(
+		t,
+		ConfigDiff{
+			Key:    "topic test-cluster/topic-changed",
+			Status: ConfigDiffStatusChanged,
+			FieldDiffs: []FieldDiff{
+				{
+					Field:    "spec.partitions",
+					OldValue: "9",
+					NewValue: "12",
+				},
+				{
+					Field:    "spec.settings.cleanup.policy",
+					OldValue: `"delete"`,
+					NewValue: `"compact"`,
+				},
+			},
+		},
+		diffs[2],
+	)
+	assert.Equal(t, "topic test-cluster/topic-removed", diffs[3].Key)
+	assert.Equal(t, ConfigDiffStatusRemoved, diffs[3].Status)
+
+	noDiffs, err := DiffConfigTrees(oldTree, oldTree)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(noDiffs))
+}
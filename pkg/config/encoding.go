@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigFormat is the serialization format of a config file.
+type ConfigFormat string
+
+const (
+	// ConfigFormatYAML is used for files ending in .yaml or .yml, and for any files with
+	// unrecognized extensions.
+	ConfigFormatYAML ConfigFormat = "yaml"
+
+	// ConfigFormatJSON is used for files ending in .json.
+	ConfigFormatJSON ConfigFormat = "json"
+
+	// ConfigFormatTOML is used for files ending in .toml.
+	ConfigFormatTOML ConfigFormat = "toml"
+)
+
+// clusterConfigNames are the file names that are checked, in order, when looking for the
+// cluster config associated with a directory of topic configs.
+var clusterConfigNames = []string{
+	"cluster.yaml",
+	"cluster.yml",
+	"cluster.json",
+	"cluster.toml",
+}
+
+var (
+	yamlMetaKeyRegexp = regexp.MustCompile(`(?m)^meta:`)
+	jsonMetaKeyRegexp = regexp.MustCompile(`"meta"\s*:`)
+	tomlMetaKeyRegexp = regexp.MustCompile(`(?m)^\s*(\[meta\]|meta\.)`)
+)
+
+// ConfigFormatForPath returns the config format for the argument path based on its extension.
+func ConfigFormatForPath(path string) ConfigFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ConfigFormatJSON
+	case ".toml":
+		return ConfigFormatTOML
+	default:
+		return ConfigFormatYAML
+	}
+}
+
+// IsConfigPath returns whether the argument path has one of the config file extensions that
+// this tool can load.
+func IsConfigPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json", ".toml":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsClusterConfigPath returns whether the argument path is named like a cluster config.
+func IsClusterConfigPath(path string) bool {
+	base := filepath.Base(path)
+	for _, name := range clusterConfigNames {
+		if base == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClusterConfigPathForDir returns the path of the cluster config in the argument directory.
+// If none of the supported cluster config names exist, then the path for cluster.yaml is
+// returned so that callers get a reasonable error when trying to load it.
+func ClusterConfigPathForDir(dir string) string {
+	for _, name := range clusterConfigNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return filepath.Join(dir, clusterConfigNames[0])
+}
+
+// toYAMLCompatible converts the argument config contents into something that can be parsed by
+// the YAML loading pipeline. JSON is a subset of YAML, so it's passed through as-is; TOML is
+// converted to JSON.
+func toYAMLCompatible(contents []byte, format ConfigFormat) ([]byte, error) {
+	if format != ConfigFormatTOML {
+		return contents, nil
+	}
+
+	obj := map[string]interface{}{}
+	if _, err := toml.Decode(string(contents), &obj); err != nil {
+		return nil, fmt.Errorf("Error parsing TOML: %+v", err)
+	}
+	return json.Marshal(obj)
+}
+
+// splitConfigDocs splits the argument file contents into separate config documents, each in
+// a format that can be parsed by the YAML loading pipeline. YAML files can contain multiple
+// documents separated by "---", and JSON files can contain either a single object or a list
+// of objects. TOML files always contain a single document.
+func splitConfigDocs(contents []byte, format ConfigFormat) ([][]byte, error) {
+	switch format {
+	case ConfigFormatJSON:
+		trimmed := bytes.TrimSpace(contents)
+		if len(trimmed) == 0 {
+			return nil, nil
+		} else if trimmed[0] != '[' {
+			return [][]byte{trimmed}, nil
+		}
+
+		rawDocs := []json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &rawDocs); err != nil {
+			return nil, err
+		}
+
+		docs := [][]byte{}
+		for _, rawDoc := range rawDocs {
+			docs = append(docs, rawDoc)
+		}
+		return docs, nil
+	case ConfigFormatTOML:
+		doc, err := toYAMLCompatible(contents, format)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{doc}, nil
+	default:
+		trimmedFile := strings.TrimSpace(string(contents))
+		docStrs := sep.Split(trimmedFile, -1)
+
+		docs := [][]byte{}
+		for _, docStr := range docStrs {
+			docStr = strings.TrimSpace(docStr)
+			if isEmpty(docStr) {
+				continue
+			}
+			docs = append(docs, []byte(docStr))
+		}
+		return docs, nil
+	}
+}
+
+// hasMetaKey returns whether the argument config contents appear to have a top-level meta
+// key. It's used to distinguish full configs from include fragments.
+func hasMetaKey(contents []byte, format ConfigFormat) bool {
+	switch format {
+	case ConfigFormatJSON:
+		return jsonMetaKeyRegexp.Match(contents)
+	case ConfigFormatTOML:
+		return tomlMetaKeyRegexp.Match(contents)
+	default:
+		return yamlMetaKeyRegexp.Match(contents)
+	}
+}
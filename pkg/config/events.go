@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// EventsConfig stores the details of the Kafka topic that check results and apply outcomes for
+// the topics in a cluster are published to.
+type EventsConfig struct {
+	// Topic is the name of the topic that events are published to. It must already exist.
+	Topic string `json:"topic"`
+
+	// ClusterConfig is the path of the config for the cluster that the topic is in, relative to
+	// the directory of this cluster config. This is useful for sending the events from all
+	// clusters to a single ops cluster. If unset, the events are published to this cluster.
+	ClusterConfig string `json:"clusterConfig,omitempty"`
+
+	// DryRuns is set if the outcomes of apply dry-runs should also be published.
+	DryRuns bool `json:"dryRuns,omitempty"`
+}
+
+// Validate evaluates whether the events config is valid.
+func (e EventsConfig) Validate() error {
+	var err error
+
+	if e.Topic == "" {
+		err = multierror.Append(err, errors.New("Topic must be set"))
+	}
+
+	return err
+}
+
+// EventsClusterConfig returns the config of the cluster that events for the current cluster
+// are published to. If the events config doesn't reference another cluster config, the current
+// one is returned.
+func (c ClusterConfig) EventsClusterConfig() (ClusterConfig, error) {
+	if c.Spec.Events == nil {
+		return ClusterConfig{}, errors.New("Events are not configured for this cluster")
+	}
+	if c.Spec.Events.ClusterConfig == "" {
+		return c, nil
+	}
+
+	path := c.absPath(c.Spec.Events.ClusterConfig)
+	eventsClusterConfig, err := LoadClusterFile(path, true)
+	if err != nil {
+		return ClusterConfig{}, err
+	}
+	if len(eventsClusterConfig.Spec.BootstrapAddrs) == 0 {
+		return ClusterConfig{}, fmt.Errorf("Events cluster config %s has no bootstrap addresses", path)
+	}
+	return eventsClusterConfig, nil
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// FormatDeprecations generates a pretty table from the deprecated topics in the argument configs.
+// Topics that are past their removal dates (relative to now) are highlighted.
+func FormatDeprecations(topicConfigs []TopicConfig, now time.Time) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Cluster",
+			"Environment",
+			"Reason",
+			"Remove After",
+			"Status",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, topicConfig := range topicConfigs {
+		deprecation := topicConfig.Meta.Deprecated
+		if deprecation == nil {
+			continue
+		}
+
+		var statusPrinter func(f string, a ...interface{}) string
+		var status string
+
+		if deprecation.PastRemoveAfter(now) {
+			status = "past removal date"
+			if util.ColorEnabled() {
+				statusPrinter = color.New(color.FgRed).SprintfFunc()
+			} else {
+				statusPrinter = fmt.Sprintf
+			}
+		} else {
+			status = "deprecated"
+			statusPrinter = fmt.Sprintf
+		}
+
+		table.Append(
+			[]string{
+				topicConfig.Meta.Name,
+				topicConfig.Meta.Cluster,
+				topicConfig.Meta.Environment,
+				deprecation.Reason,
+				deprecation.RemoveAfter,
+				statusPrinter("%s", status),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatConfigDiffs generates a pretty table from the results of a call to DiffConfigTrees.
+func FormatConfigDiffs(diffs []ConfigDiff) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Config",
+			"Change",
+			"Field",
+			"Old Value",
+			"New Value",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, diff := range diffs {
+		var changePrinter func(f string, a ...interface{}) string
+		if !util.ColorEnabled() {
+			changePrinter = fmt.Sprintf
+		} else {
+			switch diff.Status {
+			case ConfigDiffStatusAdded:
+				changePrinter = color.New(color.FgGreen).SprintfFunc()
+			case ConfigDiffStatusRemoved:
+				changePrinter = color.New(color.FgRed).SprintfFunc()
+			default:
+				changePrinter = color.New(color.FgYellow).SprintfFunc()
+			}
+		}
+
+		if diff.Status != ConfigDiffStatusChanged {
+			// Don't bother showing all of the fields for added or removed configs
+			table.Append(
+				[]string{
+					diff.Key,
+					changePrinter("%s", string(diff.Status)),
+					"",
+					"",
+					"",
+				},
+			)
+			continue
+		}
+
+		for f, fieldDiff := range diff.FieldDiffs {
+			var key, status string
+			if f == 0 {
+				key = diff.Key
+				status = changePrinter("%s", string(diff.Status))
+			}
+
+			oldValue, newValue := util.HighlightWordDiff(fieldDiff.OldValue, fieldDiff.NewValue)
+
+			table.Append(
+				[]string{
+					key,
+					status,
+					fieldDiff.Field,
+					oldValue,
+					newValue,
+				},
+			)
+		}
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
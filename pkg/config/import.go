@@ -0,0 +1,477 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// ImportFormat is a string type that represents the format of topic definitions managed by
+// another tool.
+type ImportFormat string
+
+const (
+	// ImportFormatStrimzi is the format of Strimzi KafkaTopic custom resources.
+	ImportFormatStrimzi ImportFormat = "strimzi"
+
+	// ImportFormatJulieOps is the format of julie-ops topology descriptors.
+	ImportFormatJulieOps ImportFormat = "julie-ops"
+
+	// ImportFormatKafkaGitops is the format of kafka-gitops state files.
+	ImportFormatKafkaGitops ImportFormat = "kafka-gitops"
+
+	// ImportFormatKafkaTopics is the output of kafka-topics.sh --describe.
+	ImportFormatKafkaTopics ImportFormat = "kafka-topics"
+)
+
+// AllImportFormats contains all of the supported import formats.
+var AllImportFormats = []ImportFormat{
+	ImportFormatStrimzi,
+	ImportFormatJulieOps,
+	ImportFormatKafkaGitops,
+	ImportFormatKafkaTopics,
+}
+
+const (
+	// Config keys that julie-ops uses for the topic partition count and replication factor.
+	julieOpsPartitionsKey  = "num.partitions"
+	julieOpsReplicationKey = "replication.factor"
+)
+
+// kafkaTopicsFieldRegexp matches the field names in the output of kafka-topics.sh --describe.
+// Older versions of the tool don't put a space after the colon.
+var kafkaTopicsFieldRegexp = regexp.MustCompile(
+	`(?:^|\s)(Topic|TopicId|PartitionCount|ReplicationFactor|Configs|Partition|Leader|Replicas|Isr|Offline|Elr|LastKnownElr):`,
+)
+
+// ImportOptions contains the options for converting topics from other formats.
+type ImportOptions struct {
+	// DefaultPartitions is the partition count used for topics that don't set one in the
+	// source.
+	DefaultPartitions int
+
+	// DefaultReplicationFactor is the replication factor used for topics that don't set one
+	// in the source.
+	DefaultReplicationFactor int
+}
+
+// importedTopic is the intermediate representation of a topic parsed from another format.
+type importedTopic struct {
+	name              string
+	partitions        int
+	replicationFactor int
+	configMap         map[string]string
+}
+
+// ParseImportFormat converts the argument string to an ImportFormat, returning an error if it
+// isn't recognized.
+func ParseImportFormat(value string) (ImportFormat, error) {
+	for _, format := range AllImportFormats {
+		if string(format) == value {
+			return format, nil
+		}
+	}
+
+	formatStrs := []string{}
+	for _, format := range AllImportFormats {
+		formatStrs = append(formatStrs, string(format))
+	}
+
+	return "", fmt.Errorf(
+		"Unrecognized import format %s; must be one of: %s",
+		value,
+		strings.Join(formatStrs, ", "),
+	)
+}
+
+// ImportTopicConfigs converts the topics defined in the argument contents, which are in the
+// argument format, to topicctl topic configs. The metadata for the resulting configs is taken
+// from the argument cluster config, and all topics are given the "any" placement strategy.
+func ImportTopicConfigs(
+	contents []byte,
+	format ImportFormat,
+	clusterConfig ClusterConfig,
+	options ImportOptions,
+) ([]TopicConfig, error) {
+	var importedTopics []importedTopic
+	var err error
+
+	switch format {
+	case ImportFormatStrimzi:
+		importedTopics, err = parseStrimziTopics(contents)
+	case ImportFormatJulieOps:
+		importedTopics, err = parseJulieOpsTopics(contents)
+	case ImportFormatKafkaGitops:
+		importedTopics, err = parseKafkaGitopsTopics(contents)
+	case ImportFormatKafkaTopics:
+		importedTopics, err = parseKafkaTopicsDescribe(contents)
+	default:
+		_, err = ParseImportFormat(string(format))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	topicConfigs := []TopicConfig{}
+
+	for _, topic := range importedTopics {
+		if topic.name == "" {
+			return nil, fmt.Errorf("Found %s topic without a name", format)
+		}
+		if topic.partitions <= 0 {
+			topic.partitions = options.DefaultPartitions
+		}
+		if topic.replicationFactor <= 0 {
+			topic.replicationFactor = options.DefaultReplicationFactor
+		}
+		if topic.partitions <= 0 {
+			return nil, fmt.Errorf(
+				"Topic %s does not set a partition count and no default was provided",
+				topic.name,
+			)
+		}
+		if topic.replicationFactor <= 0 {
+			return nil, fmt.Errorf(
+				"Topic %s does not set a replication factor and no default was provided",
+				topic.name,
+			)
+		}
+
+		topicConfigs = append(
+			topicConfigs,
+			newTopicConfig(
+				clusterConfig,
+				topic.name,
+				topic.partitions,
+				topic.replicationFactor,
+				topic.configMap,
+				fmt.Sprintf("Imported via topicctl import from %s", format),
+			),
+		)
+	}
+
+	return topicConfigs, nil
+}
+
+type strimziResource struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		TopicName  string                 `json:"topicName"`
+		Partitions int                    `json:"partitions"`
+		Replicas   int                    `json:"replicas"`
+		Config     map[string]interface{} `json:"config"`
+	} `json:"spec"`
+
+	// Items is set for the List resources generated by kubectl get.
+	Items []strimziResource `json:"items"`
+}
+
+func parseStrimziTopics(contents []byte) ([]importedTopic, error) {
+	importedTopics := []importedTopic{}
+
+	for _, doc := range sep.Split(string(contents), -1) {
+		if isEmpty(doc) {
+			continue
+		}
+
+		resource := strimziResource{}
+		if err := yaml.Unmarshal([]byte(doc), &resource); err != nil {
+			return nil, err
+		}
+
+		docTopics, err := strimziResourceTopics(resource)
+		if err != nil {
+			return nil, err
+		}
+		importedTopics = append(importedTopics, docTopics...)
+	}
+
+	return importedTopics, nil
+}
+
+func strimziResourceTopics(resource strimziResource) ([]importedTopic, error) {
+	switch resource.Kind {
+	case "KafkaTopic":
+		// The topic name defaults to the resource name if it's not set explicitly
+		name := resource.Spec.TopicName
+		if name == "" {
+			name = resource.Metadata.Name
+		}
+
+		configMap, err := configValuesToStrings(resource.Spec.Config)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing config for topic %s: %+v", name, err)
+		}
+
+		return []importedTopic{
+			{
+				name:              name,
+				partitions:        resource.Spec.Partitions,
+				replicationFactor: resource.Spec.Replicas,
+				configMap:         configMap,
+			},
+		}, nil
+	case "List":
+		importedTopics := []importedTopic{}
+
+		for _, item := range resource.Items {
+			itemTopics, err := strimziResourceTopics(item)
+			if err != nil {
+				return nil, err
+			}
+			importedTopics = append(importedTopics, itemTopics...)
+		}
+
+		return importedTopics, nil
+	default:
+		return nil, fmt.Errorf(
+			"Unsupported resource kind '%s' in Strimzi input; expected KafkaTopic",
+			resource.Kind,
+		)
+	}
+}
+
+type julieOpsTopology struct {
+	Context  string `json:"context"`
+	Company  string `json:"company"`
+	Env      string `json:"env"`
+	Source   string `json:"source"`
+	Projects []struct {
+		Name   string `json:"name"`
+		Topics []struct {
+			Name     string                 `json:"name"`
+			DataType string                 `json:"dataType"`
+			Config   map[string]interface{} `json:"config"`
+		} `json:"topics"`
+	} `json:"projects"`
+}
+
+func parseJulieOpsTopics(contents []byte) ([]importedTopic, error) {
+	topology := julieOpsTopology{}
+	if err := yaml.Unmarshal(contents, &topology); err != nil {
+		return nil, err
+	}
+
+	importedTopics := []importedTopic{}
+
+	for _, project := range topology.Projects {
+		for _, topic := range project.Topics {
+			// Topic names are built by joining the topology prefixes, the project name,
+			// the topic name, and the data type (if set) with dots.
+			nameElements := []string{}
+			for _, element := range []string{
+				topology.Context,
+				topology.Company,
+				topology.Env,
+				topology.Source,
+				project.Name,
+				topic.Name,
+				topic.DataType,
+			} {
+				if element != "" {
+					nameElements = append(nameElements, element)
+				}
+			}
+			name := strings.Join(nameElements, ".")
+
+			configMap, err := configValuesToStrings(topic.Config)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing config for topic %s: %+v", name, err)
+			}
+
+			partitions, err := popIntConfig(configMap, julieOpsPartitionsKey)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing config for topic %s: %+v", name, err)
+			}
+			replicationFactor, err := popIntConfig(configMap, julieOpsReplicationKey)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing config for topic %s: %+v", name, err)
+			}
+
+			importedTopics = append(
+				importedTopics,
+				importedTopic{
+					name:              name,
+					partitions:        partitions,
+					replicationFactor: replicationFactor,
+					configMap:         configMap,
+				},
+			)
+		}
+	}
+
+	return importedTopics, nil
+}
+
+type kafkaGitopsState struct {
+	Topics map[string]struct {
+		Partitions  int                    `json:"partitions"`
+		Replication int                    `json:"replication"`
+		Configs     map[string]interface{} `json:"configs"`
+	} `json:"topics"`
+}
+
+func parseKafkaGitopsTopics(contents []byte) ([]importedTopic, error) {
+	state := kafkaGitopsState{}
+	if err := yaml.Unmarshal(contents, &state); err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for name := range state.Topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	importedTopics := []importedTopic{}
+
+	for _, name := range names {
+		topic := state.Topics[name]
+
+		configMap, err := configValuesToStrings(topic.Configs)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing config for topic %s: %+v", name, err)
+		}
+
+		importedTopics = append(
+			importedTopics,
+			importedTopic{
+				name:              name,
+				partitions:        topic.Partitions,
+				replicationFactor: topic.Replication,
+				configMap:         configMap,
+			},
+		)
+	}
+
+	return importedTopics, nil
+}
+
+func parseKafkaTopicsDescribe(contents []byte) ([]importedTopic, error) {
+	importedTopics := []importedTopic{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+
+	for scanner.Scan() {
+		fields := kafkaTopicsFields(scanner.Text())
+
+		// Skip over the per-partition lines and anything else that isn't a topic summary
+		if _, ok := fields["PartitionCount"]; !ok {
+			continue
+		}
+
+		name := fields["Topic"]
+		if strings.HasPrefix(name, "__") {
+			// Never include underscore topics
+			continue
+		}
+
+		partitions, err := strconv.Atoi(fields["PartitionCount"])
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing partition count for topic %s: %+v", name, err)
+		}
+		replicationFactor, err := strconv.Atoi(fields["ReplicationFactor"])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Error parsing replication factor for topic %s: %+v",
+				name,
+				err,
+			)
+		}
+
+		importedTopics = append(
+			importedTopics,
+			importedTopic{
+				name:              name,
+				partitions:        partitions,
+				replicationFactor: replicationFactor,
+				configMap:         parseKafkaTopicsConfigs(fields["Configs"]),
+			},
+		)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return importedTopics, nil
+}
+
+// kafkaTopicsFields splits a line of kafka-topics.sh --describe output into a map from field
+// name to value.
+func kafkaTopicsFields(line string) map[string]string {
+	fields := map[string]string{}
+	matches := kafkaTopicsFieldRegexp.FindAllStringSubmatchIndex(line, -1)
+
+	for m, match := range matches {
+		valueEnd := len(line)
+		if m < len(matches)-1 {
+			valueEnd = matches[m+1][0]
+		}
+		fields[line[match[2]:match[3]]] = strings.TrimSpace(line[match[1]:valueEnd])
+	}
+
+	return fields
+}
+
+// parseKafkaTopicsConfigs parses the comma-separated key=value pairs in the Configs field of
+// kafka-topics.sh --describe output. Since values can also contain commas (e.g., for the
+// throttled replicas settings), elements without an equals sign are treated as part of the
+// previous value.
+func parseKafkaTopicsConfigs(configsStr string) map[string]string {
+	configMap := map[string]string{}
+	if configsStr == "" {
+		return configMap
+	}
+
+	var lastKey string
+
+	for _, element := range strings.Split(configsStr, ",") {
+		if index := strings.Index(element, "="); index > 0 {
+			lastKey = element[:index]
+			configMap[lastKey] = element[index+1:]
+		} else if lastKey != "" {
+			configMap[lastKey] = configMap[lastKey] + "," + element
+		}
+	}
+
+	return configMap
+}
+
+func configValuesToStrings(values map[string]interface{}) (map[string]string, error) {
+	configMap := map[string]string{}
+
+	for key, value := range values {
+		valueStr, err := interfaceToString(value)
+		if err != nil {
+			return nil, err
+		}
+		configMap[key] = valueStr
+	}
+
+	return configMap, nil
+}
+
+// popIntConfig removes the argument key from the config map and returns its value as an int.
+// Zero is returned if the key isn't set.
+func popIntConfig(configMap map[string]string, key string) (int, error) {
+	valueStr, ok := configMap[key]
+	if !ok {
+		return 0, nil
+	}
+	delete(configMap, key)
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse value of %s as an int: %+v", key, err)
+	}
+	return value, nil
+}
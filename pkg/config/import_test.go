@@ -0,0 +1,219 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportTopicConfigs(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name:        "test-cluster",
+			Region:      "test-region",
+			Environment: "test-env",
+		},
+	}
+	options := ImportOptions{
+		DefaultPartitions:        6,
+		DefaultReplicationFactor: 3,
+	}
+
+	type testCase struct {
+		description string
+		format      ImportFormat
+		contents    string
+		expTopics   []TopicConfig
+		expErr      bool
+	}
+
+	testTopic := func(
+		format ImportFormat,
+		name string,
+		partitions int,
+		replicationFactor int,
+		retentionMinutes int,
+		settings TopicSettings,
+	) TopicConfig {
+		return TopicConfig{
+			APIVersion: CurrentAPIVersion,
+			Meta: TopicMeta{
+				Name:        name,
+				Cluster:     "test-cluster",
+				Region:      "test-region",
+				Environment: "test-env",
+				Description: "Imported via topicctl import from " + string(format),
+			},
+			Spec: TopicSpec{
+				Partitions:        partitions,
+				ReplicationFactor: replicationFactor,
+				RetentionMinutes:  retentionMinutes,
+				Settings:          settings,
+				PlacementConfig: TopicPlacementConfig{
+					Strategy: PlacementStrategyAny,
+				},
+			},
+		}
+	}
+
+	testCases := []testCase{
+		{
+			description: "strimzi",
+			format:      ImportFormatStrimzi,
+			contents: `
+apiVersion: kafka.strimzi.io/v1beta2
+kind: KafkaTopic
+metadata:
+  name: topic-a
+spec:
+  partitions: 10
+  replicas: 2
+  config:
+    retention.ms: 7200000
+    cleanup.policy: delete
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: kafka.strimzi.io/v1beta2
+    kind: KafkaTopic
+    metadata:
+      name: topic-b-resource
+    spec:
+      topicName: topic.b
+`,
+			expTopics: []TopicConfig{
+				testTopic(
+					ImportFormatStrimzi,
+					"topic-a",
+					10,
+					2,
+					120,
+					TopicSettings{"cleanup.policy": "delete"},
+				),
+				testTopic(ImportFormatStrimzi, "topic.b", 6, 3, 0, TopicSettings{}),
+			},
+		},
+		{
+			description: "strimzi bad kind",
+			format:      ImportFormatStrimzi,
+			contents: `
+kind: Kafka
+metadata:
+  name: my-cluster
+`,
+			expErr: true,
+		},
+		{
+			description: "julie-ops",
+			format:      ImportFormatJulieOps,
+			contents: `
+context: ctx
+source: src
+projects:
+  - name: proj
+    topics:
+      - name: foo
+        config:
+          replication.factor: "2"
+          num.partitions: "4"
+          retention.ms: "100"
+      - name: bar
+        dataType: avro
+`,
+			expTopics: []TopicConfig{
+				testTopic(
+					ImportFormatJulieOps,
+					"ctx.src.proj.foo",
+					4,
+					2,
+					0,
+					TopicSettings{"retention.ms": "100"},
+				),
+				testTopic(ImportFormatJulieOps, "ctx.src.proj.bar.avro", 6, 3, 0, TopicSettings{}),
+			},
+		},
+		{
+			description: "kafka-gitops",
+			format:      ImportFormatKafkaGitops,
+			contents: `
+topics:
+  topic-z:
+    partitions: 3
+    replication: 1
+  topic-a:
+    partitions: 12
+    replication: 3
+    configs:
+      cleanup.policy: compact
+`,
+			expTopics: []TopicConfig{
+				testTopic(
+					ImportFormatKafkaGitops,
+					"topic-a",
+					12,
+					3,
+					0,
+					TopicSettings{"cleanup.policy": "compact"},
+				),
+				testTopic(ImportFormatKafkaGitops, "topic-z", 3, 1, 0, TopicSettings{}),
+			},
+		},
+		{
+			description: "kafka-topics",
+			format:      ImportFormatKafkaTopics,
+			contents: "Topic: topic-a\tTopicId: 7d6OwzANQ-ud8YKFTYrk6A\tPartitionCount: 2\t" +
+				"ReplicationFactor: 2\tConfigs: cleanup.policy=compact," +
+				"leader.replication.throttled.replicas=0:1,1:2\n" +
+				"\tTopic: topic-a\tPartition: 0\tLeader: 1\tReplicas: 1,2\tIsr: 1,2\n" +
+				"\tTopic: topic-a\tPartition: 1\tLeader: 2\tReplicas: 2,3\tIsr: 2,3\n" +
+				"Topic:topic-b\tPartitionCount:1\tReplicationFactor:3\tConfigs:\n" +
+				"\tTopic: topic-b\tPartition: 0\tLeader: 1\tReplicas: 1,2,3\tIsr: 1,2,3\n" +
+				"Topic: __consumer_offsets\tPartitionCount: 50\tReplicationFactor: 3\tConfigs: \n",
+			expTopics: []TopicConfig{
+				testTopic(
+					ImportFormatKafkaTopics,
+					"topic-a",
+					2,
+					2,
+					0,
+					TopicSettings{
+						"cleanup.policy":                        "compact",
+						"leader.replication.throttled.replicas": "0:1,1:2",
+					},
+				),
+				testTopic(ImportFormatKafkaTopics, "topic-b", 1, 3, 0, TopicSettings{}),
+			},
+		},
+		{
+			description: "bad format",
+			format:      ImportFormat("not-a-format"),
+			contents:    "",
+			expErr:      true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		topicConfigs, err := ImportTopicConfigs(
+			[]byte(testCase.contents),
+			testCase.format,
+			clusterConfig,
+			options,
+		)
+		if testCase.expErr {
+			assert.Error(t, err, testCase.description)
+		} else {
+			require.NoError(t, err, testCase.description)
+			assert.Equal(t, testCase.expTopics, topicConfigs, testCase.description)
+		}
+	}
+
+	_, err := ImportTopicConfigs(
+		[]byte("topics:\n  topic-a: {}\n"),
+		ImportFormatKafkaGitops,
+		clusterConfig,
+		ImportOptions{},
+	)
+	assert.Error(t, err)
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// includeKey is the top-level key that can be used in topic and cluster configs to merge in
+// shared YAML fragments, e.g. a common settings or SASL block.
+const includeKey = "include"
+
+// includeContext tracks the state needed to resolve include directives for a single config.
+type includeContext struct {
+	// baseDir is the directory that relative include paths are evaluated against.
+	baseDir string
+
+	// expandEnv is whether fragments should be run through os.ExpandEnv after being read.
+	expandEnv bool
+
+	// stack is the chain of (absolute) file paths that led to the current config; it's used
+	// for cycle detection.
+	stack []string
+
+	// values are the template values that fragments are rendered with, if non-nil.
+	values TemplateValues
+}
+
+// resolveIncludes converts the argument YAML to JSON, merging in the contents of any fragments
+// referenced in the top-level include key. Fragments are merged in the order listed, and then
+// the values in the including config are merged on top. Maps are merged recursively; all other
+// values (including lists) are replaced.
+func resolveIncludes(contents []byte, ictx includeContext) ([]byte, error) {
+	jsonBytes, err := yaml.YAMLToJSON(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, ok, err := jsonToMap(jsonBytes, includeKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Not a map or no include key; let the strict decoder handle it as-is.
+		return jsonBytes, nil
+	}
+
+	includePaths, err := includePathsFromValue(obj[includeKey])
+	if err != nil {
+		return nil, err
+	}
+	delete(obj, includeKey)
+
+	merged := map[string]interface{}{}
+
+	for _, includePath := range includePaths {
+		fragment, err := loadFragment(includePath, ictx)
+		if err != nil {
+			return nil, err
+		}
+		mergeMaps(merged, fragment)
+	}
+
+	mergeMaps(merged, obj)
+	return json.Marshal(merged)
+}
+
+func loadFragment(
+	includePath string,
+	ictx includeContext,
+) (map[string]interface{}, error) {
+	if !filepath.IsAbs(includePath) {
+		includePath = filepath.Join(ictx.baseDir, includePath)
+	}
+	absPath, err := filepath.Abs(includePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stackPath := range ictx.stack {
+		if stackPath == absPath {
+			return nil, fmt.Errorf(
+				"Include cycle detected: %s",
+				strings.Join(append(ictx.stack, absPath), " -> "),
+			)
+		}
+	}
+
+	contents, err := ioutil.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading included file %s: %+v", includePath, err)
+	}
+	contents, err = renderTemplate(absPath, contents, ictx.values)
+	if err != nil {
+		return nil, err
+	}
+	if ictx.expandEnv {
+		contents = []byte(os.ExpandEnv(string(contents)))
+	}
+	contents, err = toYAMLCompatible(contents, ConfigFormatForPath(absPath))
+	if err != nil {
+		return nil, fmt.Errorf("Error reading included file %s: %+v", includePath, err)
+	}
+
+	stack := make([]string, len(ictx.stack), len(ictx.stack)+1)
+	copy(stack, ictx.stack)
+
+	jsonBytes, err := resolveIncludes(
+		contents,
+		includeContext{
+			baseDir:   filepath.Dir(absPath),
+			expandEnv: ictx.expandEnv,
+			stack:     append(stack, absPath),
+			values:    ictx.values,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fragment := map[string]interface{}{}
+	if err := decodeJSONUseNumber(jsonBytes, &fragment); err != nil {
+		return nil, fmt.Errorf("Included file %s is not a valid config fragment: %+v", includePath, err)
+	}
+
+	return fragment, nil
+}
+
+// jsonToMap decodes the argument JSON into a map, or returns nil if the JSON isn't an object.
+// The boolean return value is true if the object contains the argument key.
+func jsonToMap(jsonBytes []byte, key string) (map[string]interface{}, bool, error) {
+	trimmed := bytes.TrimSpace(jsonBytes)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, false, nil
+	}
+
+	obj := map[string]interface{}{}
+	if err := decodeJSONUseNumber(trimmed, &obj); err != nil {
+		return nil, false, err
+	}
+
+	_, ok := obj[key]
+	return obj, ok, nil
+}
+
+func includePathsFromValue(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		paths := []string{}
+		for _, element := range v {
+			path, ok := element.(string)
+			if !ok {
+				return nil, fmt.Errorf("Include paths must be strings, got %+v", element)
+			}
+			paths = append(paths, path)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("Include must be a path or list of paths, got %+v", value)
+	}
+}
+
+// mergeMaps recursively merges src into dst, with the values in src taking precedence.
+func mergeMaps(dst map[string]interface{}, src map[string]interface{}) {
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+		} else {
+			dst[key] = srcValue
+		}
+	}
+}
+
+// decodeJSONUseNumber decodes JSON while preserving the exact representation of numbers, so
+// that round-tripping large integer settings doesn't lose precision.
+func decodeJSONUseNumber(jsonBytes []byte, o interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(jsonBytes))
+	dec.UseNumber()
+	return dec.Decode(o)
+}
@@ -0,0 +1,343 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+const (
+	// ListenerPurposeAdmin is the purpose of the connections used for administrative operations,
+	// e.g. by apply, get, and check.
+	ListenerPurposeAdmin = "admin"
+
+	// ListenerPurposeTail is the purpose of the connections used for consuming messages via the
+	// tail subcommand.
+	ListenerPurposeTail = "tail"
+)
+
+var oauthExtensionKeyRegexp = regexp.MustCompile(`^[a-zA-Z]+$`)
+
+var allListenerPurposes = []string{
+	ListenerPurposeAdmin,
+	ListenerPurposeTail,
+}
+
+// ListenerConfig stores the connection settings for a single named broker listener. This allows
+// one cluster config to cover brokers that expose listeners with different ports or security
+// settings, e.g. an internal SCRAM listener for admin operations and a TLS one for consumers.
+type ListenerConfig struct {
+	// BootstrapAddrs are the bootstrap addresses of the listener. If unset, the bootstrap
+	// addresses of the cluster are used, with their ports replaced by Port if that's set.
+	BootstrapAddrs []string `json:"bootstrapAddrs,omitempty"`
+
+	// Port is the port of the listener on the cluster bootstrap addresses. It can't be set along
+	// with BootstrapAddrs.
+	Port int `json:"port,omitempty"`
+
+	// TLS stores the TLS settings for the listener. If unset, the cluster TLS settings are used.
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// SASL stores the SASL settings for the listener. If unset, the cluster SASL settings are
+	// used.
+	SASL *SASLConfig `json:"sasl,omitempty"`
+}
+
+// ListenerPurposes selects which of the named listeners in the cluster config is used for each
+// purpose. Purposes that don't select a listener use the default connection settings in the
+// cluster spec, except for tail, which falls back to the admin listener.
+type ListenerPurposes struct {
+	// Admin is the name of the listener used for administrative operations.
+	Admin string `json:"admin,omitempty"`
+
+	// Tail is the name of the listener used by the tail subcommand.
+	Tail string `json:"tail,omitempty"`
+}
+
+// listener returns the name of the listener selected for the argument purpose, or an empty
+// string if the default connection settings should be used.
+func (p ListenerPurposes) listener(purpose string) string {
+	switch purpose {
+	case ListenerPurposeTail:
+		if p.Tail != "" {
+			return p.Tail
+		}
+		return p.Admin
+	default:
+		return p.Admin
+	}
+}
+
+// ForPurpose returns a copy of the cluster config whose bootstrap addresses, TLS settings, and
+// SASL settings are the ones of the listener selected for the argument purpose. The listeners
+// in the result are cleared so that it can be used anywhere that a cluster config is expected,
+// e.g. with NewAdminClient.
+func (c ClusterConfig) ForPurpose(purpose string) (ClusterConfig, error) {
+	if !isValidListenerPurpose(purpose) {
+		return ClusterConfig{}, fmt.Errorf(
+			"Listener purpose '%s' must be in %+v",
+			purpose,
+			allListenerPurposes,
+		)
+	}
+
+	var listenerName string
+	if c.Spec.ListenerPurposes != nil {
+		listenerName = c.Spec.ListenerPurposes.listener(purpose)
+	}
+
+	resolved := c
+	resolved.Spec.Listeners = nil
+	resolved.Spec.ListenerPurposes = nil
+
+	if listenerName == "" {
+		return resolved, nil
+	}
+
+	listener, ok := c.Spec.Listeners[listenerName]
+	if !ok {
+		return ClusterConfig{}, fmt.Errorf(
+			"Listener '%s' selected for %s connections is not defined",
+			listenerName,
+			purpose,
+		)
+	}
+
+	bootstrapAddrs, err := listener.bootstrapAddrs(c.Spec.BootstrapAddrs)
+	if err != nil {
+		return ClusterConfig{}, fmt.Errorf("Invalid listener '%s': %+v", listenerName, err)
+	}
+	resolved.Spec.BootstrapAddrs = bootstrapAddrs
+
+	if listener.TLS != nil {
+		resolved.Spec.TLS = *listener.TLS
+	}
+	if listener.SASL != nil {
+		resolved.Spec.SASL = *listener.SASL
+	}
+
+	return resolved, nil
+}
+
+// Validate evaluates whether the listener config is valid.
+func (l ListenerConfig) Validate() error {
+	var err error
+
+	if len(l.BootstrapAddrs) > 0 && l.Port != 0 {
+		err = multierror.Append(err, errors.New("Only one of bootstrapAddrs or port can be set"))
+	}
+	if l.Port < 0 || l.Port > 65535 {
+		err = multierror.Append(err, fmt.Errorf("Port %d must be between 1 and 65535", l.Port))
+	}
+	if l.SASL != nil {
+		if saslErr := l.SASL.Validate(); saslErr != nil {
+			err = multierror.Append(err, saslErr)
+		}
+	}
+
+	return err
+}
+
+// validateListeners evaluates whether the listeners and listener purposes in the cluster config
+// are valid.
+func (c ClusterConfig) validateListeners() error {
+	var err error
+
+	names := []string{}
+	for name := range c.Spec.Listeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		listener := c.Spec.Listeners[name]
+
+		if name == "" {
+			err = multierror.Append(err, errors.New("Listener names must be non-empty"))
+		}
+		if listenerErr := listener.Validate(); listenerErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid listener '%s': %+v", name, listenerErr),
+			)
+		}
+		if len(c.Spec.ZKAddrs) > 0 && (listener.TLS != nil || listener.SASL != nil) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"TLS and SASL in listener '%s' not supported with zk access mode; omit zk addresses to fix",
+					name,
+				),
+			)
+		}
+	}
+
+	if c.Spec.ListenerPurposes != nil {
+		selections := map[string]string{
+			ListenerPurposeAdmin: c.Spec.ListenerPurposes.Admin,
+			ListenerPurposeTail:  c.Spec.ListenerPurposes.Tail,
+		}
+
+		for _, purpose := range allListenerPurposes {
+			name := selections[purpose]
+			if name == "" {
+				continue
+			}
+			if _, ok := c.Spec.Listeners[name]; !ok {
+				err = multierror.Append(
+					err,
+					fmt.Errorf(
+						"Listener '%s' selected for %s connections must be in %+v",
+						name,
+						purpose,
+						names,
+					),
+				)
+			}
+		}
+	}
+
+	return err
+}
+
+func (l ListenerConfig) bootstrapAddrs(clusterAddrs []string) ([]string, error) {
+	if len(l.BootstrapAddrs) > 0 {
+		return l.BootstrapAddrs, nil
+	}
+	if l.Port == 0 {
+		return clusterAddrs, nil
+	}
+
+	port := strconv.Itoa(l.Port)
+	addrs := []string{}
+
+	for _, addr := range clusterAddrs {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			// The address doesn't have a port
+			host = addr
+		}
+		addrs = append(addrs, net.JoinHostPort(host, port))
+	}
+
+	return addrs, nil
+}
+
+// Validate evaluates whether the SASL config is valid.
+func (s SASLConfig) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+
+	var err error
+
+	saslMechanism, saslErr := admin.SASLNameToMechanism(s.Mechanism)
+	if saslErr != nil {
+		err = multierror.Append(err, saslErr)
+	}
+	if s.PasswordFrom != nil {
+		if saslMechanism == admin.SASLMechanismAWSMSKIAM {
+			err = multierror.Append(
+				err,
+				errors.New("PasswordFrom can't be set if using SASL AWS-MSK-IAM"),
+			)
+		}
+		if s.Password != "" {
+			err = multierror.Append(
+				err,
+				errors.New("Only one of password or passwordFrom can be set"),
+			)
+		}
+		if secretErr := s.PasswordFrom.Validate(); secretErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid passwordFrom: %+v", secretErr),
+			)
+		}
+	}
+	if saslMechanism == admin.SASLMechanismGSSAPI {
+		if s.GSSAPI == nil || s.GSSAPI.Principal == "" {
+			err = multierror.Append(
+				err,
+				errors.New("GSSAPI principal must be set if using SASL GSSAPI"),
+			)
+		} else if s.GSSAPI.KeytabPath == "" && s.Password == "" && s.PasswordFrom == nil {
+			err = multierror.Append(
+				err,
+				errors.New("Keytab path, password, or passwordFrom must be set if using SASL GSSAPI"),
+			)
+		}
+	}
+
+	if saslMechanism == admin.SASLMechanismOAuthBearer {
+		if oauthErr := s.validateOAuth(); oauthErr != nil {
+			err = multierror.Append(err, oauthErr)
+		}
+	}
+
+	return err
+}
+
+func (s SASLConfig) validateOAuth() error {
+	if s.OAuth == nil {
+		return errors.New("OAuth settings must be set if using SASL OAUTHBEARER")
+	}
+
+	var err error
+
+	tokenURL, urlErr := url.Parse(s.OAuth.TokenURL)
+	if s.OAuth.TokenURL == "" || urlErr != nil ||
+		(tokenURL.Scheme != "https" && tokenURL.Scheme != "http") {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("OAuth token URL '%s' must be an http(s) URL", s.OAuth.TokenURL),
+		)
+	}
+	if s.Username == "" {
+		err = multierror.Append(
+			err,
+			errors.New("Username (the OAuth client ID) must be set if using SASL OAUTHBEARER"),
+		)
+	}
+	if s.Password == "" && s.PasswordFrom == nil {
+		err = multierror.Append(
+			err,
+			errors.New("Password or passwordFrom (the OAuth client secret) must be set if using SASL OAUTHBEARER"),
+		)
+	}
+
+	// See RFC 7628 section 3.1 for the extension format
+	for key, value := range s.OAuth.Extensions {
+		if !oauthExtensionKeyRegexp.MatchString(key) || key == "auth" {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("OAuth extension key '%s' must be alphabetic and not 'auth'", key),
+			)
+		}
+		if strings.Contains(value, "\x01") {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("OAuth extension value for '%s' has invalid characters", key),
+			)
+		}
+	}
+
+	return err
+}
+
+func isValidListenerPurpose(purpose string) bool {
+	for _, validPurpose := range allListenerPurposes {
+		if purpose == validPurpose {
+			return true
+		}
+	}
+	return false
+}
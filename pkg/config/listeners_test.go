@@ -0,0 +1,228 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterForPurpose(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name: "test-cluster",
+		},
+		Spec: ClusterSpec{
+			BootstrapAddrs: []string{"broker1:9092", "broker2"},
+			TLS: TLSConfig{
+				Enabled: true,
+			},
+			SASL: SASLConfig{
+				Enabled:   true,
+				Mechanism: "PLAIN",
+				Username:  "default-user",
+				Password:  "default-password",
+			},
+			Listeners: map[string]ListenerConfig{
+				"internal": {
+					Port: 9094,
+					SASL: &SASLConfig{
+						Enabled:   true,
+						Mechanism: "SCRAM-SHA-512",
+						Username:  "admin",
+						PasswordFrom: &SecretRef{
+							FromEnv: "ADMIN_PASSWORD",
+						},
+					},
+				},
+				"external": {
+					BootstrapAddrs: []string{"kafka.example.com:9093"},
+					TLS: &TLSConfig{
+						Enabled:    true,
+						ServerName: "kafka.example.com",
+					},
+				},
+			},
+			ListenerPurposes: &ListenerPurposes{
+				Admin: "internal",
+				Tail:  "external",
+			},
+		},
+	}
+
+	adminConfig, err := clusterConfig.ForPurpose(ListenerPurposeAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"broker1:9094", "broker2:9094"}, adminConfig.Spec.BootstrapAddrs)
+	assert.Equal(t, TLSConfig{Enabled: true}, adminConfig.Spec.TLS)
+	assert.Equal(t, "SCRAM-SHA-512", adminConfig.Spec.SASL.Mechanism)
+	assert.Equal(t, "admin", adminConfig.Spec.SASL.Username)
+	assert.Nil(t, adminConfig.Spec.Listeners)
+	assert.Nil(t, adminConfig.Spec.ListenerPurposes)
+
+	// The resolved config is unaffected by resolving it again
+	resolvedAgain, err := adminConfig.ForPurpose(ListenerPurposeAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, adminConfig, resolvedAgain)
+
+	tailConfig, err := clusterConfig.ForPurpose(ListenerPurposeTail)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kafka.example.com:9093"}, tailConfig.Spec.BootstrapAddrs)
+	assert.Equal(
+		t,
+		TLSConfig{Enabled: true, ServerName: "kafka.example.com"},
+		tailConfig.Spec.TLS,
+	)
+	assert.Equal(t, "PLAIN", tailConfig.Spec.SASL.Mechanism)
+
+	// Tail falls back to the admin listener
+	clusterConfig.Spec.ListenerPurposes = &ListenerPurposes{
+		Admin: "internal",
+	}
+	tailConfig, err = clusterConfig.ForPurpose(ListenerPurposeTail)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"broker1:9094", "broker2:9094"}, tailConfig.Spec.BootstrapAddrs)
+
+	// Without any selections, the default settings are used
+	clusterConfig.Spec.ListenerPurposes = nil
+	defaultConfig, err := clusterConfig.ForPurpose(ListenerPurposeAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"broker1:9092", "broker2"}, defaultConfig.Spec.BootstrapAddrs)
+	assert.Equal(t, "default-user", defaultConfig.Spec.SASL.Username)
+
+	clusterConfig.Spec.ListenerPurposes = &ListenerPurposes{
+		Admin: "non-existent",
+	}
+	_, err = clusterConfig.ForPurpose(ListenerPurposeAdmin)
+	assert.Error(t, err)
+
+	_, err = clusterConfig.ForPurpose("produce")
+	assert.Error(t, err)
+}
+
+func TestClusterConnectorConfigPasswordFrom(t *testing.T) {
+	t.Setenv("TOPICCTL_TEST_SASL_PASSWORD", "secret-password")
+
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			BootstrapAddrs: []string{"broker1:9092"},
+			SASL: SASLConfig{
+				Enabled:   true,
+				Mechanism: "SCRAM-SHA-256",
+				Username:  "alice",
+				PasswordFrom: &SecretRef{
+					FromEnv: "TOPICCTL_TEST_SASL_PASSWORD",
+				},
+			},
+		},
+	}
+
+	connectorConfig, err := clusterConfig.connectorConfig(context.Background(), "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-password", connectorConfig.SASL.Password)
+
+	connectorConfig, err = clusterConfig.connectorConfig(context.Background(), "", "override")
+	require.NoError(t, err)
+	assert.Equal(t, "override", connectorConfig.SASL.Password)
+
+	clusterConfig.Spec.SASL.PasswordFrom.FromEnv = "TOPICCTL_TEST_MISSING_PASSWORD"
+	_, err = clusterConfig.connectorConfig(context.Background(), "", "")
+	assert.Error(t, err)
+}
+
+func TestClusterCredentialFiles(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			BootstrapAddrs: []string{"broker1:9092"},
+			TLS: TLSConfig{
+				Enabled:    true,
+				CACertPath: "/certs/ca.crt",
+				CertPath:   "client.crt",
+			},
+			Listeners: map[string]ListenerConfig{
+				"plaintext": {
+					Port: 9093,
+					TLS:  &TLSConfig{},
+				},
+			},
+			ListenerPurposes: &ListenerPurposes{
+				Tail: "plaintext",
+			},
+		},
+		RootDir: "/configs",
+	}
+
+	credentialFiles, err := clusterConfig.CredentialFiles(ListenerPurposeAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/certs/ca.crt", "/configs/client.crt"}, credentialFiles)
+
+	credentialFiles, err = clusterConfig.CredentialFiles(ListenerPurposeTail)
+	require.NoError(t, err)
+	assert.Empty(t, credentialFiles)
+
+	clusterConfig.Spec.SASL = SASLConfig{
+		Enabled:   true,
+		Mechanism: "GSSAPI",
+		GSSAPI: &GSSAPIConfig{
+			Principal:  "topicctl@EXAMPLE.COM",
+			KeytabPath: "topicctl.keytab",
+		},
+	}
+	credentialFiles, err = clusterConfig.CredentialFiles(ListenerPurposeAdmin)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]string{
+			"/certs/ca.crt",
+			"/configs/client.crt",
+			"/configs/topicctl.keytab",
+			"/etc/krb5.conf",
+		},
+		credentialFiles,
+	)
+}
+
+func TestClusterZKTLSConfig(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			BootstrapAddrs: []string{"broker1:9092"},
+			ZKAddrs:        []string{"zk1:2182"},
+			ZKTLS: &TLSConfig{
+				Enabled:    true,
+				CACertPath: "certs/zk-ca.crt",
+				CertPath:   "/certs/zk-client.crt",
+				KeyPath:    "certs/zk-client.key",
+				ServerName: "zk.example.com",
+			},
+		},
+		RootDir: "/configs",
+	}
+
+	assert.Equal(
+		t,
+		admin.TLSConfig{
+			Enabled:    true,
+			CACertPath: "/configs/certs/zk-ca.crt",
+			CertPath:   "/certs/zk-client.crt",
+			KeyPath:    "/configs/certs/zk-client.key",
+			ServerName: "zk.example.com",
+		},
+		clusterConfig.zkTLSConfig(),
+	)
+
+	credentialFiles, err := clusterConfig.CredentialFiles(ListenerPurposeAdmin)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]string{
+			"/configs/certs/zk-ca.crt",
+			"/certs/zk-client.crt",
+			"/configs/certs/zk-client.key",
+		},
+		credentialFiles,
+	)
+
+	clusterConfig.Spec.ZKTLS = nil
+	assert.Equal(t, admin.TLSConfig{}, clusterConfig.zkTLSConfig())
+}
@@ -4,35 +4,66 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
-	"github.com/ghodss/yaml"
 	"github.com/hashicorp/go-multierror"
 )
 
 var sep = regexp.MustCompile("(?:^|\\s*\n)---\\s*")
 
-// LoadClusterFile loads a ClusterConfig from a path to a YAML file.
+// LoadClusterFile loads a ClusterConfig from a path to a YAML, JSON, or TOML file. The format
+// is determined by the file extension.
 func LoadClusterFile(path string, expandEnv bool) (ClusterConfig, error) {
+	return LoadClusterFileWithValues(path, expandEnv, nil)
+}
+
+// LoadClusterFileWithValues is the same as LoadClusterFile except that, if values is non-nil,
+// the file is first rendered as a template with the argument values.
+func LoadClusterFileWithValues(
+	path string,
+	expandEnv bool,
+	values TemplateValues,
+) (ClusterConfig, error) {
 	contents, err := ioutil.ReadFile(path)
 	if err != nil {
 		return ClusterConfig{}, err
 	}
 
+	contents, err = renderTemplate(path, contents, values)
+	if err != nil {
+		return ClusterConfig{}, err
+	}
+
 	if expandEnv {
 		contents = []byte(os.ExpandEnv(string(contents)))
 	}
 
+	contents, err = toYAMLCompatible(contents, ConfigFormatForPath(path))
+	if err != nil {
+		return ClusterConfig{}, err
+	}
+
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return ClusterConfig{}, err
 	}
 
-	config, err := LoadClusterBytes(contents)
+	config := ClusterConfig{}
+	err = unmarshalConfigStrict(
+		contents,
+		includeContext{
+			baseDir:   filepath.Dir(absPath),
+			expandEnv: expandEnv,
+			stack:     []string{absPath},
+			values:    values,
+		},
+		&config,
+	)
 	if err != nil {
 		return ClusterConfig{}, err
 	}
@@ -41,34 +72,57 @@ func LoadClusterFile(path string, expandEnv bool) (ClusterConfig, error) {
 	return config, nil
 }
 
-// LoadClusterBytes loads a ClusterConfig from YAML bytes.
+// LoadClusterBytes loads a ClusterConfig from YAML (or JSON) bytes. Any include paths are
+// evaluated relative to the current working directory.
 func LoadClusterBytes(contents []byte) (ClusterConfig, error) {
 	config := ClusterConfig{}
-	err := unmarshalYAMLStrict(contents, &config)
+	err := unmarshalConfigStrict(contents, includeContext{}, &config)
 	return config, err
 }
 
-// LoadTopicsFile loads one or more TopicConfigs from a path to a YAML file.
+// LoadTopicsFile loads one or more TopicConfigs from a path to a YAML, JSON, or TOML file. The
+// format is determined by the file extension. YAML files can contain multiple configs separated
+// by "---" and JSON files can contain a list of configs; TOML files contain a single config.
 func LoadTopicsFile(path string) ([]TopicConfig, error) {
+	return LoadTopicsFileWithValues(path, nil)
+}
+
+// LoadTopicsFileWithValues is the same as LoadTopicsFile except that, if values is non-nil,
+// the file is first rendered as a template with the argument values.
+func LoadTopicsFileWithValues(path string, values TemplateValues) ([]TopicConfig, error) {
 	contents, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
+	contents, err = renderTemplate(path, contents, values)
+	if err != nil {
+		return nil, err
+	}
+
 	contents = []byte(os.ExpandEnv(string(contents)))
 
-	trimmedFile := strings.TrimSpace(string(contents))
-	topicStrs := sep.Split(trimmedFile, -1)
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	ictx := includeContext{
+		baseDir:   filepath.Dir(absPath),
+		expandEnv: true,
+		stack:     []string{absPath},
+		values:    values,
+	}
 
-	topicConfigs := []TopicConfig{}
+	topicDocs, err := splitConfigDocs(contents, ConfigFormatForPath(path))
+	if err != nil {
+		return nil, err
+	}
 
-	for _, topicStr := range topicStrs {
-		topicStr = strings.TrimSpace(topicStr)
-		if isEmpty(topicStr) {
-			continue
-		}
+	topicConfigs := []TopicConfig{}
 
-		topicConfig, err := LoadTopicBytes([]byte(topicStr))
+	for _, topicDoc := range topicDocs {
+		topicConfig := TopicConfig{}
+		err := unmarshalConfigStrict(topicDoc, ictx, &topicConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -79,13 +133,238 @@ func LoadTopicsFile(path string) ([]TopicConfig, error) {
 	return topicConfigs, nil
 }
 
-// LoadTopicBytes loads a TopicConfig from YAML bytes.
+// LoadTopicBytes loads a TopicConfig from YAML (or JSON) bytes. Any include paths are evaluated
+// relative to the current working directory.
 func LoadTopicBytes(contents []byte) (TopicConfig, error) {
 	config := TopicConfig{}
-	err := unmarshalYAMLStrict(contents, &config)
+	err := unmarshalConfigStrict(contents, includeContext{}, &config)
 	return config, err
 }
 
+// LoadACLsFile loads one or more ACLConfigs from a path to a YAML, JSON, or TOML file. As with
+// topic configs, YAML files can contain multiple configs separated by "---" and JSON files can
+// contain a list of configs. If values is non-nil, the file is first rendered as a template with
+// the argument values.
+func LoadACLsFile(path string, values TemplateValues) ([]ACLConfig, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err = renderTemplate(path, contents, values)
+	if err != nil {
+		return nil, err
+	}
+
+	contents = []byte(os.ExpandEnv(string(contents)))
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	ictx := includeContext{
+		baseDir:   filepath.Dir(absPath),
+		expandEnv: true,
+		stack:     []string{absPath},
+		values:    values,
+	}
+
+	aclDocs, err := splitConfigDocs(contents, ConfigFormatForPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	aclConfigs := []ACLConfig{}
+
+	for _, aclDoc := range aclDocs {
+		aclConfig := ACLConfig{}
+		err := unmarshalConfigStrict(aclDoc, ictx, &aclConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		aclConfigs = append(aclConfigs, aclConfig)
+	}
+
+	return aclConfigs, nil
+}
+
+// CheckACLConsistency verifies that the argument ACL config is consistent with the argument
+// cluster, i.e. has the same cluster name, environment, and region.
+func CheckACLConsistency(aclConfig ACLConfig, clusterConfig ClusterConfig) error {
+	var err error
+
+	if aclConfig.Meta.Cluster != clusterConfig.Meta.Name {
+		err = multierror.Append(
+			err,
+			errors.New("ACL config cluster name does not match name in cluster config"),
+		)
+	}
+	if aclConfig.Meta.Environment != clusterConfig.Meta.Environment {
+		err = multierror.Append(
+			err,
+			errors.New("ACL config environment does not match cluster environment"),
+		)
+	}
+	if aclConfig.Meta.Region != clusterConfig.Meta.Region {
+		err = multierror.Append(
+			err,
+			errors.New("ACL config region does not match cluster region"),
+		)
+	}
+
+	return err
+}
+
+// LoadUsersFile loads one or more UserConfigs from a path to a YAML, JSON, or TOML file. Multiple
+// configs are handled in the same way as in LoadACLsFile.
+func LoadUsersFile(path string, values TemplateValues) ([]UserConfig, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err = renderTemplate(path, contents, values)
+	if err != nil {
+		return nil, err
+	}
+
+	contents = []byte(os.ExpandEnv(string(contents)))
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	ictx := includeContext{
+		baseDir:   filepath.Dir(absPath),
+		expandEnv: true,
+		stack:     []string{absPath},
+		values:    values,
+	}
+
+	userDocs, err := splitConfigDocs(contents, ConfigFormatForPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	userConfigs := []UserConfig{}
+
+	for _, userDoc := range userDocs {
+		userConfig := UserConfig{}
+		err := unmarshalConfigStrict(userDoc, ictx, &userConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		userConfigs = append(userConfigs, userConfig)
+	}
+
+	return userConfigs, nil
+}
+
+// CheckUserConsistency verifies that the argument user config is consistent with the argument
+// cluster, i.e. has the same cluster name, environment, and region.
+func CheckUserConsistency(userConfig UserConfig, clusterConfig ClusterConfig) error {
+	var err error
+
+	if userConfig.Meta.Cluster != clusterConfig.Meta.Name {
+		err = multierror.Append(
+			err,
+			errors.New("User config cluster name does not match name in cluster config"),
+		)
+	}
+	if userConfig.Meta.Environment != clusterConfig.Meta.Environment {
+		err = multierror.Append(
+			err,
+			errors.New("User config environment does not match cluster environment"),
+		)
+	}
+	if userConfig.Meta.Region != clusterConfig.Meta.Region {
+		err = multierror.Append(
+			err,
+			errors.New("User config region does not match cluster region"),
+		)
+	}
+
+	return err
+}
+
+// LoadRoleBindingsFile loads one or more RoleBindingConfigs from a path to a YAML, JSON, or TOML
+// file. Multiple configs are handled in the same way as in LoadACLsFile.
+func LoadRoleBindingsFile(path string, values TemplateValues) ([]RoleBindingConfig, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err = renderTemplate(path, contents, values)
+	if err != nil {
+		return nil, err
+	}
+
+	contents = []byte(os.ExpandEnv(string(contents)))
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	ictx := includeContext{
+		baseDir:   filepath.Dir(absPath),
+		expandEnv: true,
+		stack:     []string{absPath},
+		values:    values,
+	}
+
+	roleBindingDocs, err := splitConfigDocs(contents, ConfigFormatForPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	roleBindingConfigs := []RoleBindingConfig{}
+
+	for _, roleBindingDoc := range roleBindingDocs {
+		roleBindingConfig := RoleBindingConfig{}
+		err := unmarshalConfigStrict(roleBindingDoc, ictx, &roleBindingConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		roleBindingConfigs = append(roleBindingConfigs, roleBindingConfig)
+	}
+
+	return roleBindingConfigs, nil
+}
+
+// CheckRoleBindingConsistency verifies that the argument role binding config is consistent with
+// the argument cluster, i.e. has the same cluster name, environment, and region.
+func CheckRoleBindingConsistency(
+	roleBindingConfig RoleBindingConfig,
+	clusterConfig ClusterConfig,
+) error {
+	var err error
+
+	if roleBindingConfig.Meta.Cluster != clusterConfig.Meta.Name {
+		err = multierror.Append(
+			err,
+			errors.New("Role binding config cluster name does not match name in cluster config"),
+		)
+	}
+	if roleBindingConfig.Meta.Environment != clusterConfig.Meta.Environment {
+		err = multierror.Append(
+			err,
+			errors.New("Role binding config environment does not match cluster environment"),
+		)
+	}
+	if roleBindingConfig.Meta.Region != clusterConfig.Meta.Region {
+		err = multierror.Append(
+			err,
+			errors.New("Role binding config region does not match cluster region"),
+		)
+	}
+
+	return err
+}
+
 // CheckConsistency verifies that the argument topic config is consistent with the argument
 // cluster, e.g. has the same environment and region, etc.
 func CheckConsistency(topicConfig TopicConfig, clusterConfig ClusterConfig) error {
@@ -109,6 +388,15 @@ func CheckConsistency(topicConfig TopicConfig, clusterConfig ClusterConfig) erro
 			errors.New("Topic region does not match cluster region"),
 		)
 	}
+	if pattern := clusterConfig.UnmanagedTopicPattern(topicConfig.Meta.Name); pattern != "" {
+		err = multierror.Append(
+			err,
+			fmt.Errorf(
+				"Topic name matches unmanaged topic pattern '%s' in cluster config",
+				pattern,
+			),
+		)
+	}
 
 	return err
 }
@@ -125,8 +413,12 @@ func isEmpty(contents string) bool {
 	return true
 }
 
-func unmarshalYAMLStrict(y []byte, o interface{}) error {
-	jsonBytes, err := yaml.YAMLToJSON(y)
+func unmarshalConfigStrict(y []byte, ictx includeContext, o interface{}) error {
+	jsonBytes, err := resolveIncludes(y, ictx)
+	if err != nil {
+		return err
+	}
+	jsonBytes, err = migrateJSON(jsonBytes)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -130,4 +131,287 @@ func TestCheckConsistency(t *testing.T) {
 
 	assert.NoError(t, CheckConsistency(topicConfig, clusterConfig))
 	assert.Error(t, CheckConsistency(topicConfigNoMatch, clusterConfig))
+
+	clusterConfig.Spec.UnmanagedTopicPatterns = []string{"-changelog$", "^topic-"}
+	assert.NoError(t, clusterConfig.Validate())
+	assert.Error(t, CheckConsistency(topicConfig, clusterConfig))
+}
+
+func TestLoadACLsFile(t *testing.T) {
+	aclConfigs, err := LoadACLsFile("testdata/test-cluster/acls/acls-test.yaml", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(aclConfigs))
+
+	assert.Equal(
+		t,
+		ACLConfig{
+			Meta: ACLMeta{
+				Name:        "acls-test",
+				Cluster:     "test-cluster",
+				Region:      "test-region",
+				Environment: "test-env",
+				Description: "Test ACLs\n",
+			},
+			Spec: ACLConfigSpec{
+				Resources: []ACLResourceConfig{
+					{
+						Type: "cluster",
+						ACLs: []ACLEntryConfig{
+							{
+								Principal:  "User:producer",
+								Operations: []string{"idempotentWrite"},
+							},
+						},
+					},
+					{
+						Type:        "group",
+						Name:        "consumer-",
+						PatternType: "prefixed",
+						ACLs: []ACLEntryConfig{
+							{
+								Principal:  "User:consumer",
+								Operations: []string{"read", "describe"},
+							},
+						},
+					},
+				},
+			},
+		},
+		aclConfigs[0],
+	)
+	assert.Equal(t, "acls-test2", aclConfigs[1].Meta.Name)
+
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name:        "test-cluster",
+			Region:      "test-region",
+			Environment: "test-env",
+		},
+	}
+	for _, aclConfig := range aclConfigs {
+		assert.NoError(t, aclConfig.Validate())
+		assert.NoError(t, CheckACLConsistency(aclConfig, clusterConfig))
+	}
+
+	clusterConfig.Meta.Region = "other-region"
+	assert.Error(t, CheckACLConsistency(aclConfigs[0], clusterConfig))
+}
+
+func TestLoadUsersFile(t *testing.T) {
+	userConfigs, err := LoadUsersFile("testdata/test-cluster/users/users-test.yaml", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(userConfigs))
+
+	assert.Equal(
+		t,
+		UserConfig{
+			Meta: UserMeta{
+				Name:        "users-test",
+				Cluster:     "test-cluster",
+				Region:      "test-region",
+				Environment: "test-env",
+				Description: "Test users\n",
+			},
+			Spec: UserConfigSpec{
+				Users: []UserEntryConfig{
+					{
+						Name:      "producer",
+						Mechanism: "SCRAM-SHA-512",
+						Password: SecretRef{
+							FromEnv: "PRODUCER_PASSWORD",
+						},
+					},
+					{
+						Name:       "consumer",
+						Mechanism:  "SCRAM-SHA-256",
+						Iterations: 8192,
+						Password: SecretRef{
+							FromCommand: []string{
+								"vault",
+								"kv",
+								"get",
+								"-field=password",
+								"secret/kafka/consumer",
+							},
+						},
+					},
+				},
+			},
+		},
+		userConfigs[0],
+	)
+
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name:        "test-cluster",
+			Region:      "test-region",
+			Environment: "test-env",
+		},
+	}
+	assert.NoError(t, userConfigs[0].Validate())
+	assert.NoError(t, CheckUserConsistency(userConfigs[0], clusterConfig))
+
+	clusterConfig.Meta.Environment = "other-env"
+	assert.Error(t, CheckUserConsistency(userConfigs[0], clusterConfig))
+}
+
+func TestLoadRoleBindingsFile(t *testing.T) {
+	roleBindingConfigs, err := LoadRoleBindingsFile(
+		"testdata/test-cluster/role-bindings/role-bindings-test.yaml",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(roleBindingConfigs))
+
+	assert.Equal(
+		t,
+		RoleBindingConfig{
+			Meta: RoleBindingMeta{
+				Name:        "role-bindings-test",
+				Cluster:     "test-cluster",
+				Region:      "test-region",
+				Environment: "test-env",
+				Description: "Test role bindings\n",
+			},
+			Spec: RoleBindingConfigSpec{
+				Principals: []RoleBindingPrincipalConfig{
+					{
+						Principal: "User:orders-service",
+						Bindings: []RoleBindingEntryConfig{
+							{
+								Role:         "DeveloperWrite",
+								ResourceType: "topic",
+								Name:         "orders-",
+								PatternType:  "prefixed",
+							},
+							{
+								Role:         "DeveloperRead",
+								ResourceType: "group",
+								Name:         "orders-service",
+							},
+						},
+					},
+					{
+						Principal: "Group:kafka-operators",
+						Bindings: []RoleBindingEntryConfig{
+							{
+								Role: "Operator",
+							},
+						},
+					},
+				},
+			},
+		},
+		roleBindingConfigs[0],
+	)
+
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name:        "test-cluster",
+			Region:      "test-region",
+			Environment: "test-env",
+		},
+	}
+	assert.NoError(t, roleBindingConfigs[0].Validate())
+	assert.NoError(t, CheckRoleBindingConsistency(roleBindingConfigs[0], clusterConfig))
+
+	clusterConfig.Meta.Environment = "other-env"
+	assert.Error(t, CheckRoleBindingConsistency(roleBindingConfigs[0], clusterConfig))
+}
+
+func TestLoadWithIncludes(t *testing.T) {
+	clusterConfig, err := LoadClusterFile("testdata/test-cluster/cluster-include.yaml", false)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		SASLConfig{
+			Enabled:   true,
+			Mechanism: "SCRAM-SHA-512",
+			Username:  "cluster-user",
+			Password:  "shared-password",
+		},
+		clusterConfig.Spec.SASL,
+	)
+	assert.Equal(t, []string{"bootstrap-addr:9092"}, clusterConfig.Spec.BootstrapAddrs)
+	assert.NoError(t, clusterConfig.Validate())
+
+	topicConfigs, err := LoadTopicsFile("testdata/test-cluster/topics/topic-test-include.yaml")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(topicConfigs))
+	topicConfig := topicConfigs[0]
+	topicConfig.SetDefaults()
+
+	assert.Equal(
+		t,
+		TopicSpec{
+			Partitions:        9,
+			ReplicationFactor: 2,
+			RetentionMinutes:  100,
+			PlacementConfig: TopicPlacementConfig{
+				Strategy: PlacementStrategyInRack,
+				Picker:   PickerMethodRandomized,
+			},
+			MigrationConfig: &TopicMigrationConfig{
+				PartitionBatchSize: 1,
+			},
+			Settings: TopicSettings{
+				"cleanup.policy":        "delete",
+				"max.compaction.lag.ms": 12345.0,
+			},
+		},
+		topicConfig.Spec,
+	)
+	assert.NoError(t, topicConfig.Validate(3))
+
+	_, err = LoadTopicsFile("testdata/test-cluster/topics/topic-test-include-cycle.yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Include cycle detected")
+}
+
+func TestLoadJSONAndTOML(t *testing.T) {
+	os.Setenv("K2_TEST_ENV_VAR", "test-region")
+	defer os.Unsetenv("K2_TEST_ENV_VAR")
+
+	expClusterConfig, err := LoadClusterFile("testdata/test-cluster/cluster.yaml", true)
+	require.NoError(t, err)
+	expClusterConfig.RootDir = ""
+
+	expTopicConfigs, err := LoadTopicsFile("testdata/test-cluster/topics/topic-test.yaml")
+	require.NoError(t, err)
+
+	for _, dir := range []string{"testdata/test-cluster-json", "testdata/test-cluster-toml"} {
+		clusterPath := ClusterConfigPathForDir(dir)
+		clusterConfig, err := LoadClusterFile(clusterPath, true)
+		require.NoError(t, err, dir)
+		clusterConfig.RootDir = ""
+
+		// Ignore the apiVersion, which is only set in the JSON and TOML configs
+		clusterConfig.APIVersion = ""
+		assert.Equal(t, expClusterConfig, clusterConfig, dir)
+
+		topicPaths, err := filepath.Glob(filepath.Join(dir, "topics", "topic-test.*"))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(topicPaths), dir)
+
+		topicConfigs, err := LoadTopicsFile(topicPaths[0])
+		require.NoError(t, err, dir)
+		assert.Equal(t, expTopicConfigs, topicConfigs, dir)
+	}
+
+	topicConfigs, err := LoadTopicsFile("testdata/test-cluster-json/topics/topic-test-multi.json")
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(topicConfigs))
+	assert.Equal(t, "topic-test1", topicConfigs[0].Meta.Name)
+	assert.Equal(t, "topic-test2", topicConfigs[1].Meta.Name)
+
+	assert.Equal(
+		t,
+		"testdata/test-cluster/cluster.yaml",
+		ClusterConfigPathForDir("testdata/test-cluster"),
+	)
+	assert.Equal(
+		t,
+		"testdata/test-cluster-toml/cluster.toml",
+		ClusterConfigPathForDir("testdata/test-cluster-toml"),
+	)
 }
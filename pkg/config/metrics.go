@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/topicctl/pkg/metrics"
+)
+
+const (
+	defaultMetricsTopicLabel     = "topic"
+	defaultMetricsPartitionLabel = "partition"
+	defaultMetricsSampleInterval = 30 * time.Second
+)
+
+// BrokerMetricsConfig stores where the per-partition throughput metrics of the brokers in a
+// cluster are scraped from, e.g. the Prometheus endpoints of JMX exporter agents running
+// alongside the brokers. These are used by throughput-aware rebalances.
+type BrokerMetricsConfig struct {
+	// URLTemplate is the URL of each broker's metrics endpoint, with {host} and {id} replaced
+	// by the broker's host and ID, e.g. http://{host}:7071/metrics.
+	URLTemplate string `json:"urlTemplate"`
+
+	// Metric is the name of the per-partition metric, e.g. kafka_log_log_logendoffset.
+	Metric string `json:"metric"`
+
+	// Type is either counter, for metrics that only go up (e.g. log end offsets), or gauge, for
+	// metrics that are already rates. It defaults to counter.
+	Type string `json:"type,omitempty"`
+
+	// TopicLabel and PartitionLabel are the labels of the metric that have the topic name and
+	// partition ID; they default to topic and partition.
+	TopicLabel     string `json:"topicLabel,omitempty"`
+	PartitionLabel string `json:"partitionLabel,omitempty"`
+
+	// SampleInterval is the time between the two scrapes that the rates of counters are
+	// computed from, e.g. 1m. It defaults to 30s.
+	SampleInterval string `json:"sampleInterval,omitempty"`
+}
+
+// ThroughputConfig returns the settings for the throughput source of the metrics config, with
+// defaults applied.
+func (b BrokerMetricsConfig) ThroughputConfig() (metrics.ThroughputConfig, error) {
+	throughputConfig := metrics.ThroughputConfig{
+		URLTemplate:    b.URLTemplate,
+		Metric:         b.Metric,
+		Type:           b.Type,
+		TopicLabel:     b.TopicLabel,
+		PartitionLabel: b.PartitionLabel,
+		SampleInterval: defaultMetricsSampleInterval,
+	}
+
+	if throughputConfig.Type == "" {
+		throughputConfig.Type = metrics.MetricTypeCounter
+	}
+	if throughputConfig.TopicLabel == "" {
+		throughputConfig.TopicLabel = defaultMetricsTopicLabel
+	}
+	if throughputConfig.PartitionLabel == "" {
+		throughputConfig.PartitionLabel = defaultMetricsPartitionLabel
+	}
+	if b.SampleInterval != "" {
+		interval, err := time.ParseDuration(b.SampleInterval)
+		if err != nil {
+			return metrics.ThroughputConfig{}, fmt.Errorf(
+				"Error parsing sample interval: %+v",
+				err,
+			)
+		}
+		throughputConfig.SampleInterval = interval
+	}
+
+	return throughputConfig, nil
+}
+
+// Validate evaluates whether the broker metrics config is valid.
+func (b BrokerMetricsConfig) Validate() error {
+	var err error
+
+	if b.URLTemplate == "" {
+		err = multierror.Append(err, errors.New("URLTemplate must be set"))
+	} else {
+		exampleURL := strings.NewReplacer("{host}", "broker", "{id}", "1").Replace(b.URLTemplate)
+		parsed, urlErr := url.Parse(exampleURL)
+		if urlErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("URLTemplate '%s' must be an http(s) URL", b.URLTemplate),
+			)
+		}
+	}
+	if b.Metric == "" {
+		err = multierror.Append(err, errors.New("Metric must be set"))
+	}
+
+	throughputConfig, configErr := b.ThroughputConfig()
+	if configErr != nil {
+		err = multierror.Append(err, configErr)
+	} else {
+		if throughputConfig.Type != metrics.MetricTypeCounter &&
+			throughputConfig.Type != metrics.MetricTypeGauge {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Type must be in %+v", metrics.AllMetricTypes),
+			)
+		}
+		if throughputConfig.SampleInterval <= 0 {
+			err = multierror.Append(err, errors.New("SampleInterval must be positive"))
+		}
+	}
+
+	return err
+}
+
+// NewThroughputSource returns a new source for the partition throughputs of the cluster, using
+// the settings in the brokerMetrics section of the cluster config.
+func (c ClusterConfig) NewThroughputSource() (*metrics.ThroughputSource, error) {
+	if c.Spec.BrokerMetrics == nil {
+		return nil, fmt.Errorf(
+			"Cluster config for %s doesn't have a brokerMetrics section",
+			c.Meta.Name,
+		)
+	}
+
+	throughputConfig, err := c.Spec.BrokerMetrics.ThroughputConfig()
+	if err != nil {
+		return nil, err
+	}
+	return metrics.NewThroughputSource(throughputConfig)
+}
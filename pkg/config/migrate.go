@@ -0,0 +1,480 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const (
+	// apiVersionKey is the top-level key that stores the config format version.
+	apiVersionKey = "apiVersion"
+
+	// APIVersionV0 is the original config format. Configs without an apiVersion are assumed
+	// to be in this format.
+	APIVersionV0 = "v0"
+
+	// APIVersionV1 is the config format that uses "rack" instead of "zone" in all placement
+	// settings.
+	APIVersionV1 = "v1"
+
+	// CurrentAPIVersion is the latest config format version supported by this tool.
+	CurrentAPIVersion = APIVersionV1
+)
+
+var allAPIVersions = []string{
+	APIVersionV0,
+	APIVersionV1,
+}
+
+// configMigration converts a config document from one API version to the next one. The migrate
+// function is used when loading configs, and the edit one is used when rewriting YAML configs in
+// place; the two should make the same changes.
+type configMigration struct {
+	fromVersion string
+	toVersion   string
+	migrate     func(obj map[string]interface{}) bool
+	edit        func(root *yamlv3.Node, editor *yamlEditor) (bool, error)
+}
+
+// configMigrations is the ordered list of all migrations. Each one should be idempotent and
+// should only touch keys that it knows about so that it can be applied to topic configs,
+// cluster configs, and include fragments alike.
+var configMigrations = []configMigration{
+	{
+		fromVersion: APIVersionV0,
+		toVersion:   APIVersionV1,
+		migrate:     migrateV0ToV1,
+		edit:        editV0ToV1,
+	},
+}
+
+// MigrateConfigFile migrates all of the config documents in the argument file to the current
+// API version. It returns the new file contents and a boolean indicating whether anything was
+// changed. Empty (e.g., comment-only) documents are preserved as-is.
+//
+// Only YAML files can be migrated in place; JSON and TOML configs are still migrated
+// automatically at load time.
+func MigrateConfigFile(path string) ([]byte, bool, error) {
+	if format := ConfigFormatForPath(path); format != ConfigFormatYAML {
+		return nil, false, fmt.Errorf(
+			"Cannot migrate %s; only YAML configs can be migrated in place (got %s)",
+			path,
+			format,
+		)
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	trimmedFile := strings.TrimSpace(string(contents))
+	docStrs := sep.Split(trimmedFile, -1)
+
+	migratedStrs := []string{}
+	anyChanged := false
+
+	for _, docStr := range docStrs {
+		docStr = strings.TrimSpace(docStr)
+		if isEmpty(docStr) {
+			migratedStrs = append(migratedStrs, docStr)
+			continue
+		}
+
+		migrated, changed, err := MigrateConfigBytes([]byte(docStr))
+		if err != nil {
+			return nil, false, fmt.Errorf("Error migrating config in %s: %+v", path, err)
+		}
+
+		if changed {
+			anyChanged = true
+			migratedStrs = append(
+				migratedStrs,
+				strings.TrimSpace(string(migrated)),
+			)
+		} else {
+			migratedStrs = append(migratedStrs, docStr)
+		}
+	}
+
+	if !anyChanged {
+		return contents, false, nil
+	}
+
+	return []byte(strings.Join(migratedStrs, "\n---\n") + "\n"), true, nil
+}
+
+// MigrateConfigBytes migrates a single YAML config document to the current API version. It
+// returns the migrated YAML and a boolean indicating whether anything was changed.
+//
+// The document is edited in place so that comments, key order, and formatting are kept; only the
+// apiVersion line and the values and keys touched by the migrations are changed.
+func MigrateConfigBytes(contents []byte) ([]byte, bool, error) {
+	doc := yamlv3.Node{}
+	if err := yamlv3.Unmarshal(contents, &doc); err != nil {
+		return nil, false, err
+	}
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 ||
+		doc.Content[0].Kind != yamlv3.MappingNode {
+		return nil, false, fmt.Errorf("Config must be a YAML mapping")
+	}
+
+	root := doc.Content[0]
+	if root.Style&yamlv3.FlowStyle != 0 {
+		return nil, false, fmt.Errorf("Cannot migrate flow-style configs in place")
+	}
+
+	version := APIVersionV0
+	_, versionValue := yamlMappingEntry(root, apiVersionKey)
+	if versionValue != nil {
+		if versionValue.Kind != yamlv3.ScalarNode || versionValue.Tag != "!!str" {
+			return nil, false, fmt.Errorf(
+				"apiVersion must be a string, got %+v",
+				versionValue.Value,
+			)
+		}
+		if versionValue.Value != "" {
+			version = versionValue.Value
+		}
+	}
+
+	if !isValidAPIVersion(version) {
+		return nil, false, fmt.Errorf(
+			"Unsupported config apiVersion '%s'; this version of topicctl supports %+v",
+			version,
+			allAPIVersions,
+		)
+	}
+
+	editor := newYAMLEditor(contents)
+	changed := false
+
+	for _, migration := range configMigrations {
+		if migration.fromVersion != version {
+			continue
+		}
+
+		migrationChanged, err := migration.edit(root, editor)
+		if err != nil {
+			return nil, false, err
+		}
+		if migrationChanged {
+			changed = true
+		}
+		version = migration.toVersion
+	}
+
+	if versionValue == nil {
+		editor.insertLine(
+			root.Content[0].Line,
+			fmt.Sprintf(
+				"%s%s: %s",
+				strings.Repeat(" ", root.Content[0].Column-1),
+				apiVersionKey,
+				CurrentAPIVersion,
+			),
+		)
+		changed = true
+	} else if versionValue.Value != CurrentAPIVersion {
+		editor.replaceScalar(versionValue, CurrentAPIVersion)
+		changed = true
+	}
+
+	if !changed {
+		return contents, false, nil
+	}
+
+	return editor.apply(), true, nil
+}
+
+// migrateJSON runs the config migrations on JSON-formatted config bytes. The apiVersion value
+// is left as-is so that the loaded config reflects what's actually in the file.
+func migrateJSON(jsonBytes []byte) ([]byte, error) {
+	obj, _, err := jsonToMap(jsonBytes, apiVersionKey)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return jsonBytes, nil
+	}
+
+	changed, err := migrateConfigMap(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return jsonBytes, nil
+	}
+
+	return json.Marshal(obj)
+}
+
+// migrateConfigMap applies all of the migrations that are needed to bring the argument config
+// document up to the current API version.
+func migrateConfigMap(obj map[string]interface{}) (bool, error) {
+	version := APIVersionV0
+	if versionValue, ok := obj[apiVersionKey]; ok {
+		versionStr, isStr := versionValue.(string)
+		if !isStr {
+			return false, fmt.Errorf("apiVersion must be a string, got %+v", versionValue)
+		}
+		if versionStr != "" {
+			version = versionStr
+		}
+	}
+
+	if !isValidAPIVersion(version) {
+		return false, fmt.Errorf(
+			"Unsupported config apiVersion '%s'; this version of topicctl supports %+v",
+			version,
+			allAPIVersions,
+		)
+	}
+
+	changed := false
+
+	for _, migration := range configMigrations {
+		if migration.fromVersion != version {
+			continue
+		}
+
+		if migration.migrate(obj) {
+			changed = true
+		}
+		version = migration.toVersion
+	}
+
+	return changed, nil
+}
+
+func isValidAPIVersion(version string) bool {
+	for _, validVersion := range allAPIVersions {
+		if version == validVersion {
+			return true
+		}
+	}
+
+	return false
+}
+
+// migrateV0ToV1 replaces the older, "zone"-based placement names with their "rack"-based
+// equivalents.
+func migrateV0ToV1(obj map[string]interface{}) bool {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	placement, ok := spec["placement"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	changed := false
+
+	switch placement["strategy"] {
+	case "in-zone":
+		placement["strategy"] = string(PlacementStrategyInRack)
+		changed = true
+	case "static-in-zone":
+		placement["strategy"] = string(PlacementStrategyStaticInRack)
+		changed = true
+	}
+
+	if zoneAssignments, ok := placement["staticZoneAssignments"]; ok {
+		if _, hasRack := placement["staticRackAssignments"]; !hasRack {
+			placement["staticRackAssignments"] = zoneAssignments
+		}
+		delete(placement, "staticZoneAssignments")
+		changed = true
+	}
+
+	return changed
+}
+
+// editV0ToV1 is the in-place version of migrateV0ToV1.
+func editV0ToV1(root *yamlv3.Node, editor *yamlEditor) (bool, error) {
+	_, spec := yamlMappingEntry(root, "spec")
+	if spec == nil || spec.Kind != yamlv3.MappingNode {
+		return false, nil
+	}
+	_, placement := yamlMappingEntry(spec, "placement")
+	if placement == nil || placement.Kind != yamlv3.MappingNode {
+		return false, nil
+	}
+
+	changed := false
+
+	if _, strategy := yamlMappingEntry(placement, "strategy"); strategy != nil {
+		switch strategy.Value {
+		case "in-zone":
+			editor.replaceScalar(strategy, string(PlacementStrategyInRack))
+			changed = true
+		case "static-in-zone":
+			editor.replaceScalar(strategy, string(PlacementStrategyStaticInRack))
+			changed = true
+		}
+	}
+
+	if zoneKey, _ := yamlMappingEntry(placement, "staticZoneAssignments"); zoneKey != nil {
+		if rackKey, _ := yamlMappingEntry(placement, "staticRackAssignments"); rackKey == nil {
+			editor.replaceScalar(zoneKey, "staticRackAssignments")
+		} else {
+			if placement.Style&yamlv3.FlowStyle != 0 {
+				return false, fmt.Errorf(
+					"Cannot remove staticZoneAssignments from flow-style placement in place",
+				)
+			}
+			editor.deleteEntry(zoneKey)
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// yamlMappingEntry returns the key and value nodes for the argument key in a mapping node, or
+// nils if the key isn't set.
+func yamlMappingEntry(mapping *yamlv3.Node, key string) (*yamlv3.Node, *yamlv3.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// yamlEdit is a single change to the lines of a YAML document. Positions are 1-based, like the
+// ones in yaml.v3 nodes.
+type yamlEdit struct {
+	line   int
+	column int
+
+	// If endLine is set, then the lines from line through endLine are removed. Otherwise, if
+	// old is set, then it's replaced by new starting at column, and if it's not, then new is
+	// inserted as a line before line.
+	endLine int
+	old     string
+	new     string
+}
+
+// yamlEditor collects edits to the lines of a YAML document and applies them all at once, so
+// that the positions in the parsed nodes stay valid while the edits are being made.
+type yamlEditor struct {
+	lines []string
+	edits []yamlEdit
+}
+
+func newYAMLEditor(contents []byte) *yamlEditor {
+	return &yamlEditor{
+		lines: strings.Split(string(contents), "\n"),
+	}
+}
+
+// replaceScalar replaces the text of a scalar node, keeping its quoting style.
+func (e *yamlEditor) replaceScalar(node *yamlv3.Node, value string) {
+	old := node.Value
+	new := value
+
+	switch {
+	case node.Style&yamlv3.DoubleQuotedStyle != 0:
+		old = fmt.Sprintf("\"%s\"", old)
+		new = fmt.Sprintf("\"%s\"", new)
+	case node.Style&yamlv3.SingleQuotedStyle != 0:
+		old = fmt.Sprintf("'%s'", old)
+		new = fmt.Sprintf("'%s'", new)
+	}
+
+	e.edits = append(
+		e.edits,
+		yamlEdit{
+			line:   node.Line,
+			column: node.Column,
+			old:    old,
+			new:    new,
+		},
+	)
+}
+
+// insertLine inserts a new line before the argument one.
+func (e *yamlEditor) insertLine(line int, text string) {
+	e.edits = append(
+		e.edits,
+		yamlEdit{
+			line: line,
+			new:  text,
+		},
+	)
+}
+
+// deleteEntry removes the lines of a block mapping entry, from its key through the last line
+// that's indented more than the key (or that has a sequence item at the key's indentation).
+func (e *yamlEditor) deleteEntry(key *yamlv3.Node) {
+	indent := key.Column - 1
+	endLine := key.Line
+
+	for l := key.Line + 1; l <= len(e.lines); l++ {
+		line := e.lines[l-1]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if lineIndent < indent ||
+			(lineIndent == indent && !strings.HasPrefix(trimmed, "- ") && trimmed != "-") {
+			break
+		}
+		endLine = l
+	}
+
+	e.edits = append(
+		e.edits,
+		yamlEdit{
+			line:    key.Line,
+			column:  key.Column,
+			endLine: endLine,
+		},
+	)
+}
+
+// apply makes all of the edits, starting with the last one in the document, and returns the
+// updated contents.
+func (e *yamlEditor) apply() []byte {
+	edits := append([]yamlEdit{}, e.edits...)
+	sort.SliceStable(edits, func(a, b int) bool {
+		if edits[a].line != edits[b].line {
+			return edits[a].line > edits[b].line
+		}
+		return edits[a].column > edits[b].column
+	})
+
+	lines := append([]string{}, e.lines...)
+
+	for _, edit := range edits {
+		switch {
+		case edit.endLine > 0:
+			lines = append(lines[:edit.line-1], lines[edit.endLine:]...)
+		case edit.old != "":
+			line := lines[edit.line-1]
+			start := edit.column - 1
+			if start > len(line) || !strings.HasPrefix(line[start:], edit.old) {
+				// Columns count characters rather than bytes, so fall back to searching the
+				// line if there's anything multi-byte before the value.
+				start = strings.Index(line, edit.old)
+				if start == -1 {
+					continue
+				}
+			}
+			lines[edit.line-1] = line[:start] + edit.new + line[start+len(edit.old):]
+		default:
+			lines = append(lines[:edit.line-1], append([]string{edit.new}, lines[edit.line-1:]...)...)
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadV0TopicConfig(t *testing.T) {
+	topicConfigs, err := LoadTopicsFile("testdata/test-cluster/topics/topic-test-v0.yaml")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(topicConfigs))
+
+	topicConfig := topicConfigs[0]
+	assert.Equal(t, "", topicConfig.APIVersion)
+	assert.Equal(t, PlacementStrategyStaticInRack, topicConfig.Spec.PlacementConfig.Strategy)
+	assert.Equal(
+		t,
+		[]string{"zone1", "zone2", "zone3"},
+		topicConfig.Spec.PlacementConfig.StaticRackAssignments,
+	)
+}
+
+func TestMigrateConfigBytes(t *testing.T) {
+	type testCase struct {
+		description string
+		input       string
+		expChanged  bool
+		expOutput   string
+		expErr      bool
+	}
+
+	testCases := []testCase{
+		{
+			description: "v0 config",
+			input: `meta:
+  name: test-topic
+spec:
+  partitions: 3
+  placement:
+    strategy: in-zone
+`,
+			expChanged: true,
+			expOutput: `apiVersion: v1
+meta:
+  name: test-topic
+spec:
+  partitions: 3
+  placement:
+    strategy: in-rack
+`,
+		},
+		{
+			description: "v0 config with static zone assignments",
+			input: `spec:
+  placement:
+    strategy: static-in-zone
+    staticZoneAssignments: [zone1, zone2]
+`,
+			expChanged: true,
+			expOutput: `apiVersion: v1
+spec:
+  placement:
+    strategy: static-in-rack
+    staticRackAssignments: [zone1, zone2]
+`,
+		},
+		{
+			description: "v0 config with comments",
+			input: `# Owned by the data team
+meta:
+  name: test-topic # the topic name
+  cluster: test-cluster
+
+spec:
+  # Keep these in sync with the producers
+  partitions: 3
+  replicationFactor: 2
+  placement:
+    strategy: "static-in-zone" # pinned for now
+    staticZoneAssignments:
+    - zone1 # primary
+    - zone2
+`,
+			expChanged: true,
+			expOutput: `# Owned by the data team
+apiVersion: v1
+meta:
+  name: test-topic # the topic name
+  cluster: test-cluster
+
+spec:
+  # Keep these in sync with the producers
+  partitions: 3
+  replicationFactor: 2
+  placement:
+    strategy: "static-in-rack" # pinned for now
+    staticRackAssignments:
+    - zone1 # primary
+    - zone2
+`,
+		},
+		{
+			description: "v0 config with zone and rack assignments",
+			input: `apiVersion: v0
+spec:
+  placement:
+    staticZoneAssignments:
+      - zone1
+      - zone2
+    staticRackAssignments: [rack1, rack2]
+    strategy: static-in-rack
+`,
+			expChanged: true,
+			expOutput: `apiVersion: v1
+spec:
+  placement:
+    staticRackAssignments: [rack1, rack2]
+    strategy: static-in-rack
+`,
+		},
+		{
+			description: "unversioned config without zone settings",
+			input: `meta:
+  name: test-cluster
+`,
+			expChanged: true,
+			expOutput: `apiVersion: v1
+meta:
+  name: test-cluster
+`,
+		},
+		{
+			description: "current config",
+			input: `apiVersion: v1
+meta:
+  name:   test-cluster # not reformatted
+`,
+			expChanged: false,
+			expOutput: `apiVersion: v1
+meta:
+  name:   test-cluster # not reformatted
+`,
+		},
+		{
+			description: "unsupported version",
+			input: `apiVersion: v100
+meta:
+  name: test-cluster
+`,
+			expErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		output, changed, err := MigrateConfigBytes([]byte(testCase.input))
+		if testCase.expErr {
+			assert.Error(t, err, testCase.description)
+			continue
+		}
+
+		require.NoError(t, err, testCase.description)
+		assert.Equal(t, testCase.expChanged, changed, testCase.description)
+		assert.Equal(t, testCase.expOutput, string(output), testCase.description)
+	}
+}
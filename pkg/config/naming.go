@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// TopicFileNamingPolicy is a string type that governs how topic config files must be named
+// relative to the topics that they contain.
+type TopicFileNamingPolicy string
+
+const (
+	// TopicFileNamingPolicyNone doesn't put any constraints on topic config file names. This is
+	// the default.
+	TopicFileNamingPolicyNone TopicFileNamingPolicy = "none"
+
+	// TopicFileNamingPolicyStrict requires that each topic config file contain exactly one
+	// topic and that the file be named [topic name].[extension].
+	TopicFileNamingPolicyStrict TopicFileNamingPolicy = "strict"
+
+	// TopicFileNamingPolicyTeamDirectories has the same requirements as
+	// TopicFileNamingPolicyStrict, and also requires that each file be in a per-team directory
+	// whose name is a prefix of the topic name, e.g. payments/payments-events.yaml.
+	TopicFileNamingPolicyTeamDirectories TopicFileNamingPolicy = "team-directories"
+)
+
+var allTopicFileNamingPolicies = []TopicFileNamingPolicy{
+	TopicFileNamingPolicyNone,
+	TopicFileNamingPolicyStrict,
+	TopicFileNamingPolicyTeamDirectories,
+}
+
+// teamPrefixSeparators are the characters that can separate the team prefix from the rest of
+// the topic name under TopicFileNamingPolicyTeamDirectories.
+var teamPrefixSeparators = []string{"-", ".", "_"}
+
+// CheckTopicFileName verifies that the argument topic config file, which contains the argument
+// topic configs, is named in accordance with the cluster's topic file naming policy.
+func (c ClusterConfig) CheckTopicFileName(
+	topicConfigPath string,
+	topicConfigs []TopicConfig,
+) error {
+	policy := c.Spec.TopicFileNaming
+	if policy == "" || policy == TopicFileNamingPolicyNone {
+		return nil
+	}
+
+	if len(topicConfigs) != 1 {
+		return fmt.Errorf(
+			"Topic config %s contains %d topics, but the '%s' topicFileNaming policy of cluster %s requires exactly one topic per file",
+			topicConfigPath,
+			len(topicConfigs),
+			policy,
+			c.Meta.Name,
+		)
+	}
+
+	topicName := topicConfigs[0].Meta.Name
+	base := filepath.Base(topicConfigPath)
+	fileName := strings.TrimSuffix(base, filepath.Ext(base))
+
+	if fileName != topicName {
+		return fmt.Errorf(
+			"Topic config %s should be named %s%s to satisfy the '%s' topicFileNaming policy of cluster %s",
+			topicConfigPath,
+			topicName,
+			filepath.Ext(base),
+			policy,
+			c.Meta.Name,
+		)
+	}
+
+	if policy == TopicFileNamingPolicyTeamDirectories {
+		absPath, err := filepath.Abs(topicConfigPath)
+		if err != nil {
+			return err
+		}
+		teamDir := filepath.Base(filepath.Dir(absPath))
+
+		if !hasTeamPrefix(topicName, teamDir) {
+			return fmt.Errorf(
+				"Topic %s in %s should be prefixed with its team directory name (%s) to satisfy the '%s' topicFileNaming policy of cluster %s",
+				topicName,
+				topicConfigPath,
+				teamDir,
+				policy,
+				c.Meta.Name,
+			)
+		}
+	}
+
+	return nil
+}
+
+func hasTeamPrefix(topicName string, team string) bool {
+	for _, separator := range teamPrefixSeparators {
+		if strings.HasPrefix(topicName, team+separator) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isValidTopicFileNamingPolicy(policy TopicFileNamingPolicy) bool {
+	for _, validPolicy := range allTopicFileNamingPolicies {
+		if policy == validPolicy {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTopicFileName(t *testing.T) {
+	type testCase struct {
+		description string
+		policy      TopicFileNamingPolicy
+		path        string
+		topicNames  []string
+		expError    bool
+	}
+
+	testCases := []testCase{
+		{
+			description: "no policy",
+			path:        "topics/anything.yaml",
+			topicNames:  []string{"topic1", "topic2"},
+			expError:    false,
+		},
+		{
+			description: "none policy",
+			policy:      TopicFileNamingPolicyNone,
+			path:        "topics/anything.yaml",
+			topicNames:  []string{"topic1"},
+			expError:    false,
+		},
+		{
+			description: "strict match",
+			policy:      TopicFileNamingPolicyStrict,
+			path:        "topics/topic1.yaml",
+			topicNames:  []string{"topic1"},
+			expError:    false,
+		},
+		{
+			description: "strict match with json",
+			policy:      TopicFileNamingPolicyStrict,
+			path:        "topics/topic1.json",
+			topicNames:  []string{"topic1"},
+			expError:    false,
+		},
+		{
+			description: "strict mismatch",
+			policy:      TopicFileNamingPolicyStrict,
+			path:        "topics/topic1.yaml",
+			topicNames:  []string{"topic2"},
+			expError:    true,
+		},
+		{
+			description: "strict multiple topics",
+			policy:      TopicFileNamingPolicyStrict,
+			path:        "topics/topic1.yaml",
+			topicNames:  []string{"topic1", "topic1-retry"},
+			expError:    true,
+		},
+		{
+			description: "team directories match",
+			policy:      TopicFileNamingPolicyTeamDirectories,
+			path:        "payments/payments-events.yaml",
+			topicNames:  []string{"payments-events"},
+			expError:    false,
+		},
+		{
+			description: "team directories match with dot separator",
+			policy:      TopicFileNamingPolicyTeamDirectories,
+			path:        "payments/payments.events.yaml",
+			topicNames:  []string{"payments.events"},
+			expError:    false,
+		},
+		{
+			description: "team directories wrong directory",
+			policy:      TopicFileNamingPolicyTeamDirectories,
+			path:        "topics/payments-events.yaml",
+			topicNames:  []string{"payments-events"},
+			expError:    true,
+		},
+		{
+			description: "team directories wrong file name",
+			policy:      TopicFileNamingPolicyTeamDirectories,
+			path:        "payments/events.yaml",
+			topicNames:  []string{"payments-events"},
+			expError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		clusterConfig := ClusterConfig{
+			Meta: ClusterMeta{
+				Name: "test-cluster",
+			},
+			Spec: ClusterSpec{
+				TopicFileNaming: testCase.policy,
+			},
+		}
+
+		topicConfigs := []TopicConfig{}
+		for _, topicName := range testCase.topicNames {
+			topicConfigs = append(
+				topicConfigs,
+				TopicConfig{
+					Meta: TopicMeta{
+						Name: topicName,
+					},
+				},
+			)
+		}
+
+		err := clusterConfig.CheckTopicFileName(testCase.path, topicConfigs)
+		if testCase.expError {
+			assert.Error(t, err, testCase.description)
+		} else {
+			assert.NoError(t, err, testCase.description)
+		}
+	}
+}
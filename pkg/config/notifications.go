@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// NotificationFormat is a string type that identifies the payload format of a notification
+// webhook.
+type NotificationFormat string
+
+const (
+	// NotificationFormatJSON sends the notification details as a generic JSON object. This is
+	// the default.
+	NotificationFormatJSON NotificationFormat = "json"
+
+	// NotificationFormatSlack sends a text message that's compatible with Slack incoming
+	// webhooks.
+	NotificationFormatSlack NotificationFormat = "slack"
+)
+
+var allNotificationFormats = []NotificationFormat{
+	NotificationFormatJSON,
+	NotificationFormatSlack,
+}
+
+// NotificationEvent is a string type that identifies the point in an apply run that a
+// notification is sent at.
+type NotificationEvent string
+
+const (
+	// NotificationEventStart is sent before the changes to a topic are applied.
+	NotificationEventStart NotificationEvent = "start"
+
+	// NotificationEventSuccess is sent after the changes to a topic are applied successfully.
+	NotificationEventSuccess NotificationEvent = "success"
+
+	// NotificationEventFailure is sent if there's an error applying the changes to a topic.
+	NotificationEventFailure NotificationEvent = "failure"
+)
+
+var allNotificationEvents = []NotificationEvent{
+	NotificationEventStart,
+	NotificationEventSuccess,
+	NotificationEventFailure,
+}
+
+// NotificationConfig stores the details of a webhook that's notified when topics in a cluster
+// are changed by apply.
+type NotificationConfig struct {
+	// URL is the webhook URL. Since these often contain secrets, consider setting it from an
+	// environment variable or template value.
+	URL string `json:"url"`
+
+	// Format is the payload format; if unset, NotificationFormatJSON is used.
+	Format NotificationFormat `json:"format,omitempty"`
+
+	// Events are the events that the webhook is notified for; if unset, all events are sent.
+	Events []NotificationEvent `json:"events,omitempty"`
+
+	// DryRuns is set if the webhook should also be notified for dry-runs.
+	DryRuns bool `json:"dryRuns,omitempty"`
+}
+
+// Validate evaluates whether the notification config is valid.
+func (n NotificationConfig) Validate() error {
+	var err error
+
+	if n.URL == "" {
+		err = multierror.Append(err, errors.New("URL must be set"))
+	} else if parsedURL, parseErr := url.Parse(n.URL); parseErr != nil {
+		err = multierror.Append(err, fmt.Errorf("Invalid URL: %+v", parseErr))
+	} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		err = multierror.Append(err, errors.New("URL must start with http:// or https://"))
+	}
+
+	if n.Format != "" && !isValidNotificationFormat(n.Format) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("Format must be in %+v", allNotificationFormats),
+		)
+	}
+
+	for _, event := range n.Events {
+		if !isValidNotificationEvent(event) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Event %s must be in %+v", event, allNotificationEvents),
+			)
+		}
+	}
+
+	return err
+}
+
+// GetFormat returns the payload format of the webhook, defaulting to NotificationFormatJSON.
+func (n NotificationConfig) GetFormat() NotificationFormat {
+	if n.Format == "" {
+		return NotificationFormatJSON
+	}
+	return n.Format
+}
+
+// NotifiesFor returns whether the webhook should be notified for the argument event.
+func (n NotificationConfig) NotifiesFor(event NotificationEvent, dryRun bool) bool {
+	if dryRun && !n.DryRuns {
+		return false
+	}
+	if len(n.Events) == 0 {
+		return true
+	}
+	for _, notificationEvent := range n.Events {
+		if notificationEvent == event {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidNotificationFormat(format NotificationFormat) bool {
+	for _, validFormat := range allNotificationFormats {
+		if format == validFormat {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidNotificationEvent(event NotificationEvent) bool {
+	for _, validEvent := range allNotificationEvents {
+		if event == validEvent {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+var (
+	// producerTopicOperations are the operations on a topic that producers need to write to it.
+	producerTopicOperations = []string{"write", "describe"}
+
+	// consumerTopicOperations and consumerGroupOperations are the operations on a topic and
+	// consumer group, respectively, that the members of the group need to consume the topic.
+	consumerTopicOperations = []string{"read", "describe"}
+	consumerGroupOperations = []string{"read", "describe"}
+)
+
+// DeclaresOwnership returns whether the topic config declares any producer or consumer principals
+// that ACLs can be generated from.
+func (t TopicConfig) DeclaresOwnership() bool {
+	return len(t.Meta.Producers) > 0 || len(t.Meta.ConsumerPrincipals) > 0
+}
+
+// ownershipACLs returns the topic ACLs that the producers and consumer principals declared in
+// the topic config need, i.e. write and describe for producers and read and describe for
+// consumers. The principals haven't been resolved.
+func (t TopicConfig) ownershipACLs() []TopicACL {
+	acls := []TopicACL{}
+
+	for _, producer := range t.Meta.Producers {
+		acls = append(
+			acls,
+			TopicACL{
+				Principal:  producer,
+				Operations: producerTopicOperations,
+			},
+		)
+	}
+
+	for _, group := range sortedKeys(t.Meta.ConsumerPrincipals) {
+		acls = append(
+			acls,
+			TopicACL{
+				Principal:  t.Meta.ConsumerPrincipals[group],
+				Operations: consumerTopicOperations,
+			},
+		)
+	}
+
+	return acls
+}
+
+// OwnershipACLConfig generates an ACL config for the consumer groups declared in the
+// consumerPrincipals of the argument topic configs, which allows each group's principal to read
+// and describe the group. Groups that are declared in more than one topic config are merged, and
+// the resources are sorted by group ID. The principals haven't been resolved, so ResolveACLs
+// should be run on the result before it's applied.
+func OwnershipACLConfig(clusterConfig ClusterConfig, topicConfigs []TopicConfig) ACLConfig {
+	groupPrincipals := map[string]map[string]struct{}{}
+
+	for _, topicConfig := range topicConfigs {
+		for group, principal := range topicConfig.Meta.ConsumerPrincipals {
+			if _, ok := groupPrincipals[group]; !ok {
+				groupPrincipals[group] = map[string]struct{}{}
+			}
+			groupPrincipals[group][principal] = struct{}{}
+		}
+	}
+
+	groups := []string{}
+	for group := range groupPrincipals {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	resources := []ACLResourceConfig{}
+
+	for _, group := range groups {
+		principals := []string{}
+		for principal := range groupPrincipals[group] {
+			principals = append(principals, principal)
+		}
+		sort.Strings(principals)
+
+		entries := []ACLEntryConfig{}
+		for _, principal := range principals {
+			entries = append(
+				entries,
+				ACLEntryConfig{
+					Principal:  principal,
+					Operations: consumerGroupOperations,
+				},
+			)
+		}
+
+		resources = append(
+			resources,
+			ACLResourceConfig{
+				Type: "group",
+				Name: group,
+				ACLs: entries,
+			},
+		)
+	}
+
+	return ACLConfig{
+		Meta: ACLMeta{
+			Name:        fmt.Sprintf("%s-ownership", clusterConfig.Meta.Name),
+			Cluster:     clusterConfig.Meta.Name,
+			Region:      clusterConfig.Meta.Region,
+			Environment: clusterConfig.Meta.Environment,
+			Description: "Consumer group ACLs generated from the consumerPrincipals in topic configs.",
+		},
+		Spec: ACLConfigSpec{
+			Resources: resources,
+		},
+	}
+}
+
+// MergeOwnershipACLs moves the entries of the group resources in the argument ownership config
+// into the matching literal group resources of the argument ACL configs for the same cluster.
+// Since applying an ACL config deletes the ACLs on each of its resources that aren't in the
+// config, this keeps the ACLs of groups that are declared in both places from being deleted by
+// whichever config is applied second. The merged resources are removed from the ownership config,
+// and the IDs of the merged groups are returned in sorted order.
+func MergeOwnershipACLs(aclConfigs []ACLConfig, ownershipConfig *ACLConfig) []string {
+	merged := map[string]struct{}{}
+
+	for c := range aclConfigs {
+		if aclConfigs[c].Meta.Cluster != ownershipConfig.Meta.Cluster {
+			continue
+		}
+
+		resources := aclConfigs[c].Spec.Resources
+		for r := range resources {
+			if resources[r].Type != "group" ||
+				(resources[r].PatternType != "" && resources[r].PatternType != "literal") {
+				continue
+			}
+
+			for _, ownershipResource := range ownershipConfig.Spec.Resources {
+				if ownershipResource.Name != resources[r].Name {
+					continue
+				}
+				resources[r].ACLs = append(
+					append([]ACLEntryConfig{}, resources[r].ACLs...),
+					ownershipResource.ACLs...,
+				)
+				merged[ownershipResource.Name] = struct{}{}
+			}
+		}
+	}
+
+	remaining := []ACLResourceConfig{}
+	for _, resource := range ownershipConfig.Spec.Resources {
+		if _, ok := merged[resource.Name]; !ok {
+			remaining = append(remaining, resource)
+		}
+	}
+	ownershipConfig.Spec.Resources = remaining
+
+	groups := []string{}
+	for group := range merged {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	return groups
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,300 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicResolveOwnershipACLs(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			Principals: map[string]string{
+				"team-payments": "User:CN=payments-prod",
+			},
+		},
+	}
+
+	topicConfig := TopicConfig{
+		Meta: TopicMeta{
+			Name:           "test-topic",
+			ConsumerGroups: []string{"payments-service", "search-indexer"},
+			ConsumerPrincipals: map[string]string{
+				"search-indexer":   "User:search",
+				"payments-service": "team-payments",
+			},
+			Producers: []string{"User:producer"},
+		},
+		Spec: TopicSpec{
+			ACLs: []TopicACL{
+				{
+					Principal:  "User:search",
+					Operations: []string{"read"},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, topicConfig.ResolveACLs(clusterConfig))
+	acls, err := topicConfig.ACLs()
+	require.NoError(t, err)
+
+	aclSpecs := []admin.ACLSpec{
+		{
+			ResourceType: "topic",
+			ResourceName: "test-topic",
+			Principals:   []string{"User:CN=payments-prod", "User:search"},
+			Operations:   []string{"read", "describe"},
+		},
+		{
+			ResourceType: "topic",
+			ResourceName: "test-topic",
+			Principals:   []string{"User:producer"},
+			Operations:   []string{"write", "describe"},
+		},
+	}
+	expACLs := []admin.ACLInfo{}
+	for _, aclSpec := range aclSpecs {
+		specACLs, err := aclSpec.ACLs()
+		require.NoError(t, err)
+		expACLs = append(expACLs, specACLs...)
+	}
+	admin.SortACLs(expACLs)
+
+	assert.Equal(t, expACLs, acls)
+
+	// Topics that don't manage their ACLs are left alone
+	topicConfig.Spec.ACLs = nil
+	require.NoError(t, topicConfig.ResolveACLs(clusterConfig))
+	assert.False(t, topicConfig.ManagesACLs())
+}
+
+func TestOwnershipACLConfig(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name:        "test-cluster",
+			Region:      "test-region",
+			Environment: "test-environment",
+		},
+		Spec: ClusterSpec{
+			Principals: map[string]string{
+				"team-payments": "User:CN=payments-prod",
+			},
+		},
+	}
+
+	topicConfigs := []TopicConfig{
+		{
+			Meta: TopicMeta{
+				Name:           "topic1",
+				ConsumerGroups: []string{"search-indexer", "payments-service"},
+				ConsumerPrincipals: map[string]string{
+					"search-indexer":   "User:search",
+					"payments-service": "team-payments",
+				},
+				Producers: []string{"User:producer"},
+			},
+		},
+		{
+			Meta: TopicMeta{
+				Name:           "topic2",
+				ConsumerGroups: []string{"search-indexer"},
+				ConsumerPrincipals: map[string]string{
+					"search-indexer": "User:search",
+				},
+			},
+		},
+		{
+			Meta: TopicMeta{
+				Name:      "topic3",
+				Producers: []string{"User:producer"},
+			},
+		},
+	}
+
+	aclConfig := OwnershipACLConfig(clusterConfig, topicConfigs)
+	assert.Equal(t, "test-cluster-ownership", aclConfig.Meta.Name)
+	assert.NoError(t, CheckACLConsistency(aclConfig, clusterConfig))
+	assert.Equal(
+		t,
+		[]ACLResourceConfig{
+			{
+				Type: "group",
+				Name: "payments-service",
+				ACLs: []ACLEntryConfig{
+					{
+						Principal:  "team-payments",
+						Operations: []string{"read", "describe"},
+					},
+				},
+			},
+			{
+				Type: "group",
+				Name: "search-indexer",
+				ACLs: []ACLEntryConfig{
+					{
+						Principal:  "User:search",
+						Operations: []string{"read", "describe"},
+					},
+				},
+			},
+		},
+		aclConfig.Spec.Resources,
+	)
+
+	require.NoError(t, aclConfig.ResolveACLs(clusterConfig))
+	assert.Equal(
+		t,
+		"User:CN=payments-prod",
+		aclConfig.Spec.Resources[0].ACLs[0].Principal,
+	)
+	assert.NoError(t, aclConfig.Validate())
+}
+
+func TestMergeOwnershipACLs(t *testing.T) {
+	ownershipConfig := ACLConfig{
+		Meta: ACLMeta{
+			Name:    "test-cluster-ownership",
+			Cluster: "test-cluster",
+		},
+		Spec: ACLConfigSpec{
+			Resources: []ACLResourceConfig{
+				{
+					Type: "group",
+					Name: "payments-service",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal:  "User:payments",
+							Operations: []string{"read", "describe"},
+						},
+					},
+				},
+				{
+					Type: "group",
+					Name: "search-indexer",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal:  "User:search",
+							Operations: []string{"read", "describe"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	aclConfigs := []ACLConfig{
+		{
+			Meta: ACLMeta{
+				Name:    "test-acls",
+				Cluster: "test-cluster",
+			},
+			Spec: ACLConfigSpec{
+				Resources: []ACLResourceConfig{
+					{
+						Type: "group",
+						Name: "payments-service",
+						ACLs: []ACLEntryConfig{
+							{
+								Principal:  "User:payments-admin",
+								Operations: []string{"delete"},
+							},
+						},
+					},
+					{
+						Type:        "group",
+						Name:        "search-indexer",
+						PatternType: "prefixed",
+						ACLs: []ACLEntryConfig{
+							{
+								Principal:  "User:search-admin",
+								Operations: []string{"describe"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Meta: ACLMeta{
+				Name:    "other-acls",
+				Cluster: "other-cluster",
+			},
+			Spec: ACLConfigSpec{
+				Resources: []ACLResourceConfig{
+					{
+						Type: "group",
+						Name: "search-indexer",
+						ACLs: []ACLEntryConfig{},
+					},
+				},
+			},
+		},
+	}
+
+	merged := MergeOwnershipACLs(aclConfigs, &ownershipConfig)
+	assert.Equal(t, []string{"payments-service"}, merged)
+
+	// The file-declared and ownership entries both end up in the file's resource
+	assert.Equal(
+		t,
+		[]ACLEntryConfig{
+			{
+				Principal:  "User:payments-admin",
+				Operations: []string{"delete"},
+			},
+			{
+				Principal:  "User:payments",
+				Operations: []string{"read", "describe"},
+			},
+		},
+		aclConfigs[0].Spec.Resources[0].ACLs,
+	)
+
+	// Prefixed resources and configs for other clusters aren't merged
+	assert.Equal(t, 1, len(aclConfigs[0].Spec.Resources[1].ACLs))
+	assert.Equal(t, 0, len(aclConfigs[1].Spec.Resources[0].ACLs))
+
+	// Only the groups that weren't merged are left in the ownership config
+	require.Equal(t, 1, len(ownershipConfig.Spec.Resources))
+	assert.Equal(t, "search-indexer", ownershipConfig.Spec.Resources[0].Name)
+}
+
+func TestTopicValidateOwnership(t *testing.T) {
+	topicConfig := TopicConfig{
+		Meta: TopicMeta{
+			Name:           "test-topic",
+			Cluster:        "test-cluster",
+			Region:         "test-region",
+			Environment:    "test-environment",
+			ConsumerGroups: []string{"payments-service"},
+			ConsumerPrincipals: map[string]string{
+				"payments-service": "User:payments",
+			},
+			Producers: []string{"User:producer"},
+		},
+		Spec: TopicSpec{
+			Partitions:        2,
+			ReplicationFactor: 3,
+			PlacementConfig: TopicPlacementConfig{
+				Strategy: PlacementStrategyAny,
+				Picker:   PickerMethodRandomized,
+			},
+		},
+	}
+	assert.NoError(t, topicConfig.Validate(-1))
+
+	topicConfig.Meta.ConsumerPrincipals["search-indexer"] = "User:search"
+	err := topicConfig.Validate(-1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'search-indexer' in consumerPrincipals must be in consumerGroups")
+
+	topicConfig.Meta.ConsumerPrincipals = map[string]string{"payments-service": ""}
+	topicConfig.Meta.Producers = []string{""}
+	err = topicConfig.Validate(-1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Principal for consumer group 'payments-service' must be set")
+	assert.Contains(t, err.Error(), "Producer principals must be set")
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// builtinSettingsProfiles are the settings profiles that ship with topicctl. Cluster configs can
+// define additional profiles or override these via the spec.settingsProfiles field.
+var builtinSettingsProfiles = map[string]TopicSettings{
+	// compacted-changelog is for changelog-style topics where only the latest value for each
+	// key needs to be retained.
+	"compacted-changelog": {
+		"cleanup.policy":            "compact",
+		"delete.retention.ms":       86400000,
+		"min.cleanable.dirty.ratio": 0.1,
+		"segment.ms":                86400000,
+	},
+	// high-throughput-events is for high-volume, append-only event streams.
+	"high-throughput-events": {
+		"cleanup.policy":   "delete",
+		"compression.type": "lz4",
+		"segment.bytes":    1073741824,
+	},
+}
+
+// SettingsProfileNames returns the sorted names of all of the settings profiles that are
+// available to topics in the argument cluster.
+func SettingsProfileNames(clusterConfig ClusterConfig) []string {
+	names := []string{}
+
+	for name := range builtinSettingsProfiles {
+		names = append(names, name)
+	}
+	for name := range clusterConfig.Spec.SettingsProfiles {
+		if _, ok := builtinSettingsProfiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// ResolveSettingsProfile expands the settings profile referenced in the topic config, if any,
+// into the topic settings. Profiles defined in the cluster config take precedence over the
+// built-in ones, and settings set explicitly in the topic config take precedence over the
+// profile values.
+func (t *TopicConfig) ResolveSettingsProfile(clusterConfig ClusterConfig) error {
+	if t.Spec.Profile == "" {
+		return nil
+	}
+
+	profile, ok := clusterConfig.Spec.SettingsProfiles[t.Spec.Profile]
+	if !ok {
+		profile, ok = builtinSettingsProfiles[t.Spec.Profile]
+	}
+	if !ok {
+		return fmt.Errorf(
+			"Unknown settings profile '%s' in topic %s; valid profiles are %+v",
+			t.Spec.Profile,
+			t.Meta.Name,
+			SettingsProfileNames(clusterConfig),
+		)
+	}
+
+	settings := profile.Copy()
+	for key, value := range t.Spec.Settings {
+		settings[key] = value
+	}
+	t.Spec.Settings = settings
+
+	return nil
+}
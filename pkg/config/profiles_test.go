@@ -0,0 +1,114 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSettingsProfile(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			SettingsProfiles: map[string]TopicSettings{
+				"custom": {
+					"cleanup.policy":    "delete",
+					"max.message.bytes": 5242880,
+				},
+				"high-throughput-events": {
+					"compression.type": "zstd",
+				},
+			},
+		},
+	}
+
+	type testCase struct {
+		description string
+		profile     string
+		settings    TopicSettings
+		expSettings TopicSettings
+		expErr      bool
+	}
+
+	testCases := []testCase{
+		{
+			description: "no profile",
+			settings: TopicSettings{
+				"cleanup.policy": "compact",
+			},
+			expSettings: TopicSettings{
+				"cleanup.policy": "compact",
+			},
+		},
+		{
+			description: "built-in profile with overrides",
+			profile:     "compacted-changelog",
+			settings: TopicSettings{
+				"segment.ms": 3600000,
+			},
+			expSettings: TopicSettings{
+				"cleanup.policy":            "compact",
+				"delete.retention.ms":       86400000,
+				"min.cleanable.dirty.ratio": 0.1,
+				"segment.ms":                3600000,
+			},
+		},
+		{
+			description: "custom profile",
+			profile:     "custom",
+			expSettings: TopicSettings{
+				"cleanup.policy":    "delete",
+				"max.message.bytes": 5242880,
+			},
+		},
+		{
+			description: "cluster profile overrides built-in one",
+			profile:     "high-throughput-events",
+			expSettings: TopicSettings{
+				"compression.type": "zstd",
+			},
+		},
+		{
+			description: "unknown profile",
+			profile:     "non-existent",
+			expErr:      true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		topicConfig := TopicConfig{
+			Meta: TopicMeta{
+				Name: "test-topic",
+			},
+			Spec: TopicSpec{
+				Profile:  testCase.profile,
+				Settings: testCase.settings,
+			},
+		}
+
+		err := topicConfig.ResolveSettingsProfile(clusterConfig)
+		if testCase.expErr {
+			assert.Error(t, err, testCase.description)
+		} else {
+			require.NoError(t, err, testCase.description)
+			assert.Equal(
+				t,
+				testCase.expSettings,
+				topicConfig.Spec.Settings,
+				testCase.description,
+			)
+		}
+	}
+
+	assert.Equal(
+		t,
+		[]string{"compacted-changelog", "custom", "high-throughput-events"},
+		SettingsProfileNames(clusterConfig),
+	)
+}
+
+func TestBuiltinSettingsProfilesValid(t *testing.T) {
+	for name, settings := range builtinSettingsProfiles {
+		assert.NoError(t, settings.Validate(), name)
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// QuotaConfig stores the desired quotas for a single user and/or client ID. Use
+// admin.QuotaEntityDefault ("<default>") as the name to set the quotas for the default user or
+// client ID.
+type QuotaConfig struct {
+	User     string             `json:"user,omitempty"`
+	ClientID string             `json:"clientID,omitempty"`
+	Values   map[string]float64 `json:"values"`
+}
+
+// Entity returns the entity that the quotas apply to.
+func (q QuotaConfig) Entity() admin.QuotaEntity {
+	return admin.QuotaEntity{
+		User:     q.User,
+		ClientID: q.ClientID,
+	}
+}
+
+// Validate evaluates whether the quota config is valid.
+func (q QuotaConfig) Validate() error {
+	var err error
+
+	if q.User == "" && q.ClientID == "" {
+		err = multierror.Append(err, errors.New("At least one of user or clientID must be set"))
+	}
+	if len(q.Values) == 0 {
+		err = multierror.Append(err, errors.New("At least one value must be set"))
+	}
+
+	keys := []string{}
+	for key := range q.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !isValidQuotaKey(key) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Quota key %s must be in %+v", key, admin.AllQuotaKeys),
+			)
+		} else if q.Values[key] <= 0 {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Value for quota key %s must be > 0", key),
+			)
+		}
+	}
+
+	return err
+}
+
+// QuotaConfigFromQuotaInfo generates a QuotaConfig from the quotas for a single entity in the
+// cluster.
+func QuotaConfigFromQuotaInfo(quotaInfo admin.QuotaInfo) QuotaConfig {
+	values := map[string]float64{}
+	for key, value := range quotaInfo.Values {
+		values[key] = value
+	}
+
+	return QuotaConfig{
+		User:     quotaInfo.Entity.User,
+		ClientID: quotaInfo.Entity.ClientID,
+		Values:   values,
+	}
+}
+
+func isValidQuotaKey(key string) bool {
+	for _, validKey := range admin.AllQuotaKeys {
+		if key == validKey {
+			return true
+		}
+	}
+	return false
+}
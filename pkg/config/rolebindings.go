@@ -0,0 +1,390 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/topicctl/pkg/rbac"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RBACProviderPlatform is the provider of clusters whose role bindings are managed by the
+	// metadata service of Confluent Platform.
+	RBACProviderPlatform = "platform"
+
+	// RBACProviderCloud is the provider of Confluent Cloud clusters.
+	RBACProviderCloud = "cloud"
+)
+
+var allRBACProviders = []string{
+	RBACProviderPlatform,
+	RBACProviderCloud,
+}
+
+var allRoleBindingResourceTypes = []string{
+	"cluster",
+	"topic",
+	"group",
+	"transactionalId",
+}
+
+var roleBindingResourceTypes = map[string]string{
+	"cluster":         rbac.ResourceTypeCluster,
+	"topic":           rbac.ResourceTypeTopic,
+	"group":           rbac.ResourceTypeGroup,
+	"transactionalId": rbac.ResourceTypeTransactionalID,
+}
+
+// RBACConfig stores how the Confluent RBAC role bindings of a cluster are managed. Role bindings
+// aren't stored in Kafka itself, so they're updated via the REST APIs of the provider instead of
+// the broker connections.
+type RBACConfig struct {
+	// Provider is either platform (Confluent Platform) or cloud (Confluent Cloud).
+	Provider string `json:"provider"`
+
+	// URL is the base URL of the Confluent Platform metadata service, e.g.
+	// https://kafka1.example.com:8090, or of the Confluent Cloud API. It's required for
+	// platform and defaults to https://api.confluent.cloud for cloud.
+	URL string `json:"url,omitempty"`
+
+	// Username is the metadata service username for platform or the Cloud API key for cloud.
+	Username string `json:"username"`
+
+	// Password is the metadata service password for platform or the Cloud API secret for cloud.
+	Password string `json:"password,omitempty"`
+
+	// PasswordFrom, if set, references the environment variable or command that the password is
+	// fetched from instead of storing it in the config. It can't be set along with Password.
+	PasswordFrom *SecretRef `json:"passwordFrom,omitempty"`
+
+	// KafkaClusterID is the ID that role bindings are scoped to, e.g. the Kafka cluster ID for
+	// platform or the lkc-... cluster ID for cloud.
+	KafkaClusterID string `json:"kafkaClusterID"`
+
+	// OrganizationID and EnvironmentID identify the cluster in Confluent Cloud. They're required
+	// for cloud and ignored for platform.
+	OrganizationID string `json:"organizationID,omitempty"`
+	EnvironmentID  string `json:"environmentID,omitempty"`
+
+	// CACertPath is the path to the CA certs for the metadata service (optional, platform only).
+	CACertPath string `json:"caCertPath,omitempty"`
+
+	// SkipVerify indicates whether verification of the metadata service certificate should be
+	// skipped (platform only).
+	SkipVerify bool `json:"skipVerify,omitempty"`
+}
+
+// Validate evaluates whether the RBAC config is valid.
+func (r RBACConfig) Validate() error {
+	var err error
+
+	switch r.Provider {
+	case RBACProviderPlatform:
+		if r.URL == "" {
+			err = multierror.Append(err, errors.New("URL must be set for platform"))
+		}
+	case RBACProviderCloud:
+		if r.OrganizationID == "" || r.EnvironmentID == "" {
+			err = multierror.Append(
+				err,
+				errors.New("OrganizationID and environmentID must be set for cloud"),
+			)
+		}
+		if r.CACertPath != "" || r.SkipVerify {
+			err = multierror.Append(
+				err,
+				errors.New("CACertPath and skipVerify can't be set for cloud"),
+			)
+		}
+	default:
+		err = multierror.Append(
+			err,
+			fmt.Errorf("Provider must be in %+v", allRBACProviders),
+		)
+	}
+
+	if r.KafkaClusterID == "" {
+		err = multierror.Append(err, errors.New("KafkaClusterID must be set"))
+	}
+	if r.Username == "" {
+		err = multierror.Append(err, errors.New("Username must be set"))
+	}
+	if r.Password == "" && r.PasswordFrom == nil {
+		err = multierror.Append(err, errors.New("One of password or passwordFrom must be set"))
+	}
+	if r.PasswordFrom != nil {
+		if r.Password != "" {
+			err = multierror.Append(
+				err,
+				errors.New("Only one of password or passwordFrom can be set"),
+			)
+		}
+		if secretErr := r.PasswordFrom.Validate(); secretErr != nil {
+			err = multierror.Append(err, fmt.Errorf("Invalid passwordFrom: %+v", secretErr))
+		}
+	}
+
+	return err
+}
+
+// NewRoleBindingClient returns a new client for the role bindings of the cluster, using the
+// settings in the rbac section of the cluster config.
+func (c ClusterConfig) NewRoleBindingClient(ctx context.Context) (rbac.Client, error) {
+	if c.Spec.RBAC == nil {
+		return nil, fmt.Errorf("Cluster config for %s doesn't have an rbac section", c.Meta.Name)
+	}
+	rbacConfig := c.Spec.RBAC
+
+	password := rbacConfig.Password
+	if rbacConfig.PasswordFrom != nil {
+		log.Debugf("Setting RBAC password from %s", rbacConfig.PasswordFrom)
+		var err error
+		password, err = rbacConfig.PasswordFrom.Resolve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Could not get RBAC password: %+v", err)
+		}
+	}
+
+	switch rbacConfig.Provider {
+	case RBACProviderPlatform:
+		return rbac.NewPlatformClient(
+			rbac.PlatformConfig{
+				URL:            rbacConfig.URL,
+				Username:       rbacConfig.Username,
+				Password:       password,
+				KafkaClusterID: rbacConfig.KafkaClusterID,
+				CACertPath:     c.absPath(rbacConfig.CACertPath),
+				SkipVerify:     rbacConfig.SkipVerify,
+			},
+		)
+	case RBACProviderCloud:
+		return rbac.NewCloudClient(
+			rbac.CloudConfig{
+				URL:            rbacConfig.URL,
+				APIKey:         rbacConfig.Username,
+				APISecret:      password,
+				OrganizationID: rbacConfig.OrganizationID,
+				EnvironmentID:  rbacConfig.EnvironmentID,
+				ClusterID:      rbacConfig.KafkaClusterID,
+			},
+		)
+	default:
+		return nil, fmt.Errorf("RBAC provider must be in %+v", allRBACProviders)
+	}
+}
+
+// RoleBindingConfig represents the desired Confluent RBAC role bindings for a set of principals
+// in a cluster.
+type RoleBindingConfig struct {
+	// APIVersion is the version of the config format. If unset, the config is assumed to be
+	// in the original (v0) format.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	Meta RoleBindingMeta       `json:"meta"`
+	Spec RoleBindingConfigSpec `json:"spec"`
+}
+
+// RoleBindingMeta stores the metadata associated with a set of role bindings.
+type RoleBindingMeta struct {
+	Name        string `json:"name"`
+	Cluster     string `json:"cluster"`
+	Region      string `json:"region"`
+	Environment string `json:"environment"`
+	Description string `json:"description"`
+}
+
+// RoleBindingConfigSpec stores the principals whose role bindings are managed by a role binding
+// config.
+type RoleBindingConfigSpec struct {
+	Principals []RoleBindingPrincipalConfig `json:"principals"`
+}
+
+// RoleBindingPrincipalConfig stores the desired role bindings of a single principal. When the
+// config is applied, the principal's bindings in the cluster are reconciled against these;
+// bindings that aren't in the config are deleted.
+type RoleBindingPrincipalConfig struct {
+	// Principal is the principal, e.g. User:alice, or one of the symbolic names in the
+	// principals section of the cluster config.
+	Principal string `json:"principal"`
+
+	Bindings []RoleBindingEntryConfig `json:"bindings"`
+}
+
+// RoleBindingEntryConfig binds a single role to the principal.
+type RoleBindingEntryConfig struct {
+	// Role is the name of the role, e.g. DeveloperRead or ResourceOwner.
+	Role string `json:"role"`
+
+	// ResourceType is cluster (the default), topic, group, or transactionalId.
+	ResourceType string `json:"resourceType,omitempty"`
+
+	// Name is the resource name or prefix. It's ignored for cluster bindings.
+	Name string `json:"name,omitempty"`
+
+	// PatternType is either literal (the default) or prefixed. It's ignored for cluster
+	// bindings.
+	PatternType string `json:"patternType,omitempty"`
+}
+
+// ResolvePrincipals replaces the symbolic principal names in the config, if any, with the
+// principals that they map to in the argument cluster config.
+func (r *RoleBindingConfig) ResolvePrincipals(clusterConfig ClusterConfig) error {
+	principals := make([]RoleBindingPrincipalConfig, len(r.Spec.Principals))
+	copy(principals, r.Spec.Principals)
+
+	for p, principalConfig := range principals {
+		principal, err := resolvePrincipal(clusterConfig, principalConfig.Principal)
+		if err != nil {
+			return fmt.Errorf("Invalid role bindings in config %s: %+v", r.Meta.Name, err)
+		}
+		principals[p].Principal = principal
+	}
+
+	r.Spec.Principals = principals
+	return nil
+}
+
+// PrincipalNames returns the names of the principals in the config, in config order.
+func (r RoleBindingConfig) PrincipalNames() []string {
+	names := []string{}
+	for _, principal := range r.Spec.Principals {
+		names = append(names, principal.Principal)
+	}
+	return names
+}
+
+// RoleBindings returns the desired role bindings in the config, sorted by principal, role, and
+// resource.
+func (r RoleBindingConfig) RoleBindings() ([]rbac.RoleBinding, error) {
+	bindings := []rbac.RoleBinding{}
+
+	for _, principal := range r.Spec.Principals {
+		for _, entry := range principal.Bindings {
+			binding, err := entry.resolve(principal.Principal)
+			if err != nil {
+				return nil, err
+			}
+			bindings = append(bindings, binding)
+		}
+	}
+
+	rbac.SortRoleBindings(bindings)
+	return bindings, nil
+}
+
+// Validate evaluates whether the role binding config is valid.
+func (r RoleBindingConfig) Validate() error {
+	var err error
+
+	if r.APIVersion != "" && !isValidAPIVersion(r.APIVersion) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("APIVersion must be in %+v", allAPIVersions),
+		)
+	}
+	if r.Meta.Name == "" {
+		err = multierror.Append(err, errors.New("Name must be set"))
+	}
+	if r.Meta.Cluster == "" {
+		err = multierror.Append(err, errors.New("Cluster must be set"))
+	}
+	if r.Meta.Region == "" {
+		err = multierror.Append(err, errors.New("Region must be set"))
+	}
+	if r.Meta.Environment == "" {
+		err = multierror.Append(err, errors.New("Environment must be set"))
+	}
+	if len(r.Spec.Principals) == 0 {
+		err = multierror.Append(err, errors.New("At least one principal must be set"))
+	}
+
+	seenPrincipals := map[string]struct{}{}
+	seenBindings := map[rbac.RoleBinding]struct{}{}
+
+	for _, principal := range r.Spec.Principals {
+		if principal.Principal == "" {
+			err = multierror.Append(err, errors.New("Principal must be set"))
+			continue
+		}
+		if _, ok := seenPrincipals[principal.Principal]; ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Principal %s is declared more than once", principal.Principal),
+			)
+		}
+		seenPrincipals[principal.Principal] = struct{}{}
+
+		for _, entry := range principal.Bindings {
+			binding, bindingErr := entry.resolve(principal.Principal)
+			if bindingErr != nil {
+				err = multierror.Append(err, bindingErr)
+				continue
+			}
+			if _, ok := seenBindings[binding]; ok {
+				err = multierror.Append(
+					err,
+					fmt.Errorf("Role binding %s is declared more than once", binding),
+				)
+			}
+			seenBindings[binding] = struct{}{}
+		}
+	}
+
+	return err
+}
+
+func (e RoleBindingEntryConfig) resolve(principal string) (rbac.RoleBinding, error) {
+	if e.Role == "" {
+		return rbac.RoleBinding{}, fmt.Errorf("Role must be set for bindings of %s", principal)
+	}
+
+	resourceTypeName := e.ResourceType
+	if resourceTypeName == "" {
+		resourceTypeName = "cluster"
+	}
+	resourceType, ok := roleBindingResourceTypes[resourceTypeName]
+	if !ok {
+		return rbac.RoleBinding{}, fmt.Errorf(
+			"Resource type for %s binding of %s must be in %+v",
+			e.Role,
+			principal,
+			allRoleBindingResourceTypes,
+		)
+	}
+
+	binding := rbac.RoleBinding{
+		Principal:    principal,
+		Role:         e.Role,
+		ResourceType: resourceType,
+	}
+	if binding.IsClusterBinding() {
+		return binding, nil
+	}
+
+	if e.Name == "" {
+		return rbac.RoleBinding{}, fmt.Errorf(
+			"Resource name must be set for %s binding of %s",
+			e.Role,
+			principal,
+		)
+	}
+	binding.Name = e.Name
+
+	switch e.PatternType {
+	case "", "literal":
+		binding.PatternType = rbac.PatternTypeLiteral
+	case "prefixed":
+		binding.PatternType = rbac.PatternTypePrefixed
+	default:
+		return rbac.RoleBinding{}, fmt.Errorf(
+			"Pattern type for %s binding of %s must be literal or prefixed",
+			e.Role,
+			principal,
+		)
+	}
+
+	return binding, nil
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleBindingConfigValidate(t *testing.T) {
+	type testCase struct {
+		description string
+		principals  []RoleBindingPrincipalConfig
+		expError    bool
+	}
+
+	testCases := []testCase{
+		{
+			description: "all valid",
+			principals: []RoleBindingPrincipalConfig{
+				{
+					Principal: "User:alice",
+					Bindings: []RoleBindingEntryConfig{
+						{
+							Role: "ClusterAdmin",
+						},
+						{
+							Role:         "ResourceOwner",
+							ResourceType: "topic",
+							Name:         "alice-",
+							PatternType:  "prefixed",
+						},
+					},
+				},
+				{
+					Principal: "team-payments",
+					Bindings: []RoleBindingEntryConfig{
+						{
+							Role:         "DeveloperRead",
+							ResourceType: "transactionalId",
+							Name:         "payments",
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "no principals",
+			principals:  []RoleBindingPrincipalConfig{},
+			expError:    true,
+		},
+		{
+			description: "duplicate principal",
+			principals: []RoleBindingPrincipalConfig{
+				{
+					Principal: "User:alice",
+				},
+				{
+					Principal: "User:alice",
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "missing role",
+			principals: []RoleBindingPrincipalConfig{
+				{
+					Principal: "User:alice",
+					Bindings: []RoleBindingEntryConfig{
+						{
+							ResourceType: "topic",
+							Name:         "orders",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "invalid resource type",
+			principals: []RoleBindingPrincipalConfig{
+				{
+					Principal: "User:alice",
+					Bindings: []RoleBindingEntryConfig{
+						{
+							Role:         "DeveloperRead",
+							ResourceType: "subject",
+							Name:         "orders-value",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "missing resource name",
+			principals: []RoleBindingPrincipalConfig{
+				{
+					Principal: "User:alice",
+					Bindings: []RoleBindingEntryConfig{
+						{
+							Role:         "DeveloperRead",
+							ResourceType: "topic",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "invalid pattern type",
+			principals: []RoleBindingPrincipalConfig{
+				{
+					Principal: "User:alice",
+					Bindings: []RoleBindingEntryConfig{
+						{
+							Role:         "DeveloperRead",
+							ResourceType: "topic",
+							Name:         "orders",
+							PatternType:  "match",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "duplicate binding",
+			principals: []RoleBindingPrincipalConfig{
+				{
+					Principal: "User:alice",
+					Bindings: []RoleBindingEntryConfig{
+						{
+							Role:         "DeveloperRead",
+							ResourceType: "topic",
+							Name:         "orders",
+						},
+						{
+							Role:         "DeveloperRead",
+							ResourceType: "topic",
+							Name:         "orders",
+							PatternType:  "literal",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		roleBindingConfig := RoleBindingConfig{
+			Meta: RoleBindingMeta{
+				Name:        "test-role-bindings",
+				Cluster:     "test-cluster",
+				Region:      "test-region",
+				Environment: "test-environment",
+			},
+			Spec: RoleBindingConfigSpec{
+				Principals: testCase.principals,
+			},
+		}
+
+		err := roleBindingConfig.Validate()
+		if testCase.expError {
+			assert.Error(t, err, testCase.description)
+		} else {
+			assert.NoError(t, err, testCase.description)
+		}
+	}
+}
+
+func TestRoleBindingConfigRoleBindings(t *testing.T) {
+	roleBindingConfig := RoleBindingConfig{
+		Spec: RoleBindingConfigSpec{
+			Principals: []RoleBindingPrincipalConfig{
+				{
+					Principal: "team-payments",
+					Bindings: []RoleBindingEntryConfig{
+						{
+							Role:         "DeveloperWrite",
+							ResourceType: "topic",
+							Name:         "payments-",
+							PatternType:  "prefixed",
+						},
+						{
+							Role: "Operator",
+						},
+					},
+				},
+			},
+		},
+	}
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			Principals: map[string]string{
+				"team-payments": "User:payments-prod",
+			},
+		},
+	}
+
+	require.NoError(t, roleBindingConfig.ResolvePrincipals(clusterConfig))
+	assert.Equal(t, []string{"User:payments-prod"}, roleBindingConfig.PrincipalNames())
+
+	bindings, err := roleBindingConfig.RoleBindings()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]rbac.RoleBinding{
+			{
+				Principal:    "User:payments-prod",
+				Role:         "DeveloperWrite",
+				ResourceType: rbac.ResourceTypeTopic,
+				Name:         "payments-",
+				PatternType:  rbac.PatternTypePrefixed,
+			},
+			{
+				Principal:    "User:payments-prod",
+				Role:         "Operator",
+				ResourceType: rbac.ResourceTypeCluster,
+			},
+		},
+		bindings,
+	)
+
+	roleBindingConfig.Spec.Principals[0].Principal = "team-unknown"
+	assert.Error(t, roleBindingConfig.ResolvePrincipals(clusterConfig))
+}
+
+func TestRBACConfigValidate(t *testing.T) {
+	platformConfig := RBACConfig{
+		Provider:       RBACProviderPlatform,
+		URL:            "https://mds.example.com:8090",
+		Username:       "topicctl",
+		PasswordFrom:   &SecretRef{FromEnv: "MDS_PASSWORD"},
+		KafkaClusterID: "abc-123",
+	}
+	assert.NoError(t, platformConfig.Validate())
+
+	platformConfig.URL = ""
+	assert.Error(t, platformConfig.Validate())
+
+	cloudConfig := RBACConfig{
+		Provider:       RBACProviderCloud,
+		Username:       "API_KEY",
+		Password:       "secret",
+		KafkaClusterID: "lkc-abc123",
+		OrganizationID: "org-1",
+		EnvironmentID:  "env-1",
+	}
+	assert.NoError(t, cloudConfig.Validate())
+
+	cloudConfig.EnvironmentID = ""
+	assert.Error(t, cloudConfig.Validate())
+
+	cloudConfig.EnvironmentID = "env-1"
+	cloudConfig.PasswordFrom = &SecretRef{FromEnv: "CLOUD_API_SECRET"}
+	assert.Error(t, cloudConfig.Validate())
+
+	assert.Error(t, RBACConfig{Provider: "other"}.Validate())
+}
+
+func TestClusterNewRoleBindingClient(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Meta: ClusterMeta{
+			Name: "test-cluster",
+		},
+	}
+	_, err := clusterConfig.NewRoleBindingClient(context.Background())
+	assert.Error(t, err)
+
+	t.Setenv("TOPICCTL_TEST_MDS_PASSWORD", "")
+	clusterConfig.Spec.RBAC = &RBACConfig{
+		Provider:       RBACProviderPlatform,
+		URL:            "https://mds.example.com:8090",
+		Username:       "topicctl",
+		PasswordFrom:   &SecretRef{FromEnv: "TOPICCTL_TEST_MDS_PASSWORD"},
+		KafkaClusterID: "abc-123",
+	}
+	_, err = clusterConfig.NewRoleBindingClient(context.Background())
+	assert.Error(t, err)
+
+	t.Setenv("TOPICCTL_TEST_MDS_PASSWORD", "password")
+	client, err := clusterConfig.NewRoleBindingClient(context.Background())
+	require.NoError(t, err)
+	assert.IsType(t, &rbac.PlatformClient{}, client)
+}
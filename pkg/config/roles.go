@@ -0,0 +1,287 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+)
+
+// ACLRole is a named bundle of ACL operations, keyed by the resource type (topic, group,
+// transactionalId, or cluster) that they apply to. Topic and ACL configs can reference a role
+// instead of listing the operations for each principal; the operations for the type of the
+// resource that the role is granted on are then filled in.
+type ACLRole map[string][]string
+
+var allACLRoleResourceTypes = []string{
+	"cluster",
+	"group",
+	"topic",
+	"transactionalId",
+}
+
+// builtinACLRoles are the ACL roles that ship with topicctl. Cluster configs can define
+// additional roles or override these via the spec.aclRoles field.
+var builtinACLRoles = map[string]ACLRole{
+	// producer can write to topics, including transactionally and idempotently.
+	"producer": {
+		"cluster":         {"idempotentWrite"},
+		"topic":           {"write", "describe"},
+		"transactionalId": {"write", "describe"},
+	},
+	// consumer can read from topics and commit offsets in consumer groups.
+	"consumer": {
+		"group": {"read"},
+		"topic": {"read", "describe"},
+	},
+	// admin can run any operation.
+	"admin": {
+		"cluster":         {"all"},
+		"group":           {"all"},
+		"topic":           {"all"},
+		"transactionalId": {"all"},
+	},
+}
+
+// Validate evaluates whether the role is valid.
+func (r ACLRole) Validate() error {
+	var err error
+
+	resourceTypes := []string{}
+	for resourceType := range r {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	for _, resourceType := range resourceTypes {
+		if !isValidACLRoleResourceType(resourceType) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Resource type '%s' must be in %+v",
+					resourceType,
+					allACLRoleResourceTypes,
+				),
+			)
+			continue
+		}
+		if len(r[resourceType]) == 0 {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("At least one operation must be set for %s resources", resourceType),
+			)
+		}
+		for _, operationStr := range r[resourceType] {
+			var operation kafka.ACLOperationType
+			if opErr := operation.UnmarshalText([]byte(operationStr)); opErr != nil ||
+				operation == kafka.ACLOperationTypeAny {
+				err = multierror.Append(
+					err,
+					fmt.Errorf(
+						"Invalid operation '%s' for %s resources",
+						operationStr,
+						resourceType,
+					),
+				)
+			}
+		}
+	}
+
+	return err
+}
+
+// ACLRoleNames returns the sorted names of all of the ACL roles that are available to topic and
+// ACL configs in the argument cluster.
+func ACLRoleNames(clusterConfig ClusterConfig) []string {
+	names := []string{}
+
+	for name := range builtinACLRoles {
+		names = append(names, name)
+	}
+	for name := range clusterConfig.Spec.ACLRoles {
+		if _, ok := builtinACLRoles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// roleOperations returns the operations that the argument role grants on resources of the
+// argument type. Roles defined in the cluster config take precedence over the built-in ones.
+func roleOperations(
+	clusterConfig ClusterConfig,
+	roleName string,
+	resourceType string,
+) ([]string, error) {
+	role, ok := clusterConfig.Spec.ACLRoles[roleName]
+	if !ok {
+		role, ok = builtinACLRoles[roleName]
+	}
+	if !ok {
+		return nil, fmt.Errorf(
+			"Unknown ACL role '%s'; valid roles are %+v",
+			roleName,
+			ACLRoleNames(clusterConfig),
+		)
+	}
+
+	operations, ok := role[resourceType]
+	if !ok {
+		return nil, fmt.Errorf(
+			"ACL role '%s' doesn't grant any operations on %s resources",
+			roleName,
+			resourceType,
+		)
+	}
+	return operations, nil
+}
+
+// mergeOperations returns the argument operations followed by the role operations that aren't
+// already in them.
+func mergeOperations(operations []string, roleOperations []string) []string {
+	merged := append([]string{}, operations...)
+	seen := map[string]struct{}{}
+	for _, operation := range operations {
+		seen[operation] = struct{}{}
+	}
+
+	for _, operation := range roleOperations {
+		if _, ok := seen[operation]; !ok {
+			merged = append(merged, operation)
+			seen[operation] = struct{}{}
+		}
+	}
+	return merged
+}
+
+// resolvePrincipal returns the principal that the argument name maps to in the principals
+// section of the cluster config. Principals that are already in [type]:[name] format are
+// returned as-is.
+func resolvePrincipal(clusterConfig ClusterConfig, name string) (string, error) {
+	if strings.Contains(name, ":") {
+		return name, nil
+	}
+
+	principal, ok := clusterConfig.Spec.Principals[name]
+	if !ok {
+		names := []string{}
+		for principalName := range clusterConfig.Spec.Principals {
+			names = append(names, principalName)
+		}
+		sort.Strings(names)
+
+		return "", fmt.Errorf(
+			"Principal '%s' must be in the form [type]:[name] or be one of the names in the cluster config principals (%+v)",
+			name,
+			names,
+		)
+	}
+	return principal, nil
+}
+
+// ResolveACLs expands the principal names and roles referenced in the acls section of the topic
+// config, if any. Principals that aren't in [type]:[name] format are looked up in the principals
+// section of the cluster config, and roles are expanded into the operations of each ACL; any
+// operations set explicitly are kept in addition to the ones from the role. The ACLs needed by
+// the producers and consumer principals declared in the topic's metadata are also added.
+func (t *TopicConfig) ResolveACLs(clusterConfig ClusterConfig) error {
+	if t.Spec.ACLs == nil {
+		return nil
+	}
+
+	// Copy the ACLs so that the resolution doesn't leak into other copies of the config
+	acls := make([]TopicACL, len(t.Spec.ACLs))
+	copy(acls, t.Spec.ACLs)
+	acls = append(acls, t.ownershipACLs()...)
+
+	for a, topicACL := range acls {
+		principal, err := resolvePrincipal(clusterConfig, topicACL.Principal)
+		if err != nil {
+			return fmt.Errorf("Invalid ACL in topic %s: %+v", t.Meta.Name, err)
+		}
+		acls[a].Principal = principal
+
+		if topicACL.Role == "" {
+			continue
+		}
+
+		operations, err := roleOperations(clusterConfig, topicACL.Role, "topic")
+		if err != nil {
+			return fmt.Errorf(
+				"Invalid ACL for principal '%s' in topic %s: %+v",
+				topicACL.Principal,
+				t.Meta.Name,
+				err,
+			)
+		}
+
+		acls[a].Operations = mergeOperations(topicACL.Operations, operations)
+		acls[a].Role = ""
+	}
+
+	t.Spec.ACLs = acls
+	return nil
+}
+
+// ResolveACLs expands the principal names and roles referenced in the ACL config, if any, in the
+// same way as TopicConfig.ResolveACLs. Roles are expanded based on the type of the resource that
+// each ACL is on.
+func (a *ACLConfig) ResolveACLs(clusterConfig ClusterConfig) error {
+	resources := make([]ACLResourceConfig, len(a.Spec.Resources))
+	copy(resources, a.Spec.Resources)
+
+	for r, resource := range resources {
+		if resource.ACLs == nil {
+			continue
+		}
+		resources[r].ACLs = make([]ACLEntryConfig, len(resource.ACLs))
+		copy(resources[r].ACLs, resource.ACLs)
+
+		for e, entry := range resource.ACLs {
+			principal, err := resolvePrincipal(clusterConfig, entry.Principal)
+			if err != nil {
+				return fmt.Errorf(
+					"Invalid ACL on %s '%s': %+v",
+					resource.Type,
+					resource.Name,
+					err,
+				)
+			}
+			resources[r].ACLs[e].Principal = principal
+
+			if entry.Role == "" {
+				continue
+			}
+
+			operations, err := roleOperations(clusterConfig, entry.Role, resource.Type)
+			if err != nil {
+				return fmt.Errorf(
+					"Invalid ACL for principal '%s' on %s '%s': %+v",
+					entry.Principal,
+					resource.Type,
+					resource.Name,
+					err,
+				)
+			}
+
+			resources[r].ACLs[e].Operations = mergeOperations(entry.Operations, operations)
+			resources[r].ACLs[e].Role = ""
+		}
+	}
+
+	a.Spec.Resources = resources
+	return nil
+}
+
+func isValidACLRoleResourceType(resourceType string) bool {
+	for _, validType := range allACLRoleResourceTypes {
+		if resourceType == validType {
+			return true
+		}
+	}
+	return false
+}
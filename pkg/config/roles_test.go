@@ -0,0 +1,298 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicResolveACLs(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			ACLRoles: map[string]ACLRole{
+				"auditor": {
+					"topic": {"describe", "describeConfigs"},
+				},
+				"consumer": {
+					"topic": {"read"},
+				},
+			},
+			Principals: map[string]string{
+				"team-payments": "User:CN=payments-prod",
+			},
+		},
+	}
+
+	type testCase struct {
+		description string
+		acls        []TopicACL
+		expACLs     []TopicACL
+		expErr      bool
+	}
+
+	testCases := []testCase{
+		{
+			description: "no acls",
+			acls:        nil,
+			expACLs:     nil,
+		},
+		{
+			description: "built-in role with extra operations",
+			acls: []TopicACL{
+				{
+					Principal:  "User:alice",
+					Role:       "producer",
+					Operations: []string{"describe", "describeConfigs"},
+				},
+				{
+					Principal:  "User:bob",
+					Operations: []string{"read"},
+				},
+			},
+			expACLs: []TopicACL{
+				{
+					Principal:  "User:alice",
+					Operations: []string{"describe", "describeConfigs", "write"},
+				},
+				{
+					Principal:  "User:bob",
+					Operations: []string{"read"},
+				},
+			},
+		},
+		{
+			description: "custom role",
+			acls: []TopicACL{
+				{
+					Principal: "User:alice",
+					Role:      "auditor",
+					Host:      "10.0.0.1",
+				},
+			},
+			expACLs: []TopicACL{
+				{
+					Principal:  "User:alice",
+					Operations: []string{"describe", "describeConfigs"},
+					Host:       "10.0.0.1",
+				},
+			},
+		},
+		{
+			description: "cluster role overrides built-in one",
+			acls: []TopicACL{
+				{
+					Principal: "User:alice",
+					Role:      "consumer",
+				},
+			},
+			expACLs: []TopicACL{
+				{
+					Principal:  "User:alice",
+					Operations: []string{"read"},
+				},
+			},
+		},
+		{
+			description: "mapped principal",
+			acls: []TopicACL{
+				{
+					Principal: "team-payments",
+					Role:      "producer",
+				},
+				{
+					Principal:  "User:team-payments",
+					Operations: []string{"read"},
+				},
+			},
+			expACLs: []TopicACL{
+				{
+					Principal:  "User:CN=payments-prod",
+					Operations: []string{"write", "describe"},
+				},
+				{
+					Principal:  "User:team-payments",
+					Operations: []string{"read"},
+				},
+			},
+		},
+		{
+			description: "unknown principal name",
+			acls: []TopicACL{
+				{
+					Principal:  "team-unknown",
+					Operations: []string{"read"},
+				},
+			},
+			expErr: true,
+		},
+		{
+			description: "unknown role",
+			acls: []TopicACL{
+				{
+					Principal: "User:alice",
+					Role:      "non-existent",
+				},
+			},
+			expErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		topicConfig := TopicConfig{
+			Meta: TopicMeta{
+				Name: "test-topic",
+			},
+			Spec: TopicSpec{
+				ACLs: testCase.acls,
+			},
+		}
+
+		err := topicConfig.ResolveACLs(clusterConfig)
+		if testCase.expErr {
+			assert.Error(t, err, testCase.description)
+		} else {
+			require.NoError(t, err, testCase.description)
+			assert.Equal(t, testCase.expACLs, topicConfig.Spec.ACLs, testCase.description)
+		}
+	}
+
+	// Unresolved roles can't be converted into ACLs
+	topicConfig := TopicConfig{
+		Meta: TopicMeta{
+			Name: "test-topic",
+		},
+		Spec: TopicSpec{
+			ACLs: []TopicACL{
+				{
+					Principal: "User:alice",
+					Role:      "consumer",
+				},
+			},
+		},
+	}
+	resolvedConfig := topicConfig
+	require.NoError(t, resolvedConfig.ResolveACLs(clusterConfig))
+	_, err := resolvedConfig.ACLs()
+	assert.NoError(t, err)
+	_, err = topicConfig.ACLs()
+	assert.Error(t, err)
+
+	assert.Equal(
+		t,
+		[]string{"admin", "auditor", "consumer", "producer"},
+		ACLRoleNames(clusterConfig),
+	)
+}
+
+func TestACLConfigResolveACLs(t *testing.T) {
+	aclConfig := ACLConfig{
+		Spec: ACLConfigSpec{
+			Resources: []ACLResourceConfig{
+				{
+					Type: "group",
+					Name: "test-group",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal: "team-alice",
+							Role:      "consumer",
+						},
+					},
+				},
+				{
+					Type: "transactionalId",
+					Name: "test-txn",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal: "User:bob",
+							Role:      "producer",
+						},
+					},
+				},
+				{
+					Type: "cluster",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal:  "User:bob",
+							Role:       "producer",
+							Permission: "deny",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(
+		t,
+		aclConfig.ResolveACLs(
+			ClusterConfig{
+				Spec: ClusterSpec{
+					Principals: map[string]string{
+						"team-alice": "User:alice",
+					},
+				},
+			},
+		),
+	)
+	assert.Equal(
+		t,
+		[]ACLEntryConfig{
+			{
+				Principal:  "User:alice",
+				Operations: []string{"read"},
+			},
+		},
+		aclConfig.Spec.Resources[0].ACLs,
+	)
+	assert.Equal(
+		t,
+		[]ACLEntryConfig{
+			{
+				Principal:  "User:bob",
+				Operations: []string{"write", "describe"},
+			},
+		},
+		aclConfig.Spec.Resources[1].ACLs,
+	)
+	assert.Equal(
+		t,
+		[]ACLEntryConfig{
+			{
+				Principal:  "User:bob",
+				Operations: []string{"idempotentWrite"},
+				Permission: "deny",
+			},
+		},
+		aclConfig.Spec.Resources[2].ACLs,
+	)
+
+	// The consumer role doesn't grant anything on transactional IDs
+	invalidConfig := ACLConfig{
+		Spec: ACLConfigSpec{
+			Resources: []ACLResourceConfig{
+				{
+					Type: "transactionalId",
+					Name: "test-txn",
+					ACLs: []ACLEntryConfig{
+						{
+							Principal: "User:alice",
+							Role:      "consumer",
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Error(t, invalidConfig.ResolveACLs(ClusterConfig{}))
+}
+
+func TestACLRoleValidate(t *testing.T) {
+	for name, role := range builtinACLRoles {
+		assert.NoError(t, role.Validate(), name)
+	}
+
+	assert.Error(t, ACLRole{"consumerGroup": {"read"}}.Validate())
+	assert.Error(t, ACLRole{"topic": {}}.Validate())
+	assert.Error(t, ACLRole{"topic": {"read", "explode"}}.Validate())
+}
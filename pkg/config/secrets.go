@@ -0,0 +1,116 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/segmentio/topicctl/pkg/secrets"
+)
+
+// SecretRef references a secret, e.g. a password, that's stored outside of the config. Exactly
+// one of the fields must be set. Secrets are only resolved when they're needed, so configs can
+// be loaded, validated, and diffed without access to them.
+type SecretRef struct {
+	// FromEnv is the name of an environment variable that contains the secret.
+	FromEnv string `json:"fromEnv,omitempty"`
+
+	// FromCommand is a command, e.g. a vault or aws secretsmanager invocation, that prints the
+	// secret to stdout. The first element is the executable and the remaining ones are its
+	// arguments; no shell expansion is done. Trailing newlines in the output are trimmed.
+	FromCommand []string `json:"fromCommand,omitempty"`
+
+	// FromURI is the URI of a secret in an external store, e.g.
+	// vault://secret/data/kafka/admin#password, aws-sm://kafka/admin#password, or
+	// ssm:///kafka/admin/password. Secrets are fetched directly from the store, using the
+	// credentials in the environment, and are cached for a few minutes.
+	FromURI string `json:"fromURI,omitempty"`
+}
+
+// Validate evaluates whether the secret reference is valid.
+func (s SecretRef) Validate() error {
+	var numSet int
+	if s.FromEnv != "" {
+		numSet++
+	}
+	if len(s.FromCommand) > 0 {
+		numSet++
+	}
+	if s.FromURI != "" {
+		numSet++
+	}
+
+	if numSet == 0 {
+		return errors.New("One of fromEnv, fromCommand, or fromURI must be set")
+	}
+	if numSet > 1 {
+		return errors.New("Only one of fromEnv, fromCommand, or fromURI can be set")
+	}
+	if len(s.FromCommand) > 0 && s.FromCommand[0] == "" {
+		return errors.New("The fromCommand executable must be set")
+	}
+	if s.FromURI != "" {
+		if _, err := secrets.ParseURI(s.FromURI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns a description of where the secret comes from; it never includes the secret
+// itself.
+func (s SecretRef) String() string {
+	if s.FromEnv != "" {
+		return fmt.Sprintf("env %s", s.FromEnv)
+	} else if len(s.FromCommand) > 0 {
+		return fmt.Sprintf("command %s", s.FromCommand[0])
+	} else if s.FromURI != "" {
+		return fmt.Sprintf("secret %s", s.FromURI)
+	}
+	return "unset"
+}
+
+// Resolve gets the value of the secret. An error is returned if the secret is empty.
+func (s SecretRef) Resolve(ctx context.Context) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	var value string
+
+	if s.FromEnv != "" {
+		value = os.Getenv(s.FromEnv)
+	} else if s.FromURI != "" {
+		var err error
+		value, err = secrets.Resolve(ctx, s.FromURI)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		cmd := exec.CommandContext(ctx, s.FromCommand[0], s.FromCommand[1:]...)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf(
+				"Error getting secret from %s: %+v (%s)",
+				s,
+				err,
+				strings.TrimSpace(stderr.String()),
+			)
+		}
+		value = strings.TrimRight(stdout.String(), "\r\n")
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("Secret from %s is empty", s)
+	}
+	return value, nil
+}
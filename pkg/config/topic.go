@@ -3,6 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/hashicorp/go-multierror"
@@ -23,6 +27,16 @@ const (
 	// of the non-leader replicas.
 	PlacementStrategyBalancedLeaders PlacementStrategy = "balanced-leaders"
 
+	// PlacementStrategyBalancedSize allows any partition placement, like PlacementStrategyAny,
+	// but rebalances move replicas so that the total on-disk size of the replicas on each broker
+	// is as even as possible instead of evening out the replica counts.
+	PlacementStrategyBalancedSize PlacementStrategy = "balanced-size"
+
+	// PlacementStrategyBalancedLeaderLoad is a strategy in which the replicas of each partition
+	// are ordered so that the preferred leaders of all of the managed topics in the cluster are
+	// evenly spread across the brokers and racks. The replica sets themselves can be anything.
+	PlacementStrategyBalancedLeaderLoad PlacementStrategy = "balanced-leader-load"
+
 	// PlacementStrategyInRack is a strategy in which the leaders are balanced
 	// and the replicas for each partition are in the same rack as the leader.
 	PlacementStrategyInRack PlacementStrategy = "in-rack"
@@ -39,15 +53,28 @@ const (
 	// are chosen from the rack in a static list, but the specific replicas within each partition
 	// aren't specified.
 	PlacementStrategyStaticInRack PlacementStrategy = "static-in-rack"
+
+	// PlacementStrategyStaticRackCounts is a strategy in which each partition has a fixed,
+	// configured number of replicas in each rack, but the specific replicas within each rack
+	// aren't specified.
+	PlacementStrategyStaticRackCounts PlacementStrategy = "static-rack-counts"
+
+	// PlacementStrategyCustom is a strategy in which the placement is chosen by a custom assigner
+	// plugin that's compiled into the binary and selected by name via the assigner field.
+	PlacementStrategyCustom PlacementStrategy = "custom"
 )
 
 var allPlacementStrategies = []PlacementStrategy{
 	PlacementStrategyAny,
 	PlacementStrategyBalancedLeaders,
+	PlacementStrategyBalancedSize,
+	PlacementStrategyBalancedLeaderLoad,
 	PlacementStrategyInRack,
 	PlacementStrategyCrossRack,
 	PlacementStrategyStatic,
 	PlacementStrategyStaticInRack,
+	PlacementStrategyStaticRackCounts,
+	PlacementStrategyCustom,
 }
 
 // PickerMethod is a string type that stores a picker method for breaking ties when choosing
@@ -75,8 +102,48 @@ var allPickerMethods = []PickerMethod{
 	PickerMethodRandomized,
 }
 
+// OffsetBaselinePosition is a string type that stores where in a topic the offsets of a
+// consumer group are initially set to.
+type OffsetBaselinePosition string
+
+const (
+	// OffsetBaselinePositionEarliest starts the group at the earliest offsets in the topic.
+	OffsetBaselinePositionEarliest OffsetBaselinePosition = "earliest"
+
+	// OffsetBaselinePositionLatest starts the group at the end of the topic.
+	OffsetBaselinePositionLatest OffsetBaselinePosition = "latest"
+
+	// OffsetBaselinePositionTimestamp starts the group at the first offsets at or after the
+	// baseline's timestamp.
+	OffsetBaselinePositionTimestamp OffsetBaselinePosition = "timestamp"
+)
+
+var allOffsetBaselinePositions = []OffsetBaselinePosition{
+	OffsetBaselinePositionEarliest,
+	OffsetBaselinePositionLatest,
+	OffsetBaselinePositionTimestamp,
+}
+
+// maxConsumerGroupLength is the maximum length of a consumer group ID in a topic config. Kafka
+// itself doesn't limit this, but we use the same limit as for topic names to catch typos.
+const maxConsumerGroupLength = 249
+
+var consumerGroupRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// labelKeyRegexp and labelValueRegexp restrict topic labels to characters that can be used in
+// label selectors without escaping.
+var labelKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9._/-]+$`)
+var labelValueRegexp = regexp.MustCompile(`^[a-zA-Z0-9._/-]*$`)
+
+// deprecationDateFormat is the format used for the removeAfter date in topic deprecations.
+const deprecationDateFormat = "2006-01-02"
+
 // TopicConfig represents the desired configuration of a topic.
 type TopicConfig struct {
+	// APIVersion is the version of the config format. If unset, the config is assumed to be
+	// in the original (v0) format.
+	APIVersion string `json:"apiVersion,omitempty"`
+
 	Meta TopicMeta `json:"meta"`
 	Spec TopicSpec `json:"spec"`
 }
@@ -93,6 +160,132 @@ type TopicMeta struct {
 	// Consumers is a list of consumers who are expected to consume from this
 	// topic.
 	Consumers []string `json:"consumers,omitempty"`
+
+	// ConsumerGroups is a list of the IDs of the consumer groups that are expected to consume
+	// from this topic.
+	ConsumerGroups []string `json:"consumerGroups,omitempty"`
+
+	// ConsumerPrincipals maps the IDs of consumer groups in ConsumerGroups to the principals
+	// that their members authenticate as, in [type]:[name] format or as principal names from the
+	// cluster config. These are used to generate the topic and group ACLs that the consumers need.
+	ConsumerPrincipals map[string]string `json:"consumerPrincipals,omitempty"`
+
+	// Producers are the principals that are expected to produce to this topic, in the same
+	// format as the values of ConsumerPrincipals. These are used to generate the topic ACLs that
+	// the producers need.
+	Producers []string `json:"producers,omitempty"`
+
+	// Deprecated is set if the topic is deprecated and should eventually be removed.
+	Deprecated *TopicDeprecation `json:"deprecated,omitempty"`
+
+	// Labels are arbitrary key/value pairs that can be used to select topics, e.g. in
+	// get topics.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Protected is set if changes to the topic's partitions, replication, or placement, and
+	// deleting it, should be refused unless they're explicitly allowed.
+	Protected bool `json:"protected,omitempty"`
+}
+
+// MatchesLabels returns whether the topic has all of the labels in the argument selector.
+func (m TopicMeta) MatchesLabels(selector map[string]string) bool {
+	for key, value := range selector {
+		if labelValue, ok := m.Labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseLabelSelector parses a selector in key=value[,key=value...] format, e.g.
+// "team=payments,tier=1".
+func ParseLabelSelector(selector string) (map[string]string, error) {
+	labels := map[string]string{}
+
+	for _, element := range strings.Split(selector, ",") {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+
+		subElements := strings.SplitN(element, "=", 2)
+		if len(subElements) != 2 || !labelKeyRegexp.MatchString(subElements[0]) {
+			return nil, fmt.Errorf(
+				"Label selector element '%s' must be in key=value format",
+				element,
+			)
+		}
+		labels[subElements[0]] = subElements[1]
+	}
+
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("Label selector '%s' is empty", selector)
+	}
+	return labels, nil
+}
+
+// TopicDeprecation describes why a topic is deprecated and when it can be removed.
+type TopicDeprecation struct {
+	Reason string `json:"reason"`
+
+	// RemoveAfter is the date, in YYYY-MM-DD format, after which the topic can be removed
+	// (optional).
+	RemoveAfter string `json:"removeAfter,omitempty"`
+}
+
+// RemoveAfterTime returns the parsed RemoveAfter date. The zero time is returned if it's not set.
+func (d TopicDeprecation) RemoveAfterTime() (time.Time, error) {
+	if d.RemoveAfter == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(deprecationDateFormat, d.RemoveAfter)
+}
+
+// PastRemoveAfter returns whether the argument time is after the end of the RemoveAfter date.
+func (d TopicDeprecation) PastRemoveAfter(now time.Time) bool {
+	removeAfter, err := d.RemoveAfterTime()
+	if err != nil || removeAfter.IsZero() {
+		return false
+	}
+	return now.After(removeAfter.AddDate(0, 0, 1))
+}
+
+// Deletable returns whether the topic is marked as safe to delete. This is the case if it's
+// deprecated and its removeAfter date, if set, has passed.
+func (t TopicConfig) Deletable(now time.Time) bool {
+	deprecation := t.Meta.Deprecated
+	if deprecation == nil {
+		return false
+	}
+	return deprecation.RemoveAfter == "" || deprecation.PastRemoveAfter(now)
+}
+
+// ProtectedReason returns why the topic is protected, either because its config is marked as
+// protected or because its name matches one of the argument cluster's protected topic patterns,
+// or an empty string if it isn't protected.
+func (t TopicConfig) ProtectedReason(clusterConfig ClusterConfig) string {
+	if t.Meta.Protected {
+		return "its config is marked as protected"
+	}
+	if pattern := clusterConfig.ProtectedTopicPattern(t.Meta.Name); pattern != "" {
+		return fmt.Sprintf("it matches the protected topic pattern '%s'", pattern)
+	}
+	return ""
+}
+
+// IsProtected returns whether the topic is protected in the argument cluster.
+func (t TopicConfig) IsProtected(clusterConfig ClusterConfig) bool {
+	return t.ProtectedReason(clusterConfig) != ""
+}
+
+// DeclaresConsumerGroup returns whether the argument group is in the topic's consumer groups.
+func (t TopicConfig) DeclaresConsumerGroup(group string) bool {
+	for _, consumerGroup := range t.Meta.ConsumerGroups {
+		if consumerGroup == group {
+			return true
+		}
+	}
+	return false
 }
 
 // TopicSpec stores the (mutable) specification for a topic.
@@ -102,8 +295,26 @@ type TopicSpec struct {
 	RetentionMinutes  int           `json:"retentionMinutes,omitempty"`
 	Settings          TopicSettings `json:"settings,omitempty"`
 
-	PlacementConfig TopicPlacementConfig  `json:"placement"`
-	MigrationConfig *TopicMigrationConfig `json:"migration,omitempty"`
+	// Profile is the name of a settings profile that's expanded into the settings above. See
+	// ResolveSettingsProfile for details.
+	Profile string `json:"profile,omitempty"`
+
+	PlacementConfig   TopicPlacementConfig    `json:"placement"`
+	MigrationConfig   *TopicMigrationConfig   `json:"migration,omitempty"`
+	AutoscalingConfig *TopicAutoscalingConfig `json:"autoscaling,omitempty"`
+
+	// ACLs are the ACLs that should be set on the topic. If the section is present, even if it's
+	// empty, apply reconciles the literal allow ACLs on the topic in the cluster against it; if
+	// it's omitted, the topic's ACLs are left as-is.
+	ACLs []TopicACL `json:"acls,omitempty"`
+
+	// OffsetBaselines are the initial positions of consumer groups in the topic. See
+	// TopicOffsetBaseline for details.
+	OffsetBaselines []TopicOffsetBaseline `json:"offsetBaselines,omitempty"`
+
+	// LagThresholds are the maximum lags of consumer groups in the topic that are checked by the
+	// check subcommand.
+	LagThresholds []TopicLagThreshold `json:"lagThresholds,omitempty"`
 }
 
 // TopicPlacementConfig describes how the partition replicas in a topic
@@ -119,6 +330,49 @@ type TopicPlacementConfig struct {
 	// StaticRackAssignments is a list of list of desired replica assignments. It's used
 	// for the "static-in-rack" strategy only.
 	StaticRackAssignments []string `json:"staticRackAssignments,omitempty"`
+
+	// StaticRackReplicaCounts is a map from rack name to the number of replicas of each
+	// partition that should be placed in that rack. It's used for the "static-rack-counts"
+	// strategy only.
+	StaticRackReplicaCounts map[string]int `json:"staticRackReplicaCounts,omitempty"`
+
+	// Assigner is the name of the registered assigner plugin that chooses the placement, and
+	// AssignerOptions are passed to it as-is. They're used for the "custom" strategy only.
+	Assigner        string            `json:"assigner,omitempty"`
+	AssignerOptions map[string]string `json:"assignerOptions,omitempty"`
+
+	// Pins are optional constraints on the leaders of specific partitions. They're applied on
+	// top of the placement strategy.
+	Pins []PartitionPin `json:"pins,omitempty"`
+}
+
+// PartitionPin constrains the leader of a single partition to either a specific broker or
+// a specific rack. Exactly one of Leader or LeaderRack should be set.
+type PartitionPin struct {
+	Partition int `json:"partition"`
+
+	// Leader is the ID of the broker that should lead the partition.
+	Leader *int `json:"leader,omitempty"`
+
+	// LeaderRack is the rack that the partition leader should be in.
+	LeaderRack string `json:"leaderRack,omitempty"`
+}
+
+// TopicACL allows a principal to run one or more operations on a topic. A separate ACL is
+// created in the cluster for each operation.
+type TopicACL struct {
+	// Principal is the principal that the ACLs apply to, e.g. User:alice.
+	Principal string `json:"principal"`
+
+	// Operations are the names of the allowed operations, e.g. read, write, or describe.
+	Operations []string `json:"operations,omitempty"`
+
+	// Role is the name of an ACL role, e.g. producer or consumer, whose topic operations are
+	// allowed in addition to the ones above. See ResolveACLs for details.
+	Role string `json:"role,omitempty"`
+
+	// Host is the host that the principal is allowed to connect from; it defaults to "*".
+	Host string `json:"host,omitempty"`
 }
 
 // TopicMigrationConfig configures the throttles and batch sizes used when
@@ -129,6 +383,172 @@ type TopicMigrationConfig struct {
 	PartitionBatchSize int   `json:"partitionBatchSize"`
 }
 
+// TopicAutoscalingConfig describes the target throughput for each partition in a topic. It's
+// used by the recommend-partitions subcommand to propose partition count increases.
+type TopicAutoscalingConfig struct {
+	// TargetMessagesPerSecPerPartition is the maximum desired produce rate, in messages per
+	// second, for each partition.
+	TargetMessagesPerSecPerPartition float64 `json:"targetMessagesPerSecPerPartition"`
+
+	// MaxPartitions is the upper bound on the recommended number of partitions (optional).
+	MaxPartitions int `json:"maxPartitions,omitempty"`
+}
+
+// RecommendedPartitions returns the number of partitions needed to keep the per-partition rate
+// at or below the target, given the argument total topic rate. The result is never less than
+// currPartitions since Kafka doesn't support removing partitions.
+func (a TopicAutoscalingConfig) RecommendedPartitions(
+	currPartitions int,
+	messagesPerSec float64,
+) int {
+	if a.TargetMessagesPerSecPerPartition <= 0 {
+		return currPartitions
+	}
+
+	recommended := a.neededPartitions(messagesPerSec)
+	if a.MaxPartitions > 0 && recommended > a.MaxPartitions {
+		recommended = a.MaxPartitions
+	}
+	if recommended < currPartitions {
+		recommended = currPartitions
+	}
+
+	return recommended
+}
+
+// CappedByMaxPartitions returns whether the argument total topic rate needs more partitions than
+// MaxPartitions, i.e. whether RecommendedPartitions is limited by it.
+func (a TopicAutoscalingConfig) CappedByMaxPartitions(messagesPerSec float64) bool {
+	return a.TargetMessagesPerSecPerPartition > 0 && a.MaxPartitions > 0 &&
+		a.neededPartitions(messagesPerSec) > a.MaxPartitions
+}
+
+func (a TopicAutoscalingConfig) neededPartitions(messagesPerSec float64) int {
+	return int(math.Ceil(messagesPerSec / a.TargetMessagesPerSecPerPartition))
+}
+
+// TopicOffsetBaseline is the initial position of a consumer group in a topic. It's only applied
+// if the group hasn't committed any offsets in the topic, so that a new consumer starts from a
+// well-defined position and its progress is never overridden afterwards.
+type TopicOffsetBaseline struct {
+	Group    string                 `json:"group"`
+	Position OffsetBaselinePosition `json:"position"`
+
+	// Timestamp is the time that the group starts from, either in RFC3339 format or as a
+	// YYYY-MM-DD date (interpreted as midnight UTC). It's used for the timestamp position only.
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// Time returns the parsed timestamp of the baseline.
+func (b TopicOffsetBaseline) Time() (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, b.Timestamp); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse("2006-01-02", b.Timestamp); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf(
+		"Could not parse timestamp '%s'; must be an RFC3339 time or a date",
+		b.Timestamp,
+	)
+}
+
+func (b TopicOffsetBaseline) validate() error {
+	var err error
+
+	if len(b.Group) > maxConsumerGroupLength || !consumerGroupRegexp.MatchString(b.Group) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf(
+				"Group must be 1-%d characters from [a-zA-Z0-9._-]",
+				maxConsumerGroupLength,
+			),
+		)
+	}
+
+	validPosition := false
+	for _, position := range allOffsetBaselinePositions {
+		if b.Position == position {
+			validPosition = true
+			break
+		}
+	}
+	if !validPosition {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("Position must be in %+v", allOffsetBaselinePositions),
+		)
+	}
+
+	if b.Position == OffsetBaselinePositionTimestamp {
+		if _, timeErr := b.Time(); timeErr != nil {
+			err = multierror.Append(err, timeErr)
+		}
+	} else if b.Timestamp != "" {
+		err = multierror.Append(
+			err,
+			errors.New("Timestamp can only be set for the timestamp position"),
+		)
+	}
+
+	return err
+}
+
+// TopicLagThreshold is the maximum lag that a consumer group can have in a topic before it's
+// considered to be stalled. At least one of the maximums must be set.
+type TopicLagThreshold struct {
+	Group string `json:"group"`
+
+	// MaxLagMessages is the maximum number of messages, summed across all partitions, that the
+	// group can be behind by.
+	MaxLagMessages int64 `json:"maxLagMessages,omitempty"`
+
+	// MaxLagTime is the maximum difference, as a duration (e.g., 15m), between the time of the
+	// newest message in any partition and the time of the group's last consumed message in it.
+	MaxLagTime string `json:"maxLagTime,omitempty"`
+}
+
+// MaxLagDuration returns the parsed MaxLagTime of the threshold, or zero if it isn't set.
+func (l TopicLagThreshold) MaxLagDuration() (time.Duration, error) {
+	if l.MaxLagTime == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(l.MaxLagTime)
+}
+
+func (l TopicLagThreshold) validate() error {
+	var err error
+
+	if len(l.Group) > maxConsumerGroupLength || !consumerGroupRegexp.MatchString(l.Group) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf(
+				"Group must be 1-%d characters from [a-zA-Z0-9._-]",
+				maxConsumerGroupLength,
+			),
+		)
+	}
+	if l.MaxLagMessages < 0 {
+		err = multierror.Append(err, errors.New("MaxLagMessages must be >= 0"))
+	}
+
+	maxLagDuration, durationErr := l.MaxLagDuration()
+	if durationErr != nil {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("MaxLagTime must be a duration: %+v", durationErr),
+		)
+	} else if maxLagDuration < 0 {
+		err = multierror.Append(err, errors.New("MaxLagTime must be >= 0"))
+	}
+
+	if l.MaxLagMessages == 0 && l.MaxLagTime == "" {
+		err = multierror.Append(err, errors.New("One of maxLagMessages or maxLagTime must be set"))
+	}
+
+	return err
+}
+
 // ToNewTopicConfig converts a TopicConfig to a kafka.TopicConfig that can be
 // used by kafka-go to create a new topic.
 func (t TopicConfig) ToNewTopicConfig() (kafka.TopicConfig, error) {
@@ -159,6 +579,52 @@ func (t TopicConfig) ToNewTopicConfig() (kafka.TopicConfig, error) {
 	return config, nil
 }
 
+// ManagesACLs returns whether the topic config has an acls section, in which case apply
+// reconciles the topic's ACLs in the cluster against it.
+func (t TopicConfig) ManagesACLs() bool {
+	return t.Spec.ACLs != nil
+}
+
+// ACLs returns the ACLs described by the acls section of the topic config. These are all allow
+// ACLs on the literal topic resource, sorted in the same way as the ACLs returned by the admin
+// clients.
+func (t TopicConfig) ACLs() ([]admin.ACLInfo, error) {
+	acls := []admin.ACLInfo{}
+	seen := map[admin.ACLInfo]struct{}{}
+
+	for _, topicACL := range t.Spec.ACLs {
+		if topicACL.Role != "" {
+			return nil, fmt.Errorf(
+				"ACL role '%s' for principal '%s' has not been resolved",
+				topicACL.Role,
+				topicACL.Principal,
+			)
+		}
+
+		specACLs, err := admin.ACLSpec{
+			ResourceType: "topic",
+			ResourceName: t.Meta.Name,
+			Principals:   []string{topicACL.Principal},
+			Host:         topicACL.Host,
+			Operations:   topicACL.Operations,
+		}.ACLs()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ACL for principal '%s': %+v", topicACL.Principal, err)
+		}
+
+		for _, acl := range specACLs {
+			if _, ok := seen[acl]; ok {
+				continue
+			}
+			seen[acl] = struct{}{}
+			acls = append(acls, acl)
+		}
+	}
+
+	admin.SortACLs(acls)
+	return acls, nil
+}
+
 // SetDefaults sets the default migration and placement settings in a topic config
 // if these aren't set.
 func (t *TopicConfig) SetDefaults() {
@@ -180,6 +646,12 @@ func (t *TopicConfig) SetDefaults() {
 func (t TopicConfig) Validate(numRacks int) error {
 	var err error
 
+	if t.APIVersion != "" && !isValidAPIVersion(t.APIVersion) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("APIVersion must be in %+v", allAPIVersions),
+		)
+	}
 	if t.Meta.Name == "" {
 		err = multierror.Append(err, errors.New("Name must be set"))
 	}
@@ -192,6 +664,75 @@ func (t TopicConfig) Validate(numRacks int) error {
 	if t.Meta.Environment == "" {
 		err = multierror.Append(err, errors.New("Environment must be set"))
 	}
+
+	seenGroups := map[string]struct{}{}
+	for _, group := range t.Meta.ConsumerGroups {
+		if len(group) > maxConsumerGroupLength || !consumerGroupRegexp.MatchString(group) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Consumer group '%s' must be 1-%d characters from [a-zA-Z0-9._-]",
+					group,
+					maxConsumerGroupLength,
+				),
+			)
+		}
+		if _, ok := seenGroups[group]; ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Consumer group '%s' is listed more than once", group),
+			)
+		}
+		seenGroups[group] = struct{}{}
+	}
+
+	for _, group := range sortedKeys(t.Meta.ConsumerPrincipals) {
+		if _, ok := seenGroups[group]; !ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Consumer group '%s' in consumerPrincipals must be in consumerGroups", group),
+			)
+		}
+		if t.Meta.ConsumerPrincipals[group] == "" {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Principal for consumer group '%s' must be set", group),
+			)
+		}
+	}
+	for _, producer := range t.Meta.Producers {
+		if producer == "" {
+			err = multierror.Append(err, errors.New("Producer principals must be set"))
+		}
+	}
+
+	for key, value := range t.Meta.Labels {
+		if !labelKeyRegexp.MatchString(key) || !labelValueRegexp.MatchString(value) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Label '%s: %s' must only have characters from [a-zA-Z0-9._/-]",
+					key,
+					value,
+				),
+			)
+		}
+	}
+
+	if t.Meta.Deprecated != nil {
+		if t.Meta.Deprecated.Reason == "" {
+			err = multierror.Append(err, errors.New("Deprecation reason must be set"))
+		}
+		if _, parseErr := t.Meta.Deprecated.RemoveAfterTime(); parseErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Deprecation removeAfter must be a date in YYYY-MM-DD format: %+v",
+					parseErr,
+				),
+			)
+		}
+	}
 	if t.Spec.Partitions <= 0 {
 		err = multierror.Append(err, errors.New("Partitions must be a positive number"))
 	}
@@ -199,6 +740,60 @@ func (t TopicConfig) Validate(numRacks int) error {
 		err = multierror.Append(err, errors.New("ReplicationFactor must be > 0"))
 	}
 
+	if t.Spec.AutoscalingConfig != nil {
+		autoscaling := t.Spec.AutoscalingConfig
+		if autoscaling.TargetMessagesPerSecPerPartition <= 0 {
+			err = multierror.Append(
+				err,
+				errors.New("Autoscaling targetMessagesPerSecPerPartition must be a positive number"),
+			)
+		}
+		if autoscaling.MaxPartitions > 0 && autoscaling.MaxPartitions < t.Spec.Partitions {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Autoscaling maxPartitions (%d) cannot be less than partitions (%d)",
+					autoscaling.MaxPartitions,
+					t.Spec.Partitions,
+				),
+			)
+		}
+	}
+
+	seenBaselineGroups := map[string]struct{}{}
+	for _, baseline := range t.Spec.OffsetBaselines {
+		if baselineErr := baseline.validate(); baselineErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid offset baseline for group '%s': %+v", baseline.Group, baselineErr),
+			)
+		}
+		if _, ok := seenBaselineGroups[baseline.Group]; ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Group '%s' has more than one offset baseline", baseline.Group),
+			)
+		}
+		seenBaselineGroups[baseline.Group] = struct{}{}
+	}
+
+	seenThresholdGroups := map[string]struct{}{}
+	for _, threshold := range t.Spec.LagThresholds {
+		if thresholdErr := threshold.validate(); thresholdErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid lag threshold for group '%s': %+v", threshold.Group, thresholdErr),
+			)
+		}
+		if _, ok := seenThresholdGroups[threshold.Group]; ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Group '%s' has more than one lag threshold", threshold.Group),
+			)
+		}
+		seenThresholdGroups[threshold.Group] = struct{}{}
+	}
+
 	if settingsErr := t.Spec.Settings.Validate(); settingsErr != nil {
 		err = multierror.Append(err, settingsErr)
 	}
@@ -302,6 +897,79 @@ func (t TopicConfig) Validate(numRacks int) error {
 				errors.New("Static rack assignments must be same length as partitions"),
 			)
 		}
+	case PlacementStrategyStaticRackCounts:
+		totalReplicas := 0
+		for rack, count := range placement.StaticRackReplicaCounts {
+			if count <= 0 {
+				err = multierror.Append(
+					err,
+					fmt.Errorf("Static rack replica count for rack %s must be positive", rack),
+				)
+			}
+			totalReplicas += count
+		}
+
+		if totalReplicas != t.Spec.ReplicationFactor {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Static rack replica counts must sum to the replication factor (%d)",
+					t.Spec.ReplicationFactor,
+				),
+			)
+		}
+	case PlacementStrategyCustom:
+		if placement.Assigner == "" {
+			err = multierror.Append(
+				err,
+				errors.New("Assigner must be set if using the custom placement strategy"),
+			)
+		}
+	}
+
+	if placement.Strategy != PlacementStrategyCustom &&
+		(placement.Assigner != "" || len(placement.AssignerOptions) > 0) {
+		err = multierror.Append(
+			err,
+			errors.New("Assigner and assignerOptions can only be set with the custom placement strategy"),
+		)
+	}
+
+	if t.Meta.Name != "" {
+		if _, aclsErr := t.ACLs(); aclsErr != nil {
+			err = multierror.Append(err, aclsErr)
+		}
+	}
+
+	pinnedPartitions := map[int]struct{}{}
+	for _, pin := range placement.Pins {
+		if pin.Partition < 0 || pin.Partition >= t.Spec.Partitions {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Pinned partition %d must be between 0 and %d",
+					pin.Partition,
+					t.Spec.Partitions-1,
+				),
+			)
+		}
+		if _, ok := pinnedPartitions[pin.Partition]; ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Partition %d is pinned more than once", pin.Partition),
+			)
+		}
+		pinnedPartitions[pin.Partition] = struct{}{}
+
+		if (pin.Leader == nil) == (pin.LeaderRack == "") {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Exactly one of leader or leaderRack must be set in pin for partition %d",
+					pin.Partition,
+				),
+			)
+		}
 	}
 
 	// Warn about the partition count in the non-balanced-leaders case
@@ -331,27 +999,49 @@ func (t TopicConfig) ToYAML() (string, error) {
 func TopicConfigFromTopicInfo(
 	clusterConfig ClusterConfig,
 	topicInfo admin.TopicInfo,
+) TopicConfig {
+	return newTopicConfig(
+		clusterConfig,
+		topicInfo.Name,
+		len(topicInfo.Partitions),
+		len(topicInfo.Partitions[0].Replicas),
+		topicInfo.Config,
+		"Bootstrapped via topicctl bootstrap",
+	)
+}
+
+// newTopicConfig generates a TopicConfig with "any" placement from the argument topic
+// parameters and raw config map. The retention setting is converted to retentionMinutes if it's
+// a whole number of minutes.
+func newTopicConfig(
+	clusterConfig ClusterConfig,
+	name string,
+	partitions int,
+	replicationFactor int,
+	configMap map[string]string,
+	description string,
 ) TopicConfig {
 	topicConfig := TopicConfig{
+		APIVersion: CurrentAPIVersion,
 		Meta: TopicMeta{
-			Name:        topicInfo.Name,
+			Name:        name,
 			Cluster:     clusterConfig.Meta.Name,
 			Region:      clusterConfig.Meta.Region,
 			Environment: clusterConfig.Meta.Environment,
-			Description: "Bootstrapped via topicctl bootstrap",
+			Description: description,
 		},
 		Spec: TopicSpec{
-			Partitions:        len(topicInfo.Partitions),
-			ReplicationFactor: len(topicInfo.Partitions[0].Replicas),
+			Partitions:        partitions,
+			ReplicationFactor: replicationFactor,
 			PlacementConfig: TopicPlacementConfig{
 				Strategy: PlacementStrategyAny,
 			},
 		},
 	}
 
-	topicConfig.Spec.Settings = FromConfigMap(topicInfo.Config)
+	topicConfig.Spec.Settings = FromConfigMap(configMap)
 
-	retentionMinutes := topicInfo.Retention().Minutes()
+	retentionMinutes := admin.TopicInfo{Config: configMap}.Retention().Minutes()
 	if retentionMinutes >= 1.0 && float64(int(retentionMinutes)) == retentionMinutes {
 		topicConfig.Spec.RetentionMinutes = int(retentionMinutes)
 		delete(topicConfig.Spec.Settings, admin.RetentionKey)
@@ -2,12 +2,16 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTopicValidate(t *testing.T) {
+	leader3 := 3
+
 	type testCase struct {
 		description string
 		topicConfig TopicConfig
@@ -17,22 +21,688 @@ func TestTopicValidate(t *testing.T) {
 
 	testCases := []testCase{
 		{
-			description: "all good any placement",
+			description: "all good any placement",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					RetentionMinutes:  120,
+					Settings: TopicSettings{
+						"cleanup.policy": "compact",
+					},
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "all good balanced-leaders placement",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        6,
+					ReplicationFactor: 3,
+					RetentionMinutes:  120,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyBalancedLeaders,
+					},
+				},
+			},
+			numRacks: 3,
+			expError: false,
+		},
+		{
+			description: "all good static placement",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					RetentionMinutes:  120,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyStatic,
+						StaticAssignments: [][]int{
+							{1, 2, 3},
+							{4, 5, 6},
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "all good static-in-rack placement",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					RetentionMinutes:  120,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyStaticInRack,
+						StaticRackAssignments: []string{
+							"zone1",
+							"zone2",
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "missing meta fields",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					RetentionMinutes:  120,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "double-setting retention",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					RetentionMinutes:  120,
+					Settings: TopicSettings{
+						"retention.ms": "1234",
+					},
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "balanced leaders invalid rack count",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        7,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyBalancedLeaders,
+					},
+				},
+			},
+			numRacks: 3,
+			expError: true,
+		},
+		{
+			description: "static placement invalid num partitions",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        7,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyStatic,
+						StaticAssignments: [][]int{
+							{1, 2, 3},
+							{4, 5, 6},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "static placement invalid replication",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyStatic,
+						StaticAssignments: [][]int{
+							{1, 2, 3},
+							{4, 5},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "static-in-rack placement invalid partition count",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyStaticInRack,
+						StaticRackAssignments: []string{
+							"rack1",
+							"rack2",
+							"rack3",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "all good static-rack-counts placement",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyStaticRackCounts,
+						StaticRackReplicaCounts: map[string]int{
+							"rack1": 2,
+							"rack2": 1,
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "static-rack-counts placement counts don't match replication factor",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyStaticRackCounts,
+						StaticRackReplicaCounts: map[string]int{
+							"rack1": 2,
+							"rack2": 2,
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "static-rack-counts placement non-positive count",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyStaticRackCounts,
+						StaticRackReplicaCounts: map[string]int{
+							"rack1": 3,
+							"rack2": 0,
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "all good custom placement",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyCustom,
+						Assigner: "test-assigner",
+						AssignerOptions: map[string]string{
+							"zone": "a",
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "custom placement without assigner",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyCustom,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "assigner without custom placement",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Description: "Bootstrapped via topicctl bootstrap",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+						Assigner: "test-assigner",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "all good consumer groups",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:           "test-topic",
+					Cluster:        "test-cluster",
+					Region:         "test-region",
+					Environment:    "test-environment",
+					ConsumerGroups: []string{"group-1", "service.group_2"},
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "invalid consumer group",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:           "test-topic",
+					Cluster:        "test-cluster",
+					Region:         "test-region",
+					Environment:    "test-environment",
+					ConsumerGroups: []string{"group 1"},
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "duplicate consumer group",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:           "test-topic",
+					Cluster:        "test-cluster",
+					Region:         "test-region",
+					Environment:    "test-environment",
+					ConsumerGroups: []string{"group-1", "group-1"},
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good offset baselines",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					OffsetBaselines: []TopicOffsetBaseline{
+						{Group: "group-1", Position: OffsetBaselinePositionEarliest},
+						{
+							Group:     "group-2",
+							Position:  OffsetBaselinePositionTimestamp,
+							Timestamp: "2021-06-01T15:04:05Z",
+						},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "offset baseline with bad position",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					OffsetBaselines: []TopicOffsetBaseline{
+						{Group: "group-1", Position: "beginning"},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "offset baseline with bad timestamp",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					OffsetBaselines: []TopicOffsetBaseline{
+						{
+							Group:     "group-1",
+							Position:  OffsetBaselinePositionTimestamp,
+							Timestamp: "yesterday",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "offset baseline with unused timestamp",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					OffsetBaselines: []TopicOffsetBaseline{
+						{
+							Group:     "group-1",
+							Position:  OffsetBaselinePositionLatest,
+							Timestamp: "2021-06-01",
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "duplicate offset baseline group",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					OffsetBaselines: []TopicOffsetBaseline{
+						{Group: "group-1", Position: OffsetBaselinePositionEarliest},
+						{Group: "group-1", Position: OffsetBaselinePositionLatest},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "good lag thresholds",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					LagThresholds: []TopicLagThreshold{
+						{Group: "group-1", MaxLagMessages: 1000},
+						{Group: "group-2", MaxLagMessages: 1000, MaxLagTime: "15m"},
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "lag threshold without maximums",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					LagThresholds: []TopicLagThreshold{
+						{Group: "group-1"},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "lag threshold with bad time",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					LagThresholds: []TopicLagThreshold{
+						{Group: "group-1", MaxLagTime: "15 minutes"},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "lag threshold with negative messages",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					LagThresholds: []TopicLagThreshold{
+						{Group: "group-1", MaxLagMessages: -1, MaxLagTime: "15m"},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "duplicate lag threshold group",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+					LagThresholds: []TopicLagThreshold{
+						{Group: "group-1", MaxLagMessages: 1000},
+						{Group: "group-1", MaxLagTime: "15m"},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "all good labels",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
+					Labels: map[string]string{
+						"team":                "payments",
+						"example.com/tier":    "1",
+						"empty-value-allowed": "",
+					},
 				},
 				Spec: TopicSpec{
 					Partitions:        2,
 					ReplicationFactor: 3,
-					RetentionMinutes:  120,
-					Settings: TopicSettings{
-						"cleanup.policy": "compact",
-					},
 					PlacementConfig: TopicPlacementConfig{
 						Strategy: PlacementStrategyAny,
 					},
@@ -41,46 +711,118 @@ func TestTopicValidate(t *testing.T) {
 			expError: false,
 		},
 		{
-			description: "all good balanced-leaders placement",
+			description: "invalid label",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
+					Labels: map[string]string{
+						"team": "payments,billing",
+					},
 				},
 				Spec: TopicSpec{
-					Partitions:        6,
+					Partitions:        2,
 					ReplicationFactor: 3,
-					RetentionMinutes:  120,
 					PlacementConfig: TopicPlacementConfig{
-						Strategy: PlacementStrategyBalancedLeaders,
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "all good deprecation",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Deprecated: &TopicDeprecation{
+						Reason:      "replaced by test-topic-v2",
+						RemoveAfter: "2021-06-01",
+					},
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
 					},
 				},
 			},
-			numRacks: 3,
 			expError: false,
 		},
 		{
-			description: "all good static placement",
+			description: "deprecation missing reason",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
+					Deprecated: &TopicDeprecation{
+						RemoveAfter: "2021-06-01",
+					},
 				},
 				Spec: TopicSpec{
 					Partitions:        2,
 					ReplicationFactor: 3,
-					RetentionMinutes:  120,
 					PlacementConfig: TopicPlacementConfig{
-						Strategy: PlacementStrategyStatic,
-						StaticAssignments: [][]int{
-							{1, 2, 3},
-							{4, 5, 6},
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "deprecation invalid removeAfter",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+					Deprecated: &TopicDeprecation{
+						Reason:      "replaced by test-topic-v2",
+						RemoveAfter: "June 1st",
+					},
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "all good pins",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+						Pins: []PartitionPin{
+							{
+								Partition: 0,
+								Leader:    &leader3,
+							},
+							{
+								Partition:  1,
+								LeaderRack: "rack1",
+							},
 						},
 					},
 				},
@@ -88,136 +830,197 @@ func TestTopicValidate(t *testing.T) {
 			expError: false,
 		},
 		{
-			description: "all good static-in-rack placement",
+			description: "pin partition out of range",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
 				},
 				Spec: TopicSpec{
 					Partitions:        2,
 					ReplicationFactor: 3,
-					RetentionMinutes:  120,
 					PlacementConfig: TopicPlacementConfig{
-						Strategy: PlacementStrategyStaticInRack,
-						StaticRackAssignments: []string{
-							"zone1",
-							"zone2",
+						Strategy: PlacementStrategyAny,
+						Pins: []PartitionPin{
+							{
+								Partition: 2,
+								Leader:    &leader3,
+							},
 						},
 					},
 				},
 			},
-			expError: false,
+			expError: true,
 		},
 		{
-			description: "missing meta fields",
+			description: "duplicate pins",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
 				},
 				Spec: TopicSpec{
 					Partitions:        2,
 					ReplicationFactor: 3,
-					RetentionMinutes:  120,
 					PlacementConfig: TopicPlacementConfig{
 						Strategy: PlacementStrategyAny,
+						Pins: []PartitionPin{
+							{
+								Partition: 0,
+								Leader:    &leader3,
+							},
+							{
+								Partition:  0,
+								LeaderRack: "rack1",
+							},
+						},
 					},
 				},
 			},
 			expError: true,
 		},
 		{
-			description: "double-setting retention",
+			description: "pin with leader and leader rack",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
 				},
 				Spec: TopicSpec{
 					Partitions:        2,
 					ReplicationFactor: 3,
-					RetentionMinutes:  120,
-					Settings: TopicSettings{
-						"retention.ms": "1234",
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
+						Pins: []PartitionPin{
+							{
+								Partition:  0,
+								Leader:     &leader3,
+								LeaderRack: "rack1",
+							},
+						},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "all good autoscaling",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
+					PlacementConfig: TopicPlacementConfig{
+						Strategy: PlacementStrategyAny,
 					},
+					AutoscalingConfig: &TopicAutoscalingConfig{
+						TargetMessagesPerSecPerPartition: 1000,
+						MaxPartitions:                    16,
+					},
+				},
+			},
+			expError: false,
+		},
+		{
+			description: "autoscaling missing target",
+			topicConfig: TopicConfig{
+				Meta: TopicMeta{
+					Name:        "test-topic",
+					Cluster:     "test-cluster",
+					Region:      "test-region",
+					Environment: "test-environment",
+				},
+				Spec: TopicSpec{
+					Partitions:        2,
+					ReplicationFactor: 3,
 					PlacementConfig: TopicPlacementConfig{
 						Strategy: PlacementStrategyAny,
 					},
+					AutoscalingConfig: &TopicAutoscalingConfig{
+						MaxPartitions: 16,
+					},
 				},
 			},
 			expError: true,
 		},
 		{
-			description: "balanced leaders invalid rack count",
+			description: "autoscaling max partitions too low",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
 				},
 				Spec: TopicSpec{
-					Partitions:        7,
+					Partitions:        2,
 					ReplicationFactor: 3,
 					PlacementConfig: TopicPlacementConfig{
-						Strategy: PlacementStrategyBalancedLeaders,
+						Strategy: PlacementStrategyAny,
+					},
+					AutoscalingConfig: &TopicAutoscalingConfig{
+						TargetMessagesPerSecPerPartition: 1000,
+						MaxPartitions:                    1,
 					},
 				},
 			},
-			numRacks: 3,
 			expError: true,
 		},
 		{
-			description: "static placement invalid num partitions",
+			description: "all good acls",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
 				},
 				Spec: TopicSpec{
-					Partitions:        7,
+					Partitions:        2,
 					ReplicationFactor: 3,
 					PlacementConfig: TopicPlacementConfig{
-						Strategy: PlacementStrategyStatic,
-						StaticAssignments: [][]int{
-							{1, 2, 3},
-							{4, 5, 6},
+						Strategy: PlacementStrategyAny,
+					},
+					ACLs: []TopicACL{
+						{
+							Principal:  "User:alice",
+							Operations: []string{"read", "describe"},
 						},
 					},
 				},
 			},
-			expError: true,
+			expError: false,
 		},
 		{
-			description: "static placement invalid replication",
+			description: "acl principal without type",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
 				},
 				Spec: TopicSpec{
 					Partitions:        2,
 					ReplicationFactor: 3,
 					PlacementConfig: TopicPlacementConfig{
-						Strategy: PlacementStrategyStatic,
-						StaticAssignments: [][]int{
-							{1, 2, 3},
-							{4, 5},
+						Strategy: PlacementStrategyAny,
+					},
+					ACLs: []TopicACL{
+						{
+							Principal:  "alice",
+							Operations: []string{"read"},
 						},
 					},
 				},
@@ -225,24 +1028,24 @@ func TestTopicValidate(t *testing.T) {
 			expError: true,
 		},
 		{
-			description: "static-in-rack placement invalid partition count",
+			description: "invalid acl operation",
 			topicConfig: TopicConfig{
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
 					Region:      "test-region",
 					Environment: "test-environment",
-					Description: "Bootstrapped via topicctl bootstrap",
 				},
 				Spec: TopicSpec{
 					Partitions:        2,
 					ReplicationFactor: 3,
 					PlacementConfig: TopicPlacementConfig{
-						Strategy: PlacementStrategyStaticInRack,
-						StaticRackAssignments: []string{
-							"rack1",
-							"rack2",
-							"rack3",
+						Strategy: PlacementStrategyAny,
+					},
+					ACLs: []TopicACL{
+						{
+							Principal:  "User:alice",
+							Operations: []string{"read", "juggle"},
 						},
 					},
 				},
@@ -262,6 +1065,51 @@ func TestTopicValidate(t *testing.T) {
 	}
 }
 
+func TestTopicACLs(t *testing.T) {
+	topicConfig := TopicConfig{
+		Meta: TopicMeta{
+			Name: "test-topic",
+		},
+	}
+	assert.False(t, topicConfig.ManagesACLs())
+
+	topicConfig.Spec.ACLs = []TopicACL{}
+	assert.True(t, topicConfig.ManagesACLs())
+
+	topicConfig.Spec.ACLs = []TopicACL{
+		{
+			Principal:  "User:bob",
+			Operations: []string{"write"},
+			Host:       "10.0.0.1",
+		},
+		{
+			Principal:  "User:alice",
+			Operations: []string{"read", "describe"},
+		},
+		{
+			Principal:  "User:alice",
+			Operations: []string{"read"},
+		},
+	}
+
+	acls, err := topicConfig.ACLs()
+	require.NoError(t, err)
+
+	aclStrs := []string{}
+	for _, acl := range acls {
+		aclStrs = append(aclStrs, acl.String())
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"Allow Read on Topic test-topic (Literal) from host * for User:alice",
+			"Allow Describe on Topic test-topic (Literal) from host * for User:alice",
+			"Allow Write on Topic test-topic (Literal) from host 10.0.0.1 for User:bob",
+		},
+		aclStrs,
+	)
+}
+
 func TestTopicConfigFromTopicInfo(t *testing.T) {
 	type testCase struct {
 		description    string
@@ -304,6 +1152,7 @@ func TestTopicConfigFromTopicInfo(t *testing.T) {
 				Version: 1,
 			},
 			expTopicConfig: TopicConfig{
+				APIVersion: CurrentAPIVersion,
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
@@ -357,6 +1206,7 @@ func TestTopicConfigFromTopicInfo(t *testing.T) {
 				Version: 1,
 			},
 			expTopicConfig: TopicConfig{
+				APIVersion: CurrentAPIVersion,
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
@@ -407,6 +1257,7 @@ func TestTopicConfigFromTopicInfo(t *testing.T) {
 				Version: 1,
 			},
 			expTopicConfig: TopicConfig{
+				APIVersion: CurrentAPIVersion,
 				Meta: TopicMeta{
 					Name:        "test-topic",
 					Cluster:     "test-cluster",
@@ -434,3 +1285,182 @@ func TestTopicConfigFromTopicInfo(t *testing.T) {
 		assert.Equal(t, testCase.expTopicConfig, topicConfig)
 	}
 }
+
+func TestTopicDeprecationPastRemoveAfter(t *testing.T) {
+	deprecation := TopicDeprecation{
+		Reason:      "replaced by test-topic-v2",
+		RemoveAfter: "2021-06-01",
+	}
+
+	assert.False(
+		t,
+		deprecation.PastRemoveAfter(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)),
+	)
+	assert.True(
+		t,
+		deprecation.PastRemoveAfter(time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)),
+	)
+	assert.False(
+		t,
+		TopicDeprecation{Reason: "no date"}.PastRemoveAfter(time.Now()),
+	)
+}
+
+func TestTopicMatchesLabels(t *testing.T) {
+	selector, err := ParseLabelSelector("team=payments, tier=1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "tier": "1"}, selector)
+
+	assert.True(
+		t,
+		TopicMeta{
+			Labels: map[string]string{"team": "payments", "tier": "1", "owner": "alice"},
+		}.MatchesLabels(selector),
+	)
+	assert.False(
+		t,
+		TopicMeta{
+			Labels: map[string]string{"team": "payments", "tier": "2"},
+		}.MatchesLabels(selector),
+	)
+	assert.False(t, TopicMeta{}.MatchesLabels(selector))
+
+	_, err = ParseLabelSelector("team")
+	assert.Error(t, err)
+	_, err = ParseLabelSelector(",")
+	assert.Error(t, err)
+}
+
+func TestTopicDeletable(t *testing.T) {
+	now := time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, TopicConfig{}.Deletable(now))
+	assert.True(
+		t,
+		TopicConfig{
+			Meta: TopicMeta{
+				Deprecated: &TopicDeprecation{Reason: "no date"},
+			},
+		}.Deletable(now),
+	)
+	assert.True(
+		t,
+		TopicConfig{
+			Meta: TopicMeta{
+				Deprecated: &TopicDeprecation{
+					Reason:      "replaced by test-topic-v2",
+					RemoveAfter: "2021-06-01",
+				},
+			},
+		}.Deletable(now),
+	)
+	assert.False(
+		t,
+		TopicConfig{
+			Meta: TopicMeta{
+				Deprecated: &TopicDeprecation{
+					Reason:      "replaced by test-topic-v2",
+					RemoveAfter: "2021-06-02",
+				},
+			},
+		}.Deletable(now),
+	)
+}
+
+func TestTopicProtected(t *testing.T) {
+	clusterConfig := ClusterConfig{
+		Spec: ClusterSpec{
+			ProtectedTopicPatterns: []string{"^payments-", "(bad"},
+		},
+	}
+
+	assert.False(t, TopicConfig{Meta: TopicMeta{Name: "test-topic"}}.IsProtected(clusterConfig))
+	assert.Equal(
+		t,
+		"its config is marked as protected",
+		TopicConfig{
+			Meta: TopicMeta{Name: "test-topic", Protected: true},
+		}.ProtectedReason(clusterConfig),
+	)
+	assert.Equal(
+		t,
+		"it matches the protected topic pattern '^payments-'",
+		TopicConfig{
+			Meta: TopicMeta{Name: "payments-events"},
+		}.ProtectedReason(clusterConfig),
+	)
+	assert.True(
+		t,
+		TopicConfig{Meta: TopicMeta{Name: "payments-events"}}.IsProtected(clusterConfig),
+	)
+}
+
+func TestRecommendedPartitions(t *testing.T) {
+	type testCase struct {
+		description    string
+		autoscaling    TopicAutoscalingConfig
+		currPartitions int
+		messagesPerSec float64
+		expPartitions  int
+	}
+
+	testCases := []testCase{
+		{
+			description: "below target",
+			autoscaling: TopicAutoscalingConfig{
+				TargetMessagesPerSecPerPartition: 1000,
+			},
+			currPartitions: 6,
+			messagesPerSec: 2500,
+			expPartitions:  6,
+		},
+		{
+			description: "above target",
+			autoscaling: TopicAutoscalingConfig{
+				TargetMessagesPerSecPerPartition: 1000,
+			},
+			currPartitions: 6,
+			messagesPerSec: 8100,
+			expPartitions:  9,
+		},
+		{
+			description: "capped by max partitions",
+			autoscaling: TopicAutoscalingConfig{
+				TargetMessagesPerSecPerPartition: 1000,
+				MaxPartitions:                    8,
+			},
+			currPartitions: 6,
+			messagesPerSec: 8100,
+			expPartitions:  8,
+		},
+		{
+			description:    "no target",
+			autoscaling:    TopicAutoscalingConfig{},
+			currPartitions: 6,
+			messagesPerSec: 8100,
+			expPartitions:  6,
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(
+			t,
+			testCase.expPartitions,
+			testCase.autoscaling.RecommendedPartitions(
+				testCase.currPartitions,
+				testCase.messagesPerSec,
+			),
+			testCase.description,
+		)
+	}
+
+	autoscaling := TopicAutoscalingConfig{
+		TargetMessagesPerSecPerPartition: 1000,
+		MaxPartitions:                    8,
+	}
+	assert.True(t, autoscaling.CappedByMaxPartitions(8100))
+	assert.False(t, autoscaling.CappedByMaxPartitions(8000))
+
+	autoscaling.MaxPartitions = 0
+	assert.False(t, autoscaling.CappedByMaxPartitions(8100))
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// DefaultScramIterations is the number of iterations used for SCRAM credentials that don't set
+// one explicitly. It matches the default in kafka-configs.sh.
+const DefaultScramIterations = 4096
+
+// UserConfig represents the desired SCRAM credentials for a set of users in a cluster. The
+// passwords aren't stored in the config itself; each one references an environment variable or
+// command that the password is fetched from when the config is applied.
+type UserConfig struct {
+	// APIVersion is the version of the config format. If unset, the config is assumed to be
+	// in the original (v0) format.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	Meta UserMeta       `json:"meta"`
+	Spec UserConfigSpec `json:"spec"`
+}
+
+// UserMeta stores the metadata associated with a set of users.
+type UserMeta struct {
+	Name        string `json:"name"`
+	Cluster     string `json:"cluster"`
+	Region      string `json:"region"`
+	Environment string `json:"environment"`
+	Description string `json:"description"`
+}
+
+// UserConfigSpec stores the users whose credentials are managed by a user config.
+type UserConfigSpec struct {
+	Users []UserEntryConfig `json:"users"`
+}
+
+// UserEntryConfig stores the desired SCRAM credential of a single user for a single mechanism.
+// Users that authenticate with both mechanisms should have one entry per mechanism.
+type UserEntryConfig struct {
+	// Name is the SCRAM username, e.g. alice for the principal User:alice.
+	Name string `json:"name"`
+
+	// Mechanism is either SCRAM-SHA-256 or SCRAM-SHA-512.
+	Mechanism string `json:"mechanism"`
+
+	// Iterations is the number of hashing iterations; it defaults to DefaultScramIterations.
+	Iterations int `json:"iterations,omitempty"`
+
+	// Password references the place that the user's password is fetched from.
+	Password SecretRef `json:"password"`
+}
+
+// Credentials returns the desired (non-secret) details of the credentials in the config, in
+// config order.
+func (u UserConfig) Credentials() []admin.ScramCredentialInfo {
+	credentials := []admin.ScramCredentialInfo{}
+
+	for _, user := range u.Spec.Users {
+		iterations := user.Iterations
+		if iterations == 0 {
+			iterations = DefaultScramIterations
+		}
+
+		credentials = append(
+			credentials,
+			admin.ScramCredentialInfo{
+				User:       user.Name,
+				Mechanism:  user.Mechanism,
+				Iterations: iterations,
+			},
+		)
+	}
+
+	return credentials
+}
+
+// Validate evaluates whether the user config is valid. The passwords aren't resolved.
+func (u UserConfig) Validate() error {
+	var err error
+
+	if u.APIVersion != "" && !isValidAPIVersion(u.APIVersion) {
+		err = multierror.Append(
+			err,
+			fmt.Errorf("APIVersion must be in %+v", allAPIVersions),
+		)
+	}
+	if u.Meta.Name == "" {
+		err = multierror.Append(err, errors.New("Name must be set"))
+	}
+	if u.Meta.Cluster == "" {
+		err = multierror.Append(err, errors.New("Cluster must be set"))
+	}
+	if u.Meta.Region == "" {
+		err = multierror.Append(err, errors.New("Region must be set"))
+	}
+	if u.Meta.Environment == "" {
+		err = multierror.Append(err, errors.New("Environment must be set"))
+	}
+	if len(u.Spec.Users) == 0 {
+		err = multierror.Append(err, errors.New("At least one user must be set"))
+	}
+
+	seen := map[admin.ScramCredentialInfo]struct{}{}
+
+	for _, user := range u.Spec.Users {
+		if user.Name == "" {
+			err = multierror.Append(err, errors.New("User name must be set"))
+			continue
+		}
+
+		validMechanism := false
+		for _, mechanism := range admin.AllScramMechanisms {
+			if user.Mechanism == mechanism {
+				validMechanism = true
+				break
+			}
+		}
+		if !validMechanism {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Mechanism for user %s must be in %+v",
+					user.Name,
+					admin.AllScramMechanisms,
+				),
+			)
+		}
+
+		if user.Iterations != 0 &&
+			(user.Iterations < admin.MinScramIterations ||
+				user.Iterations > admin.MaxScramIterations) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Iterations for user %s must be between %d and %d",
+					user.Name,
+					admin.MinScramIterations,
+					admin.MaxScramIterations,
+				),
+			)
+		}
+
+		if secretErr := user.Password.Validate(); secretErr != nil {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Invalid password for user %s: %+v", user.Name, secretErr),
+			)
+		}
+
+		key := admin.ScramCredentialInfo{User: user.Name, Mechanism: user.Mechanism}
+		if _, ok := seen[key]; ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"User %s is declared more than once for mechanism %s",
+					user.Name,
+					user.Mechanism,
+				),
+			)
+		}
+		seen[key] = struct{}{}
+	}
+
+	return err
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserConfigValidate(t *testing.T) {
+	type testCase struct {
+		description string
+		users       []UserEntryConfig
+		expError    bool
+	}
+
+	testCases := []testCase{
+		{
+			description: "all valid",
+			users: []UserEntryConfig{
+				{
+					Name:      "alice",
+					Mechanism: "SCRAM-SHA-512",
+					Password: SecretRef{
+						FromEnv: "ALICE_PASSWORD",
+					},
+				},
+				{
+					Name:       "alice",
+					Mechanism:  "SCRAM-SHA-256",
+					Iterations: 8192,
+					Password: SecretRef{
+						FromCommand: []string{"vault", "kv", "get", "secret/alice"},
+					},
+				},
+			},
+		},
+		{
+			description: "no users",
+			users:       []UserEntryConfig{},
+			expError:    true,
+		},
+		{
+			description: "invalid mechanism",
+			users: []UserEntryConfig{
+				{
+					Name:      "alice",
+					Mechanism: "PLAIN",
+					Password: SecretRef{
+						FromEnv: "ALICE_PASSWORD",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "too few iterations",
+			users: []UserEntryConfig{
+				{
+					Name:       "alice",
+					Mechanism:  "SCRAM-SHA-512",
+					Iterations: 1000,
+					Password: SecretRef{
+						FromEnv: "ALICE_PASSWORD",
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "missing password",
+			users: []UserEntryConfig{
+				{
+					Name:      "alice",
+					Mechanism: "SCRAM-SHA-512",
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "multiple password sources",
+			users: []UserEntryConfig{
+				{
+					Name:      "alice",
+					Mechanism: "SCRAM-SHA-512",
+					Password: SecretRef{
+						FromEnv:     "ALICE_PASSWORD",
+						FromCommand: []string{"cat", "password.txt"},
+					},
+				},
+			},
+			expError: true,
+		},
+		{
+			description: "duplicate user",
+			users: []UserEntryConfig{
+				{
+					Name:      "alice",
+					Mechanism: "SCRAM-SHA-512",
+					Password: SecretRef{
+						FromEnv: "ALICE_PASSWORD",
+					},
+				},
+				{
+					Name:      "alice",
+					Mechanism: "SCRAM-SHA-512",
+					Password: SecretRef{
+						FromEnv: "OTHER_PASSWORD",
+					},
+				},
+			},
+			expError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		userConfig := UserConfig{
+			Meta: UserMeta{
+				Name:        "test-users",
+				Cluster:     "test-cluster",
+				Region:      "test-region",
+				Environment: "test-env",
+			},
+			Spec: UserConfigSpec{
+				Users: testCase.users,
+			},
+		}
+
+		err := userConfig.Validate()
+		if testCase.expError {
+			assert.Error(t, err, testCase.description)
+		} else {
+			assert.NoError(t, err, testCase.description)
+		}
+	}
+}
+
+func TestUserConfigCredentials(t *testing.T) {
+	userConfig := UserConfig{
+		Spec: UserConfigSpec{
+			Users: []UserEntryConfig{
+				{
+					Name:      "alice",
+					Mechanism: "SCRAM-SHA-512",
+				},
+				{
+					Name:       "bob",
+					Mechanism:  "SCRAM-SHA-256",
+					Iterations: 8192,
+				},
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]admin.ScramCredentialInfo{
+			{
+				User:       "alice",
+				Mechanism:  "SCRAM-SHA-512",
+				Iterations: 4096,
+			},
+			{
+				User:       "bob",
+				Mechanism:  "SCRAM-SHA-256",
+				Iterations: 8192,
+			},
+		},
+		userConfig.Credentials(),
+	)
+}
+
+func TestSecretRefResolve(t *testing.T) {
+	ctx := context.Background()
+
+	t.Setenv("TOPICCTL_TEST_SECRET", "env-secret")
+	value, err := SecretRef{FromEnv: "TOPICCTL_TEST_SECRET"}.Resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "env-secret", value)
+
+	_, err = SecretRef{FromEnv: "TOPICCTL_TEST_MISSING_SECRET"}.Resolve(ctx)
+	assert.Error(t, err)
+
+	value, err = SecretRef{FromCommand: []string{"echo", "command-secret"}}.Resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "command-secret", value)
+
+	_, err = SecretRef{FromCommand: []string{"false"}}.Resolve(ctx)
+	assert.Error(t, err)
+
+	_, err = SecretRef{}.Resolve(ctx)
+	assert.Error(t, err)
+	_, err = SecretRef{FromURI: "secret/data/kafka/admin"}.Resolve(ctx)
+	assert.Error(t, err)
+
+	assert.NoError(t, SecretRef{FromURI: "ssm:///kafka/admin/password"}.Validate())
+	assert.Error(
+		t,
+		SecretRef{
+			FromEnv: "TOPICCTL_TEST_SECRET",
+			FromURI: "ssm:///kafka/admin/password",
+		}.Validate(),
+	)
+
+	assert.Equal(t, "env TOPICCTL_TEST_SECRET", SecretRef{FromEnv: "TOPICCTL_TEST_SECRET"}.String())
+	assert.Equal(t, "command vault", SecretRef{FromCommand: []string{"vault", "kv"}}.String())
+	assert.Equal(
+		t,
+		"secret vault://secret/data/kafka/admin#password",
+		SecretRef{FromURI: "vault://secret/data/kafka/admin#password"}.String(),
+	)
+}
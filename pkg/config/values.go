@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+)
+
+// TemplateValues are the variables that configs are rendered with when they're loaded as
+// templates. In the configs, these are referenced via Go template syntax, e.g.
+// {{ .Values.partitions }}.
+type TemplateValues map[string]interface{}
+
+// LoadTemplateValues builds TemplateValues from the argument values files and key=value
+// overrides, in a similar way to helm. The values files are merged in order, and then the
+// overrides are applied on top. Nested keys in overrides are separated by dots.
+//
+// A non-nil value is returned even if there are no values files or overrides, so callers
+// should only call this if they want configs to be rendered as templates.
+func LoadTemplateValues(valuesPaths []string, setValues []string) (TemplateValues, error) {
+	values := TemplateValues{}
+
+	for _, valuesPath := range valuesPaths {
+		contents, err := ioutil.ReadFile(valuesPath)
+		if err != nil {
+			return nil, err
+		}
+		contents, err = toYAMLCompatible(contents, ConfigFormatForPath(valuesPath))
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing values file %s: %+v", valuesPath, err)
+		}
+		jsonBytes, err := yaml.YAMLToJSON(contents)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing values file %s: %+v", valuesPath, err)
+		}
+
+		fileValues := map[string]interface{}{}
+		if err := decodeJSONUseNumber(jsonBytes, &fileValues); err != nil {
+			return nil, fmt.Errorf("Values file %s must contain a map: %+v", valuesPath, err)
+		}
+		mergeMaps(values, fileValues)
+	}
+
+	for _, setValue := range setValues {
+		if err := values.Set(setValue); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// Set applies an override of the form key=value. Nested keys are separated by dots, e.g.
+// "retention.minutes=60". Values of "true" and "false" are converted to booleans and integer
+// values are converted to ints; everything else is treated as a string.
+func (v TemplateValues) Set(setValue string) error {
+	elements := strings.SplitN(setValue, "=", 2)
+	if len(elements) != 2 || strings.TrimSpace(elements[0]) == "" {
+		return fmt.Errorf("Value override must be in the format key=value, got '%s'", setValue)
+	}
+
+	keys := strings.Split(strings.TrimSpace(elements[0]), ".")
+	curr := map[string]interface{}(v)
+
+	for _, key := range keys[:len(keys)-1] {
+		if key == "" {
+			return fmt.Errorf("Value override key '%s' contains an empty element", elements[0])
+		}
+
+		next, ok := curr[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			curr[key] = next
+		}
+		curr = next
+	}
+
+	lastKey := keys[len(keys)-1]
+	if lastKey == "" {
+		return fmt.Errorf("Value override key '%s' contains an empty element", elements[0])
+	}
+	curr[lastKey] = parseSetValue(elements[1])
+
+	return nil
+}
+
+func parseSetValue(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return intValue
+	}
+
+	return value
+}
+
+// renderTemplate renders the argument config contents as a Go template with the argument
+// values. If values is nil, the contents are returned as-is. Missing values are treated as
+// errors so that typos don't silently produce empty strings.
+func renderTemplate(path string, contents []byte, values TemplateValues) ([]byte, error) {
+	if values == nil {
+		return contents, nil
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(
+		string(contents),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing template %s: %+v", path, err)
+	}
+
+	out := &bytes.Buffer{}
+	err = tmpl.Execute(
+		out,
+		map[string]interface{}{
+			"Values": map[string]interface{}(values),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error rendering template %s: %+v", path, err)
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplateValues(t *testing.T) {
+	values, err := LoadTemplateValues(
+		[]string{
+			"testdata/templates/values-base.yaml",
+			"testdata/templates/values-prod.yaml",
+		},
+		[]string{
+			"compacted=true",
+			"topic.replicationFactor=3",
+			"owner.team=data=eng",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		TemplateValues{
+			"env":       "prod",
+			"region":    "us-west-2",
+			"compacted": true,
+			"topic": map[string]interface{}{
+				"partitions":        json.Number("12"),
+				"replicationFactor": int64(3),
+			},
+			"owner": map[string]interface{}{
+				"team": "data=eng",
+			},
+		},
+		values,
+	)
+
+	_, err = LoadTemplateValues(nil, []string{"no-equals"})
+	assert.Error(t, err)
+	_, err = LoadTemplateValues(nil, []string{"topic..partitions=3"})
+	assert.Error(t, err)
+	_, err = LoadTemplateValues([]string{"testdata/templates/non-existent.yaml"}, nil)
+	assert.Error(t, err)
+}
+
+func TestLoadTopicsFileWithValues(t *testing.T) {
+	values, err := LoadTemplateValues(
+		[]string{"testdata/templates/values-base.yaml"},
+		[]string{"compacted=true"},
+	)
+	require.NoError(t, err)
+
+	topicConfigs, err := LoadTopicsFileWithValues(
+		"testdata/templates/topic-template.yaml",
+		values,
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(topicConfigs))
+
+	assert.Equal(
+		t,
+		TopicConfig{
+			Meta: TopicMeta{
+				Name:        "topic-stage",
+				Cluster:     "test-cluster-stage",
+				Region:      "us-west-2",
+				Environment: "stage",
+				Description: "Test topic\n",
+			},
+			Spec: TopicSpec{
+				Partitions:        3,
+				ReplicationFactor: 2,
+				RetentionMinutes:  100,
+				PlacementConfig: TopicPlacementConfig{
+					Strategy: PlacementStrategyInRack,
+				},
+				Settings: TopicSettings{
+					"cleanup.policy": "compact",
+				},
+			},
+		},
+		topicConfigs[0],
+	)
+
+	// Missing values are errors
+	values, err = LoadTemplateValues(nil, []string{"env=stage"})
+	require.NoError(t, err)
+	_, err = LoadTopicsFileWithValues("testdata/templates/topic-template.yaml", values)
+	assert.Error(t, err)
+}
@@ -0,0 +1,354 @@
+package cruisecontrol
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TaskStatusActive is the status of user tasks whose proposals are still being computed.
+	TaskStatusActive = "Active"
+
+	// TaskStatusInExecution is the status of user tasks whose proposals are being executed.
+	TaskStatusInExecution = "InExecution"
+
+	// TaskStatusCompleted is the status of user tasks that finished successfully.
+	TaskStatusCompleted = "Completed"
+
+	// TaskStatusCompletedWithError is the status of user tasks that failed.
+	TaskStatusCompletedWithError = "CompletedWithError"
+
+	requestTimeout      = 30 * time.Second
+	defaultPollInterval = 10 * time.Second
+	userTaskIDHeader    = "User-Task-ID"
+)
+
+// Config stores the settings for connecting to the REST API of a Cruise Control instance.
+type Config struct {
+	// URL is the base URL of the API, including the path prefix, e.g.
+	// http://cruise-control.example.com:9090/kafkacruisecontrol.
+	URL string
+
+	// Username and Password are the basic auth credentials, if any.
+	Username string
+	Password string
+
+	CACertPath string
+	SkipVerify bool
+
+	// PollInterval is the time between the requests that check on asynchronous tasks. It
+	// defaults to 10 seconds.
+	PollInterval time.Duration
+}
+
+// OptimizationRequest stores the parameters of a rebalance or broker removal.
+type OptimizationRequest struct {
+	// DryRun indicates whether the proposals should only be computed, not executed.
+	DryRun bool
+
+	// Goals are the names of the goals to optimize for; if empty, the default goals of the
+	// Cruise Control instance are used.
+	Goals []string
+
+	// ExcludedTopics is a regexp of the topics whose replicas shouldn't be moved.
+	ExcludedTopics string
+
+	// ReplicationThrottle is the throttle on replica movements in bytes per second; if zero,
+	// the default throttle of the Cruise Control instance is used.
+	ReplicationThrottle int64
+}
+
+// OptimizationResult is the result of a rebalance or broker removal request.
+type OptimizationResult struct {
+	// TaskID is the ID of the user task that computed, and possibly executed, the proposals.
+	TaskID string `json:"-"`
+
+	Summary OptimizationSummary `json:"summary"`
+}
+
+// OptimizationSummary summarizes the proposals of an optimization.
+type OptimizationSummary struct {
+	NumReplicaMovements           int      `json:"numReplicaMovements"`
+	NumLeaderMovements            int      `json:"numLeaderMovements"`
+	DataToMoveMB                  float64  `json:"dataToMoveMB"`
+	MonitoredPartitionsPercentage float64  `json:"monitoredPartitionsPercentage"`
+	ExcludedTopics                []string `json:"excludedTopics"`
+}
+
+// Empty returns whether the optimization doesn't move any replicas or leaders.
+func (s OptimizationSummary) Empty() bool {
+	return s.NumReplicaMovements == 0 && s.NumLeaderMovements == 0
+}
+
+// UserTask is the state of an asynchronous request in Cruise Control.
+type UserTask struct {
+	UserTaskID string `json:"UserTaskId"`
+	RequestURL string `json:"RequestURL"`
+	Status     string `json:"Status"`
+}
+
+type userTasksResponse struct {
+	UserTasks []UserTask `json:"userTasks"`
+}
+
+type errorResponse struct {
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// Client sends requests to the REST API of a Cruise Control instance. Cruise Control computes
+// proposals asynchronously, so the requests that start optimizations are repeated with the ID of
+// their user task until the proposals are ready.
+type Client struct {
+	config     Config
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a new Client for the argument config.
+func NewClient(config Config) (*Client, error) {
+	if config.URL == "" {
+		return nil, errors.New("Cruise Control URL must be set")
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.CACertPath != "" || config.SkipVerify {
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: config.SkipVerify,
+		}
+
+		if config.CACertPath != "" {
+			log.Debugf("Adding CA certs from %s", config.CACertPath)
+			caCertContents, err := ioutil.ReadFile(config.CACertPath)
+			if err != nil {
+				return nil, err
+			}
+			caCertPool := x509.NewCertPool()
+			if ok := caCertPool.AppendCertsFromPEM(caCertContents); !ok {
+				return nil, fmt.Errorf(
+					"Could not append CA certs from %s",
+					config.CACertPath,
+				)
+			}
+			tlsConfig.RootCAs = caCertPool
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &Client{
+		config:  config,
+		baseURL: strings.TrimRight(config.URL, "/"),
+		httpClient: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: transport,
+		},
+	}, nil
+}
+
+// Rebalance computes, and unless the request is a dry-run starts executing, the proposals that
+// rebalance the cluster.
+func (c *Client) Rebalance(
+	ctx context.Context,
+	request OptimizationRequest,
+) (OptimizationResult, error) {
+	return c.optimize(ctx, "rebalance", request.params())
+}
+
+// RemoveBrokers computes, and unless the request is a dry-run starts executing, the proposals
+// that move all of the replicas off of the argument brokers.
+func (c *Client) RemoveBrokers(
+	ctx context.Context,
+	brokerIDs []int,
+	request OptimizationRequest,
+) (OptimizationResult, error) {
+	if len(brokerIDs) == 0 {
+		return OptimizationResult{}, errors.New("At least one broker must be removed")
+	}
+
+	idStrs := []string{}
+	for _, brokerID := range brokerIDs {
+		idStrs = append(idStrs, strconv.Itoa(brokerID))
+	}
+
+	params := request.params()
+	params.Set("brokerid", strings.Join(idStrs, ","))
+	return c.optimize(ctx, "remove_broker", params)
+}
+
+// GetUserTask gets the state of the user task with the argument ID.
+func (c *Client) GetUserTask(ctx context.Context, taskID string) (UserTask, error) {
+	params := url.Values{}
+	params.Set("json", "true")
+	params.Set("user_task_ids", taskID)
+
+	resp := userTasksResponse{}
+	if _, _, err := c.request(ctx, http.MethodGet, "user_tasks", params, "", &resp); err != nil {
+		return UserTask{}, err
+	}
+	for _, task := range resp.UserTasks {
+		if task.UserTaskID == taskID {
+			return task, nil
+		}
+	}
+
+	return UserTask{}, fmt.Errorf("User task %s not found in Cruise Control", taskID)
+}
+
+// WaitForTask polls the user task with the argument ID until it's no longer active or in
+// execution. An error is returned if it completed with an error.
+func (c *Client) WaitForTask(ctx context.Context, taskID string) (UserTask, error) {
+	for {
+		task, err := c.GetUserTask(ctx, taskID)
+		if err != nil {
+			return UserTask{}, err
+		}
+
+		switch task.Status {
+		case TaskStatusCompleted:
+			return task, nil
+		case TaskStatusCompletedWithError:
+			return task, fmt.Errorf("Cruise Control task %s completed with an error", taskID)
+		}
+
+		log.Infof("Cruise Control task %s is %s; checking again", taskID, task.Status)
+
+		select {
+		case <-ctx.Done():
+			return UserTask{}, ctx.Err()
+		case <-time.After(c.config.PollInterval):
+		}
+	}
+}
+
+func (c *Client) optimize(
+	ctx context.Context,
+	endpoint string,
+	params url.Values,
+) (OptimizationResult, error) {
+	var taskID string
+
+	for {
+		result := OptimizationResult{}
+		status, header, err := c.request(ctx, http.MethodPost, endpoint, params, taskID, &result)
+		if err != nil {
+			return OptimizationResult{}, err
+		}
+		if id := header.Get(userTaskIDHeader); id != "" {
+			taskID = id
+		}
+
+		if status != http.StatusAccepted {
+			result.TaskID = taskID
+			return result, nil
+		}
+		if taskID == "" {
+			return OptimizationResult{}, fmt.Errorf(
+				"Cruise Control accepted %s request without a user task ID",
+				endpoint,
+			)
+		}
+
+		log.Infof("Waiting for Cruise Control to compute proposals for task %s", taskID)
+
+		select {
+		case <-ctx.Done():
+			return OptimizationResult{}, ctx.Err()
+		case <-time.After(c.config.PollInterval):
+		}
+	}
+}
+
+func (c *Client) request(
+	ctx context.Context,
+	method string,
+	endpoint string,
+	params url.Values,
+	taskID string,
+	result interface{},
+) (int, http.Header, error) {
+	path := fmt.Sprintf("/%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+	if taskID != "" {
+		req.Header.Set(userTaskIDHeader, taskID)
+	}
+
+	log.Debugf("Sending Cruise Control request: %s %s", method, path)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(body))
+
+		errResp := errorResponse{}
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.ErrorMessage != "" {
+			message = errResp.ErrorMessage
+		}
+
+		return 0, nil, fmt.Errorf(
+			"Got status %d from Cruise Control for %s %s: %s",
+			resp.StatusCode,
+			method,
+			endpoint,
+			message,
+		)
+	}
+
+	// The bodies of accepted requests only have the progress of the task
+	if resp.StatusCode != http.StatusAccepted && result != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, result); err != nil {
+			return 0, nil, fmt.Errorf(
+				"Error parsing Cruise Control response for %s: %+v",
+				endpoint,
+				err,
+			)
+		}
+	}
+	return resp.StatusCode, resp.Header, nil
+}
+
+func (r OptimizationRequest) params() url.Values {
+	params := url.Values{}
+	params.Set("json", "true")
+	params.Set("dryrun", strconv.FormatBool(r.DryRun))
+
+	if len(r.Goals) > 0 {
+		params.Set("goals", strings.Join(r.Goals, ","))
+	}
+	if r.ExcludedTopics != "" {
+		params.Set("excluded_topics", r.ExcludedTopics)
+	}
+	if r.ReplicationThrottle > 0 {
+		params.Set("replication_throttle", strconv.FormatInt(r.ReplicationThrottle, 10))
+	}
+
+	return params
+}
@@ -0,0 +1,180 @@
+package cruisecontrol
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRemoveBrokers(t *testing.T) {
+	mutex := sync.Mutex{}
+	requests := 0
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				requests++
+
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/kafkacruisecontrol/remove_broker", r.URL.Path)
+
+				query := r.URL.Query()
+				assert.Equal(t, "1,3", query.Get("brokerid"))
+				assert.Equal(t, "true", query.Get("dryrun"))
+				assert.Equal(t, "RackAwareGoal,DiskCapacityGoal", query.Get("goals"))
+				assert.Equal(t, "2000000", query.Get("replication_throttle"))
+
+				username, password, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "test-user", username)
+				assert.Equal(t, "test-password", password)
+
+				// The first request is accepted, and the proposals are returned once it's
+				// repeated with the task ID
+				if requests == 1 {
+					assert.Equal(t, "", r.Header.Get("User-Task-ID"))
+					w.Header().Set("User-Task-ID", "task-1")
+					w.WriteHeader(http.StatusAccepted)
+					w.Write([]byte(`{"progress":[],"version":1}`))
+					return
+				}
+
+				assert.Equal(t, "task-1", r.Header.Get("User-Task-ID"))
+				w.Header().Set("User-Task-ID", "task-1")
+				w.Write(
+					[]byte(
+						`{"summary":{"numReplicaMovements":12,"numLeaderMovements":3,"dataToMoveMB":1024.5,"monitoredPartitionsPercentage":100.0,"excludedTopics":[]},"version":1}`,
+					),
+				)
+			},
+		),
+	)
+	defer server.Close()
+
+	client, err := NewClient(
+		Config{
+			URL:          server.URL + "/kafkacruisecontrol/",
+			Username:     "test-user",
+			Password:     "test-password",
+			PollInterval: time.Millisecond,
+		},
+	)
+	require.NoError(t, err)
+
+	result, err := client.RemoveBrokers(
+		context.Background(),
+		[]int{1, 3},
+		OptimizationRequest{
+			DryRun:              true,
+			Goals:               []string{"RackAwareGoal", "DiskCapacityGoal"},
+			ReplicationThrottle: 2000000,
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(
+		t,
+		OptimizationResult{
+			TaskID: "task-1",
+			Summary: OptimizationSummary{
+				NumReplicaMovements:           12,
+				NumLeaderMovements:            3,
+				DataToMoveMB:                  1024.5,
+				MonitoredPartitionsPercentage: 100.0,
+				ExcludedTopics:                []string{},
+			},
+		},
+		result,
+	)
+	assert.False(t, result.Summary.Empty())
+
+	_, err = client.RemoveBrokers(context.Background(), nil, OptimizationRequest{})
+	assert.Error(t, err)
+}
+
+func TestClientWaitForTask(t *testing.T) {
+	mutex := sync.Mutex{}
+	statuses := []string{TaskStatusInExecution, TaskStatusInExecution, TaskStatusCompleted}
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/user_tasks", r.URL.Path)
+
+				taskID := r.URL.Query().Get("user_task_ids")
+				if taskID != "task-1" {
+					w.Write([]byte(`{"userTasks":[],"version":1}`))
+					return
+				}
+
+				status := statuses[0]
+				if len(statuses) > 1 {
+					statuses = statuses[1:]
+				}
+				w.Write(
+					[]byte(
+						`{"userTasks":[{"UserTaskId":"task-1","RequestURL":"POST /rebalance","Status":"` +
+							status + `"}],"version":1}`,
+					),
+				)
+			},
+		),
+	)
+	defer server.Close()
+
+	client, err := NewClient(Config{URL: server.URL, PollInterval: time.Millisecond})
+	require.NoError(t, err)
+
+	task, err := client.WaitForTask(context.Background(), "task-1")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		UserTask{
+			UserTaskID: "task-1",
+			RequestURL: "POST /rebalance",
+			Status:     TaskStatusCompleted,
+		},
+		task,
+	)
+
+	_, err = client.WaitForTask(context.Background(), "task-2")
+	assert.Error(t, err)
+}
+
+func TestClientErrors(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write(
+					[]byte(
+						`{"errorMessage":"Not enough valid windows","stackTrace":"...","version":1}`,
+					),
+				)
+			},
+		),
+	)
+	defer server.Close()
+
+	client, err := NewClient(Config{URL: server.URL})
+	require.NoError(t, err)
+
+	_, err = client.Rebalance(context.Background(), OptimizationRequest{DryRun: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Not enough valid windows")
+
+	_, err = NewClient(Config{})
+	assert.Error(t, err)
+}
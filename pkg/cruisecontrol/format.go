@@ -0,0 +1,56 @@
+package cruisecontrol
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// FormatOptimizationSummary creates a pretty table from the summary of an optimization.
+func FormatOptimizationSummary(summary OptimizationSummary) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Replica Moves",
+			"Leader Moves",
+			"Data To Move (MB)",
+			"Monitored Partitions",
+			"Excluded Topics",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	table.Append(
+		[]string{
+			fmt.Sprintf("%d", summary.NumReplicaMovements),
+			fmt.Sprintf("%d", summary.NumLeaderMovements),
+			fmt.Sprintf("%.1f", summary.DataToMoveMB),
+			fmt.Sprintf("%.1f%%", summary.MonitoredPartitionsPercentage),
+			strings.Join(summary.ExcludedTopics, ", "),
+		},
+	)
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
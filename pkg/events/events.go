@@ -0,0 +1,198 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/check"
+	"github.com/segmentio/topicctl/pkg/config"
+	log "github.com/sirupsen/logrus"
+)
+
+const publishTimeout = 10 * time.Second
+
+// EventType is a string type that identifies the command that an event is for.
+type EventType string
+
+const (
+	// EventTypeApply is the type of the events published after each topic in an apply run.
+	EventTypeApply EventType = "apply"
+
+	// EventTypeCheck is the type of the events published after each topic is checked.
+	EventTypeCheck EventType = "check"
+)
+
+// Event is the JSON payload of each message published to an events topic. The messages are
+// keyed by topic name so that the events for each topic are ordered.
+type Event struct {
+	Type        EventType `json:"type"`
+	Time        time.Time `json:"time"`
+	Cluster     string    `json:"cluster"`
+	Environment string    `json:"environment"`
+	Topic       string    `json:"topic"`
+
+	// OK is set if the apply succeeded or all of the checks passed.
+	OK bool `json:"ok"`
+
+	// DryRun and Changes are only set for apply events.
+	DryRun  bool     `json:"dryRun,omitempty"`
+	Changes []string `json:"changes,omitempty"`
+
+	// Checks are only set for check events.
+	Checks []CheckResult `json:"checks,omitempty"`
+
+	// Error is set if the apply or check couldn't be completed.
+	Error string `json:"error,omitempty"`
+}
+
+// CheckResult is the outcome of a single check in a check event. Warning is set for results that
+// aren't OK but don't fail the topic's checks.
+type CheckResult struct {
+	Name        string `json:"name"`
+	OK          bool   `json:"ok"`
+	Warning     bool   `json:"warning,omitempty"`
+	Description string `json:"description"`
+}
+
+// ApplyEvent returns the event for an apply of the argument topic diff.
+func ApplyEvent(
+	clusterConfig config.ClusterConfig,
+	diff apply.TopicDiff,
+	dryRun bool,
+	applyErr error,
+) Event {
+	event := Event{
+		Type:        EventTypeApply,
+		Time:        time.Now().UTC(),
+		Cluster:     clusterConfig.Meta.Name,
+		Environment: clusterConfig.Meta.Environment,
+		Topic:       diff.Topic,
+		OK:          applyErr == nil,
+		DryRun:      dryRun,
+		Changes:     apply.DiffChanges(diff),
+	}
+	if applyErr != nil {
+		event.Error = fmt.Sprintf("%+v", applyErr)
+	}
+	return event
+}
+
+// CheckEvent returns the event for the argument check results for a topic.
+func CheckEvent(
+	clusterConfig config.ClusterConfig,
+	topic string,
+	results check.TopicCheckResults,
+	checkErr error,
+) Event {
+	event := Event{
+		Type:        EventTypeCheck,
+		Time:        time.Now().UTC(),
+		Cluster:     clusterConfig.Meta.Name,
+		Environment: clusterConfig.Meta.Environment,
+		Topic:       topic,
+		OK:          checkErr == nil && results.AllOK(),
+		Checks:      []CheckResult{},
+	}
+	for _, result := range results.Results {
+		event.Checks = append(
+			event.Checks,
+			CheckResult{
+				Name:        string(result.Name),
+				OK:          result.OK,
+				Warning:     result.Warning,
+				Description: result.Description,
+			},
+		)
+	}
+	if checkErr != nil {
+		event.Error = fmt.Sprintf("%+v", checkErr)
+	}
+	return event
+}
+
+// Publisher publishes events to the topic configured in a cluster config.
+type Publisher struct {
+	clusterConfig config.ClusterConfig
+}
+
+// NewPublisher returns a Publisher for the events topic in the argument cluster config.
+func NewPublisher(clusterConfig config.ClusterConfig) *Publisher {
+	return &Publisher{
+		clusterConfig: clusterConfig,
+	}
+}
+
+// Enabled returns whether an events topic is configured.
+func (p *Publisher) Enabled() bool {
+	return p.clusterConfig.Spec.Events != nil
+}
+
+// PublishesFor returns whether the argument event should be published. Events for apply
+// dry-runs are only published if the events config allows them.
+func (p *Publisher) PublishesFor(event Event) bool {
+	if !p.Enabled() {
+		return false
+	}
+	return !(event.Type == EventTypeApply && event.DryRun && !p.clusterConfig.Spec.Events.DryRuns)
+}
+
+// Publish writes the argument event to the events topic, if it should be published. Errors are
+// logged instead of returned so that they don't interrupt the command that the event is for.
+func (p *Publisher) Publish(ctx context.Context, event Event) {
+	if !p.PublishesFor(event) {
+		return
+	}
+
+	if err := p.publish(ctx, event); err != nil {
+		log.Warnf(
+			"Could not publish %s event for topic %s to %s: %+v",
+			event.Type,
+			event.Topic,
+			p.clusterConfig.Spec.Events.Topic,
+			err,
+		)
+	}
+}
+
+func (p *Publisher) publish(ctx context.Context, event Event) error {
+	eventsClusterConfig, err := p.clusterConfig.EventsClusterConfig()
+	if err != nil {
+		return err
+	}
+	connector, err := eventsClusterConfig.NewConnector()
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	writer := kafka.NewWriter(
+		kafka.WriterConfig{
+			Brokers:      []string{connector.Config.BrokerAddr},
+			Dialer:       connector.Dialer,
+			Topic:        p.clusterConfig.Spec.Events.Topic,
+			Balancer:     &kafka.Hash{},
+			BatchTimeout: 10 * time.Millisecond,
+			RequiredAcks: int(kafka.RequireAll),
+		},
+	)
+	defer writer.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	return writer.WriteMessages(
+		ctx,
+		kafka.Message{
+			Key:   []byte(event.Topic),
+			Value: value,
+		},
+	)
+}
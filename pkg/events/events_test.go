@@ -0,0 +1,139 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/check"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyEvent(t *testing.T) {
+	clusterConfig := config.ClusterConfig{
+		Meta: config.ClusterMeta{
+			Name:        "test-cluster",
+			Environment: "test-env",
+		},
+	}
+	diff := apply.TopicDiff{
+		Topic: "test-topic",
+		New:   true,
+		NewTopicConfig: kafka.TopicConfig{
+			NumPartitions:     3,
+			ReplicationFactor: 2,
+		},
+	}
+
+	event := ApplyEvent(clusterConfig, diff, true, nil)
+	assert.Equal(t, EventTypeApply, event.Type)
+	assert.Equal(t, "test-cluster", event.Cluster)
+	assert.Equal(t, "test-env", event.Environment)
+	assert.Equal(t, "test-topic", event.Topic)
+	assert.True(t, event.OK)
+	assert.True(t, event.DryRun)
+	assert.Equal(
+		t,
+		[]string{"Create topic with 3 partition(s) and a replication factor of 2"},
+		event.Changes,
+	)
+	assert.Equal(t, "", event.Error)
+
+	event = ApplyEvent(clusterConfig, diff, false, errors.New("apply failed"))
+	assert.False(t, event.OK)
+	assert.Equal(t, "apply failed", event.Error)
+}
+
+func TestCheckEvent(t *testing.T) {
+	clusterConfig := config.ClusterConfig{
+		Meta: config.ClusterMeta{
+			Name:        "test-cluster",
+			Environment: "test-env",
+		},
+	}
+	results := check.TopicCheckResults{
+		Results: []check.TopicCheckResult{
+			{
+				Name:        check.CheckNameConfigCorrect,
+				OK:          true,
+				Description: "config is correct",
+			},
+			{
+				Name:        check.CheckNameReplicasInSync,
+				OK:          false,
+				Description: "replicas are not in-sync",
+			},
+			{
+				Name:        check.CheckNameDeprecationNotExpired,
+				OK:          false,
+				Warning:     true,
+				Description: "topic is past its removal date",
+			},
+		},
+	}
+
+	event := CheckEvent(clusterConfig, "test-topic", results, nil)
+	assert.Equal(t, EventTypeCheck, event.Type)
+	assert.Equal(t, "test-topic", event.Topic)
+	assert.False(t, event.OK)
+	assert.Equal(
+		t,
+		[]CheckResult{
+			{
+				Name:        string(check.CheckNameConfigCorrect),
+				OK:          true,
+				Description: "config is correct",
+			},
+			{
+				Name:        string(check.CheckNameReplicasInSync),
+				OK:          false,
+				Description: "replicas are not in-sync",
+			},
+			{
+				Name:        string(check.CheckNameDeprecationNotExpired),
+				OK:          false,
+				Warning:     true,
+				Description: "topic is past its removal date",
+			},
+		},
+		event.Checks,
+	)
+
+	// Warnings alone don't fail the topic
+	results.Results = []check.TopicCheckResult{results.Results[0], results.Results[2]}
+	assert.True(t, CheckEvent(clusterConfig, "test-topic", results, nil).OK)
+}
+
+func TestPublisherPublishesFor(t *testing.T) {
+	applyEvent := Event{Type: EventTypeApply}
+	dryRunEvent := Event{Type: EventTypeApply, DryRun: true}
+	checkEvent := Event{Type: EventTypeCheck}
+
+	disabled := NewPublisher(config.ClusterConfig{})
+	assert.False(t, disabled.Enabled())
+	assert.False(t, disabled.PublishesFor(applyEvent))
+	assert.False(t, disabled.PublishesFor(checkEvent))
+
+	enabled := NewPublisher(
+		config.ClusterConfig{
+			Spec: config.ClusterSpec{
+				Events: &config.EventsConfig{Topic: "topicctl-events"},
+			},
+		},
+	)
+	assert.True(t, enabled.Enabled())
+	assert.True(t, enabled.PublishesFor(applyEvent))
+	assert.False(t, enabled.PublishesFor(dryRunEvent))
+	assert.True(t, enabled.PublishesFor(checkEvent))
+
+	withDryRuns := NewPublisher(
+		config.ClusterConfig{
+			Spec: config.ClusterSpec{
+				Events: &config.EventsConfig{Topic: "topicctl-events", DryRuns: true},
+			},
+		},
+	)
+	assert.True(t, withDryRuns.PublishesFor(dryRunEvent))
+}
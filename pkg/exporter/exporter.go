@@ -0,0 +1,415 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	log "github.com/sirupsen/logrus"
+)
+
+// Target is a cluster that the exporter collects metrics from.
+type Target struct {
+	ClusterConfig config.ClusterConfig
+	AdminClient   admin.Client
+
+	// TopicConfigs are the configs of the topics managed in the cluster; these are compared
+	// against the cluster state for the config drift metrics.
+	TopicConfigs []config.TopicConfig
+}
+
+// ExporterConfig contains the configuration for an Exporter.
+type ExporterConfig struct {
+	Targets []Target
+
+	// Interval is the amount of time between collections.
+	Interval time.Duration
+
+	// IncludeDrift and IncludeLag control whether the config drift and consumer lag metrics are
+	// collected. These are more expensive to collect than the other metrics since they require
+	// additional requests for each topic and group, respectively.
+	IncludeDrift bool
+	IncludeLag   bool
+
+	// IncludePartitionLag controls whether per-partition consumer lag metrics are collected in
+	// addition to the per-group and per-topic ones.
+	IncludePartitionLag bool
+
+	// LagGroups are the patterns for the IDs of the consumer groups that lag metrics are
+	// collected for. If empty, lag metrics are collected for all groups.
+	LagGroups []*regexp.Regexp
+}
+
+// Exporter periodically collects metrics about the topics and consumer groups in one or more
+// clusters and serves the latest collection in the Prometheus text format. Collections run in
+// the background instead of on each scrape so that slow clusters don't cause scrapes to time
+// out.
+type Exporter struct {
+	config ExporterConfig
+
+	mutex   sync.RWMutex
+	metrics string
+}
+
+var _ http.Handler = (*Exporter)(nil)
+
+// NewExporter returns a new Exporter instance.
+func NewExporter(exporterConfig ExporterConfig) *Exporter {
+	return &Exporter{
+		config: exporterConfig,
+	}
+}
+
+// Run collects the metrics every interval until the argument context is done.
+func (e *Exporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		e.Collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect gets the current metrics for all of the exporter's targets and replaces the ones that
+// are served with them. Errors collecting from a cluster are logged and reflected in the
+// topicctl_cluster_up metric instead of being returned.
+func (e *Exporter) Collect(ctx context.Context) {
+	metrics := newClusterMetrics()
+
+	for _, target := range e.config.Targets {
+		cluster := target.ClusterConfig.Meta.Name
+		startTime := time.Now()
+
+		err := e.collectTarget(ctx, target, metrics)
+		if err != nil {
+			log.Warnf("Could not collect metrics for cluster %s: %+v", cluster, err)
+		}
+
+		metrics.clusterUp.Add(boolValue(err == nil), "cluster", cluster)
+		metrics.collectionDuration.Add(
+			time.Since(startTime).Seconds(),
+			"cluster",
+			cluster,
+		)
+	}
+
+	formatted := FormatMetrics(metrics.all())
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.metrics = formatted
+}
+
+// ServeHTTP writes the metrics from the latest collection to the response.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(e.metrics))
+}
+
+func (e *Exporter) collectTarget(
+	ctx context.Context,
+	target Target,
+	metrics *clusterMetrics,
+) error {
+	cluster := target.ClusterConfig.Meta.Name
+
+	brokers, err := target.AdminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		return err
+	}
+	metrics.addBrokers(cluster, brokers)
+
+	topics, err := target.AdminClient.GetTopics(ctx, nil, true)
+	if err != nil {
+		return err
+	}
+	metrics.addTopics(cluster, topics)
+
+	if e.config.IncludeDrift {
+		for _, topicConfig := range target.TopicConfigs {
+			diff, err := topicDiff(ctx, target, topicConfig)
+			if err != nil {
+				log.Warnf(
+					"Could not get config drift for topic %s in cluster %s: %+v",
+					topicConfig.Meta.Name,
+					cluster,
+					err,
+				)
+				continue
+			}
+			metrics.addDiff(cluster, diff)
+		}
+	}
+
+	if e.config.IncludeLag {
+		connector := target.AdminClient.GetConnector()
+
+		groupCoordinators, err := groups.GetGroups(ctx, connector)
+		if err != nil {
+			return err
+		}
+
+		for _, groupCoordinator := range groupCoordinators {
+			if !e.lagGroupAllowed(groupCoordinator.GroupID) {
+				continue
+			}
+
+			partitionLags, err := groups.GetGroupLags(ctx, connector, groupCoordinator.GroupID)
+			if err != nil {
+				log.Debugf(
+					"Could not get lags for group %s in cluster %s: %+v",
+					groupCoordinator.GroupID,
+					cluster,
+					err,
+				)
+				continue
+			}
+			metrics.addGroupLags(
+				cluster,
+				groupCoordinator.GroupID,
+				partitionLags,
+				e.config.IncludePartitionLag,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) lagGroupAllowed(groupID string) bool {
+	if len(e.config.LagGroups) == 0 {
+		return true
+	}
+
+	for _, lagGroup := range e.config.LagGroups {
+		if lagGroup.MatchString(groupID) {
+			return true
+		}
+	}
+	return false
+}
+
+func topicDiff(
+	ctx context.Context,
+	target Target,
+	topicConfig config.TopicConfig,
+) (apply.TopicDiff, error) {
+	if err := topicConfig.ResolveSettingsProfile(target.ClusterConfig); err != nil {
+		return apply.TopicDiff{}, err
+	}
+	topicConfig.SetDefaults()
+
+	applier, err := apply.NewTopicApplier(
+		ctx,
+		target.AdminClient,
+		apply.TopicApplierConfig{
+			ClusterConfig: target.ClusterConfig,
+			DryRun:        true,
+			TopicConfig:   topicConfig,
+		},
+	)
+	if err != nil {
+		return apply.TopicDiff{}, err
+	}
+
+	return applier.Diff(ctx)
+}
+
+// clusterMetrics contains all of the metrics collected by the exporter.
+type clusterMetrics struct {
+	clusterUp          *Metric
+	collectionDuration *Metric
+
+	brokerThrottled *Metric
+
+	topicPartitions                *Metric
+	topicUnderReplicatedPartitions *Metric
+	topicThrottled                 *Metric
+	topicConfigDrift               *Metric
+
+	consumerGroupLag          *Metric
+	consumerGroupTotalLag     *Metric
+	consumerGroupPartitionLag *Metric
+}
+
+func newClusterMetrics() *clusterMetrics {
+	return &clusterMetrics{
+		clusterUp: &Metric{
+			Name: "topicctl_cluster_up",
+			Help: "Whether the last collection from the cluster succeeded.",
+			Type: MetricTypeGauge,
+		},
+		collectionDuration: &Metric{
+			Name: "topicctl_collection_duration_seconds",
+			Help: "Time taken by the last collection from the cluster.",
+			Type: MetricTypeGauge,
+		},
+		brokerThrottled: &Metric{
+			Name: "topicctl_broker_throttled",
+			Help: "Whether the broker has replication throttles set.",
+			Type: MetricTypeGauge,
+		},
+		topicPartitions: &Metric{
+			Name: "topicctl_topic_partitions",
+			Help: "Number of partitions in the topic.",
+			Type: MetricTypeGauge,
+		},
+		topicUnderReplicatedPartitions: &Metric{
+			Name: "topicctl_topic_under_replicated_partitions",
+			Help: "Number of partitions in the topic whose ISR doesn't contain all replicas.",
+			Type: MetricTypeGauge,
+		},
+		topicThrottled: &Metric{
+			Name: "topicctl_topic_throttled",
+			Help: "Whether the topic has replication throttles set.",
+			Type: MetricTypeGauge,
+		},
+		topicConfigDrift: &Metric{
+			Name: "topicctl_topic_config_drift",
+			Help: "Whether applying the topic's config would change the topic.",
+			Type: MetricTypeGauge,
+		},
+		consumerGroupLag: &Metric{
+			Name: "topicctl_consumer_group_lag",
+			Help: "Total number of messages in the topic after the group's committed offsets.",
+			Type: MetricTypeGauge,
+		},
+		consumerGroupTotalLag: &Metric{
+			Name: "topicctl_consumer_group_total_lag",
+			Help: "Total number of messages after the group's committed offsets in all topics.",
+			Type: MetricTypeGauge,
+		},
+		consumerGroupPartitionLag: &Metric{
+			Name: "topicctl_consumer_group_partition_lag",
+			Help: "Number of messages in the partition after the group's committed offset.",
+			Type: MetricTypeGauge,
+		},
+	}
+}
+
+func (c *clusterMetrics) all() []*Metric {
+	return []*Metric{
+		c.clusterUp,
+		c.collectionDuration,
+		c.brokerThrottled,
+		c.topicPartitions,
+		c.topicUnderReplicatedPartitions,
+		c.topicThrottled,
+		c.topicConfigDrift,
+		c.consumerGroupLag,
+		c.consumerGroupTotalLag,
+		c.consumerGroupPartitionLag,
+	}
+}
+
+func (c *clusterMetrics) addBrokers(cluster string, brokers []admin.BrokerInfo) {
+	for _, broker := range brokers {
+		c.brokerThrottled.Add(
+			boolValue(broker.IsThrottled()),
+			"cluster",
+			cluster,
+			"broker",
+			strconv.Itoa(broker.ID),
+		)
+	}
+}
+
+func (c *clusterMetrics) addTopics(cluster string, topics []admin.TopicInfo) {
+	for _, topic := range topics {
+		c.topicPartitions.Add(
+			float64(len(topic.Partitions)),
+			"cluster",
+			cluster,
+			"topic",
+			topic.Name,
+		)
+		c.topicUnderReplicatedPartitions.Add(
+			float64(len(topic.OutOfSyncPartitions(nil))),
+			"cluster",
+			cluster,
+			"topic",
+			topic.Name,
+		)
+		c.topicThrottled.Add(
+			boolValue(topic.IsThrottled()),
+			"cluster",
+			cluster,
+			"topic",
+			topic.Name,
+		)
+	}
+}
+
+func (c *clusterMetrics) addDiff(cluster string, diff apply.TopicDiff) {
+	c.topicConfigDrift.Add(
+		boolValue(diff.HasDiffs()),
+		"cluster",
+		cluster,
+		"topic",
+		diff.Topic,
+	)
+}
+
+func (c *clusterMetrics) addGroupLags(
+	cluster string,
+	groupID string,
+	partitionLags []groups.PartitionLag,
+	includePartitions bool,
+) {
+	topicLags := map[string][]groups.PartitionLag{}
+	for _, partitionLag := range partitionLags {
+		topicLags[partitionLag.Topic] = append(topicLags[partitionLag.Topic], partitionLag)
+
+		if includePartitions {
+			c.consumerGroupPartitionLag.Add(
+				float64(groups.TotalLag([]groups.PartitionLag{partitionLag})),
+				"cluster",
+				cluster,
+				"group",
+				groupID,
+				"topic",
+				partitionLag.Topic,
+				"partition",
+				strconv.Itoa(partitionLag.Partition),
+			)
+		}
+	}
+
+	if len(partitionLags) > 0 {
+		c.consumerGroupTotalLag.Add(
+			float64(groups.TotalLag(partitionLags)),
+			"cluster",
+			cluster,
+			"group",
+			groupID,
+		)
+	}
+
+	for topic, lags := range topicLags {
+		c.consumerGroupLag.Add(
+			float64(groups.TotalLag(lags)),
+			"cluster",
+			cluster,
+			"group",
+			groupID,
+			"topic",
+			topic,
+		)
+	}
+}
@@ -0,0 +1,175 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/groups"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterMetrics(t *testing.T) {
+	metrics := newClusterMetrics()
+
+	metrics.addBrokers(
+		"test-cluster",
+		[]admin.BrokerInfo{
+			{
+				ID: 1,
+				Config: map[string]string{
+					admin.LeaderThrottledKey: "1000000",
+				},
+			},
+			{
+				ID: 2,
+			},
+		},
+	)
+	metrics.addTopics(
+		"test-cluster",
+		[]admin.TopicInfo{
+			{
+				Name: "test-topic",
+				Config: map[string]string{
+					admin.LeaderReplicasThrottledKey: "0:1",
+				},
+				Partitions: []admin.PartitionInfo{
+					{
+						ID:       0,
+						Leader:   1,
+						Replicas: []int{1, 2},
+						ISR:      []int{1, 2},
+					},
+					{
+						ID:       1,
+						Leader:   2,
+						Replicas: []int{2, 1},
+						ISR:      []int{2},
+					},
+				},
+			},
+		},
+	)
+	metrics.addDiff(
+		"test-cluster",
+		apply.TopicDiff{
+			Topic:             "test-topic",
+			CurrPartitions:    2,
+			DesiredPartitions: 3,
+		},
+	)
+	metrics.addGroupLags(
+		"test-cluster",
+		"test-group",
+		[]groups.PartitionLag{
+			{
+				Topic:           "test-topic",
+				Partition:       0,
+				CommittedOffset: 5,
+				EndOffset:       10,
+			},
+			{
+				Topic:           "test-topic",
+				Partition:       1,
+				CommittedOffset: 3,
+				EndOffset:       4,
+			},
+		},
+		true,
+	)
+	metrics.addGroupLags(
+		"test-cluster",
+		"other-group",
+		[]groups.PartitionLag{
+			{
+				Topic:           "test-topic",
+				Partition:       0,
+				CommittedOffset: 8,
+				EndOffset:       10,
+			},
+		},
+		false,
+	)
+
+	formatted := FormatMetrics(metrics.all())
+	for _, line := range []string{
+		`topicctl_broker_throttled{cluster="test-cluster",broker="1"} 1`,
+		`topicctl_broker_throttled{cluster="test-cluster",broker="2"} 0`,
+		`topicctl_topic_partitions{cluster="test-cluster",topic="test-topic"} 2`,
+		`topicctl_topic_under_replicated_partitions{cluster="test-cluster",topic="test-topic"} 1`,
+		`topicctl_topic_throttled{cluster="test-cluster",topic="test-topic"} 1`,
+		`topicctl_topic_config_drift{cluster="test-cluster",topic="test-topic"} 1`,
+		`topicctl_consumer_group_lag{cluster="test-cluster",group="test-group",topic="test-topic"} 6`,
+		`topicctl_consumer_group_total_lag{cluster="test-cluster",group="test-group"} 6`,
+		`topicctl_consumer_group_partition_lag{cluster="test-cluster",group="test-group",topic="test-topic",partition="0"} 5`,
+		`topicctl_consumer_group_partition_lag{cluster="test-cluster",group="test-group",topic="test-topic",partition="1"} 1`,
+		`topicctl_consumer_group_total_lag{cluster="test-cluster",group="other-group"} 2`,
+	} {
+		assert.Contains(t, formatted, line+"\n")
+	}
+	assert.False(
+		t,
+		strings.Contains(formatted, `topicctl_consumer_group_partition_lag{cluster="test-cluster",group="other-group"`),
+	)
+
+	// No samples were added for these
+	assert.False(t, strings.Contains(formatted, "topicctl_cluster_up"))
+}
+
+func TestExporterLagGroupAllowed(t *testing.T) {
+	assert.True(t, NewExporter(ExporterConfig{}).lagGroupAllowed("any-group"))
+
+	exporter := NewExporter(
+		ExporterConfig{
+			LagGroups: []*regexp.Regexp{
+				regexp.MustCompile(`^payments-`),
+				regexp.MustCompile(`^search$`),
+			},
+		},
+	)
+	assert.True(t, exporter.lagGroupAllowed("payments-worker"))
+	assert.True(t, exporter.lagGroupAllowed("search"))
+	assert.False(t, exporter.lagGroupAllowed("search-indexer"))
+	assert.False(t, exporter.lagGroupAllowed("console-consumer-1234"))
+}
+
+func TestTopicDiffResolvesProfile(t *testing.T) {
+	topicConfig := config.TopicConfig{
+		Meta: config.TopicMeta{
+			Name: "test-topic",
+		},
+		Spec: config.TopicSpec{
+			Profile: "unknown-profile",
+		},
+	}
+
+	// The profile is resolved before the cluster is contacted, so this fails without an admin
+	// client.
+	_, err := topicDiff(context.Background(), Target{}, topicConfig)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unknown settings profile 'unknown-profile'")
+}
+
+func TestExporterServeHTTP(t *testing.T) {
+	exporter := NewExporter(ExporterConfig{})
+	exporter.metrics = "topicctl_cluster_up{cluster=\"test-cluster\"} 1\n"
+
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(
+		t,
+		"text/plain; version=0.0.4; charset=utf-8",
+		recorder.Header().Get("Content-Type"),
+	)
+	assert.Equal(t, "topicctl_cluster_up{cluster=\"test-cluster\"} 1\n", recorder.Body.String())
+}
@@ -0,0 +1,145 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MetricType is a string type that identifies the Prometheus type of a metric.
+type MetricType string
+
+const (
+	// MetricTypeGauge is a metric whose value can go up and down.
+	MetricTypeGauge MetricType = "gauge"
+)
+
+// Label is a single Prometheus label on a metric sample.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a metric, identified by its labels.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Metric is a named set of samples along with the details that Prometheus needs to interpret
+// them.
+type Metric struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// Add appends a sample with the argument value and label name, value pairs to the metric.
+func (m *Metric) Add(value float64, labelPairs ...string) {
+	labels := []Label{}
+	for l := 0; l+1 < len(labelPairs); l += 2 {
+		labels = append(
+			labels,
+			Label{
+				Name:  labelPairs[l],
+				Value: labelPairs[l+1],
+			},
+		)
+	}
+
+	m.Samples = append(
+		m.Samples,
+		Sample{
+			Labels: labels,
+			Value:  value,
+		},
+	)
+}
+
+// FormatMetrics converts the argument metrics to the Prometheus text exposition format.
+// Metrics without any samples are omitted, and the samples in each metric are sorted by their
+// labels so that the output is stable across collections.
+func FormatMetrics(metrics []*Metric) string {
+	buf := &bytes.Buffer{}
+
+	for _, metric := range metrics {
+		if len(metric.Samples) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(buf, "# HELP %s %s\n", metric.Name, escapeHelp(metric.Help))
+		fmt.Fprintf(buf, "# TYPE %s %s\n", metric.Name, metric.Type)
+
+		lines := []string{}
+		for _, sample := range metric.Samples {
+			lines = append(
+				lines,
+				fmt.Sprintf(
+					"%s%s %s",
+					metric.Name,
+					formatLabels(sample.Labels),
+					formatValue(sample.Value),
+				),
+			)
+		}
+		sort.Strings(lines)
+
+		for _, line := range lines {
+			fmt.Fprintf(buf, "%s\n", line)
+		}
+	}
+
+	return buf.String()
+}
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	elements := []string{}
+	for _, label := range labels {
+		elements = append(
+			elements,
+			fmt.Sprintf("%s=\"%s\"", label.Name, escapeLabelValue(label.Value)),
+		)
+	}
+	return fmt.Sprintf("{%s}", strings.Join(elements, ","))
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
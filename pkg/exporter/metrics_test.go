@@ -0,0 +1,46 @@
+package exporter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMetrics(t *testing.T) {
+	partitions := &Metric{
+		Name: "topicctl_topic_partitions",
+		Help: "Number of partitions in the topic.",
+		Type: MetricTypeGauge,
+	}
+	partitions.Add(6, "cluster", "test-cluster", "topic", "topic-b")
+	partitions.Add(3, "cluster", "test-cluster", "topic", "topic-a")
+
+	lag := &Metric{
+		Name: "topicctl_consumer_group_lag",
+		Help: "Lag with a \\ and\na newline.",
+		Type: MetricTypeGauge,
+	}
+	lag.Add(1.5e10, "group", "group \"quoted\"")
+	lag.Add(math.NaN(), "group", "other-group")
+
+	empty := &Metric{
+		Name: "topicctl_empty",
+		Help: "Metric without samples.",
+		Type: MetricTypeGauge,
+	}
+
+	assert.Equal(
+		t,
+		`# HELP topicctl_topic_partitions Number of partitions in the topic.
+# TYPE topicctl_topic_partitions gauge
+topicctl_topic_partitions{cluster="test-cluster",topic="topic-a"} 3
+topicctl_topic_partitions{cluster="test-cluster",topic="topic-b"} 6
+# HELP topicctl_consumer_group_lag Lag with a \\ and\na newline.
+# TYPE topicctl_consumer_group_lag gauge
+topicctl_consumer_group_lag{group="group \"quoted\""} 1.5e+10
+topicctl_consumer_group_lag{group="other-group"} NaN
+`,
+		FormatMetrics([]*Metric{partitions, lag, empty}),
+	)
+}
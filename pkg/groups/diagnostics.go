@@ -0,0 +1,262 @@
+package groups
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TopicAssignment summarizes how the partitions of a single topic are assigned across the
+// members of a consumer group that subscribe to it.
+type TopicAssignment struct {
+	Topic string `json:"topic"`
+
+	// Partitions is the number of partitions in the topic, or -1 if it isn't known.
+	Partitions int `json:"partitions"`
+
+	// Assigned is the number of the topic's partitions that are assigned to a member.
+	Assigned int `json:"assigned"`
+
+	// Members is the number of members that subscribe to the topic, and MinPerMember and
+	// MaxPerMember are the fewest and most partitions that are assigned to any of them.
+	Members      int `json:"members"`
+	MinPerMember int `json:"minPerMember"`
+	MaxPerMember int `json:"maxPerMember"`
+}
+
+// Uneven returns whether some subscribed members have more than one partition more than others,
+// which shouldn't happen with any of the standard assignors.
+func (t TopicAssignment) Uneven() bool {
+	return t.MaxPerMember-t.MinPerMember > 1
+}
+
+// Unassigned returns the number of partitions in the topic that aren't assigned to any member.
+func (t TopicAssignment) Unassigned() int {
+	if t.Partitions < 0 || t.Assigned >= t.Partitions {
+		return 0
+	}
+	return t.Partitions - t.Assigned
+}
+
+// TopicAssignments returns the assignment summary for each topic that the members of the
+// argument group subscribe to, sorted by topic. topicPartitions maps each topic to its number of
+// partitions; the topics that aren't in it are treated as having an unknown number.
+func TopicAssignments(
+	groupDetails GroupDetails,
+	topicPartitions map[string]int,
+) []TopicAssignment {
+	assignmentsByTopic := map[string]*TopicAssignment{}
+	assignedPartitions := map[string]map[int]struct{}{}
+
+	for _, member := range groupDetails.Members {
+		for topic, partitions := range member.TopicPartitions {
+			assignment, ok := assignmentsByTopic[topic]
+			if !ok {
+				numPartitions, ok := topicPartitions[topic]
+				if !ok {
+					numPartitions = -1
+				}
+				assignment = &TopicAssignment{
+					Topic:        topic,
+					Partitions:   numPartitions,
+					MinPerMember: len(partitions),
+				}
+				assignmentsByTopic[topic] = assignment
+				assignedPartitions[topic] = map[int]struct{}{}
+			}
+
+			assignment.Members++
+			if len(partitions) < assignment.MinPerMember {
+				assignment.MinPerMember = len(partitions)
+			}
+			if len(partitions) > assignment.MaxPerMember {
+				assignment.MaxPerMember = len(partitions)
+			}
+			for _, partition := range partitions {
+				assignedPartitions[topic][partition] = struct{}{}
+			}
+		}
+	}
+
+	assignments := []TopicAssignment{}
+	for topic, assignment := range assignmentsByTopic {
+		assignment.Assigned = len(assignedPartitions[topic])
+		assignments = append(assignments, *assignment)
+	}
+
+	sort.Slice(assignments, func(a, b int) bool {
+		return assignments[a].Topic < assignments[b].Topic
+	})
+
+	return assignments
+}
+
+// MembershipChanges are the differences between two samples of a consumer group's members.
+type MembershipChanges struct {
+	// Joined and Left are the IDs of the members that are only in the second and first samples,
+	// respectively.
+	Joined []string `json:"joined"`
+	Left   []string `json:"left"`
+
+	// Reassigned are the IDs of the members that are in both samples but whose partition
+	// assignments changed.
+	Reassigned []string `json:"reassigned"`
+}
+
+// Empty returns whether there aren't any changes.
+func (m MembershipChanges) Empty() bool {
+	return len(m.Joined) == 0 && len(m.Left) == 0 && len(m.Reassigned) == 0
+}
+
+// CompareMembers compares two samples of a consumer group's details. Since the admin APIs don't
+// expose the group's generation, changes in membership and assignments are the best available
+// evidence that the group rebalanced between the samples. The member IDs in each result field
+// are sorted.
+func CompareMembers(first GroupDetails, second GroupDetails) MembershipChanges {
+	firstMembers := map[string]MemberInfo{}
+	for _, member := range first.Members {
+		firstMembers[member.MemberID] = member
+	}
+	secondMembers := map[string]MemberInfo{}
+	for _, member := range second.Members {
+		secondMembers[member.MemberID] = member
+	}
+
+	changes := MembershipChanges{
+		Joined:     []string{},
+		Left:       []string{},
+		Reassigned: []string{},
+	}
+
+	for memberID, secondMember := range secondMembers {
+		firstMember, ok := firstMembers[memberID]
+		if !ok {
+			changes.Joined = append(changes.Joined, memberID)
+		} else if !reflect.DeepEqual(firstMember.TopicPartitions, secondMember.TopicPartitions) {
+			changes.Reassigned = append(changes.Reassigned, memberID)
+		}
+	}
+	for memberID := range firstMembers {
+		if _, ok := secondMembers[memberID]; !ok {
+			changes.Left = append(changes.Left, memberID)
+		}
+	}
+
+	sort.Strings(changes.Joined)
+	sort.Strings(changes.Left)
+	sort.Strings(changes.Reassigned)
+
+	return changes
+}
+
+// IsRebalancing returns whether the argument group state is one of the states that a group is
+// in while it rebalances.
+func IsRebalancing(state string) bool {
+	return state == "PreparingRebalance" || state == "CompletingRebalance"
+}
+
+// AssignmentWarnings returns descriptions of the problems with the argument group's assignment
+// setup that waste capacity or cause rebalance churn:
+//
+//  1. Members that encode their subscriptions with different consumer protocol versions, which
+//     usually means that they run different clients or assignors, e.g. partway through a
+//     migration from an eager assignor to a cooperative one.
+//  2. A cooperative assignment strategy with members whose subscriptions are too old to
+//     include their owned partitions, which cooperative rebalancing depends on.
+//  3. Members that subscribe to different sets of topics.
+//  4. More members subscribed to a topic than the topic has partitions, which leaves some of
+//     them idle.
+//
+// Brokers only return each member's metadata for the strategy that the group chose, so the full
+// list of strategies that each member advertises isn't available. The last warning is only
+// generated for the topics in topicPartitions, which maps topic names to partition counts.
+func AssignmentWarnings(
+	groupDetails GroupDetails,
+	topicPartitions map[string]int,
+) []string {
+	warnings := []string{}
+	if len(groupDetails.Members) == 0 {
+		return warnings
+	}
+
+	versionCounts := map[int]int{}
+	subscriptionCounts := map[string]int{}
+	topicMembers := map[string]int{}
+
+	for _, member := range groupDetails.Members {
+		versionCounts[member.SubscriptionVersion]++
+		subscriptionCounts[strings.Join(member.SubscribedTopics, ",")]++
+		for _, topic := range member.SubscribedTopics {
+			topicMembers[topic]++
+		}
+	}
+
+	if len(versionCounts) > 1 {
+		versions := []int{}
+		for version := range versionCounts {
+			versions = append(versions, version)
+		}
+		sort.Ints(versions)
+
+		versionStrs := []string{}
+		for _, version := range versions {
+			versionStrs = append(
+				versionStrs,
+				fmt.Sprintf("v%d (%d)", version, versionCounts[version]),
+			)
+		}
+		warnings = append(
+			warnings,
+			fmt.Sprintf(
+				"members use mixed subscription versions %s, e.g. from different clients or assignors",
+				strings.Join(versionStrs, ", "),
+			),
+		)
+	}
+
+	if strings.Contains(groupDetails.AssignmentStrategy, "cooperative") && versionCounts[0] > 0 {
+		warnings = append(
+			warnings,
+			fmt.Sprintf(
+				"strategy %s needs owned partitions, but %d member(s) use v0 subscriptions that don't include them",
+				groupDetails.AssignmentStrategy,
+				versionCounts[0],
+			),
+		)
+	}
+
+	if len(subscriptionCounts) > 1 {
+		warnings = append(
+			warnings,
+			fmt.Sprintf(
+				"members subscribe to %d different sets of topics",
+				len(subscriptionCounts),
+			),
+		)
+	}
+
+	topics := []string{}
+	for topic := range topicMembers {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		partitions, ok := topicPartitions[topic]
+		if !ok || topicMembers[topic] <= partitions {
+			continue
+		}
+		warnings = append(
+			warnings,
+			fmt.Sprintf(
+				"%d members subscribe to topic %s, which only has %d partitions",
+				topicMembers[topic],
+				topic,
+				partitions,
+			),
+		)
+	}
+
+	return warnings
+}
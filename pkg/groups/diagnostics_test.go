@@ -0,0 +1,180 @@
+package groups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicAssignments(t *testing.T) {
+	groupDetails := GroupDetails{
+		GroupID: "group1",
+		Members: []MemberInfo{
+			{
+				MemberID: "member1",
+				TopicPartitions: map[string][]int{
+					"topic1": {0, 1, 2, 3},
+					"topic2": {0},
+				},
+			},
+			{
+				MemberID: "member2",
+				TopicPartitions: map[string][]int{
+					"topic1": {4},
+					"topic2": {1},
+				},
+			},
+			{
+				MemberID: "member3",
+				TopicPartitions: map[string][]int{
+					"topic1": {5},
+					"topic3": {},
+				},
+			},
+		},
+	}
+
+	assignments := TopicAssignments(
+		groupDetails,
+		map[string]int{
+			"topic1": 6,
+			"topic2": 4,
+		},
+	)
+	assert.Equal(
+		t,
+		[]TopicAssignment{
+			{
+				Topic:        "topic1",
+				Partitions:   6,
+				Assigned:     6,
+				Members:      3,
+				MinPerMember: 1,
+				MaxPerMember: 4,
+			},
+			{
+				Topic:        "topic2",
+				Partitions:   4,
+				Assigned:     2,
+				Members:      2,
+				MinPerMember: 1,
+				MaxPerMember: 1,
+			},
+			{
+				Topic:        "topic3",
+				Partitions:   -1,
+				Assigned:     0,
+				Members:      1,
+				MinPerMember: 0,
+				MaxPerMember: 0,
+			},
+		},
+		assignments,
+	)
+
+	assert.True(t, assignments[0].Uneven())
+	assert.Equal(t, 0, assignments[0].Unassigned())
+	assert.False(t, assignments[1].Uneven())
+	assert.Equal(t, 2, assignments[1].Unassigned())
+	assert.Equal(t, 0, assignments[2].Unassigned())
+}
+
+func TestCompareMembers(t *testing.T) {
+	first := GroupDetails{
+		Members: []MemberInfo{
+			{
+				MemberID:        "member1",
+				TopicPartitions: map[string][]int{"topic1": {0, 1}},
+			},
+			{
+				MemberID:        "member2",
+				TopicPartitions: map[string][]int{"topic1": {2, 3}},
+			},
+			{
+				MemberID:        "member3",
+				TopicPartitions: map[string][]int{"topic1": {4}},
+			},
+		},
+	}
+	second := GroupDetails{
+		Members: []MemberInfo{
+			{
+				MemberID:        "member1",
+				TopicPartitions: map[string][]int{"topic1": {0, 1}},
+			},
+			{
+				MemberID:        "member2",
+				TopicPartitions: map[string][]int{"topic1": {2, 3, 4}},
+			},
+			{
+				MemberID:        "member4",
+				TopicPartitions: map[string][]int{"topic1": {}},
+			},
+		},
+	}
+
+	changes := CompareMembers(first, second)
+	assert.Equal(
+		t,
+		MembershipChanges{
+			Joined:     []string{"member4"},
+			Left:       []string{"member3"},
+			Reassigned: []string{"member2"},
+		},
+		changes,
+	)
+	assert.False(t, changes.Empty())
+	assert.True(t, CompareMembers(first, first).Empty())
+}
+
+func TestAssignmentWarnings(t *testing.T) {
+	assert.Equal(t, []string{}, AssignmentWarnings(GroupDetails{}, nil))
+
+	healthy := GroupDetails{
+		AssignmentStrategy: "cooperative-sticky",
+		Members: []MemberInfo{
+			{
+				MemberID:            "member1",
+				SubscribedTopics:    []string{"topic1"},
+				SubscriptionVersion: 1,
+			},
+			{
+				MemberID:            "member2",
+				SubscribedTopics:    []string{"topic1"},
+				SubscriptionVersion: 1,
+			},
+		},
+	}
+	assert.Equal(t, []string{}, AssignmentWarnings(healthy, map[string]int{"topic1": 2}))
+
+	unhealthy := GroupDetails{
+		AssignmentStrategy: "cooperative-sticky",
+		Members: []MemberInfo{
+			{
+				MemberID:            "member1",
+				SubscribedTopics:    []string{"topic1", "topic2"},
+				SubscriptionVersion: 1,
+			},
+			{
+				MemberID:            "member2",
+				SubscribedTopics:    []string{"topic1"},
+				SubscriptionVersion: 0,
+			},
+			{
+				MemberID:            "member3",
+				SubscribedTopics:    []string{"topic1"},
+				SubscriptionVersion: 1,
+			},
+		},
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"members use mixed subscription versions v0 (1), v1 (2), e.g. from different clients or assignors",
+			"strategy cooperative-sticky needs owned partitions, but 1 member(s) use v0 subscriptions that don't include them",
+			"members subscribe to 2 different sets of topics",
+			"3 members subscribe to topic topic1, which only has 2 partitions",
+		},
+		AssignmentWarnings(unhealthy, map[string]int{"topic1": 2}),
+	)
+}
@@ -52,19 +52,90 @@ func FormatGroupCoordinators(groupCoordinators []GroupCoordinator) string {
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatGroupMembers generates a pretty table from a slice of MemberInfo details.
-func FormatGroupMembers(members []MemberInfo, full bool) string {
+// FormatGroupSummaries generates a pretty table from the results of a call to
+// GetGroupSummaries.
+func FormatGroupSummaries(groupSummaries []GroupSummary) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
 	table.SetHeader(
 		[]string{
-			"Member ID",
-			"Client Host",
-			"Num\nPartitions",
-			"Partition\nAssignments",
+			"Group",
+			"State",
+			"Protocol",
+			"Members",
+			"Coordinator",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
 		},
 	)
+
+	for _, groupSummary := range groupSummaries {
+		protocol := groupSummary.ProtocolType
+		if groupSummary.Protocol != "" {
+			protocol = fmt.Sprintf("%s (%s)", protocol, groupSummary.Protocol)
+		}
+
+		table.Append(
+			[]string{
+				groupSummary.GroupID,
+				groupSummary.State,
+				protocol,
+				fmt.Sprintf("%d", groupSummary.Members),
+				fmt.Sprintf("%d", groupSummary.Coordinator),
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatGroupMembers generates a pretty table from a slice of MemberInfo details.
+func FormatGroupMembers(members []MemberInfo, full bool) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+
+	var hasInstanceIDs bool
+	for _, member := range members {
+		if member.GroupInstanceID != "" {
+			hasInstanceIDs = true
+			break
+		}
+	}
+
+	headers := []string{
+		"Member ID",
+		"Client ID",
+		"Client Host",
+	}
+	if hasInstanceIDs {
+		headers = append(headers, "Instance ID")
+	}
+	headers = append(
+		headers,
+		"Num\nPartitions",
+		"Partition\nAssignments",
+	)
+
+	table.SetHeader(headers)
 	table.SetAutoWrapText(true)
 	table.SetColumnAlignment(
 		[]int{
@@ -73,6 +144,7 @@ func FormatGroupMembers(members []MemberInfo, full bool) string {
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -105,14 +177,21 @@ func FormatGroupMembers(members []MemberInfo, full bool) string {
 			totalPartitions += len(partitions)
 		}
 
-		table.Append(
-			[]string{
-				memberID,
-				clientHost,
-				fmt.Sprintf("%d", totalPartitions),
-				fmt.Sprintf("%+v", member.TopicPartitions),
-			},
+		row := []string{
+			memberID,
+			member.ClientID,
+			clientHost,
+		}
+		if hasInstanceIDs {
+			row = append(row, member.GroupInstanceID)
+		}
+		row = append(
+			row,
+			fmt.Sprintf("%d", totalPartitions),
+			fmt.Sprintf("%+v", member.TopicPartitions),
 		)
+
+		table.Append(row)
 	}
 
 	table.Render()
@@ -181,23 +260,114 @@ func FormatMemberPartitionCounts(members []MemberInfo) string {
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatMemberLags generates a pretty table from the results of GetMemberLags.
-func FormatMemberLags(memberLags []MemberPartitionLag, full bool) string {
+// FormatTopicAssignments generates a pretty table from the results of TopicAssignments, flagging
+// the topics whose partitions are unevenly spread across members or aren't all assigned.
+func FormatTopicAssignments(assignments []TopicAssignment) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
 	table.SetHeader(
 		[]string{
-			"Partition",
-			"Member ID",
-			"Member Offset",
-			"Member Time",
-			"Latest Offset",
-			"Latest Time",
-			"Offset Lag",
-			"Time Lag",
+			"Topic",
+			"Num Partitions",
+			"Num Members",
+			"Min Per Member",
+			"Max Per Member",
+			"Status",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, assignment := range assignments {
+		partitionsStr := fmt.Sprintf("%d", assignment.Partitions)
+		if assignment.Partitions < 0 {
+			partitionsStr = "?"
+		}
+
+		statuses := []string{}
+		if assignment.Uneven() {
+			statuses = append(statuses, "uneven")
+		}
+		if unassigned := assignment.Unassigned(); unassigned > 0 {
+			statuses = append(statuses, fmt.Sprintf("%d unassigned", unassigned))
+		}
+
+		var statusStr string
+		if len(statuses) == 0 {
+			statusStr = "ok"
+		} else {
+			statusStr = strings.Join(statuses, ", ")
+			if util.ColorEnabled() {
+				statusStr = color.New(color.FgRed).Sprint(statusStr)
+			}
+		}
+
+		table.Append(
+			[]string{
+				assignment.Topic,
+				partitionsStr,
+				fmt.Sprintf("%d", assignment.Members),
+				fmt.Sprintf("%d", assignment.MinPerMember),
+				fmt.Sprintf("%d", assignment.MaxPerMember),
+				statusStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatMemberLags generates a pretty table from the results of GetMemberLags.
+func FormatMemberLags(memberLags []MemberPartitionLag, full bool) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+
+	var hasInstanceIDs bool
+	for _, memberLag := range memberLags {
+		if memberLag.InstanceID != "" {
+			hasInstanceIDs = true
+			break
+		}
+	}
+
+	headers := []string{
+		"Partition",
+		"Member ID",
+	}
+	if hasInstanceIDs {
+		headers = append(headers, "Instance ID")
+	}
+	headers = append(
+		headers,
+		"Member Offset",
+		"Member Time",
+		"Latest Offset",
+		"Latest Time",
+		"Offset Lag",
+		"Time Lag",
+	)
+
+	table.SetHeader(headers)
 	table.SetAutoWrapText(true)
 	table.SetColumnAlignment(
 		[]int{
@@ -209,6 +379,7 @@ func FormatMemberLags(memberLags []MemberPartitionLag, full bool) string {
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -232,7 +403,7 @@ func FormatMemberLags(memberLags []MemberPartitionLag, full bool) string {
 		}
 
 		var memberIDPrinter func(f string, a ...interface{}) string
-		if !util.InTerminal() || memberID != "" {
+		if !util.ColorEnabled() || memberID != "" {
 			memberIDPrinter = fmt.Sprintf
 		} else {
 			memberID = "None"
@@ -249,16 +420,71 @@ func FormatMemberLags(memberLags []MemberPartitionLag, full bool) string {
 			timeLagStr = util.PrettyDuration(memberLag.TimeLag())
 		}
 
+		row := []string{
+			fmt.Sprintf("%d", memberLag.Partition),
+			memberIDPrinter("%s", memberID),
+		}
+		if hasInstanceIDs {
+			row = append(row, memberLag.InstanceID)
+		}
+		row = append(
+			row,
+			fmt.Sprintf("%d", memberLag.MemberOffset),
+			memberTimeStr,
+			fmt.Sprintf("%d", memberLag.NewestOffset),
+			memberLag.NewestTime.Format(time.RFC3339),
+			fmt.Sprintf("%d", memberLag.OffsetLag()),
+			timeLagStr,
+		)
+
+		table.Append(row)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatPartitionLags generates a pretty table from the results of GetGroupLags.
+func FormatPartitionLags(partitionLags []PartitionLag) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Partition",
+			"Committed Offset",
+			"End Offset",
+			"Lag",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, partitionLag := range partitionLags {
 		table.Append(
 			[]string{
-				fmt.Sprintf("%d", memberLag.Partition),
-				memberIDPrinter("%s", memberID),
-				fmt.Sprintf("%d", memberLag.MemberOffset),
-				memberTimeStr,
-				fmt.Sprintf("%d", memberLag.NewestOffset),
-				memberLag.NewestTime.Format(time.RFC3339),
-				fmt.Sprintf("%d", memberLag.OffsetLag()),
-				timeLagStr,
+				partitionLag.Topic,
+				fmt.Sprintf("%d", partitionLag.Partition),
+				fmt.Sprintf("%d", partitionLag.CommittedOffset),
+				fmt.Sprintf("%d", partitionLag.EndOffset),
+				fmt.Sprintf("%d", partitionLag.Lag()),
 			},
 		)
 	}
@@ -267,16 +493,277 @@ func FormatMemberLags(memberLags []MemberPartitionLag, full bool) string {
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
 
-// FormatPartitionOffsets generates a pretty table that shows the proposed offsets for each
-// partition in a reset.
-func FormatPartitionOffsets(partitionOffsets map[int]int64) string {
+// FormatPartitionLagTotals generates a pretty table with the per-topic and overall lag totals
+// from the results of GetGroupLags.
+func FormatPartitionLagTotals(partitionLags []PartitionLag) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Partitions",
+			"Total Lag",
+			"Max Partition Lag",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	topics := []string{}
+	lagsByTopic := map[string][]PartitionLag{}
+
+	for _, partitionLag := range partitionLags {
+		if _, ok := lagsByTopic[partitionLag.Topic]; !ok {
+			topics = append(topics, partitionLag.Topic)
+		}
+		lagsByTopic[partitionLag.Topic] = append(lagsByTopic[partitionLag.Topic], partitionLag)
+	}
+	sort.Strings(topics)
+
+	var maxLag int64
+
+	for _, topic := range topics {
+		var topicMaxLag int64
+		for _, partitionLag := range lagsByTopic[topic] {
+			if partitionLag.Lag() > topicMaxLag {
+				topicMaxLag = partitionLag.Lag()
+			}
+		}
+		if topicMaxLag > maxLag {
+			maxLag = topicMaxLag
+		}
+
+		table.Append(
+			[]string{
+				topic,
+				fmt.Sprintf("%d", len(lagsByTopic[topic])),
+				fmt.Sprintf("%d", TotalLag(lagsByTopic[topic])),
+				fmt.Sprintf("%d", topicMaxLag),
+			},
+		)
+	}
+
+	table.Append(
+		[]string{
+			"Total",
+			fmt.Sprintf("%d", len(partitionLags)),
+			fmt.Sprintf("%d", TotalLag(partitionLags)),
+			fmt.Sprintf("%d", maxLag),
+		},
+	)
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatLagRates generates a pretty table from the results of EstimateLagRates, including the
+// estimated time for the group to catch up in each topic.
+func FormatLagRates(lagRates []LagRate) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Topic",
+			"Lag",
+			"Consume Rate",
+			"Produce Rate",
+			"Catch-Up ETA",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, lagRate := range lagRates {
+		var etaStr string
+
+		catchUpTime, catchingUp := lagRate.CatchUpTime()
+		switch {
+		case lagRate.Lag <= 0:
+			etaStr = "caught up"
+		case !catchingUp && lagRate.Consumed == 0 && lagRate.Produced == 0:
+			etaStr = "stalled"
+		case !catchingUp:
+			etaStr = "falling behind"
+			if util.ColorEnabled() {
+				etaStr = color.New(color.FgRed).Sprint(etaStr)
+			}
+		default:
+			etaStr = util.PrettyDuration(catchUpTime)
+		}
+
+		table.Append(
+			[]string{
+				lagRate.Topic,
+				fmt.Sprintf("%d", lagRate.Lag),
+				util.PrettyRate(lagRate.Consumed, lagRate.Window),
+				util.PrettyRate(lagRate.Produced, lagRate.Window),
+				etaStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatTopicConsumers generates a pretty table from the results of GetTopicConsumers.
+func FormatTopicConsumers(topicConsumers []TopicConsumer, now time.Time) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Group",
+			"Active Members",
+			"Last Consumed Time",
+			"Age",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, topicConsumer := range topicConsumers {
+		var lastConsumedTimeStr string
+		var ageStr string
+
+		if !topicConsumer.LastConsumedTime.IsZero() {
+			lastConsumedTimeStr = topicConsumer.LastConsumedTime.Format(time.RFC3339)
+			ageStr = util.PrettyDuration(now.Sub(topicConsumer.LastConsumedTime))
+		}
+
+		table.Append(
+			[]string{
+				topicConsumer.GroupID,
+				fmt.Sprintf("%d", topicConsumer.ActiveMembers),
+				lastConsumedTimeStr,
+				ageStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatIdleGroups generates a pretty table from the results of GetIdleGroups.
+func FormatIdleGroups(idleGroups []IdleGroup, now time.Time) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Group",
+			"Partitions",
+			"Last Consumed Time",
+			"Age",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	for _, idleGroup := range idleGroups {
+		lastConsumedTimeStr := "never"
+		var ageStr string
+
+		if !idleGroup.LastConsumedTime.IsZero() {
+			lastConsumedTimeStr = idleGroup.LastConsumedTime.Format(time.RFC3339)
+			ageStr = util.PrettyDuration(now.Sub(idleGroup.LastConsumedTime))
+		}
+
+		table.Append(
+			[]string{
+				idleGroup.GroupID,
+				fmt.Sprintf("%d", idleGroup.Partitions),
+				lastConsumedTimeStr,
+				ageStr,
+			},
+		)
+	}
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatOffsetResets generates a pretty table that previews the argument new offsets, along with
+// the current offsets and the lag before and after the reset.
+func FormatOffsetResets(
+	states []PartitionOffsetState,
+	partitionOffsets map[int]int64,
+) string {
 	buf := &bytes.Buffer{}
 
 	table := tablewriter.NewWriter(buf)
 	table.SetHeader(
 		[]string{
 			"Partition",
+			"Current Offset",
 			"New Offset",
+			"End Offset",
+			"Current Lag",
+			"New Lag",
 		},
 	)
 	table.SetAutoWrapText(false)
@@ -284,6 +771,10 @@ func FormatPartitionOffsets(partitionOffsets map[int]int64) string {
 		[]int{
 			tablewriter.ALIGN_LEFT,
 			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
 		},
 	)
 	table.SetBorders(
@@ -295,24 +786,52 @@ func FormatPartitionOffsets(partitionOffsets map[int]int64) string {
 		},
 	)
 
-	partitionIDs := []int{}
-	for partitionID := range partitionOffsets {
-		partitionIDs = append(partitionIDs, partitionID)
-	}
+	var totalCurrLag int64
+	var totalNewLag int64
 
-	sort.Slice(partitionIDs, func(a, b int) bool {
-		return partitionIDs[a] < partitionIDs[b]
-	})
+	for _, state := range states {
+		newOffset, ok := partitionOffsets[state.Partition]
+		if !ok {
+			continue
+		}
+
+		var currOffsetStr string
+		var currLagStr string
+
+		if state.CommittedOffset >= 0 {
+			currLag := state.EndOffset - state.Clamp(state.CommittedOffset)
+			totalCurrLag += currLag
+
+			currOffsetStr = fmt.Sprintf("%d", state.CommittedOffset)
+			currLagStr = fmt.Sprintf("%d", currLag)
+		}
+
+		newLag := state.EndOffset - newOffset
+		totalNewLag += newLag
 
-	for _, partitionID := range partitionIDs {
 		table.Append(
 			[]string{
-				fmt.Sprintf("%d", partitionID),
-				fmt.Sprintf("%d", partitionOffsets[partitionID]),
+				fmt.Sprintf("%d", state.Partition),
+				currOffsetStr,
+				fmt.Sprintf("%d", newOffset),
+				fmt.Sprintf("%d", state.EndOffset),
+				currLagStr,
+				fmt.Sprintf("%d", newLag),
 			},
 		)
 	}
 
+	table.Append(
+		[]string{
+			"Total",
+			"",
+			"",
+			"",
+			fmt.Sprintf("%d", totalCurrLag),
+			fmt.Sprintf("%d", totalNewLag),
+		},
+	)
+
 	table.Render()
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
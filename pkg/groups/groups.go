@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol/describegroups"
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/messages"
 	log "github.com/sirupsen/logrus"
@@ -43,6 +45,101 @@ func GetGroups(
 	return groupCoordinators, err
 }
 
+// GetGroupSummaries fetches the state, protocol, and member count of all consumer groups in the
+// cluster. If topic is set, only the groups that either have members assigned to the topic or
+// have committed offsets in it are returned.
+func GetGroupSummaries(
+	ctx context.Context,
+	connector *admin.Connector,
+	topic string,
+) ([]GroupSummary, error) {
+	groupCoordinators, err := GetGroups(ctx, connector)
+	if err != nil {
+		return nil, err
+	}
+
+	groupSummaries := []GroupSummary{}
+	if len(groupCoordinators) == 0 {
+		return groupSummaries, nil
+	}
+
+	groupIDs := []string{}
+	for _, groupCoordinator := range groupCoordinators {
+		groupIDs = append(groupIDs, groupCoordinator.GroupID)
+	}
+
+	protocolGroups, err := describeGroupProtocols(ctx, connector.KafkaClient, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, groupCoordinator := range groupCoordinators {
+		groupSummary := GroupSummary{
+			GroupID:     groupCoordinator.GroupID,
+			Coordinator: groupCoordinator.Coordinator,
+			State:       "Unknown",
+		}
+		if protocolGroup, ok := protocolGroups[groupCoordinator.GroupID]; ok {
+			groupSummary.State = protocolGroup.GroupState
+			groupSummary.ProtocolType = protocolGroup.ProtocolType
+			groupSummary.Protocol = protocolGroup.ProtocolData
+			groupSummary.Members = len(protocolGroup.Members)
+		}
+
+		if topic != "" {
+			consumes, err := groupConsumesTopic(ctx, connector, groupSummary, topic)
+			if err != nil {
+				return nil, err
+			}
+			if !consumes {
+				continue
+			}
+		}
+
+		groupSummaries = append(groupSummaries, groupSummary)
+	}
+
+	return groupSummaries, nil
+}
+
+// groupConsumesTopic returns whether the argument group either has members assigned to the
+// argument topic or has committed offsets in it.
+func groupConsumesTopic(
+	ctx context.Context,
+	connector *admin.Connector,
+	groupSummary GroupSummary,
+	topic string,
+) (bool, error) {
+	// Member assignments are only decoded for regular consumer groups
+	if groupSummary.Members > 0 && groupSummary.ProtocolType == "consumer" {
+		groupDetails, err := GetGroupDetails(ctx, connector, groupSummary.GroupID)
+		if err != nil {
+			return false, err
+		}
+		if len(groupDetails.PartitionMembers(topic)) > 0 {
+			return true, nil
+		}
+	}
+
+	offsets, err := connector.KafkaClient.ConsumerOffsets(
+		ctx, kafka.TopicAndGroup{
+			Topic:   topic,
+			GroupId: groupSummary.GroupID,
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	for _, offset := range offsets {
+		if offset >= 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // GetGroupDetails returns the details (membership, etc.) for a single consumer group.
 func GetGroupDetails(
 	ctx context.Context,
@@ -65,18 +162,34 @@ func GetGroupDetails(
 	}
 	group := describeGroupsResponse.Groups[0]
 
+	protocolGroup, err := describeGroupProtocol(ctx, connector.KafkaClient, groupID)
+	if err != nil {
+		return nil, err
+	}
+	instanceIDs := map[string]string{}
+	for _, protocolMember := range protocolGroup.Members {
+		instanceIDs[protocolMember.MemberID] = protocolMember.GroupInstanceID
+	}
+
 	groupDetails := GroupDetails{
-		GroupID: group.GroupID,
-		State:   group.GroupState,
-		Members: []MemberInfo{},
+		GroupID:            group.GroupID,
+		State:              group.GroupState,
+		ProtocolType:       protocolGroup.ProtocolType,
+		AssignmentStrategy: protocolGroup.ProtocolData,
+		Members:            []MemberInfo{},
 	}
 	for _, kafkaMember := range group.Members {
 		member := MemberInfo{
 			MemberID:        kafkaMember.MemberID,
 			ClientID:        kafkaMember.ClientID,
 			ClientHost:      kafkaMember.ClientHost,
+			GroupInstanceID: instanceIDs[kafkaMember.MemberID],
 			TopicPartitions: map[string][]int{},
+
+			SubscribedTopics:    append([]string{}, kafkaMember.MemberMetadata.Topics...),
+			SubscriptionVersion: kafkaMember.MemberMetadata.Version,
 		}
+		sort.Strings(member.SubscribedTopics)
 
 		for _, assignments := range kafkaMember.MemberAssignments.Topics {
 			partitions := []int{}
@@ -105,6 +218,94 @@ func GetGroupDetails(
 	return &groupDetails, nil
 }
 
+// describeGroupProtocol gets the raw DescribeGroups result for a single group. The higher-level
+// kafka-go API decodes the member assignments but drops the group's protocol (i.e., the
+// assignment strategy) and the members' static instance IDs, so these are fetched separately.
+func describeGroupProtocol(
+	ctx context.Context,
+	client *kafka.Client,
+	groupID string,
+) (describegroups.ResponseGroup, error) {
+	transport := client.Transport
+	if transport == nil {
+		transport = kafka.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(
+		ctx,
+		client.Addr,
+		&describegroups.Request{
+			Groups: []string{groupID},
+		},
+	)
+	if err != nil {
+		return describegroups.ResponseGroup{}, err
+	}
+
+	describeGroupsResp, ok := resp.(*describegroups.Response)
+	if !ok {
+		return describegroups.ResponseGroup{}, fmt.Errorf(
+			"Unexpected DescribeGroups response type: %T",
+			resp,
+		)
+	}
+	if len(describeGroupsResp.Groups) != 1 {
+		return describegroups.ResponseGroup{}, fmt.Errorf(
+			"Unexpected response length from describeGroups",
+		)
+	}
+
+	return describeGroupsResp.Groups[0], nil
+}
+
+// describeGroupProtocols gets the raw DescribeGroups results for the argument groups, keyed by
+// group ID. The client splits the request up by coordinator.
+func describeGroupProtocols(
+	ctx context.Context,
+	client *kafka.Client,
+	groupIDs []string,
+) (map[string]describegroups.ResponseGroup, error) {
+	transport := client.Transport
+	if transport == nil {
+		transport = kafka.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(
+		ctx,
+		client.Addr,
+		&describegroups.Request{
+			Groups: groupIDs,
+		},
+	)
+	log.Debugf("DescribeGroups response: %+v (%+v)", resp, err)
+	if err != nil {
+		return nil, err
+	}
+
+	describeGroupsResp, ok := resp.(*describegroups.Response)
+	if !ok {
+		return nil, fmt.Errorf(
+			"Unexpected DescribeGroups response type: %T",
+			resp,
+		)
+	}
+
+	protocolGroups := map[string]describegroups.ResponseGroup{}
+	for _, group := range describeGroupsResp.Groups {
+		if group.ErrorCode != 0 {
+			log.Warnf(
+				"Could not describe group %s: %+v",
+				group.GroupID,
+				kafka.Error(group.ErrorCode),
+			)
+			continue
+		}
+		protocolGroups[group.GroupID] = group
+	}
+
+	return protocolGroups, nil
+}
+
 // GetMemberLags returns the lag for each partition being consumed by the argument group in the
 // argument topic.
 func GetMemberLags(
@@ -146,6 +347,7 @@ func GetMemberLags(
 			Topic:        topic,
 			Partition:    bound.Partition,
 			MemberID:     partitionMembers[bound.Partition].MemberID,
+			InstanceID:   partitionMembers[bound.Partition].GroupInstanceID,
 			MemberOffset: offsets[bound.Partition],
 			NewestOffset: bound.LastOffset,
 			NewestTime:   bound.LastTime,
@@ -161,6 +363,330 @@ func GetMemberLags(
 	return partitionLags, nil
 }
 
+// GetTopicConsumers returns the consumer groups that either have members assigned to the
+// argument topic or have committed offsets in it, sorted by group ID.
+//
+// Kafka doesn't expose when offsets were committed, so the LastConsumedTime in each result is
+// based on the timestamp of the last message that the group consumed in each partition.
+func GetTopicConsumers(
+	ctx context.Context,
+	connector *admin.Connector,
+	topic string,
+) ([]TopicConsumer, error) {
+	groupCoordinators, err := GetGroups(ctx, connector)
+	if err != nil {
+		return nil, err
+	}
+
+	topicConsumers := []TopicConsumer{}
+
+	for _, groupCoordinator := range groupCoordinators {
+		groupDetails, err := GetGroupDetails(ctx, connector, groupCoordinator.GroupID)
+		if err != nil {
+			return nil, err
+		}
+
+		topicConsumer := TopicConsumer{
+			GroupID:       groupCoordinator.GroupID,
+			ActiveMembers: len(groupDetails.PartitionMembers(topic)),
+		}
+
+		offsets, err := connector.KafkaClient.ConsumerOffsets(
+			ctx, kafka.TopicAndGroup{
+				Topic:   topic,
+				GroupId: groupCoordinator.GroupID,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		hasOffsets := false
+
+		for partition, offset := range offsets {
+			if offset < 0 {
+				// Group hasn't committed anything for this partition
+				continue
+			}
+			hasOffsets = true
+
+			if offset == 0 {
+				continue
+			}
+
+			bounds, err := messages.GetPartitionBounds(ctx, connector, topic, partition, offset-1)
+			if err != nil {
+				return nil, err
+			}
+
+			// If the consumed message has been removed by retention, then this is the time of
+			// the first remaining message, which errs on the side of treating the group as
+			// recently active.
+			if bounds.FirstTime.After(topicConsumer.LastConsumedTime) {
+				topicConsumer.LastConsumedTime = bounds.FirstTime
+			}
+		}
+
+		if topicConsumer.ActiveMembers == 0 && !hasOffsets {
+			continue
+		}
+
+		log.Debugf("Found consumer of topic %s: %+v", topic, topicConsumer)
+		topicConsumers = append(topicConsumers, topicConsumer)
+	}
+
+	return topicConsumers, nil
+}
+
+// GetGroupLags returns the lag for every topic partition that the argument group has committed
+// offsets for, sorted by topic and partition. Unlike GetMemberLags, this doesn't require the group
+// to have any active members.
+func GetGroupLags(
+	ctx context.Context,
+	connector *admin.Connector,
+	groupID string,
+) ([]PartitionLag, error) {
+	groupDetails, err := GetGroupDetails(ctx, connector, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupDetails.State == "Dead" {
+		return nil, errors.New("Group state is dead; check that group ID is valid")
+	}
+
+	// Committed offsets can only be fetched for explicit topic partitions, so start by getting
+	// all of the partitions in the cluster.
+	metadata, err := connector.KafkaClient.Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	topicPartitions := map[string][]int{}
+	for _, topic := range metadata.Topics {
+		if topic.Internal {
+			continue
+		}
+		for _, partition := range topic.Partitions {
+			topicPartitions[topic.Name] = append(topicPartitions[topic.Name], partition.ID)
+		}
+	}
+
+	offsetsResp, err := connector.KafkaClient.OffsetFetch(
+		ctx,
+		&kafka.OffsetFetchRequest{
+			GroupID: groupID,
+			Topics:  topicPartitions,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if offsetsResp.Error != nil {
+		return nil, fmt.Errorf(
+			"Error fetching offsets for group %s: %+v",
+			groupID,
+			offsetsResp.Error,
+		)
+	}
+
+	partitionLags := []PartitionLag{}
+	offsetRequests := map[string][]kafka.OffsetRequest{}
+
+	for topic, partitionOffsets := range offsetsResp.Topics {
+		for _, partitionOffset := range partitionOffsets {
+			if partitionOffset.Error != nil {
+				return nil, fmt.Errorf(
+					"Error fetching offset for topic %s, partition %d: %+v",
+					topic,
+					partitionOffset.Partition,
+					partitionOffset.Error,
+				)
+			}
+			if partitionOffset.CommittedOffset < 0 {
+				// Group hasn't committed anything for this partition
+				continue
+			}
+
+			partitionLags = append(
+				partitionLags,
+				PartitionLag{
+					Topic:           topic,
+					Partition:       partitionOffset.Partition,
+					CommittedOffset: partitionOffset.CommittedOffset,
+				},
+			)
+			offsetRequests[topic] = append(
+				offsetRequests[topic],
+				kafka.LastOffsetOf(partitionOffset.Partition),
+			)
+		}
+	}
+
+	if len(partitionLags) == 0 {
+		return partitionLags, nil
+	}
+
+	endOffsetsResp, err := connector.KafkaClient.ListOffsets(
+		ctx,
+		&kafka.ListOffsetsRequest{
+			Topics: offsetRequests,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endOffsets := map[string]map[int]int64{}
+	for topic, partitionOffsets := range endOffsetsResp.Topics {
+		endOffsets[topic] = map[int]int64{}
+
+		for _, partitionOffset := range partitionOffsets {
+			if partitionOffset.Error != nil {
+				return nil, fmt.Errorf(
+					"Error getting end offset for topic %s, partition %d: %+v",
+					topic,
+					partitionOffset.Partition,
+					partitionOffset.Error,
+				)
+			}
+			endOffsets[topic][partitionOffset.Partition] = partitionOffset.LastOffset
+		}
+	}
+
+	for p := 0; p < len(partitionLags); p++ {
+		partitionLags[p].EndOffset = endOffsets[partitionLags[p].Topic][partitionLags[p].Partition]
+	}
+
+	sort.Slice(partitionLags, func(a, b int) bool {
+		if partitionLags[a].Topic != partitionLags[b].Topic {
+			return partitionLags[a].Topic < partitionLags[b].Topic
+		}
+		return partitionLags[a].Partition < partitionLags[b].Partition
+	})
+
+	return partitionLags, nil
+}
+
+// GetPartitionOffsetStates returns the committed offsets of the argument group in each of the
+// argument topic partitions along with the bounds of each partition. The results are sorted by
+// partition.
+func GetPartitionOffsetStates(
+	ctx context.Context,
+	connector *admin.Connector,
+	topic string,
+	groupID string,
+	partitions []int,
+) ([]PartitionOffsetState, error) {
+	offsetsResp, err := connector.KafkaClient.OffsetFetch(
+		ctx,
+		&kafka.OffsetFetchRequest{
+			GroupID: groupID,
+			Topics: map[string][]int{
+				topic: partitions,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if offsetsResp.Error != nil {
+		return nil, fmt.Errorf(
+			"Error fetching offsets for group %s: %+v",
+			groupID,
+			offsetsResp.Error,
+		)
+	}
+
+	committedOffsets := map[int]int64{}
+	for _, partitionOffset := range offsetsResp.Topics[topic] {
+		if partitionOffset.Error != nil {
+			return nil, fmt.Errorf(
+				"Error fetching offset for partition %d: %+v",
+				partitionOffset.Partition,
+				partitionOffset.Error,
+			)
+		}
+		committedOffsets[partitionOffset.Partition] = partitionOffset.CommittedOffset
+	}
+
+	// The first and end offsets need to be fetched in separate requests since brokers reject
+	// list offset requests that contain the same partition more than once.
+	firstOffsetRequests := []kafka.OffsetRequest{}
+	endOffsetRequests := []kafka.OffsetRequest{}
+	for _, partition := range partitions {
+		firstOffsetRequests = append(firstOffsetRequests, kafka.FirstOffsetOf(partition))
+		endOffsetRequests = append(endOffsetRequests, kafka.LastOffsetOf(partition))
+	}
+
+	firstOffsetsResp, err := connector.KafkaClient.ListOffsets(
+		ctx,
+		&kafka.ListOffsetsRequest{
+			Topics: map[string][]kafka.OffsetRequest{
+				topic: firstOffsetRequests,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	endOffsetsResp, err := connector.KafkaClient.ListOffsets(
+		ctx,
+		&kafka.ListOffsetsRequest{
+			Topics: map[string][]kafka.OffsetRequest{
+				topic: endOffsetRequests,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endOffsets := map[int]int64{}
+	for _, partitionOffsets := range endOffsetsResp.Topics[topic] {
+		if partitionOffsets.Error != nil {
+			return nil, fmt.Errorf(
+				"Error getting end offset for partition %d: %+v",
+				partitionOffsets.Partition,
+				partitionOffsets.Error,
+			)
+		}
+		endOffsets[partitionOffsets.Partition] = partitionOffsets.LastOffset
+	}
+
+	states := []PartitionOffsetState{}
+	for _, partitionOffsets := range firstOffsetsResp.Topics[topic] {
+		if partitionOffsets.Error != nil {
+			return nil, fmt.Errorf(
+				"Error getting first offset for partition %d: %+v",
+				partitionOffsets.Partition,
+				partitionOffsets.Error,
+			)
+		}
+
+		committedOffset, ok := committedOffsets[partitionOffsets.Partition]
+		if !ok {
+			committedOffset = -1
+		}
+
+		states = append(
+			states,
+			PartitionOffsetState{
+				Partition:       partitionOffsets.Partition,
+				CommittedOffset: committedOffset,
+				FirstOffset:     partitionOffsets.FirstOffset,
+				EndOffset:       endOffsets[partitionOffsets.Partition],
+			},
+		)
+	}
+
+	sort.Slice(states, func(a, b int) bool {
+		return states[a].Partition < states[b].Partition
+	})
+
+	return states, nil
+}
+
 // ResetOffsets updates the offsets for a given topic / group combination.
 func ResetOffsets(
 	ctx context.Context,
@@ -192,3 +718,70 @@ func ResetOffsets(
 		},
 	)
 }
+
+// DeleteGroup deletes the argument group, including all of its committed offsets. Brokers reject
+// the deletion if the group has any members.
+func DeleteGroup(
+	ctx context.Context,
+	connector *admin.Connector,
+	groupID string,
+) error {
+	req := kafka.DeleteGroupsRequest{
+		GroupIDs: []string{groupID},
+	}
+	log.Debugf("DeleteGroups request: %+v", req)
+
+	resp, err := connector.KafkaClient.DeleteGroups(ctx, &req)
+	log.Debugf("DeleteGroups response: %+v (%+v)", resp, err)
+	if err != nil {
+		return err
+	}
+	if groupErr := resp.Errors[groupID]; groupErr != nil {
+		return fmt.Errorf("Error deleting group %s: %+v", groupID, groupErr)
+	}
+
+	return nil
+}
+
+// DeleteGroupOffsets deletes the committed offsets of the argument group in the argument topic
+// partitions. Brokers reject the deletion for topics that the group's members are subscribed to.
+func DeleteGroupOffsets(
+	ctx context.Context,
+	connector *admin.Connector,
+	groupID string,
+	topicPartitions map[string][]int,
+) error {
+	req := kafka.OffsetDeleteRequest{
+		GroupID: groupID,
+		Topics:  topicPartitions,
+	}
+	log.Debugf("OffsetDelete request: %+v", req)
+
+	resp, err := connector.KafkaClient.OffsetDelete(ctx, &req)
+	log.Debugf("OffsetDelete response: %+v (%+v)", resp, err)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("Error deleting offsets for group %s: %+v", groupID, resp.Error)
+	}
+
+	var partitionErrs error
+	for topic, partitions := range resp.Topics {
+		for _, partition := range partitions {
+			if partition.Error != nil {
+				partitionErrs = multierror.Append(
+					partitionErrs,
+					fmt.Errorf(
+						"Error deleting offset for topic %s, partition %d: %+v",
+						topic,
+						partition.Partition,
+						partition.Error,
+					),
+				)
+			}
+		}
+	}
+
+	return partitionErrs
+}
@@ -58,12 +58,24 @@ func TestGetGroups(t *testing.T) {
 	}
 	require.True(t, match)
 
+	groupSummaries, err := GetGroupSummaries(ctx, connector, topicName)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(groupSummaries))
+	assert.Equal(t, groupID, groupSummaries[0].GroupID)
+	assert.Equal(t, "Stable", groupSummaries[0].State)
+	assert.Equal(t, "consumer", groupSummaries[0].ProtocolType)
+	assert.NotEqual(t, "", groupSummaries[0].Protocol)
+	assert.Equal(t, 1, groupSummaries[0].Members)
+
 	groupDetails, err := GetGroupDetails(ctx, connector, groupID)
 	require.NoError(t, err)
 	assert.Equal(t, groupID, groupDetails.GroupID)
 	assert.Equal(t, "Stable", groupDetails.State)
+	assert.Equal(t, "consumer", groupDetails.ProtocolType)
+	assert.NotEqual(t, "", groupDetails.AssignmentStrategy)
 	assert.Equal(t, 1, len(groupDetails.Members))
 	require.Equal(t, 1, len(groupDetails.Members))
+	assert.NotEqual(t, "", groupDetails.Members[0].ClientID)
 
 	groupPartitions := groupDetails.Members[0].TopicPartitions[topicName]
 
@@ -114,6 +126,134 @@ func TestGetLags(t *testing.T) {
 	}
 }
 
+func TestGetGroupLags(t *testing.T) {
+	ctx := context.Background()
+	connector, err := admin.NewConnector(admin.ConnectorConfig{
+		BrokerAddr: util.TestKafkaAddr(),
+	})
+	require.NoError(t, err)
+
+	topicName := createTestTopic(ctx, t, connector)
+	groupID := fmt.Sprintf("test-group-%s", topicName)
+
+	reader := kafka.NewReader(
+		kafka.ReaderConfig{
+			Brokers:  []string{connector.Config.BrokerAddr},
+			Dialer:   connector.Dialer,
+			GroupID:  groupID,
+			Topic:    topicName,
+			MinBytes: 50,
+			MaxBytes: 10000,
+		},
+	)
+
+	readerCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// Read enough messages so that offsets are committed in both partitions
+	for i := 0; i < 8; i++ {
+		_, err := reader.ReadMessage(readerCtx)
+		require.NoError(t, err)
+	}
+	require.NoError(t, reader.Close())
+
+	util.RetryUntil(t, 5*time.Second, func() error {
+		lags, err := GetGroupLags(ctx, connector, groupID)
+		if err != nil {
+			return err
+		}
+		if len(lags) != 2 {
+			return fmt.Errorf("Expected 2 partition lags, got %d", len(lags))
+		}
+		return nil
+	})
+
+	lags, err := GetGroupLags(ctx, connector, groupID)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(lags))
+
+	for l, lag := range lags {
+		assert.Equal(t, topicName, lag.Topic)
+		assert.Equal(t, l, lag.Partition)
+		assert.Equal(t, int64(5), lag.EndOffset)
+		assert.LessOrEqual(t, lag.CommittedOffset, int64(5))
+	}
+	assert.Equal(t, int64(2), TotalLag(lags))
+}
+
+func TestTotalLag(t *testing.T) {
+	assert.Equal(
+		t,
+		int64(15),
+		TotalLag(
+			[]PartitionLag{
+				{
+					Topic:           "topic1",
+					Partition:       0,
+					CommittedOffset: 5,
+					EndOffset:       15,
+				},
+				{
+					Topic:           "topic1",
+					Partition:       1,
+					CommittedOffset: 10,
+					EndOffset:       15,
+				},
+				{
+					// Committed offsets can be ahead of the end offset after a topic is
+					// truncated; these shouldn't count as negative lag.
+					Topic:           "topic2",
+					Partition:       0,
+					CommittedOffset: 20,
+					EndOffset:       10,
+				},
+			},
+		),
+	)
+}
+
+func TestTopicConsumerActiveSince(t *testing.T) {
+	since := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, TopicConsumer{GroupID: "group1", ActiveMembers: 2}.ActiveSince(since))
+	assert.True(
+		t,
+		TopicConsumer{
+			GroupID:          "group1",
+			LastConsumedTime: since.Add(time.Minute),
+		}.ActiveSince(since),
+	)
+	assert.False(
+		t,
+		TopicConsumer{
+			GroupID:          "group1",
+			LastConsumedTime: since.Add(-time.Minute),
+		}.ActiveSince(since),
+	)
+	assert.False(t, TopicConsumer{GroupID: "group1"}.ActiveSince(since))
+}
+
+func TestGroupDetailsStaticMembers(t *testing.T) {
+	groupDetails := GroupDetails{
+		Members: []MemberInfo{
+			{MemberID: "member1", GroupInstanceID: "instance1"},
+			{MemberID: "member2"},
+		},
+	}
+	assert.Equal(t, 1, groupDetails.StaticMembers())
+	assert.True(t, groupDetails.MixedMembership())
+
+	groupDetails.Members[1].GroupInstanceID = "instance2"
+	assert.Equal(t, 2, groupDetails.StaticMembers())
+	assert.False(t, groupDetails.MixedMembership())
+
+	assert.False(t, GroupDetails{}.MixedMembership())
+	assert.False(
+		t,
+		GroupDetails{Members: []MemberInfo{{MemberID: "member1"}}}.MixedMembership(),
+	)
+}
+
 func TestResetOffsets(t *testing.T) {
 	ctx := context.Background()
 	connector, err := admin.NewConnector(admin.ConnectorConfig{
@@ -0,0 +1,118 @@
+package groups
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/messages"
+	log "github.com/sirupsen/logrus"
+)
+
+// IdleGroup is a consumer group without any members that hasn't consumed anything recently.
+type IdleGroup struct {
+	GroupID string `json:"groupID"`
+
+	// Partitions is the number of topic partitions that the group has committed offsets in.
+	Partitions int `json:"partitions"`
+
+	// LastConsumedTime is the newest timestamp across the messages just before the group's
+	// committed offsets. It's the zero time if the group hasn't consumed anything.
+	LastConsumedTime time.Time `json:"lastConsumedTime"`
+}
+
+// IsIdleSince returns whether a group with the argument state, member count, and last consumed
+// time should be considered idle since the argument time.
+func IsIdleSince(state string, members int, lastConsumedTime time.Time, since time.Time) bool {
+	return state == "Empty" && members == 0 && lastConsumedTime.Before(since)
+}
+
+// GetIdleGroups returns the consumer groups that are empty (i.e., have no members) and haven't
+// consumed any messages that were produced after the argument time, sorted by group ID. Groups
+// whose offsets can't be fetched are skipped.
+//
+// Kafka doesn't expose when offsets were committed, so, as in GetTopicConsumers, the time that
+// a group last consumed is based on the timestamps of the messages just before its committed
+// offsets.
+func GetIdleGroups(
+	ctx context.Context,
+	connector *admin.Connector,
+	since time.Time,
+) ([]IdleGroup, error) {
+	groupSummaries, err := GetGroupSummaries(ctx, connector, "")
+	if err != nil {
+		return nil, err
+	}
+
+	idleGroups := []IdleGroup{}
+
+	for _, groupSummary := range groupSummaries {
+		if !IsIdleSince(groupSummary.State, groupSummary.Members, time.Time{}, since) {
+			continue
+		}
+
+		partitionLags, err := GetGroupLags(ctx, connector, groupSummary.GroupID)
+		if err != nil {
+			log.Warnf("Could not get offsets for group %s: %+v", groupSummary.GroupID, err)
+			continue
+		}
+
+		lastConsumedTime, err := getLastConsumedTime(ctx, connector, partitionLags)
+		if err != nil {
+			log.Warnf(
+				"Could not get last consumed time for group %s: %+v",
+				groupSummary.GroupID,
+				err,
+			)
+			continue
+		}
+
+		if !IsIdleSince(groupSummary.State, groupSummary.Members, lastConsumedTime, since) {
+			continue
+		}
+
+		idleGroups = append(
+			idleGroups,
+			IdleGroup{
+				GroupID:          groupSummary.GroupID,
+				Partitions:       len(partitionLags),
+				LastConsumedTime: lastConsumedTime,
+			},
+		)
+	}
+
+	return idleGroups, nil
+}
+
+func getLastConsumedTime(
+	ctx context.Context,
+	connector *admin.Connector,
+	partitionLags []PartitionLag,
+) (time.Time, error) {
+	var lastConsumedTime time.Time
+
+	for _, partitionLag := range partitionLags {
+		if partitionLag.CommittedOffset <= 0 {
+			continue
+		}
+
+		bounds, err := messages.GetPartitionBounds(
+			ctx,
+			connector,
+			partitionLag.Topic,
+			partitionLag.Partition,
+			partitionLag.CommittedOffset-1,
+		)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		// If the consumed message has been removed by retention, this is the time of the first
+		// remaining message, which errs on the side of treating the group as recently active.
+		if bounds.FirstTime.After(lastConsumedTime) {
+			lastConsumedTime = bounds.FirstTime
+		}
+	}
+
+	return lastConsumedTime, nil
+}
@@ -0,0 +1,19 @@
+package groups
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsIdleSince(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, IsIdleSince("Empty", 0, time.Time{}, since))
+	assert.True(t, IsIdleSince("Empty", 0, since.Add(-time.Hour), since))
+	assert.False(t, IsIdleSince("Empty", 0, since.Add(time.Hour), since))
+	assert.False(t, IsIdleSince("Stable", 2, time.Time{}, since))
+	assert.False(t, IsIdleSince("Dead", 0, time.Time{}, since))
+	assert.False(t, IsIdleSince("PreparingRebalance", 0, time.Time{}, since))
+}
@@ -0,0 +1,125 @@
+package groups
+
+import (
+	"sort"
+	"time"
+)
+
+// LagRate summarizes how quickly a consumer group is consuming from a topic relative to how
+// quickly messages are being produced to it, based on two samples of the group's lag.
+type LagRate struct {
+	Topic string `json:"topic"`
+
+	// Lag is the total lag of the group in the topic as of the second sample.
+	Lag int64 `json:"lag"`
+
+	// Consumed and Produced are the numbers of messages that the group consumed and that were
+	// produced to the topic between the samples.
+	Consumed int64 `json:"consumed"`
+	Produced int64 `json:"produced"`
+
+	// Window is the time between the samples.
+	Window time.Duration `json:"-"`
+}
+
+// ConsumeRate returns the number of messages consumed per second.
+func (l LagRate) ConsumeRate() float64 {
+	if l.Window <= 0 {
+		return 0
+	}
+	return float64(l.Consumed) / l.Window.Seconds()
+}
+
+// ProduceRate returns the number of messages produced per second.
+func (l LagRate) ProduceRate() float64 {
+	if l.Window <= 0 {
+		return 0
+	}
+	return float64(l.Produced) / l.Window.Seconds()
+}
+
+// CatchUpTime returns the estimated time until the group has no lag in the topic, assuming
+// that the current rates hold. The second return value is false if the group isn't catching up,
+// i.e. it has lag and isn't consuming faster than messages are being produced.
+func (l LagRate) CatchUpTime() (time.Duration, bool) {
+	if l.Lag <= 0 {
+		return 0, true
+	}
+
+	netRate := l.ConsumeRate() - l.ProduceRate()
+	if netRate <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(l.Lag) / netRate * float64(time.Second)), true
+}
+
+// EstimateLagRates compares two samples of a group's partition lags, taken window apart, and
+// returns the resulting rates for each topic, sorted by topic. Partitions that are only in the
+// second sample are counted towards the lag but not the rates. The last element is the total
+// across all topics if there's more than one.
+func EstimateLagRates(
+	first []PartitionLag,
+	second []PartitionLag,
+	window time.Duration,
+) []LagRate {
+	type partitionKey struct {
+		topic     string
+		partition int
+	}
+
+	firstLags := map[partitionKey]PartitionLag{}
+	for _, partitionLag := range first {
+		firstLags[partitionKey{partitionLag.Topic, partitionLag.Partition}] = partitionLag
+	}
+
+	ratesByTopic := map[string]*LagRate{}
+	topics := []string{}
+
+	for _, partitionLag := range second {
+		rate, ok := ratesByTopic[partitionLag.Topic]
+		if !ok {
+			rate = &LagRate{
+				Topic:  partitionLag.Topic,
+				Window: window,
+			}
+			ratesByTopic[partitionLag.Topic] = rate
+			topics = append(topics, partitionLag.Topic)
+		}
+		rate.Lag += partitionLag.Lag()
+
+		firstLag, ok := firstLags[partitionKey{partitionLag.Topic, partitionLag.Partition}]
+		if !ok {
+			continue
+		}
+
+		// Offsets can move backwards, e.g. if the group's offsets were reset, so the deltas
+		// are floored at zero.
+		if consumed := partitionLag.CommittedOffset - firstLag.CommittedOffset; consumed > 0 {
+			rate.Consumed += consumed
+		}
+		if produced := partitionLag.EndOffset - firstLag.EndOffset; produced > 0 {
+			rate.Produced += produced
+		}
+	}
+	sort.Strings(topics)
+
+	rates := []LagRate{}
+	total := LagRate{
+		Topic:  "Total",
+		Window: window,
+	}
+
+	for _, topic := range topics {
+		rate := *ratesByTopic[topic]
+		rates = append(rates, rate)
+
+		total.Lag += rate.Lag
+		total.Consumed += rate.Consumed
+		total.Produced += rate.Produced
+	}
+	if len(rates) > 1 {
+		rates = append(rates, total)
+	}
+
+	return rates
+}
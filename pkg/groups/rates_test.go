@@ -0,0 +1,82 @@
+package groups
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateLagRates(t *testing.T) {
+	first := []PartitionLag{
+		{Topic: "topic1", Partition: 0, CommittedOffset: 100, EndOffset: 200},
+		{Topic: "topic1", Partition: 1, CommittedOffset: 100, EndOffset: 200},
+		{Topic: "topic2", Partition: 0, CommittedOffset: 50, EndOffset: 60},
+	}
+	second := []PartitionLag{
+		{Topic: "topic1", Partition: 0, CommittedOffset: 150, EndOffset: 210},
+		{Topic: "topic1", Partition: 1, CommittedOffset: 140, EndOffset: 210},
+		{Topic: "topic2", Partition: 0, CommittedOffset: 40, EndOffset: 100},
+		{Topic: "topic2", Partition: 1, CommittedOffset: 10, EndOffset: 20},
+	}
+
+	rates := EstimateLagRates(first, second, 10*time.Second)
+	assert.Equal(
+		t,
+		[]LagRate{
+			{
+				Topic:    "topic1",
+				Lag:      130,
+				Consumed: 90,
+				Produced: 20,
+				Window:   10 * time.Second,
+			},
+			{
+				Topic:    "topic2",
+				Lag:      70,
+				Consumed: 0,
+				Produced: 40,
+				Window:   10 * time.Second,
+			},
+			{
+				Topic:    "Total",
+				Lag:      200,
+				Consumed: 90,
+				Produced: 60,
+				Window:   10 * time.Second,
+			},
+		},
+		rates,
+	)
+
+	assert.Equal(t, 9.0, rates[0].ConsumeRate())
+	assert.Equal(t, 2.0, rates[0].ProduceRate())
+
+	catchUpTime, catchingUp := rates[0].CatchUpTime()
+	assert.True(t, catchingUp)
+	assert.Equal(t, 130*time.Second/7, catchUpTime)
+
+	_, catchingUp = rates[1].CatchUpTime()
+	assert.False(t, catchingUp)
+
+	singleTopic := EstimateLagRates(first[:1], second[:1], 10*time.Second)
+	assert.Equal(t, 1, len(singleTopic))
+}
+
+func TestLagRateCatchUpTime(t *testing.T) {
+	catchUpTime, catchingUp := LagRate{Lag: 0, Window: time.Second}.CatchUpTime()
+	assert.True(t, catchingUp)
+	assert.Equal(t, time.Duration(0), catchUpTime)
+
+	_, catchingUp = LagRate{Lag: 10, Window: time.Second}.CatchUpTime()
+	assert.False(t, catchingUp)
+
+	catchUpTime, catchingUp = LagRate{
+		Lag:      100,
+		Consumed: 60,
+		Produced: 10,
+		Window:   10 * time.Second,
+	}.CatchUpTime()
+	assert.True(t, catchingUp)
+	assert.Equal(t, 20*time.Second, catchUpTime)
+}
@@ -0,0 +1,163 @@
+package groups
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ShiftedOffsets returns new offsets for each of the argument partitions that are shifted from
+// the group's committed offsets by the argument amount. Shifts that would go past the bounds of
+// a partition are clamped to the first or end offset.
+func ShiftedOffsets(states []PartitionOffsetState, shift int64) (map[int]int64, error) {
+	partitionOffsets := map[int]int64{}
+
+	for _, state := range states {
+		if state.CommittedOffset < 0 {
+			return nil, fmt.Errorf(
+				"Cannot shift offset for partition %d because group has not committed an offset in it",
+				state.Partition,
+			)
+		}
+		partitionOffsets[state.Partition] = state.Clamp(state.CommittedOffset + shift)
+	}
+
+	return partitionOffsets, nil
+}
+
+// SelectPartitions returns the sorted, de-duplicated subset of a topic's partitions that an offset
+// reset should be scoped to. If requested is empty, all of the topic's partitions are returned.
+// An error is returned if any of the requested partitions aren't in the topic.
+func SelectPartitions(topic string, topicPartitions []int, requested []int) ([]int, error) {
+	if len(requested) == 0 {
+		partitions := append([]int{}, topicPartitions...)
+		sort.Ints(partitions)
+		return partitions, nil
+	}
+
+	topicPartitionsMap := map[int]struct{}{}
+	for _, partition := range topicPartitions {
+		topicPartitionsMap[partition] = struct{}{}
+	}
+
+	selected := map[int]struct{}{}
+	var err error
+
+	for _, partition := range requested {
+		if _, ok := topicPartitionsMap[partition]; !ok {
+			err = multierror.Append(
+				err,
+				fmt.Errorf("Partition %d not found in topic %s", partition, topic),
+			)
+			continue
+		}
+		selected[partition] = struct{}{}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := []int{}
+	for partition := range selected {
+		partitions = append(partitions, partition)
+	}
+	sort.Ints(partitions)
+
+	return partitions, nil
+}
+
+// LoadOffsetsFile loads new offsets from a JSON file that maps partition IDs to offsets, e.g.
+// {"0": 1234, "1": 5678}.
+func LoadOffsetsFile(path string) (map[int]int64, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rawOffsets := map[string]int64{}
+	if err := json.Unmarshal(contents, &rawOffsets); err != nil {
+		return nil, fmt.Errorf(
+			"Offsets file %s must contain a map from partition IDs to offsets: %+v",
+			path,
+			err,
+		)
+	}
+
+	if len(rawOffsets) == 0 {
+		return nil, fmt.Errorf("Offsets file %s does not contain any offsets", path)
+	}
+
+	partitionOffsets := map[int]int64{}
+	for partitionStr, offset := range rawOffsets {
+		partition, err := strconv.Atoi(partitionStr)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Offsets file %s contains invalid partition ID '%s'",
+				path,
+				partitionStr,
+			)
+		}
+		partitionOffsets[partition] = offset
+	}
+
+	return partitionOffsets, nil
+}
+
+// CheckOffsets verifies that each of the argument new offsets is for one of the argument
+// partitions and within its bounds.
+func CheckOffsets(states []PartitionOffsetState, partitionOffsets map[int]int64) error {
+	statesMap := map[int]PartitionOffsetState{}
+	for _, state := range states {
+		statesMap[state.Partition] = state
+	}
+
+	var err error
+
+	for partition, offset := range partitionOffsets {
+		state, ok := statesMap[partition]
+		if !ok {
+			err = multierror.Append(err, fmt.Errorf("Partition %d not found in topic", partition))
+		} else if !state.InBounds(offset) {
+			err = multierror.Append(
+				err,
+				fmt.Errorf(
+					"Offset %d for partition %d is outside of the partition bounds (%d->%d)",
+					offset,
+					partition,
+					state.FirstOffset,
+					state.EndOffset,
+				),
+			)
+		}
+	}
+
+	return err
+}
+
+// ClampedOffsets returns a copy of the argument new offsets in which the offsets that are outside
+// of their partition bounds are moved to the nearest bound. Offsets for partitions without
+// states are left as-is so that CheckOffsets still reports them.
+func ClampedOffsets(
+	states []PartitionOffsetState,
+	partitionOffsets map[int]int64,
+) map[int]int64 {
+	statesMap := map[int]PartitionOffsetState{}
+	for _, state := range states {
+		statesMap[state.Partition] = state
+	}
+
+	clamped := map[int]int64{}
+	for partition, offset := range partitionOffsets {
+		if state, ok := statesMap[partition]; ok {
+			clamped[partition] = state.Clamp(offset)
+		} else {
+			clamped[partition] = offset
+		}
+	}
+
+	return clamped
+}
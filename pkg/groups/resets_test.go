@@ -0,0 +1,144 @@
+package groups
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShiftedOffsets(t *testing.T) {
+	states := []PartitionOffsetState{
+		{
+			Partition:       0,
+			CommittedOffset: 50,
+			FirstOffset:     10,
+			EndOffset:       100,
+		},
+		{
+			Partition:       1,
+			CommittedOffset: 15,
+			FirstOffset:     10,
+			EndOffset:       100,
+		},
+		{
+			Partition:       2,
+			CommittedOffset: 95,
+			FirstOffset:     10,
+			EndOffset:       100,
+		},
+	}
+
+	offsets, err := ShiftedOffsets(states, -10)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: 40, 1: 10, 2: 85}, offsets)
+
+	offsets, err = ShiftedOffsets(states, 10)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: 60, 1: 25, 2: 100}, offsets)
+
+	_, err = ShiftedOffsets(
+		[]PartitionOffsetState{
+			{
+				Partition:       0,
+				CommittedOffset: -1,
+				FirstOffset:     10,
+				EndOffset:       100,
+			},
+		},
+		10,
+	)
+	assert.Error(t, err)
+}
+
+func TestLoadOffsetsFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "offsets")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	validPath := filepath.Join(tempDir, "valid.json")
+	require.NoError(
+		t,
+		ioutil.WriteFile(validPath, []byte(`{"0": 1234, "3": 5678}`), 0644),
+	)
+	offsets, err := LoadOffsetsFile(validPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: 1234, 3: 5678}, offsets)
+
+	invalidPartitionPath := filepath.Join(tempDir, "invalid-partition.json")
+	require.NoError(
+		t,
+		ioutil.WriteFile(invalidPartitionPath, []byte(`{"zero": 1234}`), 0644),
+	)
+	_, err = LoadOffsetsFile(invalidPartitionPath)
+	assert.Error(t, err)
+
+	emptyPath := filepath.Join(tempDir, "empty.json")
+	require.NoError(t, ioutil.WriteFile(emptyPath, []byte(`{}`), 0644))
+	_, err = LoadOffsetsFile(emptyPath)
+	assert.Error(t, err)
+}
+
+func TestSelectPartitions(t *testing.T) {
+	partitions, err := SelectPartitions("topic1", []int{3, 0, 2, 1}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, partitions)
+
+	partitions, err = SelectPartitions("topic1", []int{0, 1, 2, 3}, []int{3, 1, 3})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, partitions)
+
+	_, err = SelectPartitions("topic1", []int{0, 1, 2, 3}, []int{1, 7, -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Partition 7 not found in topic topic1")
+	assert.Contains(t, err.Error(), "Partition -1 not found in topic topic1")
+}
+
+func TestCheckOffsets(t *testing.T) {
+	states := []PartitionOffsetState{
+		{
+			Partition:   0,
+			FirstOffset: 10,
+			EndOffset:   100,
+		},
+		{
+			Partition:   1,
+			FirstOffset: 10,
+			EndOffset:   100,
+		},
+	}
+
+	assert.NoError(t, CheckOffsets(states, map[int]int64{0: 10, 1: 100}))
+	assert.Error(t, CheckOffsets(states, map[int]int64{0: 5}))
+	assert.Error(t, CheckOffsets(states, map[int]int64{1: 101}))
+	assert.Error(t, CheckOffsets(states, map[int]int64{2: 50}))
+}
+
+func TestClampedOffsets(t *testing.T) {
+	states := []PartitionOffsetState{
+		{
+			Partition:   0,
+			FirstOffset: 10,
+			EndOffset:   100,
+		},
+		{
+			Partition:   1,
+			FirstOffset: 10,
+			EndOffset:   100,
+		},
+	}
+
+	assert.Equal(
+		t,
+		map[int]int64{0: 10, 1: 100, 2: 50},
+		ClampedOffsets(states, map[int]int64{0: 5, 1: 150, 2: 50}),
+	)
+	assert.Equal(
+		t,
+		map[int]int64{0: 50},
+		ClampedOffsets(states, map[int]int64{0: 50}),
+	)
+}
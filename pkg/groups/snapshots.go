@@ -0,0 +1,160 @@
+package groups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// GroupOffsetsSnapshot is a point-in-time record of the offsets that a consumer group has
+// committed in each topic.
+type GroupOffsetsSnapshot struct {
+	GroupID    string                 `json:"groupID"`
+	CapturedAt time.Time              `json:"capturedAt"`
+	Topics     []TopicOffsetsSnapshot `json:"topics"`
+}
+
+// TopicOffsetsSnapshot contains the committed offsets of a consumer group in a single topic.
+type TopicOffsetsSnapshot struct {
+	Topic string `json:"topic"`
+
+	// Offsets maps from partition ID to committed offset. This has the same structure as the
+	// offsets files used by reset-offsets, so the offsets for a topic can be restored from it.
+	Offsets map[int]int64 `json:"offsets"`
+}
+
+// SnapshotGroupOffsets creates an offsets snapshot for the argument group from its partition
+// lags. If topics is non-empty, only the offsets in those topics are included. The topics in
+// the result are sorted by name.
+func SnapshotGroupOffsets(
+	groupID string,
+	partitionLags []PartitionLag,
+	topics map[string]struct{},
+	capturedAt time.Time,
+) GroupOffsetsSnapshot {
+	topicOffsets := map[string]map[int]int64{}
+
+	for _, partitionLag := range partitionLags {
+		if _, ok := topics[partitionLag.Topic]; !ok && len(topics) > 0 {
+			continue
+		}
+		if _, ok := topicOffsets[partitionLag.Topic]; !ok {
+			topicOffsets[partitionLag.Topic] = map[int]int64{}
+		}
+		topicOffsets[partitionLag.Topic][partitionLag.Partition] = partitionLag.CommittedOffset
+	}
+
+	snapshot := GroupOffsetsSnapshot{
+		GroupID:    groupID,
+		CapturedAt: capturedAt.UTC(),
+		Topics:     []TopicOffsetsSnapshot{},
+	}
+	for topic, offsets := range topicOffsets {
+		snapshot.Topics = append(
+			snapshot.Topics,
+			TopicOffsetsSnapshot{
+				Topic:   topic,
+				Offsets: offsets,
+			},
+		)
+	}
+
+	sort.Slice(snapshot.Topics, func(a, b int) bool {
+		return snapshot.Topics[a].Topic < snapshot.Topics[b].Topic
+	})
+
+	return snapshot
+}
+
+// ToYAML converts the current GroupOffsetsSnapshot to a YAML string.
+func (g GroupOffsetsSnapshot) ToYAML() (string, error) {
+	outBytes, err := yaml.Marshal(g)
+	if err != nil {
+		return "", err
+	}
+	return string(outBytes), nil
+}
+
+// ToJSON converts the current GroupOffsetsSnapshot to an indented JSON string.
+func (g GroupOffsetsSnapshot) ToJSON() (string, error) {
+	outBytes, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(outBytes) + "\n", nil
+}
+
+// FilterTopics returns a copy of the current GroupOffsetsSnapshot that only contains the offsets
+// in the argument topics. An error is returned if any of the topics isn't in the snapshot.
+func (g GroupOffsetsSnapshot) FilterTopics(topics []string) (GroupOffsetsSnapshot, error) {
+	topicsMap := map[string]TopicOffsetsSnapshot{}
+	for _, topicOffsets := range g.Topics {
+		topicsMap[topicOffsets.Topic] = topicOffsets
+	}
+
+	filtered := GroupOffsetsSnapshot{
+		GroupID:    g.GroupID,
+		CapturedAt: g.CapturedAt,
+		Topics:     []TopicOffsetsSnapshot{},
+	}
+	for _, topic := range topics {
+		topicOffsets, ok := topicsMap[topic]
+		if !ok {
+			return GroupOffsetsSnapshot{}, fmt.Errorf(
+				"Topic %s is not in the offsets snapshot for group %s",
+				topic,
+				g.GroupID,
+			)
+		}
+		filtered.Topics = append(filtered.Topics, topicOffsets)
+	}
+
+	sort.Slice(filtered.Topics, func(a, b int) bool {
+		return filtered.Topics[a].Topic < filtered.Topics[b].Topic
+	})
+
+	return filtered, nil
+}
+
+// LoadGroupOffsetsSnapshot loads an offsets snapshot from the argument path. The file can be in
+// either JSON or YAML format, so the snapshots written by bootstrap can be loaded too.
+func LoadGroupOffsetsSnapshot(path string) (GroupOffsetsSnapshot, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return GroupOffsetsSnapshot{}, err
+	}
+
+	snapshot := GroupOffsetsSnapshot{}
+	if err := yaml.Unmarshal(contents, &snapshot); err != nil {
+		return GroupOffsetsSnapshot{}, fmt.Errorf(
+			"Error parsing offsets snapshot %s: %+v",
+			path,
+			err,
+		)
+	}
+	if snapshot.GroupID == "" {
+		return GroupOffsetsSnapshot{}, errors.New("Offsets snapshot doesn't have a groupID")
+	}
+
+	for _, topicOffsets := range snapshot.Topics {
+		if topicOffsets.Topic == "" {
+			return GroupOffsetsSnapshot{}, errors.New("All topics in offsets snapshot must have names")
+		}
+		for partition, offset := range topicOffsets.Offsets {
+			if offset < 0 {
+				return GroupOffsetsSnapshot{}, fmt.Errorf(
+					"Offset for topic %s, partition %d in offsets snapshot is negative",
+					topicOffsets.Topic,
+					partition,
+				)
+			}
+		}
+	}
+
+	return snapshot, nil
+}
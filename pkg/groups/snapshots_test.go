@@ -0,0 +1,140 @@
+package groups
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotGroupOffsets(t *testing.T) {
+	capturedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	partitionLags := []PartitionLag{
+		{Topic: "topic2", Partition: 0, CommittedOffset: 5, EndOffset: 10},
+		{Topic: "topic1", Partition: 1, CommittedOffset: 20, EndOffset: 30},
+		{Topic: "topic1", Partition: 0, CommittedOffset: 15, EndOffset: 30},
+	}
+
+	snapshot := SnapshotGroupOffsets("group1", partitionLags, nil, capturedAt)
+	assert.Equal(
+		t,
+		GroupOffsetsSnapshot{
+			GroupID:    "group1",
+			CapturedAt: capturedAt,
+			Topics: []TopicOffsetsSnapshot{
+				{
+					Topic:   "topic1",
+					Offsets: map[int]int64{0: 15, 1: 20},
+				},
+				{
+					Topic:   "topic2",
+					Offsets: map[int]int64{0: 5},
+				},
+			},
+		},
+		snapshot,
+	)
+
+	yamlStr, err := snapshot.ToYAML()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`capturedAt: "2024-03-01T12:00:00Z"
+groupID: group1
+topics:
+- offsets:
+    "0": 15
+    "1": 20
+  topic: topic1
+- offsets:
+    "0": 5
+  topic: topic2
+`,
+		yamlStr,
+	)
+
+	filteredSnapshot := SnapshotGroupOffsets(
+		"group1",
+		partitionLags,
+		map[string]struct{}{"topic2": {}},
+		capturedAt,
+	)
+	assert.Equal(
+		t,
+		[]TopicOffsetsSnapshot{
+			{
+				Topic:   "topic2",
+				Offsets: map[int]int64{0: 5},
+			},
+		},
+		filteredSnapshot.Topics,
+	)
+}
+
+func TestLoadGroupOffsetsSnapshot(t *testing.T) {
+	capturedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := GroupOffsetsSnapshot{
+		GroupID:    "group1",
+		CapturedAt: capturedAt,
+		Topics: []TopicOffsetsSnapshot{
+			{
+				Topic:   "topic1",
+				Offsets: map[int]int64{0: 15, 1: 20},
+			},
+			{
+				Topic:   "topic2",
+				Offsets: map[int]int64{0: 5},
+			},
+		},
+	}
+
+	tempDir, err := ioutil.TempDir("", "snapshots")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	jsonStr, err := snapshot.ToJSON()
+	require.NoError(t, err)
+	jsonPath := filepath.Join(tempDir, "group1.json")
+	require.NoError(t, ioutil.WriteFile(jsonPath, []byte(jsonStr), 0644))
+
+	loaded, err := LoadGroupOffsetsSnapshot(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, loaded)
+
+	yamlStr, err := snapshot.ToYAML()
+	require.NoError(t, err)
+	yamlPath := filepath.Join(tempDir, "group1.yaml")
+	require.NoError(t, ioutil.WriteFile(yamlPath, []byte(yamlStr), 0644))
+
+	loaded, err = LoadGroupOffsetsSnapshot(yamlPath)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, loaded)
+
+	filtered, err := loaded.FilterTopics([]string{"topic2"})
+	require.NoError(t, err)
+	assert.Equal(t, []TopicOffsetsSnapshot{snapshot.Topics[1]}, filtered.Topics)
+
+	_, err = loaded.FilterTopics([]string{"topic3"})
+	assert.Error(t, err)
+
+	noGroupPath := filepath.Join(tempDir, "nogroup.json")
+	require.NoError(t, ioutil.WriteFile(noGroupPath, []byte(`{"topics": []}`), 0644))
+	_, err = LoadGroupOffsetsSnapshot(noGroupPath)
+	assert.Error(t, err)
+
+	negativePath := filepath.Join(tempDir, "negative.json")
+	require.NoError(
+		t,
+		ioutil.WriteFile(
+			negativePath,
+			[]byte(`{"groupID": "group1", "topics": [{"topic": "topic1", "offsets": {"0": -1}}]}`),
+			0644,
+		),
+	)
+	_, err = LoadGroupOffsetsSnapshot(negativePath)
+	assert.Error(t, err)
+}
@@ -9,15 +9,41 @@ import (
 
 // GroupCoordinator stores the coordinator broker for a single consumer group.
 type GroupCoordinator struct {
-	GroupID     string
-	Coordinator int
+	GroupID     string `json:"groupID"`
+	Coordinator int    `json:"coordinator"`
+}
+
+// GroupSummary stores the coordinator broker, state, and size of a single consumer group.
+type GroupSummary struct {
+	GroupID     string `json:"groupID"`
+	Coordinator int    `json:"coordinator"`
+
+	// State is the group state, e.g. "Stable", "Empty", or "Dead".
+	State string `json:"state"`
+
+	// ProtocolType is the type of the group protocol, e.g. "consumer" for regular consumer
+	// groups, and Protocol is the assignment strategy within it; the latter is only set when
+	// the group is stable.
+	ProtocolType string `json:"protocolType"`
+	Protocol     string `json:"protocol"`
+
+	Members int `json:"members"`
 }
 
 // GroupDetails stores the state and members for a consumer group.
 type GroupDetails struct {
-	GroupID string
-	State   string
-	Members []MemberInfo
+	GroupID string `json:"groupID"`
+	State   string `json:"state"`
+
+	// ProtocolType is the type of the group protocol, e.g. "consumer" for regular
+	// consumer groups.
+	ProtocolType string `json:"protocolType"`
+
+	// AssignmentStrategy is the partition assignor chosen by the group (e.g., "range" or
+	// "cooperative-sticky"); it's only set when the group is stable.
+	AssignmentStrategy string `json:"assignmentStrategy"`
+
+	Members []MemberInfo `json:"members"`
 }
 
 // TopicsMap returns a map of all the topics consumed by the current group.
@@ -33,6 +59,28 @@ func (g GroupDetails) TopicsMap() map[string]struct{} {
 	return topicsMap
 }
 
+// StaticMembers returns the number of members in the group that use static membership, i.e.
+// that set a group.instance.id.
+func (g GroupDetails) StaticMembers() int {
+	staticMembers := 0
+
+	for _, member := range g.Members {
+		if member.IsStatic() {
+			staticMembers++
+		}
+	}
+
+	return staticMembers
+}
+
+// MixedMembership returns whether the group has both static and dynamic members. Dynamic members
+// trigger rebalances when they restart while static ones don't, so mixing the two often leads to
+// confusing assignments.
+func (g GroupDetails) MixedMembership() bool {
+	staticMembers := g.StaticMembers()
+	return staticMembers > 0 && staticMembers < len(g.Members)
+}
+
 // PartitionMembers returns the members for each partition in the argument topic.
 func (g GroupDetails) PartitionMembers(topic string) map[int]MemberInfo {
 	partitionsMap := map[int]MemberInfo{}
@@ -55,10 +103,22 @@ func (g GroupDetails) PartitionMembers(topic string) map[int]MemberInfo {
 
 // MemberInfo stores information about a single consumer group member.
 type MemberInfo struct {
-	MemberID        string
-	ClientID        string
-	ClientHost      string
-	TopicPartitions map[string][]int
+	MemberID        string           `json:"memberID"`
+	ClientID        string           `json:"clientID"`
+	ClientHost      string           `json:"clientHost"`
+	GroupInstanceID string           `json:"groupInstanceID"`
+	TopicPartitions map[string][]int `json:"topicPartitions"`
+
+	// SubscribedTopics are the topics in the member's subscription, and SubscriptionVersion is
+	// the version of the consumer protocol that the subscription is encoded with. These come from
+	// the member's metadata for the group's assignment strategy.
+	SubscribedTopics    []string `json:"subscribedTopics"`
+	SubscriptionVersion int      `json:"subscriptionVersion"`
+}
+
+// IsStatic returns whether the member uses static membership, i.e. sets a group.instance.id.
+func (m MemberInfo) IsStatic() bool {
+	return m.GroupInstanceID != ""
 }
 
 // Topics returns a slice of all topics that the current MemberInfo is consuming from.
@@ -79,13 +139,14 @@ func (m MemberInfo) Topics() []string {
 // MemberPartitionLag information about the lag for a single topic / partition / group member
 // combination.
 type MemberPartitionLag struct {
-	Topic        string
-	Partition    int
-	MemberID     string
-	NewestOffset int64
-	NewestTime   time.Time
-	MemberOffset int64
-	MemberTime   time.Time
+	Topic        string    `json:"topic"`
+	Partition    int       `json:"partition"`
+	MemberID     string    `json:"memberID"`
+	InstanceID   string    `json:"instanceID,omitempty"`
+	NewestOffset int64     `json:"newestOffset"`
+	NewestTime   time.Time `json:"newestTime"`
+	MemberOffset int64     `json:"memberOffset"`
+	MemberTime   time.Time `json:"memberTime"`
 }
 
 // OffsetLag returns the difference between the latest offset in the partition and the latest one
@@ -99,3 +160,77 @@ func (m MemberPartitionLag) OffsetLag() int64 {
 func (m MemberPartitionLag) TimeLag() time.Duration {
 	return m.NewestTime.Sub(m.MemberTime)
 }
+
+// PartitionLag is the lag of a consumer group in a single topic partition, based on the
+// group's committed offset.
+type PartitionLag struct {
+	Topic           string `json:"topic"`
+	Partition       int    `json:"partition"`
+	CommittedOffset int64  `json:"committedOffset"`
+	EndOffset       int64  `json:"endOffset"`
+}
+
+// Lag returns the number of messages in the partition after the group's committed offset.
+func (p PartitionLag) Lag() int64 {
+	if p.EndOffset < p.CommittedOffset {
+		return 0
+	}
+	return p.EndOffset - p.CommittedOffset
+}
+
+// TotalLag returns the sum of the lags across all of the argument partitions.
+func TotalLag(partitionLags []PartitionLag) int64 {
+	var total int64
+	for _, partitionLag := range partitionLags {
+		total += partitionLag.Lag()
+	}
+	return total
+}
+
+// TopicConsumer summarizes the activity of a consumer group in a single topic.
+type TopicConsumer struct {
+	GroupID string
+
+	// ActiveMembers is the number of group members that are currently assigned partitions in
+	// the topic.
+	ActiveMembers int
+
+	// LastConsumedTime is the newest timestamp across the messages just before the group's
+	// committed offsets in the topic. It's the zero time if the group hasn't consumed anything.
+	LastConsumedTime time.Time
+}
+
+// ActiveSince returns whether the group has active members in the topic or has consumed messages
+// that were produced after the argument time.
+func (t TopicConsumer) ActiveSince(since time.Time) bool {
+	return t.ActiveMembers > 0 || t.LastConsumedTime.After(since)
+}
+
+// PartitionOffsetState is the state of a consumer group in a single topic partition, along
+// with the bounds of the partition, that's used for previewing and validating offset resets.
+type PartitionOffsetState struct {
+	Partition int
+
+	// CommittedOffset is the group's current committed offset in the partition, or -1 if the
+	// group hasn't committed anything.
+	CommittedOffset int64
+
+	FirstOffset int64
+	EndOffset   int64
+}
+
+// Clamp returns the argument offset moved, if needed, so that it's within the bounds of the
+// partition.
+func (p PartitionOffsetState) Clamp(offset int64) int64 {
+	if offset < p.FirstOffset {
+		return p.FirstOffset
+	} else if offset > p.EndOffset {
+		return p.EndOffset
+	}
+	return offset
+}
+
+// InBounds returns whether the argument offset is within the bounds of the partition.
+func (p PartitionOffsetState) InBounds(offset int64) bool {
+	return offset >= p.FirstOffset && offset <= p.EndOffset
+}
@@ -0,0 +1,205 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Format is a string type that identifies the output format of the logs.
+type Format string
+
+const (
+	// FormatText writes logs in the standard logger's text format. This is the default.
+	FormatText Format = "text"
+
+	// FormatJSON writes each log entry as a JSON object. Entries from subsystem loggers include
+	// the name of the subsystem in the "subsystem" field.
+	FormatJSON Format = "json"
+)
+
+// Subsystem is a string type that identifies a part of topicctl that has its own logger.
+type Subsystem string
+
+const (
+	// SubsystemAdmin is the subsystem for the admin clients.
+	SubsystemAdmin Subsystem = "admin"
+
+	// SubsystemApply is the subsystem for apply runs, including rebalances.
+	SubsystemApply Subsystem = "apply"
+
+	// SubsystemMessages is the subsystem for producing and tailing messages.
+	SubsystemMessages Subsystem = "messages"
+
+	// SubsystemZK is the subsystem for the zookeeper client.
+	SubsystemZK Subsystem = "zk"
+)
+
+// AllSubsystems are all of the subsystems that have their own loggers.
+var AllSubsystems = []Subsystem{
+	SubsystemAdmin,
+	SubsystemApply,
+	SubsystemMessages,
+	SubsystemZK,
+}
+
+// Config contains the settings for the standard and subsystem loggers.
+type Config struct {
+	Format Format
+	Level  logrus.Level
+
+	// SubsystemLevels override Level for individual subsystems.
+	SubsystemLevels map[Subsystem]logrus.Level
+}
+
+var (
+	loggersMutex sync.Mutex
+	loggers      = map[Subsystem]*logrus.Logger{}
+
+	// config is the config from the last call to Configure, if any.
+	config *Config
+)
+
+// Logger returns the logger for the argument subsystem. Until Configure is called, the logger
+// uses the same output, formatter, and level as the standard logger did when it was created.
+func Logger(subsystem Subsystem) *logrus.Logger {
+	loggersMutex.Lock()
+	defer loggersMutex.Unlock()
+
+	logger, ok := loggers[subsystem]
+	if !ok {
+		logger = logrus.New()
+		if config != nil {
+			configureLogger(subsystem, logger, *config)
+		} else {
+			std := logrus.StandardLogger()
+			logger.SetOutput(std.Out)
+			logger.SetFormatter(std.Formatter)
+			logger.SetLevel(std.GetLevel())
+		}
+		loggers[subsystem] = logger
+	}
+
+	return logger
+}
+
+// Configure applies the argument config to the standard logger and all of the subsystem
+// loggers. The subsystem loggers write to the same output and use the same hooks as the
+// standard logger; in the text format, they also use its formatter.
+func Configure(loggingConfig Config) {
+	std := logrus.StandardLogger()
+	std.SetLevel(loggingConfig.Level)
+	if loggingConfig.Format == FormatJSON {
+		std.SetFormatter(&logrus.JSONFormatter{})
+	}
+
+	loggersMutex.Lock()
+	defer loggersMutex.Unlock()
+
+	config = &loggingConfig
+	for subsystem, logger := range loggers {
+		configureLogger(subsystem, logger, loggingConfig)
+	}
+}
+
+func configureLogger(subsystem Subsystem, logger *logrus.Logger, loggingConfig Config) {
+	std := logrus.StandardLogger()
+
+	logger.SetOutput(std.Out)
+	logger.ReplaceHooks(std.Hooks)
+
+	if loggingConfig.Format == FormatJSON {
+		logger.SetFormatter(
+			&subsystemFormatter{
+				subsystem: subsystem,
+				formatter: std.Formatter,
+			},
+		)
+	} else {
+		logger.SetFormatter(std.Formatter)
+	}
+
+	if level, ok := loggingConfig.SubsystemLevels[subsystem]; ok {
+		logger.SetLevel(level)
+	} else {
+		logger.SetLevel(loggingConfig.Level)
+	}
+}
+
+// ParseFormat converts the argument string to a Format.
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case FormatText, FormatJSON:
+		return Format(format), nil
+	default:
+		return "", fmt.Errorf(
+			"Unrecognized log format %s; must be one of: %s, %s",
+			format,
+			FormatText,
+			FormatJSON,
+		)
+	}
+}
+
+// ParseSubsystemLevels converts the argument [subsystem]=[level] strings, e.g. "zk=warn", to a
+// map from subsystem to level.
+func ParseSubsystemLevels(values []string) (map[Subsystem]logrus.Level, error) {
+	subsystemLevels := map[Subsystem]logrus.Level{}
+
+	for _, value := range values {
+		elements := strings.SplitN(value, "=", 2)
+		if len(elements) != 2 {
+			return nil, fmt.Errorf(
+				"Subsystem log level %s is not in the format [subsystem]=[level]",
+				value,
+			)
+		}
+
+		subsystem := Subsystem(strings.TrimSpace(elements[0]))
+		if !isValidSubsystem(subsystem) {
+			return nil, fmt.Errorf(
+				"Unrecognized log subsystem %s; must be one of: %+v",
+				subsystem,
+				AllSubsystems,
+			)
+		}
+
+		level, err := logrus.ParseLevel(strings.TrimSpace(elements[1]))
+		if err != nil {
+			return nil, err
+		}
+		subsystemLevels[subsystem] = level
+	}
+
+	return subsystemLevels, nil
+}
+
+func isValidSubsystem(subsystem Subsystem) bool {
+	for _, validSubsystem := range AllSubsystems {
+		if subsystem == validSubsystem {
+			return true
+		}
+	}
+	return false
+}
+
+// subsystemFormatter is a logrus.Formatter that adds the name of a subsystem to each entry
+// before formatting it with another formatter.
+type subsystemFormatter struct {
+	subsystem Subsystem
+	formatter logrus.Formatter
+}
+
+var _ logrus.Formatter = (*subsystemFormatter)(nil)
+
+// Format formats the argument entry.
+func (s *subsystemFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	subsystemEntry := entry.WithField("subsystem", string(s.subsystem))
+	subsystemEntry.Time = entry.Time
+	subsystemEntry.Level = entry.Level
+	subsystemEntry.Message = entry.Message
+
+	return s.formatter.Format(subsystemEntry)
+}
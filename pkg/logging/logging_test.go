@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSubsystemLevels(t *testing.T) {
+	subsystemLevels, err := ParseSubsystemLevels([]string{"zk=warn", " apply = debug"})
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		map[Subsystem]logrus.Level{
+			SubsystemZK:    logrus.WarnLevel,
+			SubsystemApply: logrus.DebugLevel,
+		},
+		subsystemLevels,
+	)
+
+	_, err = ParseSubsystemLevels([]string{"zk"})
+	assert.Error(t, err)
+	_, err = ParseSubsystemLevels([]string{"unknown=info"})
+	assert.Error(t, err)
+	_, err = ParseSubsystemLevels([]string{"zk=loud"})
+	assert.Error(t, err)
+
+	_, err = ParseFormat("json")
+	assert.NoError(t, err)
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestConfigure(t *testing.T) {
+	std := logrus.StandardLogger()
+	prevOut := std.Out
+	prevFormatter := std.Formatter
+	prevLevel := std.GetLevel()
+	defer func() {
+		std.SetOutput(prevOut)
+		std.SetFormatter(prevFormatter)
+		std.SetLevel(prevLevel)
+	}()
+
+	buf := &bytes.Buffer{}
+	std.SetOutput(buf)
+
+	adminLogger := Logger(SubsystemAdmin)
+	Configure(
+		Config{
+			Format: FormatJSON,
+			Level:  logrus.InfoLevel,
+			SubsystemLevels: map[Subsystem]logrus.Level{
+				SubsystemZK: logrus.DebugLevel,
+			},
+		},
+	)
+
+	// Loggers created after Configure also get the config
+	zkLogger := Logger(SubsystemZK)
+
+	adminLogger.Debugf("admin debug")
+	adminLogger.Infof("admin info")
+	zkLogger.Debugf("zk debug")
+	std.Infof("std info")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, 3, len(lines))
+
+	entries := []map[string]interface{}{}
+	for _, line := range lines {
+		entry := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+
+	assert.Equal(t, "admin info", entries[0]["msg"])
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, "admin", entries[0]["subsystem"])
+	assert.Equal(t, "zk debug", entries[1]["msg"])
+	assert.Equal(t, "zk", entries[1]["subsystem"])
+	assert.Equal(t, "std info", entries[2]["msg"])
+	assert.Nil(t, entries[2]["subsystem"])
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/admin"
-	log "github.com/sirupsen/logrus"
 
 	// Read snappy-compressed messages
 	_ "github.com/segmentio/kafka-go/snappy"
@@ -34,11 +33,11 @@ const (
 // Bounds represents the start and end "bounds" of the messages in
 // a partition.
 type Bounds struct {
-	Partition   int
-	FirstTime   time.Time
-	FirstOffset int64
-	LastTime    time.Time
-	LastOffset  int64
+	Partition   int       `json:"partition"`
+	FirstTime   time.Time `json:"firstTime"`
+	FirstOffset int64     `json:"firstOffset"`
+	LastTime    time.Time `json:"lastTime"`
+	LastOffset  int64     `json:"lastOffset"`
 }
 
 // GetAllPartitionBounds gets the bounds for all partitions in the argument topic.
@@ -0,0 +1,365 @@
+package messages
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/linkedin/goavro/v2"
+)
+
+// DecoderType is a string type that identifies how message keys or values are decoded.
+type DecoderType string
+
+const (
+	// DecoderTypeString shows keys and values as strings, or as byte arrays if they aren't
+	// valid UTF-8. This is the default.
+	DecoderTypeString DecoderType = "string"
+
+	// DecoderTypeJSON validates that keys or values are JSON and shows them in compact form.
+	DecoderTypeJSON DecoderType = "json"
+
+	// DecoderTypeAvro decodes Avro data serialized with a Schema Registry.
+	DecoderTypeAvro DecoderType = "avro"
+
+	// DecoderTypeProtobuf decodes protobuf data serialized with a Schema Registry.
+	DecoderTypeProtobuf DecoderType = "protobuf"
+
+	// DecoderTypeJSONSchema decodes JSON data serialized with a Schema Registry.
+	DecoderTypeJSONSchema DecoderType = "json-schema"
+)
+
+// AllDecoderTypes contains all of the supported decoder types.
+var AllDecoderTypes = []DecoderType{
+	DecoderTypeString,
+	DecoderTypeJSON,
+	DecoderTypeAvro,
+	DecoderTypeProtobuf,
+	DecoderTypeJSONSchema,
+}
+
+// Decoder converts the raw bytes in a message key or value into a printable string.
+type Decoder interface {
+	Decode(ctx context.Context, data []byte) (string, error)
+}
+
+// NewDecoder returns a Decoder for the argument type. The registry client is only required
+// for the types that need to fetch schemas from a Schema Registry.
+func NewDecoder(
+	decoderType DecoderType,
+	registryClient *SchemaRegistryClient,
+) (Decoder, error) {
+	switch decoderType {
+	case "", DecoderTypeString:
+		return &StringDecoder{}, nil
+	case DecoderTypeJSON:
+		return &JSONDecoder{}, nil
+	case DecoderTypeJSONSchema:
+		return &JSONSchemaDecoder{}, nil
+	case DecoderTypeAvro:
+		if registryClient == nil {
+			return nil, errors.New("A schema registry URL is required for the avro decoder")
+		}
+		return &AvroDecoder{
+			registryClient: registryClient,
+			codecs:         map[int]*goavro.Codec{},
+		}, nil
+	case DecoderTypeProtobuf:
+		if registryClient == nil {
+			return nil, errors.New("A schema registry URL is required for the protobuf decoder")
+		}
+		return &ProtobufDecoder{
+			registryClient: registryClient,
+			files:          map[int]*desc.FileDescriptor{},
+		}, nil
+	default:
+		return nil, fmt.Errorf(
+			"Unrecognized decoder type '%s'; choices are %+v",
+			decoderType,
+			AllDecoderTypes,
+		)
+	}
+}
+
+// StringDecoder is a Decoder that shows data as a string.
+type StringDecoder struct{}
+
+// Decode converts the argument data into a string.
+func (d *StringDecoder) Decode(ctx context.Context, data []byte) (string, error) {
+	return bytesToStr(data), nil
+}
+
+// JSONDecoder is a Decoder for data that's stored as plain JSON.
+type JSONDecoder struct{}
+
+// Decode validates that the argument data is JSON and compacts it.
+func (d *JSONDecoder) Decode(ctx context.Context, data []byte) (string, error) {
+	return compactJSON(data)
+}
+
+// JSONSchemaDecoder is a Decoder for JSON data that's serialized with a Schema Registry.
+// The schema isn't needed to decode the data, so it isn't fetched.
+type JSONSchemaDecoder struct{}
+
+// Decode strips the Schema Registry header from the argument data and compacts the JSON
+// payload.
+func (d *JSONSchemaDecoder) Decode(ctx context.Context, data []byte) (string, error) {
+	_, payload, err := splitRegistryHeader(data)
+	if err != nil {
+		return "", err
+	}
+	return compactJSON(payload)
+}
+
+// AvroDecoder is a Decoder for Avro data that's serialized with a Schema Registry.
+type AvroDecoder struct {
+	registryClient *SchemaRegistryClient
+
+	sync.Mutex
+	codecs map[int]*goavro.Codec
+}
+
+// Decode converts the argument Avro data into JSON.
+func (d *AvroDecoder) Decode(ctx context.Context, data []byte) (string, error) {
+	schemaID, payload, err := splitRegistryHeader(data)
+	if err != nil {
+		return "", err
+	}
+
+	codec, err := d.getCodec(ctx, schemaID)
+	if err != nil {
+		return "", err
+	}
+
+	native, _, err := codec.NativeFromBinary(payload)
+	if err != nil {
+		return "", fmt.Errorf("Error decoding Avro data with schema %d: %+v", schemaID, err)
+	}
+	textual, err := codec.TextualFromNative(nil, native)
+	if err != nil {
+		return "", fmt.Errorf("Error converting Avro data to JSON: %+v", err)
+	}
+
+	return string(textual), nil
+}
+
+func (d *AvroDecoder) getCodec(ctx context.Context, schemaID int) (*goavro.Codec, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	if codec, ok := d.codecs[schemaID]; ok {
+		return codec, nil
+	}
+
+	schema, err := d.registryClient.GetSchemaByID(ctx, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	if schema.SchemaType != "" && schema.SchemaType != "AVRO" {
+		return nil, fmt.Errorf("Schema %d has type %s, not AVRO", schemaID, schema.SchemaType)
+	}
+	if len(schema.References) > 0 {
+		return nil, fmt.Errorf("Schema %d has references, which aren't supported for Avro", schemaID)
+	}
+
+	codec, err := goavro.NewCodec(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Avro schema %d: %+v", schemaID, err)
+	}
+	d.codecs[schemaID] = codec
+
+	return codec, nil
+}
+
+// ProtobufDecoder is a Decoder for protobuf data that's serialized with a Schema Registry.
+type ProtobufDecoder struct {
+	registryClient *SchemaRegistryClient
+
+	sync.Mutex
+	files map[int]*desc.FileDescriptor
+}
+
+// Decode converts the argument protobuf data into JSON.
+func (d *ProtobufDecoder) Decode(ctx context.Context, data []byte) (string, error) {
+	schemaID, payload, err := splitRegistryHeader(data)
+	if err != nil {
+		return "", err
+	}
+
+	// The header is followed by the indices of the message type in the schema, e.g. [1, 0]
+	// for the first message nested in the second top-level message.
+	indices, payload, err := readMessageIndices(payload)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := d.getFile(ctx, schemaID)
+	if err != nil {
+		return "", err
+	}
+	messageDesc, err := messageForIndices(file, indices)
+	if err != nil {
+		return "", fmt.Errorf("Error finding message type in schema %d: %+v", schemaID, err)
+	}
+
+	message := dynamic.NewMessage(messageDesc)
+	if err := message.Unmarshal(payload); err != nil {
+		return "", fmt.Errorf(
+			"Error decoding protobuf data as %s: %+v",
+			messageDesc.GetFullyQualifiedName(),
+			err,
+		)
+	}
+	jsonBytes, err := message.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (d *ProtobufDecoder) getFile(
+	ctx context.Context,
+	schemaID int,
+) (*desc.FileDescriptor, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	if file, ok := d.files[schemaID]; ok {
+		return file, nil
+	}
+
+	schema, err := d.registryClient.GetSchemaByID(ctx, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	if schema.SchemaType != "PROTOBUF" {
+		return nil, fmt.Errorf("Schema %d has type %s, not PROTOBUF", schemaID, schema.SchemaType)
+	}
+
+	file, err := parseProtobufSchema(ctx, d.registryClient, schemaID, schema)
+	if err != nil {
+		return nil, err
+	}
+	d.files[schemaID] = file
+
+	return file, nil
+}
+
+// parseProtobufSchema parses the argument registry schema, fetching its references, if any,
+// from the registry.
+func parseProtobufSchema(
+	ctx context.Context,
+	registryClient *SchemaRegistryClient,
+	schemaID int,
+	schema RegistrySchema,
+) (*desc.FileDescriptor, error) {
+	fileName := fmt.Sprintf("schema-%d.proto", schemaID)
+	contents := map[string]string{
+		fileName: schema.Schema,
+	}
+	if err := addProtobufReferences(ctx, registryClient, schema.References, contents); err != nil {
+		return nil, err
+	}
+
+	parser := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(contents),
+	}
+	files, err := parser.ParseFiles(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing protobuf schema %d: %+v", schemaID, err)
+	}
+
+	return files[0], nil
+}
+
+// addProtobufReferences fetches the argument schema references, along with any references that
+// they have in turn, and adds their contents to the argument map keyed by import name.
+func addProtobufReferences(
+	ctx context.Context,
+	registryClient *SchemaRegistryClient,
+	references []RegistryReference,
+	contents map[string]string,
+) error {
+	for _, reference := range references {
+		if _, ok := contents[reference.Name]; ok {
+			continue
+		}
+
+		schema, err := registryClient.GetSchemaByVersion(
+			ctx,
+			reference.Subject,
+			reference.Version,
+		)
+		if err != nil {
+			return err
+		}
+		contents[reference.Name] = schema.Schema
+
+		if err := addProtobufReferences(ctx, registryClient, schema.References, contents); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readMessageIndices reads the message indices that precede protobuf payloads in the Schema
+// Registry wire format. These are encoded as a zig-zag varint count followed by that many
+// zig-zag varint indices; a count of zero is shorthand for [0].
+func readMessageIndices(data []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, nil, errors.New("Could not read protobuf message indices")
+	}
+	data = data[n:]
+
+	if count == 0 {
+		return []int{0}, data, nil
+	}
+
+	indices := []int{}
+	for i := int64(0); i < count; i++ {
+		index, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, nil, errors.New("Could not read protobuf message indices")
+		}
+		indices = append(indices, int(index))
+		data = data[n:]
+	}
+
+	return indices, data, nil
+}
+
+func messageForIndices(
+	file *desc.FileDescriptor,
+	indices []int,
+) (*desc.MessageDescriptor, error) {
+	messages := file.GetMessageTypes()
+	var message *desc.MessageDescriptor
+
+	for _, index := range indices {
+		if index < 0 || index >= len(messages) {
+			return nil, fmt.Errorf("Message index %d is out of range", index)
+		}
+		message = messages[index]
+		messages = message.GetNestedMessageTypes()
+	}
+
+	return message, nil
+}
+
+func compactJSON(data []byte) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, data); err != nil {
+		return "", fmt.Errorf("Data is not valid JSON: %+v", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,242 @@
+package messages
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAvroSchema = `{
+	"type": "record",
+	"name": "User",
+	"fields": [
+		{"name": "name", "type": "string"},
+		{"name": "age", "type": "int"}
+	]
+}`
+
+const testProtoSchema = `syntax = "proto3";
+package test;
+
+import "common.proto";
+
+message Outer {
+	string id = 1;
+
+	message Inner {
+		string value = 1;
+		test.Common common = 2;
+	}
+}
+`
+
+const testProtoReference = `syntax = "proto3";
+package test;
+
+message Common {
+	int32 count = 1;
+}
+`
+
+func TestNewDecoder(t *testing.T) {
+	decoder, err := NewDecoder(DecoderTypeJSON, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &JSONDecoder{}, decoder)
+
+	_, err = NewDecoder(DecoderTypeAvro, nil)
+	assert.Error(t, err)
+	_, err = NewDecoder(DecoderTypeProtobuf, nil)
+	assert.Error(t, err)
+	_, err = NewDecoder("bad-type", nil)
+	assert.Error(t, err)
+
+	_, err = NewSchemaRegistryClient("registry.example.com")
+	assert.Error(t, err)
+}
+
+func TestJSONDecoders(t *testing.T) {
+	ctx := context.Background()
+
+	value, err := (&JSONDecoder{}).Decode(ctx, []byte(`{"key": [1, 2]}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"key":[1,2]}`, value)
+
+	_, err = (&JSONDecoder{}).Decode(ctx, []byte(`not json`))
+	assert.Error(t, err)
+
+	value, err = (&JSONSchemaDecoder{}).Decode(
+		ctx,
+		addRegistryHeader(7, []byte(`{"key": "value"}`)),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, `{"key":"value"}`, value)
+
+	_, err = (&JSONSchemaDecoder{}).Decode(ctx, []byte(`{"key": "value"}`))
+	assert.Error(t, err)
+}
+
+func TestAvroDecoder(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry(
+		map[string]RegistrySchema{
+			"/schemas/ids/3": {
+				Schema: testAvroSchema,
+			},
+		},
+	)
+	defer registry.Close()
+
+	registryClient, err := NewSchemaRegistryClient(registry.URL)
+	require.NoError(t, err)
+	decoder, err := NewDecoder(DecoderTypeAvro, registryClient)
+	require.NoError(t, err)
+
+	codec, err := goavro.NewCodec(testAvroSchema)
+	require.NoError(t, err)
+	payload, err := codec.BinaryFromNative(
+		nil,
+		map[string]interface{}{
+			"name": "alice",
+			"age":  30,
+		},
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		value, err := decoder.Decode(ctx, addRegistryHeader(3, payload))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"alice","age":30}`, value)
+	}
+	assert.Equal(t, 1, registry.requestCount("/schemas/ids/3"))
+
+	_, err = decoder.Decode(ctx, addRegistryHeader(4, payload))
+	assert.Error(t, err)
+}
+
+func TestProtobufDecoder(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry(
+		map[string]RegistrySchema{
+			"/schemas/ids/5": {
+				Schema:     testProtoSchema,
+				SchemaType: "PROTOBUF",
+				References: []RegistryReference{
+					{
+						Name:    "common.proto",
+						Subject: "common",
+						Version: 2,
+					},
+				},
+			},
+			"/subjects/common/versions/2": {
+				Schema:     testProtoReference,
+				SchemaType: "PROTOBUF",
+			},
+		},
+	)
+	defer registry.Close()
+
+	registryClient, err := NewSchemaRegistryClient(registry.URL)
+	require.NoError(t, err)
+	decoder, err := NewDecoder(DecoderTypeProtobuf, registryClient)
+	require.NoError(t, err)
+
+	// Outer with id "abc"
+	outerPayload := append(varints(0), 0x0a, 0x03, 'a', 'b', 'c')
+	value, err := decoder.Decode(ctx, addRegistryHeader(5, outerPayload))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"abc"}`, value)
+
+	// Outer.Inner with value "x" and common.count 4
+	innerPayload := append(varints(2, 0, 0), 0x0a, 0x01, 'x', 0x12, 0x02, 0x08, 0x04)
+	value, err = decoder.Decode(ctx, addRegistryHeader(5, innerPayload))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":"x","common":{"count":4}}`, value)
+
+	assert.Equal(t, 1, registry.requestCount("/schemas/ids/5"))
+	assert.Equal(t, 1, registry.requestCount("/subjects/common/versions/2"))
+
+	_, err = decoder.Decode(ctx, addRegistryHeader(5, append(varints(1, 3), 0x0a)))
+	assert.Error(t, err)
+}
+
+func TestReadMessageIndices(t *testing.T) {
+	indices, rest, err := readMessageIndices(append(varints(0), 0x01))
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, indices)
+	assert.Equal(t, []byte{0x01}, rest)
+
+	indices, rest, err = readMessageIndices(append(varints(3, 1, 0, 2), 0x02))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 0, 2}, indices)
+	assert.Equal(t, []byte{0x02}, rest)
+
+	_, _, err = readMessageIndices(varints(2, 1))
+	assert.Error(t, err)
+}
+
+func TestSplitRegistryHeader(t *testing.T) {
+	schemaID, payload, err := splitRegistryHeader(addRegistryHeader(258, []byte("data")))
+	require.NoError(t, err)
+	assert.Equal(t, 258, schemaID)
+	assert.Equal(t, []byte("data"), payload)
+
+	_, _, err = splitRegistryHeader([]byte{0, 1})
+	assert.Error(t, err)
+	_, _, err = splitRegistryHeader([]byte{1, 0, 0, 0, 1, 2})
+	assert.Error(t, err)
+}
+
+type testRegistry struct {
+	*httptest.Server
+
+	sync.Mutex
+	requests map[string]int
+}
+
+func newTestRegistry(schemas map[string]RegistrySchema) *testRegistry {
+	registry := &testRegistry{
+		requests: map[string]int{},
+	}
+	registry.Server = httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				registry.Lock()
+				registry.requests[r.URL.Path]++
+				registry.Unlock()
+
+				schema, ok := schemas[r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				json.NewEncoder(w).Encode(schema)
+			},
+		),
+	)
+	return registry
+}
+
+func (r *testRegistry) requestCount(path string) int {
+	r.Lock()
+	defer r.Unlock()
+	return r.requests[path]
+}
+
+func varints(values ...int64) []byte {
+	data := []byte{}
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, value := range values {
+		n := binary.PutVarint(buf, value)
+		data = append(data, buf[:n]...)
+	}
+	return data
+}
@@ -0,0 +1,354 @@
+package messages
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/linkedin/goavro/v2"
+)
+
+// EncoderType is a string type that identifies how message keys or values are encoded.
+type EncoderType string
+
+const (
+	// EncoderTypeString writes keys and values as-is. This is the default.
+	EncoderTypeString EncoderType = "string"
+
+	// EncoderTypeJSON validates that keys or values are JSON and writes them in compact form.
+	EncoderTypeJSON EncoderType = "json"
+
+	// EncoderTypeAvro converts JSON keys or values to Avro and serializes them in the Schema
+	// Registry wire format.
+	EncoderTypeAvro EncoderType = "avro"
+
+	// EncoderTypeProtobuf converts JSON keys or values to protobuf and serializes them in the
+	// Schema Registry wire format.
+	EncoderTypeProtobuf EncoderType = "protobuf"
+
+	// EncoderTypeJSONSchema serializes JSON keys or values in the Schema Registry wire format.
+	EncoderTypeJSONSchema EncoderType = "json-schema"
+)
+
+// AllEncoderTypes contains all of the supported encoder types.
+var AllEncoderTypes = []EncoderType{
+	EncoderTypeString,
+	EncoderTypeJSON,
+	EncoderTypeAvro,
+	EncoderTypeProtobuf,
+	EncoderTypeJSONSchema,
+}
+
+// Encoder converts the bytes of a message key or value into the bytes that are written to
+// Kafka.
+type Encoder interface {
+	Encode(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// NewEncoder returns an Encoder for the argument type. The registry client and subject are
+// required for the types that serialize data with a Schema Registry. The message name is only
+// used by the protobuf encoder; if it's empty, the first message in the schema is used.
+func NewEncoder(
+	encoderType EncoderType,
+	registryClient *SchemaRegistryClient,
+	subject string,
+	messageName string,
+) (Encoder, error) {
+	switch encoderType {
+	case "", EncoderTypeString:
+		return &StringEncoder{}, nil
+	case EncoderTypeJSON:
+		return &JSONEncoder{}, nil
+	case EncoderTypeAvro, EncoderTypeProtobuf, EncoderTypeJSONSchema:
+		if registryClient == nil {
+			return nil, fmt.Errorf(
+				"A schema registry URL is required for the %s encoder",
+				encoderType,
+			)
+		}
+		if subject == "" {
+			return nil, fmt.Errorf("A subject is required for the %s encoder", encoderType)
+		}
+
+		registryEncoder := &registryEncoder{
+			registryClient: registryClient,
+			subject:        subject,
+		}
+
+		switch encoderType {
+		case EncoderTypeAvro:
+			return &AvroEncoder{registryEncoder: registryEncoder}, nil
+		case EncoderTypeProtobuf:
+			return &ProtobufEncoder{
+				registryEncoder: registryEncoder,
+				messageName:     messageName,
+			}, nil
+		default:
+			return &JSONSchemaEncoder{registryEncoder: registryEncoder}, nil
+		}
+	default:
+		return nil, fmt.Errorf(
+			"Unrecognized encoder type '%s'; choices are %+v",
+			encoderType,
+			AllEncoderTypes,
+		)
+	}
+}
+
+// StringEncoder is an Encoder that writes data as-is.
+type StringEncoder struct{}
+
+// Encode returns the argument data unchanged.
+func (e *StringEncoder) Encode(ctx context.Context, data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// JSONEncoder is an Encoder for data that's stored as plain JSON.
+type JSONEncoder struct{}
+
+// Encode validates that the argument data is JSON and compacts it.
+func (e *JSONEncoder) Encode(ctx context.Context, data []byte) ([]byte, error) {
+	compacted, err := compactJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(compacted), nil
+}
+
+// registryEncoder contains the state shared by the encoders that serialize data with a Schema
+// Registry. The latest schema for the subject is fetched the first time that it's needed and
+// then reused for the lifetime of the encoder.
+type registryEncoder struct {
+	registryClient *SchemaRegistryClient
+	subject        string
+
+	sync.Mutex
+	schema *RegistrySchema
+}
+
+func (e *registryEncoder) getSchema(
+	ctx context.Context,
+	schemaType string,
+) (RegistrySchema, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.schema != nil {
+		return *e.schema, nil
+	}
+
+	schema, err := e.registryClient.GetLatestSchema(ctx, e.subject)
+	if err != nil {
+		return RegistrySchema{}, err
+	}
+
+	// The registry omits the schema type for Avro schemas
+	actualType := schema.SchemaType
+	if actualType == "" {
+		actualType = "AVRO"
+	}
+	if actualType != schemaType {
+		return RegistrySchema{}, fmt.Errorf(
+			"Latest schema for subject %s has type %s, not %s",
+			e.subject,
+			actualType,
+			schemaType,
+		)
+	}
+
+	e.schema = &schema
+	return schema, nil
+}
+
+// AvroEncoder is an Encoder that converts JSON data to Avro using the latest schema for a
+// Schema Registry subject. Note that the JSON must be in the Avro JSON encoding, e.g. union
+// values are wrapped in an object keyed by their type.
+type AvroEncoder struct {
+	*registryEncoder
+
+	codec *goavro.Codec
+}
+
+// Encode converts the argument JSON data into Avro.
+func (e *AvroEncoder) Encode(ctx context.Context, data []byte) ([]byte, error) {
+	schema, err := e.getSchema(ctx, "AVRO")
+	if err != nil {
+		return nil, err
+	}
+
+	e.Lock()
+	if e.codec == nil {
+		if len(schema.References) > 0 {
+			e.Unlock()
+			return nil, fmt.Errorf(
+				"Schema for subject %s has references, which aren't supported for Avro",
+				e.subject,
+			)
+		}
+		e.codec, err = goavro.NewCodec(schema.Schema)
+		if err != nil {
+			e.Unlock()
+			return nil, fmt.Errorf("Error parsing Avro schema for subject %s: %+v", e.subject, err)
+		}
+	}
+	codec := e.codec
+	e.Unlock()
+
+	native, _, err := codec.NativeFromTextual(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error converting JSON to Avro: %+v", err)
+	}
+	payload, err := codec.BinaryFromNative(nil, native)
+	if err != nil {
+		return nil, fmt.Errorf("Error encoding Avro data: %+v", err)
+	}
+
+	return addRegistryHeader(schema.ID, payload), nil
+}
+
+// ProtobufEncoder is an Encoder that converts JSON data to protobuf using the latest schema for
+// a Schema Registry subject.
+type ProtobufEncoder struct {
+	*registryEncoder
+
+	messageName string
+	message     *desc.MessageDescriptor
+}
+
+// Encode converts the argument JSON data into protobuf.
+func (e *ProtobufEncoder) Encode(ctx context.Context, data []byte) ([]byte, error) {
+	schema, err := e.getSchema(ctx, "PROTOBUF")
+	if err != nil {
+		return nil, err
+	}
+
+	messageDesc, err := e.getMessage(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	message := dynamic.NewMessage(messageDesc)
+	if err := message.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf(
+			"Error converting JSON to %s: %+v",
+			messageDesc.GetFullyQualifiedName(),
+			err,
+		)
+	}
+	payload, err := message.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return addRegistryHeader(
+		schema.ID,
+		append(writeMessageIndices(indicesForMessage(messageDesc)), payload...),
+	), nil
+}
+
+func (e *ProtobufEncoder) getMessage(
+	ctx context.Context,
+	schema RegistrySchema,
+) (*desc.MessageDescriptor, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.message != nil {
+		return e.message, nil
+	}
+
+	file, err := parseProtobufSchema(ctx, e.registryClient, schema.ID, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.messageName == "" {
+		if len(file.GetMessageTypes()) == 0 {
+			return nil, fmt.Errorf("Schema for subject %s has no message types", e.subject)
+		}
+		e.message = file.GetMessageTypes()[0]
+	} else {
+		e.message = file.FindMessage(e.messageName)
+		if e.message == nil {
+			return nil, fmt.Errorf(
+				"Could not find message %s in schema for subject %s",
+				e.messageName,
+				e.subject,
+			)
+		}
+	}
+
+	return e.message, nil
+}
+
+// JSONSchemaEncoder is an Encoder that serializes JSON data with the latest schema ID for a
+// Schema Registry subject. The data itself isn't validated against the schema.
+type JSONSchemaEncoder struct {
+	*registryEncoder
+}
+
+// Encode compacts the argument JSON data and adds the Schema Registry header to it.
+func (e *JSONSchemaEncoder) Encode(ctx context.Context, data []byte) ([]byte, error) {
+	schema, err := e.getSchema(ctx, "JSON")
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, err := compactJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return addRegistryHeader(schema.ID, []byte(compacted)), nil
+}
+
+// indicesForMessage returns the path of indices to the argument message from the top level
+// of its file; this is the inverse of messageForIndices.
+func indicesForMessage(message *desc.MessageDescriptor) []int {
+	indices := []int{}
+
+	for {
+		var siblings []*desc.MessageDescriptor
+		parent, ok := message.GetParent().(*desc.MessageDescriptor)
+		if ok {
+			siblings = parent.GetNestedMessageTypes()
+		} else {
+			siblings = message.GetFile().GetMessageTypes()
+		}
+
+		for i, sibling := range siblings {
+			if sibling == message {
+				indices = append([]int{i}, indices...)
+				break
+			}
+		}
+
+		if !ok {
+			return indices
+		}
+		message = parent
+	}
+}
+
+// writeMessageIndices encodes the argument message indices in the format that's read by
+// readMessageIndices.
+func writeMessageIndices(indices []int) []byte {
+	if len(indices) == 1 && indices[0] == 0 {
+		return []byte{0}
+	}
+
+	data := []byte{}
+	buf := make([]byte, binary.MaxVarintLen64)
+
+	n := binary.PutVarint(buf, int64(len(indices)))
+	data = append(data, buf[:n]...)
+	for _, index := range indices {
+		n = binary.PutVarint(buf, int64(index))
+		data = append(data, buf[:n]...)
+	}
+
+	return data
+}
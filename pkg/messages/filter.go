@@ -0,0 +1,101 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/itchyny/gojq"
+	"github.com/segmentio/kafka-go"
+)
+
+// MessageFilter is a jq expression that's evaluated against each tailed message to decide
+// whether it should be shown.
+//
+// The expression is run on an object with the following fields:
+//
+//	key:       the decoded message key, parsed as JSON if possible
+//	value:     the decoded message value, parsed as JSON if possible
+//	headers:   an object mapping header names to their (string) values
+//	partition: the message partition
+//	offset:    the message offset
+//	time:      the message time in RFC3339 format
+//
+// A message matches if any result of the expression is something other than false or null,
+// e.g. `.value.user.id == 1234 and .partition == 0`.
+type MessageFilter struct {
+	expression string
+	code       *gojq.Code
+}
+
+// NewMessageFilter parses and compiles the argument jq expression into a MessageFilter.
+func NewMessageFilter(expression string) (*MessageFilter, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing filter expression '%s': %+v", expression, err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("Error compiling filter expression '%s': %+v", expression, err)
+	}
+
+	return &MessageFilter{
+		expression: expression,
+		code:       code,
+	}, nil
+}
+
+// String returns the expression that the filter was created from.
+func (f *MessageFilter) String() string {
+	return f.expression
+}
+
+// Match evaluates the filter against the argument message and its decoded key and value.
+func (f *MessageFilter) Match(
+	ctx context.Context,
+	message kafka.Message,
+	key string,
+	value string,
+) (bool, error) {
+	iter := f.code.RunWithContext(ctx, filterInput(message, key, value))
+
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			return false, nil
+		}
+		if err, ok := result.(error); ok {
+			return false, fmt.Errorf("Error evaluating filter expression: %+v", err)
+		}
+		if result != nil && result != false {
+			return true, nil
+		}
+	}
+}
+
+func filterInput(message kafka.Message, key string, value string) map[string]interface{} {
+	headers := map[string]interface{}{}
+	for _, header := range message.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+
+	return map[string]interface{}{
+		"key":       jsonOrString(key),
+		"value":     jsonOrString(value),
+		"headers":   headers,
+		"partition": message.Partition,
+		"offset":    int(message.Offset),
+		"time":      message.Time.Format(time.RFC3339),
+	}
+}
+
+// jsonOrString parses the argument string as JSON, falling back to the string itself if it
+// isn't valid JSON.
+func jsonOrString(str string) interface{} {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(str), &parsed); err != nil {
+		return str
+	}
+	return parsed
+}
@@ -0,0 +1,93 @@
+package messages
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageFilter(t *testing.T) {
+	ctx := context.Background()
+	message := kafka.Message{
+		Partition: 3,
+		Offset:    1234,
+		Time:      time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC),
+		Headers: []kafka.Header{
+			{
+				Key:   "source",
+				Value: []byte("service-a"),
+			},
+		},
+	}
+
+	type testCase struct {
+		expression string
+		key        string
+		value      string
+		expMatch   bool
+		expErr     bool
+	}
+
+	testCases := []testCase{
+		{
+			expression: `.value.user.id == 10`,
+			value:      `{"user": {"id": 10}}`,
+			expMatch:   true,
+		},
+		{
+			expression: `.value.user.id == 10`,
+			value:      `{"user": {"id": 11}}`,
+			expMatch:   false,
+		},
+		{
+			expression: `.value.user.name`,
+			value:      `{"user": {"id": 11}}`,
+			expMatch:   false,
+		},
+		{
+			expression: `.key == "my-key" and .partition == 3 and .offset > 1000`,
+			key:        "my-key",
+			value:      "not json",
+			expMatch:   true,
+		},
+		{
+			expression: `.headers.source == "service-a" and (.time | startswith("2021-05"))`,
+			expMatch:   true,
+		},
+		{
+			expression: `.value | test("^not")`,
+			value:      "not json",
+			expMatch:   true,
+		},
+		{
+			expression: `.value.items[] | select(. > 2)`,
+			value:      `{"items": [1, 2, 3]}`,
+			expMatch:   true,
+		},
+		{
+			expression: `.value.id + 1`,
+			value:      "not json",
+			expErr:     true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		filter, err := NewMessageFilter(testCase.expression)
+		require.NoError(t, err)
+
+		match, err := filter.Match(ctx, message, testCase.key, testCase.value)
+		if testCase.expErr {
+			assert.Error(t, err, testCase.expression)
+		} else {
+			require.NoError(t, err, testCase.expression)
+			assert.Equal(t, testCase.expMatch, match, testCase.expression)
+		}
+	}
+
+	_, err := NewMessageFilter(`.value ==`)
+	assert.Error(t, err)
+}
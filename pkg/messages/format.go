@@ -258,3 +258,63 @@ func FormatBoundTotals(boundsSlice []Bounds) string {
 	table.Render()
 	return string(bytes.TrimRight(buf.Bytes(), "\n"))
 }
+
+// FormatTimeOffsets makes a pretty table from the results of a GetTimeOffsets call. The last
+// row contains the totals across all partitions.
+func FormatTimeOffsets(timeOffsets []TimeOffset) string {
+	buf := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(
+		[]string{
+			"Partition",
+			"Offset At Time",
+			"End Offset",
+			"Messages After Time",
+		},
+	)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(
+		[]int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+		},
+	)
+	table.SetBorders(
+		tablewriter.Border{
+			Left:   false,
+			Top:    true,
+			Right:  false,
+			Bottom: true,
+		},
+	)
+
+	var totalMessages int64
+
+	for _, timeOffset := range timeOffsets {
+		totalMessages += timeOffset.MessagesAfter()
+
+		table.Append(
+			[]string{
+				fmt.Sprintf("%d", timeOffset.Partition),
+				fmt.Sprintf("%d", timeOffset.Offset),
+				fmt.Sprintf("%d", timeOffset.EndOffset),
+				fmt.Sprintf("%d", timeOffset.MessagesAfter()),
+			},
+		)
+	}
+
+	table.Append(
+		[]string{
+			"Total",
+			"",
+			"",
+			fmt.Sprintf("%d", totalMessages),
+		},
+	)
+
+	table.Render()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
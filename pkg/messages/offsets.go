@@ -0,0 +1,102 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// TimeOffset represents the offset in a partition that corresponds to a particular time.
+type TimeOffset struct {
+	Partition int       `json:"partition"`
+	Time      time.Time `json:"time"`
+
+	// Offset is the earliest offset whose timestamp is greater than or equal to Time. If there
+	// are no such messages, this is the same as EndOffset.
+	Offset int64 `json:"offset"`
+
+	// EndOffset is the offset that the next message produced to the partition will have.
+	EndOffset int64 `json:"endOffset"`
+}
+
+// MessagesAfter returns the number of messages in the partition at or after the offset's time.
+func (t TimeOffset) MessagesAfter() int64 {
+	return t.EndOffset - t.Offset
+}
+
+// GetTimeOffsets gets the offsets in each of the argument partitions that correspond to the
+// argument time. The results are sorted by partition.
+func GetTimeOffsets(
+	ctx context.Context,
+	connector *admin.Connector,
+	topic string,
+	partitions []int,
+	at time.Time,
+) ([]TimeOffset, error) {
+	offsetRequests := []kafka.OffsetRequest{}
+	for _, partition := range partitions {
+		offsetRequests = append(offsetRequests, kafka.TimeOffsetOf(partition, at))
+	}
+
+	resp, err := connector.KafkaClient.ListOffsets(
+		ctx,
+		&kafka.ListOffsetsRequest{
+			Topics: map[string][]kafka.OffsetRequest{
+				topic: offsetRequests,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The end offsets need to be fetched in a separate request since brokers reject list
+	// offset requests that contain the same partition more than once.
+	endOffsets, err := getLastOffsets(ctx, connector, topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	timeOffsets := []TimeOffset{}
+
+	for _, partitionOffsets := range resp.Topics[topic] {
+		if partitionOffsets.Error != nil {
+			return nil, fmt.Errorf(
+				"Error getting offsets for partition %d: %+v",
+				partitionOffsets.Partition,
+				partitionOffsets.Error,
+			)
+		}
+
+		endOffset := endOffsets[partitionOffsets.Partition]
+
+		// There should be at most one offset since we only requested a single time per
+		// partition. Brokers return -1 if there are no messages at or after the time.
+		offset := endOffset
+		for partitionOffset := range partitionOffsets.Offsets {
+			if partitionOffset >= 0 {
+				offset = partitionOffset
+			}
+		}
+
+		timeOffsets = append(
+			timeOffsets,
+			TimeOffset{
+				Partition: partitionOffsets.Partition,
+				Time:      at,
+				Offset:    offset,
+				EndOffset: endOffset,
+			},
+		)
+	}
+
+	sort.Slice(timeOffsets, func(a, b int) bool {
+		return timeOffsets[a].Partition < timeOffsets[b].Partition
+	})
+
+	return timeOffsets, nil
+}
@@ -0,0 +1,98 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTimeOffsets(t *testing.T) {
+	ctx := context.Background()
+	connector, err := admin.NewConnector(admin.ConnectorConfig{
+		BrokerAddr: util.TestKafkaAddr(),
+	})
+	require.NoError(t, err)
+
+	topicName := util.RandomString("topic-time-offsets-", 6)
+	_, err = connector.KafkaClient.CreateTopics(
+		ctx,
+		&kafka.CreateTopicsRequest{
+			Topics: []kafka.TopicConfig{
+				{
+					Topic:             topicName,
+					NumPartitions:     2,
+					ReplicationFactor: 1,
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	time.Sleep(200 * time.Millisecond)
+
+	writer := kafka.NewWriter(
+		kafka.WriterConfig{
+			Brokers:  []string{connector.Config.BrokerAddr},
+			Dialer:   connector.Dialer,
+			Topic:    topicName,
+			Balancer: &kafka.RoundRobin{},
+		},
+	)
+	defer writer.Close()
+
+	now := time.Now()
+	messages := []kafka.Message{}
+
+	for i := 0; i < 10; i++ {
+		messages = append(
+			messages,
+			kafka.Message{
+				Key:   []byte(fmt.Sprintf("key%d", i)),
+				Value: []byte(fmt.Sprintf("value%d", i)),
+				// First half of messages are "old", second half are "new"
+				Time: now.Add(time.Duration(i/5-1) * time.Hour),
+			},
+		)
+	}
+
+	err = writer.WriteMessages(ctx, messages...)
+	require.NoError(t, err)
+
+	timeOffsets, err := GetTimeOffsets(
+		ctx,
+		connector,
+		topicName,
+		[]int{0, 1},
+		now.Add(-30*time.Minute),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(timeOffsets))
+
+	var totalAfter int64
+	for p, timeOffset := range timeOffsets {
+		assert.Equal(t, p, timeOffset.Partition)
+		assert.Equal(t, int64(5), timeOffset.EndOffset)
+		totalAfter += timeOffset.MessagesAfter()
+	}
+	assert.Equal(t, int64(5), totalAfter)
+
+	// Times after all messages should return the end offsets
+	timeOffsets, err = GetTimeOffsets(
+		ctx,
+		connector,
+		topicName,
+		[]int{0, 1},
+		now.Add(time.Hour),
+	)
+	require.NoError(t, err)
+	for _, timeOffset := range timeOffsets {
+		assert.Equal(t, timeOffset.EndOffset, timeOffset.Offset)
+		assert.Equal(t, int64(0), timeOffset.MessagesAfter())
+	}
+}
@@ -0,0 +1,249 @@
+package messages
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// PartitionerType is a string type that identifies how produced messages are assigned to
+// partitions.
+type PartitionerType string
+
+const (
+	// PartitionerTypeHash assigns messages by the FNV-1a hash of their keys; this matches
+	// the default partitioner in sarama.
+	PartitionerTypeHash PartitionerType = "hash"
+
+	// PartitionerTypeMurmur2 assigns messages by the murmur2 hash of their keys; this matches
+	// the default partitioner in the Java client.
+	PartitionerTypeMurmur2 PartitionerType = "murmur2"
+
+	// PartitionerTypeCRC32 assigns messages by the CRC32 hash of their keys; this matches the
+	// default partitioner in librdkafka.
+	PartitionerTypeCRC32 PartitionerType = "crc32"
+
+	// PartitionerTypeRoundRobin assigns messages to partitions in turn, ignoring their keys.
+	PartitionerTypeRoundRobin PartitionerType = "round-robin"
+
+	// PartitionerTypeLeastBytes assigns each message to the partition that has been sent the
+	// fewest bytes, ignoring their keys.
+	PartitionerTypeLeastBytes PartitionerType = "least-bytes"
+)
+
+// AllPartitionerTypes contains all of the supported partitioner types.
+var AllPartitionerTypes = []PartitionerType{
+	PartitionerTypeHash,
+	PartitionerTypeMurmur2,
+	PartitionerTypeCRC32,
+	PartitionerTypeRoundRobin,
+	PartitionerTypeLeastBytes,
+}
+
+// NewBalancer returns the kafka-go balancer for the argument partitioner type. If partition is
+// non-negative, the partitioner is ignored and all messages are sent to that partition.
+func NewBalancer(partitionerType PartitionerType, partition int) (kafka.Balancer, error) {
+	if partition >= 0 {
+		return kafka.BalancerFunc(
+			func(msg kafka.Message, partitions ...int) int {
+				return partition
+			},
+		), nil
+	}
+
+	switch partitionerType {
+	case "", PartitionerTypeHash:
+		return &kafka.Hash{}, nil
+	case PartitionerTypeMurmur2:
+		return kafka.Murmur2Balancer{}, nil
+	case PartitionerTypeCRC32:
+		return kafka.CRC32Balancer{}, nil
+	case PartitionerTypeRoundRobin:
+		return &kafka.RoundRobin{}, nil
+	case PartitionerTypeLeastBytes:
+		return &kafka.LeastBytes{}, nil
+	default:
+		return nil, fmt.Errorf(
+			"Unrecognized partitioner type '%s'; choices are %+v",
+			partitionerType,
+			AllPartitionerTypes,
+		)
+	}
+}
+
+// ProduceRecord is a single message to be produced, as read from a JSON lines input. The key
+// and value can be any JSON; strings are written as-is and everything else is written as
+// compact JSON before encoding.
+type ProduceRecord struct {
+	Key     json.RawMessage   `json:"key"`
+	Value   json.RawMessage   `json:"value"`
+	Headers map[string]string `json:"headers"`
+}
+
+// ReadProduceRecords reads records from the argument JSON lines input. Blank lines are ignored.
+func ReadProduceRecords(input io.Reader) ([]ProduceRecord, error) {
+	records := []ProduceRecord{}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		record := ProduceRecord{}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("Error parsing record on line %d: %+v", lineNum, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// TopicProducer writes messages to a topic.
+type TopicProducer struct {
+	Connector *admin.Connector
+	topic     string
+	balancer  kafka.Balancer
+
+	keyEncoder   Encoder
+	valueEncoder Encoder
+}
+
+// NewTopicProducer returns a new TopicProducer instance.
+func NewTopicProducer(
+	connector *admin.Connector,
+	topic string,
+	balancer kafka.Balancer,
+) *TopicProducer {
+	return &TopicProducer{
+		Connector: connector,
+		topic:     topic,
+		balancer:  balancer,
+	}
+}
+
+// SetEncoders sets the encoders that are applied to message keys and values before they're
+// written. If either is nil, the corresponding bytes are written as-is.
+func (p *TopicProducer) SetEncoders(keyEncoder Encoder, valueEncoder Encoder) {
+	p.keyEncoder = keyEncoder
+	p.valueEncoder = valueEncoder
+}
+
+// Messages converts the argument records into kafka messages, encoding their keys and values.
+func (p *TopicProducer) Messages(
+	ctx context.Context,
+	records []ProduceRecord,
+) ([]kafka.Message, error) {
+	messages := []kafka.Message{}
+
+	for r, record := range records {
+		key, err := p.encode(ctx, p.keyEncoder, record.Key)
+		if err != nil {
+			return nil, fmt.Errorf("Error encoding key of record %d: %+v", r+1, err)
+		}
+		value, err := p.encode(ctx, p.valueEncoder, record.Value)
+		if err != nil {
+			return nil, fmt.Errorf("Error encoding value of record %d: %+v", r+1, err)
+		}
+
+		headerKeys := []string{}
+		for headerKey := range record.Headers {
+			headerKeys = append(headerKeys, headerKey)
+		}
+		sort.Strings(headerKeys)
+
+		headers := []kafka.Header{}
+		for _, headerKey := range headerKeys {
+			headers = append(
+				headers,
+				kafka.Header{
+					Key:   headerKey,
+					Value: []byte(record.Headers[headerKey]),
+				},
+			)
+		}
+
+		messages = append(
+			messages,
+			kafka.Message{
+				Key:     key,
+				Value:   value,
+				Headers: headers,
+			},
+		)
+	}
+
+	return messages, nil
+}
+
+// Produce encodes the argument records and writes them to the topic. It returns after all
+// messages have been acknowledged by the brokers.
+func (p *TopicProducer) Produce(ctx context.Context, records []ProduceRecord) error {
+	messages, err := p.Messages(ctx, records)
+	if err != nil {
+		return err
+	}
+
+	writer := kafka.NewWriter(
+		kafka.WriterConfig{
+			Brokers:      []string{p.Connector.Config.BrokerAddr},
+			Dialer:       p.Connector.Dialer,
+			Topic:        p.topic,
+			Balancer:     p.balancer,
+			BatchTimeout: 10 * time.Millisecond,
+			RequiredAcks: int(kafka.RequireAll),
+		},
+	)
+	defer writer.Close()
+
+	return writer.WriteMessages(ctx, messages...)
+}
+
+// encode converts a record key or value into bytes. JSON strings are unquoted, and nulls or
+// missing fields are treated as empty.
+func (p *TopicProducer) encode(
+	ctx context.Context,
+	encoder Encoder,
+	raw json.RawMessage,
+) ([]byte, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var data []byte
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		data = []byte(str)
+	} else {
+		compacted, err := compactJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		data = []byte(compacted)
+	}
+
+	if encoder == nil {
+		return data, nil
+	}
+	return encoder.Encode(ctx, data)
+}
@@ -0,0 +1,203 @@
+package messages
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadProduceRecords(t *testing.T) {
+	records, err := ReadProduceRecords(
+		strings.NewReader(
+			strings.Join(
+				[]string{
+					`{"key": "key1", "value": "value1"}`,
+					``,
+					`{"value": {"id": 2}, "headers": {"b": "2", "a": "1"}}`,
+				},
+				"\n",
+			),
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(records))
+
+	producer := NewTopicProducer(nil, "test-topic", nil)
+	messages, err := producer.Messages(context.Background(), records)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]kafka.Message{
+			{
+				Key:     []byte("key1"),
+				Value:   []byte("value1"),
+				Headers: []kafka.Header{},
+			},
+			{
+				Value: []byte(`{"id":2}`),
+				Headers: []kafka.Header{
+					{
+						Key:   "a",
+						Value: []byte("1"),
+					},
+					{
+						Key:   "b",
+						Value: []byte("2"),
+					},
+				},
+			},
+		},
+		messages,
+	)
+
+	_, err = ReadProduceRecords(strings.NewReader(`{"key": "key1"}` + "\n" + `not json`))
+	assert.Error(t, err)
+	_, err = ReadProduceRecords(strings.NewReader(`{"key": "key1", "partition": 2}`))
+	assert.Error(t, err)
+}
+
+func TestEncoders(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry(
+		map[string]RegistrySchema{
+			"/subjects/avro-value/versions/latest": {
+				ID:     3,
+				Schema: testAvroSchema,
+			},
+			"/schemas/ids/3": {
+				Schema: testAvroSchema,
+			},
+			"/subjects/proto-value/versions/latest": {
+				ID:         5,
+				Schema:     testProtoSchema,
+				SchemaType: "PROTOBUF",
+				References: []RegistryReference{
+					{
+						Name:    "common.proto",
+						Subject: "common",
+						Version: 2,
+					},
+				},
+			},
+			"/schemas/ids/5": {
+				Schema:     testProtoSchema,
+				SchemaType: "PROTOBUF",
+				References: []RegistryReference{
+					{
+						Name:    "common.proto",
+						Subject: "common",
+						Version: 2,
+					},
+				},
+			},
+			"/subjects/common/versions/2": {
+				Schema:     testProtoReference,
+				SchemaType: "PROTOBUF",
+			},
+			"/subjects/json-value/versions/latest": {
+				ID:         8,
+				Schema:     `{"type": "object"}`,
+				SchemaType: "JSON",
+			},
+		},
+	)
+	defer registry.Close()
+
+	registryClient, err := NewSchemaRegistryClient(registry.URL)
+	require.NoError(t, err)
+
+	type testCase struct {
+		encoderType EncoderType
+		decoderType DecoderType
+		subject     string
+		messageName string
+		input       string
+		expOutput   string
+	}
+
+	testCases := []testCase{
+		{
+			encoderType: EncoderTypeAvro,
+			decoderType: DecoderTypeAvro,
+			subject:     "avro-value",
+			input:       `{"name": "bob", "age": 40}`,
+			expOutput:   `{"name":"bob","age":40}`,
+		},
+		{
+			encoderType: EncoderTypeProtobuf,
+			decoderType: DecoderTypeProtobuf,
+			subject:     "proto-value",
+			input:       `{"id": "abc"}`,
+			expOutput:   `{"id":"abc"}`,
+		},
+		{
+			encoderType: EncoderTypeProtobuf,
+			decoderType: DecoderTypeProtobuf,
+			subject:     "proto-value",
+			messageName: "test.Outer.Inner",
+			input:       `{"value": "x", "common": {"count": 4}}`,
+			expOutput:   `{"value":"x","common":{"count":4}}`,
+		},
+		{
+			encoderType: EncoderTypeJSONSchema,
+			decoderType: DecoderTypeJSONSchema,
+			subject:     "json-value",
+			input:       `{"key": "value"}`,
+			expOutput:   `{"key":"value"}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		encoder, err := NewEncoder(
+			testCase.encoderType,
+			registryClient,
+			testCase.subject,
+			testCase.messageName,
+		)
+		require.NoError(t, err)
+		decoder, err := NewDecoder(testCase.decoderType, registryClient)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			encoded, err := encoder.Encode(ctx, []byte(testCase.input))
+			require.NoError(t, err, testCase.encoderType)
+			decoded, err := decoder.Decode(ctx, encoded)
+			require.NoError(t, err, testCase.encoderType)
+			assert.JSONEq(t, testCase.expOutput, decoded, testCase.encoderType)
+		}
+	}
+
+	assert.Equal(t, 1, registry.requestCount("/subjects/avro-value/versions/latest"))
+
+	encoder, err := NewEncoder(EncoderTypeAvro, registryClient, "proto-value", "")
+	require.NoError(t, err)
+	_, err = encoder.Encode(ctx, []byte(`{"id": "abc"}`))
+	assert.Error(t, err)
+
+	encoder, err = NewEncoder(EncoderTypeProtobuf, registryClient, "proto-value", "test.Missing")
+	require.NoError(t, err)
+	_, err = encoder.Encode(ctx, []byte(`{"id": "abc"}`))
+	assert.Error(t, err)
+
+	_, err = NewEncoder(EncoderTypeAvro, nil, "avro-value", "")
+	assert.Error(t, err)
+	_, err = NewEncoder("bad-type", nil, "", "")
+	assert.Error(t, err)
+}
+
+func TestNewBalancer(t *testing.T) {
+	balancer, err := NewBalancer(PartitionerTypeMurmur2, -1)
+	require.NoError(t, err)
+	assert.IsType(t, kafka.Murmur2Balancer{}, balancer)
+
+	balancer, err = NewBalancer(PartitionerTypeMurmur2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, balancer.Balance(kafka.Message{Key: []byte("key")}, 0, 1, 2, 3))
+
+	_, err = NewBalancer("bad-type", -1)
+	assert.Error(t, err)
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/topicctl/pkg/admin"
 	"github.com/segmentio/topicctl/pkg/util"
-	log "github.com/sirupsen/logrus"
 
 	// Read snappy-compressed messages
 	_ "github.com/segmentio/kafka-go/snappy"
@@ -29,6 +28,10 @@ type TopicTailer struct {
 	offset     int64
 	minBytes   int
 	maxBytes   int
+
+	keyDecoder   Decoder
+	valueDecoder Decoder
+	filter       *MessageFilter
 }
 
 // NewTopicTailer returns a new TopicTailer instance.
@@ -50,6 +53,19 @@ func NewTopicTailer(
 	}
 }
 
+// SetDecoders sets the decoders that LogMessages uses to format message keys and values. If
+// either is nil, the corresponding bytes are shown as strings.
+func (t *TopicTailer) SetDecoders(keyDecoder Decoder, valueDecoder Decoder) {
+	t.keyDecoder = keyDecoder
+	t.valueDecoder = valueDecoder
+}
+
+// SetFilter sets an expression filter that LogMessages applies to each message, in addition to
+// any regexp filter. If filter is nil, no expression filtering is done.
+func (t *TopicTailer) SetFilter(filter *MessageFilter) {
+	t.filter = filter
+}
+
 // TailMessage represents a single message retrieved from a kafka reader.
 type TailMessage struct {
 	Message   kafka.Message
@@ -207,25 +223,51 @@ func (t *TopicTailer) LogMessages(
 			partitionStats.LastOffset = tailMessage.Message.Offset
 			partitionStats.LastTime = tailMessage.Message.Time
 
-			if filterRegexpObj != nil && !filterRegexpObj.Match(tailMessage.Message.Value) {
+			key := t.decode(ctx, t.keyDecoder, tailMessage.Message.Key, "key")
+			value := t.decode(ctx, t.valueDecoder, tailMessage.Message.Value, "value")
+
+			if filterRegexpObj != nil && !filterRegexpObj.MatchString(value) {
 				continue
 			}
+			if t.filter != nil {
+				match, err := t.filter.Match(ctx, tailMessage.Message, key, value)
+				if err != nil {
+					log.Warnf(
+						"Error filtering message at offset %d in partition %d: %+v",
+						tailMessage.Message.Offset,
+						partition,
+						err,
+					)
+					continue
+				}
+				if !match {
+					continue
+				}
+			}
 
 			partitionStats.TotalMessagesFiltered++
 			partitionStats.TotalMessageBytesFiltered += int64(len(tailMessage.Message.Key))
 			partitionStats.TotalMessageBytesFiltered += int64(len(tailMessage.Message.Value))
 
 			if raw {
-				fmt.Printf("%s\n", string(tailMessage.Message.Value))
+				fmt.Printf("%s\n", value)
 				continue
 			}
 
+			// Without decoders, make sure that binary data is printable
+			if t.keyDecoder == nil {
+				key = bytesToStr(tailMessage.Message.Key)
+			}
+			if t.valueDecoder == nil {
+				value = bytesToStr(tailMessage.Message.Value)
+			}
+
 			var dividerPrinter func(f string, a ...interface{}) string
 			var keyPrinter func(f string, a ...interface{}) string
 			var valuePrinter func(f string, a ...interface{}) string
 			var messagePrinter func(f string, a ...interface{}) string
 
-			if !util.InTerminal() {
+			if !util.ColorEnabled() {
 				dividerPrinter = fmt.Sprintf
 				keyPrinter = fmt.Sprintf
 				valuePrinter = fmt.Sprintf
@@ -258,12 +300,12 @@ func (t *TopicTailer) LogMessages(
 			fmt.Printf(
 				"%s %s\n",
 				keyPrinter("Key:      "),
-				valuePrinter(bytesToStr(tailMessage.Message.Key)),
+				valuePrinter(key),
 			)
 			fmt.Printf(
 				"%s %s\n",
 				keyPrinter("Value:    "),
-				messagePrinter(bytesToStr(tailMessage.Message.Value)),
+				messagePrinter(value),
 			)
 
 			if maxMessages > 0 && partitionStats.TotalMessages >= maxMessages {
@@ -273,6 +315,27 @@ func (t *TopicTailer) LogMessages(
 	}
 }
 
+// decode formats the argument key or value bytes with the argument decoder. If no decoder is
+// set, the raw bytes are returned as a string. If decoding fails, a warning is logged and the
+// bytes are shown as a string instead.
+func (t *TopicTailer) decode(
+	ctx context.Context,
+	decoder Decoder,
+	data []byte,
+	name string,
+) string {
+	if decoder == nil {
+		return string(data)
+	}
+
+	decoded, err := decoder.Decode(ctx, data)
+	if err != nil {
+		log.Warnf("Error decoding message %s: %+v", name, err)
+		return bytesToStr(data)
+	}
+	return decoded
+}
+
 // bytesToStr makes a screen-printable version of a byte sequence.
 func bytesToStr(input []byte) string {
 	if utf8.Valid(input) {
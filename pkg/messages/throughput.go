@@ -0,0 +1,116 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+// TopicThroughput summarizes the produce rate into a topic over a sampling window.
+type TopicThroughput struct {
+	Topic          string
+	SampleDuration time.Duration
+
+	// MessagesPerSec is the total rate across all partitions.
+	MessagesPerSec float64
+
+	// PartitionMessagesPerSec is the rate for each partition, keyed by partition ID.
+	PartitionMessagesPerSec map[int]float64
+}
+
+// MaxPartitionMessagesPerSec returns the rate of the busiest partition.
+func (t TopicThroughput) MaxPartitionMessagesPerSec() float64 {
+	var maxRate float64
+	for _, rate := range t.PartitionMessagesPerSec {
+		if rate > maxRate {
+			maxRate = rate
+		}
+	}
+	return maxRate
+}
+
+// GetTopicThroughput measures the produce rate into the argument topic by sampling the newest
+// offset in each partition at the start and end of the sample duration.
+func GetTopicThroughput(
+	ctx context.Context,
+	connector *admin.Connector,
+	topic string,
+	partitions []int,
+	sampleDuration time.Duration,
+) (TopicThroughput, error) {
+	startOffsets, err := getLastOffsets(ctx, connector, topic, partitions)
+	if err != nil {
+		return TopicThroughput{}, err
+	}
+	startTime := time.Now()
+
+	log.Debugf("Sampling offsets in topic %s for %s", topic, sampleDuration)
+
+	select {
+	case <-ctx.Done():
+		return TopicThroughput{}, ctx.Err()
+	case <-time.After(sampleDuration):
+	}
+
+	endOffsets, err := getLastOffsets(ctx, connector, topic, partitions)
+	if err != nil {
+		return TopicThroughput{}, err
+	}
+	elapsedSecs := time.Since(startTime).Seconds()
+
+	throughput := TopicThroughput{
+		Topic:                   topic,
+		SampleDuration:          sampleDuration,
+		PartitionMessagesPerSec: map[int]float64{},
+	}
+
+	for _, partition := range partitions {
+		rate := float64(endOffsets[partition]-startOffsets[partition]) / elapsedSecs
+		throughput.PartitionMessagesPerSec[partition] = rate
+		throughput.MessagesPerSec += rate
+	}
+
+	return throughput, nil
+}
+
+func getLastOffsets(
+	ctx context.Context,
+	connector *admin.Connector,
+	topic string,
+	partitions []int,
+) (map[int]int64, error) {
+	offsetRequests := []kafka.OffsetRequest{}
+	for _, partition := range partitions {
+		offsetRequests = append(offsetRequests, kafka.LastOffsetOf(partition))
+	}
+
+	resp, err := connector.KafkaClient.ListOffsets(
+		ctx,
+		&kafka.ListOffsetsRequest{
+			Topics: map[string][]kafka.OffsetRequest{
+				topic: offsetRequests,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lastOffsets := map[int]int64{}
+
+	for _, partitionOffsets := range resp.Topics[topic] {
+		if partitionOffsets.Error != nil {
+			return nil, fmt.Errorf(
+				"Error getting offsets for partition %d: %+v",
+				partitionOffsets.Partition,
+				partitionOffsets.Error,
+			)
+		}
+		lastOffsets[partitionOffsets.Partition] = partitionOffsets.LastOffset
+	}
+
+	return lastOffsets, nil
+}
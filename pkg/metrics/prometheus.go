@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Sample is a single sample from a Prometheus metrics endpoint.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// ParseText parses the samples in the argument Prometheus text exposition format, e.g. the
+// output of the JMX exporter. Comments, including the HELP and TYPE lines, are skipped, and
+// timestamps are ignored.
+func ParseText(reader io.Reader) ([]Sample, error) {
+	samples := []Sample{}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sample, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing metrics line %d: %+v", lineNum, err)
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+func parseSample(line string) (Sample, error) {
+	sample := Sample{
+		Labels: map[string]string{},
+	}
+
+	nameEnd := strings.IndexAny(line, "{ \t")
+	if nameEnd <= 0 {
+		return Sample{}, fmt.Errorf("Sample '%s' doesn't have a value", line)
+	}
+	sample.Name = line[:nameEnd]
+	rest := line[nameEnd:]
+
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		rest, err = parseLabels(rest[1:], sample.Labels)
+		if err != nil {
+			return Sample{}, err
+		}
+	}
+
+	// The value may be followed by a timestamp
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return Sample{}, fmt.Errorf("Sample '%s' doesn't have a value", line)
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return Sample{}, fmt.Errorf("Invalid value for sample '%s': %+v", sample.Name, err)
+	}
+	sample.Value = value
+
+	return sample, nil
+}
+
+// parseLabels parses the labels after the opening brace of a sample into the argument map
+// and returns the remainder of the line after the closing brace.
+func parseLabels(input string, labels map[string]string) (string, error) {
+	for {
+		input = strings.TrimLeft(input, " \t,")
+		if strings.HasPrefix(input, "}") {
+			return input[1:], nil
+		}
+
+		equals := strings.Index(input, "=")
+		if equals <= 0 || len(input) < equals+2 || input[equals+1] != '"' {
+			return "", fmt.Errorf("Invalid labels in '%s'", input)
+		}
+		name := strings.TrimSpace(input[:equals])
+		input = input[equals+2:]
+
+		value := &strings.Builder{}
+		closed := false
+
+		for i := 0; i < len(input); i++ {
+			c := input[i]
+			if c == '\\' && i+1 < len(input) {
+				i++
+				switch input[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(input[i])
+				}
+				continue
+			}
+			if c == '"' {
+				labels[name] = value.String()
+				input = input[i+1:]
+				closed = true
+				break
+			}
+			value.WriteByte(c)
+		}
+
+		if !closed {
+			return "", fmt.Errorf("Unterminated value for label '%s'", name)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseText(t *testing.T) {
+	input := `# HELP kafka_log_log_logendoffset Log end offset
+# TYPE kafka_log_log_logendoffset untyped
+kafka_log_log_logendoffset{topic="topic1",partition="0",} 1234.0
+kafka_log_log_logendoffset{topic="topic\"2\"", partition="1"} 5.5e3 1700000000000
+
+jvm_threads_current 42
+`
+
+	samples, err := ParseText(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]Sample{
+			{
+				Name:   "kafka_log_log_logendoffset",
+				Labels: map[string]string{"topic": "topic1", "partition": "0"},
+				Value:  1234,
+			},
+			{
+				Name:   "kafka_log_log_logendoffset",
+				Labels: map[string]string{"topic": `topic"2"`, "partition": "1"},
+				Value:  5500,
+			},
+			{
+				Name:   "jvm_threads_current",
+				Labels: map[string]string{},
+				Value:  42,
+			},
+		},
+		samples,
+	)
+
+	_, err = ParseText(strings.NewReader(`metric{topic="unterminated} 1`))
+	assert.Error(t, err)
+	_, err = ParseText(strings.NewReader(`metric{topic="topic1"} not-a-number`))
+	assert.Error(t, err)
+	_, err = ParseText(strings.NewReader(`metric`))
+	assert.Error(t, err)
+}
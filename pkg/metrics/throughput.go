@@ -0,0 +1,240 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+)
+
+const (
+	// MetricTypeCounter is the type of metrics whose values only go up, e.g. the log end
+	// offsets of partitions. Their throughputs are the rates at which they increase.
+	MetricTypeCounter = "counter"
+
+	// MetricTypeGauge is the type of metrics whose values are already throughputs, e.g. the
+	// one-minute rates of per-partition meters.
+	MetricTypeGauge = "gauge"
+
+	scrapeTimeout = 30 * time.Second
+)
+
+// AllMetricTypes are all of the supported metric types.
+var AllMetricTypes = []string{
+	MetricTypeCounter,
+	MetricTypeGauge,
+}
+
+// ThroughputConfig stores the settings for getting per-partition throughputs from the
+// Prometheus metrics endpoints of the brokers in a cluster.
+type ThroughputConfig struct {
+	// URLTemplate is the URL of each broker's metrics endpoint, with {host} and {id} replaced
+	// by the broker's host and ID, e.g. http://{host}:7071/metrics.
+	URLTemplate string
+
+	// Metric is the name of the per-partition metric, and Type is either MetricTypeCounter or
+	// MetricTypeGauge.
+	Metric string
+	Type   string
+
+	// TopicLabel and PartitionLabel are the labels of the metric that have the topic name and
+	// partition ID.
+	TopicLabel     string
+	PartitionLabel string
+
+	// SampleInterval is the time between the two scrapes that the rates of counters are
+	// computed from. It isn't used for gauges.
+	SampleInterval time.Duration
+}
+
+// PartitionThroughputs are the throughputs of the partitions in a cluster, keyed by topic name
+// and then partition ID. The units depend on the metric, e.g. messages per second for log end
+// offsets.
+type PartitionThroughputs map[string]map[int]float64
+
+// ThroughputSource gets the throughputs of the partitions in a cluster by scraping the metrics
+// endpoints of its brokers.
+type ThroughputSource struct {
+	config     ThroughputConfig
+	httpClient *http.Client
+}
+
+// NewThroughputSource returns a new ThroughputSource for the argument config.
+func NewThroughputSource(config ThroughputConfig) (*ThroughputSource, error) {
+	if config.URLTemplate == "" {
+		return nil, errors.New("Metrics URL template must be set")
+	}
+	if config.Metric == "" {
+		return nil, errors.New("Metric name must be set")
+	}
+	if config.Type != MetricTypeCounter && config.Type != MetricTypeGauge {
+		return nil, fmt.Errorf("Metric type must be in %+v", AllMetricTypes)
+	}
+	if config.Type == MetricTypeCounter && config.SampleInterval <= 0 {
+		return nil, errors.New("Sample interval must be positive for counter metrics")
+	}
+
+	return &ThroughputSource{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: scrapeTimeout,
+		},
+	}, nil
+}
+
+// BrokerURL returns the URL of the metrics endpoint of the argument broker.
+func (s *ThroughputSource) BrokerURL(broker admin.BrokerInfo) string {
+	return strings.NewReplacer(
+		"{host}", broker.Host,
+		"{id}", strconv.Itoa(broker.ID),
+	).Replace(s.config.URLTemplate)
+}
+
+// PartitionThroughputs gets the throughputs of the partitions on the argument brokers. Since
+// each partition is reported by every broker that has one of its replicas, the largest value
+// across the brokers, i.e. the one from the leader, is used. For counters, the brokers are
+// scraped twice, SampleInterval apart.
+func (s *ThroughputSource) PartitionThroughputs(
+	ctx context.Context,
+	brokers []admin.BrokerInfo,
+) (PartitionThroughputs, error) {
+	first, err := s.scrapeAll(ctx, brokers)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.Type == MetricTypeGauge {
+		return first, nil
+	}
+
+	log.Infof(
+		"Waiting %s to scrape partition metrics from %d broker(s) again",
+		s.config.SampleInterval,
+		len(brokers),
+	)
+	start := time.Now()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(s.config.SampleInterval):
+	}
+
+	second, err := s.scrapeAll(ctx, brokers)
+	if err != nil {
+		return nil, err
+	}
+
+	return CounterRates(first, second, time.Since(start)), nil
+}
+
+// CounterRates returns the per-second rates at which the argument counter values increased
+// over the argument interval. Partitions that are missing from either set of values are
+// skipped, and counters that went down, e.g. because a partition was recreated or its leader
+// changed between the scrapes, are treated as not having increased.
+func CounterRates(
+	first PartitionThroughputs,
+	second PartitionThroughputs,
+	interval time.Duration,
+) PartitionThroughputs {
+	rates := PartitionThroughputs{}
+	seconds := interval.Seconds()
+	if seconds <= 0 {
+		return rates
+	}
+
+	for topic, partitions := range second {
+		for partition, value := range partitions {
+			firstValue, ok := first[topic][partition]
+			if !ok {
+				continue
+			}
+
+			rate := (value - firstValue) / seconds
+			if rate < 0 {
+				rate = 0
+			}
+
+			if _, ok := rates[topic]; !ok {
+				rates[topic] = map[int]float64{}
+			}
+			rates[topic][partition] = rate
+		}
+	}
+
+	return rates
+}
+
+func (s *ThroughputSource) scrapeAll(
+	ctx context.Context,
+	brokers []admin.BrokerInfo,
+) (PartitionThroughputs, error) {
+	values := PartitionThroughputs{}
+
+	for _, broker := range brokers {
+		samples, err := s.scrape(ctx, broker)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sample := range samples {
+			if sample.Name != s.config.Metric {
+				continue
+			}
+
+			topic := sample.Labels[s.config.TopicLabel]
+			partition, err := strconv.Atoi(sample.Labels[s.config.PartitionLabel])
+			if topic == "" || err != nil {
+				continue
+			}
+
+			if _, ok := values[topic]; !ok {
+				values[topic] = map[int]float64{}
+			}
+			if value, ok := values[topic][partition]; !ok || sample.Value > value {
+				values[topic][partition] = sample.Value
+			}
+		}
+	}
+
+	return values, nil
+}
+
+func (s *ThroughputSource) scrape(
+	ctx context.Context,
+	broker admin.BrokerInfo,
+) ([]Sample, error) {
+	url := s.BrokerURL(broker)
+	log.Debugf("Scraping metrics for broker %d from %s", broker.ID, url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error scraping metrics for broker %d: %+v", broker.ID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"Got status %d when scraping metrics for broker %d from %s",
+			resp.StatusCode,
+			broker.ID,
+			url,
+		)
+	}
+
+	samples, err := ParseText(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing metrics for broker %d: %+v", broker.ID, err)
+	}
+	return samples, nil
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThroughputSourceGauge(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/metrics":
+				fmt.Fprintln(w, `partition_bytes_in{topic="topic1",partition="0"} 100`)
+				fmt.Fprintln(w, `partition_bytes_in{topic="topic1",partition="1"} 10`)
+				fmt.Fprintln(w, `other_metric{topic="topic1",partition="0"} 1000`)
+			case "/2/metrics":
+				fmt.Fprintln(w, `partition_bytes_in{topic="topic1",partition="0"} 90`)
+				fmt.Fprintln(w, `partition_bytes_in{topic="topic2",partition="0"} 5`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	source, err := NewThroughputSource(
+		ThroughputConfig{
+			URLTemplate:    fmt.Sprintf("%s/{id}/metrics", server.URL),
+			Metric:         "partition_bytes_in",
+			Type:           MetricTypeGauge,
+			TopicLabel:     "topic",
+			PartitionLabel: "partition",
+		},
+	)
+	require.NoError(t, err)
+
+	throughputs, err := source.PartitionThroughputs(
+		context.Background(),
+		[]admin.BrokerInfo{{ID: 1}, {ID: 2}},
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		PartitionThroughputs{
+			"topic1": {0: 100, 1: 10},
+			"topic2": {0: 5},
+		},
+		throughputs,
+	)
+
+	_, err = source.PartitionThroughputs(context.Background(), []admin.BrokerInfo{{ID: 3}})
+	assert.Error(t, err)
+}
+
+func TestThroughputSourceCounter(t *testing.T) {
+	var mutex sync.Mutex
+	var offset int
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			fmt.Fprintf(w, "log_end_offset{topic=\"topic1\",partition=\"0\"} %d\n", offset)
+			offset += 1000
+		}),
+	)
+	defer server.Close()
+
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	source, err := NewThroughputSource(
+		ThroughputConfig{
+			URLTemplate:    fmt.Sprintf("http://{host}:%s/metrics", port),
+			Metric:         "log_end_offset",
+			Type:           MetricTypeCounter,
+			TopicLabel:     "topic",
+			PartitionLabel: "partition",
+			SampleInterval: 100 * time.Millisecond,
+		},
+	)
+	require.NoError(t, err)
+
+	throughputs, err := source.PartitionThroughputs(
+		context.Background(),
+		[]admin.BrokerInfo{{ID: 1, Host: host}},
+	)
+	require.NoError(t, err)
+
+	// The counter went up by 1000 in a bit more than 100ms
+	rate := throughputs["topic1"][0]
+	assert.Greater(t, rate, 0.0)
+	assert.LessOrEqual(t, rate, 10000.0)
+}
+
+func TestCounterRates(t *testing.T) {
+	assert.Equal(
+		t,
+		PartitionThroughputs{
+			"topic1": {0: 10, 1: 0},
+		},
+		CounterRates(
+			PartitionThroughputs{
+				"topic1": {0: 100, 1: 500},
+				"topic2": {0: 100},
+			},
+			PartitionThroughputs{
+				"topic1": {0: 200, 1: 100, 2: 50},
+			},
+			10*time.Second,
+		),
+	)
+}
+
+func TestNewThroughputSourceErrors(t *testing.T) {
+	_, err := NewThroughputSource(ThroughputConfig{Metric: "metric", Type: MetricTypeGauge})
+	assert.Error(t, err)
+	_, err = NewThroughputSource(
+		ThroughputConfig{URLTemplate: "http://{host}/metrics", Metric: "metric", Type: "bad"},
+	)
+	assert.Error(t, err)
+	_, err = NewThroughputSource(
+		ThroughputConfig{
+			URLTemplate: "http://{host}/metrics",
+			Metric:      "metric",
+			Type:        MetricTypeCounter,
+		},
+	)
+	assert.Error(t, err)
+}
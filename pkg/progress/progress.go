@@ -0,0 +1,151 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// StdoutTarget is the reporter target that writes events to stdout instead of a socket.
+const StdoutTarget = "stdout"
+
+// EventType is a string type that identifies the kind of progress event.
+type EventType string
+
+const (
+	// EventTypeStarted is emitted when topicctl starts updating a topic.
+	EventTypeStarted EventType = "started"
+
+	// EventTypeBatchStarted is emitted before each batch of partitions in a step is updated.
+	EventTypeBatchStarted EventType = "batch-started"
+
+	// EventTypeBatchWaiting is emitted each time topicctl checks on a batch that hasn't finished
+	// yet, e.g. because the moved replicas are still catching up.
+	EventTypeBatchWaiting EventType = "batch-waiting"
+
+	// EventTypeBatchCompleted is emitted after each batch of partitions in a step is updated.
+	EventTypeBatchCompleted EventType = "batch-completed"
+
+	// EventTypeCompleted is emitted when all of the updates to a topic have been made.
+	EventTypeCompleted EventType = "completed"
+
+	// EventTypeFailed is emitted when updating a topic fails or is interrupted.
+	EventTypeFailed EventType = "failed"
+)
+
+// Step is a string type that identifies the part of an apply that a batch event is for.
+type Step string
+
+const (
+	// StepPartitions is the step that adds partitions to a topic.
+	StepPartitions Step = "partitions"
+
+	// StepPlacement is the step that moves replicas, either to match the placement strategy
+	// or to rebalance the topic.
+	StepPlacement Step = "placement"
+
+	// StepLeaders is the step that runs leader elections for partitions with the wrong leaders.
+	StepLeaders Step = "leaders"
+)
+
+// Event is a single progress update. Each event is written as a JSON object on its own line.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    EventType `json:"type"`
+	Cluster string    `json:"cluster"`
+	Topic   string    `json:"topic"`
+	DryRun  bool      `json:"dryRun,omitempty"`
+
+	// The remaining fields are only set for batch events. Batch is the 1-based index of the
+	// batch out of Batches total, and Completed is the number of partitions in the step that
+	// have been updated out of Total.
+	Step       Step  `json:"step,omitempty"`
+	Batch      int   `json:"batch,omitempty"`
+	Batches    int   `json:"batches,omitempty"`
+	Completed  int   `json:"completed,omitempty"`
+	Total      int   `json:"total,omitempty"`
+	Partitions []int `json:"partitions,omitempty"`
+
+	// NotReady is the subset of the batch partitions that haven't been updated yet; it's only
+	// set for batch-waiting events.
+	NotReady []int `json:"notReady,omitempty"`
+
+	// Error is set for failed events.
+	Error string `json:"error,omitempty"`
+}
+
+// Reporter writes progress events as JSON lines to a unix socket or stdout. A nil Reporter is
+// valid and drops all events, so callers don't need to check whether reporting is enabled.
+type Reporter struct {
+	writer io.Writer
+	closer io.Closer
+
+	mutex  sync.Mutex
+	failed bool
+}
+
+// NewReporter returns a Reporter for the argument target, which is either StdoutTarget or the
+// path of a unix socket that's already being listened on, e.g. by an orchestration wrapper. A
+// "unix://" prefix on the path is optional. Nil is returned if the target is empty.
+func NewReporter(target string) (*Reporter, error) {
+	switch target {
+	case "":
+		return nil, nil
+	case StdoutTarget:
+		return &Reporter{writer: os.Stdout}, nil
+	}
+
+	conn, err := net.Dial("unix", strings.TrimPrefix(target, "unix://"))
+	if err != nil {
+		return nil, err
+	}
+	return &Reporter{writer: conn, closer: conn}, nil
+}
+
+// NewWriterReporter returns a Reporter that writes to the argument writer.
+func NewWriterReporter(writer io.Writer) *Reporter {
+	return &Reporter{writer: writer}
+}
+
+// Report writes the argument event, filling in its time if it isn't set. Write failures are
+// logged instead of returned so that a closed socket doesn't interrupt the operation being
+// reported on; no further events are written after the first failure.
+func (r *Reporter) Report(event Event) {
+	if r == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		log.Warnf("Could not encode progress event: %+v", err)
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.failed {
+		return
+	}
+	if _, err := r.writer.Write(append(line, '\n')); err != nil {
+		log.Warnf("Could not write progress event; no more events will be sent: %+v", err)
+		r.failed = true
+	}
+}
+
+// Close closes the underlying socket, if there is one.
+func (r *Reporter) Close() error {
+	if r == nil || r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
@@ -0,0 +1,145 @@
+package progress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporterReport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewWriterReporter(buf)
+
+	reporter.Report(
+		Event{
+			Type:       EventTypeBatchStarted,
+			Cluster:    "test-cluster",
+			Topic:      "test-topic",
+			Step:       StepPlacement,
+			Batch:      2,
+			Batches:    3,
+			Completed:  4,
+			Total:      10,
+			Partitions: []int{4, 5, 6},
+		},
+	)
+	reporter.Report(
+		Event{
+			Type:    EventTypeCompleted,
+			Cluster: "test-cluster",
+			Topic:   "test-topic",
+		},
+	)
+
+	lines := bytes.Split(bytes.TrimRight(buf.Bytes(), "\n"), []byte("\n"))
+	require.Equal(t, 2, len(lines))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(lines[0], &event))
+	assert.False(t, event.Time.IsZero())
+	event.Time = time.Time{}
+	assert.Equal(
+		t,
+		Event{
+			Type:       EventTypeBatchStarted,
+			Cluster:    "test-cluster",
+			Topic:      "test-topic",
+			Step:       StepPlacement,
+			Batch:      2,
+			Batches:    3,
+			Completed:  4,
+			Total:      10,
+			Partitions: []int{4, 5, 6},
+		},
+		event,
+	)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[1], &fields))
+	assert.Equal(t, "completed", fields["type"])
+	assert.NotContains(t, fields, "step")
+	assert.NotContains(t, fields, "partitions")
+}
+
+func TestReporterNil(t *testing.T) {
+	reporter, err := NewReporter("")
+	require.NoError(t, err)
+	assert.Nil(t, reporter)
+
+	// Nil reporters should drop events without panicking
+	reporter.Report(Event{Type: EventTypeStarted})
+	assert.NoError(t, reporter.Close())
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("closed")
+}
+
+func TestReporterWriteFailure(t *testing.T) {
+	writer := &failingWriter{}
+	reporter := NewWriterReporter(writer)
+
+	reporter.Report(Event{Type: EventTypeStarted})
+	reporter.Report(Event{Type: EventTypeCompleted})
+	assert.Equal(t, 1, writer.writes)
+}
+
+func TestReporterSocket(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "progress")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	socketPath := filepath.Join(tempDir, "progress.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	linesChan := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			linesChan <- nil
+			return
+		}
+		defer conn.Close()
+
+		lines := []string{}
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		linesChan <- lines
+	}()
+
+	reporter, err := NewReporter("unix://" + socketPath)
+	require.NoError(t, err)
+	reporter.Report(Event{Type: EventTypeStarted, Topic: "test-topic"})
+	reporter.Report(Event{Type: EventTypeCompleted, Topic: "test-topic"})
+	require.NoError(t, reporter.Close())
+
+	lines := <-linesChan
+	require.Equal(t, 2, len(lines))
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, EventTypeCompleted, event.Type)
+	assert.Equal(t, "test-topic", event.Topic)
+
+	_, err = NewReporter(filepath.Join(tempDir, "missing.sock"))
+	assert.Error(t, err)
+}
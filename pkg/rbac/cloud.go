@@ -0,0 +1,319 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultCloudURL is the base URL of the Confluent Cloud API.
+	DefaultCloudURL = "https://api.confluent.cloud"
+
+	cloudPageSize = 100
+)
+
+// CloudConfig stores the settings for managing role bindings via the Confluent Cloud IAM API.
+type CloudConfig struct {
+	// URL is the base URL of the API; it defaults to DefaultCloudURL.
+	URL string
+
+	// APIKey and APISecret are a Cloud API key, i.e. not one scoped to a Kafka cluster, of a
+	// principal that's allowed to manage role bindings in the cluster.
+	APIKey    string
+	APISecret string
+
+	// OrganizationID, EnvironmentID, and ClusterID identify the Kafka cluster, e.g. lkc-abc123,
+	// that the role bindings are scoped to.
+	OrganizationID string
+	EnvironmentID  string
+	ClusterID      string
+}
+
+// CloudClient is a Client that manages the role bindings of a Confluent Cloud cluster via the
+// IAM v2 API. Bindings are identified by CRN patterns in Confluent Cloud; these are converted
+// to and from the resource types and pattern types used elsewhere.
+type CloudClient struct {
+	config CloudConfig
+	rest   restClient
+}
+
+var _ Client = (*CloudClient)(nil)
+
+type cloudRoleBinding struct {
+	ID         string `json:"id,omitempty"`
+	Principal  string `json:"principal"`
+	RoleName   string `json:"role_name"`
+	CRNPattern string `json:"crn_pattern"`
+}
+
+type cloudRoleBindingList struct {
+	Data     []cloudRoleBinding `json:"data"`
+	Metadata struct {
+		Next string `json:"next"`
+	} `json:"metadata"`
+}
+
+var cloudResourceSegments = map[string]string{
+	ResourceTypeTopic:           "topic",
+	ResourceTypeGroup:           "group",
+	ResourceTypeTransactionalID: "transactional-id",
+}
+
+// NewCloudClient returns a new CloudClient for the argument config.
+func NewCloudClient(config CloudConfig) (*CloudClient, error) {
+	if config.OrganizationID == "" || config.EnvironmentID == "" || config.ClusterID == "" {
+		return nil, errors.New("Organization, environment, and cluster IDs must be set")
+	}
+	if config.URL == "" {
+		config.URL = DefaultCloudURL
+	}
+
+	rest, err := newRESTClient(
+		"Confluent Cloud",
+		config.URL,
+		config.APIKey,
+		config.APISecret,
+		"",
+		false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CloudClient{
+		config: config,
+		rest:   rest,
+	}, nil
+}
+
+// GetRoleBindings gets the current bindings of the argument principals in the Kafka cluster.
+func (c *CloudClient) GetRoleBindings(
+	ctx context.Context,
+	principals []string,
+) ([]RoleBinding, error) {
+	bindings := []RoleBinding{}
+
+	for _, principal := range principals {
+		// Bindings on the cluster itself and on the resources in it need separate queries
+		for _, crnPattern := range []string{
+			c.clusterCRN(),
+			fmt.Sprintf("%s/kafka=%s/*", c.clusterCRN(), c.config.ClusterID),
+		} {
+			cloudBindings, err := c.listBindings(ctx, principal, "", crnPattern)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, cloudBinding := range cloudBindings {
+				binding, ok := c.fromCloudBinding(cloudBinding)
+				if !ok {
+					log.Debugf(
+						"Ignoring %s binding of %s on unsupported resource %s",
+						cloudBinding.RoleName,
+						cloudBinding.Principal,
+						cloudBinding.CRNPattern,
+					)
+					continue
+				}
+				bindings = append(bindings, binding)
+			}
+		}
+	}
+
+	SortRoleBindings(bindings)
+	return dedupeRoleBindings(bindings), nil
+}
+
+// CreateRoleBinding creates the argument binding in the Kafka cluster.
+func (c *CloudClient) CreateRoleBinding(ctx context.Context, binding RoleBinding) error {
+	crnPattern, err := c.crnPattern(binding)
+	if err != nil {
+		return err
+	}
+
+	return c.rest.request(
+		ctx,
+		http.MethodPost,
+		"/iam/v2/role-bindings",
+		cloudRoleBinding{
+			Principal:  binding.Principal,
+			RoleName:   binding.Role,
+			CRNPattern: crnPattern,
+		},
+		nil,
+	)
+}
+
+// DeleteRoleBinding deletes the argument binding from the Kafka cluster. Cloud bindings are
+// deleted by ID, which is looked up first.
+func (c *CloudClient) DeleteRoleBinding(ctx context.Context, binding RoleBinding) error {
+	crnPattern, err := c.crnPattern(binding)
+	if err != nil {
+		return err
+	}
+
+	cloudBindings, err := c.listBindings(ctx, binding.Principal, binding.Role, crnPattern)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudBinding := range cloudBindings {
+		if cloudBinding.CRNPattern != crnPattern {
+			continue
+		}
+		return c.rest.request(
+			ctx,
+			http.MethodDelete,
+			fmt.Sprintf("/iam/v2/role-bindings/%s", url.PathEscape(cloudBinding.ID)),
+			nil,
+			nil,
+		)
+	}
+
+	return fmt.Errorf("Could not find role binding %s", binding)
+}
+
+func (c *CloudClient) listBindings(
+	ctx context.Context,
+	principal string,
+	role string,
+	crnPattern string,
+) ([]cloudRoleBinding, error) {
+	cloudBindings := []cloudRoleBinding{}
+	pageToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("principal", principal)
+		query.Set("crn_pattern", crnPattern)
+		query.Set("page_size", fmt.Sprintf("%d", cloudPageSize))
+		if role != "" {
+			query.Set("role_name", role)
+		}
+		if pageToken != "" {
+			query.Set("page_token", pageToken)
+		}
+
+		result := cloudRoleBindingList{}
+		if err := c.rest.request(
+			ctx,
+			http.MethodGet,
+			"/iam/v2/role-bindings?"+query.Encode(),
+			nil,
+			&result,
+		); err != nil {
+			return nil, err
+		}
+		cloudBindings = append(cloudBindings, result.Data...)
+
+		if result.Metadata.Next == "" {
+			return cloudBindings, nil
+		}
+		nextURL, err := url.Parse(result.Metadata.Next)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid next page URL %s: %+v", result.Metadata.Next, err)
+		}
+		pageToken = nextURL.Query().Get("page_token")
+		if pageToken == "" {
+			return cloudBindings, nil
+		}
+	}
+}
+
+func (c *CloudClient) clusterCRN() string {
+	return fmt.Sprintf(
+		"crn://confluent.cloud/organization=%s/environment=%s/cloud-cluster=%s",
+		c.config.OrganizationID,
+		c.config.EnvironmentID,
+		c.config.ClusterID,
+	)
+}
+
+// crnPattern converts the argument binding to the CRN pattern used by Confluent Cloud, e.g.
+// .../cloud-cluster=lkc-abc123/kafka=lkc-abc123/topic=orders-* for a prefixed topic binding.
+func (c *CloudClient) crnPattern(binding RoleBinding) (string, error) {
+	if binding.IsClusterBinding() {
+		return c.clusterCRN(), nil
+	}
+
+	segment, ok := cloudResourceSegments[binding.ResourceType]
+	if !ok {
+		return "", fmt.Errorf("Unsupported resource type for role binding %s", binding)
+	}
+
+	name := binding.Name
+	if binding.PatternType == PatternTypePrefixed {
+		name += "*"
+	}
+
+	return fmt.Sprintf(
+		"%s/kafka=%s/%s=%s",
+		c.clusterCRN(),
+		c.config.ClusterID,
+		segment,
+		name,
+	), nil
+}
+
+// fromCloudBinding converts the argument Confluent Cloud binding back to a RoleBinding. It
+// returns false if the binding isn't on the cluster or a supported resource type in it.
+func (c *CloudClient) fromCloudBinding(cloudBinding cloudRoleBinding) (RoleBinding, bool) {
+	binding := RoleBinding{
+		Principal: cloudBinding.Principal,
+		Role:      cloudBinding.RoleName,
+	}
+
+	if cloudBinding.CRNPattern == c.clusterCRN() {
+		binding.ResourceType = ResourceTypeCluster
+		return binding, true
+	}
+
+	resourcePrefix := fmt.Sprintf("%s/kafka=%s/", c.clusterCRN(), c.config.ClusterID)
+	if !strings.HasPrefix(cloudBinding.CRNPattern, resourcePrefix) {
+		return RoleBinding{}, false
+	}
+
+	elements := strings.SplitN(
+		strings.TrimPrefix(cloudBinding.CRNPattern, resourcePrefix),
+		"=",
+		2,
+	)
+	if len(elements) != 2 || elements[1] == "" {
+		return RoleBinding{}, false
+	}
+
+	for resourceType, segment := range cloudResourceSegments {
+		if elements[0] == segment {
+			binding.ResourceType = resourceType
+		}
+	}
+	if binding.ResourceType == "" {
+		return RoleBinding{}, false
+	}
+
+	if strings.HasSuffix(elements[1], "*") {
+		binding.Name = strings.TrimSuffix(elements[1], "*")
+		binding.PatternType = PatternTypePrefixed
+	} else {
+		binding.Name = elements[1]
+		binding.PatternType = PatternTypeLiteral
+	}
+
+	return binding, true
+}
+
+// dedupeRoleBindings removes adjacent duplicates from the argument sorted bindings.
+func dedupeRoleBindings(bindings []RoleBinding) []RoleBinding {
+	deduped := []RoleBinding{}
+	for b, binding := range bindings {
+		if b > 0 && binding == bindings[b-1] {
+			continue
+		}
+		deduped = append(deduped, binding)
+	}
+	return deduped
+}
@@ -0,0 +1,203 @@
+package rbac
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClusterCRN = "crn://confluent.cloud/organization=org-1/environment=env-1/cloud-cluster=lkc-1"
+
+func TestCloudClient(t *testing.T) {
+	mutex := sync.Mutex{}
+	requests := []recordedRequest{}
+
+	existing := []cloudRoleBinding{
+		{
+			ID:         "rb-1",
+			Principal:  "User:u-alice",
+			RoleName:   "CloudClusterAdmin",
+			CRNPattern: testClusterCRN,
+		},
+		{
+			ID:         "rb-2",
+			Principal:  "User:u-alice",
+			RoleName:   "DeveloperRead",
+			CRNPattern: testClusterCRN + "/kafka=lkc-1/topic=orders-*",
+		},
+		{
+			ID:         "rb-3",
+			Principal:  "User:u-alice",
+			RoleName:   "DeveloperRead",
+			CRNPattern: testClusterCRN + "/kafka=lkc-1/group=orders",
+		},
+		{
+			ID:         "rb-4",
+			Principal:  "User:u-alice",
+			RoleName:   "ResourceOwner",
+			CRNPattern: testClusterCRN + "/kafka=lkc-1/ksql=lksqlc-1",
+		},
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, _ := r.BasicAuth()
+			if username != "API_KEY" || password != "API_SECRET" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			body, _ := ioutil.ReadAll(r.Body)
+			mutex.Lock()
+			requests = append(
+				requests,
+				recordedRequest{Method: r.Method, Path: r.URL.Path, Body: string(body)},
+			)
+			mutex.Unlock()
+
+			if r.Method != http.MethodGet {
+				return
+			}
+
+			// Return the matching bindings one per page to exercise the pagination
+			query := r.URL.Query()
+			crnPattern := query.Get("crn_pattern")
+			matches := []cloudRoleBinding{}
+			for _, binding := range existing {
+				if binding.Principal != query.Get("principal") {
+					continue
+				}
+				if query.Get("role_name") != "" && binding.RoleName != query.Get("role_name") {
+					continue
+				}
+				if strings.HasSuffix(crnPattern, "/*") {
+					if !strings.HasPrefix(binding.CRNPattern, strings.TrimSuffix(crnPattern, "*")) {
+						continue
+					}
+				} else if binding.CRNPattern != crnPattern {
+					continue
+				}
+				matches = append(matches, binding)
+			}
+
+			page := 0
+			if query.Get("page_token") != "" {
+				page = len(query.Get("page_token"))
+			}
+
+			result := cloudRoleBindingList{Data: []cloudRoleBinding{}}
+			if page < len(matches) {
+				result.Data = append(result.Data, matches[page])
+			}
+			if page+1 < len(matches) {
+				next := *r.URL
+				nextQuery := next.Query()
+				nextQuery.Set("page_token", strings.Repeat("x", page+1))
+				next.RawQuery = nextQuery.Encode()
+				result.Metadata.Next = server.URL + next.String()
+			}
+			json.NewEncoder(w).Encode(result)
+		}),
+	)
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := NewCloudClient(
+		CloudConfig{
+			URL:            server.URL,
+			APIKey:         "API_KEY",
+			APISecret:      "API_SECRET",
+			OrganizationID: "org-1",
+			EnvironmentID:  "env-1",
+			ClusterID:      "lkc-1",
+		},
+	)
+	require.NoError(t, err)
+
+	bindings, err := client.GetRoleBindings(ctx, []string{"User:u-alice"})
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]RoleBinding{
+			{
+				Principal:    "User:u-alice",
+				Role:         "CloudClusterAdmin",
+				ResourceType: ResourceTypeCluster,
+			},
+			{
+				Principal:    "User:u-alice",
+				Role:         "DeveloperRead",
+				ResourceType: ResourceTypeGroup,
+				Name:         "orders",
+				PatternType:  PatternTypeLiteral,
+			},
+			{
+				Principal:    "User:u-alice",
+				Role:         "DeveloperRead",
+				ResourceType: ResourceTypeTopic,
+				Name:         "orders-",
+				PatternType:  PatternTypePrefixed,
+			},
+		},
+		bindings,
+	)
+
+	requests = nil
+	err = client.CreateRoleBinding(
+		ctx,
+		RoleBinding{
+			Principal:    "User:u-alice",
+			Role:         "DeveloperWrite",
+			ResourceType: ResourceTypeTransactionalID,
+			Name:         "orders",
+			PatternType:  PatternTypeLiteral,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(requests))
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "/iam/v2/role-bindings", requests[0].Path)
+	assert.JSONEq(
+		t,
+		`{"principal":"User:u-alice","role_name":"DeveloperWrite","crn_pattern":"`+
+			testClusterCRN+`/kafka=lkc-1/transactional-id=orders"}`,
+		requests[0].Body,
+	)
+
+	requests = nil
+	err = client.DeleteRoleBinding(
+		ctx,
+		RoleBinding{
+			Principal:    "User:u-alice",
+			Role:         "DeveloperRead",
+			ResourceType: ResourceTypeTopic,
+			Name:         "orders-",
+			PatternType:  PatternTypePrefixed,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(requests))
+	assert.Equal(t, http.MethodDelete, requests[1].Method)
+	assert.Equal(t, "/iam/v2/role-bindings/rb-2", requests[1].Path)
+
+	err = client.DeleteRoleBinding(
+		ctx,
+		RoleBinding{
+			Principal:    "User:u-alice",
+			Role:         "DeveloperRead",
+			ResourceType: ResourceTypeTopic,
+			Name:         "payments",
+			PatternType:  PatternTypeLiteral,
+		},
+	)
+	assert.Error(t, err)
+}
@@ -0,0 +1,5 @@
+package rbac
+
+import "github.com/segmentio/topicctl/pkg/logging"
+
+var log = logging.Logger(logging.SubsystemAdmin)
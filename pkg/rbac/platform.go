@@ -0,0 +1,207 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PlatformConfig stores the settings for managing role bindings via the metadata service (MDS)
+// of a Confluent Platform cluster.
+type PlatformConfig struct {
+	// URL is the base URL of the metadata service, e.g. https://kafka1.example.com:8090.
+	URL string
+
+	// Username and Password are the credentials of a principal with the UserAdmin or
+	// SystemAdmin role.
+	Username string
+	Password string
+
+	// KafkaClusterID is the ID of the Kafka cluster that the role bindings are scoped to.
+	KafkaClusterID string
+
+	CACertPath string
+	SkipVerify bool
+}
+
+// PlatformClient is a Client that manages the role bindings of a Confluent Platform cluster via
+// the MDS v1 API.
+type PlatformClient struct {
+	config PlatformConfig
+	rest   restClient
+}
+
+var _ Client = (*PlatformClient)(nil)
+
+type mdsScope struct {
+	Clusters map[string]string `json:"clusters"`
+}
+
+type mdsResourcePattern struct {
+	ResourceType string `json:"resourceType"`
+	Name         string `json:"name"`
+	PatternType  string `json:"patternType"`
+}
+
+type mdsBindingsRequest struct {
+	Scope            mdsScope             `json:"scope"`
+	ResourcePatterns []mdsResourcePattern `json:"resourcePatterns"`
+}
+
+// NewPlatformClient returns a new PlatformClient for the argument config.
+func NewPlatformClient(config PlatformConfig) (*PlatformClient, error) {
+	if config.URL == "" {
+		return nil, errors.New("Metadata service URL must be set")
+	}
+	if config.KafkaClusterID == "" {
+		return nil, errors.New("Kafka cluster ID must be set")
+	}
+
+	rest, err := newRESTClient(
+		"metadata service",
+		config.URL,
+		config.Username,
+		config.Password,
+		config.CACertPath,
+		config.SkipVerify,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlatformClient{
+		config: config,
+		rest:   rest,
+	}, nil
+}
+
+// GetRoleBindings gets the current bindings of the argument principals in the Kafka cluster.
+func (p *PlatformClient) GetRoleBindings(
+	ctx context.Context,
+	principals []string,
+) ([]RoleBinding, error) {
+	bindings := []RoleBinding{}
+
+	for _, principal := range principals {
+		// The result maps each principal to the resource patterns of each of its roles.
+		// Cluster-level roles have either no patterns or a single cluster pattern.
+		result := map[string]map[string][]mdsResourcePattern{}
+
+		if err := p.rest.request(
+			ctx,
+			http.MethodPost,
+			fmt.Sprintf("/security/1.0/lookup/principal/%s/resources", url.PathEscape(principal)),
+			p.scope(),
+			&result,
+		); err != nil {
+			return nil, err
+		}
+
+		for role, patterns := range result[principal] {
+			if len(patterns) == 0 {
+				bindings = append(bindings, clusterBinding(principal, role))
+				continue
+			}
+
+			for _, pattern := range patterns {
+				if !isValidResourceType(pattern.ResourceType) {
+					log.Debugf(
+						"Ignoring %s binding of %s on unsupported resource type %s",
+						role,
+						principal,
+						pattern.ResourceType,
+					)
+					continue
+				}
+				if pattern.ResourceType == ResourceTypeCluster {
+					bindings = append(bindings, clusterBinding(principal, role))
+					continue
+				}
+
+				bindings = append(
+					bindings,
+					RoleBinding{
+						Principal:    principal,
+						Role:         role,
+						ResourceType: pattern.ResourceType,
+						Name:         pattern.Name,
+						PatternType:  pattern.PatternType,
+					},
+				)
+			}
+		}
+	}
+
+	SortRoleBindings(bindings)
+	return bindings, nil
+}
+
+// CreateRoleBinding creates the argument binding in the Kafka cluster.
+func (p *PlatformClient) CreateRoleBinding(ctx context.Context, binding RoleBinding) error {
+	return p.updateBinding(ctx, http.MethodPost, binding)
+}
+
+// DeleteRoleBinding deletes the argument binding from the Kafka cluster.
+func (p *PlatformClient) DeleteRoleBinding(ctx context.Context, binding RoleBinding) error {
+	return p.updateBinding(ctx, http.MethodDelete, binding)
+}
+
+func (p *PlatformClient) updateBinding(
+	ctx context.Context,
+	method string,
+	binding RoleBinding,
+) error {
+	path := fmt.Sprintf(
+		"/security/1.0/principals/%s/roles/%s",
+		url.PathEscape(binding.Principal),
+		url.PathEscape(binding.Role),
+	)
+
+	if binding.IsClusterBinding() {
+		return p.rest.request(ctx, method, path, p.scope(), nil)
+	}
+
+	return p.rest.request(
+		ctx,
+		method,
+		path+"/bindings",
+		mdsBindingsRequest{
+			Scope: p.scope(),
+			ResourcePatterns: []mdsResourcePattern{
+				{
+					ResourceType: binding.ResourceType,
+					Name:         binding.Name,
+					PatternType:  binding.PatternType,
+				},
+			},
+		},
+		nil,
+	)
+}
+
+func (p *PlatformClient) scope() mdsScope {
+	return mdsScope{
+		Clusters: map[string]string{
+			"kafka-cluster": p.config.KafkaClusterID,
+		},
+	}
+}
+
+func clusterBinding(principal string, role string) RoleBinding {
+	return RoleBinding{
+		Principal:    principal,
+		Role:         role,
+		ResourceType: ResourceTypeCluster,
+	}
+}
+
+func isValidResourceType(resourceType string) bool {
+	for _, validType := range AllResourceTypes {
+		if resourceType == validType {
+			return true
+		}
+	}
+	return false
+}
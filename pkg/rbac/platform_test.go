@@ -0,0 +1,166 @@
+package rbac
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+func TestPlatformClient(t *testing.T) {
+	mutex := sync.Mutex{}
+	requests := []recordedRequest{}
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, _ := r.BasicAuth()
+			if username != "topicctl" || password != "test-password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			body, _ := ioutil.ReadAll(r.Body)
+			mutex.Lock()
+			requests = append(
+				requests,
+				recordedRequest{Method: r.Method, Path: r.URL.EscapedPath(), Body: string(body)},
+			)
+			mutex.Unlock()
+
+			if r.URL.Path == "/security/1.0/lookup/principal/User:alice/resources" {
+				json.NewEncoder(w).Encode(
+					map[string]map[string][]mdsResourcePattern{
+						"User:alice": {
+							"ClusterAdmin": {},
+							"DeveloperRead": {
+								{
+									ResourceType: "Topic",
+									Name:         "orders-",
+									PatternType:  "PREFIXED",
+								},
+								{
+									ResourceType: "Subject",
+									Name:         "orders-value",
+									PatternType:  "LITERAL",
+								},
+							},
+							"Operator": {
+								{
+									ResourceType: "Cluster",
+									Name:         "kafka-cluster",
+									PatternType:  "LITERAL",
+								},
+							},
+						},
+					},
+				)
+			}
+		}),
+	)
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := NewPlatformClient(
+		PlatformConfig{
+			URL:            server.URL,
+			Username:       "topicctl",
+			Password:       "test-password",
+			KafkaClusterID: "abc-123",
+		},
+	)
+	require.NoError(t, err)
+
+	bindings, err := client.GetRoleBindings(ctx, []string{"User:alice"})
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]RoleBinding{
+			{
+				Principal:    "User:alice",
+				Role:         "ClusterAdmin",
+				ResourceType: ResourceTypeCluster,
+			},
+			{
+				Principal:    "User:alice",
+				Role:         "DeveloperRead",
+				ResourceType: ResourceTypeTopic,
+				Name:         "orders-",
+				PatternType:  PatternTypePrefixed,
+			},
+			{
+				Principal:    "User:alice",
+				Role:         "Operator",
+				ResourceType: ResourceTypeCluster,
+			},
+		},
+		bindings,
+	)
+
+	err = client.CreateRoleBinding(
+		ctx,
+		RoleBinding{
+			Principal:    "User:alice",
+			Role:         "DeveloperWrite",
+			ResourceType: ResourceTypeTopic,
+			Name:         "orders",
+			PatternType:  PatternTypeLiteral,
+		},
+	)
+	require.NoError(t, err)
+
+	err = client.DeleteRoleBinding(
+		ctx,
+		RoleBinding{
+			Principal:    "User:alice",
+			Role:         "ClusterAdmin",
+			ResourceType: ResourceTypeCluster,
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]recordedRequest{
+			{
+				Method: http.MethodPost,
+				Path:   "/security/1.0/lookup/principal/User:alice/resources",
+				Body:   `{"clusters":{"kafka-cluster":"abc-123"}}`,
+			},
+			{
+				Method: http.MethodPost,
+				Path:   "/security/1.0/principals/User:alice/roles/DeveloperWrite/bindings",
+				Body:   `{"scope":{"clusters":{"kafka-cluster":"abc-123"}},"resourcePatterns":[{"resourceType":"Topic","name":"orders","patternType":"LITERAL"}]}`,
+			},
+			{
+				Method: http.MethodDelete,
+				Path:   "/security/1.0/principals/User:alice/roles/ClusterAdmin",
+				Body:   `{"clusters":{"kafka-cluster":"abc-123"}}`,
+			},
+		},
+		requests,
+	)
+
+	badClient, err := NewPlatformClient(
+		PlatformConfig{
+			URL:            server.URL,
+			Username:       "topicctl",
+			Password:       "wrong-password",
+			KafkaClusterID: "abc-123",
+		},
+	)
+	require.NoError(t, err)
+	_, err = badClient.GetRoleBindings(ctx, []string{"User:alice"})
+	assert.Error(t, err)
+}
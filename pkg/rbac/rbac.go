@@ -0,0 +1,96 @@
+// Package rbac manages Confluent RBAC role bindings via the REST APIs of Confluent Platform
+// (the metadata service) and Confluent Cloud. Unlike ACLs, role bindings aren't stored in the
+// Kafka cluster itself, so they can't be managed via the admin clients.
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+const (
+	// ResourceTypeCluster is the resource type of bindings on the Kafka cluster as a whole.
+	ResourceTypeCluster = "Cluster"
+
+	// ResourceTypeTopic is the resource type of bindings on topics.
+	ResourceTypeTopic = "Topic"
+
+	// ResourceTypeGroup is the resource type of bindings on consumer groups.
+	ResourceTypeGroup = "Group"
+
+	// ResourceTypeTransactionalID is the resource type of bindings on transactional IDs.
+	ResourceTypeTransactionalID = "TransactionalId"
+
+	// PatternTypeLiteral is the pattern type of bindings on a single, named resource.
+	PatternTypeLiteral = "LITERAL"
+
+	// PatternTypePrefixed is the pattern type of bindings on all resources whose names start
+	// with a prefix.
+	PatternTypePrefixed = "PREFIXED"
+)
+
+// AllResourceTypes contains all of the resource types that role bindings can be managed for.
+var AllResourceTypes = []string{
+	ResourceTypeCluster,
+	ResourceTypeTopic,
+	ResourceTypeGroup,
+	ResourceTypeTransactionalID,
+}
+
+// RoleBinding binds a role, e.g. DeveloperRead, to a principal, either on the Kafka cluster as a
+// whole or on the resources that match a pattern.
+type RoleBinding struct {
+	Principal    string `json:"principal"`
+	Role         string `json:"role"`
+	ResourceType string `json:"resourceType"`
+
+	// Name and PatternType are empty for cluster bindings.
+	Name        string `json:"name,omitempty"`
+	PatternType string `json:"patternType,omitempty"`
+}
+
+// IsClusterBinding returns whether the binding is on the Kafka cluster as a whole.
+func (b RoleBinding) IsClusterBinding() bool {
+	return b.ResourceType == ResourceTypeCluster
+}
+
+// Resource returns a description of the resource that the binding is on, e.g.
+// "Topic:PREFIXED:orders-".
+func (b RoleBinding) Resource() string {
+	if b.IsClusterBinding() {
+		return ResourceTypeCluster
+	}
+	return fmt.Sprintf("%s:%s:%s", b.ResourceType, b.PatternType, b.Name)
+}
+
+// String returns a description of the binding.
+func (b RoleBinding) String() string {
+	return fmt.Sprintf("%s %s on %s", b.Principal, b.Role, b.Resource())
+}
+
+// Client is an interface for getting and updating the role bindings in a single Kafka cluster.
+// Only bindings scoped to the cluster or its topics, groups, and transactional IDs are covered.
+type Client interface {
+	// GetRoleBindings gets the current bindings of the argument principals.
+	GetRoleBindings(ctx context.Context, principals []string) ([]RoleBinding, error)
+
+	// CreateRoleBinding creates the argument binding.
+	CreateRoleBinding(ctx context.Context, binding RoleBinding) error
+
+	// DeleteRoleBinding deletes the argument binding.
+	DeleteRoleBinding(ctx context.Context, binding RoleBinding) error
+}
+
+// SortRoleBindings sorts the argument bindings by principal, role, and then resource.
+func SortRoleBindings(bindings []RoleBinding) {
+	sort.Slice(bindings, func(a, b int) bool {
+		if bindings[a].Principal != bindings[b].Principal {
+			return bindings[a].Principal < bindings[b].Principal
+		}
+		if bindings[a].Role != bindings[b].Role {
+			return bindings[a].Role < bindings[b].Role
+		}
+		return bindings[a].Resource() < bindings[b].Resource()
+	})
+}
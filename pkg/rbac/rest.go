@@ -0,0 +1,126 @@
+package rbac
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const restTimeout = 30 * time.Second
+
+// restClient sends JSON requests with basic auth to one of the Confluent REST APIs.
+type restClient struct {
+	baseURL    string
+	username   string
+	password   string
+	service    string
+	httpClient *http.Client
+}
+
+func newRESTClient(
+	service string,
+	baseURL string,
+	username string,
+	password string,
+	caCertPath string,
+	skipVerify bool,
+) (restClient, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if caCertPath != "" || skipVerify {
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: skipVerify,
+		}
+
+		if caCertPath != "" {
+			log.Debugf("Adding CA certs from %s", caCertPath)
+			caCertContents, err := ioutil.ReadFile(caCertPath)
+			if err != nil {
+				return restClient{}, err
+			}
+			caCertPool := x509.NewCertPool()
+			if ok := caCertPool.AppendCertsFromPEM(caCertContents); !ok {
+				return restClient{}, fmt.Errorf("Could not append CA certs from %s", caCertPath)
+			}
+			tlsConfig.RootCAs = caCertPool
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return restClient{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		password: password,
+		service:  service,
+		httpClient: &http.Client{
+			Timeout:   restTimeout,
+			Transport: transport,
+		},
+	}, nil
+}
+
+func (r restClient) request(
+	ctx context.Context,
+	method string,
+	path string,
+	payload interface{},
+	result interface{},
+) error {
+	var reqBody *bytes.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(payloadBytes)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(r.username, r.password)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Debugf("Sending %s request: %s %s", r.service, method, path)
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf(
+			"Got status %d from %s for %s %s: %s",
+			resp.StatusCode,
+			r.service,
+			method,
+			path,
+			strings.TrimSpace(string(body)),
+		)
+	}
+
+	if result != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("Error parsing %s response for %s: %+v", r.service, path, err)
+		}
+	}
+	return nil
+}
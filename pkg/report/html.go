@@ -0,0 +1,196 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/util"
+)
+
+// OutputFormat is a string type that identifies the output format of a report.
+type OutputFormat string
+
+const (
+	// OutputFormatHTML renders the report as a standalone HTML page. This is the default.
+	OutputFormatHTML OutputFormat = "html"
+
+	// OutputFormatJSON renders the report as a JSON object, e.g. for further processing.
+	OutputFormatJSON OutputFormat = "json"
+)
+
+// AllOutputFormats are all of the supported report formats.
+var AllOutputFormats = []OutputFormat{
+	OutputFormatHTML,
+	OutputFormatJSON,
+}
+
+// ParseOutputFormat converts the argument string to an OutputFormat.
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	for _, validFormat := range AllOutputFormats {
+		if OutputFormat(format) == validFormat {
+			return validFormat, nil
+		}
+	}
+	return "", fmt.Errorf(
+		"Unrecognized report format %s; must be one of %+v",
+		format,
+		AllOutputFormats,
+	)
+}
+
+// Render renders the argument report in the argument format.
+func Render(report Report, format OutputFormat) (string, error) {
+	switch format {
+	case OutputFormatHTML:
+		return FormatHTML(report)
+	case OutputFormatJSON:
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(jsonBytes) + "\n", nil
+	default:
+		return "", fmt.Errorf("Unsupported report format: %s", format)
+	}
+}
+
+// FormatHTML renders the argument report as a self-contained HTML page, with inline styles and no
+// external resources, so that it can be shared as a single file.
+func FormatHTML(report Report) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := htmlTemplate.Execute(buf, report); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var htmlTemplate = template.Must(
+	template.New("report").Funcs(
+		template.FuncMap{
+			"bytes": util.PrettyBytes,
+			"floatBytes": func(value float64) string {
+				return util.PrettyBytes(int64(value))
+			},
+			"score": func(score float64) string {
+				return fmt.Sprintf("%.1f", score)
+			},
+			"time": func(t time.Time) string {
+				return t.Format(time.RFC1123)
+			},
+		},
+	).Parse(htmlTemplateContents),
+)
+
+const htmlTemplateContents = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>topicctl report: {{.Cluster.Name}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { margin-bottom: 0.2em; }
+h2 { margin-top: 1.8em; border-bottom: 1px solid #ddd; padding-bottom: 0.2em; }
+.meta { color: #666; }
+.cards { display: flex; flex-wrap: wrap; gap: 1em; margin: 1.5em 0; }
+.card { border: 1px solid #ddd; border-radius: 6px; padding: 0.8em 1.2em; min-width: 8em; }
+.card .value { font-size: 1.8em; font-weight: bold; }
+.card .label { color: #666; font-size: 0.9em; }
+table { border-collapse: collapse; margin-top: 0.5em; }
+th, td { border: 1px solid #ddd; padding: 0.3em 0.7em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+ul { margin: 0; padding-left: 1.2em; }
+.ok { color: #1a7f37; }
+.bad { color: #cf222e; font-weight: bold; }
+.warn { color: #9a6700; font-weight: bold; }
+.muted { color: #888; }
+</style>
+</head>
+<body>
+<h1>Cluster {{.Cluster.Name}}</h1>
+<div class="meta">
+Environment: {{.Cluster.Environment}} &middot; Region: {{.Cluster.Region}}{{if .Cluster.Description}} &middot; {{.Cluster.Description}}{{end}}<br>
+Generated {{time .GeneratedAt}} by topicctl v{{.Version}}
+</div>
+
+<div class="cards">
+<div class="card"><div class="value">{{.Cluster.Brokers}}</div><div class="label">brokers in {{.Cluster.Racks}} rack(s)</div></div>
+<div class="card"><div class="value">{{len .Topics}}</div><div class="label">topics ({{.ManagedTopics}} managed)</div></div>
+<div class="card"><div class="value{{if .DriftedTopics}} bad{{end}}">{{.DriftedTopics}}</div><div class="label">drifted topics</div></div>
+<div class="card"><div class="value{{if .FailedChecks}} bad{{end}}">{{.FailedChecks}}</div><div class="label">failed checks</div></div>
+<div class="card"><div class="value">{{score .Balance.Score}}</div><div class="label">balance score</div></div>
+</div>
+
+<h2>Drift</h2>
+{{if .Drift}}
+<table>
+<tr><th>Topic</th><th>Status</th><th>Changes</th></tr>
+{{range .Drift}}
+<tr>
+<td>{{.Topic}}</td>
+{{if .Error}}<td class="bad">error</td><td>{{.Error}}</td>
+{{else if .Drifted}}<td class="bad">drifted</td><td><ul>{{range .Changes}}<li>{{.}}</li>{{end}}</ul></td>
+{{else}}<td class="ok">in sync</td><td class="muted">none</td>
+{{end}}
+</tr>
+{{end}}
+</table>
+{{else}}
+<p class="muted">No managed topics.</p>
+{{end}}
+
+<h2>Checks</h2>
+{{if .Checks}}
+<table>
+<tr><th>Topic</th><th>Status</th><th>Failures and warnings</th></tr>
+{{range .Checks}}
+<tr>
+<td>{{.Topic}}</td>
+{{if .Error}}<td class="bad">error</td><td>{{.Error}}</td>
+{{else if and .OK .HasWarnings}}<td class="warn">warning</td><td><ul>{{range .Results}}{{if .Warning}}<li>{{.Name}}: {{.Description}}</li>{{end}}{{end}}</ul></td>
+{{else if .OK}}<td class="ok">OK</td><td class="muted">none</td>
+{{else}}<td class="bad">failed</td><td><ul>{{range .Results}}{{if not .OK}}<li>{{.Name}}: {{.Description}}{{if .Warning}} (warning){{end}}</li>{{end}}{{end}}</ul></td>
+{{end}}
+</tr>
+{{end}}
+</table>
+{{else}}
+<p class="muted">No managed topics.</p>
+{{end}}
+
+<h2>Balance</h2>
+<table>
+<tr><th>Metric</th><th>Min</th><th>Max</th><th>Mean</th><th>Score</th></tr>
+<tr><td>Replicas</td><td>{{.Balance.ReplicaSkew.Min}}</td><td>{{.Balance.ReplicaSkew.Max}}</td><td>{{score .Balance.ReplicaSkew.Mean}}</td><td>{{score .Balance.ReplicaSkew.Score}}</td></tr>
+<tr><td>Leaders</td><td>{{.Balance.LeaderSkew.Min}}</td><td>{{.Balance.LeaderSkew.Max}}</td><td>{{score .Balance.LeaderSkew.Mean}}</td><td>{{score .Balance.LeaderSkew.Score}}</td></tr>
+{{if .Balance.HasSizes}}<tr><td>Bytes</td><td>{{bytes .Balance.BytesSkew.Min}}</td><td>{{bytes .Balance.BytesSkew.Max}}</td><td>{{floatBytes .Balance.BytesSkew.Mean}}</td><td>{{score .Balance.BytesSkew.Score}}</td></tr>{{end}}
+</table>
+
+<table>
+<tr><th>Broker</th><th>Rack</th><th>Replicas</th><th>Leaders</th>{{if .Balance.HasSizes}}<th>Size</th>{{end}}</tr>
+{{$hasSizes := .Balance.HasSizes}}
+{{range .Balance.Brokers}}
+<tr><td>{{.BrokerID}}</td><td>{{.Rack}}</td><td>{{.Replicas}}</td><td>{{.Leaders}}</td>{{if $hasSizes}}<td>{{bytes .Bytes}}</td>{{end}}</tr>
+{{end}}
+</table>
+
+<h2>Topics</h2>
+<table>
+<tr><th>Name</th><th>Managed</th><th>Partitions</th><th>Replication</th><th>Retention</th><th>Under-replicated</th><th>Wrong leaders</th></tr>
+{{range .Topics}}
+<tr>
+<td>{{.Name}}</td>
+<td>{{if .Managed}}yes{{else}}<span class="muted">no</span>{{end}}</td>
+<td>{{.Partitions}}</td>
+<td>{{.Replication}}</td>
+<td>{{.Retention}}</td>
+<td{{if .UnderReplicated}} class="bad"{{end}}>{{.UnderReplicated}}</td>
+<td{{if .WrongLeaders}} class="bad"{{end}}>{{.WrongLeaders}}</td>
+</tr>
+{{end}}
+</table>
+</body>
+</html>
+`
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatHTML(t *testing.T) {
+	report := Report{
+		GeneratedAt: time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC),
+		Version:     "1.0.0",
+		Cluster: ClusterSummary{
+			Name:        "test-cluster",
+			Environment: "test-env",
+			Region:      "test-region",
+			Brokers:     3,
+			Racks:       3,
+		},
+		Topics: []TopicSummary{
+			{
+				Name:        "topic-drifted",
+				Managed:     true,
+				Partitions:  3,
+				Replication: 2,
+			},
+			{
+				Name:            "topic-<unmanaged>",
+				Partitions:      1,
+				Replication:     1,
+				UnderReplicated: 1,
+			},
+		},
+		Balance: admin.ClusterBalance{
+			Brokers: []admin.BrokerLoad{
+				{BrokerID: 1, Rack: "rack1", Replicas: 4, Leaders: 2},
+			},
+		},
+		Drift: []TopicDrift{
+			{
+				Topic:   "topic-drifted",
+				Changes: []string{"Update partitions: 2 -> 3"},
+			},
+		},
+		Checks: []TopicChecks{
+			{
+				Topic: "topic-drifted",
+				Results: []CheckResult{
+					{Name: "topic exists", OK: true},
+					{Name: "partition count correct", OK: false, Description: "2 != 3"},
+				},
+			},
+			{
+				Topic: "topic-deprecated",
+				OK:    true,
+				Results: []CheckResult{
+					{Name: "topic exists", OK: true},
+					{
+						Name:        "deprecation not expired",
+						OK:          false,
+						Warning:     true,
+						Description: "past removal date",
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, 1, report.ManagedTopics())
+	assert.Equal(t, 1, report.DriftedTopics())
+	assert.Equal(t, 1, report.FailedChecks())
+
+	contents, err := FormatHTML(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, contents, "<title>topicctl report: test-cluster</title>")
+	assert.Contains(t, contents, "<li>Update partitions: 2 -&gt; 3</li>")
+	assert.Contains(t, contents, "<li>partition count correct: 2 != 3</li>")
+	assert.Contains(t, contents, `<td class="warn">warning</td>`)
+	assert.Contains(t, contents, "<li>deprecation not expired: past removal date</li>")
+	assert.NotContains(t, contents, "topic exists:")
+	assert.Contains(t, contents, "topic-&lt;unmanaged&gt;")
+	assert.NotContains(t, contents, "<script")
+	assert.NotContains(t, contents, "http")
+}
+
+func TestRender(t *testing.T) {
+	format, err := ParseOutputFormat("json")
+	require.NoError(t, err)
+	assert.Equal(t, OutputFormatJSON, format)
+
+	_, err = ParseOutputFormat("pdf")
+	assert.Error(t, err)
+
+	contents, err := Render(
+		Report{Cluster: ClusterSummary{Name: "test-cluster"}},
+		OutputFormatJSON,
+	)
+	require.NoError(t, err)
+	assert.Contains(t, contents, `"name": "test-cluster"`)
+
+	contents, err = Render(
+		Report{Cluster: ClusterSummary{Name: "test-cluster"}},
+		OutputFormatHTML,
+	)
+	require.NoError(t, err)
+	assert.Contains(t, contents, "<h1>Cluster test-cluster</h1>")
+}
@@ -0,0 +1,306 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/segmentio/topicctl/pkg/admin"
+	"github.com/segmentio/topicctl/pkg/apply"
+	"github.com/segmentio/topicctl/pkg/check"
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/segmentio/topicctl/pkg/util"
+	"github.com/segmentio/topicctl/pkg/version"
+	log "github.com/sirupsen/logrus"
+)
+
+// Report summarizes the state of a cluster and its managed topics.
+type Report struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Version     string         `json:"version"`
+	Cluster     ClusterSummary `json:"cluster"`
+	Topics      []TopicSummary `json:"topics"`
+
+	// Balance is the balance of all of the topics in the cluster across its brokers.
+	Balance admin.ClusterBalance `json:"balance"`
+
+	// Drift and Checks contain an entry for each managed topic.
+	Drift  []TopicDrift  `json:"drift"`
+	Checks []TopicChecks `json:"checks"`
+}
+
+// ClusterSummary contains the details of the cluster that a report is for.
+type ClusterSummary struct {
+	Name        string `json:"name"`
+	Environment string `json:"environment"`
+	Region      string `json:"region"`
+	Description string `json:"description"`
+	Brokers     int    `json:"brokers"`
+	Racks       int    `json:"racks"`
+}
+
+// TopicSummary contains the details of a single topic in the cluster.
+type TopicSummary struct {
+	Name        string `json:"name"`
+	Managed     bool   `json:"managed"`
+	Partitions  int    `json:"partitions"`
+	Replication int    `json:"replication"`
+	Retention   string `json:"retention"`
+
+	// UnderReplicated is the number of partitions with out-of-sync replicas and WrongLeaders is
+	// the number whose leaders aren't their preferred ones.
+	UnderReplicated int `json:"underReplicated"`
+	WrongLeaders    int `json:"wrongLeaders"`
+}
+
+// TopicDrift contains the changes that an apply would make to a managed topic.
+type TopicDrift struct {
+	Topic   string   `json:"topic"`
+	Changes []string `json:"changes"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Drifted returns whether the topic differs from its config.
+func (t TopicDrift) Drifted() bool {
+	return len(t.Changes) > 0
+}
+
+// TopicChecks contains the check results for a managed topic.
+type TopicChecks struct {
+	Topic   string        `json:"topic"`
+	OK      bool          `json:"ok"`
+	Results []CheckResult `json:"results"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// CheckResult is the outcome of a single check of a topic. Warning is set for results that
+// aren't OK but don't fail the topic's checks.
+type CheckResult struct {
+	Name        string `json:"name"`
+	OK          bool   `json:"ok"`
+	Warning     bool   `json:"warning,omitempty"`
+	Description string `json:"description"`
+}
+
+// HasWarnings returns whether any of the topic's check results are warnings.
+func (t TopicChecks) HasWarnings() bool {
+	for _, result := range t.Results {
+		if result.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+// DriftedTopics returns the number of managed topics that differ from their configs.
+func (r Report) DriftedTopics() int {
+	var count int
+	for _, drift := range r.Drift {
+		if drift.Drifted() || drift.Error != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// FailedChecks returns the number of managed topics that failed their checks.
+func (r Report) FailedChecks() int {
+	var count int
+	for _, checks := range r.Checks {
+		if !checks.OK {
+			count++
+		}
+	}
+	return count
+}
+
+// ManagedTopics returns the number of topics in the cluster that have configs.
+func (r Report) ManagedTopics() int {
+	var count int
+	for _, topic := range r.Topics {
+		if topic.Managed {
+			count++
+		}
+	}
+	return count
+}
+
+// BuildConfig contains the inputs for building a report.
+type BuildConfig struct {
+	AdminClient   admin.Client
+	ClusterConfig config.ClusterConfig
+
+	// TopicConfigs are the configs of the managed topics in the cluster.
+	TopicConfigs []config.TopicConfig
+
+	// CheckLeaders is set if the checks should include whether the topics' leaders are correct.
+	CheckLeaders bool
+}
+
+// Build generates a report for the cluster and topics in the argument config. Errors getting
+// the drift or check results of individual topics are included in the report instead of
+// returned.
+func Build(ctx context.Context, buildConfig BuildConfig) (Report, error) {
+	clusterConfig := buildConfig.ClusterConfig
+	adminClient := buildConfig.AdminClient
+
+	brokers, err := adminClient.GetBrokers(ctx, nil)
+	if err != nil {
+		return Report{}, err
+	}
+	topics, err := adminClient.GetTopics(ctx, nil, true)
+	if err != nil {
+		return Report{}, err
+	}
+
+	topicNames := []string{}
+	for _, topic := range topics {
+		topicNames = append(topicNames, topic.Name)
+	}
+
+	// Sizes aren't critical, so don't fail if the brokers don't support getting them
+	replicaLogDirs, err := adminClient.GetReplicaLogDirs(ctx, topicNames, nil)
+	if err != nil {
+		log.Warnf("Could not get replica sizes: %+v", err)
+		replicaLogDirs = nil
+	}
+
+	racks := map[string]struct{}{}
+	for _, broker := range brokers {
+		racks[broker.Rack] = struct{}{}
+	}
+
+	report := Report{
+		GeneratedAt: time.Now().UTC(),
+		Version:     version.Version,
+		Cluster: ClusterSummary{
+			Name:        clusterConfig.Meta.Name,
+			Environment: clusterConfig.Meta.Environment,
+			Region:      clusterConfig.Meta.Region,
+			Description: clusterConfig.Meta.Description,
+			Brokers:     len(brokers),
+			Racks:       len(racks),
+		},
+		Topics:  []TopicSummary{},
+		Balance: admin.GetClusterBalance(brokers, topics, replicaLogDirs),
+		Drift:   []TopicDrift{},
+		Checks:  []TopicChecks{},
+	}
+
+	managedTopics := map[string]struct{}{}
+	for _, topicConfig := range buildConfig.TopicConfigs {
+		managedTopics[topicConfig.Meta.Name] = struct{}{}
+	}
+
+	for _, topic := range topics {
+		summary := TopicSummary{
+			Name:            topic.Name,
+			Partitions:      len(topic.Partitions),
+			Replication:     topic.MaxReplication(),
+			UnderReplicated: len(topic.OutOfSyncPartitions(nil)),
+			WrongLeaders:    len(topic.WrongLeaderPartitions(nil)),
+		}
+		if _, ok := managedTopics[topic.Name]; ok {
+			summary.Managed = true
+		}
+		if retention := topic.Retention(); retention > 0 {
+			summary.Retention = util.PrettyDuration(retention)
+		}
+		report.Topics = append(report.Topics, summary)
+	}
+	sort.Slice(report.Topics, func(a, b int) bool {
+		return report.Topics[a].Name < report.Topics[b].Name
+	})
+
+	for _, topicConfig := range buildConfig.TopicConfigs {
+		report.Drift = append(report.Drift, topicDrift(ctx, buildConfig, topicConfig))
+
+		topicConfig.SetDefaults()
+		report.Checks = append(report.Checks, topicChecks(ctx, buildConfig, topicConfig))
+	}
+
+	return report, nil
+}
+
+func topicDrift(
+	ctx context.Context,
+	buildConfig BuildConfig,
+	topicConfig config.TopicConfig,
+) TopicDrift {
+	drift := TopicDrift{
+		Topic:   topicConfig.Meta.Name,
+		Changes: []string{},
+	}
+
+	if err := topicConfig.ResolveSettingsProfile(buildConfig.ClusterConfig); err != nil {
+		drift.Error = fmt.Sprintf("%+v", err)
+		return drift
+	}
+	topicConfig.SetDefaults()
+
+	applier, err := apply.NewTopicApplier(
+		ctx,
+		buildConfig.AdminClient,
+		apply.TopicApplierConfig{
+			ClusterConfig: buildConfig.ClusterConfig,
+			DryRun:        true,
+			TopicConfig:   topicConfig,
+		},
+	)
+	if err != nil {
+		drift.Error = fmt.Sprintf("%+v", err)
+		return drift
+	}
+
+	diff, err := applier.Diff(ctx)
+	if err != nil {
+		drift.Error = fmt.Sprintf("%+v", err)
+		return drift
+	}
+
+	if diff.HasDiffs() {
+		drift.Changes = apply.DiffChanges(diff)
+	}
+	return drift
+}
+
+func topicChecks(
+	ctx context.Context,
+	buildConfig BuildConfig,
+	topicConfig config.TopicConfig,
+) TopicChecks {
+	checks := TopicChecks{
+		Topic:   topicConfig.Meta.Name,
+		Results: []CheckResult{},
+	}
+
+	results, err := check.CheckTopic(
+		ctx,
+		check.CheckConfig{
+			AdminClient:   buildConfig.AdminClient,
+			CheckLeaders:  buildConfig.CheckLeaders,
+			ClusterConfig: buildConfig.ClusterConfig,
+			NumRacks:      -1,
+			TopicConfig:   topicConfig,
+		},
+	)
+	if err != nil {
+		checks.Error = fmt.Sprintf("%+v", err)
+		return checks
+	}
+
+	checks.OK = results.AllOK()
+	for _, result := range results.Results {
+		checks.Results = append(
+			checks.Results,
+			CheckResult{
+				Name:        string(result.Name),
+				OK:          result.OK,
+				Warning:     result.Warning,
+				Description: result.Description,
+			},
+		)
+	}
+	return checks
+}
@@ -0,0 +1,27 @@
+package report
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/topicctl/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicDriftProfileError(t *testing.T) {
+	topicConfig := config.TopicConfig{
+		Meta: config.TopicMeta{
+			Name: "test-topic",
+		},
+		Spec: config.TopicSpec{
+			Profile: "unknown-profile",
+		},
+	}
+
+	// The profile is resolved before the cluster is contacted, so this doesn't need an admin
+	// client.
+	drift := topicDrift(context.Background(), BuildConfig{}, topicConfig)
+	assert.Equal(t, "test-topic", drift.Topic)
+	assert.False(t, drift.Drifted())
+	assert.Contains(t, drift.Error, "Unknown settings profile 'unknown-profile'")
+}
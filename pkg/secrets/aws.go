@@ -0,0 +1,179 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+)
+
+// awsSessions lazily creates the AWS session that's shared by the AWS backends, so that AWS
+// credentials are only looked up if a secret in AWS is actually referenced.
+type awsSessions struct {
+	once sync.Once
+	sess *session.Session
+	err  error
+}
+
+func (a *awsSessions) get() (*session.Session, error) {
+	a.once.Do(func() {
+		a.sess, a.err = session.NewSessionWithOptions(
+			session.Options{
+				SharedConfigState: session.SharedConfigEnable,
+			},
+		)
+	})
+	return a.sess, a.err
+}
+
+var sharedAWSSessions = &awsSessions{}
+
+// regionFromPath returns the region of the argument secret or parameter path if it's an ARN, or
+// an empty string otherwise.
+func regionFromPath(path string) string {
+	if !arn.IsARN(path) {
+		return ""
+	}
+	parsed, err := arn.Parse(path)
+	if err != nil {
+		return ""
+	}
+	return parsed.Region
+}
+
+func awsConfig(path string) *aws.Config {
+	config := aws.NewConfig()
+	if region := regionFromPath(path); region != "" {
+		config = config.WithRegion(region)
+	}
+	return config
+}
+
+// AWSSecretsManagerBackend is a Backend that reads secrets from AWS Secrets Manager. Paths are
+// secret names or ARNs. Secrets whose values are JSON objects, e.g. the ones created via the
+// console's key/value editor, also have a field for each key in the object.
+type AWSSecretsManagerBackend struct {
+	newClient func(path string) (secretsmanageriface.SecretsManagerAPI, error)
+}
+
+var _ Backend = (*AWSSecretsManagerBackend)(nil)
+
+// NewAWSSecretsManagerBackend returns a new AWSSecretsManagerBackend that gets its credentials
+// and default region from the environment.
+func NewAWSSecretsManagerBackend() *AWSSecretsManagerBackend {
+	return &AWSSecretsManagerBackend{
+		newClient: func(path string) (secretsmanageriface.SecretsManagerAPI, error) {
+			sess, err := sharedAWSSessions.get()
+			if err != nil {
+				return nil, err
+			}
+			return secretsmanager.New(sess, awsConfig(path)), nil
+		},
+	}
+}
+
+// Get returns the value of the secret with the argument name or ARN.
+func (a *AWSSecretsManagerBackend) Get(
+	ctx context.Context,
+	path string,
+) (map[string]string, error) {
+	client, err := a.newClient(path)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetSecretValueWithContext(
+		ctx,
+		&secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(path),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var value string
+	if output.SecretString != nil {
+		value = aws.StringValue(output.SecretString)
+	} else {
+		value = string(output.SecretBinary)
+	}
+
+	fields := map[string]string{"": value}
+
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		jsonFields := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(value), &jsonFields); err == nil {
+			for key, jsonValue := range jsonFields {
+				switch typedValue := jsonValue.(type) {
+				case string:
+					fields[key] = typedValue
+				default:
+					valueBytes, err := json.Marshal(typedValue)
+					if err != nil {
+						return nil, err
+					}
+					fields[key] = string(valueBytes)
+				}
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// SSMBackend is a Backend that reads parameters from AWS Systems Manager Parameter Store. Paths
+// are parameter names, e.g. /kafka/admin/password, or ARNs. SecureString parameters are
+// decrypted.
+type SSMBackend struct {
+	newClient func(path string) (ssmiface.SSMAPI, error)
+}
+
+var _ Backend = (*SSMBackend)(nil)
+
+// NewSSMBackend returns a new SSMBackend that gets its credentials and default region from the
+// environment.
+func NewSSMBackend() *SSMBackend {
+	return &SSMBackend{
+		newClient: func(path string) (ssmiface.SSMAPI, error) {
+			sess, err := sharedAWSSessions.get()
+			if err != nil {
+				return nil, err
+			}
+			return ssm.New(sess, awsConfig(path)), nil
+		},
+	}
+}
+
+// Get returns the value of the parameter with the argument name or ARN.
+func (s *SSMBackend) Get(ctx context.Context, path string) (map[string]string, error) {
+	client, err := s.newClient(path)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetParameterWithContext(
+		ctx,
+		&ssm.GetParameterInput{
+			Name:           aws.String(path),
+			WithDecryption: aws.Bool(true),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if output.Parameter == nil {
+		return nil, errors.New("SSM response does not have a parameter")
+	}
+
+	return map[string]string{"": aws.StringValue(output.Parameter.Value)}, nil
+}
@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSecretsManagerClient struct {
+	secretsmanageriface.SecretsManagerAPI
+
+	values map[string]string
+}
+
+func (f *fakeSecretsManagerClient) GetSecretValueWithContext(
+	ctx aws.Context,
+	input *secretsmanager.GetSecretValueInput,
+	options ...request.Option,
+) (*secretsmanager.GetSecretValueOutput, error) {
+	value, ok := f.values[aws.StringValue(input.SecretId)]
+	if !ok {
+		return nil, errors.New("ResourceNotFoundException")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil
+}
+
+type fakeSSMClient struct {
+	ssmiface.SSMAPI
+
+	values map[string]string
+}
+
+func (f *fakeSSMClient) GetParameterWithContext(
+	ctx aws.Context,
+	input *ssm.GetParameterInput,
+	options ...request.Option,
+) (*ssm.GetParameterOutput, error) {
+	if !aws.BoolValue(input.WithDecryption) {
+		return nil, errors.New("Parameter must be decrypted")
+	}
+	value, ok := f.values[aws.StringValue(input.Name)]
+	if !ok {
+		return nil, errors.New("ParameterNotFound")
+	}
+	return &ssm.GetParameterOutput{
+		Parameter: &ssm.Parameter{
+			Name:  input.Name,
+			Value: aws.String(value),
+		},
+	}, nil
+}
+
+func TestAWSSecretsManagerBackendGet(t *testing.T) {
+	ctx := context.Background()
+
+	client := &fakeSecretsManagerClient{
+		values: map[string]string{
+			"kafka/admin": `{"username": "admin", "password": "admin-password"}`,
+			"kafka/tail":  "tail-password",
+		},
+	}
+	backend := &AWSSecretsManagerBackend{
+		newClient: func(path string) (secretsmanageriface.SecretsManagerAPI, error) {
+			return client, nil
+		},
+	}
+
+	fields, err := backend.Get(ctx, "kafka/admin")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		map[string]string{
+			"":         `{"username": "admin", "password": "admin-password"}`,
+			"username": "admin",
+			"password": "admin-password",
+		},
+		fields,
+	)
+
+	fields, err = backend.Get(ctx, "kafka/tail")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"": "tail-password"}, fields)
+
+	_, err = backend.Get(ctx, "kafka/missing")
+	assert.Error(t, err)
+}
+
+func TestSSMBackendGet(t *testing.T) {
+	ctx := context.Background()
+
+	client := &fakeSSMClient{
+		values: map[string]string{
+			"/kafka/admin/password": "ssm-password",
+		},
+	}
+	backend := &SSMBackend{
+		newClient: func(path string) (ssmiface.SSMAPI, error) {
+			return client, nil
+		},
+	}
+
+	fields, err := backend.Get(ctx, "/kafka/admin/password")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"": "ssm-password"}, fields)
+
+	_, err = backend.Get(ctx, "/kafka/admin/missing")
+	assert.Error(t, err)
+}
+
+func TestRegionFromPath(t *testing.T) {
+	assert.Equal(
+		t,
+		"us-west-2",
+		regionFromPath("arn:aws:secretsmanager:us-west-2:123456789012:secret:kafka-admin"),
+	)
+	assert.Equal(t, "", regionFromPath("/kafka/admin/password"))
+}
@@ -0,0 +1,220 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SchemeVault is the URI scheme for secrets in HashiCorp Vault, e.g.
+	// vault://secret/data/kafka/admin#password.
+	SchemeVault = "vault"
+
+	// SchemeAWSSecretsManager is the URI scheme for secrets in AWS Secrets Manager, e.g.
+	// aws-sm://kafka/admin#password.
+	SchemeAWSSecretsManager = "aws-sm"
+
+	// SchemeSSM is the URI scheme for AWS Systems Manager parameters, e.g.
+	// ssm:///kafka/admin/password.
+	SchemeSSM = "ssm"
+
+	// DefaultCacheTTL is how long resolved secrets are cached for by the default resolver. It's
+	// short enough that long-running commands, e.g. the exporter, pick up rotated secrets.
+	DefaultCacheTTL = 5 * time.Minute
+)
+
+// AllSchemes are all of the supported secret URI schemes.
+var AllSchemes = []string{
+	SchemeAWSSecretsManager,
+	SchemeSSM,
+	SchemeVault,
+}
+
+// Ref is a parsed secret URI.
+type Ref struct {
+	// Scheme selects the store that the secret is in.
+	Scheme string
+
+	// Path identifies the secret in the store, e.g. a Vault API path or an AWS secret name.
+	Path string
+
+	// Key is the field of the secret to use, for secrets that have more than one. It's set via
+	// the URI fragment.
+	Key string
+}
+
+// ParseURI parses a secret URI in the format [scheme]://[path]#[key]. The key is optional. The
+// path is used as-is, so it can contain characters, e.g. the colons in ARNs, that aren't valid in
+// standard URLs.
+func ParseURI(uri string) (Ref, error) {
+	elements := strings.SplitN(uri, "://", 2)
+	if len(elements) != 2 {
+		return Ref{}, fmt.Errorf(
+			"Secret URI %s is not in the format [scheme]://[path]#[key]",
+			uri,
+		)
+	}
+
+	ref := Ref{
+		Scheme: elements[0],
+		Path:   elements[1],
+	}
+	if index := strings.LastIndex(ref.Path, "#"); index >= 0 {
+		ref.Key = ref.Path[index+1:]
+		ref.Path = ref.Path[:index]
+	}
+
+	if !isValidScheme(ref.Scheme) {
+		return Ref{}, fmt.Errorf(
+			"Scheme of secret URI %s must be in %+v",
+			uri,
+			AllSchemes,
+		)
+	}
+	if ref.Path == "" {
+		return Ref{}, fmt.Errorf("Secret URI %s does not have a path", uri)
+	}
+
+	return ref, nil
+}
+
+// String returns the URI of the ref.
+func (r Ref) String() string {
+	if r.Key == "" {
+		return fmt.Sprintf("%s://%s", r.Scheme, r.Path)
+	}
+	return fmt.Sprintf("%s://%s#%s", r.Scheme, r.Path, r.Key)
+}
+
+// Backend fetches secrets from a single kind of store.
+type Backend interface {
+	// Get returns the fields of the secret at the argument path. Stores that hold plain values
+	// return them under the empty key.
+	Get(ctx context.Context, path string) (map[string]string, error)
+}
+
+type cacheEntry struct {
+	fields    map[string]string
+	expiresAt time.Time
+}
+
+// Resolver resolves secret URIs via a set of backends, one per scheme. Secrets are cached by
+// path, so that references to different keys of the same secret only fetch it once.
+type Resolver struct {
+	backends map[string]Backend
+	ttl      time.Duration
+	now      func() time.Time
+
+	mutex sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewResolver returns a new Resolver that uses the argument backends, keyed by scheme, and that
+// caches the secrets that it fetches for the argument amount of time.
+func NewResolver(backends map[string]Backend, ttl time.Duration) *Resolver {
+	return &Resolver{
+		backends: backends,
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    map[string]cacheEntry{},
+	}
+}
+
+var defaultResolver = NewResolver(
+	map[string]Backend{
+		SchemeAWSSecretsManager: NewAWSSecretsManagerBackend(),
+		SchemeSSM:               NewSSMBackend(),
+		SchemeVault:             NewVaultBackendFromEnv(),
+	},
+	DefaultCacheTTL,
+)
+
+// Resolve gets the value of the secret that the argument URI references using the default
+// resolver, which configures each store from the environment in the same way as its CLI.
+func Resolve(ctx context.Context, uri string) (string, error) {
+	return defaultResolver.Resolve(ctx, uri)
+}
+
+// Resolve gets the value of the secret that the argument URI references.
+func (r *Resolver) Resolve(ctx context.Context, uri string) (string, error) {
+	ref, err := ParseURI(uri)
+	if err != nil {
+		return "", err
+	}
+
+	fields, err := r.get(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.Key != "" {
+		value, ok := fields[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("Secret %s does not have key %s", ref.Path, ref.Key)
+		}
+		return value, nil
+	}
+	if value, ok := fields[""]; ok {
+		return value, nil
+	}
+	if len(fields) == 1 {
+		for _, value := range fields {
+			return value, nil
+		}
+	}
+
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return "", fmt.Errorf(
+		"Secret %s has multiple keys (%+v); select one via %s#[key]",
+		ref.Path,
+		keys,
+		uri,
+	)
+}
+
+func (r *Resolver) get(ctx context.Context, ref Ref) (map[string]string, error) {
+	backend, ok := r.backends[ref.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("No backend configured for secret scheme %s", ref.Scheme)
+	}
+
+	cacheKey := Ref{Scheme: ref.Scheme, Path: ref.Path}.String()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if entry, ok := r.cache[cacheKey]; ok && r.now().Before(entry.expiresAt) {
+		log.Debugf("Using cached secret %s", cacheKey)
+		return entry.fields, nil
+	}
+
+	log.Debugf("Fetching secret %s", cacheKey)
+	fields, err := backend.Get(ctx, ref.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting secret %s: %+v", cacheKey, err)
+	}
+
+	r.cache[cacheKey] = cacheEntry{
+		fields:    fields,
+		expiresAt: r.now().Add(r.ttl),
+	}
+	return fields, nil
+}
+
+func isValidScheme(scheme string) bool {
+	for _, validScheme := range AllSchemes {
+		if scheme == validScheme {
+			return true
+		}
+	}
+	return false
+}
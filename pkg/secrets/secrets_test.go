@@ -0,0 +1,152 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBackend struct {
+	secrets map[string]map[string]string
+	gets    int
+}
+
+func (f *fakeBackend) Get(ctx context.Context, path string) (map[string]string, error) {
+	f.gets++
+	fields, ok := f.secrets[path]
+	if !ok {
+		return nil, errors.New("Secret not found")
+	}
+	return fields, nil
+}
+
+func TestParseURI(t *testing.T) {
+	type testCase struct {
+		uri      string
+		expRef   Ref
+		expError bool
+	}
+
+	testCases := []testCase{
+		{
+			uri: "vault://secret/data/kafka/admin#password",
+			expRef: Ref{
+				Scheme: SchemeVault,
+				Path:   "secret/data/kafka/admin",
+				Key:    "password",
+			},
+		},
+		{
+			uri: "aws-sm://arn:aws:secretsmanager:us-west-2:123456789012:secret:kafka-admin",
+			expRef: Ref{
+				Scheme: SchemeAWSSecretsManager,
+				Path:   "arn:aws:secretsmanager:us-west-2:123456789012:secret:kafka-admin",
+			},
+		},
+		{
+			uri: "ssm:///kafka/admin/password",
+			expRef: Ref{
+				Scheme: SchemeSSM,
+				Path:   "/kafka/admin/password",
+			},
+		},
+		{
+			uri:      "secret/data/kafka/admin",
+			expError: true,
+		},
+		{
+			uri:      "gcp-sm://projects/test/secrets/kafka-admin",
+			expError: true,
+		},
+		{
+			uri:      "vault://#password",
+			expError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		ref, err := ParseURI(testCase.uri)
+		if testCase.expError {
+			assert.Error(t, err, testCase.uri)
+		} else {
+			require.NoError(t, err, testCase.uri)
+			assert.Equal(t, testCase.expRef, ref, testCase.uri)
+			assert.Equal(t, testCase.uri, ref.String())
+		}
+	}
+}
+
+func TestResolverResolve(t *testing.T) {
+	ctx := context.Background()
+
+	vaultBackend := &fakeBackend{
+		secrets: map[string]map[string]string{
+			"secret/data/kafka/admin": {
+				"username": "admin",
+				"password": "admin-password",
+			},
+			"secret/data/kafka/tail": {
+				"password": "tail-password",
+			},
+		},
+	}
+	ssmBackend := &fakeBackend{
+		secrets: map[string]map[string]string{
+			"/kafka/admin/password": {
+				"": "ssm-password",
+			},
+		},
+	}
+
+	resolver := NewResolver(
+		map[string]Backend{
+			SchemeVault: vaultBackend,
+			SchemeSSM:   ssmBackend,
+		},
+		time.Minute,
+	)
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	resolver.now = func() time.Time { return now }
+
+	value, err := resolver.Resolve(ctx, "vault://secret/data/kafka/admin#password")
+	require.NoError(t, err)
+	assert.Equal(t, "admin-password", value)
+
+	// Other keys of the same secret come from the cache
+	value, err = resolver.Resolve(ctx, "vault://secret/data/kafka/admin#username")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", value)
+	assert.Equal(t, 1, vaultBackend.gets)
+
+	// The key can be omitted if the secret only has one
+	value, err = resolver.Resolve(ctx, "vault://secret/data/kafka/tail")
+	require.NoError(t, err)
+	assert.Equal(t, "tail-password", value)
+
+	_, err = resolver.Resolve(ctx, "vault://secret/data/kafka/admin")
+	assert.Error(t, err)
+	_, err = resolver.Resolve(ctx, "vault://secret/data/kafka/admin#token")
+	assert.Error(t, err)
+	_, err = resolver.Resolve(ctx, "vault://secret/data/kafka/missing#password")
+	assert.Error(t, err)
+
+	value, err = resolver.Resolve(ctx, "ssm:///kafka/admin/password")
+	require.NoError(t, err)
+	assert.Equal(t, "ssm-password", value)
+
+	// Secrets are fetched again once the cache entry expires
+	vaultBackend.secrets["secret/data/kafka/admin"]["password"] = "rotated-password"
+	now = now.Add(2 * time.Minute)
+
+	value, err = resolver.Resolve(ctx, "vault://secret/data/kafka/admin#password")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated-password", value)
+	assert.Equal(t, 4, vaultBackend.gets)
+
+	_, err = resolver.Resolve(ctx, "aws-sm://kafka/admin#password")
+	assert.Error(t, err)
+}
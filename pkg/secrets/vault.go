@@ -0,0 +1,214 @@
+package secrets
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultVaultAddr is the Vault address that's used if VAULT_ADDR isn't set. It matches the
+	// default of the vault CLI.
+	DefaultVaultAddr = "https://127.0.0.1:8200"
+
+	vaultTimeout = 30 * time.Second
+)
+
+// VaultConfig stores the settings for connecting to Vault.
+type VaultConfig struct {
+	// Addr is the base URL of the Vault server.
+	Addr string
+
+	// Token is the Vault token. If it's unset, the token in TokenPath is used instead.
+	Token string
+
+	// TokenPath is the path of a file that contains the token, e.g. the ~/.vault-token file that
+	// vault login writes.
+	TokenPath string
+
+	// Namespace is the Vault Enterprise namespace, if any.
+	Namespace string
+
+	// CACertPath is the path of the CA cert that's used to verify the server, if it's not signed
+	// by a public CA.
+	CACertPath string
+}
+
+// VaultBackend is a Backend that reads secrets from Vault via its HTTP API. Paths are API paths
+// without the v1 prefix, e.g. secret/data/kafka/admin for the kafka/admin secret in a KV v2
+// engine mounted at secret/; both KV v1 and v2 engines are supported.
+type VaultBackend struct {
+	config     VaultConfig
+	httpClient *http.Client
+}
+
+var _ Backend = (*VaultBackend)(nil)
+
+// NewVaultBackend returns a new VaultBackend for the argument config.
+func NewVaultBackend(config VaultConfig) *VaultBackend {
+	return &VaultBackend{
+		config: config,
+	}
+}
+
+// NewVaultBackendFromEnv returns a new VaultBackend that's configured via the same environment
+// variables as the vault CLI: VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE, and VAULT_CACERT.
+func NewVaultBackendFromEnv() *VaultBackend {
+	config := VaultConfig{
+		Addr:       os.Getenv("VAULT_ADDR"),
+		Token:      os.Getenv("VAULT_TOKEN"),
+		Namespace:  os.Getenv("VAULT_NAMESPACE"),
+		CACertPath: os.Getenv("VAULT_CACERT"),
+	}
+	if config.Addr == "" {
+		config.Addr = DefaultVaultAddr
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		config.TokenPath = filepath.Join(home, ".vault-token")
+	}
+
+	return NewVaultBackend(config)
+}
+
+type vaultResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []string               `json:"errors"`
+}
+
+// Get returns the fields of the secret at the argument API path.
+func (v *VaultBackend) Get(ctx context.Context, path string) (map[string]string, error) {
+	token, err := v.token()
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := v.getHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf(
+			"%s/v1/%s",
+			strings.TrimRight(v.config.Addr, "/"),
+			strings.TrimLeft(path, "/"),
+		),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.config.Namespace)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	vaultResp := vaultResponse{}
+	if err := json.Unmarshal(body, &vaultResp); err != nil {
+		return nil, fmt.Errorf(
+			"Error parsing Vault response (status %d): %+v",
+			resp.StatusCode,
+			err,
+		)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"Got status %d from Vault: %s",
+			resp.StatusCode,
+			strings.Join(vaultResp.Errors, "; "),
+		)
+	}
+
+	data := vaultResp.Data
+
+	// KV v2 engines nest the secret's fields under data.data, alongside its metadata
+	if nestedData, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nestedData
+		}
+	}
+	if len(data) == 0 {
+		return nil, errors.New("Vault secret has no data")
+	}
+
+	fields := map[string]string{}
+	for key, value := range data {
+		switch typedValue := value.(type) {
+		case string:
+			fields[key] = typedValue
+		default:
+			valueBytes, err := json.Marshal(typedValue)
+			if err != nil {
+				return nil, err
+			}
+			fields[key] = string(valueBytes)
+		}
+	}
+
+	return fields, nil
+}
+
+func (v *VaultBackend) token() (string, error) {
+	if v.config.Token != "" {
+		return v.config.Token, nil
+	}
+	if v.config.TokenPath != "" {
+		contents, err := ioutil.ReadFile(v.config.TokenPath)
+		if err == nil && strings.TrimSpace(string(contents)) != "" {
+			return strings.TrimSpace(string(contents)), nil
+		} else if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", errors.New("No Vault token found; set VAULT_TOKEN or run vault login")
+}
+
+func (v *VaultBackend) getHTTPClient() (*http.Client, error) {
+	if v.httpClient != nil {
+		return v.httpClient, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if v.config.CACertPath != "" {
+		log.Debugf("Adding Vault CA certs from %s", v.config.CACertPath)
+		caCertContents, err := ioutil.ReadFile(v.config.CACertPath)
+		if err != nil {
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM(caCertContents); !ok {
+			return nil, fmt.Errorf("Could not append CA certs from %s", v.config.CACertPath)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs: caCertPool,
+		}
+	}
+
+	v.httpClient = &http.Client{
+		Timeout:   vaultTimeout,
+		Transport: transport,
+	}
+	return v.httpClient, nil
+}
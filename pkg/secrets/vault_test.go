@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultBackendGet(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Vault-Token") != "test-token" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"errors": ["permission denied"]}`)
+				return
+			}
+			assert.Equal(t, "test-namespace", r.Header.Get("X-Vault-Namespace"))
+
+			switch r.URL.Path {
+			case "/v1/secret/data/kafka/admin":
+				fmt.Fprint(
+					w,
+					`{"data": {"data": {"password": "v2-password", "port": 9092}, "metadata": {"version": 3}}}`,
+				)
+			case "/v1/kv/kafka/admin":
+				fmt.Fprint(w, `{"data": {"password": "v1-password"}}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"errors": []}`)
+			}
+		}),
+	)
+	defer server.Close()
+
+	backend := NewVaultBackend(
+		VaultConfig{
+			Addr:      server.URL + "/",
+			Token:     "test-token",
+			Namespace: "test-namespace",
+		},
+	)
+
+	fields, err := backend.Get(ctx, "secret/data/kafka/admin")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"password": "v2-password", "port": "9092"}, fields)
+
+	fields, err = backend.Get(ctx, "/kv/kafka/admin")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"password": "v1-password"}, fields)
+
+	_, err = backend.Get(ctx, "secret/data/kafka/missing")
+	assert.Error(t, err)
+
+	// The token can also come from a file, as written by vault login
+	tokenPath := filepath.Join(t.TempDir(), ".vault-token")
+	require.NoError(t, ioutil.WriteFile(tokenPath, []byte("test-token\n"), 0600))
+
+	backend = NewVaultBackend(
+		VaultConfig{
+			Addr:      server.URL,
+			TokenPath: tokenPath,
+			Namespace: "test-namespace",
+		},
+	)
+	fields, err = backend.Get(ctx, "kv/kafka/admin")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"password": "v1-password"}, fields)
+
+	backend = NewVaultBackend(
+		VaultConfig{
+			Addr:      server.URL,
+			TokenPath: filepath.Join(t.TempDir(), ".vault-token"),
+		},
+	)
+	_, err = backend.Get(ctx, "kv/kafka/admin")
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"github.com/segmentio/topicctl/pkg/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName = "topicctl"
+	tracerName  = "github.com/segmentio/topicctl"
+)
+
+// endpointEnvVars are the standard OpenTelemetry environment variables that set the OTLP
+// endpoint; tracing is only enabled if one of these is set.
+var endpointEnvVars = []string{
+	"OTEL_EXPORTER_OTLP_ENDPOINT",
+	"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+}
+
+// Enabled returns whether an OTLP endpoint is configured in the environment.
+func Enabled() bool {
+	for _, envVar := range endpointEnvVars {
+		if os.Getenv(envVar) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Init sets up the global tracer provider to export spans via OTLP over HTTP if Enabled is
+// true. The exporter is configured with the standard OTEL_EXPORTER_OTLP_* environment
+// variables. The returned function flushes any pending spans and should be called before
+// exiting; it's a no-op if tracing isn't enabled.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	if !Enabled() {
+		return func(ctx context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(
+			resource.NewWithAttributes(
+				semconv.SchemaURL,
+				semconv.ServiceNameKey.String(serviceName),
+				semconv.ServiceVersionKey.String(version.Version),
+			),
+		),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span with the argument name and attributes as a child of the span in the
+// argument context, if any. If tracing isn't set up, the span is a no-op.
+func StartSpan(
+	ctx context.Context,
+	name string,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the argument error, if it's non-nil, in the argument span and then ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// wordRegexp matches either a word or a single separator character. Separators are whitespace
+// and the punctuation that typically delimits the elements of config values, e.g. the commas in
+// lists and the parentheses around time suffixes.
+var wordRegexp = regexp.MustCompile(`[^\s,;:=()\[\]{}]+|[\s,;:=()\[\]{}]`)
+
+// HighlightWordDiff returns the argument old and new values with the words that were removed
+// from old colored red and the ones that were added in new colored green, so that small changes
+// in long values stand out. If colors aren't enabled, the values are returned as-is.
+func HighlightWordDiff(old string, new string) (string, string) {
+	if !ColorEnabled() || old == new {
+		return old, new
+	}
+
+	oldWords := splitWords(old)
+	newWords := splitWords(new)
+	removed, added := diffWords(oldWords, newWords)
+
+	removedColor := color.New(color.FgRed)
+	removedColor.EnableColor()
+	addedColor := color.New(color.FgGreen)
+	addedColor.EnableColor()
+
+	return joinWords(oldWords, removed, removedColor), joinWords(newWords, added, addedColor)
+}
+
+func splitWords(value string) []string {
+	return wordRegexp.FindAllString(value, -1)
+}
+
+// diffWords compares the argument word lists via their longest common subsequence and returns,
+// for each word, whether it's only in old (removed) or only in new (added).
+func diffWords(oldWords []string, newWords []string) ([]bool, []bool) {
+	// lengths[i][j] is the length of the LCS of oldWords[i:] and newWords[j:]
+	lengths := make([][]int, len(oldWords)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(newWords)+1)
+	}
+	for i := len(oldWords) - 1; i >= 0; i-- {
+		for j := len(newWords) - 1; j >= 0; j-- {
+			if oldWords[i] == newWords[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	removed := make([]bool, len(oldWords))
+	added := make([]bool, len(newWords))
+
+	i, j := 0, 0
+	for i < len(oldWords) && j < len(newWords) {
+		if oldWords[i] == newWords[j] {
+			i++
+			j++
+		} else if lengths[i+1][j] >= lengths[i][j+1] {
+			removed[i] = true
+			i++
+		} else {
+			added[j] = true
+			j++
+		}
+	}
+	for ; i < len(oldWords); i++ {
+		removed[i] = true
+	}
+	for ; j < len(newWords); j++ {
+		added[j] = true
+	}
+
+	return removed, added
+}
+
+func joinWords(words []string, changed []bool, changedColor *color.Color) string {
+	elements := []string{}
+	for w, word := range words {
+		if changed[w] && strings.TrimSpace(word) != "" {
+			elements = append(elements, changedColor.Sprint(word))
+		} else {
+			elements = append(elements, word)
+		}
+	}
+	return strings.Join(elements, "")
+}
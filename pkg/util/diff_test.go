@@ -0,0 +1,52 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitWords(t *testing.T) {
+	assert.Equal(
+		t,
+		[]string{"86400000", " ", "(", "1440min", ")"},
+		splitWords("86400000 (1440min)"),
+	)
+	assert.Equal(
+		t,
+		[]string{"1", ":", "2", ",", "1", ":", "3"},
+		splitWords("1:2,1:3"),
+	)
+	assert.Equal(t, []string(nil), splitWords(""))
+}
+
+func TestDiffWords(t *testing.T) {
+	removed, added := diffWords(
+		splitWords("compact,delete"),
+		splitWords("delete"),
+	)
+	assert.Equal(t, []bool{true, true, false}, removed)
+	assert.Equal(t, []bool{false}, added)
+
+	removed, added = diffWords(
+		splitWords("86400000 (1440min)"),
+		splitWords("604800000 (10080min)"),
+	)
+	assert.Equal(t, []bool{true, false, false, true, false}, removed)
+	assert.Equal(t, []bool{true, false, false, true, false}, added)
+
+	removed, added = diffWords(
+		splitWords(""),
+		splitWords("a b"),
+	)
+	assert.Equal(t, []bool{}, removed)
+	assert.Equal(t, []bool{true, true, true}, added)
+}
+
+func TestHighlightWordDiffWithoutColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	old, new := HighlightWordDiff("compact,delete", "delete")
+	assert.Equal(t, "compact,delete", old)
+	assert.Equal(t, "delete", new)
+}
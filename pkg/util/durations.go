@@ -2,6 +2,8 @@ package util
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -47,3 +49,55 @@ func PrettyRate(count int64, duration time.Duration) string {
 		return fmt.Sprintf("~0")
 	}
 }
+
+// ParseDuration parses a user-provided duration. In addition to the formats supported by
+// time.ParseDuration, this accepts a leading number of days, e.g. 30d or 1d12h.
+func ParseDuration(value string) (time.Duration, error) {
+	var days time.Duration
+	rest := value
+
+	if index := strings.Index(value, "d"); index > 0 {
+		numDays, err := strconv.Atoi(value[:index])
+		if err == nil && numDays >= 0 {
+			days = time.Duration(numDays) * 24 * time.Hour
+			rest = value[index+1:]
+			if rest == "" {
+				return days, nil
+			}
+		}
+	}
+
+	duration, err := time.ParseDuration(rest)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"Could not parse duration '%s'; must be a duration like 90m or 30d",
+			value,
+		)
+	}
+	if days > 0 && duration < 0 {
+		return 0, fmt.Errorf("Could not parse duration '%s'; parts must have the same sign", value)
+	}
+
+	return days + duration, nil
+}
+
+// ParseTime parses a user-provided time. This can either be an absolute time in RFC3339
+// format (e.g., 2021-06-01T15:04:05Z), a date (e.g., 2021-06-01, interpreted as midnight UTC),
+// or a duration (e.g., 90m or -90m), which is interpreted as that amount of time before the
+// argument now time.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse("2006-01-02", value); err == nil {
+		return parsed, nil
+	}
+	if duration, err := time.ParseDuration(strings.TrimPrefix(value, "-")); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"Could not parse time '%s'; must be an RFC3339 time, a date, or a duration",
+		value,
+	)
+}
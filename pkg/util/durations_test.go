@@ -98,3 +98,102 @@ func TestPrettyRate(t *testing.T) {
 		)
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	type testCase struct {
+		value    string
+		expected time.Duration
+		expError bool
+	}
+
+	testCases := []testCase{
+		{
+			value:    "90m",
+			expected: 90 * time.Minute,
+		},
+		{
+			value:    "30d",
+			expected: 30 * 24 * time.Hour,
+		},
+		{
+			value:    "1d12h",
+			expected: 36 * time.Hour,
+		},
+		{
+			value:    "0d",
+			expected: 0,
+		},
+		{
+			value:    "d",
+			expError: true,
+		},
+		{
+			value:    "1d-2h",
+			expError: true,
+		},
+		{
+			value:    "week",
+			expError: true,
+		},
+	}
+
+	for _, testCaseObj := range testCases {
+		parsed, err := ParseDuration(testCaseObj.value)
+		if testCaseObj.expError {
+			assert.Error(t, err, testCaseObj.value)
+		} else {
+			assert.NoError(t, err, testCaseObj.value)
+			assert.Equal(t, testCaseObj.expected, parsed, testCaseObj.value)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		value    string
+		expected time.Time
+		expError bool
+	}
+
+	testCases := []testCase{
+		{
+			value:    "2021-05-31T15:04:05Z",
+			expected: time.Date(2021, 5, 31, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			value:    "2021-05-31",
+			expected: time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			value:    "90m",
+			expected: time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			value:    "-2h",
+			expected: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			value:    "yesterday",
+			expError: true,
+		},
+	}
+
+	for _, testCaseObj := range testCases {
+		parsed, err := ParseTime(testCaseObj.value, now)
+		if testCaseObj.expError {
+			assert.Error(t, err, testCaseObj.value)
+		} else {
+			assert.NoError(t, err, testCaseObj.value)
+			assert.True(
+				t,
+				testCaseObj.expected.Equal(parsed),
+				"%s: expected %s, got %s",
+				testCaseObj.value,
+				testCaseObj.expected,
+				parsed,
+			)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package util
+
+import "fmt"
+
+// PrettyBytes returns a human-formatted size string given a number of bytes.
+func PrettyBytes(bytes int64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+
+	value := float64(bytes)
+	suffixes := []string{"KB", "MB", "GB", "TB", "PB"}
+
+	var suffix string
+	for _, suffix = range suffixes {
+		value /= unit
+		if value < unit {
+			break
+		}
+	}
+
+	if value < 10.0 {
+		return fmt.Sprintf("%0.1f%s", value, suffix)
+	}
+	return fmt.Sprintf("%d%s", int(value), suffix)
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrettyBytes(t *testing.T) {
+	type testCase struct {
+		bytes    int64
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			bytes:    0,
+			expected: "0B",
+		},
+		{
+			bytes:    512,
+			expected: "512B",
+		},
+		{
+			bytes:    1536,
+			expected: "1.5KB",
+		},
+		{
+			bytes:    25 * 1024 * 1024,
+			expected: "25MB",
+		},
+		{
+			bytes:    3 * 1024 * 1024 * 1024,
+			expected: "3.0GB",
+		},
+	}
+
+	for _, testCaseObj := range testCases {
+		assert.Equal(
+			t,
+			testCaseObj.expected,
+			PrettyBytes(testCaseObj.bytes),
+		)
+	}
+}
@@ -3,12 +3,29 @@ package util
 import (
 	"os"
 
+	"github.com/fatih/color"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
+var colorDisabled bool
+
 // InTerminal determines whether we're running in a terminal or not.
 //
 // Implementation from 	https://rosettacode.org/wiki/Check_output_device_is_a_terminal#Go.
 func InTerminal() bool {
 	return terminal.IsTerminal(int(os.Stdout.Fd()))
 }
+
+// ColorEnabled determines whether output should be colorized. Colors are only used when running
+// in a terminal, and can be turned off with the NO_COLOR environment variable (see
+// https://no-color.org) or DisableColor.
+func ColorEnabled() bool {
+	return !colorDisabled && os.Getenv("NO_COLOR") == "" && InTerminal()
+}
+
+// DisableColor turns off colorized output. This also applies to any colors printed directly via
+// the color package.
+func DisableColor() {
+	colorDisabled = true
+	color.NoColor = true
+}
@@ -2,13 +2,14 @@ package zk
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"time"
 
 	szk "github.com/samuel/go-zookeeper/zk"
-	log "github.com/sirupsen/logrus"
 )
 
 // Client exposes some common, zk operations. Unlike the underlying
@@ -38,6 +39,7 @@ type Client interface {
 		obj interface{},
 		version int32,
 	) (*szk.Stat, error)
+	Delete(ctx context.Context, path string, version int32) error
 
 	// Lock operations
 	AcquireLock(ctx context.Context, path string) (Lock, error)
@@ -70,22 +72,30 @@ type PooledClient struct {
 	readOnly    bool
 }
 
-// NewPooledClient returns a new PooledClient instance.
+// NewPooledClient returns a new PooledClient instance. If tlsConfig is non-nil, the connections
+// to zookeeper are made over TLS using it.
 func NewPooledClient(
 	zkAddrs []string,
 	timeout time.Duration,
 	logger szk.Logger,
 	poolSize int,
 	readOnly bool,
+	tlsConfig *tls.Config,
 ) (*PooledClient, error) {
 	connections := []*szk.Conn{}
-	log.Debugf("Creating zk client with addresses %+v", zkAddrs)
+	log.Debugf("Creating zk client with addresses %+v, TLS enabled=%v", zkAddrs, tlsConfig != nil)
+
+	var dialer szk.Dialer = net.DialTimeout
+	if tlsConfig != nil {
+		dialer = TLSDialer(tlsConfig)
+	}
 
 	for i := 0; i < poolSize; i++ {
 		conn, _, err := szk.Connect(
 			zkAddrs,
 			time.Minute,
 			szk.WithLogger(logger),
+			szk.WithDialer(dialer),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("Error connecting to zkAddr %+v: %+v", zkAddrs, err)
@@ -134,6 +144,19 @@ func NewPooledClient(
 	}, nil
 }
 
+// TLSDialer returns a zk dialer that connects over TLS using the argument config. If the config
+// doesn't set a server name, the host of each zk address is used to verify its certificate.
+func TLSDialer(tlsConfig *tls.Config) szk.Dialer {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return tls.DialWithDialer(
+			&net.Dialer{Timeout: timeout},
+			network,
+			address,
+			tlsConfig,
+		)
+	}
+}
+
 // Get returns the value at the argument zk path.
 func (c *PooledClient) Get(
 	ctx context.Context,
@@ -320,6 +343,31 @@ func (c *PooledClient) SetJSON(
 	return c.Set(ctx, path, data, version)
 }
 
+// Delete removes the node at the argument zk path. A version of -1 matches any version of the
+// node.
+func (c *PooledClient) Delete(
+	ctx context.Context,
+	path string,
+	version int32,
+) error {
+	if c.readOnly {
+		return errors.New("Cannot write in read-only mode")
+	}
+
+	errChan := make(chan error)
+
+	go func() {
+		errChan <- c.connections[0].Delete(path, version)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errChan:
+		return err
+	}
+}
+
 // AcquireLock tries to acquire a lock using the argument zk path.
 func (c *PooledClient) AcquireLock(ctx context.Context, path string) (Lock, error) {
 	if c.readOnly {
@@ -2,7 +2,10 @@ package zk
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -92,6 +95,7 @@ func TestPooledClientRead(t *testing.T) {
 		&DebugLogger{},
 		2,
 		true,
+		nil,
 	)
 	defer pooledClient.Close()
 	require.NoError(t, err)
@@ -183,6 +187,7 @@ func TestPooledClientWrites(t *testing.T) {
 		&DebugLogger{},
 		2,
 		false,
+		nil,
 	)
 	defer pooledClient.Close()
 	require.NoError(t, err)
@@ -225,6 +230,13 @@ func TestPooledClientWrites(t *testing.T) {
 		},
 		testObj,
 	)
+
+	err = pooledClient.Delete(ctx, testPath, -1)
+	require.NoError(t, err)
+
+	exists, _, err := pooledClient.Exists(ctx, testPath)
+	require.NoError(t, err)
+	assert.False(t, exists)
 }
 
 func TestPooledClientSequentialWrites(t *testing.T) {
@@ -253,6 +265,7 @@ func TestPooledClientSequentialWrites(t *testing.T) {
 		&DebugLogger{},
 		2,
 		false,
+		nil,
 	)
 	defer pooledClient.Close()
 	require.NoError(t, err)
@@ -300,6 +313,7 @@ func TestPooledClientLocks(t *testing.T) {
 		&DebugLogger{},
 		2,
 		false,
+		nil,
 	)
 	defer pooledClient.Close()
 	require.NoError(t, err)
@@ -326,3 +340,26 @@ func TestPooledClientLocks(t *testing.T) {
 func testPrefix(name string) string {
 	return util.RandomString(fmt.Sprintf("zk-test-%s-", name), 6)
 }
+
+func TestTLSDialer(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	dialer := TLSDialer(
+		&tls.Config{
+			RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs,
+		},
+	)
+
+	conn, err := dialer("tcp", server.Listener.Addr().String(), 5*time.Second)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	tlsConn, ok := conn.(*tls.Conn)
+	require.True(t, ok)
+	assert.True(t, tlsConn.ConnectionState().HandshakeComplete)
+
+	// The server cert isn't trusted without the right CA
+	_, err = TLSDialer(&tls.Config{})("tcp", server.Listener.Addr().String(), 5*time.Second)
+	assert.Error(t, err)
+}
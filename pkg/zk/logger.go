@@ -2,7 +2,6 @@ package zk
 
 import (
 	szk "github.com/samuel/go-zookeeper/zk"
-	log "github.com/sirupsen/logrus"
 )
 
 // DebugLogger is a logger that satisfies the szk.Logger interface.
@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	szk "github.com/samuel/go-zookeeper/zk"
-	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
 